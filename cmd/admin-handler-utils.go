@@ -26,6 +26,7 @@ import (
 	"github.com/b33s/kes"
 	"github.com/b33s/madmin-go/v2"
 	"github.com/infobsmi/b33s/internal/auth"
+	"github.com/infobsmi/b33s/internal/bucket/placement"
 	"github.com/infobsmi/b33s/internal/config"
 	iampolicy "github.com/b33s/pkg/iam/policy"
 )
@@ -104,6 +105,18 @@ func toAdminAPIErr(ctx context.Context, err error) APIError {
 		}
 	case SRError:
 		apiErr = errorCodes.ToAPIErrWithErr(e.Code, e.Cause)
+	case placement.Error:
+		apiErr = APIError{
+			Code:           "XMinioAdminInvalidPlacementPolicy",
+			Description:    e.Error(),
+			HTTPStatusCode: http.StatusBadRequest,
+		}
+	case BucketPlacementConfigNotFound:
+		apiErr = APIError{
+			Code:           "XMinioAdminNoSuchPlacementConfiguration",
+			Description:    e.Error(),
+			HTTPStatusCode: http.StatusNotFound,
+		}
 	case decomError:
 		apiErr = APIError{
 			Code:           "XMinioDecommissionNotAllowed",
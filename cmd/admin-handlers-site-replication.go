@@ -269,6 +269,8 @@ func (a adminAPIHandlers) SRPeerReplicateBucketItem(w http.ResponseWriter, r *ht
 		err = globalSiteReplicationSys.PeerBucketObjectLockConfigHandler(ctx, item.Bucket, item.ObjectLockConfig, item.UpdatedAt)
 	case madmin.SRBucketMetaTypeSSEConfig:
 		err = globalSiteReplicationSys.PeerBucketSSEConfigHandler(ctx, item.Bucket, item.SSEConfig, item.UpdatedAt)
+	case srBucketMetaTypeCorsConfig, srBucketMetaTypeWebsiteConfig, srBucketMetaTypeLoggingConfig, srBucketMetaTypeInventoryConfig:
+		err = globalSiteReplicationSys.PeerBucketMetaConfigHandler(ctx, item)
 	}
 	if err != nil {
 		logger.LogIf(ctx, err)
@@ -204,6 +204,7 @@ func TestIAMInternalIDPServerSuite(t *testing.T) {
 				suite.TestUserCreate(c)
 				suite.TestUserPolicyEscalationBug(c)
 				suite.TestPolicyCreate(c)
+				suite.TestBucketSubresourcePolicy(c)
 				suite.TestCannedPolicies(c)
 				suite.TestGroupAddRemove(c)
 				suite.TestServiceAccountOpsByAdmin(c)
@@ -609,6 +610,133 @@ func (s *TestSuiteIAM) TestPolicyCreate(c *check) {
 	}
 }
 
+func (s *TestSuiteIAM) TestBucketSubresourcePolicy(c *check) {
+	ctx, cancel := context.WithTimeout(context.Background(), testDefaultTimeout)
+	defer cancel()
+
+	bucket := getRandomBucketName()
+	err := s.client.MakeBucket(ctx, bucket, b33s.MakeBucketOptions{})
+	if err != nil {
+		c.Fatalf("bucket creat error: %v", err)
+	}
+
+	// Bucket sub-resources without actions of their own in the policy
+	// package, they are authorized with the bucket policy actions.
+	testCases := []struct {
+		query  url.Values
+		config string
+	}{
+		{
+			query: url.Values{"cors": []string{""}},
+			config: `<CORSConfiguration><CORSRule><AllowedOrigin>https://example.com</AllowedOrigin>` +
+				`<AllowedMethod>GET</AllowedMethod></CORSRule></CORSConfiguration>`,
+		},
+		{
+			query:  url.Values{"website": []string{""}},
+			config: `<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument></WebsiteConfiguration>`,
+		},
+		{
+			query:  url.Values{"logging": []string{""}},
+			config: `<BucketLoggingStatus xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></BucketLoggingStatus>`,
+		},
+		{
+			query: url.Values{"inventory": []string{""}, "id": []string{"report"}},
+			config: fmt.Sprintf(`<InventoryConfiguration><Id>report</Id><IsEnabled>true</IsEnabled>`+
+				`<Destination><S3BucketDestination><Bucket>arn:aws:s3:::%s</Bucket><Format>CSV</Format>`+
+				`<Prefix>inventory</Prefix></S3BucketDestination></Destination>`+
+				`<IncludedObjectVersions>Current</IncludedObjectVersions>`+
+				`<Schedule><Frequency>Daily</Frequency></Schedule></InventoryConfiguration>`, bucket),
+		},
+	}
+
+	doRequest := func(method, accessKey, secretKey string, query url.Values, config string) int {
+		var headers map[string]string
+		if config != "" {
+			headers = map[string]string{"Content-Md5": getMD5HashBase64([]byte(config))}
+		}
+		req, err := newTestSignedRequestV4(method, makeTestTargetURL(s.endPoint, bucket, "", query),
+			int64(len(config)), strings.NewReader(config), accessKey, secretKey, headers)
+		if err != nil {
+			c.Fatalf("unable to create request: %v", err)
+		}
+		resp, err := s.TestSuiteCommon.client.Do(req)
+		if err != nil {
+			c.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// 1. Create a user without any policy and verify access is denied.
+	accessKey, secretKey := mustGenerateCredentials(c)
+	err = s.adm.SetUser(ctx, accessKey, secretKey, madmin.AccountEnabled)
+	if err != nil {
+		c.Fatalf("Unable to set user: %v", err)
+	}
+	for _, testCase := range testCases {
+		if code := doRequest(http.MethodPut, accessKey, secretKey, testCase.query, testCase.config); code != http.StatusForbidden {
+			c.Fatalf("%v: expected access denied for PUT, got %d", testCase.query, code)
+		}
+		if code := doRequest(http.MethodGet, accessKey, secretKey, testCase.query, ""); code != http.StatusForbidden {
+			c.Fatalf("%v: expected access denied for GET, got %d", testCase.query, code)
+		}
+	}
+
+	// 2. Associate an explicit bucket policy and verify access, inventory
+	// reports are written into the bucket itself.
+	policy := "mypolicy-bucket-subresource"
+	policyBytes := []byte(fmt.Sprintf(`{
+ "Version": "2012-10-17",
+ "Statement": [
+  {
+   "Effect": "Allow",
+   "Action": [
+    "s3:GetBucketPolicy",
+    "s3:PutBucketPolicy"
+   ],
+   "Resource": [
+    "arn:aws:s3:::%s"
+   ]
+  },
+  {
+   "Effect": "Allow",
+   "Action": [
+    "s3:PutObject"
+   ],
+   "Resource": [
+    "arn:aws:s3:::%s/*"
+   ]
+  }
+ ]
+}`, bucket, bucket))
+	err = s.adm.AddCannedPolicy(ctx, policy, policyBytes)
+	if err != nil {
+		c.Fatalf("policy add error: %v", err)
+	}
+	err = s.adm.SetPolicy(ctx, policy, accessKey, false)
+	if err != nil {
+		c.Fatalf("Unable to set policy: %v", err)
+	}
+	for _, testCase := range testCases {
+		if code := doRequest(http.MethodPut, accessKey, secretKey, testCase.query, testCase.config); code != http.StatusOK {
+			c.Fatalf("%v: expected PUT to succeed, got %d", testCase.query, code)
+		}
+		if code := doRequest(http.MethodGet, accessKey, secretKey, testCase.query, ""); code != http.StatusOK {
+			c.Fatalf("%v: expected GET to succeed, got %d", testCase.query, code)
+		}
+	}
+
+	// 3. Delete the user and then delete the policy.
+	err = s.adm.RemoveUser(ctx, accessKey)
+	if err != nil {
+		c.Fatalf("user could not be deleted: %v", err)
+	}
+	err = s.adm.RemoveCannedPolicy(ctx, policy)
+	if err != nil {
+		c.Fatalf("policy del err: %v", err)
+	}
+}
+
 func (s *TestSuiteIAM) TestCannedPolicies(c *check) {
 	ctx, cancel := context.WithTimeout(context.Background(), testDefaultTimeout)
 	defer cancel()
@@ -202,6 +202,16 @@ func registerAdminRouter(router *mux.Router, enableConfigOps bool) {
 		adminRouter.Methods(http.MethodPut).Path(adminVersion+"/set-bucket-quota").HandlerFunc(
 			gz(httpTraceHdrs(adminAPI.PutBucketQuotaConfigHandler))).Queries("bucket", "{bucket:.*}")
 
+		// GetBucketPlacementConfig
+		adminRouter.Methods(http.MethodGet).Path(adminVersion+"/get-bucket-placement").HandlerFunc(
+			gz(httpTraceHdrs(adminAPI.GetBucketPlacementConfigHandler))).Queries("bucket", "{bucket:.*}")
+		// PutBucketPlacementConfig
+		adminRouter.Methods(http.MethodPut).Path(adminVersion+"/set-bucket-placement").HandlerFunc(
+			gz(httpTraceHdrs(adminAPI.PutBucketPlacementConfigHandler))).Queries("bucket", "{bucket:.*}")
+		// RemoveBucketPlacementConfig
+		adminRouter.Methods(http.MethodDelete).Path(adminVersion+"/remove-bucket-placement").HandlerFunc(
+			gz(httpTraceHdrs(adminAPI.RemoveBucketPlacementConfigHandler))).Queries("bucket", "{bucket:.*}")
+
 		// Bucket replication operations
 		// GetBucketTargetHandler
 		adminRouter.Methods(http.MethodGet).Path(adminVersion+"/list-remote-targets").HandlerFunc(
@@ -240,6 +250,10 @@ func registerAdminRouter(router *mux.Router, enableConfigOps bool) {
 		adminRouter.Methods(http.MethodGet).Path(adminVersion + "/tier").HandlerFunc(gz(httpTraceHdrs(adminAPI.ListTierHandler)))
 		adminRouter.Methods(http.MethodDelete).Path(adminVersion + "/tier/{tier}").HandlerFunc(gz(httpTraceHdrs(adminAPI.RemoveTierHandler)))
 		adminRouter.Methods(http.MethodGet).Path(adminVersion + "/tier/{tier}").HandlerFunc(gz(httpTraceHdrs(adminAPI.VerifyTierHandler)))
+		// Tier migration operations
+		adminRouter.Methods(http.MethodPost).Path(adminVersion+"/tier/{tier}/migrate").HandlerFunc(gz(httpTraceHdrs(adminAPI.StartTierMigrationHandler))).Queries("to", "{to:.*}")
+		adminRouter.Methods(http.MethodGet).Path(adminVersion + "/tier/{tier}/migrate").HandlerFunc(gz(httpTraceHdrs(adminAPI.TierMigrationStatusHandler)))
+		adminRouter.Methods(http.MethodDelete).Path(adminVersion + "/tier/{tier}/migrate").HandlerFunc(gz(httpTraceHdrs(adminAPI.StopTierMigrationHandler)))
 		// Tier stats
 		adminRouter.Methods(http.MethodGet).Path(adminVersion + "/tier-stats").HandlerFunc(gz(httpTraceHdrs(adminAPI.TierStatsHandler)))
 
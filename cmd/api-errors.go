@@ -96,6 +96,8 @@ const (
 	ErrInvalidMaxParts
 	ErrInvalidPartNumberMarker
 	ErrInvalidPartNumber
+	ErrMissingObjectAttributes
+	ErrInvalidObjectAttributes
 	ErrInvalidRequestBody
 	ErrInvalidCopySource
 	ErrInvalidMetadataDirective
@@ -114,7 +116,11 @@ const (
 	ErrInvalidLifecycleWithObjectLock
 	ErrNoSuchBucketSSEConfig
 	ErrNoSuchCORSConfiguration
+	ErrCORSForbidden
 	ErrNoSuchWebsiteConfiguration
+	ErrInvalidTargetBucketForLogging
+	ErrNoSuchInventoryConfiguration
+	ErrInvalidInventoryDestination
 	ErrReplicationConfigurationNotFoundError
 	ErrRemoteDestinationNotFoundError
 	ErrReplicationDestinationMissingLock
@@ -490,6 +496,16 @@ var errorCodes = errorCodeMap{
 		Description:    "The requested partnumber is not satisfiable",
 		HTTPStatusCode: http.StatusRequestedRangeNotSatisfiable,
 	},
+	ErrMissingObjectAttributes: {
+		Code:           "InvalidArgument",
+		Description:    "The x-amz-object-attributes header specifying the attributes to be retrieved is either missing or empty",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidObjectAttributes: {
+		Code:           "InvalidArgument",
+		Description:    "Invalid attribute name specified.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrInvalidPolicyDocument: {
 		Code:           "InvalidPolicyDocument",
 		Description:    "The content of the form does not meet the conditions specified in the policy document.",
@@ -875,11 +891,31 @@ var errorCodes = errorCodeMap{
 		Description:    "The CORS configuration does not exist",
 		HTTPStatusCode: http.StatusNotFound,
 	},
+	ErrCORSForbidden: {
+		Code:           "AccessForbidden",
+		Description:    "CORSResponse: This CORS request is not allowed. This is usually because the evalution of Origin, request method / Access-Control-Request-Method or Access-Control-Request-Headers are not whitelisted by the resource's CORS spec.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
 	ErrNoSuchWebsiteConfiguration: {
 		Code:           "NoSuchWebsiteConfiguration",
 		Description:    "The specified bucket does not have a website configuration",
 		HTTPStatusCode: http.StatusNotFound,
 	},
+	ErrInvalidTargetBucketForLogging: {
+		Code:           "InvalidTargetBucketForLogging",
+		Description:    "The target bucket for logging does not exist, is not owned by you, or does not have the appropriate grants for the log-delivery group.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrNoSuchInventoryConfiguration: {
+		Code:           "NoSuchConfiguration",
+		Description:    "The specified configuration does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidInventoryDestination: {
+		Code:           "InvalidArgument",
+		Description:    "The inventory destination bucket does not exist or is not writable by you.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrReplicationConfigurationNotFoundError: {
 		Code:           "ReplicationConfigurationNotFoundError",
 		Description:    "The replication configuration was not found",
@@ -2133,6 +2169,12 @@ func toAPIErrorCode(ctx context.Context, err error) (apiErr APIErrorCode) {
 		apiErr = ErrNoSuchLifecycleConfiguration
 	case BucketSSEConfigNotFound:
 		apiErr = ErrNoSuchBucketSSEConfig
+	case BucketCorsNotFound:
+		apiErr = ErrNoSuchCORSConfiguration
+	case BucketWebsiteNotFound:
+		apiErr = ErrNoSuchWebsiteConfiguration
+	case BucketInventoryNotFound:
+		apiErr = ErrNoSuchInventoryConfiguration
 	case BucketTaggingNotFound:
 		apiErr = ErrBucketTaggingNotFound
 	case BucketObjectLockConfigNotFound:
@@ -2184,6 +2226,8 @@ func toAPIErrorCode(ctx context.Context, err error) (apiErr APIErrorCode) {
 		apiErr = ErrFilterNameSuffix
 	case *event.ErrInvalidFilterValue:
 		apiErr = ErrFilterValueInvalid
+	case *event.ErrDuplicateFilterName:
+		apiErr = ErrFilterNameInvalid
 	case *event.ErrDuplicateEventName:
 		apiErr = ErrOverlappingConfigs
 	case *event.ErrDuplicateQueueConfiguration:
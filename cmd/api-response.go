@@ -721,7 +721,10 @@ func generateListPartsResponse(partsInfo ListPartsInfo, encodingType string) Lis
 	listPartsResponse.Bucket = partsInfo.Bucket
 	listPartsResponse.Key = s3EncodeName(partsInfo.Object, encodingType)
 	listPartsResponse.UploadID = partsInfo.UploadID
-	listPartsResponse.StorageClass = globalMinioDefaultStorageClass
+	listPartsResponse.StorageClass = partsInfo.StorageClass
+	if listPartsResponse.StorageClass == "" {
+		listPartsResponse.StorageClass = globalMinioDefaultStorageClass
+	}
 
 	// Dumb values not meaningful
 	listPartsResponse.Initiator = Initiator{
@@ -825,6 +828,8 @@ const (
 	mimeJSON mimeType = "application/json"
 	// Means response type is XML.
 	mimeXML mimeType = "application/xml"
+	// Means response type is HTML.
+	mimeHTML mimeType = "text/html; charset=utf-8"
 )
 
 // writeSuccessResponseJSON writes success headers and response if any,
@@ -122,29 +122,14 @@ var rejectedObjAPIs = []rejectedAPI{
 }
 
 var rejectedBucketAPIs = []rejectedAPI{
-	{
-		api:     "inventory",
-		methods: []string{http.MethodGet, http.MethodPut, http.MethodDelete},
-		queries: []string{"inventory", ""},
-	},
-	{
-		api:     "cors",
-		methods: []string{http.MethodPut, http.MethodDelete},
-		queries: []string{"cors", ""},
-	},
 	{
 		api:     "metrics",
 		methods: []string{http.MethodGet, http.MethodPut, http.MethodDelete},
 		queries: []string{"metrics", ""},
 	},
-	{
-		api:     "website",
-		methods: []string{http.MethodPut},
-		queries: []string{"website", ""},
-	},
 	{
 		api:     "logging",
-		methods: []string{http.MethodPut, http.MethodDelete},
+		methods: []string{http.MethodDelete},
 		queries: []string{"logging", ""},
 	},
 	{
@@ -195,6 +180,13 @@ func registerAPIRouter(router *mux.Router) {
 	// API Router
 	apiRouter := router.PathPrefix(SlashSeparator).Subrouter()
 
+	// Static website endpoints must be matched before the S3 API
+	// endpoints since they may share the same parent domain.
+	var websiteRouters []*mux.Router
+	for _, domainName := range globalWebsiteDomainNames {
+		websiteRouters = append(websiteRouters, apiRouter.Host("{bucket:.+}."+domainName).Subrouter())
+	}
+
 	var routers []*mux.Router
 	for _, domainName := range globalDomainNames {
 		if IsKubernetes() {
@@ -228,6 +220,15 @@ func registerAPIRouter(router *mux.Router) {
 		logger.Fatal(err, "Unable to initialize server")
 	}
 
+	for _, router := range websiteRouters {
+		// Website - note gzip compression is *not* added due to Range requests.
+		router.Methods(http.MethodGet, http.MethodHead).HandlerFunc(
+			collectAPIStats("website", maxClients(httpTraceHdrs(api.WebsiteHandler))))
+		// Website endpoints are read-only.
+		router.NewRoute().HandlerFunc(
+			collectAPIStats("website", httpTraceAll(websiteMethodNotAllowedHandler)))
+	}
+
 	for _, router := range routers {
 		// Register all rejected object APIs
 		for _, r := range rejectedObjAPIs {
@@ -285,6 +286,9 @@ func registerAPIRouter(router *mux.Router) {
 		// GetObjectLegalHold
 		router.Methods(http.MethodGet).Path("/{object:.+}").HandlerFunc(
 			collectAPIStats("getobjectlegalhold", maxClients(gz(httpTraceAll(api.GetObjectLegalHoldHandler))))).Queries("legal-hold", "")
+		// GetObjectAttributes
+		router.Methods(http.MethodGet).Path("/{object:.+}").HandlerFunc(
+			collectAPIStats("getobjectattributes", maxClients(gz(httpTraceHdrs(api.GetObjectAttributesHandler))))).Queries("attributes", "")
 		// GetObject - note gzip compression is *not* added due to Range requests.
 		router.Methods(http.MethodGet).Path("/{object:.+}").HandlerFunc(
 			collectAPIStats("getobject", maxClients(gz(httpTraceHdrs(api.GetObjectHandler)))))
@@ -340,6 +344,18 @@ func registerAPIRouter(router *mux.Router) {
 		// GetBucketNotification
 		router.Methods(http.MethodGet).HandlerFunc(
 			collectAPIStats("getbucketnotification", maxClients(gz(httpTraceAll(api.GetBucketNotificationHandler))))).Queries("notification", "")
+		// GetBucketCors
+		router.Methods(http.MethodGet).HandlerFunc(
+			collectAPIStats("getbucketcors", maxClients(gz(httpTraceAll(api.GetBucketCorsHandler))))).Queries("cors", "")
+		// GetBucketWebsite
+		router.Methods(http.MethodGet).HandlerFunc(
+			collectAPIStats("getbucketwebsite", maxClients(gz(httpTraceAll(api.GetBucketWebsiteHandler))))).Queries("website", "")
+		// GetBucketInventoryConfiguration
+		router.Methods(http.MethodGet).HandlerFunc(
+			collectAPIStats("getbucketinventoryconfiguration", maxClients(gz(httpTraceAll(api.GetBucketInventoryConfigurationHandler))))).Queries("inventory", "", "id", "{id:.*}")
+		// ListBucketInventoryConfigurations
+		router.Methods(http.MethodGet).HandlerFunc(
+			collectAPIStats("listbucketinventoryconfigurations", maxClients(gz(httpTraceAll(api.ListBucketInventoryConfigurationsHandler))))).Queries("inventory", "")
 		// ListenNotification
 		router.Methods(http.MethodGet).HandlerFunc(
 			collectAPIStats("listennotification", gz(httpTraceAll(api.ListenNotificationHandler)))).Queries("events", "{events:.*}")
@@ -354,27 +370,18 @@ func registerAPIRouter(router *mux.Router) {
 		// PutBucketACL -- this is a dummy call.
 		router.Methods(http.MethodPut).HandlerFunc(
 			collectAPIStats("putbucketacl", maxClients(gz(httpTraceAll(api.PutBucketACLHandler))))).Queries("acl", "")
-		// GetBucketCors - this is a dummy call.
-		router.Methods(http.MethodGet).HandlerFunc(
-			collectAPIStats("getbucketcors", maxClients(gz(httpTraceAll(api.GetBucketCorsHandler))))).Queries("cors", "")
-		// GetBucketWebsiteHandler - this is a dummy call.
-		router.Methods(http.MethodGet).HandlerFunc(
-			collectAPIStats("getbucketwebsite", maxClients(gz(httpTraceAll(api.GetBucketWebsiteHandler))))).Queries("website", "")
 		// GetBucketAccelerateHandler - this is a dummy call.
 		router.Methods(http.MethodGet).HandlerFunc(
 			collectAPIStats("getbucketaccelerate", maxClients(gz(httpTraceAll(api.GetBucketAccelerateHandler))))).Queries("accelerate", "")
 		// GetBucketRequestPaymentHandler - this is a dummy call.
 		router.Methods(http.MethodGet).HandlerFunc(
 			collectAPIStats("getbucketrequestpayment", maxClients(gz(httpTraceAll(api.GetBucketRequestPaymentHandler))))).Queries("requestPayment", "")
-		// GetBucketLoggingHandler - this is a dummy call.
+		// GetBucketLogging
 		router.Methods(http.MethodGet).HandlerFunc(
 			collectAPIStats("getbucketlogging", maxClients(gz(httpTraceAll(api.GetBucketLoggingHandler))))).Queries("logging", "")
 		// GetBucketTaggingHandler
 		router.Methods(http.MethodGet).HandlerFunc(
 			collectAPIStats("getbuckettagging", maxClients(gz(httpTraceAll(api.GetBucketTaggingHandler))))).Queries("tagging", "")
-		// DeleteBucketWebsiteHandler
-		router.Methods(http.MethodDelete).HandlerFunc(
-			collectAPIStats("deletebucketwebsite", maxClients(gz(httpTraceAll(api.DeleteBucketWebsiteHandler))))).Queries("website", "")
 		// DeleteBucketTaggingHandler
 		router.Methods(http.MethodDelete).HandlerFunc(
 			collectAPIStats("deletebuckettagging", maxClients(gz(httpTraceAll(api.DeleteBucketTaggingHandler))))).Queries("tagging", "")
@@ -403,6 +410,18 @@ func registerAPIRouter(router *mux.Router) {
 		// PutBucketEncryption
 		router.Methods(http.MethodPut).HandlerFunc(
 			collectAPIStats("putbucketencryption", maxClients(gz(httpTraceAll(api.PutBucketEncryptionHandler))))).Queries("encryption", "")
+		// PutBucketCors
+		router.Methods(http.MethodPut).HandlerFunc(
+			collectAPIStats("putbucketcors", maxClients(gz(httpTraceAll(api.PutBucketCorsHandler))))).Queries("cors", "")
+		// PutBucketWebsite
+		router.Methods(http.MethodPut).HandlerFunc(
+			collectAPIStats("putbucketwebsite", maxClients(gz(httpTraceAll(api.PutBucketWebsiteHandler))))).Queries("website", "")
+		// PutBucketLogging
+		router.Methods(http.MethodPut).HandlerFunc(
+			collectAPIStats("putbucketlogging", maxClients(gz(httpTraceAll(api.PutBucketLoggingHandler))))).Queries("logging", "")
+		// PutBucketInventoryConfiguration
+		router.Methods(http.MethodPut).HandlerFunc(
+			collectAPIStats("putbucketinventoryconfiguration", maxClients(gz(httpTraceAll(api.PutBucketInventoryConfigurationHandler))))).Queries("inventory", "", "id", "{id:.*}")
 
 		// PutBucketPolicy
 		router.Methods(http.MethodPut).HandlerFunc(
@@ -448,6 +467,15 @@ func registerAPIRouter(router *mux.Router) {
 		// DeleteBucketEncryption
 		router.Methods(http.MethodDelete).HandlerFunc(
 			collectAPIStats("deletebucketencryption", maxClients(gz(httpTraceAll(api.DeleteBucketEncryptionHandler))))).Queries("encryption", "")
+		// DeleteBucketCors
+		router.Methods(http.MethodDelete).HandlerFunc(
+			collectAPIStats("deletebucketcors", maxClients(gz(httpTraceAll(api.DeleteBucketCorsHandler))))).Queries("cors", "")
+		// DeleteBucketWebsite
+		router.Methods(http.MethodDelete).HandlerFunc(
+			collectAPIStats("deletebucketwebsite", maxClients(gz(httpTraceAll(api.DeleteBucketWebsiteHandler))))).Queries("website", "")
+		// DeleteBucketInventoryConfiguration
+		router.Methods(http.MethodDelete).HandlerFunc(
+			collectAPIStats("deletebucketinventoryconfiguration", maxClients(gz(httpTraceAll(api.DeleteBucketInventoryConfigurationHandler))))).Queries("inventory", "", "id", "{id:.*}")
 		// DeleteBucket
 		router.Methods(http.MethodDelete).HandlerFunc(
 			collectAPIStats("deletebucket", maxClients(gz(httpTraceAll(api.DeleteBucketHandler)))))
@@ -514,7 +542,7 @@ func corsHandler(handler http.Handler) http.Handler {
 		"*",
 	}
 
-	return cors.New(cors.Options{
+	globalCors := cors.New(cors.Options{
 		AllowOriginFunc: func(origin string) bool {
 			for _, allowedOrigin := range globalAPIConfig.getCorsAllowOrigins() {
 				if wildcard.MatchSimple(allowedOrigin, origin) {
@@ -536,4 +564,7 @@ func corsHandler(handler http.Handler) http.Handler {
 		ExposedHeaders:   commonS3Headers,
 		AllowCredentials: true,
 	}).Handler(handler)
+
+	// Bucket CORS configuration takes precedence over the global setting.
+	return setBucketCorsHandler(handler, globalCors)
 }
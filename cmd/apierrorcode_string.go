@@ -30,291 +30,297 @@ func _() {
 	_ = x[ErrInvalidMaxParts-19]
 	_ = x[ErrInvalidPartNumberMarker-20]
 	_ = x[ErrInvalidPartNumber-21]
-	_ = x[ErrInvalidRequestBody-22]
-	_ = x[ErrInvalidCopySource-23]
-	_ = x[ErrInvalidMetadataDirective-24]
-	_ = x[ErrInvalidCopyDest-25]
-	_ = x[ErrInvalidPolicyDocument-26]
-	_ = x[ErrInvalidObjectState-27]
-	_ = x[ErrMalformedXML-28]
-	_ = x[ErrMissingContentLength-29]
-	_ = x[ErrMissingContentMD5-30]
-	_ = x[ErrMissingRequestBodyError-31]
-	_ = x[ErrMissingSecurityHeader-32]
-	_ = x[ErrNoSuchBucket-33]
-	_ = x[ErrNoSuchBucketPolicy-34]
-	_ = x[ErrNoSuchBucketLifecycle-35]
-	_ = x[ErrNoSuchLifecycleConfiguration-36]
-	_ = x[ErrInvalidLifecycleWithObjectLock-37]
-	_ = x[ErrNoSuchBucketSSEConfig-38]
-	_ = x[ErrNoSuchCORSConfiguration-39]
-	_ = x[ErrNoSuchWebsiteConfiguration-40]
-	_ = x[ErrReplicationConfigurationNotFoundError-41]
-	_ = x[ErrRemoteDestinationNotFoundError-42]
-	_ = x[ErrReplicationDestinationMissingLock-43]
-	_ = x[ErrRemoteTargetNotFoundError-44]
-	_ = x[ErrReplicationRemoteConnectionError-45]
-	_ = x[ErrReplicationBandwidthLimitError-46]
-	_ = x[ErrBucketRemoteIdenticalToSource-47]
-	_ = x[ErrBucketRemoteAlreadyExists-48]
-	_ = x[ErrBucketRemoteLabelInUse-49]
-	_ = x[ErrBucketRemoteArnTypeInvalid-50]
-	_ = x[ErrBucketRemoteArnInvalid-51]
-	_ = x[ErrBucketRemoteRemoveDisallowed-52]
-	_ = x[ErrRemoteTargetNotVersionedError-53]
-	_ = x[ErrReplicationSourceNotVersionedError-54]
-	_ = x[ErrReplicationNeedsVersioningError-55]
-	_ = x[ErrReplicationBucketNeedsVersioningError-56]
-	_ = x[ErrReplicationDenyEditError-57]
-	_ = x[ErrReplicationNoExistingObjects-58]
-	_ = x[ErrObjectRestoreAlreadyInProgress-59]
-	_ = x[ErrNoSuchKey-60]
-	_ = x[ErrNoSuchUpload-61]
-	_ = x[ErrInvalidVersionID-62]
-	_ = x[ErrNoSuchVersion-63]
-	_ = x[ErrNotImplemented-64]
-	_ = x[ErrPreconditionFailed-65]
-	_ = x[ErrRequestTimeTooSkewed-66]
-	_ = x[ErrSignatureDoesNotMatch-67]
-	_ = x[ErrMethodNotAllowed-68]
-	_ = x[ErrInvalidPart-69]
-	_ = x[ErrInvalidPartOrder-70]
-	_ = x[ErrAuthorizationHeaderMalformed-71]
-	_ = x[ErrMalformedPOSTRequest-72]
-	_ = x[ErrPOSTFileRequired-73]
-	_ = x[ErrSignatureVersionNotSupported-74]
-	_ = x[ErrBucketNotEmpty-75]
-	_ = x[ErrAllAccessDisabled-76]
-	_ = x[ErrPolicyInvalidVersion-77]
-	_ = x[ErrMissingFields-78]
-	_ = x[ErrMissingCredTag-79]
-	_ = x[ErrCredMalformed-80]
-	_ = x[ErrInvalidRegion-81]
-	_ = x[ErrInvalidServiceS3-82]
-	_ = x[ErrInvalidServiceSTS-83]
-	_ = x[ErrInvalidRequestVersion-84]
-	_ = x[ErrMissingSignTag-85]
-	_ = x[ErrMissingSignHeadersTag-86]
-	_ = x[ErrMalformedDate-87]
-	_ = x[ErrMalformedPresignedDate-88]
-	_ = x[ErrMalformedCredentialDate-89]
-	_ = x[ErrMalformedCredentialRegion-90]
-	_ = x[ErrMalformedExpires-91]
-	_ = x[ErrNegativeExpires-92]
-	_ = x[ErrAuthHeaderEmpty-93]
-	_ = x[ErrExpiredPresignRequest-94]
-	_ = x[ErrRequestNotReadyYet-95]
-	_ = x[ErrUnsignedHeaders-96]
-	_ = x[ErrMissingDateHeader-97]
-	_ = x[ErrInvalidQuerySignatureAlgo-98]
-	_ = x[ErrInvalidQueryParams-99]
-	_ = x[ErrBucketAlreadyOwnedByYou-100]
-	_ = x[ErrInvalidDuration-101]
-	_ = x[ErrBucketAlreadyExists-102]
-	_ = x[ErrMetadataTooLarge-103]
-	_ = x[ErrUnsupportedMetadata-104]
-	_ = x[ErrMaximumExpires-105]
-	_ = x[ErrSlowDown-106]
-	_ = x[ErrInvalidPrefixMarker-107]
-	_ = x[ErrBadRequest-108]
-	_ = x[ErrKeyTooLongError-109]
-	_ = x[ErrInvalidBucketObjectLockConfiguration-110]
-	_ = x[ErrObjectLockConfigurationNotFound-111]
-	_ = x[ErrObjectLockConfigurationNotAllowed-112]
-	_ = x[ErrNoSuchObjectLockConfiguration-113]
-	_ = x[ErrObjectLocked-114]
-	_ = x[ErrInvalidRetentionDate-115]
-	_ = x[ErrPastObjectLockRetainDate-116]
-	_ = x[ErrUnknownWORMModeDirective-117]
-	_ = x[ErrBucketTaggingNotFound-118]
-	_ = x[ErrObjectLockInvalidHeaders-119]
-	_ = x[ErrInvalidTagDirective-120]
-	_ = x[ErrInvalidEncryptionMethod-121]
-	_ = x[ErrInvalidEncryptionKeyID-122]
-	_ = x[ErrInsecureSSECustomerRequest-123]
-	_ = x[ErrSSEMultipartEncrypted-124]
-	_ = x[ErrSSEEncryptedObject-125]
-	_ = x[ErrInvalidEncryptionParameters-126]
-	_ = x[ErrInvalidSSECustomerAlgorithm-127]
-	_ = x[ErrInvalidSSECustomerKey-128]
-	_ = x[ErrMissingSSECustomerKey-129]
-	_ = x[ErrMissingSSECustomerKeyMD5-130]
-	_ = x[ErrSSECustomerKeyMD5Mismatch-131]
-	_ = x[ErrInvalidSSECustomerParameters-132]
-	_ = x[ErrIncompatibleEncryptionMethod-133]
-	_ = x[ErrKMSNotConfigured-134]
-	_ = x[ErrKMSKeyNotFoundException-135]
-	_ = x[ErrNoAccessKey-136]
-	_ = x[ErrInvalidToken-137]
-	_ = x[ErrEventNotification-138]
-	_ = x[ErrARNNotification-139]
-	_ = x[ErrRegionNotification-140]
-	_ = x[ErrOverlappingFilterNotification-141]
-	_ = x[ErrFilterNameInvalid-142]
-	_ = x[ErrFilterNamePrefix-143]
-	_ = x[ErrFilterNameSuffix-144]
-	_ = x[ErrFilterValueInvalid-145]
-	_ = x[ErrOverlappingConfigs-146]
-	_ = x[ErrUnsupportedNotification-147]
-	_ = x[ErrContentSHA256Mismatch-148]
-	_ = x[ErrContentChecksumMismatch-149]
-	_ = x[ErrReadQuorum-150]
-	_ = x[ErrWriteQuorum-151]
-	_ = x[ErrStorageFull-152]
-	_ = x[ErrRequestBodyParse-153]
-	_ = x[ErrObjectExistsAsDirectory-154]
-	_ = x[ErrInvalidObjectName-155]
-	_ = x[ErrInvalidObjectNamePrefixSlash-156]
-	_ = x[ErrInvalidResourceName-157]
-	_ = x[ErrServerNotInitialized-158]
-	_ = x[ErrOperationTimedOut-159]
-	_ = x[ErrClientDisconnected-160]
-	_ = x[ErrOperationMaxedOut-161]
-	_ = x[ErrInvalidRequest-162]
-	_ = x[ErrTransitionStorageClassNotFoundError-163]
-	_ = x[ErrInvalidStorageClass-164]
-	_ = x[ErrBackendDown-165]
-	_ = x[ErrMalformedJSON-166]
-	_ = x[ErrAdminNoSuchUser-167]
-	_ = x[ErrAdminNoSuchGroup-168]
-	_ = x[ErrAdminGroupNotEmpty-169]
-	_ = x[ErrAdminNoSuchJob-170]
-	_ = x[ErrAdminNoSuchPolicy-171]
-	_ = x[ErrAdminPolicyChangeAlreadyApplied-172]
-	_ = x[ErrAdminInvalidArgument-173]
-	_ = x[ErrAdminInvalidAccessKey-174]
-	_ = x[ErrAdminInvalidSecretKey-175]
-	_ = x[ErrAdminConfigNoQuorum-176]
-	_ = x[ErrAdminConfigTooLarge-177]
-	_ = x[ErrAdminConfigBadJSON-178]
-	_ = x[ErrAdminNoSuchConfigTarget-179]
-	_ = x[ErrAdminConfigEnvOverridden-180]
-	_ = x[ErrAdminConfigDuplicateKeys-181]
-	_ = x[ErrAdminConfigInvalidIDPType-182]
-	_ = x[ErrAdminConfigLDAPValidation-183]
-	_ = x[ErrAdminConfigIDPCfgNameAlreadyExists-184]
-	_ = x[ErrAdminConfigIDPCfgNameDoesNotExist-185]
-	_ = x[ErrAdminCredentialsMismatch-186]
-	_ = x[ErrInsecureClientRequest-187]
-	_ = x[ErrObjectTampered-188]
-	_ = x[ErrSiteReplicationInvalidRequest-189]
-	_ = x[ErrSiteReplicationPeerResp-190]
-	_ = x[ErrSiteReplicationBackendIssue-191]
-	_ = x[ErrSiteReplicationServiceAccountError-192]
-	_ = x[ErrSiteReplicationBucketConfigError-193]
-	_ = x[ErrSiteReplicationBucketMetaError-194]
-	_ = x[ErrSiteReplicationIAMError-195]
-	_ = x[ErrSiteReplicationConfigMissing-196]
-	_ = x[ErrAdminRebalanceAlreadyStarted-197]
-	_ = x[ErrAdminRebalanceNotStarted-198]
-	_ = x[ErrAdminBucketQuotaExceeded-199]
-	_ = x[ErrAdminNoSuchQuotaConfiguration-200]
-	_ = x[ErrHealNotImplemented-201]
-	_ = x[ErrHealNoSuchProcess-202]
-	_ = x[ErrHealInvalidClientToken-203]
-	_ = x[ErrHealMissingBucket-204]
-	_ = x[ErrHealAlreadyRunning-205]
-	_ = x[ErrHealOverlappingPaths-206]
-	_ = x[ErrIncorrectContinuationToken-207]
-	_ = x[ErrEmptyRequestBody-208]
-	_ = x[ErrUnsupportedFunction-209]
-	_ = x[ErrInvalidExpressionType-210]
-	_ = x[ErrBusy-211]
-	_ = x[ErrUnauthorizedAccess-212]
-	_ = x[ErrExpressionTooLong-213]
-	_ = x[ErrIllegalSQLFunctionArgument-214]
-	_ = x[ErrInvalidKeyPath-215]
-	_ = x[ErrInvalidCompressionFormat-216]
-	_ = x[ErrInvalidFileHeaderInfo-217]
-	_ = x[ErrInvalidJSONType-218]
-	_ = x[ErrInvalidQuoteFields-219]
-	_ = x[ErrInvalidRequestParameter-220]
-	_ = x[ErrInvalidDataType-221]
-	_ = x[ErrInvalidTextEncoding-222]
-	_ = x[ErrInvalidDataSource-223]
-	_ = x[ErrInvalidTableAlias-224]
-	_ = x[ErrMissingRequiredParameter-225]
-	_ = x[ErrObjectSerializationConflict-226]
-	_ = x[ErrUnsupportedSQLOperation-227]
-	_ = x[ErrUnsupportedSQLStructure-228]
-	_ = x[ErrUnsupportedSyntax-229]
-	_ = x[ErrUnsupportedRangeHeader-230]
-	_ = x[ErrLexerInvalidChar-231]
-	_ = x[ErrLexerInvalidOperator-232]
-	_ = x[ErrLexerInvalidLiteral-233]
-	_ = x[ErrLexerInvalidIONLiteral-234]
-	_ = x[ErrParseExpectedDatePart-235]
-	_ = x[ErrParseExpectedKeyword-236]
-	_ = x[ErrParseExpectedTokenType-237]
-	_ = x[ErrParseExpected2TokenTypes-238]
-	_ = x[ErrParseExpectedNumber-239]
-	_ = x[ErrParseExpectedRightParenBuiltinFunctionCall-240]
-	_ = x[ErrParseExpectedTypeName-241]
-	_ = x[ErrParseExpectedWhenClause-242]
-	_ = x[ErrParseUnsupportedToken-243]
-	_ = x[ErrParseUnsupportedLiteralsGroupBy-244]
-	_ = x[ErrParseExpectedMember-245]
-	_ = x[ErrParseUnsupportedSelect-246]
-	_ = x[ErrParseUnsupportedCase-247]
-	_ = x[ErrParseUnsupportedCaseClause-248]
-	_ = x[ErrParseUnsupportedAlias-249]
-	_ = x[ErrParseUnsupportedSyntax-250]
-	_ = x[ErrParseUnknownOperator-251]
-	_ = x[ErrParseMissingIdentAfterAt-252]
-	_ = x[ErrParseUnexpectedOperator-253]
-	_ = x[ErrParseUnexpectedTerm-254]
-	_ = x[ErrParseUnexpectedToken-255]
-	_ = x[ErrParseUnexpectedKeyword-256]
-	_ = x[ErrParseExpectedExpression-257]
-	_ = x[ErrParseExpectedLeftParenAfterCast-258]
-	_ = x[ErrParseExpectedLeftParenValueConstructor-259]
-	_ = x[ErrParseExpectedLeftParenBuiltinFunctionCall-260]
-	_ = x[ErrParseExpectedArgumentDelimiter-261]
-	_ = x[ErrParseCastArity-262]
-	_ = x[ErrParseInvalidTypeParam-263]
-	_ = x[ErrParseEmptySelect-264]
-	_ = x[ErrParseSelectMissingFrom-265]
-	_ = x[ErrParseExpectedIdentForGroupName-266]
-	_ = x[ErrParseExpectedIdentForAlias-267]
-	_ = x[ErrParseUnsupportedCallWithStar-268]
-	_ = x[ErrParseNonUnaryAgregateFunctionCall-269]
-	_ = x[ErrParseMalformedJoin-270]
-	_ = x[ErrParseExpectedIdentForAt-271]
-	_ = x[ErrParseAsteriskIsNotAloneInSelectList-272]
-	_ = x[ErrParseCannotMixSqbAndWildcardInSelectList-273]
-	_ = x[ErrParseInvalidContextForWildcardInSelectList-274]
-	_ = x[ErrIncorrectSQLFunctionArgumentType-275]
-	_ = x[ErrValueParseFailure-276]
-	_ = x[ErrEvaluatorInvalidArguments-277]
-	_ = x[ErrIntegerOverflow-278]
-	_ = x[ErrLikeInvalidInputs-279]
-	_ = x[ErrCastFailed-280]
-	_ = x[ErrInvalidCast-281]
-	_ = x[ErrEvaluatorInvalidTimestampFormatPattern-282]
-	_ = x[ErrEvaluatorInvalidTimestampFormatPatternSymbolForParsing-283]
-	_ = x[ErrEvaluatorTimestampFormatPatternDuplicateFields-284]
-	_ = x[ErrEvaluatorTimestampFormatPatternHourClockAmPmMismatch-285]
-	_ = x[ErrEvaluatorUnterminatedTimestampFormatPatternToken-286]
-	_ = x[ErrEvaluatorInvalidTimestampFormatPatternToken-287]
-	_ = x[ErrEvaluatorInvalidTimestampFormatPatternSymbol-288]
-	_ = x[ErrEvaluatorBindingDoesNotExist-289]
-	_ = x[ErrMissingHeaders-290]
-	_ = x[ErrInvalidColumnIndex-291]
-	_ = x[ErrAdminConfigNotificationTargetsFailed-292]
-	_ = x[ErrAdminProfilerNotEnabled-293]
-	_ = x[ErrInvalidDecompressedSize-294]
-	_ = x[ErrAddUserInvalidArgument-295]
-	_ = x[ErrAdminResourceInvalidArgument-296]
-	_ = x[ErrAdminAccountNotEligible-297]
-	_ = x[ErrAccountNotEligible-298]
-	_ = x[ErrAdminServiceAccountNotFound-299]
-	_ = x[ErrPostPolicyConditionInvalidFormat-300]
-	_ = x[ErrInvalidChecksum-301]
+	_ = x[ErrMissingObjectAttributes-22]
+	_ = x[ErrInvalidObjectAttributes-23]
+	_ = x[ErrInvalidRequestBody-24]
+	_ = x[ErrInvalidCopySource-25]
+	_ = x[ErrInvalidMetadataDirective-26]
+	_ = x[ErrInvalidCopyDest-27]
+	_ = x[ErrInvalidPolicyDocument-28]
+	_ = x[ErrInvalidObjectState-29]
+	_ = x[ErrMalformedXML-30]
+	_ = x[ErrMissingContentLength-31]
+	_ = x[ErrMissingContentMD5-32]
+	_ = x[ErrMissingRequestBodyError-33]
+	_ = x[ErrMissingSecurityHeader-34]
+	_ = x[ErrNoSuchBucket-35]
+	_ = x[ErrNoSuchBucketPolicy-36]
+	_ = x[ErrNoSuchBucketLifecycle-37]
+	_ = x[ErrNoSuchLifecycleConfiguration-38]
+	_ = x[ErrInvalidLifecycleWithObjectLock-39]
+	_ = x[ErrNoSuchBucketSSEConfig-40]
+	_ = x[ErrNoSuchCORSConfiguration-41]
+	_ = x[ErrCORSForbidden-42]
+	_ = x[ErrNoSuchWebsiteConfiguration-43]
+	_ = x[ErrInvalidTargetBucketForLogging-44]
+	_ = x[ErrNoSuchInventoryConfiguration-45]
+	_ = x[ErrInvalidInventoryDestination-46]
+	_ = x[ErrReplicationConfigurationNotFoundError-47]
+	_ = x[ErrRemoteDestinationNotFoundError-48]
+	_ = x[ErrReplicationDestinationMissingLock-49]
+	_ = x[ErrRemoteTargetNotFoundError-50]
+	_ = x[ErrReplicationRemoteConnectionError-51]
+	_ = x[ErrReplicationBandwidthLimitError-52]
+	_ = x[ErrBucketRemoteIdenticalToSource-53]
+	_ = x[ErrBucketRemoteAlreadyExists-54]
+	_ = x[ErrBucketRemoteLabelInUse-55]
+	_ = x[ErrBucketRemoteArnTypeInvalid-56]
+	_ = x[ErrBucketRemoteArnInvalid-57]
+	_ = x[ErrBucketRemoteRemoveDisallowed-58]
+	_ = x[ErrRemoteTargetNotVersionedError-59]
+	_ = x[ErrReplicationSourceNotVersionedError-60]
+	_ = x[ErrReplicationNeedsVersioningError-61]
+	_ = x[ErrReplicationBucketNeedsVersioningError-62]
+	_ = x[ErrReplicationDenyEditError-63]
+	_ = x[ErrReplicationNoExistingObjects-64]
+	_ = x[ErrObjectRestoreAlreadyInProgress-65]
+	_ = x[ErrNoSuchKey-66]
+	_ = x[ErrNoSuchUpload-67]
+	_ = x[ErrInvalidVersionID-68]
+	_ = x[ErrNoSuchVersion-69]
+	_ = x[ErrNotImplemented-70]
+	_ = x[ErrPreconditionFailed-71]
+	_ = x[ErrRequestTimeTooSkewed-72]
+	_ = x[ErrSignatureDoesNotMatch-73]
+	_ = x[ErrMethodNotAllowed-74]
+	_ = x[ErrInvalidPart-75]
+	_ = x[ErrInvalidPartOrder-76]
+	_ = x[ErrAuthorizationHeaderMalformed-77]
+	_ = x[ErrMalformedPOSTRequest-78]
+	_ = x[ErrPOSTFileRequired-79]
+	_ = x[ErrSignatureVersionNotSupported-80]
+	_ = x[ErrBucketNotEmpty-81]
+	_ = x[ErrAllAccessDisabled-82]
+	_ = x[ErrPolicyInvalidVersion-83]
+	_ = x[ErrMissingFields-84]
+	_ = x[ErrMissingCredTag-85]
+	_ = x[ErrCredMalformed-86]
+	_ = x[ErrInvalidRegion-87]
+	_ = x[ErrInvalidServiceS3-88]
+	_ = x[ErrInvalidServiceSTS-89]
+	_ = x[ErrInvalidRequestVersion-90]
+	_ = x[ErrMissingSignTag-91]
+	_ = x[ErrMissingSignHeadersTag-92]
+	_ = x[ErrMalformedDate-93]
+	_ = x[ErrMalformedPresignedDate-94]
+	_ = x[ErrMalformedCredentialDate-95]
+	_ = x[ErrMalformedCredentialRegion-96]
+	_ = x[ErrMalformedExpires-97]
+	_ = x[ErrNegativeExpires-98]
+	_ = x[ErrAuthHeaderEmpty-99]
+	_ = x[ErrExpiredPresignRequest-100]
+	_ = x[ErrRequestNotReadyYet-101]
+	_ = x[ErrUnsignedHeaders-102]
+	_ = x[ErrMissingDateHeader-103]
+	_ = x[ErrInvalidQuerySignatureAlgo-104]
+	_ = x[ErrInvalidQueryParams-105]
+	_ = x[ErrBucketAlreadyOwnedByYou-106]
+	_ = x[ErrInvalidDuration-107]
+	_ = x[ErrBucketAlreadyExists-108]
+	_ = x[ErrMetadataTooLarge-109]
+	_ = x[ErrUnsupportedMetadata-110]
+	_ = x[ErrMaximumExpires-111]
+	_ = x[ErrSlowDown-112]
+	_ = x[ErrInvalidPrefixMarker-113]
+	_ = x[ErrBadRequest-114]
+	_ = x[ErrKeyTooLongError-115]
+	_ = x[ErrInvalidBucketObjectLockConfiguration-116]
+	_ = x[ErrObjectLockConfigurationNotFound-117]
+	_ = x[ErrObjectLockConfigurationNotAllowed-118]
+	_ = x[ErrNoSuchObjectLockConfiguration-119]
+	_ = x[ErrObjectLocked-120]
+	_ = x[ErrInvalidRetentionDate-121]
+	_ = x[ErrPastObjectLockRetainDate-122]
+	_ = x[ErrUnknownWORMModeDirective-123]
+	_ = x[ErrBucketTaggingNotFound-124]
+	_ = x[ErrObjectLockInvalidHeaders-125]
+	_ = x[ErrInvalidTagDirective-126]
+	_ = x[ErrInvalidEncryptionMethod-127]
+	_ = x[ErrInvalidEncryptionKeyID-128]
+	_ = x[ErrInsecureSSECustomerRequest-129]
+	_ = x[ErrSSEMultipartEncrypted-130]
+	_ = x[ErrSSEEncryptedObject-131]
+	_ = x[ErrInvalidEncryptionParameters-132]
+	_ = x[ErrInvalidSSECustomerAlgorithm-133]
+	_ = x[ErrInvalidSSECustomerKey-134]
+	_ = x[ErrMissingSSECustomerKey-135]
+	_ = x[ErrMissingSSECustomerKeyMD5-136]
+	_ = x[ErrSSECustomerKeyMD5Mismatch-137]
+	_ = x[ErrInvalidSSECustomerParameters-138]
+	_ = x[ErrIncompatibleEncryptionMethod-139]
+	_ = x[ErrKMSNotConfigured-140]
+	_ = x[ErrKMSKeyNotFoundException-141]
+	_ = x[ErrNoAccessKey-142]
+	_ = x[ErrInvalidToken-143]
+	_ = x[ErrEventNotification-144]
+	_ = x[ErrARNNotification-145]
+	_ = x[ErrRegionNotification-146]
+	_ = x[ErrOverlappingFilterNotification-147]
+	_ = x[ErrFilterNameInvalid-148]
+	_ = x[ErrFilterNamePrefix-149]
+	_ = x[ErrFilterNameSuffix-150]
+	_ = x[ErrFilterValueInvalid-151]
+	_ = x[ErrOverlappingConfigs-152]
+	_ = x[ErrUnsupportedNotification-153]
+	_ = x[ErrContentSHA256Mismatch-154]
+	_ = x[ErrContentChecksumMismatch-155]
+	_ = x[ErrReadQuorum-156]
+	_ = x[ErrWriteQuorum-157]
+	_ = x[ErrStorageFull-158]
+	_ = x[ErrRequestBodyParse-159]
+	_ = x[ErrObjectExistsAsDirectory-160]
+	_ = x[ErrInvalidObjectName-161]
+	_ = x[ErrInvalidObjectNamePrefixSlash-162]
+	_ = x[ErrInvalidResourceName-163]
+	_ = x[ErrServerNotInitialized-164]
+	_ = x[ErrOperationTimedOut-165]
+	_ = x[ErrClientDisconnected-166]
+	_ = x[ErrOperationMaxedOut-167]
+	_ = x[ErrInvalidRequest-168]
+	_ = x[ErrTransitionStorageClassNotFoundError-169]
+	_ = x[ErrInvalidStorageClass-170]
+	_ = x[ErrBackendDown-171]
+	_ = x[ErrMalformedJSON-172]
+	_ = x[ErrAdminNoSuchUser-173]
+	_ = x[ErrAdminNoSuchGroup-174]
+	_ = x[ErrAdminGroupNotEmpty-175]
+	_ = x[ErrAdminNoSuchJob-176]
+	_ = x[ErrAdminNoSuchPolicy-177]
+	_ = x[ErrAdminPolicyChangeAlreadyApplied-178]
+	_ = x[ErrAdminInvalidArgument-179]
+	_ = x[ErrAdminInvalidAccessKey-180]
+	_ = x[ErrAdminInvalidSecretKey-181]
+	_ = x[ErrAdminConfigNoQuorum-182]
+	_ = x[ErrAdminConfigTooLarge-183]
+	_ = x[ErrAdminConfigBadJSON-184]
+	_ = x[ErrAdminNoSuchConfigTarget-185]
+	_ = x[ErrAdminConfigEnvOverridden-186]
+	_ = x[ErrAdminConfigDuplicateKeys-187]
+	_ = x[ErrAdminConfigInvalidIDPType-188]
+	_ = x[ErrAdminConfigLDAPValidation-189]
+	_ = x[ErrAdminConfigIDPCfgNameAlreadyExists-190]
+	_ = x[ErrAdminConfigIDPCfgNameDoesNotExist-191]
+	_ = x[ErrAdminCredentialsMismatch-192]
+	_ = x[ErrInsecureClientRequest-193]
+	_ = x[ErrObjectTampered-194]
+	_ = x[ErrSiteReplicationInvalidRequest-195]
+	_ = x[ErrSiteReplicationPeerResp-196]
+	_ = x[ErrSiteReplicationBackendIssue-197]
+	_ = x[ErrSiteReplicationServiceAccountError-198]
+	_ = x[ErrSiteReplicationBucketConfigError-199]
+	_ = x[ErrSiteReplicationBucketMetaError-200]
+	_ = x[ErrSiteReplicationIAMError-201]
+	_ = x[ErrSiteReplicationConfigMissing-202]
+	_ = x[ErrAdminRebalanceAlreadyStarted-203]
+	_ = x[ErrAdminRebalanceNotStarted-204]
+	_ = x[ErrAdminBucketQuotaExceeded-205]
+	_ = x[ErrAdminNoSuchQuotaConfiguration-206]
+	_ = x[ErrHealNotImplemented-207]
+	_ = x[ErrHealNoSuchProcess-208]
+	_ = x[ErrHealInvalidClientToken-209]
+	_ = x[ErrHealMissingBucket-210]
+	_ = x[ErrHealAlreadyRunning-211]
+	_ = x[ErrHealOverlappingPaths-212]
+	_ = x[ErrIncorrectContinuationToken-213]
+	_ = x[ErrEmptyRequestBody-214]
+	_ = x[ErrUnsupportedFunction-215]
+	_ = x[ErrInvalidExpressionType-216]
+	_ = x[ErrBusy-217]
+	_ = x[ErrUnauthorizedAccess-218]
+	_ = x[ErrExpressionTooLong-219]
+	_ = x[ErrIllegalSQLFunctionArgument-220]
+	_ = x[ErrInvalidKeyPath-221]
+	_ = x[ErrInvalidCompressionFormat-222]
+	_ = x[ErrInvalidFileHeaderInfo-223]
+	_ = x[ErrInvalidJSONType-224]
+	_ = x[ErrInvalidQuoteFields-225]
+	_ = x[ErrInvalidRequestParameter-226]
+	_ = x[ErrInvalidDataType-227]
+	_ = x[ErrInvalidTextEncoding-228]
+	_ = x[ErrInvalidDataSource-229]
+	_ = x[ErrInvalidTableAlias-230]
+	_ = x[ErrMissingRequiredParameter-231]
+	_ = x[ErrObjectSerializationConflict-232]
+	_ = x[ErrUnsupportedSQLOperation-233]
+	_ = x[ErrUnsupportedSQLStructure-234]
+	_ = x[ErrUnsupportedSyntax-235]
+	_ = x[ErrUnsupportedRangeHeader-236]
+	_ = x[ErrLexerInvalidChar-237]
+	_ = x[ErrLexerInvalidOperator-238]
+	_ = x[ErrLexerInvalidLiteral-239]
+	_ = x[ErrLexerInvalidIONLiteral-240]
+	_ = x[ErrParseExpectedDatePart-241]
+	_ = x[ErrParseExpectedKeyword-242]
+	_ = x[ErrParseExpectedTokenType-243]
+	_ = x[ErrParseExpected2TokenTypes-244]
+	_ = x[ErrParseExpectedNumber-245]
+	_ = x[ErrParseExpectedRightParenBuiltinFunctionCall-246]
+	_ = x[ErrParseExpectedTypeName-247]
+	_ = x[ErrParseExpectedWhenClause-248]
+	_ = x[ErrParseUnsupportedToken-249]
+	_ = x[ErrParseUnsupportedLiteralsGroupBy-250]
+	_ = x[ErrParseExpectedMember-251]
+	_ = x[ErrParseUnsupportedSelect-252]
+	_ = x[ErrParseUnsupportedCase-253]
+	_ = x[ErrParseUnsupportedCaseClause-254]
+	_ = x[ErrParseUnsupportedAlias-255]
+	_ = x[ErrParseUnsupportedSyntax-256]
+	_ = x[ErrParseUnknownOperator-257]
+	_ = x[ErrParseMissingIdentAfterAt-258]
+	_ = x[ErrParseUnexpectedOperator-259]
+	_ = x[ErrParseUnexpectedTerm-260]
+	_ = x[ErrParseUnexpectedToken-261]
+	_ = x[ErrParseUnexpectedKeyword-262]
+	_ = x[ErrParseExpectedExpression-263]
+	_ = x[ErrParseExpectedLeftParenAfterCast-264]
+	_ = x[ErrParseExpectedLeftParenValueConstructor-265]
+	_ = x[ErrParseExpectedLeftParenBuiltinFunctionCall-266]
+	_ = x[ErrParseExpectedArgumentDelimiter-267]
+	_ = x[ErrParseCastArity-268]
+	_ = x[ErrParseInvalidTypeParam-269]
+	_ = x[ErrParseEmptySelect-270]
+	_ = x[ErrParseSelectMissingFrom-271]
+	_ = x[ErrParseExpectedIdentForGroupName-272]
+	_ = x[ErrParseExpectedIdentForAlias-273]
+	_ = x[ErrParseUnsupportedCallWithStar-274]
+	_ = x[ErrParseNonUnaryAgregateFunctionCall-275]
+	_ = x[ErrParseMalformedJoin-276]
+	_ = x[ErrParseExpectedIdentForAt-277]
+	_ = x[ErrParseAsteriskIsNotAloneInSelectList-278]
+	_ = x[ErrParseCannotMixSqbAndWildcardInSelectList-279]
+	_ = x[ErrParseInvalidContextForWildcardInSelectList-280]
+	_ = x[ErrIncorrectSQLFunctionArgumentType-281]
+	_ = x[ErrValueParseFailure-282]
+	_ = x[ErrEvaluatorInvalidArguments-283]
+	_ = x[ErrIntegerOverflow-284]
+	_ = x[ErrLikeInvalidInputs-285]
+	_ = x[ErrCastFailed-286]
+	_ = x[ErrInvalidCast-287]
+	_ = x[ErrEvaluatorInvalidTimestampFormatPattern-288]
+	_ = x[ErrEvaluatorInvalidTimestampFormatPatternSymbolForParsing-289]
+	_ = x[ErrEvaluatorTimestampFormatPatternDuplicateFields-290]
+	_ = x[ErrEvaluatorTimestampFormatPatternHourClockAmPmMismatch-291]
+	_ = x[ErrEvaluatorUnterminatedTimestampFormatPatternToken-292]
+	_ = x[ErrEvaluatorInvalidTimestampFormatPatternToken-293]
+	_ = x[ErrEvaluatorInvalidTimestampFormatPatternSymbol-294]
+	_ = x[ErrEvaluatorBindingDoesNotExist-295]
+	_ = x[ErrMissingHeaders-296]
+	_ = x[ErrInvalidColumnIndex-297]
+	_ = x[ErrAdminConfigNotificationTargetsFailed-298]
+	_ = x[ErrAdminProfilerNotEnabled-299]
+	_ = x[ErrInvalidDecompressedSize-300]
+	_ = x[ErrAddUserInvalidArgument-301]
+	_ = x[ErrAdminResourceInvalidArgument-302]
+	_ = x[ErrAdminAccountNotEligible-303]
+	_ = x[ErrAccountNotEligible-304]
+	_ = x[ErrAdminServiceAccountNotFound-305]
+	_ = x[ErrPostPolicyConditionInvalidFormat-306]
+	_ = x[ErrInvalidChecksum-307]
 }
 
-const _APIErrorCode_name = "NoneAccessDeniedBadDigestEntityTooSmallEntityTooLargePolicyTooLargeIncompleteBodyInternalErrorInvalidAccessKeyIDAccessKeyDisabledInvalidBucketNameInvalidDigestInvalidRangeInvalidRangePartNumberInvalidCopyPartRangeInvalidCopyPartRangeSourceInvalidMaxKeysInvalidEncodingMethodInvalidMaxUploadsInvalidMaxPartsInvalidPartNumberMarkerInvalidPartNumberInvalidRequestBodyInvalidCopySourceInvalidMetadataDirectiveInvalidCopyDestInvalidPolicyDocumentInvalidObjectStateMalformedXMLMissingContentLengthMissingContentMD5MissingRequestBodyErrorMissingSecurityHeaderNoSuchBucketNoSuchBucketPolicyNoSuchBucketLifecycleNoSuchLifecycleConfigurationInvalidLifecycleWithObjectLockNoSuchBucketSSEConfigNoSuchCORSConfigurationNoSuchWebsiteConfigurationReplicationConfigurationNotFoundErrorRemoteDestinationNotFoundErrorReplicationDestinationMissingLockRemoteTargetNotFoundErrorReplicationRemoteConnectionErrorReplicationBandwidthLimitErrorBucketRemoteIdenticalToSourceBucketRemoteAlreadyExistsBucketRemoteLabelInUseBucketRemoteArnTypeInvalidBucketRemoteArnInvalidBucketRemoteRemoveDisallowedRemoteTargetNotVersionedErrorReplicationSourceNotVersionedErrorReplicationNeedsVersioningErrorReplicationBucketNeedsVersioningErrorReplicationDenyEditErrorReplicationNoExistingObjectsObjectRestoreAlreadyInProgressNoSuchKeyNoSuchUploadInvalidVersionIDNoSuchVersionNotImplementedPreconditionFailedRequestTimeTooSkewedSignatureDoesNotMatchMethodNotAllowedInvalidPartInvalidPartOrderAuthorizationHeaderMalformedMalformedPOSTRequestPOSTFileRequiredSignatureVersionNotSupportedBucketNotEmptyAllAccessDisabledPolicyInvalidVersionMissingFieldsMissingCredTagCredMalformedInvalidRegionInvalidServiceS3InvalidServiceSTSInvalidRequestVersionMissingSignTagMissingSignHeadersTagMalformedDateMalformedPresignedDateMalformedCredentialDateMalformedCredentialRegionMalformedExpiresNegativeExpiresAuthHeaderEmptyExpiredPresignRequestRequestNotReadyYetUnsignedHeadersMissingDateHeaderInvalidQuerySignatureAlgoInvalidQueryParamsBucketAlreadyOwnedByYouInvalidDurationBucketAlreadyExistsMetadataTooLargeUnsupportedMetadataMaximumExpiresSlowDownInvalidPrefixMarkerBadRequestKeyTooLongErrorInvalidBucketObjectLockConfigurationObjectLockConfigurationNotFoundObjectLockConfigurationNotAllowedNoSuchObjectLockConfigurationObjectLockedInvalidRetentionDatePastObjectLockRetainDateUnknownWORMModeDirectiveBucketTaggingNotFoundObjectLockInvalidHeadersInvalidTagDirectiveInvalidEncryptionMethodInvalidEncryptionKeyIDInsecureSSECustomerRequestSSEMultipartEncryptedSSEEncryptedObjectInvalidEncryptionParametersInvalidSSECustomerAlgorithmInvalidSSECustomerKeyMissingSSECustomerKeyMissingSSECustomerKeyMD5SSECustomerKeyMD5MismatchInvalidSSECustomerParametersIncompatibleEncryptionMethodKMSNotConfiguredKMSKeyNotFoundExceptionNoAccessKeyInvalidTokenEventNotificationARNNotificationRegionNotificationOverlappingFilterNotificationFilterNameInvalidFilterNamePrefixFilterNameSuffixFilterValueInvalidOverlappingConfigsUnsupportedNotificationContentSHA256MismatchContentChecksumMismatchReadQuorumWriteQuorumStorageFullRequestBodyParseObjectExistsAsDirectoryInvalidObjectNameInvalidObjectNamePrefixSlashInvalidResourceNameServerNotInitializedOperationTimedOutClientDisconnectedOperationMaxedOutInvalidRequestTransitionStorageClassNotFoundErrorInvalidStorageClassBackendDownMalformedJSONAdminNoSuchUserAdminNoSuchGroupAdminGroupNotEmptyAdminNoSuchJobAdminNoSuchPolicyAdminPolicyChangeAlreadyAppliedAdminInvalidArgumentAdminInvalidAccessKeyAdminInvalidSecretKeyAdminConfigNoQuorumAdminConfigTooLargeAdminConfigBadJSONAdminNoSuchConfigTargetAdminConfigEnvOverriddenAdminConfigDuplicateKeysAdminConfigInvalidIDPTypeAdminConfigLDAPValidationAdminConfigIDPCfgNameAlreadyExistsAdminConfigIDPCfgNameDoesNotExistAdminCredentialsMismatchInsecureClientRequestObjectTamperedSiteReplicationInvalidRequestSiteReplicationPeerRespSiteReplicationBackendIssueSiteReplicationServiceAccountErrorSiteReplicationBucketConfigErrorSiteReplicationBucketMetaErrorSiteReplicationIAMErrorSiteReplicationConfigMissingAdminRebalanceAlreadyStartedAdminRebalanceNotStartedAdminBucketQuotaExceededAdminNoSuchQuotaConfigurationHealNotImplementedHealNoSuchProcessHealInvalidClientTokenHealMissingBucketHealAlreadyRunningHealOverlappingPathsIncorrectContinuationTokenEmptyRequestBodyUnsupportedFunctionInvalidExpressionTypeBusyUnauthorizedAccessExpressionTooLongIllegalSQLFunctionArgumentInvalidKeyPathInvalidCompressionFormatInvalidFileHeaderInfoInvalidJSONTypeInvalidQuoteFieldsInvalidRequestParameterInvalidDataTypeInvalidTextEncodingInvalidDataSourceInvalidTableAliasMissingRequiredParameterObjectSerializationConflictUnsupportedSQLOperationUnsupportedSQLStructureUnsupportedSyntaxUnsupportedRangeHeaderLexerInvalidCharLexerInvalidOperatorLexerInvalidLiteralLexerInvalidIONLiteralParseExpectedDatePartParseExpectedKeywordParseExpectedTokenTypeParseExpected2TokenTypesParseExpectedNumberParseExpectedRightParenBuiltinFunctionCallParseExpectedTypeNameParseExpectedWhenClauseParseUnsupportedTokenParseUnsupportedLiteralsGroupByParseExpectedMemberParseUnsupportedSelectParseUnsupportedCaseParseUnsupportedCaseClauseParseUnsupportedAliasParseUnsupportedSyntaxParseUnknownOperatorParseMissingIdentAfterAtParseUnexpectedOperatorParseUnexpectedTermParseUnexpectedTokenParseUnexpectedKeywordParseExpectedExpressionParseExpectedLeftParenAfterCastParseExpectedLeftParenValueConstructorParseExpectedLeftParenBuiltinFunctionCallParseExpectedArgumentDelimiterParseCastArityParseInvalidTypeParamParseEmptySelectParseSelectMissingFromParseExpectedIdentForGroupNameParseExpectedIdentForAliasParseUnsupportedCallWithStarParseNonUnaryAgregateFunctionCallParseMalformedJoinParseExpectedIdentForAtParseAsteriskIsNotAloneInSelectListParseCannotMixSqbAndWildcardInSelectListParseInvalidContextForWildcardInSelectListIncorrectSQLFunctionArgumentTypeValueParseFailureEvaluatorInvalidArgumentsIntegerOverflowLikeInvalidInputsCastFailedInvalidCastEvaluatorInvalidTimestampFormatPatternEvaluatorInvalidTimestampFormatPatternSymbolForParsingEvaluatorTimestampFormatPatternDuplicateFieldsEvaluatorTimestampFormatPatternHourClockAmPmMismatchEvaluatorUnterminatedTimestampFormatPatternTokenEvaluatorInvalidTimestampFormatPatternTokenEvaluatorInvalidTimestampFormatPatternSymbolEvaluatorBindingDoesNotExistMissingHeadersInvalidColumnIndexAdminConfigNotificationTargetsFailedAdminProfilerNotEnabledInvalidDecompressedSizeAddUserInvalidArgumentAdminResourceInvalidArgumentAdminAccountNotEligibleAccountNotEligibleAdminServiceAccountNotFoundPostPolicyConditionInvalidFormatInvalidChecksum"
+const _APIErrorCode_name = "NoneAccessDeniedBadDigestEntityTooSmallEntityTooLargePolicyTooLargeIncompleteBodyInternalErrorInvalidAccessKeyIDAccessKeyDisabledInvalidBucketNameInvalidDigestInvalidRangeInvalidRangePartNumberInvalidCopyPartRangeInvalidCopyPartRangeSourceInvalidMaxKeysInvalidEncodingMethodInvalidMaxUploadsInvalidMaxPartsInvalidPartNumberMarkerInvalidPartNumberMissingObjectAttributesInvalidObjectAttributesInvalidRequestBodyInvalidCopySourceInvalidMetadataDirectiveInvalidCopyDestInvalidPolicyDocumentInvalidObjectStateMalformedXMLMissingContentLengthMissingContentMD5MissingRequestBodyErrorMissingSecurityHeaderNoSuchBucketNoSuchBucketPolicyNoSuchBucketLifecycleNoSuchLifecycleConfigurationInvalidLifecycleWithObjectLockNoSuchBucketSSEConfigNoSuchCORSConfigurationCORSForbiddenNoSuchWebsiteConfigurationInvalidTargetBucketForLoggingNoSuchInventoryConfigurationInvalidInventoryDestinationReplicationConfigurationNotFoundErrorRemoteDestinationNotFoundErrorReplicationDestinationMissingLockRemoteTargetNotFoundErrorReplicationRemoteConnectionErrorReplicationBandwidthLimitErrorBucketRemoteIdenticalToSourceBucketRemoteAlreadyExistsBucketRemoteLabelInUseBucketRemoteArnTypeInvalidBucketRemoteArnInvalidBucketRemoteRemoveDisallowedRemoteTargetNotVersionedErrorReplicationSourceNotVersionedErrorReplicationNeedsVersioningErrorReplicationBucketNeedsVersioningErrorReplicationDenyEditErrorReplicationNoExistingObjectsObjectRestoreAlreadyInProgressNoSuchKeyNoSuchUploadInvalidVersionIDNoSuchVersionNotImplementedPreconditionFailedRequestTimeTooSkewedSignatureDoesNotMatchMethodNotAllowedInvalidPartInvalidPartOrderAuthorizationHeaderMalformedMalformedPOSTRequestPOSTFileRequiredSignatureVersionNotSupportedBucketNotEmptyAllAccessDisabledPolicyInvalidVersionMissingFieldsMissingCredTagCredMalformedInvalidRegionInvalidServiceS3InvalidServiceSTSInvalidRequestVersionMissingSignTagMissingSignHeadersTagMalformedDateMalformedPresignedDateMalformedCredentialDateMalformedCredentialRegionMalformedExpiresNegativeExpiresAuthHeaderEmptyExpiredPresignRequestRequestNotReadyYetUnsignedHeadersMissingDateHeaderInvalidQuerySignatureAlgoInvalidQueryParamsBucketAlreadyOwnedByYouInvalidDurationBucketAlreadyExistsMetadataTooLargeUnsupportedMetadataMaximumExpiresSlowDownInvalidPrefixMarkerBadRequestKeyTooLongErrorInvalidBucketObjectLockConfigurationObjectLockConfigurationNotFoundObjectLockConfigurationNotAllowedNoSuchObjectLockConfigurationObjectLockedInvalidRetentionDatePastObjectLockRetainDateUnknownWORMModeDirectiveBucketTaggingNotFoundObjectLockInvalidHeadersInvalidTagDirectiveInvalidEncryptionMethodInvalidEncryptionKeyIDInsecureSSECustomerRequestSSEMultipartEncryptedSSEEncryptedObjectInvalidEncryptionParametersInvalidSSECustomerAlgorithmInvalidSSECustomerKeyMissingSSECustomerKeyMissingSSECustomerKeyMD5SSECustomerKeyMD5MismatchInvalidSSECustomerParametersIncompatibleEncryptionMethodKMSNotConfiguredKMSKeyNotFoundExceptionNoAccessKeyInvalidTokenEventNotificationARNNotificationRegionNotificationOverlappingFilterNotificationFilterNameInvalidFilterNamePrefixFilterNameSuffixFilterValueInvalidOverlappingConfigsUnsupportedNotificationContentSHA256MismatchContentChecksumMismatchReadQuorumWriteQuorumStorageFullRequestBodyParseObjectExistsAsDirectoryInvalidObjectNameInvalidObjectNamePrefixSlashInvalidResourceNameServerNotInitializedOperationTimedOutClientDisconnectedOperationMaxedOutInvalidRequestTransitionStorageClassNotFoundErrorInvalidStorageClassBackendDownMalformedJSONAdminNoSuchUserAdminNoSuchGroupAdminGroupNotEmptyAdminNoSuchJobAdminNoSuchPolicyAdminPolicyChangeAlreadyAppliedAdminInvalidArgumentAdminInvalidAccessKeyAdminInvalidSecretKeyAdminConfigNoQuorumAdminConfigTooLargeAdminConfigBadJSONAdminNoSuchConfigTargetAdminConfigEnvOverriddenAdminConfigDuplicateKeysAdminConfigInvalidIDPTypeAdminConfigLDAPValidationAdminConfigIDPCfgNameAlreadyExistsAdminConfigIDPCfgNameDoesNotExistAdminCredentialsMismatchInsecureClientRequestObjectTamperedSiteReplicationInvalidRequestSiteReplicationPeerRespSiteReplicationBackendIssueSiteReplicationServiceAccountErrorSiteReplicationBucketConfigErrorSiteReplicationBucketMetaErrorSiteReplicationIAMErrorSiteReplicationConfigMissingAdminRebalanceAlreadyStartedAdminRebalanceNotStartedAdminBucketQuotaExceededAdminNoSuchQuotaConfigurationHealNotImplementedHealNoSuchProcessHealInvalidClientTokenHealMissingBucketHealAlreadyRunningHealOverlappingPathsIncorrectContinuationTokenEmptyRequestBodyUnsupportedFunctionInvalidExpressionTypeBusyUnauthorizedAccessExpressionTooLongIllegalSQLFunctionArgumentInvalidKeyPathInvalidCompressionFormatInvalidFileHeaderInfoInvalidJSONTypeInvalidQuoteFieldsInvalidRequestParameterInvalidDataTypeInvalidTextEncodingInvalidDataSourceInvalidTableAliasMissingRequiredParameterObjectSerializationConflictUnsupportedSQLOperationUnsupportedSQLStructureUnsupportedSyntaxUnsupportedRangeHeaderLexerInvalidCharLexerInvalidOperatorLexerInvalidLiteralLexerInvalidIONLiteralParseExpectedDatePartParseExpectedKeywordParseExpectedTokenTypeParseExpected2TokenTypesParseExpectedNumberParseExpectedRightParenBuiltinFunctionCallParseExpectedTypeNameParseExpectedWhenClauseParseUnsupportedTokenParseUnsupportedLiteralsGroupByParseExpectedMemberParseUnsupportedSelectParseUnsupportedCaseParseUnsupportedCaseClauseParseUnsupportedAliasParseUnsupportedSyntaxParseUnknownOperatorParseMissingIdentAfterAtParseUnexpectedOperatorParseUnexpectedTermParseUnexpectedTokenParseUnexpectedKeywordParseExpectedExpressionParseExpectedLeftParenAfterCastParseExpectedLeftParenValueConstructorParseExpectedLeftParenBuiltinFunctionCallParseExpectedArgumentDelimiterParseCastArityParseInvalidTypeParamParseEmptySelectParseSelectMissingFromParseExpectedIdentForGroupNameParseExpectedIdentForAliasParseUnsupportedCallWithStarParseNonUnaryAgregateFunctionCallParseMalformedJoinParseExpectedIdentForAtParseAsteriskIsNotAloneInSelectListParseCannotMixSqbAndWildcardInSelectListParseInvalidContextForWildcardInSelectListIncorrectSQLFunctionArgumentTypeValueParseFailureEvaluatorInvalidArgumentsIntegerOverflowLikeInvalidInputsCastFailedInvalidCastEvaluatorInvalidTimestampFormatPatternEvaluatorInvalidTimestampFormatPatternSymbolForParsingEvaluatorTimestampFormatPatternDuplicateFieldsEvaluatorTimestampFormatPatternHourClockAmPmMismatchEvaluatorUnterminatedTimestampFormatPatternTokenEvaluatorInvalidTimestampFormatPatternTokenEvaluatorInvalidTimestampFormatPatternSymbolEvaluatorBindingDoesNotExistMissingHeadersInvalidColumnIndexAdminConfigNotificationTargetsFailedAdminProfilerNotEnabledInvalidDecompressedSizeAddUserInvalidArgumentAdminResourceInvalidArgumentAdminAccountNotEligibleAccountNotEligibleAdminServiceAccountNotFoundPostPolicyConditionInvalidFormatInvalidChecksum"
 
-var _APIErrorCode_index = [...]uint16{0, 4, 16, 25, 39, 53, 67, 81, 94, 112, 129, 146, 159, 171, 193, 213, 239, 253, 274, 291, 306, 329, 346, 364, 381, 405, 420, 441, 459, 471, 491, 508, 531, 552, 564, 582, 603, 631, 661, 682, 705, 731, 768, 798, 831, 856, 888, 918, 947, 972, 994, 1020, 1042, 1070, 1099, 1133, 1164, 1201, 1225, 1253, 1283, 1292, 1304, 1320, 1333, 1347, 1365, 1385, 1406, 1422, 1433, 1449, 1477, 1497, 1513, 1541, 1555, 1572, 1592, 1605, 1619, 1632, 1645, 1661, 1678, 1699, 1713, 1734, 1747, 1769, 1792, 1817, 1833, 1848, 1863, 1884, 1902, 1917, 1934, 1959, 1977, 2000, 2015, 2034, 2050, 2069, 2083, 2091, 2110, 2120, 2135, 2171, 2202, 2235, 2264, 2276, 2296, 2320, 2344, 2365, 2389, 2408, 2431, 2453, 2479, 2500, 2518, 2545, 2572, 2593, 2614, 2638, 2663, 2691, 2719, 2735, 2758, 2769, 2781, 2798, 2813, 2831, 2860, 2877, 2893, 2909, 2927, 2945, 2968, 2989, 3012, 3022, 3033, 3044, 3060, 3083, 3100, 3128, 3147, 3167, 3184, 3202, 3219, 3233, 3268, 3287, 3298, 3311, 3326, 3342, 3360, 3374, 3391, 3422, 3442, 3463, 3484, 3503, 3522, 3540, 3563, 3587, 3611, 3636, 3661, 3695, 3728, 3752, 3773, 3787, 3816, 3839, 3866, 3900, 3932, 3962, 3985, 4013, 4041, 4065, 4089, 4118, 4136, 4153, 4175, 4192, 4210, 4230, 4256, 4272, 4291, 4312, 4316, 4334, 4351, 4377, 4391, 4415, 4436, 4451, 4469, 4492, 4507, 4526, 4543, 4560, 4584, 4611, 4634, 4657, 4674, 4696, 4712, 4732, 4751, 4773, 4794, 4814, 4836, 4860, 4879, 4921, 4942, 4965, 4986, 5017, 5036, 5058, 5078, 5104, 5125, 5147, 5167, 5191, 5214, 5233, 5253, 5275, 5298, 5329, 5367, 5408, 5438, 5452, 5473, 5489, 5511, 5541, 5567, 5595, 5628, 5646, 5669, 5704, 5744, 5786, 5818, 5835, 5860, 5875, 5892, 5902, 5913, 5951, 6005, 6051, 6103, 6151, 6194, 6238, 6266, 6280, 6298, 6334, 6357, 6380, 6402, 6430, 6453, 6471, 6498, 6530, 6545}
+var _APIErrorCode_index = [...]uint16{0, 4, 16, 25, 39, 53, 67, 81, 94, 112, 129, 146, 159, 171, 193, 213, 239, 253, 274, 291, 306, 329, 346, 369, 392, 410, 427, 451, 466, 487, 505, 517, 537, 554, 577, 598, 610, 628, 649, 677, 707, 728, 751, 764, 790, 819, 847, 874, 911, 941, 974, 999, 1031, 1061, 1090, 1115, 1137, 1163, 1185, 1213, 1242, 1276, 1307, 1344, 1368, 1396, 1426, 1435, 1447, 1463, 1476, 1490, 1508, 1528, 1549, 1565, 1576, 1592, 1620, 1640, 1656, 1684, 1698, 1715, 1735, 1748, 1762, 1775, 1788, 1804, 1821, 1842, 1856, 1877, 1890, 1912, 1935, 1960, 1976, 1991, 2006, 2027, 2045, 2060, 2077, 2102, 2120, 2143, 2158, 2177, 2193, 2212, 2226, 2234, 2253, 2263, 2278, 2314, 2345, 2378, 2407, 2419, 2439, 2463, 2487, 2508, 2532, 2551, 2574, 2596, 2622, 2643, 2661, 2688, 2715, 2736, 2757, 2781, 2806, 2834, 2862, 2878, 2901, 2912, 2924, 2941, 2956, 2974, 3003, 3020, 3036, 3052, 3070, 3088, 3111, 3132, 3155, 3165, 3176, 3187, 3203, 3226, 3243, 3271, 3290, 3310, 3327, 3345, 3362, 3376, 3411, 3430, 3441, 3454, 3469, 3485, 3503, 3517, 3534, 3565, 3585, 3606, 3627, 3646, 3665, 3683, 3706, 3730, 3754, 3779, 3804, 3838, 3871, 3895, 3916, 3930, 3959, 3982, 4009, 4043, 4075, 4105, 4128, 4156, 4184, 4208, 4232, 4261, 4279, 4296, 4318, 4335, 4353, 4373, 4399, 4415, 4434, 4455, 4459, 4477, 4494, 4520, 4534, 4558, 4579, 4594, 4612, 4635, 4650, 4669, 4686, 4703, 4727, 4754, 4777, 4800, 4817, 4839, 4855, 4875, 4894, 4916, 4937, 4957, 4979, 5003, 5022, 5064, 5085, 5108, 5129, 5160, 5179, 5201, 5221, 5247, 5268, 5290, 5310, 5334, 5357, 5376, 5396, 5418, 5441, 5472, 5510, 5551, 5581, 5595, 5616, 5632, 5654, 5684, 5710, 5738, 5771, 5789, 5812, 5847, 5887, 5929, 5961, 5978, 6003, 6018, 6035, 6045, 6056, 6094, 6148, 6194, 6246, 6294, 6337, 6381, 6409, 6423, 6441, 6477, 6500, 6523, 6545, 6573, 6596, 6614, 6641, 6673, 6688}
 
 func (i APIErrorCode) String() string {
 	if i < 0 || i >= APIErrorCode(len(_APIErrorCode_index)-1) {
@@ -0,0 +1,415 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/infobsmi/b33s-go/v7/pkg/tags"
+	"github.com/infobsmi/b33s/internal/event"
+	"github.com/infobsmi/b33s/internal/logger"
+	"github.com/minio/madmin-go/v2"
+)
+
+// expire:
+//   apiVersion: v1
+//   bucket: "testbucket"
+//   prefix: "spark/"
+//
+//   # optional flags based filtering criteria
+//   # for the object versions to be deleted
+//   flags:
+//     filter:
+//       olderThan: "168h"
+//       createdBefore: "date"
+//       tags:
+//         - key: "name"
+//           value: "value*"
+//       metadata:
+//         - key: "content-type"
+//           value: "image/*"
+//       size:
+//         lessThan: "10MiB"
+//         greaterThan: "1KiB"
+//       deleteMarkersOnly: false
+//     notify:
+//       endpoint: "https://splunk-hec.dev.com"
+//       token: "Splunk ..." # e.g. "Bearer token"
+//     retry:
+//       attempts: 10
+//       delay: "500ms"
+
+//go:generate msgp -file $GOFILE -unexported
+
+const (
+	// batchJobTypeExpire - batch job deleting object versions.
+	batchJobTypeExpire madmin.BatchJobType = "expire"
+
+	batchExpireName          = "batch-expire.bin"
+	batchExpireJobAPIVersion = "v1"
+
+	// BatchExpire - audit trail for object versions deleted by
+	// an expire batch job.
+	BatchExpire = "batch:expire"
+)
+
+// BatchJobSize - size in bytes, written in humanized form
+// (e.g. "10MiB") in the job definition.
+type BatchJobSize int64
+
+// UnmarshalYAML - parses a humanized size.
+func (s *BatchJobSize) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	sz, err := humanize.ParseBytes(str)
+	if err != nil {
+		return err
+	}
+	*s = BatchJobSize(sz)
+	return nil
+}
+
+// MarshalYAML - returns the humanized size.
+func (s BatchJobSize) MarshalYAML() (interface{}, error) {
+	return humanize.IBytes(uint64(s)), nil
+}
+
+// BatchJobSizeFilter - size range of the object versions to be
+// deleted, a zero bound is ignored.
+type BatchJobSizeFilter struct {
+	LessThan    BatchJobSize `yaml:"lessThan,omitempty" json:"lessThan"`
+	GreaterThan BatchJobSize `yaml:"greaterThan,omitempty" json:"greaterThan"`
+}
+
+// Validate returns an error if the size range is empty.
+func (sf BatchJobSizeFilter) Validate() error {
+	if sf.LessThan < 0 || sf.GreaterThan < 0 {
+		return errInvalidArgument
+	}
+	if sf.LessThan > 0 && sf.GreaterThan >= sf.LessThan {
+		return errInvalidArgument
+	}
+	return nil
+}
+
+// InRange returns true if size is within the size range.
+func (sf BatchJobSizeFilter) InRange(size int64) bool {
+	if sf.LessThan > 0 && size >= int64(sf.LessThan) {
+		return false
+	}
+	if sf.GreaterThan > 0 && size <= int64(sf.GreaterThan) {
+		return false
+	}
+	return true
+}
+
+// BatchJobExpireFilter holds all the filters currently supported for
+// batch expiration. Delete markers are only deleted when
+// DeleteMarkersOnly is set, the tags, metadata and size filters do not
+// apply to them.
+type BatchJobExpireFilter struct {
+	OlderThan         time.Duration         `yaml:"olderThan,omitempty" json:"olderThan"`
+	CreatedBefore     time.Time             `yaml:"createdBefore,omitempty" json:"createdBefore"`
+	Tags              []BatchJobReplicateKV `yaml:"tags,omitempty" json:"tags"`
+	Metadata          []BatchJobReplicateKV `yaml:"metadata,omitempty" json:"metadata"`
+	Size              BatchJobSizeFilter    `yaml:"size,omitempty" json:"size"`
+	DeleteMarkersOnly bool                  `yaml:"deleteMarkersOnly,omitempty" json:"deleteMarkersOnly"`
+}
+
+// Validate validates the filters.
+func (ef BatchJobExpireFilter) Validate() error {
+	if ef.OlderThan < 0 {
+		return errInvalidArgument
+	}
+	for _, tag := range ef.Tags {
+		if err := tag.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, meta := range ef.Metadata {
+		if err := meta.Validate(); err != nil {
+			return err
+		}
+	}
+	return ef.Size.Validate()
+}
+
+// Matches returns true if the object version oi is selected by the
+// filters. The tags and metadata filters match if any of their
+// key/value pairs match, all the filters must match.
+func (ef BatchJobExpireFilter) Matches(oi ObjectInfo, now time.Time) bool {
+	if ef.OlderThan > 0 && now.Sub(oi.ModTime) < ef.OlderThan {
+		return false
+	}
+	if !ef.CreatedBefore.IsZero() && !oi.ModTime.Before(ef.CreatedBefore) {
+		return false
+	}
+	if oi.DeleteMarker != ef.DeleteMarkersOnly {
+		return false
+	}
+	if oi.DeleteMarker {
+		return true
+	}
+
+	if len(ef.Tags) > 0 {
+		t, err := tags.ParseObjectTags(oi.UserTags)
+		if err != nil {
+			return false
+		}
+		if !batchJobMatchKV(ef.Tags, t.ToMap()) {
+			return false
+		}
+	}
+
+	if len(ef.Metadata) > 0 {
+		if !batchJobMatchMetadata(ef.Metadata, oi.UserDefined) {
+			return false
+		}
+	}
+
+	size, err := oi.GetActualSize()
+	if err != nil {
+		size = oi.Size
+	}
+	return ef.Size.InRange(size)
+}
+
+// batchJobMatchKV returns true if any of the key/value pairs in m is
+// matched by one of kvs.
+func batchJobMatchKV(kvs []BatchJobReplicateKV, m map[string]string) bool {
+	for _, kv := range kvs {
+		for k, v := range m {
+			if kv.Match(BatchJobReplicateKV{Key: k, Value: v}) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// batchJobMatchMetadata returns true if any of the user metadata or
+// standard headers in metadata is matched by one of kvs.
+func batchJobMatchMetadata(kvs []BatchJobReplicateKV, metadata map[string]string) bool {
+	meta := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		// We only need to match x-amz-meta or standardHeaders
+		if strings.HasPrefix(strings.ToLower(k), "x-amz-meta-") || isStandardHeader(k) {
+			meta[k] = v
+		}
+	}
+	return batchJobMatchKV(kvs, meta)
+}
+
+// BatchJobExpireFlags various configurations for expiration job definition currently includes
+// - filter
+// - notify
+// - retry
+type BatchJobExpireFlags struct {
+	Filter BatchJobExpireFilter       `yaml:"filter" json:"filter"`
+	Notify BatchReplicateNotification `yaml:"notify" json:"notify"`
+	Retry  BatchReplicateRetry        `yaml:"retry" json:"retry"`
+}
+
+// BatchJobExpire v1 of batch job expiration, deletes the object
+// versions of bucket/prefix matching the filters.
+type BatchJobExpire struct {
+	APIVersion string              `yaml:"apiVersion" json:"apiVersion"`
+	Bucket     string              `yaml:"bucket" json:"bucket"`
+	Prefix     string              `yaml:"prefix" json:"prefix"`
+	Flags      BatchJobExpireFlags `yaml:"flags" json:"flags"`
+}
+
+// Notify notifies notification endpoint if configured regarding job failure or success.
+func (r BatchJobExpire) Notify(ctx context.Context, body io.Reader) error {
+	return r.Flags.Notify.notify(ctx, body)
+}
+
+// Validate validates the job definition input
+func (r *BatchJobExpire) Validate(ctx context.Context, o ObjectLayer) error {
+	if r == nil {
+		return nil
+	}
+
+	if r.APIVersion != batchExpireJobAPIVersion {
+		return errInvalidArgument
+	}
+
+	if r.Bucket == "" {
+		return errInvalidArgument
+	}
+
+	if _, err := o.GetBucketInfo(ctx, r.Bucket, BucketOptions{}); err != nil {
+		if isErrBucketNotFound(err) {
+			return batchReplicationJobError{
+				Code:           "NoSuchBucket",
+				Description:    "The specified bucket does not exist",
+				HTTPStatusCode: http.StatusNotFound,
+			}
+		}
+		return err
+	}
+
+	if err := r.Flags.Filter.Validate(); err != nil {
+		return err
+	}
+
+	return r.Flags.Retry.Validate()
+}
+
+// Expire deletes the object version oi, versions protected by object
+// lock are skipped.
+func (r *BatchJobExpire) Expire(ctx context.Context, api ObjectLayer, oi ObjectInfo) (deleted bool, err error) {
+	if enforceRetentionForDeletion(ctx, oi) {
+		return false, nil
+	}
+
+	opts := ObjectOptions{
+		VersionID: oi.VersionID,
+	}
+	if opts.VersionID == "" && (globalBucketVersioningSys.Enabled(r.Bucket) || globalBucketVersioningSys.Suspended(r.Bucket)) {
+		// Delete the null version instead of creating a delete marker.
+		opts.VersionID = nullVersionID
+	}
+
+	dobj, err := api.DeleteObject(ctx, r.Bucket, oi.Name, opts)
+	if err != nil {
+		return false, err
+	}
+	if dobj.Name == "" {
+		dobj = oi
+	}
+
+	auditLogInternal(ctx, AuditLogOptions{
+		Event:     BatchExpire,
+		APIName:   "BatchExpire",
+		Bucket:    r.Bucket,
+		Object:    oi.Name,
+		VersionID: oi.VersionID,
+	})
+
+	// Versions are deleted permanently, no delete marker is created.
+	sendEvent(eventArgs{
+		EventName:  event.ObjectRemovedDelete,
+		BucketName: r.Bucket,
+		Object:     dobj,
+		Host:       "Internal: [Batch-Expire]",
+	})
+	return true, nil
+}
+
+// Start start the batch expiration job, resumes if there was a pending job via "job.ID"
+func (r *BatchJobExpire) Start(ctx context.Context, api ObjectLayer, job BatchJobRequest) error {
+	ri := &batchJobInfo{
+		JobID:     job.ID,
+		JobType:   string(job.Type()),
+		StartTime: job.Started,
+	}
+	if err := ri.load(ctx, api, job); err != nil {
+		return err
+	}
+	globalBatchJobsMetrics.save(job.ID, ri.clone())
+	lastObject := ri.Object
+
+	delay := r.Flags.Retry.Delay
+	if delay == 0 {
+		delay = batchReplJobDefaultRetryDelay
+	}
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	retryAttempts := ri.RetryAttempts
+	for attempts := 1; attempts <= retryAttempts; attempts++ {
+		ctx, cancel := context.WithCancel(ctx)
+
+		results := make(chan ObjectInfo, 100)
+		if err := api.Walk(ctx, r.Bucket, r.Prefix, results, ObjectOptions{
+			WalkMarker: lastObject,
+		}); err != nil {
+			cancel()
+			// Do not need to retry if we can't list objects.
+			return err
+		}
+
+		now := UTCNow()
+		for result := range results {
+			if !r.Flags.Filter.Matches(result, now) {
+				continue
+			}
+			stopFn := globalBatchJobsMetrics.trace(batchJobMetricExpire, job.ID, attempts, result)
+			deleted, err := r.Expire(ctx, api, result)
+			if err != nil {
+				if isErrVersionNotFound(err) || isErrObjectNotFound(err) {
+					// object must be deleted concurrently, allow
+					// these failures but do not count them
+					continue
+				}
+				stopFn(err)
+				logger.LogIf(ctx, err)
+			} else {
+				stopFn(nil)
+				if !deleted {
+					// object version is locked, skip it.
+					continue
+				}
+			}
+			ri.trackCurrentBucketObject(r.Bucket, result, err == nil)
+			globalBatchJobsMetrics.save(job.ID, ri.clone())
+			// persist in-memory state to disk after every 10secs.
+			logger.LogIf(ctx, ri.updateAfter(ctx, api, 10*time.Second, job.Location))
+		}
+
+		ri.RetryAttempts = attempts
+		ri.Complete = ri.ObjectsFailed == 0 && ri.DeleteMarkersFailed == 0
+		ri.Failed = !ri.Complete
+
+		globalBatchJobsMetrics.save(job.ID, ri.clone())
+
+		buf, _ := json.Marshal(ri)
+		if err := r.Notify(ctx, bytes.NewReader(buf)); err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to notify %v", err))
+		}
+
+		cancel()
+		if ri.Failed {
+			ri.ObjectsFailed = 0
+			ri.DeleteMarkersFailed = 0
+			ri.Bucket = ""
+			ri.Object = ""
+			ri.Objects = 0
+			ri.DeleteMarkers = 0
+			ri.BytesFailed = 0
+			ri.BytesTransferred = 0
+			time.Sleep(delay + time.Duration(rnd.Float64()*float64(delay)))
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestBatchJobExpireParse(t *testing.T) {
+	expireYaml := `
+expire:
+  apiVersion: v1
+  bucket: mybucket
+  prefix: logs/
+  flags:
+    filter:
+      olderThan: 168h
+      tags:
+        - key: "app"
+          value: "spark*"
+      size:
+        lessThan: 10MiB
+        greaterThan: 1KiB
+`
+	job := BatchJobRequest{}
+	if err := yaml.Unmarshal([]byte(expireYaml), &job); err != nil {
+		t.Fatalf("Unable to parse expire job: %v", err)
+	}
+	if job.Type() != batchJobTypeExpire {
+		t.Fatalf("Expected job type %s, got %s", batchJobTypeExpire, job.Type())
+	}
+	filter := job.Expire.Flags.Filter
+	if filter.OlderThan != 7*24*time.Hour {
+		t.Fatalf("Unexpected olderThan %s", filter.OlderThan)
+	}
+	if filter.Size.LessThan != 10<<20 || filter.Size.GreaterThan != 1<<10 {
+		t.Fatalf("Unexpected size filter %#v", filter.Size)
+	}
+	if err := filter.Validate(); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+}
+
+func TestBatchJobExpireFilterMatches(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-30 * 24 * time.Hour)
+
+	testCases := []struct {
+		filter BatchJobExpireFilter
+		oi     ObjectInfo
+		match  bool
+	}{
+		{
+			filter: BatchJobExpireFilter{OlderThan: 24 * time.Hour},
+			oi:     ObjectInfo{ModTime: old, Size: 1},
+			match:  true,
+		},
+		{
+			filter: BatchJobExpireFilter{OlderThan: 24 * time.Hour},
+			oi:     ObjectInfo{ModTime: now, Size: 1},
+			match:  false,
+		},
+		{
+			filter: BatchJobExpireFilter{CreatedBefore: now},
+			oi:     ObjectInfo{ModTime: old, Size: 1},
+			match:  true,
+		},
+		{
+			filter: BatchJobExpireFilter{CreatedBefore: old},
+			oi:     ObjectInfo{ModTime: now, Size: 1},
+			match:  false,
+		},
+		// delete markers are only selected by deleteMarkersOnly
+		{
+			filter: BatchJobExpireFilter{},
+			oi:     ObjectInfo{ModTime: old, DeleteMarker: true},
+			match:  false,
+		},
+		{
+			filter: BatchJobExpireFilter{DeleteMarkersOnly: true},
+			oi:     ObjectInfo{ModTime: old, DeleteMarker: true},
+			match:  true,
+		},
+		{
+			filter: BatchJobExpireFilter{DeleteMarkersOnly: true},
+			oi:     ObjectInfo{ModTime: old, Size: 1},
+			match:  false,
+		},
+		{
+			filter: BatchJobExpireFilter{Tags: []BatchJobReplicateKV{{Key: "app", Value: "spark*"}}},
+			oi:     ObjectInfo{ModTime: old, UserTags: "app=spark-job&env=dev"},
+			match:  true,
+		},
+		{
+			filter: BatchJobExpireFilter{Tags: []BatchJobReplicateKV{{Key: "app", Value: "spark*"}}},
+			oi:     ObjectInfo{ModTime: old, UserTags: "app=hive"},
+			match:  false,
+		},
+		{
+			filter: BatchJobExpireFilter{Metadata: []BatchJobReplicateKV{{Key: "content-type", Value: "image/*"}}},
+			oi:     ObjectInfo{ModTime: old, UserDefined: map[string]string{"content-type": "image/png"}},
+			match:  true,
+		},
+		{
+			filter: BatchJobExpireFilter{Metadata: []BatchJobReplicateKV{{Key: "content-type", Value: "image/*"}}},
+			oi:     ObjectInfo{ModTime: old, UserDefined: map[string]string{"content-type": "text/plain"}},
+			match:  false,
+		},
+		{
+			filter: BatchJobExpireFilter{Size: BatchJobSizeFilter{LessThan: 100, GreaterThan: 10}},
+			oi:     ObjectInfo{ModTime: old, Size: 50},
+			match:  true,
+		},
+		{
+			filter: BatchJobExpireFilter{Size: BatchJobSizeFilter{LessThan: 100, GreaterThan: 10}},
+			oi:     ObjectInfo{ModTime: old, Size: 100},
+			match:  false,
+		},
+	}
+
+	for i, testCase := range testCases {
+		if match := testCase.filter.Matches(testCase.oi, now); match != testCase.match {
+			t.Errorf("Test %d: expected match %t, got %t", i+1, testCase.match, match)
+		}
+	}
+}
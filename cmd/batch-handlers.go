@@ -37,6 +37,7 @@ import (
 	"github.com/infobsmi/b33s-go/v7/pkg/credentials"
 	"github.com/infobsmi/b33s-go/v7/pkg/tags"
 	"github.com/infobsmi/b33s/internal/auth"
+	"github.com/infobsmi/b33s/internal/hash"
 	xhttp "github.com/infobsmi/b33s/internal/http"
 	"github.com/infobsmi/b33s/internal/logger"
 	"github.com/lithammer/shortuuid/v4"
@@ -48,7 +49,8 @@ import (
 )
 
 // replicate:
-//   # source of the objects to be replicated
+//   # source of the objects to be replicated, the source is
+//   # remote (pull mode) if an endpoint is configured
 //   source:
 //     type: "minio"
 //     bucket: "testbucket"
@@ -72,9 +74,10 @@ import (
 //       endpoint: "https://splunk-hec.dev.com"
 //       token: "Splunk ..." # e.g. "Bearer token"
 //
-//   # target where the objects must be replicated
+//   # target where the objects must be replicated, the target
+//   # is remote (push mode) if an endpoint is configured
 //   target:
-//     type: "minio"
+//     type: "minio" # or "s3" for S3 endpoints without B33S extensions
 //     bucket: "testbucket1"
 //     endpoint: "https://play.min.io"
 //     credentials:
@@ -142,6 +145,49 @@ type BatchReplicateFilter struct {
 	Metadata      []BatchJobReplicateKV `yaml:"metadata,omitempty" json:"metadata"`
 }
 
+// Matches returns true if the object version oi is selected by the
+// filters, all the filters must match.
+func (f BatchReplicateFilter) Matches(oi ObjectInfo, now time.Time) bool {
+	if f.OlderThan > 0 && now.Sub(oi.ModTime) < f.OlderThan {
+		// skip all objects that are newer than specified older duration
+		return false
+	}
+
+	if f.NewerThan > 0 && now.Sub(oi.ModTime) >= f.NewerThan {
+		// skip all objects that are older than specified newer duration
+		return false
+	}
+
+	if !f.CreatedAfter.IsZero() && !oi.ModTime.After(f.CreatedAfter) {
+		// skip all objects that are created before the specified time.
+		return false
+	}
+
+	if !f.CreatedBefore.IsZero() && !oi.ModTime.Before(f.CreatedBefore) {
+		// skip all objects that are created after the specified time.
+		return false
+	}
+
+	if len(f.Tags) > 0 {
+		// Only parse object tags if tags filter is specified.
+		t, err := tags.ParseObjectTags(oi.UserTags)
+		if err != nil {
+			return false
+		}
+		if !batchJobMatchKV(f.Tags, t.ToMap()) {
+			// None of the provided tags filter match skip the object
+			return false
+		}
+	}
+
+	if len(f.Metadata) > 0 && !batchJobMatchMetadata(f.Metadata, oi.UserDefined) {
+		// None of the provided metadata filters match skip the object.
+		return false
+	}
+
+	return true
+}
+
 // BatchReplicateNotification success or failure notification endpoint for each job attempts
 type BatchReplicateNotification struct {
 	Endpoint string `yaml:"endpoint" json:"endpoint"`
@@ -164,7 +210,7 @@ type BatchJobReplicateResourceType string
 // Validate validates if the replicate resource type is recognized and supported
 func (t BatchJobReplicateResourceType) Validate() error {
 	switch t {
-	case BatchJobReplicateResourceB33S:
+	case BatchJobReplicateResourceB33S, BatchJobReplicateResourceS3:
 	default:
 		return errInvalidArgument
 	}
@@ -174,6 +220,9 @@ func (t BatchJobReplicateResourceType) Validate() error {
 // Different types of batch jobs..
 const (
 	BatchJobReplicateResourceB33S BatchJobReplicateResourceType = "minio"
+	// BatchJobReplicateResourceS3 - any S3 compatible endpoint, B33S
+	// specific extensions of the S3 API are not used.
+	BatchJobReplicateResourceS3 BatchJobReplicateResourceType = "s3"
 	// add future targets
 )
 
@@ -230,24 +279,31 @@ type BatchJobRequest struct {
 	Started   time.Time            `yaml:"-" json:"started"`
 	Location  string               `yaml:"-" json:"location"`
 	Replicate *BatchJobReplicateV1 `yaml:"replicate" json:"replicate"`
+	Expire    *BatchJobExpire      `yaml:"expire" json:"expire"`
+	KeyRotate *BatchJobKeyRotateV1 `yaml:"keyrotate" json:"keyrotate"`
 }
 
 // Notify notifies notification endpoint if configured regarding job failure or success.
 func (r BatchJobReplicateV1) Notify(ctx context.Context, body io.Reader) error {
-	if r.Flags.Notify.Endpoint == "" {
+	return r.Flags.Notify.notify(ctx, body)
+}
+
+// notify posts body to the notification endpoint, if configured.
+func (n BatchReplicateNotification) notify(ctx context.Context, body io.Reader) error {
+	if n.Endpoint == "" {
 		return nil
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.Flags.Notify.Endpoint, body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.Endpoint, body)
 	if err != nil {
 		return err
 	}
 
-	if r.Flags.Notify.Token != "" {
-		req.Header.Set("Authorization", r.Flags.Notify.Token)
+	if n.Token != "" {
+		req.Header.Set("Authorization", n.Token)
 	}
 
 	clnt := http.Client{Transport: getRemoteInstanceTransport}
@@ -264,9 +320,136 @@ func (r BatchJobReplicateV1) Notify(ctx context.Context, body io.Reader) error {
 	return nil
 }
 
-// ReplicateFromSource - this is not implemented yet where source is 'remote' and target is local.
-func (r *BatchJobReplicateV1) ReplicateFromSource(ctx context.Context, api ObjectLayer, c *miniogo.Core, srcObject string) error {
-	return nil
+// RemoteToLocal returns true if the source is remote and the target
+// is local (pull mode).
+func (r BatchJobReplicateV1) RemoteToLocal() bool {
+	return r.Source.Endpoint != ""
+}
+
+// newBatchReplicateClient returns a client for the remote endpoint of
+// the job.
+func newBatchReplicateClient(endpoint string, cred BatchJobReplicateCredentials) (*miniogo.Core, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return miniogo.NewCore(u.Host, &miniogo.Options{
+		Creds:     credentials.NewStaticV4(cred.AccessKey, cred.SecretKey, cred.SessionToken),
+		Secure:    u.Scheme == "https",
+		Transport: getRemoteInstanceTransport,
+	})
+}
+
+// batchRemoteObjectInfo converts an object listed or fetched from the
+// remote source into an ObjectInfo, such that the filters can be
+// applied on it.
+func batchRemoteObjectInfo(bucket string, obj miniogo.ObjectInfo) ObjectInfo {
+	oi := ObjectInfo{
+		Bucket:       bucket,
+		Name:         obj.Key,
+		ModTime:      obj.LastModified,
+		Size:         obj.Size,
+		ETag:         obj.ETag,
+		ContentType:  obj.ContentType,
+		StorageClass: obj.StorageClass,
+		UserDefined:  make(map[string]string, len(obj.Metadata)+len(obj.UserMetadata)),
+	}
+	for k, v := range obj.Metadata {
+		if len(v) > 0 {
+			oi.UserDefined[k] = v[0]
+		}
+	}
+	for k, v := range obj.UserMetadata {
+		oi.UserDefined[k] = v
+	}
+	if len(obj.UserTags) > 0 {
+		if t, err := tags.MapToObjectTags(obj.UserTags); err == nil {
+			oi.UserTags = t.String()
+		}
+	}
+	return oi
+}
+
+// remoteObjectInfo returns the remote source object obj as ObjectInfo.
+// Generic S3 sources do not list metadata and tags, they are fetched
+// only if the filters need them.
+func (r *BatchJobReplicateV1) remoteObjectInfo(ctx context.Context, c *miniogo.Core, obj miniogo.ObjectInfo) (ObjectInfo, error) {
+	if r.Source.Type == BatchJobReplicateResourceB33S {
+		return batchRemoteObjectInfo(r.Source.Bucket, obj), nil
+	}
+	if len(r.Flags.Filter.Metadata) > 0 {
+		stat, err := c.StatObject(ctx, r.Source.Bucket, obj.Key, miniogo.StatObjectOptions{})
+		if err != nil {
+			return ObjectInfo{}, err
+		}
+		obj.Metadata = stat.Metadata
+		obj.ContentType = stat.ContentType
+	}
+	if len(r.Flags.Filter.Tags) > 0 {
+		t, err := c.GetObjectTagging(ctx, r.Source.Bucket, obj.Key, miniogo.GetObjectTaggingOptions{})
+		if err != nil {
+			return ObjectInfo{}, err
+		}
+		obj.UserTags = t.ToMap()
+	}
+	return batchRemoteObjectInfo(r.Source.Bucket, obj), nil
+}
+
+// ReplicateFromSource reads from the remote source and replicates into the local target.
+func (r *BatchJobReplicateV1) ReplicateFromSource(ctx context.Context, api ObjectLayer, c *miniogo.Core, srcObjInfo ObjectInfo, retry bool) error {
+	srcBucket := r.Source.Bucket
+	tgtBucket := r.Target.Bucket
+	tgtObject := pathJoin(r.Target.Prefix, srcObjInfo.Name)
+
+	versioned := globalBucketVersioningSys.PrefixEnabled(tgtBucket, tgtObject)
+	versionSuspended := globalBucketVersioningSys.PrefixSuspended(tgtBucket, tgtObject)
+
+	if retry { // when we are retrying avoid copying if necessary.
+		oi, err := api.GetObjectInfo(ctx, tgtBucket, tgtObject, ObjectOptions{
+			Versioned:        versioned,
+			VersionSuspended: versionSuspended,
+		})
+		if err == nil && oi.ETag == srcObjInfo.ETag {
+			return nil
+		}
+	}
+
+	rd, objInfo, _, err := c.GetObject(ctx, srcBucket, srcObjInfo.Name, miniogo.GetObjectOptions{})
+	if err != nil {
+		return ErrorRespToObjectError(err, srcBucket, srcObjInfo.Name)
+	}
+	defer rd.Close()
+
+	meta := make(map[string]string)
+	for k, v := range objInfo.Metadata {
+		if len(v) == 0 {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(k), "x-amz-meta-") || equals(k, xhttp.ContentType, xhttp.CacheControl,
+			xhttp.ContentEncoding, xhttp.ContentLanguage, xhttp.ContentDisposition, xhttp.Expires) {
+			meta[k] = v[0]
+		}
+	}
+	if objInfo.UserTagCount > 0 {
+		t, err := c.GetObjectTagging(ctx, srcBucket, srcObjInfo.Name, miniogo.GetObjectTaggingOptions{})
+		if err != nil {
+			return err
+		}
+		meta[xhttp.AmzObjectTagging] = t.String()
+	}
+
+	hr, err := hash.NewReader(rd, objInfo.Size, "", "", objInfo.Size)
+	if err != nil {
+		return err
+	}
+	_, err = api.PutObject(ctx, tgtBucket, tgtObject, NewPutObjReader(hr), ObjectOptions{
+		UserDefined:      meta,
+		MTime:            objInfo.LastModified,
+		Versioned:        versioned,
+		VersionSuspended: versionSuspended,
+	})
+	return err
 }
 
 // ReplicateToTarget read from source and replicate to configured target
@@ -275,6 +458,13 @@ func (r *BatchJobReplicateV1) ReplicateToTarget(ctx context.Context, api ObjectL
 	tgtBucket := r.Target.Bucket
 	tgtPrefix := r.Target.Prefix
 	srcObject := srcObjInfo.Name
+	genericS3 := r.Target.Type == BatchJobReplicateResourceS3
+
+	if genericS3 && srcObjInfo.DeleteMarker {
+		// A latest delete marker deletes the object on the target,
+		// older versions are not listed, see skipVersion().
+		return c.RemoveObject(ctx, tgtBucket, pathJoin(tgtPrefix, srcObject), miniogo.RemoveObjectOptions{})
+	}
 
 	if srcObjInfo.DeleteMarker || !srcObjInfo.VersionPurgeStatus.Empty() {
 		if retry {
@@ -340,6 +530,17 @@ func (r *BatchJobReplicateV1) ReplicateToTarget(ctx context.Context, api ObjectL
 		return err
 	}
 
+	if genericS3 {
+		// B33S extensions are not understood by generic S3 endpoints.
+		putOpts.Internal = miniogo.AdvancedPutOptions{}
+		if !putOpts.RetainUntilDate.IsZero() || putOpts.LegalHold != "" {
+			// Object lock requires the Content-MD5 or a checksum.
+			putOpts.SendContentMd5 = true
+		}
+		_, err = c.Client.PutObject(ctx, tgtBucket, pathJoin(tgtPrefix, objInfo.Name), rd, size, putOpts)
+		return err
+	}
+
 	if objInfo.isMultipart() {
 		if err := replicateObjectWithMultipart(ctx, c, tgtBucket, pathJoin(tgtPrefix, objInfo.Name), rd, objInfo, putOpts); err != nil {
 			return err
@@ -367,8 +568,9 @@ type batchJobInfo struct {
 	Failed   bool `json:"failed" msg:"fld"`
 
 	// Last bucket/object batch replicated
-	Bucket string `json:"-" msg:"lbkt"`
-	Object string `json:"-" msg:"lobj"`
+	Bucket    string `json:"-" msg:"lbkt"`
+	Object    string `json:"-" msg:"lobj"`
+	VersionID string `json:"-" msg:"lvid"`
 
 	// Verbose information
 	Objects             int64 `json:"objects" msg:"ob"`
@@ -392,13 +594,34 @@ const (
 	batchReplJobDefaultRetryDelay = 250 * time.Millisecond
 )
 
+// getJobReportName returns the name of the object persisting the
+// progress of a job, next to the job definition.
+func getJobReportName(jobType madmin.BatchJobType) string {
+	switch jobType {
+	case batchJobTypeExpire:
+		return batchExpireName
+	case batchJobTypeKeyRotate:
+		return batchKeyRotateName
+	}
+	return batchReplName
+}
+
 func (ri *batchJobInfo) load(ctx context.Context, api ObjectLayer, job BatchJobRequest) error {
-	data, err := readConfig(ctx, api, pathJoin(job.Location, batchReplName))
+	data, err := readConfig(ctx, api, pathJoin(job.Location, getJobReportName(job.Type())))
 	if err != nil {
 		if errors.Is(err, errConfigNotFound) || isErrObjectNotFound(err) {
 			ri.Version = batchReplVersionV1
-			if job.Replicate.Flags.Retry.Attempts > 0 {
-				ri.RetryAttempts = job.Replicate.Flags.Retry.Attempts
+			var attempts int
+			switch {
+			case job.Replicate != nil:
+				attempts = job.Replicate.Flags.Retry.Attempts
+			case job.Expire != nil:
+				attempts = job.Expire.Flags.Retry.Attempts
+			case job.KeyRotate != nil:
+				attempts = job.KeyRotate.Flags.Retry.Attempts
+			}
+			if attempts > 0 {
+				ri.RetryAttempts = attempts
 			} else {
 				ri.RetryAttempts = batchReplJobDefaultRetries
 			}
@@ -441,20 +664,23 @@ func (ri *batchJobInfo) load(ctx context.Context, api ObjectLayer, job BatchJobR
 
 func (ri batchJobInfo) clone() batchJobInfo {
 	return batchJobInfo{
-		Version:          ri.Version,
-		JobID:            ri.JobID,
-		JobType:          ri.JobType,
-		RetryAttempts:    ri.RetryAttempts,
-		Complete:         ri.Complete,
-		Failed:           ri.Failed,
-		StartTime:        ri.StartTime,
-		LastUpdate:       ri.LastUpdate,
-		Bucket:           ri.Bucket,
-		Object:           ri.Object,
-		Objects:          ri.Objects,
-		ObjectsFailed:    ri.ObjectsFailed,
-		BytesTransferred: ri.BytesTransferred,
-		BytesFailed:      ri.BytesFailed,
+		Version:             ri.Version,
+		JobID:               ri.JobID,
+		JobType:             ri.JobType,
+		RetryAttempts:       ri.RetryAttempts,
+		Complete:            ri.Complete,
+		Failed:              ri.Failed,
+		StartTime:           ri.StartTime,
+		LastUpdate:          ri.LastUpdate,
+		Bucket:              ri.Bucket,
+		Object:              ri.Object,
+		VersionID:           ri.VersionID,
+		Objects:             ri.Objects,
+		DeleteMarkers:       ri.DeleteMarkers,
+		ObjectsFailed:       ri.ObjectsFailed,
+		DeleteMarkersFailed: ri.DeleteMarkersFailed,
+		BytesTransferred:    ri.BytesTransferred,
+		BytesFailed:         ri.BytesFailed,
 	}
 }
 
@@ -470,7 +696,7 @@ func (ri batchJobInfo) save(ctx context.Context, api ObjectLayer, jobLocation st
 		return err
 	}
 
-	return saveConfig(ctx, api, pathJoin(jobLocation, batchReplName), buf)
+	return saveConfig(ctx, api, pathJoin(jobLocation, getJobReportName(madmin.BatchJobType(ri.JobType))), buf)
 }
 
 func (ri *batchJobInfo) countItem(size int64, dmarker, success bool) {
@@ -516,9 +742,97 @@ func (ri *batchJobInfo) trackCurrentBucketObject(bucket string, info ObjectInfo,
 	}
 	ri.Bucket = bucket
 	ri.Object = info.Name
+	ri.VersionID = info.VersionID
+	if ri.VersionID == "" {
+		// resume after the null version, not after the whole object.
+		ri.VersionID = nullVersionID
+	}
 	ri.countItem(info.Size, info.DeleteMarker, failed)
 }
 
+// batchJobListVersions lists all the object versions under prefix in
+// lexical order, resuming after marker and versionMarker, and calls fn
+// for each of them. Unlike Walk the listing order is stable, which allows
+// resuming a job from the last object version it has processed.
+func batchJobListVersions(ctx context.Context, api ObjectLayer, bucket, prefix, marker, versionMarker string, fn func(ObjectInfo) error) error {
+	if marker == "" {
+		versionMarker = ""
+	}
+	for {
+		loi, err := api.ListObjectVersions(ctx, bucket, prefix, marker, versionMarker, "", maxObjectList)
+		if err != nil {
+			return err
+		}
+		for _, oi := range loi.Objects {
+			if err := fn(oi); err != nil {
+				return err
+			}
+		}
+		if !loi.IsTruncated {
+			return nil
+		}
+		marker, versionMarker = loi.NextMarker, loi.NextVersionIDMarker
+		if versionMarker == "" {
+			versionMarker = nullVersionID
+		}
+	}
+}
+
+// skipVersion returns true for the local object versions which are not
+// replicated to the target. Generic S3 endpoints can not preserve version
+// IDs and modification times, only the latest version is replicated.
+func (r *BatchJobReplicateV1) skipVersion(oi ObjectInfo) bool {
+	if r.Target.Type != BatchJobReplicateResourceS3 {
+		return false
+	}
+	return !oi.IsLatest || !oi.VersionPurgeStatus.Empty()
+}
+
+// listLocal lists the local object versions in lexical order starting
+// after the version versionMarker of marker, and calls fn for each
+// version selected by the filters and replicated to the target.
+func (r *BatchJobReplicateV1) listLocal(ctx context.Context, api ObjectLayer, marker, versionMarker string, fn func(ObjectInfo) error) error {
+	now := time.Now()
+	return batchJobListVersions(ctx, api, r.Source.Bucket, r.Source.Prefix, marker, versionMarker, func(oi ObjectInfo) error {
+		if !r.Flags.Filter.Matches(oi, now) || r.skipVersion(oi) {
+			return nil
+		}
+		return fn(oi)
+	})
+}
+
+// listSource lists the latest versions of the objects on the remote
+// source in lexical order starting after startAfter, and calls fn for
+// each object selected by the filters.
+func (r *BatchJobReplicateV1) listSource(ctx context.Context, c *miniogo.Core, startAfter string, fn func(ObjectInfo) error) error {
+	now := time.Now()
+	for obj := range c.Client.ListObjects(ctx, r.Source.Bucket, miniogo.ListObjectsOptions{
+		Prefix:       r.Source.Prefix,
+		Recursive:    true,
+		StartAfter:   startAfter,
+		WithMetadata: r.Source.Type == BatchJobReplicateResourceB33S,
+	}) {
+		if obj.Err != nil {
+			return ErrorRespToObjectError(obj.Err, r.Source.Bucket, r.Source.Prefix)
+		}
+		oi, err := r.remoteObjectInfo(ctx, c, obj)
+		if err != nil {
+			if isErrObjectNotFound(ErrorRespToObjectError(err, r.Source.Bucket, obj.Key)) {
+				// object must be deleted concurrently.
+				continue
+			}
+			return err
+		}
+		if !r.Flags.Filter.Matches(oi, now) {
+			continue
+		}
+		if err := fn(oi); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Start start the batch replication job, resumes if there was a pending job via "job.ID"
 func (r *BatchJobReplicateV1) Start(ctx context.Context, api ObjectLayer, job BatchJobRequest) error {
 	ri := &batchJobInfo{
@@ -530,7 +844,6 @@ func (r *BatchJobReplicateV1) Start(ctx context.Context, api ObjectLayer, job Ba
 		return err
 	}
 	globalBatchJobsMetrics.save(job.ID, ri.clone())
-	lastObject := ri.Object
 
 	delay := job.Replicate.Flags.Retry.Delay
 	if delay == 0 {
@@ -538,83 +851,11 @@ func (r *BatchJobReplicateV1) Start(ctx context.Context, api ObjectLayer, job Ba
 	}
 	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
 
-	skip := func(info FileInfo) (ok bool) {
-		if r.Flags.Filter.OlderThan > 0 && time.Since(info.ModTime) < r.Flags.Filter.OlderThan {
-			// skip all objects that are newer than specified older duration
-			return false
-		}
-
-		if r.Flags.Filter.NewerThan > 0 && time.Since(info.ModTime) >= r.Flags.Filter.NewerThan {
-			// skip all objects that are older than specified newer duration
-			return false
-		}
-
-		if !r.Flags.Filter.CreatedAfter.IsZero() && r.Flags.Filter.CreatedAfter.Before(info.ModTime) {
-			// skip all objects that are created before the specified time.
-			return false
-		}
-
-		if !r.Flags.Filter.CreatedBefore.IsZero() && r.Flags.Filter.CreatedBefore.After(info.ModTime) {
-			// skip all objects that are created after the specified time.
-			return false
-		}
-
-		if len(r.Flags.Filter.Tags) > 0 {
-			// Only parse object tags if tags filter is specified.
-			tagMap := map[string]string{}
-			tagStr := info.Metadata[xhttp.AmzObjectTagging]
-			if len(tagStr) != 0 {
-				t, err := tags.ParseObjectTags(tagStr)
-				if err != nil {
-					return false
-				}
-				tagMap = t.ToMap()
-			}
-
-			for _, kv := range r.Flags.Filter.Tags {
-				for t, v := range tagMap {
-					if kv.Match(BatchJobReplicateKV{Key: t, Value: v}) {
-						return true
-					}
-				}
-			}
-
-			// None of the provided tags filter match skip the object
-			return false
-		}
-
-		if len(r.Flags.Filter.Metadata) > 0 {
-			for _, kv := range r.Flags.Filter.Metadata {
-				for k, v := range info.Metadata {
-					if !strings.HasPrefix(strings.ToLower(k), "x-amz-meta-") && !isStandardHeader(k) {
-						continue
-					}
-					// We only need to match x-amz-meta or standardHeaders
-					if kv.Match(BatchJobReplicateKV{Key: k, Value: v}) {
-						return true
-					}
-				}
-			}
-
-			// None of the provided metadata filters match skip the object.
-			return false
-		}
-
-		return true
+	endpoint, cred := r.Target.Endpoint, r.Target.Creds
+	if r.RemoteToLocal() {
+		endpoint, cred = r.Source.Endpoint, r.Source.Creds
 	}
-
-	u, err := url.Parse(r.Target.Endpoint)
-	if err != nil {
-		return err
-	}
-
-	cred := r.Target.Creds
-
-	c, err := miniogo.NewCore(u.Host, &miniogo.Options{
-		Creds:     credentials.NewStaticV4(cred.AccessKey, cred.SecretKey, cred.SessionToken),
-		Secure:    u.Scheme == "https",
-		Transport: getRemoteInstanceTransport,
-	})
+	c, err := newBatchReplicateClient(endpoint, cred)
 	if err != nil {
 		return err
 	}
@@ -624,24 +865,20 @@ func (r *BatchJobReplicateV1) Start(ctx context.Context, api ObjectLayer, job Ba
 	for attempts := 1; attempts <= retryAttempts; attempts++ {
 		ctx, cancel := context.WithCancel(ctx)
 
-		results := make(chan ObjectInfo, 100)
-		if err := api.Walk(ctx, r.Source.Bucket, r.Source.Prefix, results, ObjectOptions{
-			WalkMarker: lastObject,
-			WalkFilter: skip,
-		}); err != nil {
-			cancel()
-			// Do not need to retry if we can't list objects on source.
-			return err
-		}
-
-		for result := range results {
-			stopFn := globalBatchJobsMetrics.trace(batchReplicationMetricObject, job.ID, attempts, result)
+		replicate := func(result ObjectInfo) error {
+			stopFn := globalBatchJobsMetrics.trace(batchJobMetricReplication, job.ID, attempts, result)
 			success := true
-			if err := r.ReplicateToTarget(ctx, api, c, result, retry); err != nil {
+			var err error
+			if r.RemoteToLocal() {
+				err = r.ReplicateFromSource(ctx, api, c, result, retry)
+			} else {
+				err = r.ReplicateToTarget(ctx, api, c, result, retry)
+			}
+			if err != nil {
 				if isErrVersionNotFound(err) || isErrObjectNotFound(err) {
 					// object must be deleted concurrently, allow
 					// these failures but do not count them
-					continue
+					return nil
 				}
 				stopFn(err)
 				logger.LogIf(ctx, err)
@@ -653,6 +890,20 @@ func (r *BatchJobReplicateV1) Start(ctx context.Context, api ObjectLayer, job Ba
 			globalBatchJobsMetrics.save(job.ID, ri.clone())
 			// persist in-memory state to disk after every 10secs.
 			logger.LogIf(ctx, ri.updateAfter(ctx, api, 10*time.Second, job.Location))
+			return nil
+		}
+
+		// Resume after the last object version processed by a
+		// previous run of this job.
+		if r.RemoteToLocal() {
+			err = r.listSource(ctx, c, ri.Object, replicate)
+		} else {
+			err = r.listLocal(ctx, api, ri.Object, ri.VersionID, replicate)
+		}
+		if err != nil {
+			cancel()
+			// Do not need to retry if we can't list objects on source.
+			return err
 		}
 
 		ri.RetryAttempts = attempts
@@ -671,6 +922,7 @@ func (r *BatchJobReplicateV1) Start(ctx context.Context, api ObjectLayer, job Ba
 			ri.ObjectsFailed = 0
 			ri.Bucket = ""
 			ri.Object = ""
+			ri.VersionID = ""
 			ri.Objects = 0
 			ri.BytesFailed = 0
 			ri.BytesTransferred = 0
@@ -706,39 +958,31 @@ func (r *BatchJobReplicateV1) Validate(ctx context.Context, o ObjectLayer) error
 		return errInvalidArgument
 	}
 
-	if r.Source.Bucket == "" {
+	if r.Source.Bucket == "" || r.Target.Bucket == "" {
 		return errInvalidArgument
 	}
 
-	info, err := o.GetBucketInfo(ctx, r.Source.Bucket, BucketOptions{})
-	if err != nil {
-		if isErrBucketNotFound(err) {
-			return batchReplicationJobError{
-				Code:           "NoSuchSourceBucket",
-				Description:    "The specified source bucket does not exist",
-				HTTPStatusCode: http.StatusNotFound,
-			}
-		}
-		return err
-	}
-
 	if err := r.Source.Type.Validate(); err != nil {
 		return err
 	}
 
-	if r.Target.Endpoint == "" {
-		return errInvalidArgument
+	if err := r.Target.Type.Validate(); err != nil {
+		return err
 	}
 
-	if r.Target.Bucket == "" {
+	// Exactly one of source and target must be remote.
+	if (r.Source.Endpoint == "") == (r.Target.Endpoint == "") {
 		return errInvalidArgument
 	}
 
-	if err := r.Target.Creds.Validate(); err != nil {
-		return err
+	localBucket, remoteBucket := r.Source.Bucket, r.Target.Bucket
+	endpoint, cred := r.Target.Endpoint, r.Target.Creds
+	if r.RemoteToLocal() {
+		localBucket, remoteBucket = r.Target.Bucket, r.Source.Bucket
+		endpoint, cred = r.Source.Endpoint, r.Source.Creds
 	}
 
-	if err := r.Target.Type.Validate(); err != nil {
+	if err := cred.Validate(); err != nil {
 		return err
 	}
 
@@ -758,25 +1002,40 @@ func (r *BatchJobReplicateV1) Validate(ctx context.Context, o ObjectLayer) error
 		return err
 	}
 
-	u, err := url.Parse(r.Target.Endpoint)
+	info, err := o.GetBucketInfo(ctx, localBucket, BucketOptions{})
 	if err != nil {
+		if isErrBucketNotFound(err) {
+			if r.RemoteToLocal() {
+				return batchReplicationJobError{
+					Code:           "NoSuchTargetBucket",
+					Description:    "The specified target bucket does not exist",
+					HTTPStatusCode: http.StatusNotFound,
+				}
+			}
+			return batchReplicationJobError{
+				Code:           "NoSuchSourceBucket",
+				Description:    "The specified source bucket does not exist",
+				HTTPStatusCode: http.StatusNotFound,
+			}
+		}
 		return err
 	}
 
-	cred := r.Target.Creds
-
-	c, err := miniogo.NewCore(u.Host, &miniogo.Options{
-		Creds:     credentials.NewStaticV4(cred.AccessKey, cred.SecretKey, cred.SessionToken),
-		Secure:    u.Scheme == "https",
-		Transport: getRemoteInstanceTransport,
-	})
+	c, err := newBatchReplicateClient(endpoint, cred)
 	if err != nil {
 		return err
 	}
 
-	vcfg, err := c.GetBucketVersioning(ctx, r.Target.Bucket)
+	vcfg, err := c.GetBucketVersioning(ctx, remoteBucket)
 	if err != nil {
 		if miniogo.ToErrorResponse(err).Code == "NoSuchBucket" {
+			if r.RemoteToLocal() {
+				return batchReplicationJobError{
+					Code:           "NoSuchSourceBucket",
+					Description:    "The specified source bucket does not exist",
+					HTTPStatusCode: http.StatusNotFound,
+				}
+			}
 			return batchReplicationJobError{
 				Code:           "NoSuchTargetBucket",
 				Description:    "The specified target bucket does not exist",
@@ -786,7 +1045,8 @@ func (r *BatchJobReplicateV1) Validate(ctx context.Context, o ObjectLayer) error
 		return err
 	}
 
-	if info.Versioning && !vcfg.Enabled() {
+	// Versions are only preserved when replicating to B33S targets.
+	if !r.RemoteToLocal() && r.Target.Type == BatchJobReplicateResourceB33S && info.Versioning && !vcfg.Enabled() {
 		return batchReplicationJobError{
 			Code: "InvalidBucketState",
 			Description: fmt.Sprintf("The source '%s' has versioning enabled, target '%s' must have versioning enabled",
@@ -799,33 +1059,58 @@ func (r *BatchJobReplicateV1) Validate(ctx context.Context, o ObjectLayer) error
 	return nil
 }
 
-// Type returns type of batch job, currently only supports 'replicate'
+// Type returns type of batch job, currently supports 'replicate', 'expire'
+// and 'keyrotate'
 func (j BatchJobRequest) Type() madmin.BatchJobType {
-	if j.Replicate != nil {
+	switch {
+	case j.Replicate != nil:
 		return madmin.BatchJobReplicate
+	case j.Expire != nil:
+		return batchJobTypeExpire
+	case j.KeyRotate != nil:
+		return batchJobTypeKeyRotate
 	}
 	return madmin.BatchJobType("unknown")
 }
 
+// jobs returns the number of job definitions in the request.
+func (j BatchJobRequest) jobs() (n int) {
+	for _, defined := range []bool{j.Replicate != nil, j.Expire != nil, j.KeyRotate != nil} {
+		if defined {
+			n++
+		}
+	}
+	return n
+}
+
 // Validate validates the current job, used by 'save()' before
 // persisting the job request
 func (j BatchJobRequest) Validate(ctx context.Context, o ObjectLayer) error {
-	if j.Replicate != nil {
+	if j.jobs() != 1 {
+		// exactly one job type per request
+		return errInvalidArgument
+	}
+	switch {
+	case j.Replicate != nil:
 		return j.Replicate.Validate(ctx, o)
+	case j.Expire != nil:
+		return j.Expire.Validate(ctx, o)
+	case j.KeyRotate != nil:
+		return j.KeyRotate.Validate(ctx, o)
 	}
 	return errInvalidArgument
 }
 
 func (j BatchJobRequest) delete(ctx context.Context, api ObjectLayer) {
-	if j.Replicate != nil {
-		deleteConfig(ctx, api, pathJoin(j.Location, batchReplName))
+	if j.jobs() > 0 {
+		deleteConfig(ctx, api, pathJoin(j.Location, getJobReportName(j.Type())))
 	}
 	globalBatchJobsMetrics.delete(j.ID)
 	deleteConfig(ctx, api, j.Location)
 }
 
 func (j *BatchJobRequest) save(ctx context.Context, api ObjectLayer) error {
-	if j.Replicate == nil {
+	if j.jobs() == 0 {
 		return errInvalidArgument
 	}
 
@@ -1083,14 +1368,21 @@ func (j *BatchJobPool) AddWorker() {
 			if !ok {
 				return
 			}
-			if job.Replicate != nil {
-				if err := job.Replicate.Start(j.ctx, j.objLayer, *job); err != nil {
-					if !isErrBucketNotFound(err) {
-						logger.LogIf(j.ctx, err)
-						continue
-					}
-					// Bucket not found proceed to delete such a job.
+			var err error
+			switch {
+			case job.Replicate != nil:
+				err = job.Replicate.Start(j.ctx, j.objLayer, *job)
+			case job.Expire != nil:
+				err = job.Expire.Start(j.ctx, j.objLayer, *job)
+			case job.KeyRotate != nil:
+				err = job.KeyRotate.Start(j.ctx, j.objLayer, *job)
+			}
+			if err != nil {
+				if !isErrBucketNotFound(err) {
+					logger.LogIf(j.ctx, err)
+					continue
 				}
+				// Bucket not found proceed to delete such a job.
 			}
 			job.delete(j.ctx, j.objLayer)
 		case <-j.workerKillCh:
@@ -1145,22 +1437,34 @@ var globalBatchJobsMetrics = batchJobMetrics{
 	metrics: make(map[string]batchJobInfo),
 }
 
-//msgp:ignore batchReplicationMetric
-//go:generate stringer -type=batchReplicationMetric -trimprefix=batchReplicationMetric $GOFILE
-type batchReplicationMetric uint8
+//msgp:ignore batchJobMetric
+//go:generate stringer -type=batchJobMetric -trimprefix=batchJobMetric $GOFILE
+type batchJobMetric uint8
 
 const (
-	batchReplicationMetricObject batchReplicationMetric = iota
+	batchJobMetricReplication batchJobMetric = iota
+	batchJobMetricExpire
+	batchJobMetricKeyRotation
 )
 
-func batchReplicationTrace(d batchReplicationMetric, job string, startTime time.Time, duration time.Duration, info ObjectInfo, attempts int, err error) madmin.TraceInfo {
+// traceName returns the function name batch job traces are published
+// with, replication keeps the name it was traced with before other job
+// types existed so that existing trace filters keep working.
+func (d batchJobMetric) traceName() string {
+	if d == batchJobMetricReplication {
+		return "batchReplication.Object"
+	}
+	return "batchJob." + d.String()
+}
+
+func batchJobTrace(d batchJobMetric, job string, startTime time.Time, duration time.Duration, info ObjectInfo, attempts int, err error) madmin.TraceInfo {
 	var errStr string
 	if err != nil {
 		errStr = err.Error()
 	}
-	funcName := fmt.Sprintf("batchReplication.%s (job-name=%s)", d.String(), job)
+	funcName := fmt.Sprintf("%s (job-name=%s)", d.traceName(), job)
 	if attempts > 0 {
-		funcName = fmt.Sprintf("batchReplication.%s (job-name=%s,attempts=%s)", d.String(), job, humanize.Ordinal(attempts))
+		funcName = fmt.Sprintf("%s (job-name=%s,attempts=%s)", d.traceName(), job, humanize.Ordinal(attempts))
 	}
 	return madmin.TraceInfo{
 		TraceType: madmin.TraceBatchReplication,
@@ -1179,6 +1483,20 @@ func (m *batchJobMetrics) report(jobID string) (metrics *madmin.BatchJobMetrics)
 	defer m.RUnlock()
 	for id, job := range m.metrics {
 		match := jobID != "" && id == jobID
+		info := &madmin.ReplicateInfo{
+			Bucket:           job.Bucket,
+			Object:           job.Object,
+			Objects:          job.Objects,
+			ObjectsFailed:    job.ObjectsFailed,
+			BytesTransferred: job.BytesTransferred,
+			BytesFailed:      job.BytesFailed,
+		}
+		if madmin.BatchJobType(job.JobType) == batchJobTypeExpire {
+			// There are no expire specific metrics, deleted delete
+			// markers are reported as objects.
+			info.Objects += job.DeleteMarkers
+			info.ObjectsFailed += job.DeleteMarkersFailed
+		}
 		metrics.Jobs[id] = madmin.JobMetric{
 			JobID:         job.JobID,
 			JobType:       job.JobType,
@@ -1187,14 +1505,7 @@ func (m *batchJobMetrics) report(jobID string) (metrics *madmin.BatchJobMetrics)
 			RetryAttempts: job.RetryAttempts,
 			Complete:      job.Complete,
 			Failed:        job.Failed,
-			Replicate: &madmin.ReplicateInfo{
-				Bucket:           job.Bucket,
-				Object:           job.Object,
-				Objects:          job.Objects,
-				ObjectsFailed:    job.ObjectsFailed,
-				BytesTransferred: job.BytesTransferred,
-				BytesFailed:      job.BytesFailed,
-			},
+			Replicate:     info,
 		}
 		if match {
 			break
@@ -1217,12 +1528,12 @@ func (m *batchJobMetrics) save(jobID string, ri batchJobInfo) {
 	m.metrics[jobID] = ri
 }
 
-func (m *batchJobMetrics) trace(d batchReplicationMetric, job string, attempts int, info ObjectInfo) func(err error) {
+func (m *batchJobMetrics) trace(d batchJobMetric, job string, attempts int, info ObjectInfo) func(err error) {
 	startTime := time.Now()
 	return func(err error) {
 		duration := time.Since(startTime)
 		if globalTrace.NumSubscribers(madmin.TraceBatchReplication) > 0 {
-			globalTrace.Publish(batchReplicationTrace(d, job, startTime, duration, info, attempts, err))
+			globalTrace.Publish(batchJobTrace(d, job, startTime, duration, info, attempts, err))
 		}
 	}
 }
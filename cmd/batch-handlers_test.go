@@ -0,0 +1,293 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBatchReplicateFilterMatches(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-30 * 24 * time.Hour)
+
+	testCases := []struct {
+		filter BatchReplicateFilter
+		oi     ObjectInfo
+		match  bool
+	}{
+		{
+			filter: BatchReplicateFilter{},
+			oi:     ObjectInfo{ModTime: old},
+			match:  true,
+		},
+		{
+			filter: BatchReplicateFilter{OlderThan: 24 * time.Hour},
+			oi:     ObjectInfo{ModTime: now},
+			match:  false,
+		},
+		{
+			filter: BatchReplicateFilter{NewerThan: 24 * time.Hour},
+			oi:     ObjectInfo{ModTime: old},
+			match:  false,
+		},
+		{
+			filter: BatchReplicateFilter{CreatedAfter: old},
+			oi:     ObjectInfo{ModTime: now},
+			match:  true,
+		},
+		{
+			filter: BatchReplicateFilter{CreatedAfter: now},
+			oi:     ObjectInfo{ModTime: old},
+			match:  false,
+		},
+		{
+			filter: BatchReplicateFilter{CreatedBefore: now},
+			oi:     ObjectInfo{ModTime: old},
+			match:  true,
+		},
+		// all filters must match
+		{
+			filter: BatchReplicateFilter{
+				Tags:     []BatchJobReplicateKV{{Key: "app", Value: "spark*"}},
+				Metadata: []BatchJobReplicateKV{{Key: "content-type", Value: "image/*"}},
+			},
+			oi:    ObjectInfo{ModTime: old, UserTags: "app=spark-job", UserDefined: map[string]string{"content-type": "text/plain"}},
+			match: false,
+		},
+		{
+			filter: BatchReplicateFilter{
+				Tags:     []BatchJobReplicateKV{{Key: "app", Value: "spark*"}},
+				Metadata: []BatchJobReplicateKV{{Key: "content-type", Value: "image/*"}},
+			},
+			oi:    ObjectInfo{ModTime: old, UserTags: "app=spark-job", UserDefined: map[string]string{"content-type": "image/png"}},
+			match: true,
+		},
+	}
+
+	for i, testCase := range testCases {
+		if match := testCase.filter.Matches(testCase.oi, now); match != testCase.match {
+			t.Errorf("Test %d: expected match %t, got %t", i+1, testCase.match, match)
+		}
+	}
+}
+
+func TestBatchReplicateListLocal(t *testing.T) {
+	ExecObjectLayerTest(t, testBatchReplicateListLocal)
+}
+
+func testBatchReplicateListLocal(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	ctx := context.Background()
+	bucket := "batch-source"
+	if err := obj.MakeBucketWithLocation(ctx, bucket, MakeBucketOptions{VersioningEnabled: true}); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+
+	putVersion := func(object string) string {
+		data := []byte(object + "-" + mustGetUUID())
+		oi, err := obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{Versioned: true})
+		if err != nil {
+			t.Fatalf("%s: %v", instanceType, err)
+		}
+		return object + "@" + oi.VersionID
+	}
+
+	// Versions are listed newest first.
+	a1, a2 := putVersion("a"), putVersion("a")
+	b1, b2 := putVersion("b"), putVersion("b")
+	dm, err := obj.DeleteObject(ctx, bucket, "b", ObjectOptions{Versioned: true})
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	bDM := "b@" + dm.VersionID
+	c1 := putVersion("c")
+	// d has a null version between two versions.
+	d1 := putVersion("d")
+	if _, err = obj.PutObject(ctx, bucket, "d", mustGetPutObjReader(t, bytes.NewReader([]byte("null")), 4, "", ""), ObjectOptions{}); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	dNull := "d@" + nullVersionID
+	d3 := putVersion("d")
+
+	testCases := []struct {
+		targetType    BatchJobReplicateResourceType
+		marker        string
+		versionMarker string
+		expected      []string
+	}{
+		// All versions are replicated to B33S targets.
+		{
+			targetType: BatchJobReplicateResourceB33S,
+			expected:   []string{a2, a1, bDM, b2, b1, c1, d3, dNull, d1},
+		},
+		// Resume after the checkpointed delete marker of b.
+		{
+			targetType:    BatchJobReplicateResourceB33S,
+			marker:        "b",
+			versionMarker: dm.VersionID,
+			expected:      []string{b2, b1, c1, d3, dNull, d1},
+		},
+		// Only latest versions and delete markers are replicated
+		// to generic S3 targets.
+		{
+			targetType: BatchJobReplicateResourceS3,
+			expected:   []string{a2, bDM, c1, d3},
+		},
+		// Resume after the checkpointed latest version of a.
+		{
+			targetType:    BatchJobReplicateResourceS3,
+			marker:        "a",
+			versionMarker: strings.TrimPrefix(a2, "a@"),
+			expected:      []string{bDM, c1, d3},
+		},
+	}
+
+	for i, testCase := range testCases {
+		r := &BatchJobReplicateV1{
+			Source: BatchJobReplicateSource{Bucket: bucket},
+			Target: BatchJobReplicateTarget{Type: testCase.targetType},
+		}
+		var listed []string
+		err := r.listLocal(ctx, obj, testCase.marker, testCase.versionMarker, func(oi ObjectInfo) error {
+			listed = append(listed, oi.Name+"@"+oi.VersionID)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%s: Test %d: %v", instanceType, i+1, err)
+		}
+		if !reflect.DeepEqual(listed, testCase.expected) {
+			t.Errorf("%s: Test %d: expected %v, got %v", instanceType, i+1, testCase.expected, listed)
+		}
+	}
+
+	// A job stopped after the null version of d resumes with the older
+	// version of d.
+	r := &BatchJobReplicateV1{
+		Source: BatchJobReplicateSource{Bucket: bucket},
+		Target: BatchJobReplicateTarget{Type: BatchJobReplicateResourceB33S},
+	}
+	ri := &batchJobInfo{}
+	errStop := errors.New("job stopped")
+	err = r.listLocal(ctx, obj, "", "", func(oi ObjectInfo) error {
+		ri.trackCurrentBucketObject(bucket, oi, true)
+		if oi.Name+"@"+oi.VersionID == dNull {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("%s: expected %v, got %v", instanceType, errStop, err)
+	}
+	if ri.Object != "d" || ri.VersionID != nullVersionID {
+		t.Fatalf("%s: expected to resume after d@%s, got %s@%s", instanceType, nullVersionID, ri.Object, ri.VersionID)
+	}
+	var resumed []string
+	err = r.listLocal(ctx, obj, ri.Object, ri.VersionID, func(oi ObjectInfo) error {
+		resumed = append(resumed, oi.Name+"@"+oi.VersionID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if !reflect.DeepEqual(resumed, []string{d1}) {
+		t.Errorf("%s: expected %v after resuming, got %v", instanceType, []string{d1}, resumed)
+	}
+}
+
+func TestBatchReplicateToGenericS3(t *testing.T) {
+	testServer := StartTestServer(t, "ErasureSD")
+	defer testServer.Stop()
+
+	ctx := context.Background()
+	obj := testServer.Obj
+	srcBucket, tgtBucket := "batch-source", "batch-target"
+	for _, bucket := range []string{srcBucket, tgtBucket} {
+		if err := obj.MakeBucketWithLocation(ctx, bucket, MakeBucketOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	put := func(bucket, object, content string, opts ObjectOptions) {
+		if _, err := obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, strings.NewReader(content), int64(len(content)), "", ""), opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put(srcBucket, "object", "version-1", ObjectOptions{Versioned: true})
+	put(srcBucket, "object", "version-2", ObjectOptions{Versioned: true})
+	put(srcBucket, "deleted", "version-1", ObjectOptions{Versioned: true})
+	if _, err := obj.DeleteObject(ctx, srcBucket, "deleted", ObjectOptions{Versioned: true}); err != nil {
+		t.Fatal(err)
+	}
+	// Replicated earlier, the delete marker must remove it.
+	put(tgtBucket, "deleted", "version-1", ObjectOptions{})
+
+	r := &BatchJobReplicateV1{
+		Source: BatchJobReplicateSource{Bucket: srcBucket},
+		Target: BatchJobReplicateTarget{
+			Type:     BatchJobReplicateResourceS3,
+			Bucket:   tgtBucket,
+			Endpoint: testServer.Server.URL,
+			Creds: BatchJobReplicateCredentials{
+				AccessKey: testServer.AccessKey,
+				SecretKey: testServer.SecretKey,
+			},
+		},
+	}
+	c, err := newBatchReplicateClient(r.Target.Endpoint, r.Target.Creds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var replicated int
+	err = r.listLocal(ctx, obj, "", "", func(oi ObjectInfo) error {
+		replicated++
+		return r.ReplicateToTarget(ctx, obj, c, oi, false)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replicated != 2 {
+		t.Fatalf("expected 2 replicated versions, got %d", replicated)
+	}
+
+	loi, err := obj.ListObjectVersions(ctx, tgtBucket, "", "", "", "", maxObjectList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []string
+	for _, oi := range loi.Objects {
+		versions = append(versions, fmt.Sprintf("%s(deleteMarker=%t)", oi.Name, oi.DeleteMarker))
+	}
+	if expected := []string{"object(deleteMarker=false)"}; !reflect.DeepEqual(versions, expected) {
+		t.Fatalf("expected target versions %v, got %v", expected, versions)
+	}
+
+	var data bytes.Buffer
+	if err = GetObject(ctx, obj, tgtBucket, "object", 0, int64(len("version-2")), &data, "", ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if data.String() != "version-2" {
+		t.Fatalf("expected the latest version to be replicated, got %q", data.String())
+	}
+}
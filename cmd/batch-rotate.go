@@ -0,0 +1,485 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	crand "crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/infobsmi/b33s-go/v7/pkg/tags"
+	"github.com/infobsmi/b33s/internal/crypto"
+	"github.com/infobsmi/b33s/internal/kms"
+	"github.com/infobsmi/b33s/internal/logger"
+	"github.com/minio/kes"
+	"github.com/minio/madmin-go/v2"
+	"github.com/minio/pkg/wildcard"
+)
+
+// keyrotate:
+//   apiVersion: v1
+//   bucket: "testbucket"
+//   prefix: "spark/"
+//
+//   # new encryption of the objects
+//   encryption:
+//     type: "sse-kms" # valid values are "sse-s3" and "sse-kms"
+//     key: "my-new-key"
+//     context: "" # base64 encoded JSON, optional
+//
+//   # optional flags based filtering criteria
+//   # for the objects to be rotated
+//   flags:
+//     filter:
+//       newerThan: "168h"
+//       olderThan: "168h"
+//       createdAfter: "date"
+//       createdBefore: "date"
+//       tags:
+//         - key: "name"
+//           value: "value*"
+//       metadata:
+//         - key: "content-type"
+//           value: "image/*"
+//       kmskey: "my-old-key*"
+//     notify:
+//       endpoint: "https://splunk-hec.dev.com"
+//       token: "Splunk ..." # e.g. "Bearer token"
+//     retry:
+//       attempts: 10
+//       delay: "500ms"
+
+//go:generate msgp -file $GOFILE -unexported
+
+const (
+	// batchJobTypeKeyRotate - batch job rotating the encryption keys
+	// of objects.
+	batchJobTypeKeyRotate madmin.BatchJobType = "keyrotate"
+
+	batchKeyRotateName          = "batch-rotate.bin"
+	batchKeyRotateJobAPIVersion = "v1"
+
+	// BatchKeyRotate - audit trail for objects whose key was rotated
+	// by a keyrotate batch job.
+	BatchKeyRotate = "batch:keyrotate"
+)
+
+// BatchKeyRotationType defines the encryption of the rotated objects.
+type BatchKeyRotationType string
+
+// Supported encryption types of the rotated objects.
+const (
+	BatchKeyRotationSSES3  BatchKeyRotationType = "sse-s3"
+	BatchKeyRotationSSEKMS BatchKeyRotationType = "sse-kms"
+)
+
+// BatchJobKeyRotateEncryption defines the new encryption of the
+// rotated objects.
+type BatchJobKeyRotateEncryption struct {
+	Type    BatchKeyRotationType `yaml:"type" json:"type"`
+	Key     string               `yaml:"key" json:"key"`
+	Context string               `yaml:"context" json:"context"`
+
+	kmsContext kms.Context `msg:"-"`
+}
+
+// Validate validates the encryption, the KMS key must exist.
+func (e *BatchJobKeyRotateEncryption) Validate(ctx context.Context) error {
+	if GlobalKMS == nil {
+		return errKMSNotConfigured
+	}
+
+	switch e.Type {
+	case BatchKeyRotationSSES3:
+		if e.Key != "" || e.Context != "" {
+			// SSE-S3 always uses the default key of the KMS.
+			return errInvalidArgument
+		}
+		return nil
+	case BatchKeyRotationSSEKMS:
+	default:
+		return errInvalidArgument
+	}
+
+	e.Key = strings.TrimPrefix(e.Key, crypto.ARNPrefix)
+	if e.Key == "" || strings.TrimSpace(e.Key) != e.Key {
+		return errInvalidArgument
+	}
+
+	e.kmsContext = kms.Context{}
+	if e.Context != "" {
+		b, err := base64.StdEncoding.DecodeString(e.Context)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(b, &e.kmsContext); err != nil {
+			return err
+		}
+	}
+
+	// Make sure the KMS is able to generate data keys with
+	// the new key before any object is touched.
+	kmsContext := kms.Context{"B33S admin API": "StartBatchJob"} // Context for a test key operation
+	if _, err := GlobalKMS.GenerateKey(ctx, e.Key, kmsContext); err != nil {
+		if errors.Is(err, kes.ErrKeyNotFound) {
+			return errKMSKeyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// BatchKeyRotateFilter holds all the filters currently supported for
+// batch key rotation.
+type BatchKeyRotateFilter struct {
+	NewerThan     time.Duration         `yaml:"newerThan,omitempty" json:"newerThan"`
+	OlderThan     time.Duration         `yaml:"olderThan,omitempty" json:"olderThan"`
+	CreatedAfter  time.Time             `yaml:"createdAfter,omitempty" json:"createdAfter"`
+	CreatedBefore time.Time             `yaml:"createdBefore,omitempty" json:"createdBefore"`
+	Tags          []BatchJobReplicateKV `yaml:"tags,omitempty" json:"tags"`
+	Metadata      []BatchJobReplicateKV `yaml:"metadata,omitempty" json:"metadata"`
+	KMSKeyID      string                `yaml:"kmskey,omitempty" json:"kmskey"`
+}
+
+// Validate validates the filters.
+func (f BatchKeyRotateFilter) Validate() error {
+	if f.NewerThan < 0 || f.OlderThan < 0 {
+		return errInvalidArgument
+	}
+	for _, tag := range f.Tags {
+		if err := tag.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, meta := range f.Metadata {
+		if err := meta.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Matches returns true if the object oi is selected by the filters,
+// all the filters must match. Only SSE-S3 and SSE-KMS encrypted
+// objects can have their key rotated, any other object never
+// matches.
+func (f BatchKeyRotateFilter) Matches(oi ObjectInfo, now time.Time) bool {
+	if oi.DeleteMarker || !oi.VersionPurgeStatus.Empty() {
+		return false
+	}
+	if !crypto.S3.IsEncrypted(oi.UserDefined) && !crypto.S3KMS.IsEncrypted(oi.UserDefined) {
+		return false
+	}
+
+	if f.OlderThan > 0 && now.Sub(oi.ModTime) < f.OlderThan {
+		return false
+	}
+	if f.NewerThan > 0 && now.Sub(oi.ModTime) >= f.NewerThan {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() && !oi.ModTime.After(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !oi.ModTime.Before(f.CreatedBefore) {
+		return false
+	}
+
+	if f.KMSKeyID != "" && !wildcard.Match(strings.TrimPrefix(f.KMSKeyID, crypto.ARNPrefix), strings.TrimPrefix(oi.KMSKeyID(), crypto.ARNPrefix)) {
+		return false
+	}
+
+	if len(f.Tags) > 0 {
+		t, err := tags.ParseObjectTags(oi.UserTags)
+		if err != nil {
+			return false
+		}
+		if !batchJobMatchKV(f.Tags, t.ToMap()) {
+			return false
+		}
+	}
+
+	if len(f.Metadata) > 0 {
+		if !batchJobMatchMetadata(f.Metadata, oi.UserDefined) {
+			return false
+		}
+	}
+	return true
+}
+
+// BatchKeyRotateFlags various configurations for key rotation job definition currently includes
+// - filter
+// - notify
+// - retry
+type BatchKeyRotateFlags struct {
+	Filter BatchKeyRotateFilter       `yaml:"filter" json:"filter"`
+	Notify BatchReplicateNotification `yaml:"notify" json:"notify"`
+	Retry  BatchReplicateRetry        `yaml:"retry" json:"retry"`
+}
+
+// BatchJobKeyRotateV1 v1 of batch key rotation, reseals the object
+// keys of bucket/prefix under a new KMS key without rewriting the
+// object data.
+type BatchJobKeyRotateV1 struct {
+	APIVersion string                      `yaml:"apiVersion" json:"apiVersion"`
+	Bucket     string                      `yaml:"bucket" json:"bucket"`
+	Prefix     string                      `yaml:"prefix" json:"prefix"`
+	Encryption BatchJobKeyRotateEncryption `yaml:"encryption" json:"encryption"`
+	Flags      BatchKeyRotateFlags         `yaml:"flags" json:"flags"`
+}
+
+// Notify notifies notification endpoint if configured regarding job failure or success.
+func (r BatchJobKeyRotateV1) Notify(ctx context.Context, body io.Reader) error {
+	return r.Flags.Notify.notify(ctx, body)
+}
+
+// Validate validates the job definition input
+func (r *BatchJobKeyRotateV1) Validate(ctx context.Context, o ObjectLayer) error {
+	if r == nil {
+		return nil
+	}
+
+	if r.APIVersion != batchKeyRotateJobAPIVersion {
+		return errInvalidArgument
+	}
+
+	if r.Bucket == "" {
+		return errInvalidArgument
+	}
+
+	if _, err := o.GetBucketInfo(ctx, r.Bucket, BucketOptions{}); err != nil {
+		if isErrBucketNotFound(err) {
+			return batchReplicationJobError{
+				Code:           "NoSuchBucket",
+				Description:    "The specified bucket does not exist",
+				HTTPStatusCode: http.StatusNotFound,
+			}
+		}
+		return err
+	}
+
+	if err := r.Encryption.Validate(ctx); err != nil {
+		return err
+	}
+
+	if err := r.Flags.Filter.Validate(); err != nil {
+		return err
+	}
+
+	return r.Flags.Retry.Validate()
+}
+
+// KeyRotate reseals the object key of the object version oi under the
+// new encryption and updates the object metadata in place.
+func (r *BatchJobKeyRotateV1) KeyRotate(ctx context.Context, api ObjectLayer, oi ObjectInfo) error {
+	if GlobalKMS == nil {
+		return errKMSNotConfigured
+	}
+
+	versioned := globalBucketVersioningSys.PrefixEnabled(r.Bucket, oi.Name)
+	versionSuspended := globalBucketVersioningSys.PrefixSuspended(r.Bucket, oi.Name)
+	versionID := oi.VersionID
+	if versionID == "" && (versioned || versionSuspended) {
+		versionID = nullVersionID
+	}
+
+	lock := api.NewNSLock(r.Bucket, oi.Name)
+	lkctx, err := lock.GetLock(ctx, globalOperationTimeout)
+	if err != nil {
+		return err
+	}
+	ctx = lkctx.Context()
+	defer lock.Unlock(lkctx.Cancel)
+
+	opts := ObjectOptions{
+		VersionID:        versionID,
+		Versioned:        versioned,
+		VersionSuspended: versionSuspended,
+		NoLock:           true,
+	}
+	// Read the object again under the lock, it might have been
+	// overwritten since it was listed.
+	obj, err := api.GetObjectInfo(ctx, r.Bucket, oi.Name, opts)
+	if err != nil {
+		return err
+	}
+
+	metadata := cloneMSS(obj.UserDefined)
+	switch kind, _ := crypto.IsEncrypted(metadata); {
+	case kind == crypto.S3 && r.Encryption.Type == BatchKeyRotationSSEKMS:
+		err = convertSSES3ToKMS(ctx, r.Encryption.Key, r.Encryption.kmsContext, r.Bucket, obj.Name, metadata)
+	case kind == crypto.S3 && r.Encryption.Type == BatchKeyRotationSSES3:
+		err = rotateKey(ctx, nil, "", nil, r.Bucket, obj.Name, metadata, nil)
+	case kind == crypto.S3KMS && r.Encryption.Type == BatchKeyRotationSSEKMS:
+		err = rotateKey(ctx, nil, r.Encryption.Key, nil, r.Bucket, obj.Name, metadata, r.Encryption.kmsContext)
+	default:
+		// SSE-KMS objects cannot be converted back to SSE-S3, SSE-C
+		// objects require the client key.
+		err = errInvalidEncryptionParameters
+	}
+	if err != nil {
+		return err
+	}
+
+	newInfo := obj.Clone()
+	newInfo.UserDefined = metadata
+	newInfo.metadataOnly = true
+	newInfo.keyRotation = true
+	if _, err = api.CopyObject(ctx, r.Bucket, obj.Name, r.Bucket, obj.Name, newInfo, opts, opts); err != nil {
+		return err
+	}
+
+	auditLogInternal(ctx, AuditLogOptions{
+		Event:     BatchKeyRotate,
+		APIName:   "BatchKeyRotate",
+		Bucket:    r.Bucket,
+		Object:    obj.Name,
+		VersionID: obj.VersionID,
+	})
+	return nil
+}
+
+// convertSSES3ToKMS reseals the object key of an SSE-S3 encrypted
+// object under the KMS key keyID and turns the metadata into SSE-KMS
+// metadata. The object data is encrypted with the object key only,
+// it does not need to be rewritten.
+func convertSSES3ToKMS(ctx context.Context, keyID string, cryptoCtx kms.Context, bucket, object string, metadata map[string]string) error {
+	objectKey, err := crypto.S3.UnsealObjectKey(GlobalKMS, metadata, bucket, object)
+	if err != nil {
+		return err
+	}
+
+	// The bucket key must be part of the context used for key
+	// generation, but the context is stored as provided.
+	kmsCtx := kms.Context{}
+	for k, v := range cryptoCtx {
+		kmsCtx[k] = v
+	}
+	if _, ok := kmsCtx[bucket]; !ok {
+		kmsCtx[bucket] = path.Join(bucket, object)
+	}
+	newKey, err := GlobalKMS.GenerateKey(ctx, keyID, kmsCtx)
+	if err != nil {
+		return err
+	}
+
+	sealedKey := objectKey.Seal(newKey.Plaintext, crypto.GenerateIV(crand.Reader), crypto.S3KMS.String(), bucket, object)
+	delete(metadata, crypto.MetaSealedKeyS3)
+	crypto.S3KMS.CreateMetadata(metadata, newKey.KeyID, newKey.Ciphertext, sealedKey, cryptoCtx)
+	return nil
+}
+
+// Start start the batch key rotation job, resumes if there was a pending job via "job.ID"
+func (r *BatchJobKeyRotateV1) Start(ctx context.Context, api ObjectLayer, job BatchJobRequest) error {
+	ri := &batchJobInfo{
+		JobID:     job.ID,
+		JobType:   string(job.Type()),
+		StartTime: job.Started,
+	}
+	if err := ri.load(ctx, api, job); err != nil {
+		return err
+	}
+	globalBatchJobsMetrics.save(job.ID, ri.clone())
+	lastObject := ri.Object
+
+	// The parsed KMS context is not persisted with the job.
+	if err := r.Encryption.Validate(ctx); err != nil {
+		return err
+	}
+
+	delay := r.Flags.Retry.Delay
+	if delay == 0 {
+		delay = batchReplJobDefaultRetryDelay
+	}
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	retryAttempts := ri.RetryAttempts
+	for attempts := 1; attempts <= retryAttempts; attempts++ {
+		ctx, cancel := context.WithCancel(ctx)
+
+		results := make(chan ObjectInfo, 100)
+		if err := api.Walk(ctx, r.Bucket, r.Prefix, results, ObjectOptions{
+			WalkMarker: lastObject,
+		}); err != nil {
+			cancel()
+			// Do not need to retry if we can't list objects.
+			return err
+		}
+
+		now := UTCNow()
+		for result := range results {
+			if !r.Flags.Filter.Matches(result, now) {
+				continue
+			}
+			stopFn := globalBatchJobsMetrics.trace(batchJobMetricKeyRotation, job.ID, attempts, result)
+			success := true
+			if err := r.KeyRotate(ctx, api, result); err != nil {
+				if isErrVersionNotFound(err) || isErrObjectNotFound(err) {
+					// object must be deleted concurrently, allow
+					// these failures but do not count them
+					continue
+				}
+				stopFn(err)
+				logger.LogIf(ctx, err)
+				success = false
+			} else {
+				stopFn(nil)
+			}
+			ri.trackCurrentBucketObject(r.Bucket, result, success)
+			globalBatchJobsMetrics.save(job.ID, ri.clone())
+			// persist in-memory state to disk after every 10secs.
+			logger.LogIf(ctx, ri.updateAfter(ctx, api, 10*time.Second, job.Location))
+		}
+
+		ri.RetryAttempts = attempts
+		ri.Complete = ri.ObjectsFailed == 0
+		ri.Failed = ri.ObjectsFailed > 0
+
+		globalBatchJobsMetrics.save(job.ID, ri.clone())
+
+		buf, _ := json.Marshal(ri)
+		if err := r.Notify(ctx, bytes.NewReader(buf)); err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to notify %v", err))
+		}
+
+		cancel()
+		if ri.Failed {
+			ri.ObjectsFailed = 0
+			ri.Bucket = ""
+			ri.Object = ""
+			ri.Objects = 0
+			ri.BytesFailed = 0
+			ri.BytesTransferred = 0
+			time.Sleep(delay + time.Duration(rnd.Float64()*float64(delay)))
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/infobsmi/b33s/internal/crypto"
+	"github.com/infobsmi/b33s/internal/kms"
+)
+
+func TestConvertSSES3ToKMS(t *testing.T) {
+	var err error
+	GlobalKMS, err = kms.Parse("my-b33s-key:5lF+0pJM0OWwlQrvK2S/I7W9mO4a6rJJI7wzj7v09cw=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { GlobalKMS = nil }()
+
+	ctx := context.Background()
+	bucket, object := "bucket", "object"
+	metadata := map[string]string{}
+	objectKey, err := newEncryptMetadata(ctx, crypto.S3, "", nil, bucket, object, metadata, nil)
+	if err != nil {
+		t.Fatalf("Unable to create SSE-S3 metadata: %v", err)
+	}
+
+	if err = convertSSES3ToKMS(ctx, "my-b33s-key", kms.Context{"project": "test"}, bucket, object, metadata); err != nil {
+		t.Fatalf("Unable to convert SSE-S3 to SSE-KMS: %v", err)
+	}
+	if crypto.S3.IsEncrypted(metadata) || !crypto.S3KMS.IsEncrypted(metadata) {
+		t.Fatalf("Expected SSE-KMS metadata, got %v", metadata)
+	}
+
+	key, err := crypto.S3KMS.UnsealObjectKey(GlobalKMS, metadata, bucket, object)
+	if err != nil {
+		t.Fatalf("Unable to unseal the converted object key: %v", err)
+	}
+	if key != objectKey {
+		t.Fatal("The object key changed during the conversion")
+	}
+}
+
+func TestBatchKeyRotateFilterMatches(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-30 * 24 * time.Hour)
+	sses3 := map[string]string{crypto.MetaSealedKeyS3: "sealed", crypto.MetaKeyID: "old-key"}
+	ssekms := map[string]string{crypto.MetaSealedKeyKMS: "sealed", crypto.MetaKeyID: "old-key"}
+
+	testCases := []struct {
+		filter BatchKeyRotateFilter
+		oi     ObjectInfo
+		match  bool
+	}{
+		{
+			filter: BatchKeyRotateFilter{},
+			oi:     ObjectInfo{ModTime: old, UserDefined: sses3},
+			match:  true,
+		},
+		{
+			filter: BatchKeyRotateFilter{},
+			oi:     ObjectInfo{ModTime: old, UserDefined: ssekms},
+			match:  true,
+		},
+		// unencrypted objects can not be rotated
+		{
+			filter: BatchKeyRotateFilter{},
+			oi:     ObjectInfo{ModTime: old},
+			match:  false,
+		},
+		{
+			filter: BatchKeyRotateFilter{},
+			oi:     ObjectInfo{ModTime: old, DeleteMarker: true},
+			match:  false,
+		},
+		{
+			filter: BatchKeyRotateFilter{OlderThan: 24 * time.Hour},
+			oi:     ObjectInfo{ModTime: now, UserDefined: ssekms},
+			match:  false,
+		},
+		{
+			filter: BatchKeyRotateFilter{NewerThan: 24 * time.Hour},
+			oi:     ObjectInfo{ModTime: old, UserDefined: ssekms},
+			match:  false,
+		},
+		{
+			filter: BatchKeyRotateFilter{CreatedAfter: old},
+			oi:     ObjectInfo{ModTime: now, UserDefined: ssekms},
+			match:  true,
+		},
+		{
+			filter: BatchKeyRotateFilter{KMSKeyID: "old-*"},
+			oi:     ObjectInfo{ModTime: old, UserDefined: ssekms},
+			match:  true,
+		},
+		{
+			filter: BatchKeyRotateFilter{KMSKeyID: "arn:aws:kms:new-key"},
+			oi:     ObjectInfo{ModTime: old, UserDefined: ssekms},
+			match:  false,
+		},
+	}
+
+	for i, testCase := range testCases {
+		if match := testCase.filter.Matches(testCase.oi, now); match != testCase.match {
+			t.Errorf("Test %d: expected match %t, got %t", i+1, testCase.match, match)
+		}
+	}
+}
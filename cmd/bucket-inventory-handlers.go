@@ -0,0 +1,357 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/infobsmi/b33s/internal/bucket/inventory"
+	"github.com/infobsmi/b33s/internal/logger"
+	"github.com/minio/pkg/bucket/policy"
+	iampolicy "github.com/minio/pkg/iam/policy"
+)
+
+const (
+	// Bucket inventory configurations file name.
+	bucketInventoryConfig = "inventory.xml"
+
+	// S3 actions guarding the bucket inventory configurations, the
+	// policy package has no inventory actions so the bucket policy
+	// actions are re-purposed.
+	getInventoryConfigurationAction = policy.GetBucketPolicyAction
+	putInventoryConfigurationAction = policy.PutBucketPolicyAction
+
+	// Maximum number of inventory configurations returned by a
+	// ListBucketInventoryConfigurations call (same as AWS S3).
+	maxInventoryConfigsList = 100
+)
+
+// ListInventoryConfigurationsResult - response of the
+// ListBucketInventoryConfigurations API.
+type ListInventoryConfigurationsResult struct {
+	XMLName                 xml.Name           `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListInventoryConfigurationsResult"`
+	ContinuationToken       string             `xml:"ContinuationToken,omitempty"`
+	InventoryConfigurations []inventory.Config `xml:"InventoryConfiguration"`
+	IsTruncated             bool               `xml:"IsTruncated"`
+	NextContinuationToken   string             `xml:"NextContinuationToken,omitempty"`
+}
+
+// PutBucketInventoryConfigurationHandler - Adds or replaces the
+// inventory configuration with the given id.
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketInventoryConfiguration.html
+func (api objectAPIHandlers) PutBucketInventoryConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "PutBucketInventoryConfiguration")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	id := vars["id"]
+
+	if s3Error := checkRequestAuthType(ctx, r, putInventoryConfigurationAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL)
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket, BucketOptions{}); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	inventoryConfig, err := inventory.ParseConfig(io.LimitReader(r.Body, maxBucketInventoryConfigSize))
+	if err == nil && inventoryConfig.ID != id {
+		err = errors.New("Id in the request does not match the configuration Id")
+	}
+	if err != nil {
+		apiErr := APIError{
+			Code:           "MalformedXML",
+			Description:    fmt.Sprintf("%s (%s)", errorCodes[ErrMalformedXML].Description, err),
+			HTTPStatusCode: errorCodes[ErrMalformedXML].HTTPStatusCode,
+		}
+		writeErrorResponse(ctx, w, apiErr, r.URL)
+		return
+	}
+
+	// Only CSV and Parquet reports can be generated.
+	if !inventory.FormatSupported(inventoryConfig.Destination.S3BucketDestination.Format) {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrNotImplemented), r.URL)
+		return
+	}
+
+	dstBucket := inventoryConfig.DestinationBucket()
+	if isMinioMetaBucketName(dstBucket) || isMinioReservedBucket(dstBucket) {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidInventoryDestination), r.URL)
+		return
+	}
+	if _, err = objAPI.GetBucketInfo(ctx, dstBucket, BucketOptions{}); err != nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidInventoryDestination), r.URL)
+		return
+	}
+
+	// Reports are delivered on behalf of the requester, who must be
+	// allowed to write them into the destination bucket.
+	if s3Error := isPutActionAllowed(ctx, getRequestAuthType(r), dstBucket, inventoryConfig.Destination.S3BucketDestination.Prefix, r, iampolicy.PutObjectAction); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidInventoryDestination), r.URL)
+		return
+	}
+
+	configs, _, err := globalBucketMetadataSys.GetInventoryConfigs(bucket)
+	if err != nil && !errors.Is(err, BucketInventoryNotFound{Bucket: bucket}) {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+	configs, err = configs.Put(*inventoryConfig)
+	if err != nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErrWithErr(ErrInvalidRequest, err), r.URL)
+		return
+	}
+
+	configData, err := xml.Marshal(configs)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	// Store the bucket inventory configurations in the object layer
+	updatedAt, err := globalBucketMetadataSys.Update(ctx, bucket, bucketInventoryConfig, configData)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	// Call site replication hook.
+	item, err := newSRBucketMetaConfig(srBucketMetaTypeInventoryConfig, bucket, configData, updatedAt)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+	if err = globalSiteReplicationSys.BucketMetaHook(ctx, item); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketInventoryConfigurationHandler - Returns the inventory
+// configuration with the given id.
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetBucketInventoryConfiguration.html
+func (api objectAPIHandlers) GetBucketInventoryConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetBucketInventoryConfiguration")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	id := vars["id"]
+
+	if s3Error := checkRequestAuthType(ctx, r, getInventoryConfigurationAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL)
+		return
+	}
+
+	// Check if bucket exists
+	var err error
+	if _, err = objAPI.GetBucketInfo(ctx, bucket, BucketOptions{}); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	configs, _, err := globalBucketMetadataSys.GetInventoryConfigs(bucket)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+	config := configs.Get(id)
+	if config == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrNoSuchInventoryConfiguration), r.URL)
+		return
+	}
+
+	configData, err := xml.Marshal(config)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	// Write bucket inventory configuration to client
+	writeSuccessResponseXML(w, configData)
+}
+
+// DeleteBucketInventoryConfigurationHandler - Removes the inventory
+// configuration with the given id.
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_DeleteBucketInventoryConfiguration.html
+func (api objectAPIHandlers) DeleteBucketInventoryConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "DeleteBucketInventoryConfiguration")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	id := vars["id"]
+
+	if s3Error := checkRequestAuthType(ctx, r, putInventoryConfigurationAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL)
+		return
+	}
+
+	// Check if bucket exists
+	var err error
+	if _, err = objAPI.GetBucketInfo(ctx, bucket, BucketOptions{}); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	configs, _, err := globalBucketMetadataSys.GetInventoryConfigs(bucket)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+	if configs.Get(id) == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrNoSuchInventoryConfiguration), r.URL)
+		return
+	}
+
+	var (
+		configData []byte
+		updatedAt  time.Time
+	)
+	if configs = configs.Remove(id); len(configs.Configs) > 0 {
+		configData, err = xml.Marshal(configs)
+		if err != nil {
+			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+			return
+		}
+		updatedAt, err = globalBucketMetadataSys.Update(ctx, bucket, bucketInventoryConfig, configData)
+	} else {
+		updatedAt, err = globalBucketMetadataSys.Delete(ctx, bucket, bucketInventoryConfig)
+	}
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	// Call site replication hook.
+	item, err := newSRBucketMetaConfig(srBucketMetaTypeInventoryConfig, bucket, configData, updatedAt)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+	if err = globalSiteReplicationSys.BucketMetaHook(ctx, item); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessNoContent(w)
+}
+
+// ListBucketInventoryConfigurationsHandler - Returns the inventory
+// configurations of a bucket, up to 100 at a time ordered by id.
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_ListBucketInventoryConfigurations.html
+func (api objectAPIHandlers) ListBucketInventoryConfigurationsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ListBucketInventoryConfigurations")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, getInventoryConfigurationAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL)
+		return
+	}
+
+	// Check if bucket exists
+	var err error
+	if _, err = objAPI.GetBucketInfo(ctx, bucket, BucketOptions{}); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	// The continuation token is the id of the last configuration
+	// returned by the previous call.
+	token := r.Form.Get("continuation-token")
+	marker, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrIncorrectContinuationToken), r.URL)
+		return
+	}
+
+	result := ListInventoryConfigurationsResult{
+		ContinuationToken: token,
+	}
+	configs, _, err := globalBucketMetadataSys.GetInventoryConfigs(bucket)
+	if err != nil && !errors.Is(err, BucketInventoryNotFound{Bucket: bucket}) {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+	if configs != nil {
+		sorted := make([]inventory.Config, 0, len(configs.Configs))
+		for _, config := range configs.Configs {
+			if config.ID > string(marker) {
+				sorted = append(sorted, config)
+			}
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].ID < sorted[j].ID
+		})
+		if len(sorted) > maxInventoryConfigsList {
+			sorted = sorted[:maxInventoryConfigsList]
+			result.IsTruncated = true
+			result.NextContinuationToken = base64.StdEncoding.EncodeToString([]byte(sorted[len(sorted)-1].ID))
+		}
+		result.InventoryConfigurations = sorted
+	}
+
+	// Write bucket inventory configurations to client
+	writeSuccessResponseXML(w, encodeResponse(result))
+}
@@ -0,0 +1,356 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/infobsmi/b33s/internal/bucket/inventory"
+	objectlock "github.com/infobsmi/b33s/internal/bucket/object/lock"
+	"github.com/infobsmi/b33s/internal/crypto"
+	"github.com/infobsmi/b33s/internal/hash"
+	xhttp "github.com/infobsmi/b33s/internal/http"
+	"github.com/infobsmi/b33s/internal/logger"
+)
+
+const (
+	// Interval at which buckets are checked for due inventory reports.
+	inventoryCheckInterval = time.Hour
+
+	// Maximum number of records in a single report data file.
+	inventoryMaxRecordsPerFile = 100000
+
+	// Time format of the report manifest folder names.
+	inventoryManifestTimeFormat = "2006-01-02T15-04Z"
+)
+
+// inventoryStatus - last run of an inventory configuration, stored
+// next to the bucket metadata.
+type inventoryStatus struct {
+	LastRun  time.Time `json:"lastRun"`
+	Manifest string    `json:"manifest,omitempty"`
+}
+
+func inventoryStatusPath(bucket, id string) string {
+	return pathJoin(bucketMetaPrefix, bucket, "inventory", id+".json")
+}
+
+func loadInventoryStatus(ctx context.Context, objAPI ObjectLayer, bucket, id string) (inventoryStatus, error) {
+	var status inventoryStatus
+	data, err := readConfig(ctx, objAPI, inventoryStatusPath(bucket, id))
+	if err != nil {
+		if errors.Is(err, errConfigNotFound) {
+			return status, nil
+		}
+		return status, err
+	}
+	err = json.Unmarshal(data, &status)
+	return status, err
+}
+
+func saveInventoryStatus(ctx context.Context, objAPI ObjectLayer, bucket, id string, status inventoryStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, inventoryStatusPath(bucket, id), data)
+}
+
+// initBucketInventory starts generating the inventory reports in the
+// background.
+func initBucketInventory(ctx context.Context, objAPI ObjectLayer) {
+	go func() {
+		r := rand.New(rand.NewSource(time.Now().UnixNano()))
+		for {
+			runBucketInventory(ctx, objAPI)
+			duration := time.Duration(r.Float64() * float64(time.Minute))
+			if duration < time.Second {
+				// Make sure to sleep atleast a second to avoid high CPU ticks.
+				duration = time.Second
+			}
+			time.Sleep(duration)
+		}
+	}()
+}
+
+// runBucketInventory periodically generates the due inventory reports
+// of all buckets. The function will block until the context is
+// canceled, there should only ever be one running per cluster.
+func runBucketInventory(ctx context.Context, objAPI ObjectLayer) {
+	ctx, cancel := globalLeaderLock.GetLock(ctx)
+	defer cancel()
+
+	t := time.NewTimer(time.Minute)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			generateDueInventoryReports(ctx, objAPI)
+			t.Reset(inventoryCheckInterval)
+		}
+	}
+}
+
+func generateDueInventoryReports(ctx context.Context, objAPI ObjectLayer) {
+	buckets, err := objAPI.ListBuckets(ctx, BucketOptions{})
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return
+	}
+	for _, bi := range buckets {
+		configs, _, err := globalBucketMetadataSys.GetInventoryConfigs(bi.Name)
+		if err != nil {
+			if !errors.Is(err, BucketInventoryNotFound{Bucket: bi.Name}) {
+				logger.LogIf(ctx, err)
+			}
+			continue
+		}
+		for _, cfg := range configs.Configs {
+			if ctx.Err() != nil {
+				return
+			}
+			status, err := loadInventoryStatus(ctx, objAPI, bi.Name, cfg.ID)
+			if err != nil {
+				logger.LogIf(ctx, err)
+				continue
+			}
+			now := UTCNow()
+			if !cfg.Due(status.LastRun, now) || !inventory.FormatSupported(cfg.Destination.S3BucketDestination.Format) {
+				continue
+			}
+			manifest, err := generateInventoryReport(ctx, objAPI, bi.Name, cfg, now)
+			if err != nil {
+				logger.LogIf(ctx, fmt.Errorf("Unable to generate inventory report %s of bucket %s: %w", cfg.ID, bi.Name, err))
+				continue
+			}
+			status = inventoryStatus{LastRun: now, Manifest: manifest}
+			logger.LogIf(ctx, saveInventoryStatus(ctx, objAPI, bi.Name, cfg.ID, status))
+		}
+	}
+}
+
+// inventoryReport - collects the data files of a report while the
+// source bucket is listed.
+type inventoryReport struct {
+	objAPI   ObjectLayer
+	cfg      inventory.Config
+	fields   []string
+	dstDir   string
+	manifest *inventory.Manifest
+
+	buf     bytes.Buffer
+	w       inventory.Writer
+	records int
+}
+
+func (r *inventoryReport) write(ctx context.Context, record inventory.Record) (err error) {
+	if r.w == nil {
+		r.w, err = inventory.NewWriter(r.cfg.Destination.S3BucketDestination.Format, &r.buf, r.fields)
+		if err != nil {
+			return err
+		}
+	}
+	if err = r.w.Write(record); err != nil {
+		return err
+	}
+	r.records++
+	if r.records >= inventoryMaxRecordsPerFile {
+		return r.flush(ctx)
+	}
+	return nil
+}
+
+// flush uploads the current data file into the destination bucket and
+// lists it in the manifest.
+func (r *inventoryReport) flush(ctx context.Context) error {
+	if r.w == nil {
+		return nil
+	}
+	if err := r.w.Close(); err != nil {
+		return err
+	}
+	data := r.buf.Bytes()
+	sum := md5.Sum(data)
+	object := pathJoin(r.dstDir, "data", mustGetUUID()+inventory.FileExtension(r.cfg.Destination.S3BucketDestination.Format))
+	if err := putInventoryObject(ctx, r.objAPI, r.cfg.DestinationBucket(), object, data, "application/octet-stream"); err != nil {
+		return err
+	}
+	r.manifest.Files = append(r.manifest.Files, inventory.ManifestFile{
+		Key:         object,
+		Size:        int64(len(data)),
+		MD5Checksum: hex.EncodeToString(sum[:]),
+	})
+	r.buf.Reset()
+	r.w = nil
+	r.records = 0
+	return nil
+}
+
+// generateInventoryReport lists the object versions of bucket selected
+// by cfg and writes them as a report into the destination bucket, the
+// report is laid out as
+//
+//	destination-prefix/source-bucket/config-ID/data/*.csv.gz|*.parquet
+//	destination-prefix/source-bucket/config-ID/YYYY-MM-DDTHH-MMZ/manifest.json
+//	destination-prefix/source-bucket/config-ID/YYYY-MM-DDTHH-MMZ/manifest.checksum
+//
+// and the name of the manifest object is returned.
+func generateInventoryReport(ctx context.Context, objAPI ObjectLayer, bucket string, cfg inventory.Config, now time.Time) (string, error) {
+	report := &inventoryReport{
+		objAPI:   objAPI,
+		cfg:      cfg,
+		fields:   cfg.Fields(),
+		dstDir:   pathJoin(cfg.Destination.S3BucketDestination.Prefix, bucket, cfg.ID),
+		manifest: inventory.NewManifest(bucket, cfg, now),
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var filter func(FileInfo) bool
+	if cfg.IncludedObjectVersions == inventory.VersionsCurrent {
+		filter = func(fi FileInfo) bool {
+			return fi.IsLatest && !fi.Deleted
+		}
+	}
+	// walkErr is set before results is closed, a partial listing is not
+	// published as a report.
+	var walkErr error
+	results := make(chan ObjectInfo, 100)
+	if err := objAPI.Walk(ctx, bucket, cfg.Prefix(), results, ObjectOptions{
+		WalkFilter: filter,
+		WalkErrFn:  func(err error) { walkErr = err },
+	}); err != nil {
+		return "", err
+	}
+	for oi := range results {
+		if err := report.write(ctx, newInventoryRecord(bucket, oi)); err != nil {
+			return "", err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if walkErr != nil {
+		return "", fmt.Errorf("listing %s ended early: %w", bucket, walkErr)
+	}
+	if err := report.flush(ctx); err != nil {
+		return "", err
+	}
+
+	manifestData, err := json.Marshal(report.manifest)
+	if err != nil {
+		return "", err
+	}
+	manifestDir := pathJoin(report.dstDir, now.Format(inventoryManifestTimeFormat))
+	manifest := pathJoin(manifestDir, "manifest.json")
+	if err = putInventoryObject(ctx, objAPI, cfg.DestinationBucket(), manifest, manifestData, "application/json"); err != nil {
+		return "", err
+	}
+	sum := md5.Sum(manifestData)
+	checksum := []byte(hex.EncodeToString(sum[:]))
+	if err = putInventoryObject(ctx, objAPI, cfg.DestinationBucket(), pathJoin(manifestDir, "manifest.checksum"), checksum, "text/plain"); err != nil {
+		return "", err
+	}
+	return manifest, nil
+}
+
+func putInventoryObject(ctx context.Context, objAPI ObjectLayer, bucket, object string, data []byte, contentType string) error {
+	hr, err := hash.NewReader(bytes.NewReader(data), int64(len(data)), "", "", int64(len(data)))
+	if err != nil {
+		return err
+	}
+	opts := ObjectOptions{
+		Versioned:        globalBucketVersioningSys.PrefixEnabled(bucket, object),
+		VersionSuspended: globalBucketVersioningSys.PrefixSuspended(bucket, object),
+		UserDefined: map[string]string{
+			xhttp.ContentType: contentType,
+		},
+	}
+	_, err = objAPI.PutObject(ctx, bucket, object, NewPutObjReader(hr), opts)
+	return err
+}
+
+// newInventoryRecord returns the inventory record of an object version.
+func newInventoryRecord(bucket string, oi ObjectInfo) inventory.Record {
+	record := inventory.Record{
+		Bucket:            bucket,
+		Key:               oi.Name,
+		VersionID:         oi.VersionID,
+		IsLatest:          oi.IsLatest,
+		IsDeleteMarker:    oi.DeleteMarker,
+		LastModifiedDate:  oi.ModTime,
+		StorageClass:      oi.StorageClass,
+		ETag:              oi.ETag,
+		ReplicationStatus: oi.ReplicationStatus.String(),
+	}
+	if record.VersionID == "" {
+		record.VersionID = nullVersionID
+	}
+	if oi.DeleteMarker {
+		return record
+	}
+
+	record.Size = oi.Size
+	if size, err := oi.GetActualSize(); err == nil {
+		record.Size = size
+	}
+	if record.StorageClass == "" {
+		record.StorageClass = "STANDARD"
+	}
+	record.IsMultipartUploaded = oi.isMultipart()
+
+	record.EncryptionStatus = "NOT-SSE"
+	if kind, ok := crypto.IsEncrypted(oi.UserDefined); ok {
+		switch kind {
+		case crypto.S3:
+			record.EncryptionStatus = "SSE-S3"
+		case crypto.S3KMS:
+			record.EncryptionStatus = "SSE-KMS"
+		case crypto.SSEC:
+			record.EncryptionStatus = "SSE-C"
+		}
+	}
+
+	retention := objectlock.GetObjectRetentionMeta(oi.UserDefined)
+	if retention.Mode.Valid() {
+		record.ObjectLockMode = string(retention.Mode)
+		record.ObjectLockRetainUntilDate = retention.RetainUntilDate.Time
+	}
+	if legalHold := objectlock.GetObjectLegalHoldMeta(oi.UserDefined); legalHold.Status.Valid() {
+		record.ObjectLockLegalHoldStatus = string(legalHold.Status)
+	} else {
+		record.ObjectLockLegalHoldStatus = string(objectlock.LegalHoldOff)
+	}
+
+	for algorithm := range oi.decryptChecksums() {
+		record.ChecksumAlgorithm = algorithm
+	}
+	return record
+}
@@ -35,6 +35,7 @@ import (
 	sse "github.com/infobsmi/b33s/internal/bucket/encryption"
 	"github.com/infobsmi/b33s/internal/bucket/lifecycle"
 	"github.com/infobsmi/b33s/internal/event"
+	"github.com/infobsmi/b33s/internal/hash"
 	xhttp "github.com/infobsmi/b33s/internal/http"
 	"github.com/infobsmi/b33s/internal/logger"
 	"github.com/infobsmi/b33s/internal/s3select"
@@ -280,13 +281,13 @@ var errInvalidStorageClass = errors.New("invalid storage class")
 
 func validateTransitionTier(lc *lifecycle.Lifecycle) error {
 	for _, rule := range lc.Rules {
-		if rule.Transition.StorageClass != "" {
-			if valid := globalTierConfigMgr.IsTierValid(rule.Transition.StorageClass); !valid {
+		if sc := rule.Transition.StorageClass; sc != "" {
+			if valid := globalTierConfigMgr.IsTierValid(sc) || globalStorageClass.IsCustom(sc); !valid {
 				return errInvalidStorageClass
 			}
 		}
-		if rule.NoncurrentVersionTransition.StorageClass != "" {
-			if valid := globalTierConfigMgr.IsTierValid(rule.NoncurrentVersionTransition.StorageClass); !valid {
+		if sc := rule.NoncurrentVersionTransition.StorageClass; sc != "" {
+			if valid := globalTierConfigMgr.IsTierValid(sc) || globalStorageClass.IsCustom(sc); !valid {
 				return errInvalidStorageClass
 			}
 		}
@@ -352,30 +353,31 @@ func expireTransitionedObject(ctx context.Context, objectAPI ObjectLayer, oi *Ob
 		// Send audit for the lifecycle delete operation
 		auditLogLifecycle(ctx, *oi, ILMExpiry)
 
-		eventName := event.ObjectRemovedDelete
-		if lcOpts.DeleteMarker {
-			eventName = event.ObjectRemovedDeleteMarkerCreated
-		}
 		objInfo := ObjectInfo{
 			Name:         oi.Name,
 			VersionID:    lcOpts.VersionID,
 			DeleteMarker: lcOpts.DeleteMarker,
 		}
 		// Notify object deleted event.
-		sendEvent(eventArgs{
-			EventName:  eventName,
-			BucketName: oi.Bucket,
-			Object:     objInfo,
-			Host:       "Internal: [ILM-EXPIRY]",
-		})
+		sendLifecycleExpiryEvents(oi.Bucket, objInfo)
 
 	case expireRestoredObj:
 		// delete locally restored copy of object or object version
 		// from the source, while leaving metadata behind. The data on
 		// transitioned tier lies untouched and still accessible
 		opts.Transition.ExpireRestored = true
-		_, err := objectAPI.DeleteObject(ctx, oi.Bucket, oi.Name, opts)
-		return err
+		if _, err := objectAPI.DeleteObject(ctx, oi.Bucket, oi.Name, opts); err != nil {
+			return err
+		}
+
+		// Notify restored object expired event.
+		sendEvent(eventArgs{
+			EventName:  event.ObjectRestoreDelete,
+			BucketName: oi.Bucket,
+			Object:     *oi,
+			Host:       "Internal: [ILM-EXPIRY]",
+		})
+		return nil
 	default:
 		return fmt.Errorf("Unknown expire action %v", action)
 	}
@@ -383,6 +385,24 @@ func expireTransitionedObject(ctx context.Context, objectAPI ObjectLayer, oi *Ob
 	return nil
 }
 
+// sendLifecycleExpiryEvents notifies the removal of an object (version) by a
+// lifecycle expiry rule, both as s3:ObjectRemoved event for existing
+// consumers and as s3:LifecycleExpiration event.
+func sendLifecycleExpiryEvents(bucket string, objInfo ObjectInfo) {
+	eventNames := []event.Name{event.ObjectRemovedDelete, event.ObjectLifecycleExpirationDelete}
+	if objInfo.DeleteMarker {
+		eventNames = []event.Name{event.ObjectRemovedDeleteMarkerCreated, event.ObjectLifecycleExpirationDeleteMarkerCreated}
+	}
+	for _, eventName := range eventNames {
+		sendEvent(eventArgs{
+			EventName:  eventName,
+			BucketName: bucket,
+			Object:     objInfo,
+			Host:       "Internal: [ILM-EXPIRY]",
+		})
+	}
+}
+
 // generate an object name for transitioned object
 func genTransitionObjName(bucket string) (string, error) {
 	u, err := uuid.NewRandom()
@@ -399,6 +419,9 @@ func genTransitionObjName(bucket string) (string, error) {
 // is moved to the transition tier. Note that in the case of encrypted objects, entire encrypted stream is moved
 // to the transition tier without decrypting or re-encrypting.
 func transitionObject(ctx context.Context, objectAPI ObjectLayer, oi ObjectInfo, tier string) error {
+	if globalStorageClass.IsCustom(tier) {
+		return transitionObjectToStorageClass(ctx, objectAPI, oi, tier)
+	}
 	opts := ObjectOptions{
 		Transition: TransitionOptions{
 			Status: lifecycle.TransitionPending,
@@ -413,6 +436,117 @@ func transitionObject(ctx context.Context, objectAPI ObjectLayer, oi ObjectInfo,
 	return objectAPI.TransitionObject(ctx, oi.Bucket, oi.Name, opts)
 }
 
+// transitionObjectToStorageClass rewrites an object version with the custom
+// storage class sc of this cluster, i.e. with the parity, inline threshold
+// and pool affinity of sc. Version ID, modification time and ETag of the
+// version are preserved, the rewrite is skipped if the version was modified
+// after oi was read. An object on a pool outside of the affinity of sc is
+// moved with all its versions to a pool of sc.
+func transitionObjectToStorageClass(ctx context.Context, objectAPI ObjectLayer, oi ObjectInfo, sc string) (err error) {
+	gr, err := objectAPI.GetObjectNInfo(ctx, oi.Bucket, oi.Name, nil, http.Header{}, noLock, ObjectOptions{
+		VersionID:    oi.VersionID,
+		NoDecryption: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	objInfo := gr.ObjInfo
+	modified := func(o ObjectInfo) bool {
+		return o.ETag != oi.ETag || !o.ModTime.Equal(oi.ModTime)
+	}
+	if modified(objInfo) {
+		return nil
+	}
+
+	defer func() {
+		eventName := event.ObjectTransitionComplete
+		if err != nil {
+			eventName = event.ObjectTransitionFailed
+		}
+		sendEvent(eventArgs{
+			EventName:  eventName,
+			BucketName: oi.Bucket,
+			Object:     objInfo,
+			Host:       "Internal: [ILM-Transition]",
+		})
+	}()
+
+	if z, ok := objectAPI.(*erasureServerPools); ok {
+		var moving bool
+		if moving, err = z.moveObjectToSCPool(ctx, oi, sc); moving || err != nil {
+			return err
+		}
+	}
+
+	actualSize, err := objInfo.GetActualSize()
+	if err != nil {
+		return err
+	}
+
+	userDefined := cloneMSS(objInfo.UserDefined)
+	userDefined[xhttp.AmzStorageClass] = sc
+	opts := ObjectOptions{
+		VersionID:        objInfo.VersionID,
+		Versioned:        globalBucketVersioningSys.PrefixEnabled(oi.Bucket, oi.Name),
+		VersionSuspended: globalBucketVersioningSys.PrefixSuspended(oi.Bucket, oi.Name),
+		MTime:            objInfo.ModTime,
+		UserDefined:      userDefined,
+		CheckPrecondFn:   modified,
+	}
+
+	if objInfo.isMultipart() {
+		res, err := objectAPI.NewMultipartUpload(ctx, oi.Bucket, oi.Name, opts)
+		if err != nil {
+			return err
+		}
+		defer objectAPI.AbortMultipartUpload(ctx, oi.Bucket, oi.Name, res.UploadID, ObjectOptions{})
+		parts := make([]CompletePart, len(objInfo.Parts))
+		for i, part := range objInfo.Parts {
+			hr, err := hash.NewReader(gr, part.Size, "", "", part.ActualSize)
+			if err != nil {
+				return err
+			}
+			pi, err := objectAPI.PutObjectPart(ctx, oi.Bucket, oi.Name, res.UploadID,
+				part.Number,
+				NewPutObjReader(hr),
+				ObjectOptions{
+					PreserveETag: part.ETag, // Preserve original ETag to ensure same metadata.
+					IndexCB: func() []byte {
+						return part.Index // Preserve part Index to ensure decompression works.
+					},
+				})
+			if err != nil {
+				return err
+			}
+			parts[i] = CompletePart{
+				ETag:           pi.ETag,
+				PartNumber:     pi.PartNumber,
+				ChecksumCRC32:  pi.ChecksumCRC32,
+				ChecksumCRC32C: pi.ChecksumCRC32C,
+				ChecksumSHA256: pi.ChecksumSHA256,
+				ChecksumSHA1:   pi.ChecksumSHA1,
+			}
+		}
+		_, err = objectAPI.CompleteMultipartUpload(ctx, oi.Bucket, oi.Name, res.UploadID, parts, ObjectOptions{
+			MTime: objInfo.ModTime,
+		})
+		return err
+	}
+
+	hr, err := hash.NewReader(gr, objInfo.Size, "", "", actualSize)
+	if err != nil {
+		return err
+	}
+	opts.PreserveETag = objInfo.ETag // Preserve original ETag to ensure same metadata.
+	opts.IndexCB = func() []byte {
+		return objInfo.Parts[0].Index // Preserve part Index to ensure decompression works.
+	}
+	_, err = objectAPI.PutObject(ctx, oi.Bucket, oi.Name, NewPutObjReader(hr), opts)
+	return err
+}
+
 type auditTierOp struct {
 	Tier             string `json:"tier"`
 	TimeToResponseNS int64  `json:"timeToResponseNS"`
@@ -812,5 +946,6 @@ func (oi ObjectInfo) ToLifecycleOpts() lifecycle.ObjectOpts {
 		RestoreOngoing:   oi.RestoreOngoing,
 		RestoreExpires:   oi.RestoreExpires,
 		TransitionStatus: oi.TransitionedObject.Status,
+		StorageClass:     oi.StorageClass,
 	}
 }
@@ -0,0 +1,184 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/infobsmi/b33s/internal/bucket/logging"
+	"github.com/infobsmi/b33s/internal/logger"
+	"github.com/minio/pkg/bucket/policy"
+	iampolicy "github.com/minio/pkg/iam/policy"
+)
+
+const (
+	// Bucket logging configuration file name.
+	bucketLoggingConfig = "logging.xml"
+
+	// S3 actions guarding the bucket logging configuration, the policy
+	// package has no logging actions so the bucket policy actions are
+	// re-purposed.
+	getBucketLoggingAction = policy.GetBucketPolicyAction
+	putBucketLoggingAction = policy.PutBucketPolicyAction
+)
+
+// PutBucketLoggingHandler - Enables or disables server access logging
+// for a bucket, an empty BucketLoggingStatus disables logging.
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketLogging.html
+func (api objectAPIHandlers) PutBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "PutBucketLogging")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, putBucketLoggingAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL)
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket, BucketOptions{}); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	loggingConfig, err := logging.ParseConfig(io.LimitReader(r.Body, maxBucketLoggingConfigSize))
+	if err != nil {
+		apiErr := APIError{
+			Code:           "MalformedXML",
+			Description:    fmt.Sprintf("%s (%s)", errorCodes[ErrMalformedXML].Description, err),
+			HTTPStatusCode: errorCodes[ErrMalformedXML].HTTPStatusCode,
+		}
+		writeErrorResponse(ctx, w, apiErr, r.URL)
+		return
+	}
+
+	var (
+		configData []byte
+		updatedAt  time.Time
+	)
+	if loggingConfig.Enabled() {
+		target := loggingConfig.LoggingEnabled.TargetBucket
+		if isMinioMetaBucketName(target) || isMinioReservedBucket(target) {
+			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidTargetBucketForLogging), r.URL)
+			return
+		}
+		if _, err = objAPI.GetBucketInfo(ctx, target, BucketOptions{}); err != nil {
+			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidTargetBucketForLogging), r.URL)
+			return
+		}
+
+		// Access logs are delivered on behalf of the requester, who
+		// must be allowed to write them into the target bucket.
+		if s3Error := isPutActionAllowed(ctx, getRequestAuthType(r), target, loggingConfig.LoggingEnabled.TargetPrefix, r, iampolicy.PutObjectAction); s3Error != ErrNone {
+			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidTargetBucketForLogging), r.URL)
+			return
+		}
+
+		configData, err = xml.Marshal(loggingConfig)
+		if err != nil {
+			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+			return
+		}
+
+		// Store the bucket logging configuration in the object layer
+		updatedAt, err = globalBucketMetadataSys.Update(ctx, bucket, bucketLoggingConfig, configData)
+	} else {
+		// Delete the bucket logging configuration from the object layer
+		updatedAt, err = globalBucketMetadataSys.Delete(ctx, bucket, bucketLoggingConfig)
+	}
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	// Call site replication hook.
+	item, err := newSRBucketMetaConfig(srBucketMetaTypeLoggingConfig, bucket, configData, updatedAt)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+	if err = globalSiteReplicationSys.BucketMetaHook(ctx, item); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketLoggingHandler - Returns bucket logging status, buckets
+// without logging enabled return an empty BucketLoggingStatus.
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetBucketLogging.html
+func (api objectAPIHandlers) GetBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetBucketLogging")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, getBucketLoggingAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL)
+		return
+	}
+
+	// Check if bucket exists
+	var err error
+	if _, err = objAPI.GetBucketInfo(ctx, bucket, BucketOptions{}); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	config, err := globalBucketLoggingSys.Get(bucket)
+	if err != nil {
+		if !errors.Is(err, BucketLoggingNotFound{Bucket: bucket}) {
+			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+			return
+		}
+		config = &logging.Config{XMLNS: "http://s3.amazonaws.com/doc/2006-03-01/"}
+	}
+
+	configData, err := xml.Marshal(config)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	// Write bucket logging status to client
+	writeSuccessResponseXML(w, configData)
+}
@@ -0,0 +1,307 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/infobsmi/b33s/internal/bucket/logging"
+	"github.com/infobsmi/b33s/internal/hash"
+	xhttp "github.com/infobsmi/b33s/internal/http"
+	"github.com/infobsmi/b33s/internal/logger"
+	"github.com/infobsmi/b33s/internal/logger/message/audit"
+)
+
+const (
+	// Interval at which buffered access log records are delivered.
+	accessLogFlushInterval = 5 * time.Minute
+
+	// Buffered access log records are delivered early once they
+	// reach this size.
+	accessLogMaxBufferSize = 8 * humanize.MiByte
+
+	// Records which could not be delivered are kept for the next
+	// flush as long as the buffered records stay below this size.
+	accessLogMaxRetainedSize = 4 * accessLogMaxBufferSize
+
+	// Time allowed to deliver the buffered records on shutdown.
+	accessLogShutdownTimeout = 10 * time.Second
+
+	// Time format of the delivered log object names.
+	accessLogObjectTimeFormat = "2006-01-02-15-04-05"
+)
+
+// accessLogTarget - where the access logs of a bucket are delivered.
+type accessLogTarget struct {
+	bucket string
+	prefix string
+}
+
+// BucketLoggingSys - buffers the server access log records of buckets
+// with logging enabled and periodically delivers them as objects into
+// the configured target buckets.
+type BucketLoggingSys struct {
+	sync.Mutex
+	buffers  map[accessLogTarget]*bytes.Buffer
+	size     int
+	retained int // part of size which failed to deliver before
+	flushCh  chan struct{}
+}
+
+// NewBucketLoggingSys returns initialized BucketLoggingSys
+func NewBucketLoggingSys() *BucketLoggingSys {
+	return &BucketLoggingSys{
+		buffers: make(map[accessLogTarget]*bytes.Buffer),
+		flushCh: make(chan struct{}, 1),
+	}
+}
+
+// Init starts collecting access log records and delivering them.
+func (sys *BucketLoggingSys) Init(ctx context.Context, objAPI ObjectLayer) {
+	logger.SetAuditHook(sys)
+	go sys.run(ctx, objAPI)
+}
+
+// Get - Get bucket logging configuration.
+func (sys *BucketLoggingSys) Get(bucket string) (*logging.Config, error) {
+	config, _, err := globalBucketMetadataSys.GetLoggingConfig(bucket)
+	return config, err
+}
+
+// Enabled returns true if access logging is enabled on bucket, only
+// bucket metadata already loaded in memory is looked at.
+func (sys *BucketLoggingSys) Enabled(bucket string) bool {
+	if isMinioMetaBucketName(bucket) {
+		return false
+	}
+	meta, err := globalBucketMetadataSys.Get(bucket)
+	if err != nil {
+		return false
+	}
+	return meta.loggingConfig.Enabled()
+}
+
+// Log buffers the access log record of an API call.
+func (sys *BucketLoggingSys) Log(entry audit.Entry, r *http.Request, reqInfo *logger.ReqInfo) {
+	meta, err := globalBucketMetadataSys.Get(reqInfo.BucketName)
+	if err != nil || !meta.loggingConfig.Enabled() {
+		return
+	}
+	target := accessLogTarget{
+		bucket: meta.loggingConfig.LoggingEnabled.TargetBucket,
+		prefix: meta.loggingConfig.LoggingEnabled.TargetPrefix,
+	}
+	line := newAccessLogRecord(entry, r, reqInfo).String() + "\n"
+
+	sys.Lock()
+	buf, ok := sys.buffers[target]
+	if !ok {
+		buf = &bytes.Buffer{}
+		sys.buffers[target] = buf
+	}
+	buf.WriteString(line)
+	sys.size += len(line)
+	full := sys.size-sys.retained >= accessLogMaxBufferSize
+	sys.Unlock()
+
+	if full {
+		select {
+		case sys.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (sys *BucketLoggingSys) run(ctx context.Context, objAPI ObjectLayer) {
+	t := time.NewTicker(accessLogFlushInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Deliver what is still buffered before returning.
+			sctx, cancel := context.WithTimeout(context.Background(), accessLogShutdownTimeout)
+			sys.flush(sctx, objAPI)
+			cancel()
+			return
+		case <-t.C:
+		case <-sys.flushCh:
+		}
+		sys.flush(ctx, objAPI)
+	}
+}
+
+// flush delivers all buffered access log records, each target gets
+// a new object named TargetPrefixYYYY-mm-DD-HH-MM-SS-UniqueString.
+// Records which could not be delivered are retried with the next flush.
+func (sys *BucketLoggingSys) flush(ctx context.Context, objAPI ObjectLayer) {
+	sys.Lock()
+	buffers := sys.buffers
+	sys.buffers = make(map[accessLogTarget]*bytes.Buffer, len(buffers))
+	sys.size = 0
+	sys.retained = 0
+	sys.Unlock()
+
+	now := UTCNow()
+	for target, buf := range buffers {
+		object := target.prefix + now.Format(accessLogObjectTimeFormat) + "-" +
+			strings.ToUpper(strings.ReplaceAll(mustGetUUID(), "-", ""))[:16]
+		if err := deliverAccessLogs(ctx, objAPI, target.bucket, object, buf.Bytes()); err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to deliver access logs to %s/%s: %w", target.bucket, object, err))
+			if !sys.retain(target, buf) {
+				logger.LogIf(ctx, fmt.Errorf("Dropping %d bytes of access logs for %s, too many undelivered records", buf.Len(), target.bucket))
+			}
+		}
+	}
+}
+
+// retain puts the records of a failed delivery back in front of the
+// records buffered since, returns false if they would exceed
+// accessLogMaxRetainedSize.
+func (sys *BucketLoggingSys) retain(target accessLogTarget, buf *bytes.Buffer) bool {
+	sys.Lock()
+	defer sys.Unlock()
+
+	n := buf.Len()
+	if sys.size+n > accessLogMaxRetainedSize {
+		return false
+	}
+	if newer, ok := sys.buffers[target]; ok {
+		buf.Write(newer.Bytes())
+	}
+	sys.buffers[target] = buf
+	sys.size += n
+	sys.retained += n
+	return true
+}
+
+func deliverAccessLogs(ctx context.Context, objAPI ObjectLayer, bucket, object string, data []byte) error {
+	hr, err := hash.NewReader(bytes.NewReader(data), int64(len(data)), "", "", int64(len(data)))
+	if err != nil {
+		return err
+	}
+	opts := ObjectOptions{
+		Versioned:        globalBucketVersioningSys.PrefixEnabled(bucket, object),
+		VersionSuspended: globalBucketVersioningSys.PrefixSuspended(bucket, object),
+		UserDefined: map[string]string{
+			xhttp.ContentType: "text/plain",
+		},
+	}
+	_, err = objAPI.PutObject(ctx, bucket, object, NewPutObjReader(hr), opts)
+	return err
+}
+
+// newAccessLogRecord builds the access log record of an API call from
+// its audit entry.
+func newAccessLogRecord(entry audit.Entry, r *http.Request, reqInfo *logger.ReqInfo) logging.Record {
+	record := logging.Record{
+		Bucket:         reqInfo.BucketName,
+		Time:           entry.Time,
+		RemoteIP:       entry.RemoteHost,
+		Requester:      reqInfo.Cred.AccessKey,
+		RequestID:      entry.RequestID,
+		Operation:      logging.Operation(r.Method, r.URL.Query(), reqInfo.ObjectName),
+		Key:            reqInfo.ObjectName,
+		RequestURI:     r.Method + " " + r.URL.RequestURI() + " " + r.Proto,
+		HTTPStatus:     entry.API.StatusCode,
+		ErrorCode:      entry.Error,
+		BytesSent:      entry.API.OutputBytes,
+		TotalTime:      parseAuditDuration(entry.API.TimeToResponse),
+		TurnAroundTime: parseAuditDuration(entry.API.TimeToFirstByte),
+		Referer:        r.Referer(),
+		UserAgent:      entry.UserAgent,
+		VersionID:      reqInfo.VersionID,
+		HostHeader:     r.Host,
+	}
+	if reqInfo.ObjectName != "" {
+		record.ObjectSize = accessLogObjectSize(entry, r)
+	}
+
+	switch getRequestAuthType(r) {
+	case authTypeSigned, authTypeStreamingSigned:
+		record.SignatureVersion, record.AuthType = "SigV4", "AuthHeader"
+	case authTypePresigned:
+		record.SignatureVersion, record.AuthType = "SigV4", "QueryString"
+	case authTypeSignedV2:
+		record.SignatureVersion, record.AuthType = "SigV2", "AuthHeader"
+	case authTypePresignedV2:
+		record.SignatureVersion, record.AuthType = "SigV2", "QueryString"
+	}
+
+	if r.TLS != nil {
+		record.CipherSuite = tls.CipherSuiteName(r.TLS.CipherSuite)
+		record.TLSVersion = tlsVersionName(r.TLS.Version)
+	}
+	return record
+}
+
+// accessLogObjectSize returns the total size of the object an API call
+// was made on, if known.
+func accessLogObjectSize(entry audit.Entry, r *http.Request) int64 {
+	switch r.Method {
+	case http.MethodPut, http.MethodPost:
+		return entry.API.InputBytes
+	}
+	// Range requests report the total object size in Content-Range.
+	if cr := entry.RespHeader[xhttp.ContentRange]; cr != "" {
+		if i := strings.LastIndex(cr, "/"); i >= 0 {
+			if size, err := strconv.ParseInt(cr[i+1:], 10, 64); err == nil {
+				return size
+			}
+		}
+	}
+	size, err := strconv.ParseInt(entry.RespHeader[xhttp.ContentLength], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return size
+}
+
+func parseAuditDuration(s string) time.Duration {
+	if s == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLSv1"
+	case tls.VersionTLS11:
+		return "TLSv1.1"
+	case tls.VersionTLS12:
+		return "TLSv1.2"
+	case tls.VersionTLS13:
+		return "TLSv1.3"
+	}
+	return ""
+}
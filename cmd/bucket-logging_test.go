@@ -0,0 +1,75 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestBucketLoggingFlushRetry(t *testing.T) {
+	ExecObjectLayerTest(t, testBucketLoggingFlushRetry)
+}
+
+func testBucketLoggingFlushRetry(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	ctx := context.Background()
+	target := accessLogTarget{bucket: "access-logs", prefix: "logs/"}
+
+	sys := NewBucketLoggingSys()
+	sys.buffers[target] = bytes.NewBufferString("record-1\n")
+	sys.size = len("record-1\n")
+
+	// Target bucket does not exist, records are kept for the next flush.
+	sys.flush(ctx, obj)
+	if buf, ok := sys.buffers[target]; !ok || buf.String() != "record-1\n" {
+		t.Fatalf("%s: undelivered records were not retained", instanceType)
+	}
+
+	// Records buffered meanwhile are delivered after the retained ones.
+	sys.buffers[target].WriteString("record-2\n")
+	if err := obj.MakeBucketWithLocation(ctx, target.bucket, MakeBucketOptions{}); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	sys.flush(ctx, obj)
+	if len(sys.buffers) != 0 || sys.size != 0 || sys.retained != 0 {
+		t.Fatalf("%s: delivered records were not released", instanceType)
+	}
+
+	result, err := obj.ListObjects(ctx, target.bucket, target.prefix, "", "", 10)
+	if err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if len(result.Objects) != 1 {
+		t.Fatalf("%s: expected 1 log object, got %d", instanceType, len(result.Objects))
+	}
+	var data bytes.Buffer
+	if err = GetObject(ctx, obj, target.bucket, result.Objects[0].Name, 0, result.Objects[0].Size, &data, "", ObjectOptions{}); err != nil {
+		t.Fatalf("%s: %v", instanceType, err)
+	}
+	if data.String() != "record-1\nrecord-2\n" {
+		t.Fatalf("%s: unexpected log object content %q", instanceType, data.String())
+	}
+
+	// Undelivered records beyond the retained size limit are dropped.
+	sys.buffers[accessLogTarget{bucket: "missing-bucket"}] = bytes.NewBuffer(make([]byte, accessLogMaxRetainedSize+1))
+	sys.flush(ctx, obj)
+	if len(sys.buffers) != 0 {
+		t.Fatalf("%s: records beyond the size limit were retained", instanceType)
+	}
+}
@@ -26,11 +26,16 @@ import (
 
 	"github.com/b33s/madmin-go/v2"
 	"github.com/infobsmi/b33s-go/v7/pkg/tags"
+	"github.com/infobsmi/b33s/internal/bucket/cors"
 	bucketsse "github.com/infobsmi/b33s/internal/bucket/encryption"
+	"github.com/infobsmi/b33s/internal/bucket/inventory"
 	"github.com/infobsmi/b33s/internal/bucket/lifecycle"
+	"github.com/infobsmi/b33s/internal/bucket/logging"
 	objectlock "github.com/infobsmi/b33s/internal/bucket/object/lock"
+	"github.com/infobsmi/b33s/internal/bucket/placement"
 	"github.com/infobsmi/b33s/internal/bucket/replication"
 	"github.com/infobsmi/b33s/internal/bucket/versioning"
+	"github.com/infobsmi/b33s/internal/bucket/website"
 	"github.com/infobsmi/b33s/internal/event"
 	"github.com/infobsmi/b33s/internal/kms"
 	"github.com/infobsmi/b33s/internal/logger"
@@ -119,6 +124,21 @@ func (sys *BucketMetadataSys) updateAndParse(ctx context.Context, bucket string,
 	case bucketReplicationConfig:
 		meta.ReplicationConfigXML = configData
 		meta.ReplicationConfigUpdatedAt = updatedAt
+	case bucketCorsConfig:
+		meta.CorsConfigXML = configData
+		meta.CorsConfigUpdatedAt = updatedAt
+	case bucketWebsiteConfig:
+		meta.WebsiteConfigXML = configData
+		meta.WebsiteConfigUpdatedAt = updatedAt
+	case bucketLoggingConfig:
+		meta.LoggingConfigXML = configData
+		meta.LoggingConfigUpdatedAt = updatedAt
+	case bucketInventoryConfig:
+		meta.InventoryConfigXML = configData
+		meta.InventoryConfigUpdatedAt = updatedAt
+	case bucketPlacementConfigFile:
+		meta.PlacementConfigJSON = configData
+		meta.PlacementConfigUpdatedAt = updatedAt
 	case bucketTargetsFile:
 		meta.BucketTargetsConfigJSON, meta.BucketTargetsConfigMetaJSON, err = encryptBucketMetadata(ctx, meta.Name, configData, kms.Context{
 			bucket:            meta.Name,
@@ -266,6 +286,70 @@ func (sys *BucketMetadataSys) GetSSEConfig(bucket string) (*bucketsse.BucketSSEC
 	return meta.sseConfig, meta.EncryptionConfigUpdatedAt, nil
 }
 
+// GetCorsConfig returns configured bucket CORS config
+// The returned object may not be modified.
+func (sys *BucketMetadataSys) GetCorsConfig(bucket string) (*cors.Config, time.Time, error) {
+	meta, err := sys.GetConfig(GlobalContext, bucket)
+	if err != nil {
+		if errors.Is(err, errConfigNotFound) {
+			return nil, time.Time{}, BucketCorsNotFound{Bucket: bucket}
+		}
+		return nil, time.Time{}, err
+	}
+	if meta.corsConfig == nil {
+		return nil, time.Time{}, BucketCorsNotFound{Bucket: bucket}
+	}
+	return meta.corsConfig, meta.CorsConfigUpdatedAt, nil
+}
+
+// GetWebsiteConfig returns configured bucket website config
+// The returned object may not be modified.
+func (sys *BucketMetadataSys) GetWebsiteConfig(bucket string) (*website.Config, time.Time, error) {
+	meta, err := sys.GetConfig(GlobalContext, bucket)
+	if err != nil {
+		if errors.Is(err, errConfigNotFound) {
+			return nil, time.Time{}, BucketWebsiteNotFound{Bucket: bucket}
+		}
+		return nil, time.Time{}, err
+	}
+	if meta.websiteConfig == nil {
+		return nil, time.Time{}, BucketWebsiteNotFound{Bucket: bucket}
+	}
+	return meta.websiteConfig, meta.WebsiteConfigUpdatedAt, nil
+}
+
+// GetLoggingConfig returns configured bucket logging config
+// The returned object may not be modified.
+func (sys *BucketMetadataSys) GetLoggingConfig(bucket string) (*logging.Config, time.Time, error) {
+	meta, err := sys.GetConfig(GlobalContext, bucket)
+	if err != nil {
+		if errors.Is(err, errConfigNotFound) {
+			return nil, time.Time{}, BucketLoggingNotFound{Bucket: bucket}
+		}
+		return nil, time.Time{}, err
+	}
+	if meta.loggingConfig == nil {
+		return nil, time.Time{}, BucketLoggingNotFound{Bucket: bucket}
+	}
+	return meta.loggingConfig, meta.LoggingConfigUpdatedAt, nil
+}
+
+// GetInventoryConfigs returns all configured bucket inventory configs
+// The returned object may not be modified.
+func (sys *BucketMetadataSys) GetInventoryConfigs(bucket string) (*inventory.Configs, time.Time, error) {
+	meta, err := sys.GetConfig(GlobalContext, bucket)
+	if err != nil {
+		if errors.Is(err, errConfigNotFound) {
+			return nil, time.Time{}, BucketInventoryNotFound{Bucket: bucket}
+		}
+		return nil, time.Time{}, err
+	}
+	if meta.inventoryConfigs == nil || len(meta.inventoryConfigs.Configs) == 0 {
+		return nil, time.Time{}, BucketInventoryNotFound{Bucket: bucket}
+	}
+	return meta.inventoryConfigs, meta.InventoryConfigUpdatedAt, nil
+}
+
 // CreatedAt returns the time of creation of bucket
 func (sys *BucketMetadataSys) CreatedAt(bucket string) (time.Time, error) {
 	meta, err := sys.GetConfig(GlobalContext, bucket)
@@ -304,6 +388,22 @@ func (sys *BucketMetadataSys) GetQuotaConfig(ctx context.Context, bucket string)
 	return meta.quotaConfig, meta.QuotaConfigUpdatedAt, nil
 }
 
+// GetPlacementConfig returns configured bucket pool placement policy
+// The returned object may not be modified.
+func (sys *BucketMetadataSys) GetPlacementConfig(ctx context.Context, bucket string) (*placement.Policy, time.Time, error) {
+	meta, err := sys.GetConfig(ctx, bucket)
+	if err != nil {
+		if errors.Is(err, errConfigNotFound) {
+			return nil, time.Time{}, BucketPlacementConfigNotFound{Bucket: bucket}
+		}
+		return nil, time.Time{}, err
+	}
+	if meta.placementConfig == nil {
+		return nil, time.Time{}, BucketPlacementConfigNotFound{Bucket: bucket}
+	}
+	return meta.placementConfig, meta.PlacementConfigUpdatedAt, nil
+}
+
 // GetReplicationConfig returns configured bucket replication config
 // The returned object may not be modified.
 func (sys *BucketMetadataSys) GetReplicationConfig(ctx context.Context, bucket string) (*replication.Config, time.Time, error) {
@@ -31,11 +31,16 @@ import (
 
 	"github.com/b33s/madmin-go/v2"
 	"github.com/infobsmi/b33s-go/v7/pkg/tags"
+	"github.com/infobsmi/b33s/internal/bucket/cors"
 	bucketsse "github.com/infobsmi/b33s/internal/bucket/encryption"
+	"github.com/infobsmi/b33s/internal/bucket/inventory"
 	"github.com/infobsmi/b33s/internal/bucket/lifecycle"
+	"github.com/infobsmi/b33s/internal/bucket/logging"
 	objectlock "github.com/infobsmi/b33s/internal/bucket/object/lock"
+	"github.com/infobsmi/b33s/internal/bucket/placement"
 	"github.com/infobsmi/b33s/internal/bucket/replication"
 	"github.com/infobsmi/b33s/internal/bucket/versioning"
+	"github.com/infobsmi/b33s/internal/bucket/website"
 	"github.com/infobsmi/b33s/internal/crypto"
 	"github.com/infobsmi/b33s/internal/event"
 	"github.com/infobsmi/b33s/internal/fips"
@@ -81,6 +86,11 @@ type BucketMetadata struct {
 	ReplicationConfigXML        []byte
 	BucketTargetsConfigJSON     []byte
 	BucketTargetsConfigMetaJSON []byte
+	CorsConfigXML               []byte
+	WebsiteConfigXML            []byte
+	LoggingConfigXML            []byte
+	InventoryConfigXML          []byte
+	PlacementConfigJSON         []byte
 	PolicyConfigUpdatedAt       time.Time
 	ObjectLockConfigUpdatedAt   time.Time
 	EncryptionConfigUpdatedAt   time.Time
@@ -88,6 +98,11 @@ type BucketMetadata struct {
 	QuotaConfigUpdatedAt        time.Time
 	ReplicationConfigUpdatedAt  time.Time
 	VersioningConfigUpdatedAt   time.Time
+	CorsConfigUpdatedAt         time.Time
+	WebsiteConfigUpdatedAt      time.Time
+	LoggingConfigUpdatedAt      time.Time
+	InventoryConfigUpdatedAt    time.Time
+	PlacementConfigUpdatedAt    time.Time
 
 	// Unexported fields. Must be updated atomically.
 	policyConfig           *policy.Policy
@@ -101,6 +116,11 @@ type BucketMetadata struct {
 	replicationConfig      *replication.Config
 	bucketTargetConfig     *madmin.BucketTargets
 	bucketTargetConfigMeta map[string]string
+	corsConfig             *cors.Config
+	websiteConfig          *website.Config
+	loggingConfig          *logging.Config
+	inventoryConfigs       *inventory.Configs
+	placementConfig        *placement.Policy
 }
 
 // newBucketMetadata creates BucketMetadata with the supplied name and Created to Now.
@@ -292,6 +312,51 @@ func (b *BucketMetadata) parseAllConfigs(ctx context.Context, objectAPI ObjectLa
 	} else {
 		b.bucketTargetConfig = &madmin.BucketTargets{}
 	}
+
+	if len(b.CorsConfigXML) != 0 {
+		b.corsConfig, err = cors.ParseConfig(bytes.NewReader(b.CorsConfigXML))
+		if err != nil {
+			return err
+		}
+	} else {
+		b.corsConfig = nil
+	}
+
+	if len(b.WebsiteConfigXML) != 0 {
+		b.websiteConfig, err = website.ParseConfig(bytes.NewReader(b.WebsiteConfigXML))
+		if err != nil {
+			return err
+		}
+	} else {
+		b.websiteConfig = nil
+	}
+
+	if len(b.LoggingConfigXML) != 0 {
+		b.loggingConfig, err = logging.ParseConfig(bytes.NewReader(b.LoggingConfigXML))
+		if err != nil {
+			return err
+		}
+	} else {
+		b.loggingConfig = nil
+	}
+
+	if len(b.InventoryConfigXML) != 0 {
+		b.inventoryConfigs, err = inventory.ParseConfigs(bytes.NewReader(b.InventoryConfigXML))
+		if err != nil {
+			return err
+		}
+	} else {
+		b.inventoryConfigs = nil
+	}
+
+	if len(b.PlacementConfigJSON) != 0 {
+		b.placementConfig, err = placement.ParseConfig(bytes.NewReader(b.PlacementConfigJSON))
+		if err != nil {
+			return err
+		}
+	} else {
+		b.placementConfig = nil
+	}
 	return nil
 }
 
@@ -418,6 +483,26 @@ func (b *BucketMetadata) defaultTimestamps() {
 	if b.VersioningConfigUpdatedAt.IsZero() {
 		b.VersioningConfigUpdatedAt = b.Created
 	}
+
+	if b.CorsConfigUpdatedAt.IsZero() {
+		b.CorsConfigUpdatedAt = b.Created
+	}
+
+	if b.WebsiteConfigUpdatedAt.IsZero() {
+		b.WebsiteConfigUpdatedAt = b.Created
+	}
+
+	if b.LoggingConfigUpdatedAt.IsZero() {
+		b.LoggingConfigUpdatedAt = b.Created
+	}
+
+	if b.InventoryConfigUpdatedAt.IsZero() {
+		b.InventoryConfigUpdatedAt = b.Created
+	}
+
+	if b.PlacementConfigUpdatedAt.IsZero() {
+		b.PlacementConfigUpdatedAt = b.Created
+	}
 }
 
 // Save config to supplied ObjectLayer api.
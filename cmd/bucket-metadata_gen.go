@@ -108,6 +108,36 @@ func (z *BucketMetadata) DecodeMsg(dc *msgp.Reader) (err error) {
 				err = msgp.WrapError(err, "BucketTargetsConfigMetaJSON")
 				return
 			}
+		case "CorsConfigXML":
+			z.CorsConfigXML, err = dc.ReadBytes(z.CorsConfigXML)
+			if err != nil {
+				err = msgp.WrapError(err, "CorsConfigXML")
+				return
+			}
+		case "WebsiteConfigXML":
+			z.WebsiteConfigXML, err = dc.ReadBytes(z.WebsiteConfigXML)
+			if err != nil {
+				err = msgp.WrapError(err, "WebsiteConfigXML")
+				return
+			}
+		case "LoggingConfigXML":
+			z.LoggingConfigXML, err = dc.ReadBytes(z.LoggingConfigXML)
+			if err != nil {
+				err = msgp.WrapError(err, "LoggingConfigXML")
+				return
+			}
+		case "InventoryConfigXML":
+			z.InventoryConfigXML, err = dc.ReadBytes(z.InventoryConfigXML)
+			if err != nil {
+				err = msgp.WrapError(err, "InventoryConfigXML")
+				return
+			}
+		case "PlacementConfigJSON":
+			z.PlacementConfigJSON, err = dc.ReadBytes(z.PlacementConfigJSON)
+			if err != nil {
+				err = msgp.WrapError(err, "PlacementConfigJSON")
+				return
+			}
 		case "PolicyConfigUpdatedAt":
 			z.PolicyConfigUpdatedAt, err = dc.ReadTime()
 			if err != nil {
@@ -150,6 +180,36 @@ func (z *BucketMetadata) DecodeMsg(dc *msgp.Reader) (err error) {
 				err = msgp.WrapError(err, "VersioningConfigUpdatedAt")
 				return
 			}
+		case "CorsConfigUpdatedAt":
+			z.CorsConfigUpdatedAt, err = dc.ReadTime()
+			if err != nil {
+				err = msgp.WrapError(err, "CorsConfigUpdatedAt")
+				return
+			}
+		case "WebsiteConfigUpdatedAt":
+			z.WebsiteConfigUpdatedAt, err = dc.ReadTime()
+			if err != nil {
+				err = msgp.WrapError(err, "WebsiteConfigUpdatedAt")
+				return
+			}
+		case "LoggingConfigUpdatedAt":
+			z.LoggingConfigUpdatedAt, err = dc.ReadTime()
+			if err != nil {
+				err = msgp.WrapError(err, "LoggingConfigUpdatedAt")
+				return
+			}
+		case "InventoryConfigUpdatedAt":
+			z.InventoryConfigUpdatedAt, err = dc.ReadTime()
+			if err != nil {
+				err = msgp.WrapError(err, "InventoryConfigUpdatedAt")
+				return
+			}
+		case "PlacementConfigUpdatedAt":
+			z.PlacementConfigUpdatedAt, err = dc.ReadTime()
+			if err != nil {
+				err = msgp.WrapError(err, "PlacementConfigUpdatedAt")
+				return
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -163,9 +223,9 @@ func (z *BucketMetadata) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z *BucketMetadata) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 21
+	// map header, size 31
 	// write "Name"
-	err = en.Append(0xde, 0x0, 0x15, 0xa4, 0x4e, 0x61, 0x6d, 0x65)
+	err = en.Append(0xde, 0x0, 0x1f, 0xa4, 0x4e, 0x61, 0x6d, 0x65)
 	if err != nil {
 		return
 	}
@@ -304,6 +364,56 @@ func (z *BucketMetadata) EncodeMsg(en *msgp.Writer) (err error) {
 		err = msgp.WrapError(err, "BucketTargetsConfigMetaJSON")
 		return
 	}
+	// write "CorsConfigXML"
+	err = en.Append(0xad, 0x43, 0x6f, 0x72, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x58, 0x4d, 0x4c)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.CorsConfigXML)
+	if err != nil {
+		err = msgp.WrapError(err, "CorsConfigXML")
+		return
+	}
+	// write "WebsiteConfigXML"
+	err = en.Append(0xb0, 0x57, 0x65, 0x62, 0x73, 0x69, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x58, 0x4d, 0x4c)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.WebsiteConfigXML)
+	if err != nil {
+		err = msgp.WrapError(err, "WebsiteConfigXML")
+		return
+	}
+	// write "LoggingConfigXML"
+	err = en.Append(0xb0, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x58, 0x4d, 0x4c)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.LoggingConfigXML)
+	if err != nil {
+		err = msgp.WrapError(err, "LoggingConfigXML")
+		return
+	}
+	// write "InventoryConfigXML"
+	err = en.Append(0xb2, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x58, 0x4d, 0x4c)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.InventoryConfigXML)
+	if err != nil {
+		err = msgp.WrapError(err, "InventoryConfigXML")
+		return
+	}
+	// write "PlacementConfigJSON"
+	err = en.Append(0xb3, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4a, 0x53, 0x4f, 0x4e)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.PlacementConfigJSON)
+	if err != nil {
+		err = msgp.WrapError(err, "PlacementConfigJSON")
+		return
+	}
 	// write "PolicyConfigUpdatedAt"
 	err = en.Append(0xb5, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
 	if err != nil {
@@ -374,15 +484,65 @@ func (z *BucketMetadata) EncodeMsg(en *msgp.Writer) (err error) {
 		err = msgp.WrapError(err, "VersioningConfigUpdatedAt")
 		return
 	}
+	// write "CorsConfigUpdatedAt"
+	err = en.Append(0xb3, 0x43, 0x6f, 0x72, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteTime(z.CorsConfigUpdatedAt)
+	if err != nil {
+		err = msgp.WrapError(err, "CorsConfigUpdatedAt")
+		return
+	}
+	// write "WebsiteConfigUpdatedAt"
+	err = en.Append(0xb6, 0x57, 0x65, 0x62, 0x73, 0x69, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteTime(z.WebsiteConfigUpdatedAt)
+	if err != nil {
+		err = msgp.WrapError(err, "WebsiteConfigUpdatedAt")
+		return
+	}
+	// write "LoggingConfigUpdatedAt"
+	err = en.Append(0xb6, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteTime(z.LoggingConfigUpdatedAt)
+	if err != nil {
+		err = msgp.WrapError(err, "LoggingConfigUpdatedAt")
+		return
+	}
+	// write "InventoryConfigUpdatedAt"
+	err = en.Append(0xb8, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteTime(z.InventoryConfigUpdatedAt)
+	if err != nil {
+		err = msgp.WrapError(err, "InventoryConfigUpdatedAt")
+		return
+	}
+	// write "PlacementConfigUpdatedAt"
+	err = en.Append(0xb8, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteTime(z.PlacementConfigUpdatedAt)
+	if err != nil {
+		err = msgp.WrapError(err, "PlacementConfigUpdatedAt")
+		return
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
 func (z *BucketMetadata) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 21
+	// map header, size 31
 	// string "Name"
-	o = append(o, 0xde, 0x0, 0x15, 0xa4, 0x4e, 0x61, 0x6d, 0x65)
+	o = append(o, 0xde, 0x0, 0x1f, 0xa4, 0x4e, 0x61, 0x6d, 0x65)
 	o = msgp.AppendString(o, z.Name)
 	// string "Created"
 	o = append(o, 0xa7, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64)
@@ -423,6 +583,21 @@ func (z *BucketMetadata) MarshalMsg(b []byte) (o []byte, err error) {
 	// string "BucketTargetsConfigMetaJSON"
 	o = append(o, 0xbb, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4d, 0x65, 0x74, 0x61, 0x4a, 0x53, 0x4f, 0x4e)
 	o = msgp.AppendBytes(o, z.BucketTargetsConfigMetaJSON)
+	// string "CorsConfigXML"
+	o = append(o, 0xad, 0x43, 0x6f, 0x72, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x58, 0x4d, 0x4c)
+	o = msgp.AppendBytes(o, z.CorsConfigXML)
+	// string "WebsiteConfigXML"
+	o = append(o, 0xb0, 0x57, 0x65, 0x62, 0x73, 0x69, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x58, 0x4d, 0x4c)
+	o = msgp.AppendBytes(o, z.WebsiteConfigXML)
+	// string "LoggingConfigXML"
+	o = append(o, 0xb0, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x58, 0x4d, 0x4c)
+	o = msgp.AppendBytes(o, z.LoggingConfigXML)
+	// string "InventoryConfigXML"
+	o = append(o, 0xb2, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x58, 0x4d, 0x4c)
+	o = msgp.AppendBytes(o, z.InventoryConfigXML)
+	// string "PlacementConfigJSON"
+	o = append(o, 0xb3, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4a, 0x53, 0x4f, 0x4e)
+	o = msgp.AppendBytes(o, z.PlacementConfigJSON)
 	// string "PolicyConfigUpdatedAt"
 	o = append(o, 0xb5, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
 	o = msgp.AppendTime(o, z.PolicyConfigUpdatedAt)
@@ -444,6 +619,21 @@ func (z *BucketMetadata) MarshalMsg(b []byte) (o []byte, err error) {
 	// string "VersioningConfigUpdatedAt"
 	o = append(o, 0xb9, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
 	o = msgp.AppendTime(o, z.VersioningConfigUpdatedAt)
+	// string "CorsConfigUpdatedAt"
+	o = append(o, 0xb3, 0x43, 0x6f, 0x72, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
+	o = msgp.AppendTime(o, z.CorsConfigUpdatedAt)
+	// string "WebsiteConfigUpdatedAt"
+	o = append(o, 0xb6, 0x57, 0x65, 0x62, 0x73, 0x69, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
+	o = msgp.AppendTime(o, z.WebsiteConfigUpdatedAt)
+	// string "LoggingConfigUpdatedAt"
+	o = append(o, 0xb6, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
+	o = msgp.AppendTime(o, z.LoggingConfigUpdatedAt)
+	// string "InventoryConfigUpdatedAt"
+	o = append(o, 0xb8, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
+	o = msgp.AppendTime(o, z.InventoryConfigUpdatedAt)
+	// string "PlacementConfigUpdatedAt"
+	o = append(o, 0xb8, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
+	o = msgp.AppendTime(o, z.PlacementConfigUpdatedAt)
 	return
 }
 
@@ -549,6 +739,36 @@ func (z *BucketMetadata) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				err = msgp.WrapError(err, "BucketTargetsConfigMetaJSON")
 				return
 			}
+		case "CorsConfigXML":
+			z.CorsConfigXML, bts, err = msgp.ReadBytesBytes(bts, z.CorsConfigXML)
+			if err != nil {
+				err = msgp.WrapError(err, "CorsConfigXML")
+				return
+			}
+		case "WebsiteConfigXML":
+			z.WebsiteConfigXML, bts, err = msgp.ReadBytesBytes(bts, z.WebsiteConfigXML)
+			if err != nil {
+				err = msgp.WrapError(err, "WebsiteConfigXML")
+				return
+			}
+		case "LoggingConfigXML":
+			z.LoggingConfigXML, bts, err = msgp.ReadBytesBytes(bts, z.LoggingConfigXML)
+			if err != nil {
+				err = msgp.WrapError(err, "LoggingConfigXML")
+				return
+			}
+		case "InventoryConfigXML":
+			z.InventoryConfigXML, bts, err = msgp.ReadBytesBytes(bts, z.InventoryConfigXML)
+			if err != nil {
+				err = msgp.WrapError(err, "InventoryConfigXML")
+				return
+			}
+		case "PlacementConfigJSON":
+			z.PlacementConfigJSON, bts, err = msgp.ReadBytesBytes(bts, z.PlacementConfigJSON)
+			if err != nil {
+				err = msgp.WrapError(err, "PlacementConfigJSON")
+				return
+			}
 		case "PolicyConfigUpdatedAt":
 			z.PolicyConfigUpdatedAt, bts, err = msgp.ReadTimeBytes(bts)
 			if err != nil {
@@ -591,6 +811,36 @@ func (z *BucketMetadata) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				err = msgp.WrapError(err, "VersioningConfigUpdatedAt")
 				return
 			}
+		case "CorsConfigUpdatedAt":
+			z.CorsConfigUpdatedAt, bts, err = msgp.ReadTimeBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "CorsConfigUpdatedAt")
+				return
+			}
+		case "WebsiteConfigUpdatedAt":
+			z.WebsiteConfigUpdatedAt, bts, err = msgp.ReadTimeBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "WebsiteConfigUpdatedAt")
+				return
+			}
+		case "LoggingConfigUpdatedAt":
+			z.LoggingConfigUpdatedAt, bts, err = msgp.ReadTimeBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "LoggingConfigUpdatedAt")
+				return
+			}
+		case "InventoryConfigUpdatedAt":
+			z.InventoryConfigUpdatedAt, bts, err = msgp.ReadTimeBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "InventoryConfigUpdatedAt")
+				return
+			}
+		case "PlacementConfigUpdatedAt":
+			z.PlacementConfigUpdatedAt, bts, err = msgp.ReadTimeBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "PlacementConfigUpdatedAt")
+				return
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -605,6 +855,6 @@ func (z *BucketMetadata) UnmarshalMsg(bts []byte) (o []byte, err error) {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *BucketMetadata) Msgsize() (s int) {
-	s = 3 + 5 + msgp.StringPrefixSize + len(z.Name) + 8 + msgp.TimeSize + 12 + msgp.BoolSize + 17 + msgp.BytesPrefixSize + len(z.PolicyConfigJSON) + 22 + msgp.BytesPrefixSize + len(z.NotificationConfigXML) + 19 + msgp.BytesPrefixSize + len(z.LifecycleConfigXML) + 20 + msgp.BytesPrefixSize + len(z.ObjectLockConfigXML) + 20 + msgp.BytesPrefixSize + len(z.VersioningConfigXML) + 20 + msgp.BytesPrefixSize + len(z.EncryptionConfigXML) + 17 + msgp.BytesPrefixSize + len(z.TaggingConfigXML) + 16 + msgp.BytesPrefixSize + len(z.QuotaConfigJSON) + 21 + msgp.BytesPrefixSize + len(z.ReplicationConfigXML) + 24 + msgp.BytesPrefixSize + len(z.BucketTargetsConfigJSON) + 28 + msgp.BytesPrefixSize + len(z.BucketTargetsConfigMetaJSON) + 22 + msgp.TimeSize + 26 + msgp.TimeSize + 26 + msgp.TimeSize + 23 + msgp.TimeSize + 21 + msgp.TimeSize + 27 + msgp.TimeSize + 26 + msgp.TimeSize
+	s = 3 + 5 + msgp.StringPrefixSize + len(z.Name) + 8 + msgp.TimeSize + 12 + msgp.BoolSize + 17 + msgp.BytesPrefixSize + len(z.PolicyConfigJSON) + 22 + msgp.BytesPrefixSize + len(z.NotificationConfigXML) + 19 + msgp.BytesPrefixSize + len(z.LifecycleConfigXML) + 20 + msgp.BytesPrefixSize + len(z.ObjectLockConfigXML) + 20 + msgp.BytesPrefixSize + len(z.VersioningConfigXML) + 20 + msgp.BytesPrefixSize + len(z.EncryptionConfigXML) + 17 + msgp.BytesPrefixSize + len(z.TaggingConfigXML) + 16 + msgp.BytesPrefixSize + len(z.QuotaConfigJSON) + 21 + msgp.BytesPrefixSize + len(z.ReplicationConfigXML) + 24 + msgp.BytesPrefixSize + len(z.BucketTargetsConfigJSON) + 28 + msgp.BytesPrefixSize + len(z.BucketTargetsConfigMetaJSON) + 14 + msgp.BytesPrefixSize + len(z.CorsConfigXML) + 17 + msgp.BytesPrefixSize + len(z.WebsiteConfigXML) + 17 + msgp.BytesPrefixSize + len(z.LoggingConfigXML) + 19 + msgp.BytesPrefixSize + len(z.InventoryConfigXML) + 20 + msgp.BytesPrefixSize + len(z.PlacementConfigJSON) + 22 + msgp.TimeSize + 26 + msgp.TimeSize + 26 + msgp.TimeSize + 23 + msgp.TimeSize + 21 + msgp.TimeSize + 27 + msgp.TimeSize + 26 + msgp.TimeSize + 20 + msgp.TimeSize + 23 + msgp.TimeSize + 23 + msgp.TimeSize + 25 + msgp.TimeSize + 25 + msgp.TimeSize
 	return
 }
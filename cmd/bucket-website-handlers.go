@@ -0,0 +1,404 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/infobsmi/b33s/internal/bucket/website"
+	"github.com/infobsmi/b33s/internal/event"
+	"github.com/infobsmi/b33s/internal/handlers"
+	xhttp "github.com/infobsmi/b33s/internal/http"
+	xioutil "github.com/infobsmi/b33s/internal/ioutil"
+	"github.com/infobsmi/b33s/internal/logger"
+	"github.com/minio/pkg/bucket/policy"
+	xnet "github.com/minio/pkg/net"
+)
+
+const (
+	// Bucket website configuration file name.
+	bucketWebsiteConfig = "website.xml"
+
+	// S3 actions guarding the bucket website configuration, the policy
+	// package has no website actions so the bucket policy actions are
+	// re-purposed.
+	getBucketWebsiteAction    = policy.GetBucketPolicyAction
+	putBucketWebsiteAction    = policy.PutBucketPolicyAction
+	deleteBucketWebsiteAction = policy.DeleteBucketPolicyAction
+)
+
+// PutBucketWebsiteHandler - Stores given bucket website configuration
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketWebsite.html
+func (api objectAPIHandlers) PutBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "PutBucketWebsite")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, putBucketWebsiteAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL)
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket, BucketOptions{}); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	websiteConfig, err := website.ParseConfig(io.LimitReader(r.Body, maxBucketWebsiteConfigSize))
+	if err != nil {
+		apiErr := APIError{
+			Code:           "MalformedXML",
+			Description:    fmt.Sprintf("%s (%s)", errorCodes[ErrMalformedXML].Description, err),
+			HTTPStatusCode: errorCodes[ErrMalformedXML].HTTPStatusCode,
+		}
+		writeErrorResponse(ctx, w, apiErr, r.URL)
+		return
+	}
+
+	configData, err := xml.Marshal(websiteConfig)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	// Store the bucket website configuration in the object layer
+	updatedAt, err := globalBucketMetadataSys.Update(ctx, bucket, bucketWebsiteConfig, configData)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	// Call site replication hook.
+	item, err := newSRBucketMetaConfig(srBucketMetaTypeWebsiteConfig, bucket, configData, updatedAt)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+	if err = globalSiteReplicationSys.BucketMetaHook(ctx, item); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketWebsiteHandler - Returns bucket website configuration
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetBucketWebsite.html
+func (api objectAPIHandlers) GetBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetBucketWebsite")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, getBucketWebsiteAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL)
+		return
+	}
+
+	// Check if bucket exists
+	var err error
+	if _, err = objAPI.GetBucketInfo(ctx, bucket, BucketOptions{}); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	config, _, err := globalBucketMetadataSys.GetWebsiteConfig(bucket)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	configData, err := xml.Marshal(config)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	// Write bucket website configuration to client
+	writeSuccessResponseXML(w, configData)
+}
+
+// DeleteBucketWebsiteHandler - Removes bucket website configuration
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_DeleteBucketWebsite.html
+func (api objectAPIHandlers) DeleteBucketWebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "DeleteBucketWebsite")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, deleteBucketWebsiteAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL)
+		return
+	}
+
+	// Check if bucket exists
+	var err error
+	if _, err = objAPI.GetBucketInfo(ctx, bucket, BucketOptions{}); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	// Delete bucket website config from object layer
+	updatedAt, err := globalBucketMetadataSys.Delete(ctx, bucket, bucketWebsiteConfig)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	// Call site replication hook.
+	item, err := newSRBucketMetaConfig(srBucketMetaTypeWebsiteConfig, bucket, nil, updatedAt)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+	if err = globalSiteReplicationSys.BucketMetaHook(ctx, item); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessNoContent(w)
+}
+
+// getWebsiteBucket returns the bucket addressed by host when it is a
+// virtual host of one of the static website domains.
+func getWebsiteBucket(host string) (string, bool) {
+	if len(globalWebsiteDomainNames) == 0 {
+		return "", false
+	}
+	xhost, err := xnet.ParseHost(host)
+	if err != nil {
+		return "", false
+	}
+	for _, domain := range globalWebsiteDomainNames {
+		if bucket := strings.TrimSuffix(xhost.Name, "."+domain); bucket != xhost.Name && bucket != "" {
+			return bucket, true
+		}
+	}
+	return "", false
+}
+
+// writeWebsiteErrorResponse - website endpoints are meant to be used by
+// browsers, errors are rendered as a minimal HTML document.
+func writeWebsiteErrorResponse(w http.ResponseWriter, err APIError) {
+	status := html.EscapeString(fmt.Sprintf("%d %s", err.HTTPStatusCode, http.StatusText(err.HTTPStatusCode)))
+	body := fmt.Sprintf("<html>\n<head><title>%s</title></head>\n<body>\n<h1>%s</h1>\n<ul>\n<li>Code: %s</li>\n<li>Message: %s</li>\n<li>RequestId: %s</li>\n</ul>\n</body>\n</html>\n",
+		status, status, html.EscapeString(err.Code), html.EscapeString(err.Description), html.EscapeString(w.Header().Get(xhttp.AmzRequestID)))
+	writeResponse(w, err.HTTPStatusCode, []byte(body), mimeHTML)
+}
+
+func websiteMethodNotAllowedHandler(w http.ResponseWriter, r *http.Request) {
+	writeWebsiteErrorResponse(w, errorCodes.ToAPIErr(ErrMethodNotAllowed))
+}
+
+// WebsiteHandler - serves the objects of a bucket configured for static
+// website hosting, requests on '/' and on keys ending with '/' are served
+// the index document, errors are served the custom error document.
+// https://docs.aws.amazon.com/AmazonS3/latest/userguide/WebsiteHosting.html
+func (api objectAPIHandlers) WebsiteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "Website")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeWebsiteErrorResponse(w, errorCodes.ToAPIErr(ErrServerNotInitialized))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	key := strings.TrimPrefix(r.URL.Path, SlashSeparator)
+
+	config, _, err := globalBucketMetadataSys.GetWebsiteConfig(bucket)
+	if err != nil {
+		writeWebsiteErrorResponse(w, toAPIError(ctx, err))
+		return
+	}
+
+	proto := handlers.GetSourceScheme(r)
+	if proto == "" {
+		proto = getURLScheme(globalIsTLS)
+	}
+
+	if config.RedirectAllRequestsTo != nil {
+		http.Redirect(w, r, config.RedirectAllRequestsTo.Location(key, proto), http.StatusMovedPermanently)
+		return
+	}
+
+	if rule := config.MatchRoutingRule(key, 0); rule != nil {
+		http.Redirect(w, r, rule.Location(key, r.Host, proto), rule.StatusCode())
+		return
+	}
+
+	object := config.IndexKey(key)
+	apiErr := api.serveWebsiteObject(ctx, objAPI, w, r, bucket, object, http.StatusOK)
+	if apiErr == nil {
+		return
+	}
+
+	if apiErr.HTTPStatusCode == http.StatusNotFound && key != "" && object == key {
+		// Requests on a "directory" without the trailing slash are
+		// redirected when it has an index document.
+		indexKey := config.IndexKey(key + SlashSeparator)
+		if checkRequestAuthType(ctx, r, policy.GetObjectAction, bucket, indexKey) == ErrNone {
+			if _, err := objAPI.GetObjectInfo(ctx, bucket, indexKey, ObjectOptions{}); err == nil {
+				http.Redirect(w, r, SlashSeparator+key+SlashSeparator, http.StatusFound)
+				return
+			}
+		}
+	}
+
+	if rule := config.MatchRoutingRule(key, apiErr.HTTPStatusCode); rule != nil {
+		http.Redirect(w, r, rule.Location(key, r.Host, proto), rule.StatusCode())
+		return
+	}
+
+	if errorKey := config.ErrorKey(); errorKey != "" &&
+		(apiErr.HTTPStatusCode == http.StatusNotFound || apiErr.HTTPStatusCode == http.StatusForbidden) {
+		if api.serveWebsiteObject(ctx, objAPI, w, r, bucket, errorKey, apiErr.HTTPStatusCode) == nil {
+			return
+		}
+	}
+
+	writeWebsiteErrorResponse(w, *apiErr)
+}
+
+// serveWebsiteObject writes object to the client with the given status
+// code, the returned error is nil once a response has been written.
+func (api objectAPIHandlers) serveWebsiteObject(ctx context.Context, objAPI ObjectLayer, w http.ResponseWriter, r *http.Request, bucket, object string, statusCode int) *APIError {
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetObjectAction, bucket, object); s3Error != ErrNone {
+		apiErr := errorCodes.ToAPIErr(s3Error)
+		return &apiErr
+	}
+
+	getObjectNInfo := objAPI.GetObjectNInfo
+	if api.CacheAPI() != nil {
+		getObjectNInfo = api.CacheAPI().GetObjectNInfo
+	}
+
+	// Range and conditional requests only apply to the requested
+	// object, never to the error document.
+	var rs *HTTPRangeSpec
+	if rangeHeader := r.Header.Get(xhttp.Range); rangeHeader != "" && statusCode == http.StatusOK {
+		var rangeErr error
+		rs, rangeErr = parseRequestRangeSpec(rangeHeader)
+		if rangeErr == errInvalidRange {
+			apiErr := errorCodes.ToAPIErr(ErrInvalidRange)
+			return &apiErr
+		}
+		if rangeErr != nil {
+			logger.LogIf(ctx, rangeErr, logger.Application)
+		}
+	}
+
+	var opts ObjectOptions
+	opts.CheckPrecondFn = func(oi ObjectInfo) bool {
+		if objAPI.IsEncryptionSupported() {
+			if _, err := DecryptObjectInfo(&oi, r); err != nil {
+				writeWebsiteErrorResponse(w, toAPIError(ctx, err))
+				return true
+			}
+		}
+		return statusCode == http.StatusOK && checkPreconditions(ctx, w, r, oi, opts)
+	}
+
+	gr, err := getObjectNInfo(ctx, bucket, object, rs, r.Header, readLock, opts)
+	if err != nil {
+		if isErrPreconditionFailed(err) {
+			// Response has already been written.
+			return nil
+		}
+		apiErr := toAPIError(ctx, err)
+		return &apiErr
+	}
+	defer gr.Close()
+
+	objInfo := gr.ObjInfo
+	if objInfo.DeleteMarker {
+		apiErr := errorCodes.ToAPIErr(ErrNoSuchKey)
+		return &apiErr
+	}
+
+	if err = setObjectHeaders(w, objInfo, rs, opts); err != nil {
+		apiErr := toAPIError(ctx, err)
+		return &apiErr
+	}
+
+	if rs != nil {
+		statusCode = http.StatusPartialContent
+	}
+
+	w.WriteHeader(statusCode)
+
+	eventName := event.ObjectAccessedGet
+	if r.Method == http.MethodHead {
+		eventName = event.ObjectAccessedHead
+	} else {
+		if _, err = xioutil.Copy(w, gr); err != nil {
+			if !xnet.IsNetworkOrHostDown(err, true) { // do not need to log disconnected clients
+				logger.LogIf(ctx, fmt.Errorf("Unable to write all the data to client %w", err))
+			}
+			return nil
+		}
+	}
+
+	// Notify object accessed via the website endpoint.
+	sendEvent(eventArgs{
+		EventName:    eventName,
+		BucketName:   bucket,
+		Object:       objInfo,
+		ReqParams:    extractReqParams(r),
+		RespElements: extractRespElements(w),
+		UserAgent:    r.UserAgent(),
+		Host:         handlers.GetSourceIP(r),
+	})
+	return nil
+}
@@ -693,6 +693,24 @@ func handleCommonEnvVars() {
 		}
 	}
 
+	websiteDomains := env.Get(config.EnvWebsiteDomain, "")
+	if len(websiteDomains) != 0 {
+		for _, domainName := range strings.Split(websiteDomains, config.ValueSeparator) {
+			if _, ok := dns2.IsDomainName(domainName); !ok {
+				logger.Fatal(config.ErrInvalidDomainValue(nil).Msg("Unknown value `%s`", domainName),
+					"Invalid MINIO_WEBSITE_DOMAIN value in environment variable")
+			}
+			for _, apiDomainName := range globalDomainNames {
+				if domainName == apiDomainName {
+					logger.Fatal(config.ErrOverlappingDomainValue(nil).Msg("Website domain `%s` is already used by MINIO_DOMAIN", domainName),
+						"Invalid MINIO_WEBSITE_DOMAIN value in environment variable")
+				}
+			}
+			globalWebsiteDomainNames = append(globalWebsiteDomainNames, domainName)
+		}
+		sort.Strings(globalWebsiteDomainNames)
+	}
+
 	publicIPs := env.Get(config.EnvPublicIPs, "")
 	if len(publicIPs) != 0 {
 		minioEndpoints := strings.Split(publicIPs, config.ValueSeparator)
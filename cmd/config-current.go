@@ -69,6 +69,8 @@ func initHelp() {
 		config.LoggerWebhookSubSys:  logger.DefaultLoggerWebhookKVS,
 		config.AuditWebhookSubSys:   logger.DefaultAuditWebhookKVS,
 		config.AuditKafkaSubSys:     logger.DefaultAuditKafkaKVS,
+		config.LoggerFileSubSys:     logger.DefaultLoggerFileKVS,
+		config.AuditFileSubSys:      logger.DefaultAuditFileKVS,
 		config.ScannerSubSys:        scanner.DefaultKVS,
 		config.SubnetSubSys:         subnet.DefaultKVS,
 		config.CallhomeSubSys:       callhome.DefaultKVS,
@@ -148,6 +150,16 @@ func initHelp() {
 			Description:     "send audit logs to kafka endpoints",
 			MultipleTargets: true,
 		},
+		config.HelpKV{
+			Key:             config.LoggerFileSubSys,
+			Description:     "write server logs to local rotating files",
+			MultipleTargets: true,
+		},
+		config.HelpKV{
+			Key:             config.AuditFileSubSys,
+			Description:     "write audit logs to local rotating files",
+			MultipleTargets: true,
+		},
 		config.HelpKV{
 			Key:             config.NotifyWebhookSubSys,
 			Description:     "publish bucket notifications to webhook endpoints",
@@ -178,6 +190,11 @@ func initHelp() {
 			Description:     "publish bucket notifications to NSQ endpoints",
 			MultipleTargets: true,
 		},
+		config.HelpKV{
+			Key:             config.NotifyPulsarSubSys,
+			Description:     "publish bucket notifications to Pulsar endpoints",
+			MultipleTargets: true,
+		},
 		config.HelpKV{
 			Key:             config.NotifyMySQLSubSys,
 			Description:     "publish bucket notifications to MySQL databases",
@@ -238,11 +255,14 @@ func initHelp() {
 		config.LoggerWebhookSubSys:  logger.Help,
 		config.AuditWebhookSubSys:   logger.HelpWebhook,
 		config.AuditKafkaSubSys:     logger.HelpKafka,
+		config.LoggerFileSubSys:     logger.HelpLoggerFile,
+		config.AuditFileSubSys:      logger.HelpAuditFile,
 		config.NotifyAMQPSubSys:     notify.HelpAMQP,
 		config.NotifyKafkaSubSys:    notify.HelpKafka,
 		config.NotifyMQTTSubSys:     notify.HelpMQTT,
 		config.NotifyNATSSubSys:     notify.HelpNATS,
 		config.NotifyNSQSubSys:      notify.HelpNSQ,
+		config.NotifyPulsarSubSys:   notify.HelpPulsar,
 		config.NotifyMySQLSubSys:    notify.HelpMySQL,
 		config.NotifyPostgresSubSys: notify.HelpPostgres,
 		config.NotifyRedisSubSys:    notify.HelpRedis,
@@ -289,8 +309,13 @@ func validateSubSysConfig(s config.Config, subSys string, objAPI ObjectLayer) er
 		if objAPI == nil {
 			return errServerNotInitialized
 		}
-		for _, setDriveCount := range objAPI.SetDriveCounts() {
-			if _, err := storageclass.LookupConfig(s[config.StorageClassSubSys][config.Default], setDriveCount); err != nil {
+		setDriveCounts := objAPI.SetDriveCounts()
+		for _, setDriveCount := range setDriveCounts {
+			sc, err := storageclass.LookupConfig(s[config.StorageClassSubSys][config.Default], setDriveCount)
+			if err != nil {
+				return err
+			}
+			if err = sc.ValidatePools(len(setDriveCounts)); err != nil {
 				return err
 			}
 		}
@@ -628,6 +653,30 @@ func applyDynamicConfigForSubSys(ctx context.Context, objAPI ObjectLayer, s conf
 		if errs := logger.UpdateAuditKafkaTargets(loggerCfg); len(errs) > 0 {
 			logger.LogIf(ctx, fmt.Errorf("Unable to update audit kafka targets: %v", errs))
 		}
+	case config.LoggerFileSubSys:
+		loggerCfg, err := logger.LookupConfigForSubSys(s, config.LoggerFileSubSys)
+		if err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to load logger file config: %w", err))
+		}
+		for n, l := range loggerCfg.LoggerFile {
+			l.LogOnce = logger.LogOnceConsoleIf
+			loggerCfg.LoggerFile[n] = l
+		}
+		if errs := logger.UpdateSystemFileTargets(loggerCfg); len(errs) > 0 {
+			logger.LogIf(ctx, fmt.Errorf("Unable to update logger file targets: %v", errs))
+		}
+	case config.AuditFileSubSys:
+		loggerCfg, err := logger.LookupConfigForSubSys(s, config.AuditFileSubSys)
+		if err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to load audit file config: %w", err))
+		}
+		for n, l := range loggerCfg.AuditFile {
+			l.LogOnce = logger.LogOnceIf
+			loggerCfg.AuditFile[n] = l
+		}
+		if errs := logger.UpdateAuditFileTargets(loggerCfg); len(errs) > 0 {
+			logger.LogIf(ctx, fmt.Errorf("Unable to update audit file targets: %v", errs))
+		}
 	case config.StorageClassSubSys:
 		for i, setDriveCount := range setDriveCounts {
 			sc, err := storageclass.LookupConfig(s[config.StorageClassSubSys][config.Default], setDriveCount)
@@ -635,6 +684,10 @@ func applyDynamicConfigForSubSys(ctx context.Context, objAPI ObjectLayer, s conf
 				logger.LogIf(ctx, fmt.Errorf("Unable to initialize storage class config: %w", err))
 				break
 			}
+			if err = sc.ValidatePools(len(setDriveCounts)); err != nil {
+				logger.LogIf(ctx, fmt.Errorf("Unable to initialize storage class config: %w", err))
+				break
+			}
 			// if we validated all setDriveCounts and it was successful
 			// proceed to store the correct storage class globally.
 			if i == len(setDriveCounts)-1 {
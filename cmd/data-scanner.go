@@ -40,7 +40,6 @@ import (
 	"github.com/infobsmi/b33s/internal/bucket/replication"
 	"github.com/infobsmi/b33s/internal/color"
 	"github.com/infobsmi/b33s/internal/config/heal"
-	"github.com/infobsmi/b33s/internal/event"
 	"github.com/infobsmi/b33s/internal/logger"
 	"github.com/minio/madmin-go/v2"
 	"github.com/minio/pkg/console"
@@ -1103,6 +1102,9 @@ func (i *scannerItem) applyActions(ctx context.Context, o ObjectLayer, oi Object
 		done := globalScannerMetrics.time(scannerMetricCheckReplication)
 		i.healReplication(ctx, o, oi.Clone(), sizeS)
 		done()
+
+		// resume restores from archive tiers pending before a restart.
+		globalArchiveRestoreState.resume(oi)
 	}
 	return size
 }
@@ -1188,18 +1190,8 @@ func applyExpiryOnNonTransitionedObjects(ctx context.Context, objLayer ObjectLay
 	// Send audit for the lifecycle delete operation
 	auditLogLifecycle(ctx, obj, ILMExpiry)
 
-	eventName := event.ObjectRemovedDelete
-	if obj.DeleteMarker {
-		eventName = event.ObjectRemovedDeleteMarkerCreated
-	}
-
 	// Notify object deleted event.
-	sendEvent(eventArgs{
-		EventName:  eventName,
-		BucketName: obj.Bucket,
-		Object:     obj,
-		Host:       "Internal: [ILM-EXPIRY]",
-	})
+	sendLifecycleExpiryEvents(obj.Bucket, obj)
 
 	return true
 }
@@ -101,11 +101,12 @@ func (dui DataUsageInfo) tierStats() []madmin.TierInfo {
 	}
 
 	cfgs := globalTierConfigMgr.ListTiers()
-	if len(cfgs) == 0 {
+	fsCfgs := globalTierConfigMgr.ListFSTiers()
+	if len(cfgs) == 0 && len(fsCfgs) == 0 {
 		return nil
 	}
 
-	ts := make(map[string]madmin.TierStats, len(cfgs)+1)
+	ts := make(map[string]madmin.TierStats, len(cfgs)+len(fsCfgs)+1)
 	infos := make([]madmin.TierInfo, 0, len(ts))
 
 	// Add STANDARD (hot-tier)
@@ -122,6 +123,13 @@ func (dui DataUsageInfo) tierStats() []madmin.TierInfo {
 			Type: cfg.Type.String(),
 		})
 	}
+	for _, cfg := range fsCfgs {
+		ts[cfg.Name] = madmin.TierStats{}
+		infos = append(infos, madmin.TierInfo{
+			Name: cfg.Name,
+			Type: tierTypeFS,
+		})
+	}
 
 	ts = dui.TierStats.adminStats(ts)
 	for i := range infos {
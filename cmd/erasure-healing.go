@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/b33s/madmin-go/v2"
+	xhttp "github.com/infobsmi/b33s/internal/http"
 	"github.com/infobsmi/b33s/internal/logger"
 	"github.com/infobsmi/b33s/internal/sync/errgroup"
 )
@@ -398,13 +399,13 @@ func (er *erasureObjects) healObject(ctx context.Context, bucket string, object
 		}
 
 		// Is only 'true' if the opts.Recreate is true and
-		// the object shardSize < smallFileThreshold do not
+		// the object shardSize < inline threshold do not
 		// set this to 'true' arbitrarily and must be only
 		// 'true' with caller ask.
 		recreate = (opts.Recreate &&
 			!latestMeta.InlineData() &&
 			len(latestMeta.Parts) == 1 &&
-			erasure.ShardFileSize(latestMeta.Parts[0].ActualSize) < smallFileThreshold)
+			erasure.ShardFileSize(latestMeta.Parts[0].ActualSize) < inlineBlockForSC(latestMeta.Metadata[xhttp.AmzStorageClass]))
 	}
 
 	// Loop to find number of disks with valid data, per-drive
@@ -836,6 +836,7 @@ func (er erasureObjects) ListObjectParts(ctx context.Context, bucket, object, up
 	result.MaxParts = maxParts
 	result.PartNumberMarker = partNumberMarker
 	result.UserDefined = cloneMSS(fi.Metadata)
+	result.StorageClass = fi.Metadata[xhttp.AmzStorageClass]
 	result.ChecksumAlgorithm = fi.Metadata[hash.B33SMultipartChecksum]
 
 	// For empty number of parts or maxParts as zero, return right here.
@@ -934,6 +934,15 @@ func (er erasureObjects) PutObject(ctx context.Context, bucket string, object st
 	return er.putObject(ctx, bucket, object, data, opts)
 }
 
+// inlineBlockForSC returns the shard size below which the data of
+// objects of the storage class sc is inlined with the metadata.
+func inlineBlockForSC(sc string) int64 {
+	if size, ok := globalStorageClass.InlineBlockForSC(sc); ok {
+		return size
+	}
+	return smallFileThreshold
+}
+
 // putObject wrapper for erasureObjects PutObject
 func (er erasureObjects) putObject(ctx context.Context, bucket string, object string, r *PutObjReader, opts ObjectOptions) (objInfo ObjectInfo, err error) {
 	auditObjectErasureSet(ctx, object, &er)
@@ -1076,19 +1085,20 @@ func (er erasureObjects) putObject(ctx context.Context, bucket string, object st
 
 	shardFileSize := erasure.ShardFileSize(data.Size())
 	writers := make([]io.Writer, len(onlineDisks))
+	inlineBlock := inlineBlockForSC(userDefined[xhttp.AmzStorageClass])
 	var inlineBuffers []*bytes.Buffer
 	if shardFileSize >= 0 {
-		if !opts.Versioned && shardFileSize < smallFileThreshold {
+		if !opts.Versioned && shardFileSize < inlineBlock {
 			inlineBuffers = make([]*bytes.Buffer, len(onlineDisks))
-		} else if shardFileSize < smallFileThreshold/8 {
+		} else if shardFileSize < inlineBlock/8 {
 			inlineBuffers = make([]*bytes.Buffer, len(onlineDisks))
 		}
 	} else {
 		// If compressed, use actual size to determine.
 		if sz := erasure.ShardFileSize(data.ActualSize()); sz > 0 {
-			if !opts.Versioned && sz < smallFileThreshold {
+			if !opts.Versioned && sz < inlineBlock {
 				inlineBuffers = make([]*bytes.Buffer, len(onlineDisks))
-			} else if sz < smallFileThreshold/8 {
+			} else if sz < inlineBlock/8 {
 				inlineBuffers = make([]*bytes.Buffer, len(onlineDisks))
 			}
 		}
@@ -1901,6 +1911,9 @@ func (er erasureObjects) TransitionObject(ctx context.Context, bucket, object st
 	if !opts.MTime.Equal(fi.ModTime) || !strings.EqualFold(opts.Transition.ETag, extractETag(fi.Metadata)) {
 		return toObjectErr(errFileNotFound, bucket, object)
 	}
+	if opts.Transition.FromTier != "" {
+		return er.migrateTransitionedObject(ctx, bucket, object, fi, tgtClient, opts)
+	}
 	// if object already transitioned, return
 	if fi.TransitionStatus == lifecycle.TransitionComplete {
 		return nil
@@ -1936,6 +1949,12 @@ func (er erasureObjects) TransitionObject(ctx context.Context, bucket, object st
 	pr.CloseWithError(err)
 	if err != nil {
 		logger.LogIf(ctx, fmt.Errorf("Unable to transition %s/%s(%s) to %s tier: %w", bucket, object, opts.VersionID, opts.Transition.Tier, err))
+		sendEvent(eventArgs{
+			EventName:  event.ObjectTransitionFailed,
+			BucketName: bucket,
+			Object:     fi.ToObjectInfo(bucket, object, opts.Versioned || opts.VersionSuspended),
+			Host:       "Internal: [ILM-Transition]",
+		})
 		return err
 	}
 	fi.TransitionStatus = lifecycle.TransitionComplete
@@ -1969,6 +1988,62 @@ func (er erasureObjects) TransitionObject(ctx context.Context, bucket, object st
 	return err
 }
 
+// migrateTransitionedObject copies the content of a version transitioned to
+// opts.Transition.FromTier to the tier of tgtClient and points the version to
+// the new copy. The copy on the previous tier is deleted via the tier journal.
+// Caller must hold the write lock of the object.
+func (er erasureObjects) migrateTransitionedObject(ctx context.Context, bucket, object string, fi FileInfo, tgtClient WarmBackend, opts ObjectOptions) error {
+	// version was moved or restored since it was listed, nothing to do.
+	if fi.TransitionStatus != lifecycle.TransitionComplete || fi.TransitionTier != opts.Transition.FromTier {
+		return nil
+	}
+	oi := fi.ToObjectInfo(bucket, object, opts.Versioned || opts.VersionSuspended)
+	if oi.RestoreOngoing || !oi.RestoreExpires.IsZero() {
+		// Updating the version would remove the restored copy.
+		return errTierObjectRestored
+	}
+
+	srcClient, err := globalTierConfigMgr.getDriver(fi.TransitionTier)
+	if err != nil {
+		return err
+	}
+	r, err := srcClient.Get(ctx, fi.TransitionedObjName, remoteVersionID(fi.TransitionVersionID), WarmBackendGetOpts{})
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	destObj, err := genTransitionObjName(bucket)
+	if err != nil {
+		return err
+	}
+	rv, err := tgtClient.Put(ctx, destObj, r, fi.Size)
+	if err != nil {
+		return fmt.Errorf("Unable to migrate %s/%s(%s) from %s to %s tier: %w", bucket, object, opts.VersionID, fi.TransitionTier, opts.Transition.Tier, err)
+	}
+
+	entry := jentry{
+		ObjName:   fi.TransitionedObjName,
+		VersionID: fi.TransitionVersionID,
+		TierName:  fi.TransitionTier,
+	}
+	fi.TransitionedObjName = destObj
+	fi.TransitionTier = opts.Transition.Tier
+	fi.TransitionVersionID = string(rv)
+	if err = er.deleteObjectVersion(ctx, bucket, object, fi, false); err != nil {
+		// The new copy is left behind, as some drives may refer to it.
+		return err
+	}
+	for _, disk := range er.getDisks() {
+		if disk != nil && disk.IsOnline() {
+			continue
+		}
+		er.addPartial(bucket, object, opts.VersionID, -1)
+		break
+	}
+	return globalTierJournal.AddEntry(entry)
+}
+
 // RestoreTransitionedObject - restore transitioned object content locally on this cluster.
 // This is similar to PostObjectRestore from AWS GLACIER
 // storage class. When PostObjectRestore API is called, a temporary copy of the object
@@ -2014,6 +2089,24 @@ func (er erasureObjects) restoreTransitionedObject(ctx context.Context, bucket s
 	}
 
 	oi = actualfi.ToObjectInfo(bucket, object, opts.Versioned || opts.VersionSuspended)
+	staged, err := stageTransitionedObject(ctx, oi, opts.Transition.RestoreRequest)
+	if err != nil {
+		return setRestoreHeaderFn(oi, toObjectErr(err, bucket, object))
+	}
+	if !staged {
+		// The archive tier restores the object asynchronously, keep the
+		// restore ongoing and retry once the object is staged.
+		r := archiveRestore{
+			bucket:    bucket,
+			object:    decodeDirObject(object),
+			versionID: oi.VersionID,
+		}
+		if rreq := opts.Transition.RestoreRequest; rreq != nil {
+			r.days, r.tier = rreq.Days, rreq.Tier
+		}
+		globalArchiveRestoreState.queue(r)
+		return errRestorePending
+	}
 	ropts := putRestoreOpts(bucket, object, opts.Transition.RestoreRequest, oi)
 	if len(oi.Parts) == 1 {
 		var rs *HTTPRangeSpec
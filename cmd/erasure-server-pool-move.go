@@ -0,0 +1,299 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/infobsmi/b33s/internal/hash"
+	xhttp "github.com/infobsmi/b33s/internal/http"
+	"github.com/infobsmi/b33s/internal/logger"
+	"github.com/infobsmi/b33s/internal/sync/errgroup"
+)
+
+// objectVersions returns the versions of an object in the set, latest
+// first, as agreed by a read quorum of the drives.
+func (er erasureObjects) objectVersions(ctx context.Context, bucket, object string) ([]FileInfo, error) {
+	disks := er.getDisks()
+	metas := make([]*xlMetaV2, len(disks))
+
+	g := errgroup.WithNErrs(len(disks))
+	for index := range disks {
+		index := index
+		g.Go(func() error {
+			if disks[index] == nil {
+				return errDiskNotFound
+			}
+			rf, err := disks[index].ReadXL(ctx, bucket, object, false)
+			if err != nil {
+				return err
+			}
+			var xl xlMetaV2
+			if err = xl.LoadOrConvert(rf.Buf); err != nil {
+				return err
+			}
+			metas[index] = &xl
+			return nil
+		}, index)
+	}
+
+	readQuorum := (len(disks) + 1) / 2
+	if err := reduceReadQuorumErrs(ctx, g.Wait(), objectOpIgnoredErrs, readQuorum); err != nil {
+		return nil, toObjectErr(err, bucket, object)
+	}
+
+	var merged xlMetaV2
+	versions := make([][]xlMetaV2ShallowVersion, 0, len(metas))
+	for _, meta := range metas {
+		if meta != nil {
+			merged.metaV = meta.metaV
+			versions = append(versions, meta.versions)
+		}
+	}
+	merged.versions = mergeXLV2Versions(readQuorum, false, 0, versions...)
+	return merged.ListVersions(bucket, object)
+}
+
+// sameVersions returns true if a and b are the same versions of an object.
+func sameVersions(a, b []FileInfo) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].VersionID != b[i].VersionID || a[i].Deleted != b[i].Deleted || !a[i].ModTime.Equal(b[i].ModTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// moveObjectToSCPool moves an object to a pool of the storage class sc, if
+// sc has a pool affinity and the object is on another pool, and rewrites its
+// version oi with sc on the way. All the versions of an object are kept on a
+// single pool, so all of them are moved: they are copied oldest first to the
+// new pool, like rebalance copies them, and are removed from the old pool
+// once all are copied, unless the object was modified in the meantime.
+// moving is false if the object does not have to move, it is then
+// rewritten in place by the caller.
+func (z *erasureServerPools) moveObjectToSCPool(ctx context.Context, oi ObjectInfo, sc string) (moving bool, err error) {
+	pools := globalStorageClass.PoolsForSC(sc)
+	if z.SinglePool() || len(pools) == 0 {
+		return false, nil
+	}
+
+	bucket, object := oi.Bucket, encodeDirObject(oi.Name)
+	versionID := oi.VersionID
+	if versionID == nullVersionID {
+		versionID = ""
+	}
+	srcIdx, err := z.getPoolIdxExistingWithOpts(ctx, bucket, object, ObjectOptions{})
+	if err != nil {
+		return false, err
+	}
+	for _, idx := range pools {
+		if idx == srcIdx {
+			return false, nil
+		}
+	}
+	// The object is moved by the decommission or the rebalance
+	// of its pool.
+	if z.IsSuspended(srcIdx) || z.IsPoolRebalancing(srcIdx) {
+		return false, nil
+	}
+
+	src := z.serverPools[srcIdx].getHashedSet(object)
+	versions, err := src.objectVersions(ctx, bucket, object)
+	if err != nil {
+		return true, err
+	}
+	found := false
+	for _, version := range versions {
+		// Transitioned versions are not moved, like by rebalance.
+		if version.IsRemote() {
+			return false, nil
+		}
+		if version.VersionID == versionID && version.ModTime.Equal(oi.ModTime) {
+			found = true
+		}
+	}
+	if !found {
+		// The version was modified after oi was read.
+		return true, nil
+	}
+
+	dstIdx := z.getAvailablePoolIdx(ctx, bucket, object, oi.Size, sc)
+	if dstIdx < 0 {
+		return true, toObjectErr(errDiskFull)
+	}
+	dst := z.serverPools[dstIdx].getHashedSet(object)
+
+	// Remove the versions copied so far on failure.
+	var copied []FileInfo
+	defer func() {
+		if err != nil {
+			removeObjectVersions(ctx, dst, bucket, object, copied)
+		}
+	}()
+
+	for i := len(versions) - 1; i >= 0; i-- {
+		version := versions[i]
+		if version.Deleted {
+			_, err = dst.DeleteObject(ctx, bucket, object, ObjectOptions{
+				Versioned:         version.VersionID != "",
+				VersionSuspended:  version.VersionID == "",
+				VersionID:         version.VersionID,
+				MTime:             version.ModTime,
+				DeleteReplication: version.ReplicationState,
+				DeleteMarker:      true, // make sure we create a delete marker
+			})
+		} else if version.VersionID == versionID {
+			err = copyVersionToSet(ctx, src, dst, bucket, object, version, sc)
+		} else {
+			err = copyVersionToSet(ctx, src, dst, bucket, object, version, "")
+		}
+		if err != nil {
+			return true, fmt.Errorf("moving %s/%s (%s) to pool %d: %w", bucket, oi.Name, version.VersionID, dstIdx+1, err)
+		}
+		copied = append(copied, version)
+	}
+
+	// Block writes while checking that the object did not change
+	// and removing it from the old pool.
+	lk := z.NewNSLock(bucket, object)
+	lkctx, err := lk.GetLock(ctx, globalOperationTimeout)
+	if err != nil {
+		return true, err
+	}
+	ctx = lkctx.Context()
+	defer lk.Unlock(lkctx.Cancel)
+
+	current, err := src.objectVersions(ctx, bucket, object)
+	if err != nil {
+		return true, err
+	}
+	if !sameVersions(versions, current) {
+		// Moved again by a later scan.
+		removeObjectVersions(ctx, dst, bucket, object, copied)
+		return true, nil
+	}
+	removeObjectVersions(ctx, src, bucket, object, versions)
+	return true, nil
+}
+
+// copyVersionToSet copies an object version from the set src to the set
+// dst of another pool, preserving its version ID, modification time, ETag
+// and parts. The version is written with the storage class sc if not empty.
+func copyVersionToSet(ctx context.Context, src, dst *erasureObjects, bucket, object string, version FileInfo, sc string) error {
+	versionID := version.VersionID
+	if versionID == "" {
+		versionID = nullVersionID
+	}
+	gr, err := src.GetObjectNInfo(ctx, bucket, object, nil, http.Header{}, noLock, ObjectOptions{
+		VersionID:    versionID,
+		NoDecryption: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	objInfo := gr.ObjInfo
+	userDefined := objInfo.UserDefined
+	if sc != "" {
+		userDefined = cloneMSS(userDefined)
+		userDefined[xhttp.AmzStorageClass] = sc
+	}
+
+	actualSize, err := objInfo.GetActualSize()
+	if err != nil {
+		return err
+	}
+
+	if objInfo.isMultipart() {
+		res, err := dst.NewMultipartUpload(ctx, bucket, object, ObjectOptions{
+			VersionID:   version.VersionID,
+			MTime:       objInfo.ModTime,
+			UserDefined: userDefined,
+		})
+		if err != nil {
+			return err
+		}
+		defer dst.AbortMultipartUpload(ctx, bucket, object, res.UploadID, ObjectOptions{})
+
+		parts := make([]CompletePart, len(objInfo.Parts))
+		for i, part := range objInfo.Parts {
+			hr, err := hash.NewReader(gr, part.Size, "", "", part.ActualSize)
+			if err != nil {
+				return err
+			}
+			pi, err := dst.PutObjectPart(ctx, bucket, object, res.UploadID,
+				part.Number,
+				NewPutObjReader(hr),
+				ObjectOptions{
+					PreserveETag: part.ETag, // Preserve original ETag to ensure same metadata.
+					IndexCB: func() []byte {
+						return part.Index // Preserve part Index to ensure decompression works.
+					},
+				})
+			if err != nil {
+				return err
+			}
+			parts[i] = CompletePart{
+				ETag:           pi.ETag,
+				PartNumber:     pi.PartNumber,
+				ChecksumCRC32:  pi.ChecksumCRC32,
+				ChecksumCRC32C: pi.ChecksumCRC32C,
+				ChecksumSHA256: pi.ChecksumSHA256,
+				ChecksumSHA1:   pi.ChecksumSHA1,
+			}
+		}
+		_, err = dst.CompleteMultipartUpload(ctx, bucket, object, res.UploadID, parts, ObjectOptions{
+			MTime: objInfo.ModTime,
+		})
+		return err
+	}
+
+	hr, err := hash.NewReader(gr, objInfo.Size, "", "", actualSize)
+	if err != nil {
+		return err
+	}
+	_, err = dst.PutObject(ctx, bucket, object, NewPutObjReader(hr), ObjectOptions{
+		VersionID:    version.VersionID,
+		MTime:        objInfo.ModTime,
+		UserDefined:  userDefined,
+		PreserveETag: objInfo.ETag, // Preserve original ETag to ensure same metadata.
+		IndexCB: func() []byte {
+			return objInfo.Parts[0].Index // Preserve part Index to ensure decompression works.
+		},
+	})
+	return err
+}
+
+// removeObjectVersions removes the versions of an object from the set.
+func removeObjectVersions(ctx context.Context, set *erasureObjects, bucket, object string, versions []FileInfo) {
+	for _, version := range versions {
+		_, err := set.DeleteObject(ctx, bucket, object, ObjectOptions{
+			VersionID: version.VersionID,
+		})
+		if err != nil && !isErrObjectNotFound(err) && !isErrVersionNotFound(err) {
+			logger.LogIf(ctx, err)
+		}
+	}
+}
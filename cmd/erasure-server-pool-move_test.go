@@ -0,0 +1,124 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/infobsmi/b33s/internal/config/storageclass"
+	xhttp "github.com/infobsmi/b33s/internal/http"
+)
+
+func TestTransitionObjectToStorageClassPools(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	objLayer, fsDirs, err := prepareErasurePools()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	z := objLayer.(*erasureServerPools)
+
+	initAllSubsystems(ctx)
+
+	storageclass.ConfigLock.Lock()
+	globalStorageClass.Custom = map[string]storageclass.CustomClass{
+		"COLD": {Parity: 4, InlineBlock: -1, Pools: []int{1}},
+	}
+	storageclass.ConfigLock.Unlock()
+	defer func() {
+		storageclass.ConfigLock.Lock()
+		globalStorageClass.Custom = nil
+		storageclass.ConfigLock.Unlock()
+	}()
+
+	bucket, object := "bucket", "object"
+	if err = objLayer.MakeBucketWithLocation(ctx, bucket, MakeBucketOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two versions and a delete marker on the first pool.
+	data := [][]byte{bytes.Repeat([]byte("a"), 1<<20), []byte("version-2")}
+	var infos []ObjectInfo
+	for _, b := range data {
+		oi, err := z.serverPools[0].PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(b), int64(len(b)), "", ""), ObjectOptions{Versioned: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		infos = append(infos, oi)
+	}
+	marker, err := z.serverPools[0].DeleteObject(ctx, bucket, object, ObjectOptions{Versioned: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oi, err := z.serverPools[0].GetObjectInfo(ctx, bucket, object, ObjectOptions{VersionID: infos[0].VersionID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = transitionObjectToStorageClass(ctx, objLayer, oi, "COLD"); err != nil {
+		t.Fatal(err)
+	}
+
+	// All the versions are moved to the pool of the storage class.
+	if _, err = z.serverPools[0].getHashedSet(object).objectVersions(ctx, bucket, object); !isErrObjectNotFound(err) {
+		t.Fatalf("expected the object to be removed from the first pool, got %v", err)
+	}
+	versions, err := z.serverPools[1].getHashedSet(object).objectVersions(ctx, bucket, object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 3 {
+		t.Fatalf("expected 3 versions, got %d", len(versions))
+	}
+	if !versions[0].Deleted || versions[0].VersionID != marker.VersionID {
+		t.Errorf("expected the delete marker %s to be the latest version, got %+v", marker.VersionID, versions[0])
+	}
+	for i, oi := range []ObjectInfo{infos[1], infos[0]} {
+		fi := versions[i+1]
+		if fi.VersionID != oi.VersionID || !fi.ModTime.Equal(oi.ModTime) {
+			t.Errorf("expected version %s modified at %v, got %s modified at %v", oi.VersionID, oi.ModTime, fi.VersionID, fi.ModTime)
+		}
+	}
+	if sc := versions[2].Metadata[xhttp.AmzStorageClass]; sc != "COLD" {
+		t.Errorf("expected storage class COLD for the transitioned version, got %q", sc)
+	}
+	if sc := versions[1].Metadata[xhttp.AmzStorageClass]; sc == "COLD" {
+		t.Errorf("expected the storage class of the other versions to be kept")
+	}
+
+	for i, b := range data {
+		gr, err := objLayer.GetObjectNInfo(ctx, bucket, object, nil, http.Header{}, noLock, ObjectOptions{VersionID: infos[i].VersionID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(gr)
+		gr.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, b) || gr.ObjInfo.ETag != infos[i].ETag {
+			t.Errorf("version %s: unexpected content or ETag %s", infos[i].VersionID, gr.ObjInfo.ETag)
+		}
+	}
+}
@@ -422,9 +422,31 @@ func (z *erasureServerPools) checkIfRebalanceDone(poolIdx int) bool {
 	return false
 }
 
+// canRebalanceBucket returns true if the placement policy of the bucket
+// allows moving its objects to at least one pool not participating in
+// the rebalance.
+func (z *erasureServerPools) canRebalanceBucket(ctx context.Context, bucket string) bool {
+	weights, _ := z.bucketPlacement(ctx, bucket)
+	if weights == nil {
+		return true
+	}
+	for idx := range weights {
+		if !z.IsSuspended(idx) && !z.IsPoolRebalancing(idx) {
+			return true
+		}
+	}
+	return false
+}
+
 // rebalanceBucket rebalances objects under bucket in poolIdx pool
 func (z *erasureServerPools) rebalanceBucket(ctx context.Context, bucket string, poolIdx int) error {
 	ctx = logger.SetReqInfo(ctx, &logger.ReqInfo{})
+	// Objects are only moved to the pools allowed by the placement policy
+	// of the bucket, see getAvailablePoolIdx. Skip buckets which can't be
+	// moved anywhere instead of failing each of their objects.
+	if !z.canRebalanceBucket(ctx, bucket) {
+		return nil
+	}
 	vc, _ := globalBucketVersioningSys.Get(bucket)
 	// Check if the current bucket has a configured lifecycle policy
 	lc, _ := globalLifecycleSys.Get(bucket)
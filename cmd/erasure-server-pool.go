@@ -37,6 +37,7 @@ import (
 	"github.com/infobsmi/b33s-go/v7/pkg/tags"
 	"github.com/infobsmi/b33s/internal/bucket/lifecycle"
 	"github.com/infobsmi/b33s/internal/config/storageclass"
+	xhttp "github.com/infobsmi/b33s/internal/http"
 	"github.com/infobsmi/b33s/internal/logger"
 	"github.com/infobsmi/b33s/internal/sync/errgroup"
 	"github.com/b33s/pkg/wildcard"
@@ -281,11 +282,89 @@ func (p serverPoolsAvailableSpace) FilterMaxUsed(max int) {
 	}
 }
 
+// FilterPools returns only the pools with the given indexes, all pools
+// are returned if no indexes are given.
+func (p serverPoolsAvailableSpace) FilterPools(indexes []int) serverPoolsAvailableSpace {
+	if len(indexes) == 0 {
+		return p
+	}
+	filtered := make(serverPoolsAvailableSpace, 0, len(indexes))
+	for _, z := range p {
+		for _, idx := range indexes {
+			if z.Index == idx {
+				filtered = append(filtered, z)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// Weighted returns the pools with available space, with their weight
+// given as available space, so that pools are chosen by weight.
+func (p serverPoolsAvailableSpace) Weighted(weights map[int]uint64) serverPoolsAvailableSpace {
+	weighted := make(serverPoolsAvailableSpace, 0, len(p))
+	for _, z := range p {
+		if z.Available == 0 {
+			continue
+		}
+		z.Available = weights[z.Index]
+		weighted = append(weighted, z)
+	}
+	return weighted
+}
+
+// poolIdxByName returns the index of a pool named by its index or by
+// its command line argument.
+func (z *erasureServerPools) poolIdxByName(name string) (int, bool) {
+	if idx, err := strconv.Atoi(name); err == nil {
+		return idx, idx >= 0 && idx < len(z.serverPools)
+	}
+	idx := globalEndpoints.GetPoolIdx(name)
+	return idx, idx >= 0 && idx < len(z.serverPools)
+}
+
+// bucketPlacement returns the weight of each pool new objects of the
+// bucket may be placed on and whether objects are spread by weight.
+// nil is returned if the bucket has no placement policy.
+func (z *erasureServerPools) bucketPlacement(ctx context.Context, bucket string) (map[int]uint64, bool) {
+	if isMinioMetaBucketName(bucket) {
+		return nil, false
+	}
+	placementConfig, _, err := globalBucketMetadataSys.GetPlacementConfig(ctx, bucket)
+	if err != nil {
+		return nil, false
+	}
+	weights, err := placementConfig.Resolve(z.poolIdxByName, len(z.serverPools))
+	if err != nil {
+		// Pools may have been renamed or removed since the policy
+		// was set, ignore the policy rather than failing writes.
+		logger.LogOnceIf(ctx, fmt.Errorf("ignoring placement policy of bucket %s: %w", bucket, err), "placement-"+bucket)
+		return nil, false
+	}
+	return weights, placementConfig.Weighted()
+}
+
 // getAvailablePoolIdx will return an index that can hold size bytes.
+// Only the pools of the storage class sc are considered if it has a
+// pool affinity, and only the pools allowed by the placement policy
+// of the bucket if it has one.
 // -1 is returned if no serverPools have available space for the size given.
-func (z *erasureServerPools) getAvailablePoolIdx(ctx context.Context, bucket, object string, size int64) int {
+func (z *erasureServerPools) getAvailablePoolIdx(ctx context.Context, bucket, object string, size int64, sc string) int {
 	serverPools := z.getServerPoolsAvailableSpace(ctx, bucket, object, size)
+	serverPools = serverPools.FilterPools(globalStorageClass.PoolsForSC(sc))
+	weights, weighted := z.bucketPlacement(ctx, bucket)
+	if weights != nil {
+		indexes := make([]int, 0, len(weights))
+		for idx := range weights {
+			indexes = append(indexes, idx)
+		}
+		serverPools = serverPools.FilterPools(indexes)
+	}
 	serverPools.FilterMaxUsed(100 - (100 * diskReserveFraction))
+	if weighted {
+		serverPools = serverPools.Weighted(weights)
+	}
 	total := serverPools.TotalAvailable()
 	if total == 0 {
 		return -1
@@ -464,14 +543,14 @@ func (z *erasureServerPools) getPoolIdxExistingNoLock(ctx context.Context, bucke
 	})
 }
 
-func (z *erasureServerPools) getPoolIdxNoLock(ctx context.Context, bucket, object string, size int64) (idx int, err error) {
+func (z *erasureServerPools) getPoolIdxNoLock(ctx context.Context, bucket, object string, size int64, sc string) (idx int, err error) {
 	idx, err = z.getPoolIdxExistingNoLock(ctx, bucket, object)
 	if err != nil && !isErrObjectNotFound(err) {
 		return idx, err
 	}
 
 	if isErrObjectNotFound(err) {
-		idx = z.getAvailablePoolIdx(ctx, bucket, object, size)
+		idx = z.getAvailablePoolIdx(ctx, bucket, object, size, sc)
 		if idx < 0 {
 			return -1, toObjectErr(errDiskFull)
 		}
@@ -483,7 +562,7 @@ func (z *erasureServerPools) getPoolIdxNoLock(ctx context.Context, bucket, objec
 // getPoolIdx returns the found previous object and its corresponding pool idx,
 // if none are found falls back to most available space pool, this function is
 // designed to be only used by PutObject, CopyObject (newObject creation) and NewMultipartUpload.
-func (z *erasureServerPools) getPoolIdx(ctx context.Context, bucket, object string, size int64) (idx int, err error) {
+func (z *erasureServerPools) getPoolIdx(ctx context.Context, bucket, object string, size int64, sc string) (idx int, err error) {
 	idx, err = z.getPoolIdxExistingWithOpts(ctx, bucket, object, ObjectOptions{
 		SkipDecommissioned: true,
 		SkipRebalancing:    true,
@@ -493,7 +572,7 @@ func (z *erasureServerPools) getPoolIdx(ctx context.Context, bucket, object stri
 	}
 
 	if isErrObjectNotFound(err) {
-		idx = z.getAvailablePoolIdx(ctx, bucket, object, size)
+		idx = z.getAvailablePoolIdx(ctx, bucket, object, size, sc)
 		if idx < 0 {
 			return -1, toObjectErr(errDiskFull)
 		}
@@ -940,7 +1019,7 @@ func (z *erasureServerPools) PutObject(ctx context.Context, bucket string, objec
 		opts.NoLock = true
 	}
 
-	idx, err := z.getPoolIdxNoLock(ctx, bucket, object, data.Size())
+	idx, err := z.getPoolIdxNoLock(ctx, bucket, object, data.Size(), opts.UserDefined[xhttp.AmzStorageClass])
 	if err != nil {
 		return ObjectInfo{}, err
 	}
@@ -1126,7 +1205,7 @@ func (z *erasureServerPools) CopyObject(ctx context.Context, srcBucket, srcObjec
 		dstOpts.NoLock = true
 	}
 
-	poolIdx, err := z.getPoolIdxNoLock(ctx, dstBucket, dstObject, srcInfo.Size)
+	poolIdx, err := z.getPoolIdxNoLock(ctx, dstBucket, dstObject, srcInfo.Size, srcInfo.UserDefined[xhttp.AmzStorageClass])
 	if err != nil {
 		return objInfo, err
 	}
@@ -1402,7 +1481,7 @@ func (z *erasureServerPools) NewMultipartUpload(ctx context.Context, bucket, obj
 
 	// any parallel writes on the object will block for this poolIdx
 	// to return since this holds a read lock on the namespace.
-	idx, err := z.getPoolIdx(ctx, bucket, object, -1)
+	idx, err := z.getPoolIdx(ctx, bucket, object, -1, opts.UserDefined[xhttp.AmzStorageClass])
 	if err != nil {
 		return nil, err
 	}
@@ -1849,6 +1928,15 @@ func (z *erasureServerPools) Walk(ctx context.Context, bucket, prefix string, re
 	vcfg, _ := globalBucketVersioningSys.Get(bucket)
 
 	ctx, cancel := context.WithCancel(ctx)
+	var failOnce sync.Once
+	fail := func(err error) {
+		failOnce.Do(func() {
+			if opts.WalkErrFn != nil {
+				opts.WalkErrFn(err)
+			}
+		})
+		cancel()
+	}
 	go func() {
 		defer cancel()
 		defer close(results)
@@ -1863,7 +1951,7 @@ func (z *erasureServerPools) Walk(ctx context.Context, bucket, prefix string, re
 
 					disks, _ := set.getOnlineDisksWithHealing()
 					if len(disks) == 0 {
-						cancel()
+						fail(errErasureReadQuorum)
 						return
 					}
 
@@ -1874,7 +1962,7 @@ func (z *erasureServerPools) Walk(ctx context.Context, bucket, prefix string, re
 
 						fivs, err := entry.fileInfoVersions(bucket)
 						if err != nil {
-							cancel()
+							fail(err)
 							return
 						}
 
@@ -1939,7 +2027,7 @@ func (z *erasureServerPools) Walk(ctx context.Context, bucket, prefix string, re
 
 					if err := listPathRaw(ctx, lopts); err != nil {
 						logger.LogIf(ctx, fmt.Errorf("listPathRaw returned %w: opts(%#v)", err, lopts))
-						cancel()
+						fail(err)
 						return
 					}
 				}()
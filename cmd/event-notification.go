@@ -166,8 +166,8 @@ func (evnot *EventNotifier) ConfiguredTargetIDs() []event.TargetID {
 	var targetIDs []event.TargetID
 	for _, rmap := range evnot.bucketRulesMap {
 		for _, rules := range rmap {
-			for _, targetSet := range rules {
-				for id := range targetSet {
+			for _, rt := range rules {
+				for id := range rt.TargetIDs {
 					targetIDs = append(targetIDs, id)
 				}
 			}
@@ -221,7 +221,7 @@ func (evnot *EventNotifier) Send(args eventArgs) {
 
 func (evnot *EventNotifier) send(args eventArgs) {
 	evnot.RLock()
-	targetIDSet := evnot.bucketRulesMap[args.BucketName].Match(args.EventName, args.Object.Name)
+	targetIDSet := evnot.bucketRulesMap[args.BucketName].MatchObject(args.EventName, args.ToObjectProps())
 	evnot.RUnlock()
 
 	if len(targetIDSet) == 0 {
@@ -241,6 +241,38 @@ type eventArgs struct {
 	UserAgent    string
 }
 
+// ToObjectProps - returns the object properties matched by the object
+// filters of the notification rules.
+func (args eventArgs) ToObjectProps() event.ObjectProps {
+	props := event.ObjectProps{
+		Name: args.Object.Name,
+		Size: args.Object.Size,
+	}
+	if args.EventName == event.ObjectRemovedDelete || args.EventName == event.ObjectRemovedDeleteMarkerCreated {
+		return props
+	}
+
+	props.Metadata = make(map[string]string, len(args.Object.UserDefined)+1)
+	for k, v := range args.Object.UserDefined {
+		if strings.HasPrefix(strings.ToLower(k), ReservedMetadataPrefixLower) {
+			continue
+		}
+		props.Metadata[k] = v
+	}
+	if args.Object.ContentType != "" {
+		props.Metadata[xhttp.ContentType] = args.Object.ContentType
+	}
+	if args.Object.UserTags != "" {
+		if tags, err := url.ParseQuery(args.Object.UserTags); err == nil {
+			props.Tags = make(map[string]string, len(tags))
+			for k := range tags {
+				props.Tags[k] = tags.Get(k)
+			}
+		}
+	}
+	return props
+}
+
 // ToEvent - converts to notification event.
 func (args eventArgs) ToEvent(escape bool) event.Event {
 	eventTime := UTCNow()
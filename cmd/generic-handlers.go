@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"path"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -32,6 +33,7 @@ import (
 	xnet "github.com/minio/pkg/net"
 
 	"github.com/infobsmi/b33s/internal/amztime"
+	"github.com/infobsmi/b33s/internal/bucket/cors"
 	"github.com/infobsmi/b33s/internal/config/dns"
 	"github.com/infobsmi/b33s/internal/crypto"
 	xhttp "github.com/infobsmi/b33s/internal/http"
@@ -176,6 +178,10 @@ var redirectPrefixes = map[string]struct{}{
 // serves only limited purpose on redirect-handler for
 // browser requests.
 func getRedirectLocation(r *http.Request) *xnet.URL {
+	if _, ok := getWebsiteBucket(r.Host); ok {
+		// Website endpoints are served as is.
+		return nil
+	}
 	resource, err := getResource(r.URL.Path, r.Host, globalDomainNames)
 	if err != nil {
 		return nil
@@ -534,6 +540,82 @@ func setBucketForwardingHandler(h http.Handler) http.Handler {
 	})
 }
 
+// setBucketCorsHandler applies the CORS configuration of a bucket to
+// cross-origin requests made on that bucket. Requests on buckets without
+// a CORS configuration are handed over to globalCors, which enforces the
+// cluster wide 'cors_allow_origin' API setting.
+func setBucketCorsHandler(h http.Handler, globalCors http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get(xhttp.Origin)
+		if origin == "" || guessIsHealthCheckReq(r) || guessIsMetricsReq(r) ||
+			guessIsRPCReq(r) || guessIsLoginSTSReq(r) || isAdminReq(r) {
+			globalCors.ServeHTTP(w, r)
+			return
+		}
+
+		bucket, _ := request2BucketObjectName(r)
+		if bucket == "" || isMinioMetaBucketName(bucket) || isMinioReservedBucket(bucket) {
+			globalCors.ServeHTTP(w, r)
+			return
+		}
+
+		corsConfig, _, err := globalBucketMetadataSys.GetCorsConfig(bucket)
+		if err != nil {
+			globalCors.ServeHTTP(w, r)
+			return
+		}
+
+		// Preflight request, answered here without reaching the API handlers.
+		if r.Method == http.MethodOptions && r.Header.Get(xhttp.AccessControlRequestMethod) != "" {
+			var reqHeaders []string
+			if v := r.Header.Get(xhttp.AccessControlRequestHeaders); v != "" {
+				reqHeaders = strings.Split(v, ",")
+			}
+			rule := corsConfig.Match(origin, r.Header.Get(xhttp.AccessControlRequestMethod), reqHeaders)
+			if rule == nil {
+				writeErrorResponse(r.Context(), w, errorCodes.ToAPIErr(ErrCORSForbidden), r.URL)
+				return
+			}
+			setCorsResponseHeaders(w.Header(), rule, origin)
+			w.Header().Add(xhttp.Vary, xhttp.AccessControlRequestMethod)
+			w.Header().Add(xhttp.Vary, xhttp.AccessControlRequestHeaders)
+			w.Header().Set(xhttp.AccessControlAllowMethods, strings.Join(rule.AllowedMethods, ", "))
+			if len(reqHeaders) > 0 {
+				w.Header().Set(xhttp.AccessControlAllowHeaders, r.Header.Get(xhttp.AccessControlRequestHeaders))
+			}
+			if rule.MaxAgeSeconds != nil {
+				w.Header().Set(xhttp.AccessControlMaxAge, strconv.Itoa(*rule.MaxAgeSeconds))
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		// Actual request, CORS headers are only added when a rule matches,
+		// the request itself is always served.
+		if rule := corsConfig.Match(origin, r.Method, nil); rule != nil {
+			setCorsResponseHeaders(w.Header(), rule, origin)
+		} else {
+			w.Header().Add(xhttp.Vary, xhttp.Origin)
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// setCorsResponseHeaders sets the CORS response headers common to
+// preflight and actual requests for a matching CORS rule.
+func setCorsResponseHeaders(header http.Header, rule *cors.Rule, origin string) {
+	header.Add(xhttp.Vary, xhttp.Origin)
+	if rule.AllowsAnyOrigin() {
+		header.Set(xhttp.AccessControlAllowOrigin, "*")
+	} else {
+		header.Set(xhttp.AccessControlAllowOrigin, origin)
+		header.Set(xhttp.AccessControlAllowCredentials, "true")
+	}
+	if len(rule.ExposeHeaders) > 0 {
+		header.Set(xhttp.AccessControlExposeHeaders, strings.Join(rule.ExposeHeaders, ", "))
+	}
+}
+
 // addCustomHeaders adds various HTTP(S) response headers.
 // Security Headers enable various security protections behaviors in the client's browser.
 func addCustomHeaders(h http.Handler) http.Handler {
@@ -114,6 +114,21 @@ const (
 	// Maximum size of default bucket encryption configuration allowed
 	maxBucketSSEConfigSize = 1 * humanize.MiByte
 
+	// Maximum size of bucket CORS configuration allowed
+	maxBucketCorsConfigSize = 64 * humanize.KiByte
+
+	// Maximum size of bucket website configuration allowed
+	maxBucketWebsiteConfigSize = 64 * humanize.KiByte
+
+	// Maximum size of bucket logging configuration allowed
+	maxBucketLoggingConfigSize = 64 * humanize.KiByte
+
+	// Maximum size of a bucket inventory configuration allowed
+	maxBucketInventoryConfigSize = 64 * humanize.KiByte
+
+	// Maximum size of all inventory configurations of a bucket allowed
+	maxBucketInventoryConfigsSize = 16 * humanize.MiByte
+
 	// diskFillFraction is the fraction of a disk we allow to be filled.
 	diskFillFraction = 0.99
 
@@ -262,12 +277,15 @@ var (
 	globalDomainNames []string      // Root domains for virtual host style requests
 	globalDomainIPs   set.StringSet // Root domain IP address(s) for a distributed B33S deployment
 
+	globalWebsiteDomainNames []string // Root domains for the static website endpoint
+
 	globalOperationTimeout       = newDynamicTimeout(10*time.Minute, 5*time.Minute) // default timeout for general ops
 	globalDeleteOperationTimeout = newDynamicTimeout(5*time.Minute, 1*time.Minute)  // default time for delete ops
 
 	globalBucketObjectLockSys *BucketObjectLockSys
 	globalBucketQuotaSys      *BucketQuotaSys
 	globalBucketVersioningSys *BucketVersioningSys
+	globalBucketLoggingSys    *BucketLoggingSys
 
 	// Disk cache drives
 	globalCacheConfig cache.Config
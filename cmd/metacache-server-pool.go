@@ -384,7 +384,7 @@ func applyBucketActions(ctx context.Context, o listPathOptions, in <-chan metaCa
 
 func (z *erasureServerPools) listAndSave(ctx context.Context, o *listPathOptions) (entries metaCacheEntriesSorted, err error) {
 	// Use ID as the object name...
-	o.pool = z.getAvailablePoolIdx(ctx, minioMetaBucket, o.ID, 10<<20)
+	o.pool = z.getAvailablePoolIdx(ctx, minioMetaBucket, o.ID, 10<<20, "")
 	if o.pool < 0 {
 		// No space or similar, don't persist the listing.
 		o.pool = 0
@@ -30,6 +30,7 @@ import (
 	"github.com/minio/kes"
 	"github.com/minio/madmin-go/v2"
 	"github.com/infobsmi/b33s/internal/bucket/lifecycle"
+	"github.com/infobsmi/b33s/internal/event"
 	"github.com/infobsmi/b33s/internal/logger"
 	"github.com/infobsmi/b33s/internal/mcontext"
 	"github.com/infobsmi/b33s/internal/rest"
@@ -1574,8 +1575,13 @@ func getCacheMetrics() *MetricsGroup {
 func getNotificationMetrics() *MetricsGroup {
 	mg := &MetricsGroup{}
 	mg.RegisterRead(func(ctx context.Context) []Metric {
-		stats := globalConfigTargetList.Stats()
-		metrics := make([]Metric, 0, 1+len(stats.TargetStats))
+		// Events are sent through the target list of the event notifier,
+		// which holds the configured and the bucket targets.
+		var stats event.TargetStats
+		if globalEventNotifier != nil {
+			stats = globalEventNotifier.targetList.Stats()
+		}
+		metrics := make([]Metric, 0, 1+6*len(stats.TargetStats))
 		metrics = append(metrics, Metric{
 			Description: MetricDescription{
 				Namespace: minioNamespace,
@@ -1598,6 +1604,61 @@ func getNotificationMetrics() *MetricsGroup {
 				VariableLabels: map[string]string{"target_id": st.ID.ID, "target_name": st.ID.Name},
 				Value:          float64(st.CurrentQueue),
 			})
+			metrics = append(metrics, Metric{
+				Description: MetricDescription{
+					Namespace: minioNamespace,
+					Subsystem: notifySubsystem,
+					Name:      "target_total_events",
+					Help:      "Total number of events delivered to target since start",
+					Type:      counterMetric,
+				},
+				VariableLabels: map[string]string{"target_id": st.ID.ID, "target_name": st.ID.Name},
+				Value:          float64(st.TotalEvents),
+			})
+			metrics = append(metrics, Metric{
+				Description: MetricDescription{
+					Namespace: minioNamespace,
+					Subsystem: notifySubsystem,
+					Name:      "target_failed_events",
+					Help:      "Total number of failed attempts to deliver events to target since start",
+					Type:      counterMetric,
+				},
+				VariableLabels: map[string]string{"target_id": st.ID.ID, "target_name": st.ID.Name},
+				Value:          float64(st.FailedEvents),
+			})
+			metrics = append(metrics, Metric{
+				Description: MetricDescription{
+					Namespace: minioNamespace,
+					Subsystem: notifySubsystem,
+					Name:      "target_dropped_events",
+					Help:      "Total number of events dropped because of a full queue or too many concurrent sends since start",
+					Type:      counterMetric,
+				},
+				VariableLabels: map[string]string{"target_id": st.ID.ID, "target_name": st.ID.Name},
+				Value:          float64(st.DroppedEvents),
+			})
+			metrics = append(metrics, Metric{
+				Description: MetricDescription{
+					Namespace: minioNamespace,
+					Subsystem: notifySubsystem,
+					Name:      "target_latency_ms_sum",
+					Help:      "Total time in milliseconds taken by the successful deliveries to target since start",
+					Type:      counterMetric,
+				},
+				VariableLabels: map[string]string{"target_id": st.ID.ID, "target_name": st.ID.Name},
+				Value:          float64(st.TotalLatency) / float64(time.Millisecond),
+			})
+			metrics = append(metrics, Metric{
+				Description: MetricDescription{
+					Namespace: minioNamespace,
+					Subsystem: notifySubsystem,
+					Name:      "target_latency_ms_count",
+					Help:      "Total number of successful deliveries to target since start",
+					Type:      counterMetric,
+				},
+				VariableLabels: map[string]string{"target_id": st.ID.ID, "target_name": st.ID.Name},
+				Value:          float64(st.LatencyCount),
+			})
 		}
 		// Audit and system:
 		audit := logger.CurrentStats()
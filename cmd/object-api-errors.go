@@ -373,6 +373,34 @@ func (e BucketSSEConfigNotFound) Error() string {
 	return "No bucket encryption configuration found for bucket: " + e.Bucket
 }
 
+// BucketCorsNotFound - no bucket CORS configuration found
+type BucketCorsNotFound GenericError
+
+func (e BucketCorsNotFound) Error() string {
+	return "No bucket CORS configuration found for bucket: " + e.Bucket
+}
+
+// BucketWebsiteNotFound - no bucket website configuration found
+type BucketWebsiteNotFound GenericError
+
+func (e BucketWebsiteNotFound) Error() string {
+	return "No bucket website configuration found for bucket: " + e.Bucket
+}
+
+// BucketLoggingNotFound - no bucket logging configuration found
+type BucketLoggingNotFound GenericError
+
+func (e BucketLoggingNotFound) Error() string {
+	return "No bucket logging configuration found for bucket: " + e.Bucket
+}
+
+// BucketInventoryNotFound - no bucket inventory configuration found
+type BucketInventoryNotFound GenericError
+
+func (e BucketInventoryNotFound) Error() string {
+	return "No bucket inventory configuration found for bucket: " + e.Bucket
+}
+
 // BucketTaggingNotFound - no bucket tags found
 type BucketTaggingNotFound GenericError
 
@@ -394,6 +422,13 @@ func (e BucketQuotaConfigNotFound) Error() string {
 	return "No quota config found for bucket : " + e.Bucket
 }
 
+// BucketPlacementConfigNotFound - no bucket pool placement policy found.
+type BucketPlacementConfigNotFound GenericError
+
+func (e BucketPlacementConfigNotFound) Error() string {
+	return "No pool placement policy found for bucket: " + e.Bucket
+}
+
 // BucketQuotaExceeded - bucket quota exceeded.
 type BucketQuotaExceeded GenericError
 
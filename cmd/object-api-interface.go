@@ -91,6 +91,7 @@ type ObjectOptions struct {
 
 	WalkFilter      func(info FileInfo) bool // return WalkFilter returns 'true/false'
 	WalkMarker      string                   // set to skip until this object
+	WalkErrFn       func(err error)          // called at most once if the walk ends early, before the results channel is closed
 	PrefixEnabledFn func(prefix string) bool // function which returns true if versioning is enabled on prefix
 
 	// IndexCB will return any index created but the compression.
@@ -111,6 +112,7 @@ type TransitionOptions struct {
 	RestoreRequest *RestoreObjectRequest
 	RestoreExpiry  time.Time
 	ExpireRestored bool
+	FromTier       string // moves a version transitioned to FromTier over to Tier
 }
 
 // MakeBucketOptions represents bucket options for ObjectLayer bucket operations
@@ -0,0 +1,275 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/infobsmi/b33s/internal/crypto"
+	"github.com/infobsmi/b33s/internal/hash"
+	xhttp "github.com/infobsmi/b33s/internal/http"
+	"github.com/infobsmi/b33s/internal/logger"
+	"github.com/minio/pkg/bucket/policy"
+)
+
+// Object attributes which can be requested by GetObjectAttributes.
+const (
+	objectAttributeETag         = "ETag"
+	objectAttributeChecksum     = "Checksum"
+	objectAttributeObjectParts  = "ObjectParts"
+	objectAttributeStorageClass = "StorageClass"
+	objectAttributeObjectSize   = "ObjectSize"
+)
+
+// Default number of parts returned by GetObjectAttributes (same as AWS S3).
+const defaultObjectAttributesMaxParts = 1000
+
+// ObjectAttributesChecksum - checksum of an object or object part.
+type ObjectAttributesChecksum struct {
+	ChecksumCRC32  string `xml:"ChecksumCRC32,omitempty"`
+	ChecksumCRC32C string `xml:"ChecksumCRC32C,omitempty"`
+	ChecksumSHA1   string `xml:"ChecksumSHA1,omitempty"`
+	ChecksumSHA256 string `xml:"ChecksumSHA256,omitempty"`
+}
+
+// ObjectAttributesPart - a part of a multipart object.
+type ObjectAttributesPart struct {
+	ObjectAttributesChecksum
+	PartNumber int   `xml:"PartNumber"`
+	Size       int64 `xml:"Size"`
+}
+
+// ObjectAttributesParts - the parts of a multipart object.
+type ObjectAttributesParts struct {
+	IsTruncated          bool                   `xml:"IsTruncated"`
+	MaxParts             int                    `xml:"MaxParts"`
+	NextPartNumberMarker int                    `xml:"NextPartNumberMarker"`
+	PartNumberMarker     int                    `xml:"PartNumberMarker"`
+	Parts                []ObjectAttributesPart `xml:"Part"`
+	PartsCount           int                    `xml:"PartsCount"`
+}
+
+// GetObjectAttributesResponse - response of the GetObjectAttributes API.
+type GetObjectAttributesResponse struct {
+	XMLName      xml.Name                  `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetObjectAttributesOutput"`
+	ETag         string                    `xml:"ETag,omitempty"`
+	Checksum     *ObjectAttributesChecksum `xml:"Checksum,omitempty"`
+	ObjectParts  *ObjectAttributesParts    `xml:"ObjectParts,omitempty"`
+	StorageClass string                    `xml:"StorageClass,omitempty"`
+	ObjectSize   *int64                    `xml:"ObjectSize,omitempty"`
+}
+
+func newObjectAttributesChecksum(checksums map[string]string) ObjectAttributesChecksum {
+	return ObjectAttributesChecksum{
+		ChecksumCRC32:  checksums[hash.ChecksumCRC32.String()],
+		ChecksumCRC32C: checksums[hash.ChecksumCRC32C.String()],
+		ChecksumSHA1:   checksums[hash.ChecksumSHA1.String()],
+		ChecksumSHA256: checksums[hash.ChecksumSHA256.String()],
+	}
+}
+
+// getObjectAttributesArgs parses the requested attributes and the part
+// listing arguments of a GetObjectAttributes request.
+func getObjectAttributesArgs(header http.Header) (attributes map[string]bool, partNumberMarker, maxParts int, errCode APIErrorCode) {
+	var err error
+	errCode = ErrNone
+
+	attributes = make(map[string]bool)
+	for _, values := range header.Values(xhttp.AmzObjectAttributes) {
+		for _, attribute := range strings.Split(values, ",") {
+			attribute = strings.TrimSpace(attribute)
+			switch attribute {
+			case "":
+			case objectAttributeETag, objectAttributeChecksum, objectAttributeObjectParts,
+				objectAttributeStorageClass, objectAttributeObjectSize:
+				attributes[attribute] = true
+			default:
+				errCode = ErrInvalidObjectAttributes
+				return
+			}
+		}
+	}
+	if len(attributes) == 0 {
+		errCode = ErrMissingObjectAttributes
+		return
+	}
+
+	maxParts = defaultObjectAttributesMaxParts
+	if v := header.Get(xhttp.AmzMaxParts); v != "" {
+		if maxParts, err = strconv.Atoi(v); err != nil || maxParts < 0 {
+			errCode = ErrInvalidMaxParts
+			return
+		}
+		if maxParts > maxPartsList {
+			maxParts = maxPartsList
+		}
+	}
+
+	if v := header.Get(xhttp.AmzPartNumberMarker); v != "" {
+		if partNumberMarker, err = strconv.Atoi(v); err != nil || partNumberMarker < 0 {
+			errCode = ErrInvalidPartNumberMarker
+			return
+		}
+	}
+	return
+}
+
+// GetObjectAttributesHandler - Returns the ETag, checksum, parts, storage
+// class and size of an object without returning the object itself.
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetObjectAttributes.html
+func (api objectAPIHandlers) GetObjectAttributesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetObjectAttributes")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objectAPI := api.ObjectAPI()
+	if objectAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object, err := unescapePath(vars["object"])
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	if crypto.S3.IsRequested(r.Header) || crypto.S3KMS.IsRequested(r.Header) { // If SSE-S3 or SSE-KMS present -> AWS fails with undefined error
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrBadRequest), r.URL)
+		return
+	}
+	if crypto.Requested(r.Header) && !objectAPI.IsEncryptionSupported() {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrBadRequest), r.URL)
+		return
+	}
+
+	// The attributes are part of the object metadata, reading them
+	// requires the same permission as HEAD Object.
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetObjectAction, bucket, object); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL)
+		return
+	}
+
+	attributes, partNumberMarker, maxParts, s3Error := getObjectAttributesArgs(r.Header)
+	if s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL)
+		return
+	}
+
+	getObjectInfo := objectAPI.GetObjectInfo
+	if api.CacheAPI() != nil {
+		getObjectInfo = api.CacheAPI().GetObjectInfo
+	}
+
+	opts, err := getOpts(ctx, r, bucket, object)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	objInfo, err := getObjectInfo(ctx, bucket, object, opts)
+	if err != nil {
+		if objInfo.VersionID != "" && objInfo.DeleteMarker {
+			w.Header()[xhttp.AmzVersionID] = []string{objInfo.VersionID}
+			w.Header()[xhttp.AmzDeleteMarker] = []string{strconv.FormatBool(objInfo.DeleteMarker)}
+		}
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	if objectAPI.IsEncryptionSupported() {
+		if _, err = DecryptObjectInfo(&objInfo, r); err != nil {
+			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+			return
+		}
+		if crypto.SSEC.IsEncrypted(objInfo.UserDefined) {
+			// Validate the SSE-C Key set in the header.
+			if _, err = crypto.SSEC.UnsealObjectKey(r.Header, objInfo.UserDefined, bucket, object); err != nil {
+				writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+				return
+			}
+		}
+	}
+
+	response := GetObjectAttributesResponse{}
+	if attributes[objectAttributeETag] {
+		response.ETag = objInfo.ETag
+	}
+	if attributes[objectAttributeChecksum] {
+		if checksums := objInfo.decryptChecksums(); len(checksums) > 0 {
+			checksum := newObjectAttributesChecksum(checksums)
+			response.Checksum = &checksum
+		}
+	}
+	if attributes[objectAttributeObjectParts] && objInfo.isMultipart() {
+		parts := &ObjectAttributesParts{
+			MaxParts:         maxParts,
+			PartNumberMarker: partNumberMarker,
+			PartsCount:       len(objInfo.Parts),
+		}
+		for _, part := range objInfo.Parts {
+			if part.Number <= partNumberMarker {
+				continue
+			}
+			if len(parts.Parts) == maxParts {
+				parts.IsTruncated = true
+				break
+			}
+			size := part.ActualSize
+			if size <= 0 {
+				size = part.Size
+			}
+			parts.Parts = append(parts.Parts, ObjectAttributesPart{
+				ObjectAttributesChecksum: newObjectAttributesChecksum(part.Checksums),
+				PartNumber:               part.Number,
+				Size:                     size,
+			})
+			parts.NextPartNumberMarker = part.Number
+		}
+		response.ObjectParts = parts
+	}
+	if attributes[objectAttributeStorageClass] {
+		response.StorageClass = objInfo.StorageClass
+		if response.StorageClass == "" {
+			response.StorageClass = globalMinioDefaultStorageClass
+		}
+	}
+	if attributes[objectAttributeObjectSize] {
+		size, err := objInfo.GetActualSize()
+		if err != nil {
+			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL)
+			return
+		}
+		response.ObjectSize = &size
+	}
+
+	if !objInfo.ModTime.IsZero() {
+		w.Header().Set(xhttp.LastModified, objInfo.ModTime.UTC().Format(http.TimeFormat))
+	}
+	if objInfo.VersionID != "" {
+		w.Header()[xhttp.AmzVersionID] = []string{objInfo.VersionID}
+	}
+
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
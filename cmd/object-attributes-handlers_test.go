@@ -0,0 +1,291 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/dustin/go-humanize"
+	"github.com/infobsmi/b33s/internal/auth"
+	xhttp "github.com/infobsmi/b33s/internal/http"
+)
+
+// Wrapper for calling GetObjectAttributes API handler tests for both Erasure multiple disks and FS single drive setup.
+func TestAPIGetObjectAttributesHandler(t *testing.T) {
+	globalPolicySys = NewPolicySys()
+	defer func() { globalPolicySys = nil }()
+
+	defer DetectTestLeak(t)()
+	ExecObjectLayerAPITest(t, testAPIGetObjectAttributesHandler, []string{"GetObjectAttributes", "NewMultipart", "PutObjectPart", "CompleteMultipart", "PutObject"})
+}
+
+func testAPIGetObjectAttributesHandler(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials auth.Credentials, t *testing.T,
+) {
+	// Set SSL to on to do encryption tests
+	globalIsTLS = true
+	defer func() { globalIsTLS = false }()
+
+	var (
+		oneMiB        int64 = 1024 * 1024
+		key32Bytes          = generateBytesData(32 * humanize.Byte)
+		key32BytesMd5       = md5.Sum(key32Bytes)
+		metaWithSSEC        = map[string]string{
+			xhttp.AmzServerSideEncryptionCustomerAlgorithm: xhttp.AmzEncryptionAES,
+			xhttp.AmzServerSideEncryptionCustomerKey:       base64.StdEncoding.EncodeToString(key32Bytes),
+			xhttp.AmzServerSideEncryptionCustomerKeyMD5:    base64.StdEncoding.EncodeToString(key32BytesMd5[:]),
+		}
+		withAttributes = func(attributes string, m map[string]string) map[string]string {
+			r := map[string]string{xhttp.AmzObjectAttributes: attributes}
+			for k, v := range m {
+				r[k] = v
+			}
+			return r
+		}
+		int64Ptr = func(i int64) *int64 { return &i }
+	)
+
+	// Single part object with a SHA256 checksum.
+	data := generateBytesData(509)
+	dataMD5 := md5.Sum(data)
+	dataSHA256 := sha256.Sum256(data)
+	checksum := base64.StdEncoding.EncodeToString(dataSHA256[:])
+	req, err := newTestSignedRequestV4(http.MethodPut, getPutObjectURL("", bucketName, "small"),
+		int64(len(data)), bytes.NewReader(data), credentials.AccessKey, credentials.SecretKey,
+		map[string]string{xhttp.AmzChecksumSHA256: checksum})
+	if err != nil {
+		t.Fatalf("%s: Failed to create HTTP request for Put Object: <ERROR> %v", instanceType, err)
+	}
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: Unable to upload object with checksum: %d %s", instanceType, rec.Code, rec.Body.String())
+	}
+
+	// Multipart and SSE-C encrypted objects.
+	uploadTestObject(t, apiRouter, credentials, bucketName, "mp", []int64{5 * oneMiB, 5 * oneMiB, 1}, nil, false)
+	uploadTestObject(t, apiRouter, credentials, bucketName, "enc", []int64{509}, metaWithSSEC, false)
+
+	// Two versions of the same object.
+	var versions []ObjectInfo
+	for _, size := range []int64{11, 13} {
+		objInfo, err := obj.PutObject(context.Background(), bucketName, "versioned",
+			mustGetPutObjReader(t, bytes.NewReader(generateBytesData(int(size))), size, "", ""), ObjectOptions{Versioned: true})
+		if err != nil {
+			t.Fatalf("%s: Unable to upload object version: %v", instanceType, err)
+		}
+		versions = append(versions, objInfo)
+	}
+
+	testCases := []struct {
+		objectName string
+		versionID  string
+		headers    map[string]string
+
+		expectedCode     int
+		expectedErrCode  string
+		expectedResponse GetObjectAttributesResponse
+	}{
+		// Test case - 1.
+		// Missing x-amz-object-attributes header.
+		{
+			objectName:      "small",
+			headers:         map[string]string{},
+			expectedCode:    http.StatusBadRequest,
+			expectedErrCode: "InvalidArgument",
+		},
+		// Test case - 2.
+		// Invalid attribute requested.
+		{
+			objectName:      "small",
+			headers:         withAttributes("ETag,Owner", nil),
+			expectedCode:    http.StatusBadRequest,
+			expectedErrCode: "InvalidArgument",
+		},
+		// Test case - 3.
+		// All attributes of a single part object, no parts are returned.
+		{
+			objectName:   "small",
+			headers:      withAttributes("ETag, Checksum, ObjectParts, StorageClass, ObjectSize", nil),
+			expectedCode: http.StatusOK,
+			expectedResponse: GetObjectAttributesResponse{
+				ETag:         hex.EncodeToString(dataMD5[:]),
+				Checksum:     &ObjectAttributesChecksum{ChecksumSHA256: checksum},
+				StorageClass: globalMinioDefaultStorageClass,
+				ObjectSize:   int64Ptr(509),
+			},
+		},
+		// Test case - 4.
+		// First page of the parts of a multipart object.
+		{
+			objectName:   "mp",
+			headers:      withAttributes("ObjectParts,ObjectSize", map[string]string{xhttp.AmzMaxParts: "2"}),
+			expectedCode: http.StatusOK,
+			expectedResponse: GetObjectAttributesResponse{
+				ObjectParts: &ObjectAttributesParts{
+					IsTruncated:          true,
+					MaxParts:             2,
+					NextPartNumberMarker: 2,
+					Parts: []ObjectAttributesPart{
+						{PartNumber: 1, Size: 5 * oneMiB},
+						{PartNumber: 2, Size: 5 * oneMiB},
+					},
+					PartsCount: 3,
+				},
+				ObjectSize: int64Ptr(10*oneMiB + 1),
+			},
+		},
+		// Test case - 5.
+		// Second page of the parts of a multipart object.
+		{
+			objectName: "mp",
+			headers: withAttributes("ObjectParts", map[string]string{
+				xhttp.AmzMaxParts:         "2",
+				xhttp.AmzPartNumberMarker: "2",
+			}),
+			expectedCode: http.StatusOK,
+			expectedResponse: GetObjectAttributesResponse{
+				ObjectParts: &ObjectAttributesParts{
+					MaxParts:             2,
+					NextPartNumberMarker: 3,
+					PartNumberMarker:     2,
+					Parts: []ObjectAttributesPart{
+						{PartNumber: 3, Size: 1},
+					},
+					PartsCount: 3,
+				},
+			},
+		},
+		// Test case - 6.
+		// Invalid max-parts.
+		{
+			objectName:      "mp",
+			headers:         withAttributes("ObjectParts", map[string]string{xhttp.AmzMaxParts: "-1"}),
+			expectedCode:    http.StatusBadRequest,
+			expectedErrCode: "InvalidArgument",
+		},
+		// Test case - 7.
+		// Invalid part-number-marker.
+		{
+			objectName:      "mp",
+			headers:         withAttributes("ObjectParts", map[string]string{xhttp.AmzPartNumberMarker: "abc"}),
+			expectedCode:    http.StatusBadRequest,
+			expectedErrCode: "InvalidArgument",
+		},
+		// Test case - 8.
+		// SSE-C encrypted object without the customer key.
+		{
+			objectName:   "enc",
+			headers:      withAttributes("ObjectSize", nil),
+			expectedCode: http.StatusBadRequest,
+		},
+		// Test case - 9.
+		// SSE-C encrypted object with the customer key.
+		{
+			objectName:   "enc",
+			headers:      withAttributes("ObjectSize", metaWithSSEC),
+			expectedCode: http.StatusOK,
+			expectedResponse: GetObjectAttributesResponse{
+				ObjectSize: int64Ptr(509),
+			},
+		},
+		// Test case - 10.
+		// Latest version of a versioned object.
+		{
+			objectName:   "versioned",
+			headers:      withAttributes("ETag,ObjectSize", nil),
+			expectedCode: http.StatusOK,
+			expectedResponse: GetObjectAttributesResponse{
+				ETag:       versions[1].ETag,
+				ObjectSize: int64Ptr(13),
+			},
+		},
+		// Test case - 11.
+		// Older version of a versioned object.
+		{
+			objectName:   "versioned",
+			versionID:    versions[0].VersionID,
+			headers:      withAttributes("ETag,ObjectSize", nil),
+			expectedCode: http.StatusOK,
+			expectedResponse: GetObjectAttributesResponse{
+				ETag:       versions[0].ETag,
+				ObjectSize: int64Ptr(11),
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		queryValues := url.Values{"attributes": []string{""}}
+		if testCase.versionID != "" {
+			queryValues.Set(xhttp.VersionID, testCase.versionID)
+		}
+		req, err := newTestSignedRequestV4(http.MethodGet, makeTestTargetURL("", bucketName, testCase.objectName, queryValues),
+			0, nil, credentials.AccessKey, credentials.SecretKey, testCase.headers)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request for Get Object Attributes: <ERROR> %v", i+1, instanceType, err)
+		}
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+
+		if rec.Code != testCase.expectedCode {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`: %s",
+				i+1, instanceType, testCase.expectedCode, rec.Code, rec.Body.String())
+		}
+		if rec.Code != http.StatusOK {
+			if testCase.expectedErrCode != "" {
+				errResp := APIErrorResponse{}
+				if err = xml.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+					t.Fatalf("Test %d: %s: Failed parsing error response: <ERROR> %v", i+1, instanceType, err)
+				}
+				if errResp.Code != testCase.expectedErrCode {
+					t.Errorf("Test %d: %s: Expected error code `%s`, but found `%s`", i+1, instanceType, testCase.expectedErrCode, errResp.Code)
+				}
+			}
+			continue
+		}
+
+		response := GetObjectAttributesResponse{}
+		if err = xml.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Test %d: %s: Failed parsing response: <ERROR> %v", i+1, instanceType, err)
+		}
+		response.XMLName = xml.Name{}
+		if !reflect.DeepEqual(response, testCase.expectedResponse) {
+			t.Errorf("Test %d: %s: Expected response %#v, but found %#v", i+1, instanceType, testCase.expectedResponse, response)
+		}
+		if testCase.objectName == "versioned" {
+			expectedVersionID := versions[len(versions)-1].VersionID
+			if testCase.versionID != "" {
+				expectedVersionID = testCase.versionID
+			}
+			if versionID := rec.Header().Get(xhttp.AmzVersionID); versionID != expectedVersionID {
+				t.Errorf("Test %d: %s: Expected version id `%s`, but found `%s`", i+1, instanceType, expectedVersionID, versionID)
+			}
+		}
+	}
+}
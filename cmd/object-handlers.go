@@ -46,7 +46,6 @@ import (
 	objectlock "github.com/infobsmi/b33s/internal/bucket/object/lock"
 	"github.com/infobsmi/b33s/internal/bucket/replication"
 	"github.com/infobsmi/b33s/internal/config/dns"
-	"github.com/infobsmi/b33s/internal/config/storageclass"
 	"github.com/infobsmi/b33s/internal/crypto"
 	"github.com/infobsmi/b33s/internal/etag"
 	"github.com/infobsmi/b33s/internal/event"
@@ -1083,7 +1082,7 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 
 	// Validate storage class metadata if present
 	dstSc := r.Header.Get(xhttp.AmzStorageClass)
-	if dstSc != "" && !storageclass.IsValid(dstSc) {
+	if dstSc != "" && !globalStorageClass.IsValid(dstSc) {
 		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidStorageClass), r.URL)
 		return
 	}
@@ -1653,7 +1652,7 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 
 	// Validate storage class metadata if present
 	if sc := r.Header.Get(xhttp.AmzStorageClass); sc != "" {
-		if !storageclass.IsValid(sc) {
+		if !globalStorageClass.IsValid(sc) {
 			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidStorageClass), r.URL)
 			return
 		}
@@ -2025,7 +2024,7 @@ func (api objectAPIHandlers) PutObjectExtractHandler(w http.ResponseWriter, r *h
 	// Validate storage class metadata if present
 	sc := r.Header.Get(xhttp.AmzStorageClass)
 	if sc != "" {
-		if !storageclass.IsValid(sc) {
+		if !globalStorageClass.IsValid(sc) {
 			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidStorageClass), r.URL)
 			return
 		}
@@ -3218,7 +3217,11 @@ func (api objectAPIHandlers) PostRestoreObjectHandler(w http.ResponseWriter, r *
 			VersionID: objInfo.VersionID,
 		}
 		if err := objectAPI.RestoreTransitionedObject(rctx, bucket, object, opts); err != nil {
-			logger.LogIf(ctx, err)
+			// Restores from archive tiers complete in the background,
+			// see archiveRestoreState.
+			if !errors.Is(err, errRestorePending) {
+				logger.LogIf(ctx, err)
+			}
 			return
 		}
 
@@ -38,7 +38,6 @@ import (
 	objectlock "github.com/infobsmi/b33s/internal/bucket/object/lock"
 	"github.com/infobsmi/b33s/internal/bucket/replication"
 	"github.com/infobsmi/b33s/internal/config/dns"
-	"github.com/infobsmi/b33s/internal/config/storageclass"
 	"github.com/infobsmi/b33s/internal/crypto"
 	"github.com/infobsmi/b33s/internal/etag"
 	"github.com/infobsmi/b33s/internal/event"
@@ -99,7 +98,7 @@ func (api objectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 
 	// Validate storage class metadata if present
 	if sc := r.Header.Get(xhttp.AmzStorageClass); sc != "" {
-		if !storageclass.IsValid(sc) {
+		if !globalStorageClass.IsValid(sc) {
 			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidStorageClass), r.URL)
 			return
 		}
@@ -320,6 +320,9 @@ func initAllSubsystems(ctx context.Context) {
 	// Create new bucket quota subsystem
 	globalBucketQuotaSys = NewBucketQuotaSys()
 
+	// Create new bucket access logging subsystem
+	globalBucketLoggingSys = NewBucketLoggingSys()
+
 	// Create new bucket versioning subsystem
 	if globalBucketVersioningSys == nil {
 		globalBucketVersioningSys = NewBucketVersioningSys()
@@ -647,6 +650,7 @@ func serverMain(ctx *cli.Context) {
 		// Initialize transition tier configuration manager
 		initBackgroundReplication(GlobalContext, newObject)
 		initBackgroundTransition(GlobalContext, newObject)
+		initBackgroundArchiveRestore(GlobalContext, newObject)
 
 		globalBatchJobPool = newBatchJobPool(GlobalContext, newObject, 100)
 
@@ -665,8 +669,14 @@ func serverMain(ctx *cli.Context) {
 		// Initialize quota manager.
 		globalBucketQuotaSys.Init(newObject)
 
+		// Initialize bucket access log delivery.
+		globalBucketLoggingSys.Init(GlobalContext, newObject)
+
 		initDataScanner(GlobalContext, newObject)
 
+		// Initialize bucket inventory reports.
+		initBucketInventory(GlobalContext, newObject)
+
 		// List buckets to heal, and be re-used for loading configs.
 		buckets, err := newObject.ListBuckets(GlobalContext, BucketOptions{})
 		if err != nil {
@@ -1330,6 +1330,101 @@ func (c *SiteReplicationSys) BucketMetaHook(ctx context.Context, item madmin.SRB
 	return errors.Unwrap(cerr)
 }
 
+// Bucket metadata types replicated to peer clusters in addition to the
+// ones known to madmin, their configuration is carried in the Policy
+// field of madmin.SRBucketMeta as a srBucketMetaConfig JSON document.
+const (
+	srBucketMetaTypeCorsConfig      = "cors-config"
+	srBucketMetaTypeWebsiteConfig   = "website-config"
+	srBucketMetaTypeLoggingConfig   = "logging-config"
+	srBucketMetaTypeInventoryConfig = "inventory-config"
+)
+
+// srBucketMetaConfigFiles maps the additional bucket metadata types to
+// the bucket metadata config file they update.
+var srBucketMetaConfigFiles = map[string]string{
+	srBucketMetaTypeCorsConfig:      bucketCorsConfig,
+	srBucketMetaTypeWebsiteConfig:   bucketWebsiteConfig,
+	srBucketMetaTypeLoggingConfig:   bucketLoggingConfig,
+	srBucketMetaTypeInventoryConfig: bucketInventoryConfig,
+}
+
+// srBucketMetaConfig - payload of the additional bucket metadata types,
+// a nil ConfigData removes the configuration on the peer.
+type srBucketMetaConfig struct {
+	ConfigData []byte `json:"configData,omitempty"`
+}
+
+// newSRBucketMetaConfig returns the site replication item for one of
+// the additional bucket metadata types.
+func newSRBucketMetaConfig(typ, bucket string, configData []byte, updatedAt time.Time) (madmin.SRBucketMeta, error) {
+	item := madmin.SRBucketMeta{
+		Type:      typ,
+		Bucket:    bucket,
+		UpdatedAt: updatedAt,
+	}
+	if configData == nil {
+		return item, nil
+	}
+	data, err := json.Marshal(srBucketMetaConfig{ConfigData: configData})
+	if err != nil {
+		return item, err
+	}
+	item.Policy = data
+	return item, nil
+}
+
+// bucketMetaConfigUpdatedAt returns the last update time of one of the
+// bucket configurations replicated through srBucketMetaConfig.
+func bucketMetaConfigUpdatedAt(bucket, configFile string) (updatedAt time.Time, err error) {
+	switch configFile {
+	case bucketCorsConfig:
+		_, updatedAt, err = globalBucketMetadataSys.GetCorsConfig(bucket)
+	case bucketWebsiteConfig:
+		_, updatedAt, err = globalBucketMetadataSys.GetWebsiteConfig(bucket)
+	case bucketLoggingConfig:
+		_, updatedAt, err = globalBucketMetadataSys.GetLoggingConfig(bucket)
+	case bucketInventoryConfig:
+		_, updatedAt, err = globalBucketMetadataSys.GetInventoryConfigs(bucket)
+	default:
+		err = errInvalidArgument
+	}
+	return updatedAt, err
+}
+
+// PeerBucketMetaConfigHandler - copies/deletes one of the additional bucket
+// metadata configurations to local cluster.
+func (c *SiteReplicationSys) PeerBucketMetaConfigHandler(ctx context.Context, item madmin.SRBucketMeta) error {
+	configFile, ok := srBucketMetaConfigFiles[item.Type]
+	if !ok {
+		return errSRInvalidRequest(errInvalidArgument)
+	}
+
+	// skip overwrite if local update is newer than peer update.
+	if !item.UpdatedAt.IsZero() {
+		if updateTm, err := bucketMetaConfigUpdatedAt(item.Bucket, configFile); err == nil && updateTm.After(item.UpdatedAt) {
+			return nil
+		}
+	}
+
+	if item.Policy != nil {
+		var cfg srBucketMetaConfig
+		if err := json.Unmarshal(item.Policy, &cfg); err != nil {
+			return wrapSRErr(err)
+		}
+		if _, err := globalBucketMetadataSys.Update(ctx, item.Bucket, configFile, cfg.ConfigData); err != nil {
+			return wrapSRErr(err)
+		}
+		return nil
+	}
+
+	// Delete the configuration
+	if _, err := globalBucketMetadataSys.Delete(ctx, item.Bucket, configFile); err != nil {
+		return wrapSRErr(err)
+	}
+	return nil
+}
+
 // PeerBucketVersioningHandler - updates versioning config to local cluster.
 func (c *SiteReplicationSys) PeerBucketVersioningHandler(ctx context.Context, bucket string, versioning *string, updatedAt time.Time) error {
 	if versioning != nil {
@@ -1702,6 +1797,94 @@ func (c *SiteReplicationSys) syncToAllPeers(ctx context.Context) error {
 				return errSRBucketMetaError(err)
 			}
 		}
+
+		// Replicate existing bucket CORS configuration
+		corsConfig, tm, err := globalBucketMetadataSys.GetCorsConfig(bucket)
+		found = true
+		if _, ok := err.(BucketCorsNotFound); ok {
+			found = false
+		} else if err != nil {
+			return errSRBackendIssue(err)
+		}
+		if found {
+			corsConfigData, err := xml.Marshal(corsConfig)
+			if err != nil {
+				return wrapSRErr(err)
+			}
+			item, err := newSRBucketMetaConfig(srBucketMetaTypeCorsConfig, bucket, corsConfigData, tm)
+			if err != nil {
+				return wrapSRErr(err)
+			}
+			if err = c.BucketMetaHook(ctx, item); err != nil {
+				return errSRBucketMetaError(err)
+			}
+		}
+
+		// Replicate existing bucket website configuration
+		websiteConfig, tm, err := globalBucketMetadataSys.GetWebsiteConfig(bucket)
+		found = true
+		if _, ok := err.(BucketWebsiteNotFound); ok {
+			found = false
+		} else if err != nil {
+			return errSRBackendIssue(err)
+		}
+		if found {
+			websiteConfigData, err := xml.Marshal(websiteConfig)
+			if err != nil {
+				return wrapSRErr(err)
+			}
+			item, err := newSRBucketMetaConfig(srBucketMetaTypeWebsiteConfig, bucket, websiteConfigData, tm)
+			if err != nil {
+				return wrapSRErr(err)
+			}
+			if err = c.BucketMetaHook(ctx, item); err != nil {
+				return errSRBucketMetaError(err)
+			}
+		}
+
+		// Replicate existing bucket logging configuration
+		loggingConfig, tm, err := globalBucketMetadataSys.GetLoggingConfig(bucket)
+		found = true
+		if _, ok := err.(BucketLoggingNotFound); ok {
+			found = false
+		} else if err != nil {
+			return errSRBackendIssue(err)
+		}
+		if found {
+			loggingConfigData, err := xml.Marshal(loggingConfig)
+			if err != nil {
+				return wrapSRErr(err)
+			}
+			item, err := newSRBucketMetaConfig(srBucketMetaTypeLoggingConfig, bucket, loggingConfigData, tm)
+			if err != nil {
+				return wrapSRErr(err)
+			}
+			if err = c.BucketMetaHook(ctx, item); err != nil {
+				return errSRBucketMetaError(err)
+			}
+		}
+
+		// Replicate existing bucket inventory configurations
+		inventoryConfigs, tm, err := globalBucketMetadataSys.GetInventoryConfigs(bucket)
+		found = true
+		if _, ok := err.(BucketInventoryNotFound); ok {
+			found = false
+		} else if err != nil {
+			return errSRBackendIssue(err)
+		}
+		if found {
+			inventoryConfigData, err := xml.Marshal(inventoryConfigs)
+			if err != nil {
+				return wrapSRErr(err)
+			}
+			item, err := newSRBucketMetaConfig(srBucketMetaTypeInventoryConfig, bucket, inventoryConfigData, tm)
+			if err != nil {
+				return wrapSRErr(err)
+			}
+			if err = c.BucketMetaHook(ctx, item); err != nil {
+				return errSRBucketMetaError(err)
+			}
+		}
 	}
 
 	// Order matters from now on how the information is
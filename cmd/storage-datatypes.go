@@ -126,6 +126,9 @@ func (f *FileInfoVersions) findVersionIndex(v string) int {
 	if f == nil || v == "" {
 		return -1
 	}
+	if v == nullVersionID {
+		v = ""
+	}
 	for i, ver := range f.Versions {
 		if ver.VersionID == v {
 			return i
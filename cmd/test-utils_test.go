@@ -1963,6 +1963,9 @@ func registerBucketLevelFunc(bucket *mux.Router, api objectAPIHandlers, apiFunct
 		case "GetObject":
 			// Register GetObject handler.
 			bucket.Methods(http.MethodGet).Path("/{object:.+}").HandlerFunc(api.GetObjectHandler)
+		case "GetObjectAttributes":
+			// Register GetObjectAttributes handler.
+			bucket.Methods(http.MethodGet).Path("/{object:.+}").HandlerFunc(api.GetObjectAttributesHandler).Queries("attributes", "")
 		case "PutObject":
 			// Register PutObject handler.
 			bucket.Methods(http.MethodPut).Path("/{object:.+}").HandlerFunc(api.PutObjectHandler)
@@ -18,6 +18,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -61,6 +62,12 @@ var (
 		Message:    "Invalid remote tier credentials",
 		StatusCode: http.StatusBadRequest,
 	}
+	// error returned when editing the credentials of a tier without credentials.
+	errTierNoCredentials = AdminError{
+		Code:       "XMinioAdminTierNoCredentials",
+		Message:    "Specified remote tier has no credentials",
+		StatusCode: http.StatusBadRequest,
+	}
 	// error returned when reserved internal names are used.
 	errTierReservedName = AdminError{
 		Code:       "XMinioAdminTierReserved",
@@ -69,6 +76,15 @@ var (
 	}
 )
 
+// tierFSConfig is the admin API representation of a filesystem tier, it
+// follows the layout of madmin.TierConfig.
+type tierFSConfig struct {
+	Version string  `json:",omitempty"`
+	Type    string  `json:",omitempty"`
+	Name    string  `json:",omitempty"`
+	FS      *TierFS `json:",omitempty"`
+}
+
 func (api adminAPIHandlers) AddTierHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := newContext(r, w, "AddTier")
 
@@ -87,9 +103,20 @@ func (api adminAPIHandlers) AddTierHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	var cfg madmin.TierConfig
+	var (
+		cfg   madmin.TierConfig
+		fsCfg tierFSConfig
+	)
 	json := jsoniter.ConfigCompatibleWithStandardLibrary
-	if err := json.Unmarshal(reqBytes, &cfg); err != nil {
+	// Filesystem tiers have no madmin.TierType, look for them first.
+	if err := json.Unmarshal(reqBytes, &fsCfg); err == nil && fsCfg.Type == tierTypeFS {
+		if fsCfg.FS == nil {
+			writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, madmin.ErrTierInvalidConfig), r.URL)
+			return
+		}
+		fsCfg.FS.Name = fsCfg.Name
+		cfg.Name = fsCfg.Name
+	} else if err := json.Unmarshal(reqBytes, &cfg); err != nil {
 		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
 		return
 	}
@@ -100,6 +127,11 @@ func (api adminAPIHandlers) AddTierHandler(w http.ResponseWriter, r *http.Reques
 		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, errTierReservedName), r.URL)
 		return
 	}
+	// Disallow remote tiers shadowing custom storage classes
+	if globalStorageClass.IsCustom(cfg.Name) {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, errTierReservedName), r.URL)
+		return
+	}
 
 	// Refresh from the disk in case we had missed notifications about edits from peers.
 	if err := globalTierConfigMgr.Reload(ctx, objAPI); err != nil {
@@ -107,7 +139,11 @@ func (api adminAPIHandlers) AddTierHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	err = globalTierConfigMgr.Add(ctx, cfg)
+	if fsCfg.FS != nil {
+		err = globalTierConfigMgr.AddFS(ctx, *fsCfg.FS)
+	} else {
+		err = globalTierConfigMgr.Add(ctx, cfg)
+	}
 	if err != nil {
 		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
 		return
@@ -134,7 +170,12 @@ func (api adminAPIHandlers) ListTierHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	tiers := globalTierConfigMgr.ListTiers()
+	var tiers interface{}
+	if r.Form.Get("type") == tierTypeFS {
+		tiers = globalTierConfigMgr.ListFSTiers()
+	} else {
+		tiers = globalTierConfigMgr.ListAllTiers()
+	}
 	data, err := json.Marshal(tiers)
 	if err != nil {
 		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
@@ -202,23 +243,17 @@ func (api adminAPIHandlers) RemoveTierHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	vars := mux.Vars(r)
-	tier := vars["tier"]
-	if err := globalTierConfigMgr.Reload(ctx, objAPI); err != nil {
-		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
-		return
-	}
-
-	if err := globalTierConfigMgr.Remove(ctx, tier); err != nil {
-		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+	// Tiers are removed by the node running the migrations, which
+	// checks that none moves versions from or to the tier.
+	if proxyTierMigrationRequest(ctx, w, r) {
 		return
 	}
 
-	if err := globalTierConfigMgr.Save(ctx, objAPI); err != nil {
+	vars := mux.Vars(r)
+	if err := removeTier(ctx, objAPI, vars["tier"], nil); err != nil {
 		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
 		return
 	}
-	globalNotificationSys.LoadTransitionTierConfig(ctx)
 
 	writeSuccessNoContent(w)
 }
@@ -272,3 +307,112 @@ func (api adminAPIHandlers) TierStatsHandler(w http.ResponseWriter, r *http.Requ
 	}
 	writeSuccessResponseJSON(w, data)
 }
+
+// proxyTierMigrationRequest proxies tier migration and removal requests to
+// the first node of the first pool, which runs all tier migrations. It
+// returns true if the request was proxied.
+func proxyTierMigrationRequest(ctx context.Context, w http.ResponseWriter, r *http.Request) bool {
+	ep := globalEndpoints[0].Endpoints[0]
+	if ep.IsLocal {
+		return false
+	}
+	for nodeIdx, proxyEp := range globalProxyEndpoints {
+		if proxyEp.Endpoint.Host == ep.Host {
+			return proxyRequestByNodeIndex(ctx, w, r, nodeIdx)
+		}
+	}
+	return false
+}
+
+// StartTierMigrationHandler - POST /minio/admin/v3/tier/{tier}/migrate?to={tier}&remove={bool}
+// ----------
+// Starts moving all objects transitioned to a tier over to another tier. If
+// remove is true, the tier is removed once all its objects were moved.
+func (api adminAPIHandlers) StartTierMigrationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "StartTierMigration")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI, _ := validateAdminReq(ctx, w, r, iampolicy.SetTierAction)
+	if objAPI == nil || globalNotificationSys == nil || globalTierConfigMgr == nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+	if proxyTierMigrationRequest(ctx, w, r) {
+		return
+	}
+
+	vars := mux.Vars(r)
+	// Refresh from the disk in case we had missed notifications about edits from peers.
+	if err := globalTierConfigMgr.Reload(ctx, objAPI); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	remove := r.Form.Get("remove") == "true"
+	if err := globalTierMigrations.Start(GlobalContext, objAPI, vars["tier"], vars["to"], remove); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessNoContent(w)
+}
+
+// TierMigrationStatusHandler - GET /minio/admin/v3/tier/{tier}/migrate
+// ----------
+// Returns the progress of the last migration of a tier.
+func (api adminAPIHandlers) TierMigrationStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "TierMigrationStatus")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI, _ := validateAdminReq(ctx, w, r, iampolicy.ListTierAction)
+	if objAPI == nil || globalNotificationSys == nil || globalTierConfigMgr == nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+	if proxyTierMigrationRequest(ctx, w, r) {
+		return
+	}
+
+	vars := mux.Vars(r)
+	status, err := globalTierMigrations.Status(vars["tier"])
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	data, err := json.Marshal(status)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+	writeSuccessResponseJSON(w, data)
+}
+
+// StopTierMigrationHandler - DELETE /minio/admin/v3/tier/{tier}/migrate
+// ----------
+// Stops the migration of a tier, objects already migrated stay on the
+// destination tier.
+func (api adminAPIHandlers) StopTierMigrationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "StopTierMigration")
+
+	defer logger.AuditLog(ctx, w, r, mustGetClaimsFromToken(r))
+
+	objAPI, _ := validateAdminReq(ctx, w, r, iampolicy.SetTierAction)
+	if objAPI == nil || globalNotificationSys == nil || globalTierConfigMgr == nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+	if proxyTierMigrationRequest(ctx, w, r) {
+		return
+	}
+
+	vars := mux.Vars(r)
+	if err := globalTierMigrations.Stop(vars["tier"]); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessNoContent(w)
+}
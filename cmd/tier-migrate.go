@@ -0,0 +1,422 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/infobsmi/b33s/internal/bucket/lifecycle"
+	"github.com/infobsmi/b33s/internal/logger"
+)
+
+var (
+	errTierMigrationInProgress = AdminError{
+		Code:       "XMinioAdminTierMigrationInProgress",
+		Message:    "Specified remote tier is being migrated",
+		StatusCode: http.StatusConflict,
+	}
+
+	errTierMigrationNotFound = AdminError{
+		Code:       "XMinioAdminNoSuchTierMigration",
+		Message:    "Specified remote tier was not migrated",
+		StatusCode: http.StatusNotFound,
+	}
+
+	errTierMigrationSameTier = AdminError{
+		Code:       "XMinioAdminTierMigrationSameTier",
+		Message:    "Remote tier can't be migrated to itself",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	errTierInUseByLifecycle = AdminError{
+		Code:       "XMinioAdminTierInUseByLifecycle",
+		Message:    "Specified remote tier is used by bucket lifecycle rules",
+		StatusCode: http.StatusConflict,
+	}
+)
+
+// errTierObjectRestored is returned when migrating a version which has a
+// restored copy, it is migrated once the restored copy expired.
+var errTierObjectRestored = errors.New("transitioned object has a restored copy")
+
+// tierMigrationWorkers is the number of versions migrated in parallel.
+const tierMigrationWorkers = 4
+
+// The copies left on the source tier are deleted in the background via the
+// tier journal, its removal is retried until they are gone.
+const (
+	tierMigrationRemoveRetries  = 10
+	tierMigrationRemoveInterval = 5 * time.Second
+)
+
+const (
+	tierMigrationRunning   = "running"
+	tierMigrationRemoving  = "removing"
+	tierMigrationCompleted = "completed"
+	tierMigrationFailed    = "failed"
+	tierMigrationStopped   = "stopped"
+)
+
+// tierMigrationStatus is the progress of a tier migration.
+type tierMigrationStatus struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Status    string    `json:"status"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime,omitempty"`
+	Bucket    string    `json:"bucket,omitempty"` // bucket being migrated
+	Objects   uint64    `json:"objects"`          // versions migrated
+	Bytes     uint64    `json:"bytes"`
+	Skipped   uint64    `json:"skipped"` // versions with a restored copy
+	Failed    uint64    `json:"failed"`
+	Remove    bool      `json:"remove,omitempty"`  // remove the source tier once migrated
+	Removed   bool      `json:"removed,omitempty"` // source tier was removed
+	Error     string    `json:"error,omitempty"`
+}
+
+// tierMigration moves all versions transitioned to a tier over to another
+// tier, so the former can be removed. Migrations are idempotent, a stopped
+// or failed migration is continued by starting it again.
+type tierMigration struct {
+	mu     sync.Mutex
+	status tierMigrationStatus
+	cancel context.CancelFunc
+}
+
+// tierMigrations tracks the migrations started on this node, by source tier.
+// Migrations are run by the first node of the first pool, see
+// proxyTierMigrationRequest.
+type tierMigrations struct {
+	sync.Mutex
+	migrations map[string]*tierMigration
+}
+
+var globalTierMigrations = &tierMigrations{
+	migrations: make(map[string]*tierMigration),
+}
+
+// Start starts migrating the versions transitioned to tier from over to
+// tier to. If remove is set, tier from is removed once all its versions
+// were migrated.
+func (m *tierMigrations) Start(ctx context.Context, objAPI ObjectLayer, from, to string, remove bool) error {
+	// Versions transitioned to tier from while it is migrated would be
+	// left behind.
+	if err := checkTierLifecycleRefs(ctx, objAPI, from); err != nil {
+		return err
+	}
+	t, ctx, err := m.start(ctx, from, to, remove)
+	if err != nil {
+		return err
+	}
+	go t.run(ctx, objAPI)
+	return nil
+}
+
+// start registers a new migration and returns it with the context to run
+// it with, which is canceled by Stop.
+func (m *tierMigrations) start(ctx context.Context, from, to string, remove bool) (*tierMigration, context.Context, error) {
+	if from == to {
+		return nil, nil, errTierMigrationSameTier
+	}
+
+	// Tiers are removed with m locked, see TierConfigMgr.Remove.
+	m.Lock()
+	defer m.Unlock()
+	if !globalTierConfigMgr.IsTierValid(from) || !globalTierConfigMgr.IsTierValid(to) {
+		return nil, nil, errTierNotFound
+	}
+	for _, t := range m.migrations {
+		st := t.Status()
+		if !st.active() {
+			continue
+		}
+		// A tier can't be moved while it receives or loses versions.
+		if st.From == from || st.From == to || st.To == from {
+			return nil, nil, errTierMigrationInProgress
+		}
+	}
+
+	t := &tierMigration{
+		status: tierMigrationStatus{
+			From:      from,
+			To:        to,
+			Status:    tierMigrationRunning,
+			StartTime: UTCNow(),
+			Remove:    remove,
+		},
+	}
+	ctx, t.cancel = context.WithCancel(ctx)
+	m.migrations[from] = t
+	return t, ctx, nil
+}
+
+// busy returns true if a migration other than self moves versions from or
+// to tier, or removes it, m must be locked.
+func (m *tierMigrations) busy(tier string, self *tierMigration) bool {
+	for _, t := range m.migrations {
+		if t == self {
+			continue
+		}
+		st := t.Status()
+		if st.active() && (st.From == tier || st.To == tier) {
+			return true
+		}
+	}
+	return false
+}
+
+// Status returns the progress of the last migration of tier.
+func (m *tierMigrations) Status(tier string) (tierMigrationStatus, error) {
+	m.Lock()
+	t, ok := m.migrations[tier]
+	m.Unlock()
+	if !ok {
+		return tierMigrationStatus{}, errTierMigrationNotFound
+	}
+	return t.Status(), nil
+}
+
+// Stop stops the migration of tier, versions already migrated stay on the
+// destination tier.
+func (m *tierMigrations) Stop(tier string) error {
+	m.Lock()
+	t, ok := m.migrations[tier]
+	m.Unlock()
+	if !ok {
+		return errTierMigrationNotFound
+	}
+	t.cancel()
+	return nil
+}
+
+// active returns true until the migration and the removal of the source
+// tier are over.
+func (st tierMigrationStatus) active() bool {
+	return st.Status == tierMigrationRunning || st.Status == tierMigrationRemoving
+}
+
+func (t *tierMigration) Status() tierMigrationStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+func (t *tierMigration) run(ctx context.Context, objAPI ObjectLayer) {
+	defer t.cancel()
+
+	buckets, err := objAPI.ListBuckets(ctx, BucketOptions{})
+	if err == nil {
+		for _, bi := range buckets {
+			if err = t.migrateBucket(ctx, objAPI, bi.Name); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil && ctx.Err() == nil {
+		logger.LogIf(ctx, fmt.Errorf("Unable to migrate remote tier %s to %s: %w", t.status.From, t.status.To, err))
+	}
+
+	t.mu.Lock()
+	t.status.Bucket = ""
+	switch {
+	case ctx.Err() != nil:
+		t.status.Status = tierMigrationStopped
+	case err != nil:
+		t.status.Status = tierMigrationFailed
+		t.status.Error = err.Error()
+	case t.status.Failed > 0:
+		t.status.Status = tierMigrationFailed
+	case t.status.Remove && t.status.Skipped > 0:
+		// versions with a restored copy are left on the source tier.
+		t.status.Status = tierMigrationCompleted
+		t.status.Error = errTierBackendNotEmpty.Message
+	case t.status.Remove:
+		t.status.Status = tierMigrationRemoving
+	default:
+		t.status.Status = tierMigrationCompleted
+	}
+	remove := t.status.Status == tierMigrationRemoving
+	if !remove {
+		t.status.EndTime = UTCNow()
+	}
+	t.mu.Unlock()
+	if !remove {
+		return
+	}
+
+	err = t.removeSource(ctx, objAPI)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.status.EndTime = UTCNow()
+	switch {
+	case ctx.Err() != nil:
+		t.status.Status = tierMigrationStopped
+	case err != nil:
+		t.status.Status = tierMigrationFailed
+		t.status.Error = err.Error()
+	default:
+		t.status.Status = tierMigrationCompleted
+		t.status.Removed = true
+	}
+}
+
+// removeSource removes the migrated tier, once the copies left on it were
+// deleted.
+func (t *tierMigration) removeSource(ctx context.Context, objAPI ObjectLayer) (err error) {
+	for i := 0; i < tierMigrationRemoveRetries; i++ {
+		if err = removeTier(ctx, objAPI, t.status.From, t); err != errTierBackendNotEmpty {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(tierMigrationRemoveInterval):
+		}
+	}
+	return err
+}
+
+func (t *tierMigration) migrateBucket(ctx context.Context, objAPI ObjectLayer, bucket string) error {
+	t.mu.Lock()
+	t.status.Bucket = bucket
+	from, to := t.status.From, t.status.To
+	t.mu.Unlock()
+
+	results := make(chan ObjectInfo, 100)
+	err := objAPI.Walk(ctx, bucket, "", results, ObjectOptions{
+		WalkFilter: func(fi FileInfo) bool {
+			return fi.TransitionStatus == lifecycle.TransitionComplete && fi.TransitionTier == from
+		},
+	})
+	if err != nil {
+		if isErrBucketNotFound(err) {
+			// bucket was deleted since it was listed.
+			return nil
+		}
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < tierMigrationWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for oi := range results {
+				t.update(ctx, oi, migrateTransitionedObject(ctx, objAPI, oi, from, to))
+			}
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// update records the result of migrating oi.
+func (t *tierMigration) update(ctx context.Context, oi ObjectInfo, err error) {
+	if err != nil && (isErrObjectNotFound(err) || isErrVersionNotFound(err) || ctx.Err() != nil) {
+		// version was deleted or overwritten since it was listed, or
+		// the migration was stopped.
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case err == nil:
+		t.status.Objects++
+		t.status.Bytes += uint64(oi.Size)
+	case errors.Is(err, errTierObjectRestored):
+		t.status.Skipped++
+	default:
+		t.status.Failed++
+		logger.LogIf(ctx, err)
+	}
+}
+
+// lifecycleUsesTier returns true if a rule of lc transitions versions to tier.
+func lifecycleUsesTier(lc *lifecycle.Lifecycle, tier string) bool {
+	for _, rule := range lc.Rules {
+		if rule.Transition.StorageClass == tier || rule.NoncurrentVersionTransition.StorageClass == tier {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTierLifecycleRefs returns errTierInUseByLifecycle if the lifecycle
+// rules of a bucket transition versions to tier.
+func checkTierLifecycleRefs(ctx context.Context, objAPI ObjectLayer, tier string) error {
+	buckets, err := objAPI.ListBuckets(ctx, BucketOptions{})
+	if err != nil {
+		return err
+	}
+	for _, bi := range buckets {
+		lc, err := globalLifecycleSys.Get(bi.Name)
+		if err != nil {
+			if _, ok := err.(BucketLifecycleNotFound); ok {
+				continue
+			}
+			return err
+		}
+		if lifecycleUsesTier(lc, tier) {
+			return errTierInUseByLifecycle
+		}
+	}
+	return nil
+}
+
+// removeTier removes tier from the tier config of the cluster, unless
+// versions, lifecycle rules or migrations other than self still refer
+// to it.
+func removeTier(ctx context.Context, objAPI ObjectLayer, tier string, self *tierMigration) error {
+	if err := checkTierLifecycleRefs(ctx, objAPI, tier); err != nil {
+		return err
+	}
+	// Refresh from the disk in case we had missed notifications about edits from peers.
+	if err := globalTierConfigMgr.Reload(ctx, objAPI); err != nil {
+		return err
+	}
+	if err := globalTierConfigMgr.remove(ctx, tier, self); err != nil {
+		return err
+	}
+	if err := globalTierConfigMgr.Save(ctx, objAPI); err != nil {
+		return err
+	}
+	globalNotificationSys.LoadTransitionTierConfig(ctx)
+	return nil
+}
+
+// migrateTransitionedObject moves the version oi transitioned to tier from
+// over to tier to, unless it was modified since it was listed.
+func migrateTransitionedObject(ctx context.Context, objAPI ObjectLayer, oi ObjectInfo, from, to string) error {
+	opts := ObjectOptions{
+		Transition: TransitionOptions{
+			Tier:     to,
+			ETag:     oi.ETag,
+			FromTier: from,
+		},
+		VersionID:        oi.VersionID,
+		Versioned:        globalBucketVersioningSys.PrefixEnabled(oi.Bucket, oi.Name),
+		VersionSuspended: globalBucketVersioningSys.PrefixSuspended(oi.Bucket, oi.Name),
+		MTime:            oi.ModTime,
+	}
+	return objAPI.TransitionObject(ctx, oi.Bucket, oi.Name, opts)
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/infobsmi/b33s/internal/bucket/lifecycle"
+)
+
+func TestTierMigrationStart(t *testing.T) {
+	oldTierConfigMgr := globalTierConfigMgr
+	defer func() { globalTierConfigMgr = oldTierConfigMgr }()
+	globalTierConfigMgr = NewTierConfigMgr()
+	for _, tier := range []string{"WARM", "COLD", "ARCHIVE"} {
+		globalTierConfigMgr.FSTiers[tier] = TierFS{Name: tier, Path: "/mnt/" + tier}
+	}
+
+	ctx := context.Background()
+	m := &tierMigrations{migrations: make(map[string]*tierMigration)}
+	testCases := []struct {
+		from, to string
+		err      error
+	}{
+		{from: "WARM", to: "WARM", err: errTierMigrationSameTier},
+		{from: "WARM", to: "UNKNOWN", err: errTierNotFound},
+		{from: "UNKNOWN", to: "WARM", err: errTierNotFound},
+		{from: "WARM", to: "COLD"},
+		{from: "WARM", to: "ARCHIVE", err: errTierMigrationInProgress},
+		{from: "COLD", to: "ARCHIVE", err: errTierMigrationInProgress},
+		{from: "ARCHIVE", to: "WARM", err: errTierMigrationInProgress},
+		{from: "ARCHIVE", to: "COLD"},
+	}
+	for i, tc := range testCases {
+		_, _, err := m.start(ctx, tc.from, tc.to, false)
+		if !errors.Is(err, tc.err) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, tc.err, err)
+		}
+	}
+
+	if err := m.Stop("WARM"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Stop("COLD"); !errors.Is(err, errTierMigrationNotFound) {
+		t.Fatalf("expected %v, got %v", errTierMigrationNotFound, err)
+	}
+	if _, err := m.Status("COLD"); !errors.Is(err, errTierMigrationNotFound) {
+		t.Fatalf("expected %v, got %v", errTierMigrationNotFound, err)
+	}
+}
+
+func TestTierMigrationRun(t *testing.T) {
+	oldTierConfigMgr := globalTierConfigMgr
+	defer func() { globalTierConfigMgr = oldTierConfigMgr }()
+	globalTierConfigMgr = NewTierConfigMgr()
+	for _, tier := range []string{"WARM", "COLD"} {
+		globalTierConfigMgr.FSTiers[tier] = TierFS{Name: tier, Path: "/mnt/" + tier}
+	}
+
+	m := &tierMigrations{migrations: make(map[string]*tierMigration)}
+	mig, ctx, err := m.start(context.Background(), "WARM", "COLD", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mig.update(ctx, ObjectInfo{Size: 10}, nil)
+	mig.update(ctx, ObjectInfo{Size: 20}, nil)
+	mig.update(ctx, ObjectInfo{Size: 30}, errTierObjectRestored)
+	mig.update(ctx, ObjectInfo{Size: 40}, ObjectNotFound{})
+	mig.update(ctx, ObjectInfo{Size: 50}, errors.New("remote tier unavailable"))
+
+	st, err := m.Status("WARM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Status != tierMigrationRunning || st.Objects != 2 || st.Bytes != 30 || st.Skipped != 1 || st.Failed != 1 {
+		t.Fatalf("unexpected migration status %+v", st)
+	}
+
+	// Once stopped, failures are not counted anymore.
+	m.Stop("WARM")
+	mig.update(ctx, ObjectInfo{Size: 60}, context.Canceled)
+	if st = mig.Status(); st.Failed != 1 {
+		t.Fatalf("expected 1 failed version, got %d", st.Failed)
+	}
+}
+
+func TestTierRemoveMigrating(t *testing.T) {
+	oldTierConfigMgr, oldTierMigrations := globalTierConfigMgr, globalTierMigrations
+	defer func() { globalTierConfigMgr, globalTierMigrations = oldTierConfigMgr, oldTierMigrations }()
+	globalTierConfigMgr = NewTierConfigMgr()
+	for _, tier := range []string{"WARM", "COLD", "ARCHIVE"} {
+		globalTierConfigMgr.FSTiers[tier] = TierFS{Name: tier, Path: t.TempDir()}
+	}
+	globalTierMigrations = &tierMigrations{migrations: make(map[string]*tierMigration)}
+
+	ctx := context.Background()
+	if _, _, err := globalTierMigrations.start(ctx, "WARM", "COLD", false); err != nil {
+		t.Fatal(err)
+	}
+	for _, tier := range []string{"WARM", "COLD"} {
+		if err := globalTierConfigMgr.Remove(ctx, tier); !errors.Is(err, errTierMigrationInProgress) {
+			t.Fatalf("%s: expected %v, got %v", tier, errTierMigrationInProgress, err)
+		}
+	}
+	if err := globalTierConfigMgr.Remove(ctx, "ARCHIVE"); err != nil {
+		t.Fatal(err)
+	}
+	// A removed tier can't be migrated to.
+	if _, _, err := globalTierMigrations.start(ctx, "COLD", "ARCHIVE", false); !errors.Is(err, errTierNotFound) {
+		t.Fatalf("expected %v, got %v", errTierNotFound, err)
+	}
+}
+
+func TestTierMigrationRemoving(t *testing.T) {
+	oldTierConfigMgr, oldTierMigrations := globalTierConfigMgr, globalTierMigrations
+	defer func() { globalTierConfigMgr, globalTierMigrations = oldTierConfigMgr, oldTierMigrations }()
+	globalTierConfigMgr = NewTierConfigMgr()
+	for _, tier := range []string{"WARM", "COLD", "ARCHIVE"} {
+		globalTierConfigMgr.FSTiers[tier] = TierFS{Name: tier, Path: t.TempDir()}
+	}
+	globalTierMigrations = &tierMigrations{migrations: make(map[string]*tierMigration)}
+
+	ctx := context.Background()
+	mig, _, err := globalTierMigrations.start(ctx, "WARM", "COLD", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mig.status.Status = tierMigrationRemoving
+
+	// The migration removing its source tier can't be replaced.
+	if _, _, err = globalTierMigrations.start(ctx, "WARM", "ARCHIVE", false); !errors.Is(err, errTierMigrationInProgress) {
+		t.Fatalf("expected %v, got %v", errTierMigrationInProgress, err)
+	}
+	if err = globalTierConfigMgr.Remove(ctx, "WARM"); !errors.Is(err, errTierMigrationInProgress) {
+		t.Fatalf("expected %v, got %v", errTierMigrationInProgress, err)
+	}
+	if err = globalTierConfigMgr.remove(ctx, "WARM", mig); err != nil {
+		t.Fatal(err)
+	}
+	if st, err := globalTierMigrations.Status("WARM"); err != nil || st.To != "COLD" {
+		t.Fatalf("expected the migration to COLD, got %+v, %v", st, err)
+	}
+}
+
+func TestLifecycleUsesTier(t *testing.T) {
+	lc := &lifecycle.Lifecycle{
+		Rules: []lifecycle.Rule{
+			{ID: "current", Transition: lifecycle.Transition{StorageClass: "WARM"}},
+			{ID: "noncurrent", NoncurrentVersionTransition: lifecycle.NoncurrentVersionTransition{StorageClass: "COLD"}},
+		},
+	}
+	for tier, used := range map[string]bool{"WARM": true, "COLD": true, "ARCHIVE": false} {
+		if got := lifecycleUsesTier(lc, tier); got != used {
+			t.Errorf("%s: expected %v, got %v", tier, used, got)
+		}
+	}
+}
+
+func TestMigrateTransitionedObject(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	objLayer, fsDirs, err := prepareErasure16(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := objLayer.(*erasureServerPools).serverPools[0].sets[0]
+
+	initAllSubsystems(ctx)
+
+	oldTierConfigMgr, oldTierJournal := globalTierConfigMgr, globalTierJournal
+	defer func() { globalTierConfigMgr, globalTierJournal = oldTierConfigMgr, oldTierJournal }()
+	globalTierConfigMgr = NewTierConfigMgr()
+	for _, tier := range []string{"WARM", "COLD"} {
+		globalTierConfigMgr.FSTiers[tier] = TierFS{Name: tier, Path: t.TempDir()}
+	}
+	globalTierJournal = &tierJournal{
+		tierMemJournal:  newTierMemJoural(10),
+		tierDiskJournal: newTierDiskJournal(),
+	}
+
+	bucket, object := "bucket", "object"
+	if err = objLayer.MakeBucketWithLocation(ctx, bucket, MakeBucketOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("a"), 1<<20)
+	oi, err := xl.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := ObjectOptions{
+		Transition: TransitionOptions{Tier: "WARM", ETag: oi.ETag},
+		MTime:      oi.ModTime,
+	}
+	if err = xl.TransitionObject(ctx, bucket, object, opts); err != nil {
+		t.Fatal(err)
+	}
+	warm, _, _, err := xl.getObjectFileInfo(ctx, bucket, object, ObjectOptions{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts.Transition = TransitionOptions{Tier: "COLD", ETag: oi.ETag, FromTier: "WARM"}
+	if err = xl.TransitionObject(ctx, bucket, object, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	// All the drives point the version to its copy on the new tier.
+	metaArr, errs := readAllFileInfo(ctx, xl.getDisks(), bucket, object, "", false)
+	for i, fi := range metaArr {
+		if errs[i] != nil {
+			t.Fatalf("drive %d: %v", i, errs[i])
+		}
+		if fi.TransitionStatus != lifecycle.TransitionComplete || fi.TransitionTier != "COLD" ||
+			fi.TransitionedObjName == "" || fi.TransitionedObjName == warm.TransitionedObjName {
+			t.Fatalf("drive %d: unexpected transition status %s, tier %s, object %s", i, fi.TransitionStatus, fi.TransitionTier, fi.TransitionedObjName)
+		}
+		if !fi.ModTime.Equal(oi.ModTime) || fi.Size != oi.Size {
+			t.Fatalf("drive %d: expected the version to be kept, got modtime %v size %d", i, fi.ModTime, fi.Size)
+		}
+	}
+	cold, err := globalTierConfigMgr.getDriver("COLD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := cold.Get(ctx, metaArr[0].TransitionedObjName, remoteVersionID(metaArr[0].TransitionVersionID), WarmBackendGetOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(r)
+	r.Close()
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("unexpected content on the new tier, %v", err)
+	}
+
+	// The copy on the previous tier is queued for deletion.
+	var entry jentry
+	select {
+	case entry = <-globalTierJournal.entries:
+	default:
+		t.Fatal("expected a tier journal entry")
+	}
+	want := jentry{ObjName: warm.TransitionedObjName, VersionID: warm.TransitionVersionID, TierName: "WARM"}
+	if entry != want {
+		t.Fatalf("expected tier journal entry %+v, got %+v", want, entry)
+	}
+
+	// The previous tier is removed once its copy is deleted.
+	if err = removeTier(ctx, objLayer, "WARM", nil); !errors.Is(err, errTierBackendNotEmpty) {
+		t.Fatalf("expected %v, got %v", errTierBackendNotEmpty, err)
+	}
+	if err = deleteObjectFromRemoteTier(ctx, entry.ObjName, entry.VersionID, entry.TierName); err != nil {
+		t.Fatal(err)
+	}
+	if err = removeTier(ctx, objLayer, "WARM", nil); err != nil {
+		t.Fatal(err)
+	}
+	if globalTierConfigMgr.IsTierValid("WARM") || !globalTierConfigMgr.IsTierValid("COLD") {
+		t.Fatal("expected only the previous tier to be removed")
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/infobsmi/b33s/internal/bucket/lifecycle"
+	"github.com/infobsmi/b33s/internal/event"
+	xhttp "github.com/infobsmi/b33s/internal/http"
+	"github.com/infobsmi/b33s/internal/logger"
+)
+
+// errRestorePending is returned when a restore from an archive tier was
+// requested and is waiting for the remote to stage the object.
+var errRestorePending = errors.New("restore from remote tier is pending")
+
+// archiveRestoreInterval is the interval at which pending restores from
+// archive tiers are retried.
+const archiveRestoreInterval = 5 * time.Minute
+
+// archiveRestore is a restore of an object version waiting for an archive
+// tier to stage the object.
+type archiveRestore struct {
+	bucket    string
+	object    string
+	versionID string
+	days      int
+	tier      string // retrieval tier requested, optional
+}
+
+func (r archiveRestore) key() string {
+	return pathJoin(r.bucket, r.object, r.versionID)
+}
+
+// archiveRestoreState tracks the restores pending on archive tiers. Pending
+// restores aren't persisted, after a restart they are queued again by the
+// scanner from the ongoing restore status in the object metadata.
+type archiveRestoreState struct {
+	mu      sync.Mutex
+	pending map[string]archiveRestore
+
+	// restore retries a pending restore, it returns errRestorePending
+	// while the object isn't staged yet.
+	restore func(ctx context.Context, r archiveRestore) error
+}
+
+var globalArchiveRestoreState *archiveRestoreState
+
+func newArchiveRestoreState(objAPI ObjectLayer) *archiveRestoreState {
+	return &archiveRestoreState{
+		pending: make(map[string]archiveRestore),
+		restore: func(ctx context.Context, r archiveRestore) error {
+			return restoreArchivedObject(ctx, objAPI, r)
+		},
+	}
+}
+
+func initBackgroundArchiveRestore(ctx context.Context, objAPI ObjectLayer) {
+	globalArchiveRestoreState = newArchiveRestoreState(objAPI)
+	go globalArchiveRestoreState.run(ctx)
+}
+
+// queue adds r to the pending restores, unless the object version is
+// already pending.
+func (s *archiveRestoreState) queue(r archiveRestore) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.pending[r.key()]; !ok {
+		s.pending[r.key()] = r
+	}
+}
+
+// Pending returns the number of pending restores.
+func (s *archiveRestoreState) Pending() int {
+	if s == nil {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending)
+}
+
+// poll retries all pending restores once, restores which completed or
+// failed are removed.
+func (s *archiveRestoreState) poll(ctx context.Context) {
+	s.mu.Lock()
+	pending := make([]archiveRestore, 0, len(s.pending))
+	for _, r := range s.pending {
+		pending = append(pending, r)
+	}
+	s.mu.Unlock()
+
+	for _, r := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+		err := s.restore(ctx, r)
+		if errors.Is(err, errRestorePending) {
+			continue
+		}
+		if err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to restore %s/%s(%s) from remote tier: %w", r.bucket, r.object, r.versionID, err))
+		}
+		s.mu.Lock()
+		delete(s.pending, r.key())
+		s.mu.Unlock()
+	}
+}
+
+func (s *archiveRestoreState) run(ctx context.Context) {
+	t := time.NewTicker(archiveRestoreInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			s.poll(ctx)
+		}
+	}
+}
+
+// resume queues the restore of oi if it is ongoing on an archive tier. It
+// picks up restores which were pending before a restart.
+func (s *archiveRestoreState) resume(oi ObjectInfo) {
+	if s == nil || !oi.RestoreOngoing || oi.TransitionedObject.Status != lifecycle.TransitionComplete {
+		return
+	}
+	d, err := globalTierConfigMgr.getDriver(oi.TransitionedObject.Tier)
+	if err != nil {
+		return
+	}
+	if _, ok := d.(warmBackendArchive); !ok {
+		return
+	}
+	days, err := strconv.Atoi(oi.UserDefined[xhttp.AmzRestoreExpiryDays])
+	if err != nil {
+		return
+	}
+	s.queue(archiveRestore{
+		bucket:    oi.Bucket,
+		object:    oi.Name,
+		versionID: oi.VersionID,
+		days:      days,
+	})
+}
+
+// stageTransitionedObject requests the remote tier of oi to stage the object
+// for reads. It returns true if the object can be read, which is always the
+// case for tiers which aren't archive tiers.
+func stageTransitionedObject(ctx context.Context, oi ObjectInfo, rreq *RestoreObjectRequest) (bool, error) {
+	d, err := globalTierConfigMgr.getDriver(oi.TransitionedObject.Tier)
+	if err != nil {
+		return false, err
+	}
+	a, ok := d.(warmBackendArchive)
+	if !ok {
+		return true, nil
+	}
+	var tier string
+	if rreq != nil {
+		tier = rreq.Tier
+	}
+	return a.Restore(ctx, oi.TransitionedObject.Name, remoteVersionID(oi.TransitionedObject.VersionID), tier)
+}
+
+// restoreArchivedObject retries the restore r, the restored copy expires
+// r.days after the object was staged by the remote tier.
+func restoreArchivedObject(ctx context.Context, objAPI ObjectLayer, r archiveRestore) error {
+	opts := ObjectOptions{
+		Transition: TransitionOptions{
+			RestoreRequest: &RestoreObjectRequest{Days: r.days, Tier: r.tier},
+			RestoreExpiry:  lifecycle.ExpectedExpiryTime(time.Now(), r.days),
+		},
+		VersionID: r.versionID,
+	}
+	if err := objAPI.RestoreTransitionedObject(ctx, r.bucket, r.object, opts); err != nil {
+		return err
+	}
+
+	oi, err := objAPI.GetObjectInfo(ctx, r.bucket, r.object, ObjectOptions{VersionID: r.versionID})
+	if err != nil {
+		return err
+	}
+	// Notify object restore completed.
+	sendEvent(eventArgs{
+		EventName:  event.ObjectRestorePostCompleted,
+		BucketName: r.bucket,
+		Object:     oi,
+		Host:       "Internal: [ILM-RESTORE]",
+	})
+	return nil
+}
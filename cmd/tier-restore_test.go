@@ -0,0 +1,196 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+)
+
+// fakeArchiveBackend is an in-memory archive tier. Objects can only be read
+// once they were staged by stage, after a restore was requested.
+type fakeArchiveBackend struct {
+	mu        sync.Mutex
+	objects   map[string][]byte
+	requested map[string]string // object -> retrieval tier requested
+	staged    map[string]bool
+}
+
+func newFakeArchiveBackend() *fakeArchiveBackend {
+	return &fakeArchiveBackend{
+		objects:   make(map[string][]byte),
+		requested: make(map[string]string),
+		staged:    make(map[string]bool),
+	}
+}
+
+// stage completes the restores requested so far.
+func (f *fakeArchiveBackend) stage() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for object := range f.requested {
+		f.staged[object] = true
+	}
+}
+
+func (f *fakeArchiveBackend) Put(ctx context.Context, object string, r io.Reader, length int64) (remoteVersionID, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objects[object] = data
+	return "", nil
+}
+
+func (f *fakeArchiveBackend) Get(ctx context.Context, object string, rv remoteVersionID, opts WarmBackendGetOpts) (io.ReadCloser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, ok := f.objects[object]
+	if !ok {
+		return nil, ObjectNotFound{Object: object}
+	}
+	if !f.staged[object] {
+		return nil, InvalidObjectState{Object: object}
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (f *fakeArchiveBackend) Remove(ctx context.Context, object string, rv remoteVersionID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.objects, object)
+	delete(f.requested, object)
+	delete(f.staged, object)
+	return nil
+}
+
+func (f *fakeArchiveBackend) InUse(ctx context.Context) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.objects) > 0, nil
+}
+
+func (f *fakeArchiveBackend) Restore(ctx context.Context, object string, rv remoteVersionID, tier string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.objects[object]; !ok {
+		return false, ObjectNotFound{Object: object}
+	}
+	if _, ok := f.requested[object]; !ok {
+		f.requested[object] = tier
+	}
+	return f.staged[object], nil
+}
+
+func TestStageTransitionedObject(t *testing.T) {
+	ctx := context.Background()
+	archive := newFakeArchiveBackend()
+	if err := checkWarmBackend(ctx, archive); err != nil {
+		t.Fatal(err)
+	}
+
+	oldTierConfigMgr := globalTierConfigMgr
+	defer func() { globalTierConfigMgr = oldTierConfigMgr }()
+	globalTierConfigMgr = NewTierConfigMgr()
+	globalTierConfigMgr.drivercache["ARCHIVE"] = archive
+
+	if _, err := archive.Put(ctx, "obj", bytes.NewReader([]byte("data")), 4); err != nil {
+		t.Fatal(err)
+	}
+	oi := ObjectInfo{}
+	oi.TransitionedObject.Tier = "ARCHIVE"
+	oi.TransitionedObject.Name = "obj"
+
+	staged, err := stageTransitionedObject(ctx, oi, &RestoreObjectRequest{Days: 1, Tier: "Bulk"})
+	if err != nil || staged {
+		t.Fatalf("expected restore to be pending, got %v, %v", staged, err)
+	}
+	if tier := archive.requested["obj"]; tier != "Bulk" {
+		t.Fatalf("expected Bulk retrieval tier, got %q", tier)
+	}
+	if _, err = archive.Get(ctx, "obj", "", WarmBackendGetOpts{}); !errors.As(err, &InvalidObjectState{}) {
+		t.Fatalf("expected unstaged read to fail, got %v", err)
+	}
+
+	archive.stage()
+	if staged, err = stageTransitionedObject(ctx, oi, nil); err != nil || !staged {
+		t.Fatalf("expected object to be staged, got %v, %v", staged, err)
+	}
+
+	oi.TransitionedObject.Tier = "UNKNOWN"
+	if _, err = stageTransitionedObject(ctx, oi, nil); !errors.Is(err, errTierNotFound) {
+		t.Fatalf("expected %v, got %v", errTierNotFound, err)
+	}
+}
+
+func TestArchiveRestoreStatePoll(t *testing.T) {
+	ctx := context.Background()
+	archive := newFakeArchiveBackend()
+	archive.Put(ctx, "staged", bytes.NewReader(nil), 0)
+	archive.Put(ctx, "pending", bytes.NewReader(nil), 0)
+
+	var restored []string
+	s := &archiveRestoreState{
+		pending: make(map[string]archiveRestore),
+		restore: func(ctx context.Context, r archiveRestore) error {
+			ok, err := archive.Restore(ctx, r.object, "", r.tier)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errRestorePending
+			}
+			restored = append(restored, r.object)
+			return nil
+		},
+	}
+	for _, object := range []string{"staged", "pending", "missing"} {
+		s.queue(archiveRestore{bucket: "bucket", object: object, days: 1})
+	}
+	s.queue(archiveRestore{bucket: "bucket", object: "staged", days: 2})
+	if n := s.Pending(); n != 3 {
+		t.Fatalf("expected 3 pending restores, got %d", n)
+	}
+
+	// Nothing is staged yet, only the restore of the missing object fails.
+	s.poll(ctx)
+	if n := s.Pending(); n != 2 || len(restored) != 0 {
+		t.Fatalf("expected 2 pending and no restored objects, got %d, %v", n, restored)
+	}
+	if r := s.pending[archiveRestore{bucket: "bucket", object: "staged"}.key()]; r.days != 1 {
+		t.Fatalf("expected the first restore queued to be kept, got %d days", r.days)
+	}
+
+	archive.stage()
+	s.poll(ctx)
+	if n := s.Pending(); n != 0 || len(restored) != 2 {
+		t.Fatalf("expected no pending and 2 restored objects, got %d, %v", n, restored)
+	}
+
+	// A nil state, before initBackgroundArchiveRestore, ignores restores.
+	var nilState *archiveRestoreState
+	nilState.queue(archiveRestore{bucket: "bucket", object: "obj"})
+	if n := nilState.Pending(); n != 0 {
+		t.Fatalf("expected no pending restores, got %d", n)
+	}
+}
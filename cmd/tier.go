@@ -74,12 +74,27 @@ const (
 // tierConfigPath refers to remote tier config object name
 var tierConfigPath = path.Join(minioConfigPrefix, tierConfigFile)
 
+// tierTypeFS is the tier type of a directory on a mounted filesystem, which
+// is not one of the madmin.TierType backends.
+const tierTypeFS = "fs"
+
+// TierFS represents the remote tier configuration of a directory on a
+// filesystem, usually a network filesystem like NFS mounted at the same
+// path on all servers of the deployment.
+type TierFS struct {
+	Name   string `json:",omitempty"`
+	Path   string `json:",omitempty"`
+	Prefix string `json:",omitempty"`
+}
+
 // TierConfigMgr holds the collection of remote tiers configured in this deployment.
 type TierConfigMgr struct {
 	sync.RWMutex `msg:"-"`
 	drivercache  map[string]WarmBackend `msg:"-"`
 
 	Tiers map[string]madmin.TierConfig `json:"tiers"`
+	// FSTiers holds the filesystem tiers, which have no madmin.TierType.
+	FSTiers map[string]TierFS `json:"fsTiers,omitempty"`
 }
 
 // IsTierValid returns true if there exists a remote tier by name tierName,
@@ -92,53 +107,99 @@ func (config *TierConfigMgr) IsTierValid(tierName string) bool {
 }
 
 // isTierNameInUse returns tier type and true if there exists a remote tier by
-// name tierName, otherwise returns madmin.Unsupported and false. N B this
-// function is meant for internal use, where the caller is expected to take
-// appropriate locks.
+// name tierName, otherwise returns madmin.Unsupported and false. Filesystem
+// tiers are returned as madmin.Unsupported and true. N B this function is
+// meant for internal use, where the caller is expected to take appropriate
+// locks.
 func (config *TierConfigMgr) isTierNameInUse(tierName string) (madmin.TierType, bool) {
 	if t, ok := config.Tiers[tierName]; ok {
 		return t.Type, true
 	}
+	if _, ok := config.FSTiers[tierName]; ok {
+		return madmin.Unsupported, true
+	}
 	return madmin.Unsupported, false
 }
 
-// Add adds tier to config if it passes all validations.
-func (config *TierConfigMgr) Add(ctx context.Context, tier madmin.TierConfig) error {
-	config.Lock()
-	defer config.Unlock()
-
+// checkNewTier validates the name of a new tier and returns its warm backend
+// created by newDriver if it is not in use already. N B the caller is
+// expected to take appropriate locks.
+func (config *TierConfigMgr) checkNewTier(ctx context.Context, tierName string, newDriver func() (WarmBackend, error)) (WarmBackend, error) {
 	// check if tier name is in all caps
-	tierName := tier.Name
 	if tierName != strings.ToUpper(tierName) {
-		return errTierNameNotUppercase
+		return nil, errTierNameNotUppercase
 	}
 
 	// check if tier name already in use
 	if _, exists := config.isTierNameInUse(tierName); exists {
-		return errTierAlreadyExists
+		return nil, errTierAlreadyExists
 	}
 
-	d, err := newWarmBackend(ctx, tier)
+	d, err := newDriver()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// Check if warmbackend is in use by other B33S tenants
 	inUse, err := d.InUse(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if inUse {
-		return errTierBackendInUse
+		return nil, errTierBackendInUse
 	}
+	return d, nil
+}
 
-	config.Tiers[tierName] = tier
-	config.drivercache[tierName] = d
+// Add adds tier to config if it passes all validations.
+func (config *TierConfigMgr) Add(ctx context.Context, tier madmin.TierConfig) error {
+	config.Lock()
+	defer config.Unlock()
+
+	d, err := config.checkNewTier(ctx, tier.Name, func() (WarmBackend, error) {
+		return newWarmBackend(ctx, tier)
+	})
+	if err != nil {
+		return err
+	}
+
+	config.Tiers[tier.Name] = tier
+	config.drivercache[tier.Name] = d
+
+	return nil
+}
+
+// AddFS adds filesystem tier to config if it passes all validations.
+func (config *TierConfigMgr) AddFS(ctx context.Context, tier TierFS) error {
+	config.Lock()
+	defer config.Unlock()
+
+	d, err := config.checkNewTier(ctx, tier.Name, func() (WarmBackend, error) {
+		return newWarmBackendFromFS(ctx, tier)
+	})
+	if err != nil {
+		return err
+	}
+
+	config.FSTiers[tier.Name] = tier
+	config.drivercache[tier.Name] = d
 
 	return nil
 }
 
 // Remove removes tier if it is empty.
 func (config *TierConfigMgr) Remove(ctx context.Context, tier string) error {
+	return config.remove(ctx, tier, nil)
+}
+
+// remove removes tier unless a migration other than self uses it.
+func (config *TierConfigMgr) remove(ctx context.Context, tier string, self *tierMigration) error {
+	// No migration from or to tier may start until it is removed.
+	globalTierMigrations.Lock()
+	defer globalTierMigrations.Unlock()
+	if globalTierMigrations.busy(tier, self) {
+		return errTierMigrationInProgress
+	}
+
 	d, err := config.getDriver(tier)
 	if err != nil {
 		return err
@@ -150,6 +211,7 @@ func (config *TierConfigMgr) Remove(ctx context.Context, tier string) error {
 	} else {
 		config.Lock()
 		delete(config.Tiers, tier)
+		delete(config.FSTiers, tier)
 		delete(config.drivercache, tier)
 		config.Unlock()
 	}
@@ -171,7 +233,9 @@ func (config *TierConfigMgr) Empty() bool {
 	if config == nil {
 		return true
 	}
-	return len(config.ListTiers()) == 0
+	config.RLock()
+	defer config.RUnlock()
+	return len(config.Tiers) == 0 && len(config.FSTiers) == 0
 }
 
 // ListTiers lists remote tiers configured in this deployment.
@@ -189,6 +253,50 @@ func (config *TierConfigMgr) ListTiers() []madmin.TierConfig {
 	return tierCfgs
 }
 
+// ListFSTiers lists filesystem tiers configured in this deployment.
+func (config *TierConfigMgr) ListFSTiers() []TierFS {
+	config.RLock()
+	defer config.RUnlock()
+
+	var tierCfgs []TierFS
+	for _, tier := range config.FSTiers {
+		tierCfgs = append(tierCfgs, tier)
+	}
+	return tierCfgs
+}
+
+//msgp:ignore tierListEntryFS
+
+// tierListEntryFS is a filesystem tier in the list of all tiers, in the
+// shape of madmin.TierConfig. Its Type is left out, since "fs" is not a
+// madmin.TierType, so that madmin clients list it as an unsupported tier
+// instead of failing to decode the list.
+type tierListEntryFS struct {
+	Version string `json:",omitempty"`
+	Name    string `json:",omitempty"`
+	FS      TierFS
+}
+
+// ListAllTiers lists the remote and the filesystem tiers configured in
+// this deployment.
+func (config *TierConfigMgr) ListAllTiers() []interface{} {
+	tiers := config.ListTiers()
+	fsTiers := config.ListFSTiers()
+
+	all := make([]interface{}, 0, len(tiers)+len(fsTiers))
+	for _, tier := range tiers {
+		all = append(all, tier)
+	}
+	for _, tier := range fsTiers {
+		all = append(all, tierListEntryFS{
+			Version: madmin.TierConfigVer,
+			Name:    tier.Name,
+			FS:      tier,
+		})
+	}
+	return all
+}
+
 // Edit replaces the credentials of the remote tier specified by tierName with creds.
 func (config *TierConfigMgr) Edit(ctx context.Context, tierName string, creds madmin.TierCreds) error {
 	config.Lock()
@@ -199,6 +307,9 @@ func (config *TierConfigMgr) Edit(ctx context.Context, tierName string, creds ma
 	if !exists {
 		return errTierNotFound
 	}
+	if _, ok := config.FSTiers[tierName]; ok {
+		return errTierNoCredentials
+	}
 
 	cfg := config.Tiers[tierName]
 	switch tierType {
@@ -268,11 +379,13 @@ func (config *TierConfigMgr) getDriver(tierName string) (d WarmBackend, err erro
 	}
 
 	// Initialize driver from tier config matching tierName
-	t, ok := config.Tiers[tierName]
-	if !ok {
+	if t, ok := config.FSTiers[tierName]; ok {
+		d, err = newWarmBackendFromFS(context.TODO(), t)
+	} else if t, ok := config.Tiers[tierName]; ok {
+		d, err = newWarmBackend(context.TODO(), t)
+	} else {
 		return nil, errTierNotFound
 	}
-	d, err = newWarmBackend(context.TODO(), t)
 	if err != nil {
 		return nil, err
 	}
@@ -354,10 +467,16 @@ func (config *TierConfigMgr) Reload(ctx context.Context, objAPI ObjectLayer) err
 	for k := range config.Tiers {
 		delete(config.Tiers, k)
 	}
+	for k := range config.FSTiers {
+		delete(config.FSTiers, k)
+	}
 	// Copy over the new tier configs
 	for tier, cfg := range newConfig.Tiers {
 		config.Tiers[tier] = cfg
 	}
+	for tier, cfg := range newConfig.FSTiers {
+		config.FSTiers[tier] = cfg
+	}
 
 	return nil
 }
@@ -382,6 +501,7 @@ func NewTierConfigMgr() *TierConfigMgr {
 	return &TierConfigMgr{
 		drivercache: make(map[string]WarmBackend),
 		Tiers:       make(map[string]madmin.TierConfig),
+		FSTiers:     make(map[string]TierFS),
 	}
 }
 
@@ -429,6 +549,9 @@ func (config *TierConfigMgr) Reset() {
 	for k := range config.Tiers {
 		delete(config.Tiers, k)
 	}
+	for k := range config.FSTiers {
+		delete(config.FSTiers, k)
+	}
 	config.Unlock()
 }
 
@@ -156,6 +156,8 @@ func ErrorRespToObjectError(err error, params ...string) error {
 		err = InvalidUploadID{}
 	case "EntityTooSmall":
 		err = PartTooSmall{}
+	case "InvalidObjectState":
+		err = InvalidObjectState{Bucket: bucket, Object: object}
 	}
 
 	switch minioErr.StatusCode {
@@ -174,6 +176,10 @@ func hasSpaceBE(s string) bool {
 }
 
 func request2BucketObjectName(r *http.Request) (bucketName, objectName string) {
+	if bucket, ok := getWebsiteBucket(r.Host); ok {
+		return bucket, strings.TrimPrefix(r.URL.Path, SlashSeparator)
+	}
+
 	path, err := getResource(r.URL.Path, r.Host, globalDomainNames)
 	if err != nil {
 		logger.CriticalIf(GlobalContext, err)
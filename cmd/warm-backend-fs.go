@@ -0,0 +1,147 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type warmBackendFS struct {
+	Path   string
+	Prefix string
+}
+
+func (fs *warmBackendFS) getDest(object string) string {
+	return filepath.Join(fs.Path, fs.Prefix, filepath.FromSlash(object))
+}
+
+func (fs *warmBackendFS) toObjectError(err error, object string) error {
+	if osIsNotExist(err) {
+		return ObjectNotFound{Bucket: fs.Path, Object: object}
+	}
+	return err
+}
+
+// Put writes the object to a temporary file next to its destination and
+// renames it into place once it is persisted, so that partially written
+// objects are never read.
+func (fs *warmBackendFS) Put(ctx context.Context, object string, r io.Reader, length int64) (remoteVersionID, error) {
+	dest := fs.getDest(object)
+	if err := mkdirAll(filepath.Dir(dest), 0o750); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	tmp := f.Name()
+	defer Remove(tmp)
+
+	n, err := io.Copy(f, r)
+	switch {
+	case err != nil, length < 0:
+	case n < length:
+		err = errLessData
+	case n > length:
+		err = errMoreData
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+	return "", renameAll(tmp, dest)
+}
+
+func (fs *warmBackendFS) Get(ctx context.Context, object string, rv remoteVersionID, opts WarmBackendGetOpts) (io.ReadCloser, error) {
+	f, err := os.Open(fs.getDest(object))
+	if err != nil {
+		return nil, fs.toObjectError(err, object)
+	}
+	if opts.startOffset >= 0 && opts.length > 0 {
+		if _, err = f.Seek(opts.startOffset, io.SeekStart); err != nil {
+			f.Close()
+			return nil, err
+		}
+		return struct {
+			io.Reader
+			io.Closer
+		}{io.LimitReader(f, opts.length), f}, nil
+	}
+	return f, nil
+}
+
+// Remove removes the object and the directories left empty by it.
+func (fs *warmBackendFS) Remove(ctx context.Context, object string, rv remoteVersionID) error {
+	dest := fs.getDest(object)
+	if err := Remove(dest); err != nil && !osIsNotExist(err) {
+		return err
+	}
+	base := filepath.Join(fs.Path, fs.Prefix)
+	for dir := filepath.Dir(dest); strings.HasPrefix(dir, base+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		if Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+func (fs *warmBackendFS) InUse(ctx context.Context) (bool, error) {
+	f, err := os.Open(filepath.Join(fs.Path, fs.Prefix))
+	if err != nil {
+		if osIsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer f.Close()
+	names, err := f.Readdirnames(1)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	return len(names) > 0, nil
+}
+
+func newWarmBackendFS(conf TierFS) (*warmBackendFS, error) {
+	if !filepath.IsAbs(conf.Path) {
+		return nil, errors.New("tier path must be absolute")
+	}
+	fi, err := os.Stat(conf.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return nil, errors.New("tier path must be a directory")
+	}
+	prefix := strings.Trim(conf.Prefix, slashSeparator)
+	if prefix != "" && (prefix != filepath.ToSlash(filepath.Clean(prefix)) || strings.HasPrefix(prefix, "..")) {
+		return nil, errors.New("tier prefix must not leave the tier path")
+	}
+	return &warmBackendFS{
+		Path:   filepath.Clean(conf.Path),
+		Prefix: filepath.FromSlash(prefix),
+	}, nil
+}
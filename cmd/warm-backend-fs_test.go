@@ -0,0 +1,152 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestWarmBackendFS(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	if _, err := newWarmBackendFS(TierFS{Path: "relative"}); err == nil {
+		t.Fatal("expected relative path to be rejected")
+	}
+	if _, err := newWarmBackendFS(TierFS{Path: root, Prefix: "../escape"}); err == nil {
+		t.Fatal("expected prefix outside of path to be rejected")
+	}
+
+	fs, err := newWarmBackendFS(TierFS{Path: root, Prefix: "tier/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = checkWarmBackend(ctx, fs); err != nil {
+		t.Fatal(err)
+	}
+	if inUse, err := fs.InUse(ctx); err != nil || inUse {
+		t.Fatalf("expected unused tier, got %v, %v", inUse, err)
+	}
+
+	const object = "deployment/bucket/ab/cd/abcd-uuid"
+	data := []byte("hello, cold storage")
+	if _, err = fs.Put(ctx, object, bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fs.Put(ctx, "short", bytes.NewReader(data), int64(len(data))+1); !errors.Is(err, errLessData) {
+		t.Fatalf("expected %v, got %v", errLessData, err)
+	}
+	if inUse, err := fs.InUse(ctx); err != nil || !inUse {
+		t.Fatalf("expected tier in use, got %v, %v", inUse, err)
+	}
+
+	testCases := []struct {
+		opts WarmBackendGetOpts
+		want []byte
+	}{
+		{WarmBackendGetOpts{}, data},
+		{WarmBackendGetOpts{startOffset: 7, length: 4}, data[7:11]},
+		{WarmBackendGetOpts{startOffset: 7}, data},
+	}
+	for i, tc := range testCases {
+		r, err := fs.Get(ctx, object, "", tc.opts)
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		got, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		if !bytes.Equal(got, tc.want) {
+			t.Errorf("case %d: expected %q, got %q", i, tc.want, got)
+		}
+	}
+
+	if err = fs.Remove(ctx, object, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fs.Get(ctx, object, "", WarmBackendGetOpts{}); !isErrObjectNotFound(err) {
+		t.Fatalf("expected object not found, got %v", err)
+	}
+	// Removing a missing object is not an error.
+	if err = fs.Remove(ctx, object, ""); err != nil {
+		t.Fatal(err)
+	}
+	if inUse, err := fs.InUse(ctx); err != nil || inUse {
+		t.Fatalf("expected empty directories to be removed, got %v, %v", inUse, err)
+	}
+	if _, err = os.Stat(filepath.Join(root, "tier")); err != nil {
+		t.Fatalf("expected prefix directory to be kept, got %v", err)
+	}
+}
+
+func TestListAllTiersFS(t *testing.T) {
+	config := &TierConfigMgr{
+		Tiers: map[string]madmin.TierConfig{
+			"S3TIER": {
+				Version: madmin.TierConfigVer,
+				Type:    madmin.S3,
+				Name:    "S3TIER",
+				S3:      &madmin.TierS3{Endpoint: "https://s3.amazonaws.com", Bucket: "bucket"},
+			},
+		},
+		FSTiers: map[string]TierFS{
+			"NASTIER": {Name: "NASTIER", Path: "/mnt/nas", Prefix: "tier"},
+		},
+	}
+	data, err := json.Marshal(config.ListAllTiers())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Clients decoding the list as madmin.TierConfig see the
+	// filesystem tier as an unsupported tier.
+	var tiers []madmin.TierConfig
+	if err = json.Unmarshal(data, &tiers); err != nil {
+		t.Fatal(err)
+	}
+	types := make(map[string]madmin.TierType, len(tiers))
+	for _, tier := range tiers {
+		types[tier.Name] = tier.Type
+	}
+	if len(types) != 2 || types["S3TIER"] != madmin.S3 || types["NASTIER"] != madmin.Unsupported {
+		t.Fatalf("unexpected tiers %s", data)
+	}
+
+	var entries []struct {
+		Name string
+		FS   *TierFS
+	}
+	if err = json.Unmarshal(data, &entries); err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.Name == "NASTIER" && (entry.FS == nil || *entry.FS != config.FSTiers["NASTIER"]) {
+			t.Fatalf("unexpected filesystem tier %s", data)
+		}
+	}
+}
@@ -105,6 +105,46 @@ func (s3 *warmBackendS3) InUse(ctx context.Context) (bool, error) {
 	return len(result.CommonPrefixes) > 0 || len(result.Contents) > 0, nil
 }
 
+// isArchiveStorageClass returns true if objects of the S3 storage class sc
+// must be restored before they can be read.
+func isArchiveStorageClass(sc string) bool {
+	switch sc {
+	case "GLACIER", "DEEP_ARCHIVE":
+		return true
+	}
+	return false
+}
+
+// warmBackendS3Archive is a S3 tier transitioning objects to an archive
+// storage class, see isArchiveStorageClass.
+type warmBackendS3Archive struct {
+	*warmBackendS3
+}
+
+// Restore requests a temporary copy of the object from the remote. It
+// returns true once the copy can be read.
+func (s3 warmBackendS3Archive) Restore(ctx context.Context, object string, rv remoteVersionID, tier string) (bool, error) {
+	oi, err := s3.client.StatObject(ctx, s3.Bucket, s3.getDest(object), b33s.StatObjectOptions{VersionID: string(rv)})
+	if err != nil {
+		return false, s3.ToObjectError(err, object)
+	}
+	if oi.Restore != nil {
+		return !oi.Restore.OngoingRestore, nil
+	}
+
+	req := b33s.RestoreRequest{}
+	// The temporary copy is only read once to restore the object locally.
+	req.SetDays(1)
+	if tier != "" {
+		req.SetGlacierJobParameters(b33s.GlacierJobParameters{Tier: b33s.TierType(tier)})
+	}
+	err = s3.client.RestoreObject(ctx, s3.Bucket, s3.getDest(object), string(rv), req)
+	if b33s.ToErrorResponse(err).Code == "RestoreAlreadyInProgress" {
+		return false, nil
+	}
+	return false, s3.ToObjectError(err, object)
+}
+
 func newWarmBackendS3(conf madmin.TierS3) (*warmBackendS3, error) {
 	u, err := url.Parse(conf.Endpoint)
 	if err != nil {
@@ -42,6 +42,17 @@ type WarmBackend interface {
 	InUse(ctx context.Context) (bool, error)
 }
 
+// warmBackendArchive is implemented by remote tier backends archiving objects
+// on media which can't be read immediately, like tapes or archive storage
+// classes. Reads of an object fail until a restore requested with Restore
+// has staged it.
+type warmBackendArchive interface {
+	WarmBackend
+	// Restore requests object to be staged for reads, tier optionally selects
+	// the retrieval tier. It returns true once object is staged.
+	Restore(ctx context.Context, object string, rv remoteVersionID, tier string) (bool, error)
+}
+
 const probeObject = "probeobject"
 
 // checkWarmBackend checks if tier config credentials have sufficient privileges
@@ -60,7 +71,13 @@ func checkWarmBackend(ctx context.Context, w WarmBackend) error {
 		}
 	}
 
-	_, err = w.Get(ctx, probeObject, rv, WarmBackendGetOpts{})
+	if a, ok := w.(warmBackendArchive); ok {
+		// Objects of archive tiers can't be read before they are restored,
+		// check that restores can be requested instead.
+		_, err = a.Restore(ctx, probeObject, rv, "")
+	} else {
+		_, err = w.Get(ctx, probeObject, rv, WarmBackendGetOpts{})
+	}
 	if err != nil {
 		switch err.(type) {
 		case BackendDown:
@@ -136,7 +153,12 @@ type remoteVersionID string
 func newWarmBackend(ctx context.Context, tier madmin.TierConfig) (d WarmBackend, err error) {
 	switch tier.Type {
 	case madmin.S3:
-		d, err = newWarmBackendS3(*tier.S3)
+		var s3 *warmBackendS3
+		s3, err = newWarmBackendS3(*tier.S3)
+		d = s3
+		if err == nil && isArchiveStorageClass(tier.S3.StorageClass) {
+			d = warmBackendS3Archive{s3}
+		}
 	case madmin.Azure:
 		d, err = newWarmBackendAzure(*tier.Azure)
 	case madmin.GCS:
@@ -156,3 +178,18 @@ func newWarmBackend(ctx context.Context, tier madmin.TierConfig) (d WarmBackend,
 	}
 	return d, nil
 }
+
+// newWarmBackendFromFS instantiates the WarmBackend of a filesystem tier,
+// runs checkWarmBackend on it.
+func newWarmBackendFromFS(ctx context.Context, tier TierFS) (WarmBackend, error) {
+	d, err := newWarmBackendFS(tier)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", madmin.ErrTierInvalidConfig, err)
+	}
+
+	err = checkWarmBackend(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cors
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/minio/pkg/wildcard"
+)
+
+const (
+	// Maximum number of CORS rules allowed on a bucket (same as AWS S3).
+	maxRules = 100
+
+	// Maximum length of a rule ID.
+	maxRuleIDLength = 255
+
+	xmlNS = "http://s3.amazonaws.com/doc/2006-03-01/"
+)
+
+var (
+	errNoRules          = Errorf("CORS configuration must have at least one rule")
+	errTooManyRules     = Errorf("CORS configuration allows a maximum of 100 rules")
+	errRuleIDTooLong    = Errorf("ID length is limited to 255 characters")
+	errMissingMethods   = Errorf("CORSRule must have at least one AllowedMethod")
+	errMissingOrigins   = Errorf("CORSRule must have at least one AllowedOrigin")
+	errNegativeMaxAge   = Errorf("MaxAgeSeconds must not be negative")
+	errWildcardExposed  = Errorf("ExposeHeader does not support wildcards")
+	errTooManyWildcards = Errorf("AllowedOrigin and AllowedHeader can have at most one wildcard '*'")
+)
+
+// supportedMethods - HTTP methods that can be allowed by a CORS rule.
+var supportedMethods = map[string]struct{}{
+	http.MethodGet:    {},
+	http.MethodPut:    {},
+	http.MethodHead:   {},
+	http.MethodPost:   {},
+	http.MethodDelete: {},
+}
+
+// Rule - a single CORSRule of a bucket CORS configuration.
+type Rule struct {
+	ID             string   `xml:"ID,omitempty"`
+	AllowedHeaders []string `xml:"AllowedHeader,omitempty"`
+	AllowedMethods []string `xml:"AllowedMethod"`
+	AllowedOrigins []string `xml:"AllowedOrigin"`
+	ExposeHeaders  []string `xml:"ExposeHeader,omitempty"`
+	MaxAgeSeconds  *int     `xml:"MaxAgeSeconds,omitempty"`
+}
+
+// Validate - validates the CORS rule.
+func (r Rule) Validate() error {
+	if len(r.ID) > maxRuleIDLength {
+		return errRuleIDTooLong
+	}
+	if len(r.AllowedMethods) == 0 {
+		return errMissingMethods
+	}
+	for _, method := range r.AllowedMethods {
+		if _, ok := supportedMethods[method]; !ok {
+			return Errorf("Found unsupported HTTP method in CORS config. Unsupported method is %s", method)
+		}
+	}
+	if len(r.AllowedOrigins) == 0 {
+		return errMissingOrigins
+	}
+	for _, origin := range r.AllowedOrigins {
+		if strings.Count(origin, "*") > 1 {
+			return errTooManyWildcards
+		}
+	}
+	for _, header := range r.AllowedHeaders {
+		if strings.Count(header, "*") > 1 {
+			return errTooManyWildcards
+		}
+	}
+	for _, header := range r.ExposeHeaders {
+		if strings.Contains(header, "*") {
+			return errWildcardExposed
+		}
+	}
+	if r.MaxAgeSeconds != nil && *r.MaxAgeSeconds < 0 {
+		return errNegativeMaxAge
+	}
+	return nil
+}
+
+// AllowsAnyOrigin - returns true if the rule allows all origins with
+// a single '*' entry.
+func (r Rule) AllowsAnyOrigin() bool {
+	for _, origin := range r.AllowedOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) matchOrigin(origin string) bool {
+	for _, allowed := range r.AllowedOrigins {
+		if wildcard.MatchSimple(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) matchMethod(method string) bool {
+	for _, allowed := range r.AllowedMethods {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHeaders - header names are case-insensitive, every requested
+// header must be allowed by the rule.
+func (r Rule) matchHeaders(headers []string) bool {
+	for _, header := range headers {
+		header = strings.ToLower(strings.TrimSpace(header))
+		if header == "" {
+			continue
+		}
+		var found bool
+		for _, allowed := range r.AllowedHeaders {
+			if wildcard.MatchSimple(strings.ToLower(allowed), header) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Config - bucket CORS configuration.
+type Config struct {
+	XMLNS     string   `xml:"xmlns,attr,omitempty"`
+	XMLName   xml.Name `xml:"CORSConfiguration"`
+	CORSRules []Rule   `xml:"CORSRule"`
+}
+
+// Validate - validates the CORS configuration.
+func (c Config) Validate() error {
+	if len(c.CORSRules) == 0 {
+		return errNoRules
+	}
+	if len(c.CORSRules) > maxRules {
+		return errTooManyRules
+	}
+	for _, rule := range c.CORSRules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Match - returns the first rule which allows a request from origin
+// using method and sending the given request headers, rules are
+// evaluated in the order they appear in the configuration. Returns
+// nil if no rule matches.
+func (c *Config) Match(origin, method string, headers []string) *Rule {
+	if c == nil || origin == "" {
+		return nil
+	}
+	for i := range c.CORSRules {
+		rule := &c.CORSRules[i]
+		if rule.matchOrigin(origin) && rule.matchMethod(method) && rule.matchHeaders(headers) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// ParseConfig - parses data in given reader to CORSConfiguration.
+func ParseConfig(reader io.Reader) (*Config, error) {
+	var c Config
+	if err := xml.NewDecoder(reader).Decode(&c); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if c.XMLNS == "" {
+		c.XMLNS = xmlNS
+	}
+	return &c, nil
+}
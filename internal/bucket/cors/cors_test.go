@@ -0,0 +1,162 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		input string
+		err   error
+	}{
+		{
+			input: `<CORSConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <CORSRule>
+                                    <AllowedOrigin>https://*.example.com</AllowedOrigin>
+                                    <AllowedMethod>GET</AllowedMethod>
+                                    <AllowedMethod>PUT</AllowedMethod>
+                                    <AllowedHeader>*</AllowedHeader>
+                                    <ExposeHeader>ETag</ExposeHeader>
+                                    <MaxAgeSeconds>3000</MaxAgeSeconds>
+                                  </CORSRule>
+                                </CORSConfiguration>`,
+			err: nil,
+		},
+		{
+			input: `<CORSConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                </CORSConfiguration>`,
+			err: errNoRules,
+		},
+		{
+			input: `<CORSConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <CORSRule>
+                                    <AllowedOrigin>*</AllowedOrigin>
+                                  </CORSRule>
+                                </CORSConfiguration>`,
+			err: errMissingMethods,
+		},
+		{
+			input: `<CORSConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <CORSRule>
+                                    <AllowedMethod>GET</AllowedMethod>
+                                  </CORSRule>
+                                </CORSConfiguration>`,
+			err: errMissingOrigins,
+		},
+		{
+			input: `<CORSConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <CORSRule>
+                                    <AllowedOrigin>https://*.*.example.com</AllowedOrigin>
+                                    <AllowedMethod>GET</AllowedMethod>
+                                  </CORSRule>
+                                </CORSConfiguration>`,
+			err: errTooManyWildcards,
+		},
+		{
+			input: `<CORSConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <CORSRule>
+                                    <AllowedOrigin>*</AllowedOrigin>
+                                    <AllowedMethod>GET</AllowedMethod>
+                                    <ExposeHeader>x-amz-*</ExposeHeader>
+                                  </CORSRule>
+                                </CORSConfiguration>`,
+			err: errWildcardExposed,
+		},
+		{
+			input: `<CORSConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <CORSRule>
+                                    <AllowedOrigin>*</AllowedOrigin>
+                                    <AllowedMethod>GET</AllowedMethod>
+                                    <MaxAgeSeconds>-1</MaxAgeSeconds>
+                                  </CORSRule>
+                                </CORSConfiguration>`,
+			err: errNegativeMaxAge,
+		},
+	}
+
+	for i, tc := range testCases {
+		_, err := ParseConfig(strings.NewReader(tc.input))
+		if err != tc.err {
+			t.Fatalf("Test %d: expected %v but got %v", i+1, tc.err, err)
+		}
+	}
+
+	// Unsupported methods are rejected.
+	_, err := ParseConfig(strings.NewReader(`<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin><AllowedMethod>PATCH</AllowedMethod></CORSRule></CORSConfiguration>`))
+	if err == nil {
+		t.Fatal("expected PATCH to be rejected as an unsupported method")
+	}
+}
+
+func TestConfigMatch(t *testing.T) {
+	config, err := ParseConfig(strings.NewReader(`<CORSConfiguration>
+  <CORSRule>
+    <ID>uploads</ID>
+    <AllowedOrigin>https://*.example.com</AllowedOrigin>
+    <AllowedMethod>PUT</AllowedMethod>
+    <AllowedMethod>POST</AllowedMethod>
+    <AllowedHeader>Content-*</AllowedHeader>
+    <AllowedHeader>x-amz-meta-*</AllowedHeader>
+  </CORSRule>
+  <CORSRule>
+    <ID>reads</ID>
+    <AllowedOrigin>*</AllowedOrigin>
+    <AllowedMethod>GET</AllowedMethod>
+    <AllowedMethod>HEAD</AllowedMethod>
+  </CORSRule>
+</CORSConfiguration>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		origin  string
+		method  string
+		headers []string
+		ruleID  string
+	}{
+		{"https://app.example.com", "PUT", []string{"Content-Type", "X-Amz-Meta-Owner"}, "uploads"},
+		{"https://app.example.com", "PUT", nil, "uploads"},
+		{"https://app.example.com", "PUT", []string{"Authorization"}, ""},
+		{"https://app.example.org", "PUT", nil, ""},
+		{"https://app.example.org", "GET", nil, "reads"},
+		{"https://app.example.org", "GET", []string{"Range"}, ""},
+		{"https://app.example.org", "DELETE", nil, ""},
+		{"", "GET", nil, ""},
+	}
+
+	for i, tc := range testCases {
+		rule := config.Match(tc.origin, tc.method, tc.headers)
+		switch {
+		case rule == nil && tc.ruleID != "":
+			t.Errorf("Test %d: expected rule %s to match", i+1, tc.ruleID)
+		case rule != nil && rule.ID != tc.ruleID:
+			t.Errorf("Test %d: expected rule %q to match, got %q", i+1, tc.ruleID, rule.ID)
+		}
+	}
+
+	if !config.CORSRules[1].AllowsAnyOrigin() {
+		t.Error("expected rule to allow any origin")
+	}
+	if config.CORSRules[0].AllowsAnyOrigin() {
+		t.Error("expected rule to not allow any origin")
+	}
+}
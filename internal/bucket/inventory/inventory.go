@@ -0,0 +1,311 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package inventory
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// Maximum number of inventory configurations allowed on a bucket
+	// (same as AWS S3).
+	maxConfigs = 1000
+
+	// Maximum length of an inventory configuration ID.
+	maxIDLength = 64
+
+	// bucketARNPrefix - destination buckets are given as ARNs.
+	bucketARNPrefix = "arn:aws:s3:::"
+
+	xmlNS = "http://s3.amazonaws.com/doc/2006-03-01/"
+)
+
+// Report formats.
+const (
+	FormatCSV     = "CSV"
+	FormatORC     = "ORC"
+	FormatParquet = "Parquet"
+)
+
+// Report schedules.
+const (
+	FrequencyDaily  = "Daily"
+	FrequencyWeekly = "Weekly"
+)
+
+// Object versions included in a report.
+const (
+	VersionsAll     = "All"
+	VersionsCurrent = "Current"
+)
+
+var (
+	errInvalidID              = Errorf("Id must be between 1 and 64 characters and only contain letters, numbers, '.', '-' and '_'")
+	errMissingDestination     = Errorf("Destination must specify an S3BucketDestination")
+	errInvalidDestination     = Errorf("S3BucketDestination Bucket must be a bucket ARN of the form arn:aws:s3:::bucket")
+	errInvalidFormat          = Errorf("S3BucketDestination Format must be one of CSV, ORC or Parquet")
+	errInvalidPrefix          = Errorf("S3BucketDestination Prefix must not start with a slash")
+	errInvalidFrequency       = Errorf("Schedule Frequency must be either Daily or Weekly")
+	errInvalidIncludedVersion = Errorf("IncludedObjectVersions must be either All or Current")
+	errTooManyConfigs         = Errorf("A bucket allows a maximum of 1000 inventory configurations")
+)
+
+// S3BucketDestination - the bucket reports are written to.
+type S3BucketDestination struct {
+	AccountID  string      `xml:"AccountId,omitempty"`
+	Bucket     string      `xml:"Bucket"`
+	Format     string      `xml:"Format"`
+	Prefix     string      `xml:"Prefix,omitempty"`
+	Encryption *Encryption `xml:"Encryption,omitempty"`
+}
+
+// Encryption - server side encryption of the reports.
+type Encryption struct {
+	SSES3  *struct{} `xml:"SSE-S3,omitempty"`
+	SSEKMS *struct {
+		KeyID string `xml:"KeyId"`
+	} `xml:"SSE-KMS,omitempty"`
+}
+
+// Destination - where reports are written to.
+type Destination struct {
+	S3BucketDestination *S3BucketDestination `xml:"S3BucketDestination"`
+}
+
+// Filter - limits a report to the objects under a prefix.
+type Filter struct {
+	Prefix string `xml:"Prefix,omitempty"`
+}
+
+// Schedule - how often reports are generated.
+type Schedule struct {
+	Frequency string `xml:"Frequency"`
+}
+
+// OptionalFields - the object metadata included in the reports.
+type OptionalFields struct {
+	Fields []string `xml:"Field"`
+}
+
+// Config - a bucket inventory configuration.
+type Config struct {
+	XMLNS                  string          `xml:"xmlns,attr,omitempty"`
+	XMLName                xml.Name        `xml:"InventoryConfiguration"`
+	ID                     string          `xml:"Id"`
+	IsEnabled              bool            `xml:"IsEnabled"`
+	Destination            Destination     `xml:"Destination"`
+	Filter                 *Filter         `xml:"Filter,omitempty"`
+	IncludedObjectVersions string          `xml:"IncludedObjectVersions"`
+	OptionalFields         *OptionalFields `xml:"OptionalFields,omitempty"`
+	Schedule               Schedule        `xml:"Schedule"`
+}
+
+func validID(id string) bool {
+	if id == "" || len(id) > maxIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '.', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Validate - validates the inventory configuration.
+func (c Config) Validate() error {
+	if !validID(c.ID) {
+		return errInvalidID
+	}
+	dst := c.Destination.S3BucketDestination
+	if dst == nil {
+		return errMissingDestination
+	}
+	if !strings.HasPrefix(dst.Bucket, bucketARNPrefix) || dst.Bucket == bucketARNPrefix {
+		return errInvalidDestination
+	}
+	switch dst.Format {
+	case FormatCSV, FormatORC, FormatParquet:
+	default:
+		return errInvalidFormat
+	}
+	if strings.HasPrefix(dst.Prefix, "/") {
+		return errInvalidPrefix
+	}
+	switch c.Schedule.Frequency {
+	case FrequencyDaily, FrequencyWeekly:
+	default:
+		return errInvalidFrequency
+	}
+	switch c.IncludedObjectVersions {
+	case VersionsAll, VersionsCurrent:
+	default:
+		return errInvalidIncludedVersion
+	}
+	if c.OptionalFields != nil {
+		for _, field := range c.OptionalFields.Fields {
+			if _, ok := optionalFields[field]; !ok {
+				return Errorf("Unsupported inventory optional field %s", field)
+			}
+		}
+	}
+	return nil
+}
+
+// DestinationBucket - returns the name of the destination bucket.
+func (c Config) DestinationBucket() string {
+	return strings.TrimPrefix(c.Destination.S3BucketDestination.Bucket, bucketARNPrefix)
+}
+
+// Prefix - returns the prefix of the objects included in the reports.
+func (c Config) Prefix() string {
+	if c.Filter == nil {
+		return ""
+	}
+	return c.Filter.Prefix
+}
+
+// Interval - returns the interval between two reports.
+func (c Config) Interval() time.Duration {
+	if c.Schedule.Frequency == FrequencyWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// Due - returns true if a report generated last at lastRun is due
+// at now.
+func (c Config) Due(lastRun, now time.Time) bool {
+	return c.IsEnabled && (lastRun.IsZero() || now.Sub(lastRun) >= c.Interval())
+}
+
+// Fields - returns the columns of the reports in order, Bucket and Key
+// are always included, VersionId, IsLatest and IsDeleteMarker when all
+// object versions are included, followed by the optional fields.
+func (c Config) Fields() []string {
+	fields := []string{FieldBucket, FieldKey}
+	if c.IncludedObjectVersions == VersionsAll {
+		fields = append(fields, FieldVersionID, FieldIsLatest, FieldIsDeleteMarker)
+	}
+	if c.OptionalFields == nil {
+		return fields
+	}
+	requested := make(map[string]struct{}, len(c.OptionalFields.Fields))
+	for _, field := range c.OptionalFields.Fields {
+		requested[field] = struct{}{}
+	}
+	for _, field := range optionalFieldsOrder {
+		if _, ok := requested[field]; ok {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// ParseConfig - parses data in given reader to InventoryConfiguration.
+func ParseConfig(reader io.Reader) (*Config, error) {
+	var c Config
+	if err := xml.NewDecoder(reader).Decode(&c); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if c.XMLNS == "" {
+		c.XMLNS = xmlNS
+	}
+	return &c, nil
+}
+
+// Configs - all inventory configurations of a bucket.
+type Configs struct {
+	XMLName xml.Name `xml:"InventoryConfigurations"`
+	Configs []Config `xml:"InventoryConfiguration"`
+}
+
+// Get - returns the inventory configuration with the given ID, nil
+// if not found.
+func (c *Configs) Get(id string) *Config {
+	if c == nil {
+		return nil
+	}
+	for i := range c.Configs {
+		if c.Configs[i].ID == id {
+			return &c.Configs[i]
+		}
+	}
+	return nil
+}
+
+// Put - returns a copy of the configurations with cfg added, or
+// replacing the configuration with the same ID.
+func (c *Configs) Put(cfg Config) (*Configs, error) {
+	updated := &Configs{}
+	if c != nil {
+		updated.Configs = make([]Config, 0, len(c.Configs)+1)
+		for _, existing := range c.Configs {
+			if existing.ID != cfg.ID {
+				updated.Configs = append(updated.Configs, existing)
+			}
+		}
+	}
+	updated.Configs = append(updated.Configs, cfg)
+	if len(updated.Configs) > maxConfigs {
+		return nil, errTooManyConfigs
+	}
+	return updated, nil
+}
+
+// Remove - returns a copy of the configurations without the
+// configuration with the given ID.
+func (c *Configs) Remove(id string) *Configs {
+	updated := &Configs{}
+	if c == nil {
+		return updated
+	}
+	for _, existing := range c.Configs {
+		if existing.ID != id {
+			updated.Configs = append(updated.Configs, existing)
+		}
+	}
+	return updated
+}
+
+// ParseConfigs - parses data in given reader to the inventory
+// configurations of a bucket.
+func ParseConfigs(reader io.Reader) (*Configs, error) {
+	var c Configs
+	if err := xml.NewDecoder(reader).Decode(&c); err != nil {
+		return nil, err
+	}
+	if len(c.Configs) > maxConfigs {
+		return nil, errTooManyConfigs
+	}
+	for _, cfg := range c.Configs {
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &c, nil
+}
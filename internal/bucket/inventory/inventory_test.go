@@ -0,0 +1,217 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package inventory
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	parquetgo "github.com/fraugster/parquet-go"
+)
+
+const testConfig = `<InventoryConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Id>report1</Id>
+  <IsEnabled>true</IsEnabled>
+  <Destination>
+    <S3BucketDestination>
+      <Format>CSV</Format>
+      <Bucket>arn:aws:s3:::reports</Bucket>
+      <Prefix>inventory</Prefix>
+    </S3BucketDestination>
+  </Destination>
+  <Filter><Prefix>photos/</Prefix></Filter>
+  <IncludedObjectVersions>All</IncludedObjectVersions>
+  <OptionalFields>
+    <Field>ETag</Field>
+    <Field>Size</Field>
+  </OptionalFields>
+  <Schedule><Frequency>Daily</Frequency></Schedule>
+</InventoryConfiguration>`
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		input string
+		err   error
+	}{
+		{input: testConfig},
+		{input: strings.Replace(testConfig, "report1", "report 1", 1), err: errInvalidID},
+		{input: strings.Replace(testConfig, "arn:aws:s3:::reports", "reports", 1), err: errInvalidDestination},
+		{input: strings.Replace(testConfig, "<Format>CSV", "<Format>JSON", 1), err: errInvalidFormat},
+		{input: strings.Replace(testConfig, "<Prefix>inventory", "<Prefix>/inventory", 1), err: errInvalidPrefix},
+		{input: strings.Replace(testConfig, "Daily", "Hourly", 1), err: errInvalidFrequency},
+		{input: strings.Replace(testConfig, "<IncludedObjectVersions>All", "<IncludedObjectVersions>Some", 1), err: errInvalidIncludedVersion},
+	}
+
+	for i, tc := range testCases {
+		_, err := ParseConfig(strings.NewReader(tc.input))
+		if err != tc.err {
+			t.Fatalf("Test %d: expected %v but got %v", i+1, tc.err, err)
+		}
+	}
+
+	if _, err := ParseConfig(strings.NewReader(strings.Replace(testConfig, "<Field>ETag", "<Field>Owner", 1))); err == nil {
+		t.Fatal("expected unsupported optional field to fail")
+	}
+}
+
+func TestConfigFields(t *testing.T) {
+	cfg, err := ParseConfig(strings.NewReader(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DestinationBucket() != "reports" || cfg.Prefix() != "photos/" {
+		t.Fatalf("unexpected destination %s or prefix %s", cfg.DestinationBucket(), cfg.Prefix())
+	}
+	expected := []string{FieldBucket, FieldKey, FieldVersionID, FieldIsLatest, FieldIsDeleteMarker, FieldSize, FieldETag}
+	if fields := cfg.Fields(); !reflect.DeepEqual(fields, expected) {
+		t.Fatalf("expected %v but got %v", expected, fields)
+	}
+
+	cfg.IncludedObjectVersions = VersionsCurrent
+	expected = []string{FieldBucket, FieldKey, FieldSize, FieldETag}
+	if fields := cfg.Fields(); !reflect.DeepEqual(fields, expected) {
+		t.Fatalf("expected %v but got %v", expected, fields)
+	}
+}
+
+func TestConfigDue(t *testing.T) {
+	cfg, err := ParseConfig(strings.NewReader(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		frequency string
+		lastRun   time.Time
+		due       bool
+	}{
+		{FrequencyDaily, time.Time{}, true},
+		{FrequencyDaily, now.Add(-23 * time.Hour), false},
+		{FrequencyDaily, now.Add(-24 * time.Hour), true},
+		{FrequencyWeekly, now.Add(-6 * 24 * time.Hour), false},
+		{FrequencyWeekly, now.Add(-7 * 24 * time.Hour), true},
+	}
+	for i, tc := range testCases {
+		cfg.Schedule.Frequency = tc.frequency
+		if due := cfg.Due(tc.lastRun, now); due != tc.due {
+			t.Errorf("Test %d: expected due to be %v", i+1, tc.due)
+		}
+	}
+
+	cfg.IsEnabled = false
+	if cfg.Due(time.Time{}, now) {
+		t.Fatal("expected disabled configuration to never be due")
+	}
+}
+
+func TestConfigs(t *testing.T) {
+	cfg, err := ParseConfig(strings.NewReader(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var configs *Configs
+	if configs.Get(cfg.ID) != nil {
+		t.Fatal("expected no configuration")
+	}
+	configs, err = configs.Put(*cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.IsEnabled = false
+	configs, err = configs.Put(*cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(configs.Configs) != 1 || configs.Get(cfg.ID).IsEnabled {
+		t.Fatal("expected configuration to be replaced")
+	}
+	if configs = configs.Remove(cfg.ID); len(configs.Configs) != 0 {
+		t.Fatal("expected configuration to be removed")
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatCSV, &buf, []string{FieldBucket, FieldKey, FieldIsLatest, FieldSize, FieldLastModifiedDate, FieldETag})
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := []Record{
+		{Bucket: "photos", Key: "cat, 1.jpg", IsLatest: true, Size: 1024, LastModifiedDate: time.Date(2023, time.March, 1, 10, 0, 0, 0, time.UTC), ETag: "abc"},
+		{Bucket: "photos", Key: "dog.jpg", IsDeleteMarker: true},
+	}
+	for _, r := range records {
+		if err = w.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "photos,\"cat, 1.jpg\",true,1024,2023-03-01T10:00:00.000Z,abc\nphotos,dog.jpg,false,,,\n"
+	if string(data) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, data)
+	}
+}
+
+func TestParquetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatParquet, &buf, []string{FieldBucket, FieldKey, FieldSize, FieldLastModifiedDate, FieldETag})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Write(Record{Bucket: "photos", Key: "cat.jpg", Size: 1024, LastModifiedDate: time.Unix(1677664800, 0), ETag: "abc"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := parquetgo.NewFileReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	row, err := r.NextRow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(row[FieldKey].([]byte)) != "cat.jpg" || row[FieldSize].(int64) != 1024 ||
+		row[FieldLastModifiedDate].(int64) != 1677664800000 {
+		t.Fatalf("unexpected row %v", row)
+	}
+}
+
+func TestUnsupportedWriter(t *testing.T) {
+	if _, err := NewWriter(FormatORC, io.Discard, []string{FieldBucket, FieldKey}); err == nil {
+		t.Fatal("expected ORC reports to be unsupported")
+	}
+}
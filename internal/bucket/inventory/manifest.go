@@ -0,0 +1,59 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package inventory
+
+import (
+	"strconv"
+	"time"
+)
+
+// manifestVersion - version of the manifest format (same as AWS S3).
+const manifestVersion = "2016-11-30"
+
+// ManifestFile - a data file of a report.
+type ManifestFile struct {
+	Key         string `json:"key"`
+	Size        int64  `json:"size"`
+	MD5Checksum string `json:"MD5checksum"`
+}
+
+// Manifest - lists the data files of a report, written as
+// manifest.json next to a manifest.checksum holding its MD5 sum.
+type Manifest struct {
+	SourceBucket      string         `json:"sourceBucket"`
+	DestinationBucket string         `json:"destinationBucket"`
+	Version           string         `json:"version"`
+	CreationTimestamp string         `json:"creationTimestamp"`
+	FileFormat        string         `json:"fileFormat"`
+	FileSchema        string         `json:"fileSchema"`
+	Files             []ManifestFile `json:"files"`
+}
+
+// NewManifest - returns an empty manifest of a report generated for
+// bucket with cfg at the given time.
+func NewManifest(bucket string, cfg Config, created time.Time) *Manifest {
+	return &Manifest{
+		SourceBucket:      bucket,
+		DestinationBucket: cfg.Destination.S3BucketDestination.Bucket,
+		Version:           manifestVersion,
+		CreationTimestamp: strconv.FormatInt(created.UnixMilli(), 10),
+		FileFormat:        cfg.Destination.S3BucketDestination.Format,
+		FileSchema:        FileSchema(cfg.Destination.S3BucketDestination.Format, cfg.Fields()),
+		Files:             []ManifestFile{},
+	}
+}
@@ -0,0 +1,318 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package inventory
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	parquetgo "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+)
+
+// Report fields.
+const (
+	FieldBucket                       = "Bucket"
+	FieldKey                          = "Key"
+	FieldVersionID                    = "VersionId"
+	FieldIsLatest                     = "IsLatest"
+	FieldIsDeleteMarker               = "IsDeleteMarker"
+	FieldSize                         = "Size"
+	FieldLastModifiedDate             = "LastModifiedDate"
+	FieldStorageClass                 = "StorageClass"
+	FieldETag                         = "ETag"
+	FieldIsMultipartUploaded          = "IsMultipartUploaded"
+	FieldReplicationStatus            = "ReplicationStatus"
+	FieldEncryptionStatus             = "EncryptionStatus"
+	FieldObjectLockRetainUntilDate    = "ObjectLockRetainUntilDate"
+	FieldObjectLockMode               = "ObjectLockMode"
+	FieldObjectLockLegalHoldStatus    = "ObjectLockLegalHoldStatus"
+	FieldIntelligentTieringAccessTier = "IntelligentTieringAccessTier"
+	FieldBucketKeyStatus              = "BucketKeyStatus"
+	FieldChecksumAlgorithm            = "ChecksumAlgorithm"
+)
+
+// optionalFieldsOrder - the optional fields in the order they appear
+// in the reports.
+var optionalFieldsOrder = []string{
+	FieldSize,
+	FieldLastModifiedDate,
+	FieldStorageClass,
+	FieldETag,
+	FieldIsMultipartUploaded,
+	FieldReplicationStatus,
+	FieldEncryptionStatus,
+	FieldObjectLockRetainUntilDate,
+	FieldObjectLockMode,
+	FieldObjectLockLegalHoldStatus,
+	FieldIntelligentTieringAccessTier,
+	FieldBucketKeyStatus,
+	FieldChecksumAlgorithm,
+}
+
+var optionalFields = func() map[string]struct{} {
+	m := make(map[string]struct{}, len(optionalFieldsOrder))
+	for _, field := range optionalFieldsOrder {
+		m[field] = struct{}{}
+	}
+	return m
+}()
+
+// timeFormat - time format of the date fields in CSV reports.
+const timeFormat = "2006-01-02T15:04:05.000Z"
+
+// Record - a single object version listed in a report.
+type Record struct {
+	Bucket                       string
+	Key                          string
+	VersionID                    string
+	IsLatest                     bool
+	IsDeleteMarker               bool
+	Size                         int64
+	LastModifiedDate             time.Time
+	StorageClass                 string
+	ETag                         string
+	IsMultipartUploaded          bool
+	ReplicationStatus            string
+	EncryptionStatus             string
+	ObjectLockRetainUntilDate    time.Time
+	ObjectLockMode               string
+	ObjectLockLegalHoldStatus    string
+	IntelligentTieringAccessTier string
+	BucketKeyStatus              string
+	ChecksumAlgorithm            string
+}
+
+// value - returns the value of field, nil if the record has no value
+// for it. Strings, int64, bool and time.Time values are returned.
+func (r Record) value(field string) interface{} {
+	str := func(s string) interface{} {
+		if s == "" {
+			return nil
+		}
+		return s
+	}
+	date := func(t time.Time) interface{} {
+		if t.IsZero() {
+			return nil
+		}
+		return t.UTC()
+	}
+	switch field {
+	case FieldBucket:
+		return r.Bucket
+	case FieldKey:
+		return r.Key
+	case FieldVersionID:
+		return str(r.VersionID)
+	case FieldIsLatest:
+		return r.IsLatest
+	case FieldIsDeleteMarker:
+		return r.IsDeleteMarker
+	case FieldSize:
+		if r.IsDeleteMarker {
+			return nil
+		}
+		return r.Size
+	case FieldLastModifiedDate:
+		return date(r.LastModifiedDate)
+	case FieldStorageClass:
+		return str(r.StorageClass)
+	case FieldETag:
+		return str(r.ETag)
+	case FieldIsMultipartUploaded:
+		return r.IsMultipartUploaded
+	case FieldReplicationStatus:
+		return str(r.ReplicationStatus)
+	case FieldEncryptionStatus:
+		return str(r.EncryptionStatus)
+	case FieldObjectLockRetainUntilDate:
+		return date(r.ObjectLockRetainUntilDate)
+	case FieldObjectLockMode:
+		return str(r.ObjectLockMode)
+	case FieldObjectLockLegalHoldStatus:
+		return str(r.ObjectLockLegalHoldStatus)
+	case FieldIntelligentTieringAccessTier:
+		return str(r.IntelligentTieringAccessTier)
+	case FieldBucketKeyStatus:
+		return str(r.BucketKeyStatus)
+	case FieldChecksumAlgorithm:
+		return str(r.ChecksumAlgorithm)
+	}
+	return nil
+}
+
+// Writer - writes the records of a report data file.
+type Writer interface {
+	// Write - adds a record to the data file.
+	Write(Record) error
+	// Close - flushes the data file, the underlying writer is not
+	// closed.
+	Close() error
+}
+
+// FormatSupported - returns true if reports can be written in format.
+func FormatSupported(format string) bool {
+	return format == FormatCSV || format == FormatParquet
+}
+
+// NewWriter - returns a Writer writing records with the given fields
+// in format to w.
+func NewWriter(format string, w io.Writer, fields []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, fields), nil
+	case FormatParquet:
+		return newParquetWriter(w, fields)
+	}
+	return nil, Errorf("Inventory report format %s is not supported", format)
+}
+
+// FileExtension - returns the file extension of data files written in
+// format.
+func FileExtension(format string) string {
+	switch format {
+	case FormatCSV:
+		return ".csv.gz"
+	case FormatParquet:
+		return ".parquet"
+	case FormatORC:
+		return ".orc"
+	}
+	return ""
+}
+
+// FileSchema - returns the schema of data files with the given fields
+// written in format, as listed in the manifest.
+func FileSchema(format string, fields []string) string {
+	if format == FormatParquet {
+		return parquetSchema(fields)
+	}
+	return strings.Join(fields, ", ")
+}
+
+// csvWriter - writes gzip compressed CSV data files without header,
+// the columns are listed by the manifest instead.
+type csvWriter struct {
+	fields []string
+	gz     *gzip.Writer
+	csv    *csv.Writer
+	row    []string
+}
+
+func newCSVWriter(w io.Writer, fields []string) *csvWriter {
+	gz := gzip.NewWriter(w)
+	return &csvWriter{
+		fields: fields,
+		gz:     gz,
+		csv:    csv.NewWriter(gz),
+		row:    make([]string, len(fields)),
+	}
+}
+
+func (w *csvWriter) Write(r Record) error {
+	for i, field := range w.fields {
+		switch v := r.value(field).(type) {
+		case string:
+			w.row[i] = v
+		case int64:
+			w.row[i] = strconv.FormatInt(v, 10)
+		case bool:
+			w.row[i] = strconv.FormatBool(v)
+		case time.Time:
+			w.row[i] = v.Format(timeFormat)
+		default:
+			w.row[i] = ""
+		}
+	}
+	return w.csv.Write(w.row)
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
+
+// parquetWriter - writes snappy compressed Parquet data files.
+type parquetWriter struct {
+	fields []string
+	fw     *parquetgo.FileWriter
+}
+
+func parquetSchema(fields []string) string {
+	var b strings.Builder
+	b.WriteString("message s3.inventory {\n")
+	for _, field := range fields {
+		switch field {
+		case FieldBucket, FieldKey:
+			b.WriteString("  required binary " + field + " (STRING);\n")
+		case FieldSize:
+			b.WriteString("  optional int64 " + field + ";\n")
+		case FieldIsLatest, FieldIsDeleteMarker, FieldIsMultipartUploaded:
+			b.WriteString("  optional boolean " + field + ";\n")
+		case FieldLastModifiedDate, FieldObjectLockRetainUntilDate:
+			b.WriteString("  optional int64 " + field + " (TIMESTAMP(MILLIS,true));\n")
+		default:
+			b.WriteString("  optional binary " + field + " (STRING);\n")
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func newParquetWriter(w io.Writer, fields []string) (*parquetWriter, error) {
+	sd, err := parquetschema.ParseSchemaDefinition(parquetSchema(fields))
+	if err != nil {
+		return nil, err
+	}
+	return &parquetWriter{
+		fields: fields,
+		fw: parquetgo.NewFileWriter(w,
+			parquetgo.WithSchemaDefinition(sd),
+			parquetgo.WithCompressionCodec(parquet.CompressionCodec_SNAPPY),
+		),
+	}, nil
+}
+
+func (w *parquetWriter) Write(r Record) error {
+	row := make(map[string]interface{}, len(w.fields))
+	for _, field := range w.fields {
+		switch v := r.value(field).(type) {
+		case string:
+			row[field] = []byte(v)
+		case time.Time:
+			row[field] = v.UnixMilli()
+		case nil:
+		default:
+			row[field] = v
+		}
+	}
+	return w.fw.AddData(row)
+}
+
+func (w *parquetWriter) Close() error {
+	return w.fw.Close()
+}
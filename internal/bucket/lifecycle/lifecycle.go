@@ -257,6 +257,7 @@ type ObjectOpts struct {
 	NumVersions      int
 	SuccessorModTime time.Time
 	TransitionStatus string
+	StorageClass     string
 	RestoreOngoing   bool
 	RestoreExpires   time.Time
 }
@@ -385,7 +386,10 @@ func (lc Lifecycle) eval(obj ObjectOpts, now time.Time) Event {
 		}
 
 		if !obj.IsLatest && !rule.NoncurrentVersionTransition.IsNull() {
-			if !obj.DeleteMarker && obj.TransitionStatus != TransitionComplete {
+			// Versions already in the target storage class, e.g. a custom
+			// storage class of this cluster, need no transition.
+			if !obj.DeleteMarker && obj.TransitionStatus != TransitionComplete &&
+				obj.StorageClass != rule.NoncurrentVersionTransition.StorageClass {
 				// Non current versions should be transitioned if their age exceeds non current days configuration
 				// https://docs.aws.amazon.com/AmazonS3/latest/dev/intro-lifecycle-rules.html#intro-lifecycle-rules-actions
 				if due, ok := rule.NoncurrentVersionTransition.NextDue(obj); ok && (now.IsZero() || now.After(due)) {
@@ -420,7 +424,7 @@ func (lc Lifecycle) eval(obj ObjectOpts, now time.Time) Event {
 				}
 			}
 
-			if obj.TransitionStatus != TransitionComplete {
+			if obj.TransitionStatus != TransitionComplete && obj.StorageClass != rule.Transition.StorageClass {
 				if due, ok := rule.Transition.NextDue(obj); ok && (now.IsZero() || now.After(due)) {
 					events = append(events, Event{
 						Action:       TransitionAction,
@@ -751,6 +751,16 @@ func TestTransitionTier(t *testing.T) {
 	if evt.StorageClass != "TIER-2" {
 		t.Fatalf("Expected TIER-2 but got %s", evt.StorageClass)
 	}
+
+	// Objects already in the target storage class are not transitioned.
+	obj1.StorageClass = "TIER-1"
+	if evt = lc.eval(obj1, now); evt.Action != NoneAction {
+		t.Fatalf("Expected action: %s but got %s", NoneAction, evt.Action)
+	}
+	obj2.StorageClass = "TIER-2"
+	if evt = lc.eval(obj2, now); evt.Action != NoneAction {
+		t.Fatalf("Expected action: %s but got %s", NoneAction, evt.Action)
+	}
 }
 
 func TestTransitionTierWithPrefixAndTags(t *testing.T) {
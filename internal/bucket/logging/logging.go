@@ -0,0 +1,88 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logging
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+const (
+	// Maximum length of the target prefix, leaves room for the
+	// generated part of the log object names.
+	maxTargetPrefixLength = 512
+
+	xmlNS = "http://s3.amazonaws.com/doc/2006-03-01/"
+)
+
+var (
+	errMissingTargetBucket = Errorf("LoggingEnabled must specify a TargetBucket")
+	errTargetPrefixTooLong = Errorf("TargetPrefix length is limited to 512 characters")
+	errInvalidTargetPrefix = Errorf("TargetPrefix must not start with a slash")
+)
+
+// LoggingEnabled - where the access logs of a bucket are delivered.
+type LoggingEnabled struct {
+	TargetBucket string `xml:"TargetBucket"`
+	TargetPrefix string `xml:"TargetPrefix"`
+}
+
+// Config - bucket logging status.
+type Config struct {
+	XMLNS          string          `xml:"xmlns,attr,omitempty"`
+	XMLName        xml.Name        `xml:"BucketLoggingStatus"`
+	LoggingEnabled *LoggingEnabled `xml:"LoggingEnabled,omitempty"`
+}
+
+// Enabled - returns true if access logging is enabled.
+func (c *Config) Enabled() bool {
+	return c != nil && c.LoggingEnabled != nil
+}
+
+// Validate - validates the bucket logging status.
+func (c Config) Validate() error {
+	if c.LoggingEnabled == nil {
+		return nil
+	}
+	if c.LoggingEnabled.TargetBucket == "" {
+		return errMissingTargetBucket
+	}
+	if len(c.LoggingEnabled.TargetPrefix) > maxTargetPrefixLength {
+		return errTargetPrefixTooLong
+	}
+	if strings.HasPrefix(c.LoggingEnabled.TargetPrefix, "/") {
+		return errInvalidTargetPrefix
+	}
+	return nil
+}
+
+// ParseConfig - parses data in given reader to BucketLoggingStatus.
+func ParseConfig(reader io.Reader) (*Config, error) {
+	var c Config
+	if err := xml.NewDecoder(reader).Decode(&c); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if c.XMLNS == "" {
+		c.XMLNS = xmlNS
+	}
+	return &c, nil
+}
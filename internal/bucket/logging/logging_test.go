@@ -0,0 +1,126 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logging
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		input   string
+		err     error
+		enabled bool
+	}{
+		{
+			input: `<BucketLoggingStatus xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <LoggingEnabled>
+                                    <TargetBucket>logs</TargetBucket>
+                                    <TargetPrefix>access/</TargetPrefix>
+                                  </LoggingEnabled>
+                                </BucketLoggingStatus>`,
+			enabled: true,
+		},
+		{
+			input:   `<BucketLoggingStatus xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></BucketLoggingStatus>`,
+			enabled: false,
+		},
+		{
+			input: `<BucketLoggingStatus xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <LoggingEnabled>
+                                    <TargetPrefix>access/</TargetPrefix>
+                                  </LoggingEnabled>
+                                </BucketLoggingStatus>`,
+			err: errMissingTargetBucket,
+		},
+		{
+			input: `<BucketLoggingStatus xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <LoggingEnabled>
+                                    <TargetBucket>logs</TargetBucket>
+                                    <TargetPrefix>/access/</TargetPrefix>
+                                  </LoggingEnabled>
+                                </BucketLoggingStatus>`,
+			err: errInvalidTargetPrefix,
+		},
+	}
+
+	for i, tc := range testCases {
+		config, err := ParseConfig(strings.NewReader(tc.input))
+		if err != tc.err {
+			t.Fatalf("Test %d: expected %v but got %v", i+1, tc.err, err)
+		}
+		if err == nil && config.Enabled() != tc.enabled {
+			t.Fatalf("Test %d: expected enabled to be %v", i+1, tc.enabled)
+		}
+	}
+}
+
+func TestOperation(t *testing.T) {
+	testCases := []struct {
+		method   string
+		query    string
+		key      string
+		expected string
+	}{
+		{"GET", "", "photos/cat.jpg", "REST.GET.OBJECT"},
+		{"PUT", "", "", "REST.PUT.BUCKET"},
+		{"PUT", "uploadId=abc&partNumber=1", "video.mp4", "REST.PUT.UPLOAD"},
+		{"POST", "uploads", "video.mp4", "REST.POST.UPLOADS"},
+		{"get", "tagging", "", "REST.GET.TAGGING"},
+		{"POST", "delete", "", "REST.POST.MULTI_OBJECT_DELETE"},
+	}
+	for i, tc := range testCases {
+		query, err := url.ParseQuery(tc.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Operation(tc.method, query, tc.key); got != tc.expected {
+			t.Errorf("Test %d: expected %s but got %s", i+1, tc.expected, got)
+		}
+	}
+}
+
+func TestRecordString(t *testing.T) {
+	record := Record{
+		BucketOwner:      "owner",
+		Bucket:           "photos",
+		Time:             time.Date(2023, time.February, 6, 0, 0, 38, 0, time.UTC),
+		RemoteIP:         "192.0.2.3",
+		Requester:        "alice",
+		RequestID:        "17416A3C8A2D7B1E",
+		Operation:        "REST.GET.OBJECT",
+		Key:              "2023/cat photo.jpg",
+		RequestURI:       "GET /photos/2023/cat%20photo.jpg HTTP/1.1",
+		HTTPStatus:       200,
+		BytesSent:        2662992,
+		ObjectSize:       3462992,
+		TotalTime:        70 * time.Millisecond,
+		TurnAroundTime:   10 * time.Millisecond,
+		UserAgent:        `curl/7.15.1 "test"`,
+		SignatureVersion: "SigV4",
+		AuthType:         "AuthHeader",
+		HostHeader:       "localhost:9000",
+	}
+	expected := `owner photos [06/Feb/2023:00:00:38 +0000] 192.0.2.3 alice 17416A3C8A2D7B1E REST.GET.OBJECT 2023%2Fcat+photo.jpg "GET /photos/2023/cat%20photo.jpg HTTP/1.1" 200 - 2662992 3462992 70 10 - "curl/7.15.1 \"test\"" - - SigV4 - AuthHeader localhost:9000 -`
+	if got := record.String(); got != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, got)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logging
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeFormat - time format of access log records.
+const timeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// subResources - query parameters which name the resource of the
+// operation field, in order of precedence.
+var subResources = []struct {
+	query, resource string
+}{
+	{"uploadId", "UPLOAD"},
+	{"uploads", "UPLOADS"},
+	{"acl", "ACL"},
+	{"cors", "CORS"},
+	{"delete", "MULTI_OBJECT_DELETE"},
+	{"encryption", "ENCRYPTION"},
+	{"legal-hold", "LEGAL_HOLD"},
+	{"lifecycle", "LIFECYCLE"},
+	{"location", "LOCATION"},
+	{"logging", "LOGGING_STATUS"},
+	{"notification", "NOTIFICATION"},
+	{"object-lock", "OBJECT_LOCK_CONFIGURATION"},
+	{"policy", "POLICY"},
+	{"replication", "REPLICATION"},
+	{"restore", "RESTORE"},
+	{"retention", "RETENTION"},
+	{"select", "SELECT"},
+	{"tagging", "TAGGING"},
+	{"versioning", "VERSIONING"},
+	{"versions", "VERSIONS"},
+	{"website", "WEBSITE"},
+}
+
+// Operation - returns the operation of an access log record, formatted
+// as REST.HTTP_method.resource_type.
+func Operation(method string, query url.Values, key string) string {
+	resource := "BUCKET"
+	if key != "" {
+		resource = "OBJECT"
+	}
+	for _, sr := range subResources {
+		if _, ok := query[sr.query]; ok {
+			resource = sr.resource
+			break
+		}
+	}
+	return "REST." + strings.ToUpper(method) + "." + resource
+}
+
+// Record - a single server access log record.
+// https://docs.aws.amazon.com/AmazonS3/latest/userguide/LogFormat.html
+type Record struct {
+	BucketOwner      string
+	Bucket           string
+	Time             time.Time
+	RemoteIP         string
+	Requester        string
+	RequestID        string
+	Operation        string
+	Key              string
+	RequestURI       string
+	HTTPStatus       int
+	ErrorCode        string
+	BytesSent        int64
+	ObjectSize       int64
+	TotalTime        time.Duration
+	TurnAroundTime   time.Duration
+	Referer          string
+	UserAgent        string
+	VersionID        string
+	HostID           string
+	SignatureVersion string
+	CipherSuite      string
+	AuthType         string
+	HostHeader       string
+	TLSVersion       string
+}
+
+func field(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func quoted(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func number(n int64) string {
+	if n <= 0 {
+		return "-"
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+func milliseconds(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return strconv.FormatInt(d.Milliseconds(), 10)
+}
+
+// String - returns the record in the server access log format,
+// missing values are logged as '-'.
+func (r Record) String() string {
+	key := r.Key
+	if key != "" {
+		key = url.QueryEscape(key)
+	}
+	status := "-"
+	if r.HTTPStatus > 0 {
+		status = strconv.Itoa(r.HTTPStatus)
+	}
+	fields := []string{
+		field(r.BucketOwner),
+		field(r.Bucket),
+		"[" + r.Time.UTC().Format(timeFormat) + "]",
+		field(r.RemoteIP),
+		field(r.Requester),
+		field(r.RequestID),
+		field(r.Operation),
+		field(key),
+		quoted(r.RequestURI),
+		status,
+		field(r.ErrorCode),
+		number(r.BytesSent),
+		number(r.ObjectSize),
+		milliseconds(r.TotalTime),
+		milliseconds(r.TurnAroundTime),
+		quoted(r.Referer),
+		quoted(r.UserAgent),
+		field(r.VersionID),
+		field(r.HostID),
+		field(r.SignatureVersion),
+		field(r.CipherSuite),
+		field(r.AuthType),
+		field(r.HostHeader),
+		field(r.TLSVersion),
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package placement
+
+import (
+	"encoding/json"
+	"io"
+)
+
+var (
+	errEmptyPolicy      = Errorf("Placement policy must specify pools, exclude or weights")
+	errEmptyPoolName    = Errorf("Pool name must not be empty")
+	errAllPoolsExcluded = Errorf("Placement policy excludes all pools")
+)
+
+// Policy - pool placement policy of a bucket, decides which pools of a
+// multi-pool deployment new objects of the bucket are placed on. Pools
+// are named by their index, starting at 0, or by their command line
+// argument.
+type Policy struct {
+	// Pools new objects are pinned to, all pools if empty.
+	Pools []string `json:"pools,omitempty"`
+	// Pools new objects are never placed on.
+	Exclude []string `json:"exclude,omitempty"`
+	// Relative share of new objects per pool, pools not listed have a
+	// weight of 1. Without weights new objects are spread by available
+	// space.
+	Weights map[string]uint64 `json:"weights,omitempty"`
+}
+
+// Weighted - returns true if new objects are spread by weight.
+func (p *Policy) Weighted() bool {
+	return p != nil && len(p.Weights) > 0
+}
+
+// Validate - validates the placement policy, pool names are validated
+// by Resolve.
+func (p Policy) Validate() error {
+	if len(p.Pools) == 0 && len(p.Exclude) == 0 && len(p.Weights) == 0 {
+		return errEmptyPolicy
+	}
+	for _, pools := range [][]string{p.Pools, p.Exclude} {
+		seen := make(map[string]struct{}, len(pools))
+		for _, pool := range pools {
+			if pool == "" {
+				return errEmptyPoolName
+			}
+			if _, ok := seen[pool]; ok {
+				return Errorf("Duplicate pool %s", pool)
+			}
+			seen[pool] = struct{}{}
+		}
+	}
+	for pool, weight := range p.Weights {
+		if pool == "" {
+			return errEmptyPoolName
+		}
+		if weight == 0 {
+			return Errorf("Weight of pool %s must be greater than 0, use exclude instead", pool)
+		}
+	}
+	return nil
+}
+
+// Resolve - returns the weight of each pool new objects may be placed on,
+// by pool index. poolIdx maps a pool name to its index, it returns false
+// for unknown pools. poolCount is the number of pools of the deployment.
+func (p Policy) Resolve(poolIdx func(pool string) (int, bool), poolCount int) (map[int]uint64, error) {
+	resolve := func(pool string) (int, error) {
+		idx, ok := poolIdx(pool)
+		if !ok {
+			return -1, Errorf("Unknown pool %s", pool)
+		}
+		return idx, nil
+	}
+
+	weights := make(map[int]uint64, poolCount)
+	if len(p.Pools) > 0 {
+		for _, pool := range p.Pools {
+			idx, err := resolve(pool)
+			if err != nil {
+				return nil, err
+			}
+			weights[idx] = 1
+		}
+	} else {
+		for idx := 0; idx < poolCount; idx++ {
+			weights[idx] = 1
+		}
+	}
+	for _, pool := range p.Exclude {
+		idx, err := resolve(pool)
+		if err != nil {
+			return nil, err
+		}
+		if len(p.Pools) > 0 {
+			if _, ok := weights[idx]; ok {
+				return nil, Errorf("Pool %s is both pinned and excluded", pool)
+			}
+		}
+		delete(weights, idx)
+	}
+	for pool, weight := range p.Weights {
+		idx, err := resolve(pool)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := weights[idx]; !ok {
+			return nil, Errorf("Weighted pool %s is excluded or not pinned", pool)
+		}
+		weights[idx] = weight
+	}
+	if len(weights) == 0 {
+		return nil, errAllPoolsExcluded
+	}
+	return weights, nil
+}
+
+// ParseConfig - parses data in given reader to a placement policy.
+func ParseConfig(reader io.Reader) (*Policy, error) {
+	var p Policy
+	d := json.NewDecoder(reader)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&p); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
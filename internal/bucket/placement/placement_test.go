@@ -0,0 +1,92 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package placement
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		input   string
+		success bool
+	}{
+		{`{"pools":["0","1"]}`, true},
+		{`{"exclude":["2"]}`, true},
+		{`{"weights":{"0":3,"1":1}}`, true},
+		{`{"pools":["0","1"],"weights":{"0":3}}`, true},
+		{`{}`, false},
+		{`{"pools":[""]}`, false},
+		{`{"pools":["0","0"]}`, false},
+		{`{"weights":{"0":0}}`, false},
+		{`{"pool":["0"]}`, false},
+		{`{"pools":"0"}`, false},
+	}
+	for i, tc := range testCases {
+		_, err := ParseConfig(strings.NewReader(tc.input))
+		if err != nil && tc.success {
+			t.Errorf("Test %d: expected success, got %v", i+1, err)
+		}
+		if err == nil && !tc.success {
+			t.Errorf("Test %d: expected failure, got success", i+1)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	const poolCount = 3
+	poolIdx := func(pool string) (int, bool) {
+		if pool == "http://nvme{1...4}/data{1...4}" {
+			return 0, true
+		}
+		idx, err := strconv.Atoi(pool)
+		return idx, err == nil && idx >= 0 && idx < poolCount
+	}
+	testCases := []struct {
+		policy  Policy
+		weights map[int]uint64
+		success bool
+	}{
+		{Policy{Pools: []string{"http://nvme{1...4}/data{1...4}"}}, map[int]uint64{0: 1}, true},
+		{Policy{Pools: []string{"0", "2"}}, map[int]uint64{0: 1, 2: 1}, true},
+		{Policy{Exclude: []string{"1"}}, map[int]uint64{0: 1, 2: 1}, true},
+		{Policy{Weights: map[string]uint64{"0": 3}}, map[int]uint64{0: 3, 1: 1, 2: 1}, true},
+		{Policy{Pools: []string{"0", "1"}, Weights: map[string]uint64{"1": 4}}, map[int]uint64{0: 1, 1: 4}, true},
+		{Policy{Pools: []string{"3"}}, nil, false},
+		{Policy{Pools: []string{"0"}, Exclude: []string{"http://nvme{1...4}/data{1...4}"}}, nil, false},
+		{Policy{Exclude: []string{"0"}, Weights: map[string]uint64{"0": 2}}, nil, false},
+		{Policy{Pools: []string{"0"}, Weights: map[string]uint64{"1": 2}}, nil, false},
+		{Policy{Exclude: []string{"0", "1", "2"}}, nil, false},
+	}
+	for i, tc := range testCases {
+		weights, err := tc.policy.Resolve(poolIdx, poolCount)
+		if err != nil && tc.success {
+			t.Errorf("Test %d: expected success, got %v", i+1, err)
+			continue
+		}
+		if err == nil && !tc.success {
+			t.Errorf("Test %d: expected failure, got success", i+1)
+			continue
+		}
+		if !reflect.DeepEqual(weights, tc.weights) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, tc.weights, weights)
+		}
+	}
+}
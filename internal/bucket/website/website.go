@@ -0,0 +1,267 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package website
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Maximum number of routing rules allowed on a bucket (same as AWS S3).
+	maxRoutingRules = 50
+
+	xmlNS = "http://s3.amazonaws.com/doc/2006-03-01/"
+)
+
+var (
+	errMissingIndexDocument  = Errorf("IndexDocument or RedirectAllRequestsTo must be specified")
+	errRedirectAllExclusive  = Errorf("RedirectAllRequestsTo cannot be combined with other website configuration")
+	errInvalidIndexSuffix    = Errorf("IndexDocument Suffix must not be empty or contain a slash")
+	errMissingErrorKey       = Errorf("ErrorDocument Key must not be empty")
+	errMissingHostName       = Errorf("RedirectAllRequestsTo must specify a HostName")
+	errInvalidProtocol       = Errorf("Protocol must be either http or https")
+	errTooManyRoutingRules   = Errorf("Website configuration allows a maximum of 50 routing rules")
+	errMissingRedirect       = Errorf("RoutingRule must specify a Redirect")
+	errReplaceKeyExclusive   = Errorf("ReplaceKeyPrefixWith and ReplaceKeyWith cannot both be specified")
+	errInvalidRedirectCode   = Errorf("HttpRedirectCode must be a 3XX status code")
+	errInvalidErrorCondition = Errorf("HttpErrorCodeReturnedEquals must be a 4XX or 5XX status code")
+)
+
+// IndexDocument - the object suffix served for requests on a directory.
+type IndexDocument struct {
+	Suffix string `xml:"Suffix"`
+}
+
+// ErrorDocument - the object served when an error occurs.
+type ErrorDocument struct {
+	Key string `xml:"Key"`
+}
+
+// RedirectAllRequestsTo - redirects every request to another host.
+type RedirectAllRequestsTo struct {
+	HostName string `xml:"HostName"`
+	Protocol string `xml:"Protocol,omitempty"`
+}
+
+// Condition - condition under which a routing rule redirect applies.
+type Condition struct {
+	KeyPrefixEquals             string `xml:"KeyPrefixEquals,omitempty"`
+	HTTPErrorCodeReturnedEquals string `xml:"HttpErrorCodeReturnedEquals,omitempty"`
+}
+
+// Redirect - where a matching request is redirected to.
+type Redirect struct {
+	HostName             string `xml:"HostName,omitempty"`
+	HTTPRedirectCode     string `xml:"HttpRedirectCode,omitempty"`
+	Protocol             string `xml:"Protocol,omitempty"`
+	ReplaceKeyPrefixWith string `xml:"ReplaceKeyPrefixWith,omitempty"`
+	ReplaceKeyWith       string `xml:"ReplaceKeyWith,omitempty"`
+}
+
+// RoutingRule - a conditional redirect.
+type RoutingRule struct {
+	Condition *Condition `xml:"Condition,omitempty"`
+	Redirect  *Redirect  `xml:"Redirect"`
+}
+
+// Config - bucket website configuration.
+type Config struct {
+	XMLNS                 string                 `xml:"xmlns,attr,omitempty"`
+	XMLName               xml.Name               `xml:"WebsiteConfiguration"`
+	IndexDocument         *IndexDocument         `xml:"IndexDocument,omitempty"`
+	ErrorDocument         *ErrorDocument         `xml:"ErrorDocument,omitempty"`
+	RedirectAllRequestsTo *RedirectAllRequestsTo `xml:"RedirectAllRequestsTo,omitempty"`
+	RoutingRules          []RoutingRule          `xml:"RoutingRules>RoutingRule,omitempty"`
+}
+
+func validProtocol(protocol string) bool {
+	return protocol == "" || protocol == "http" || protocol == "https"
+}
+
+// Validate - validates the routing rule.
+func (r RoutingRule) Validate() error {
+	if r.Redirect == nil {
+		return errMissingRedirect
+	}
+	if r.Condition != nil && r.Condition.HTTPErrorCodeReturnedEquals != "" {
+		code, err := strconv.Atoi(r.Condition.HTTPErrorCodeReturnedEquals)
+		if err != nil || code < 400 || code > 599 {
+			return errInvalidErrorCondition
+		}
+	}
+	if r.Redirect.ReplaceKeyPrefixWith != "" && r.Redirect.ReplaceKeyWith != "" {
+		return errReplaceKeyExclusive
+	}
+	if r.Redirect.HTTPRedirectCode != "" {
+		code, err := strconv.Atoi(r.Redirect.HTTPRedirectCode)
+		if err != nil || code < 300 || code > 399 {
+			return errInvalidRedirectCode
+		}
+	}
+	if !validProtocol(r.Redirect.Protocol) {
+		return errInvalidProtocol
+	}
+	return nil
+}
+
+// Validate - validates the website configuration.
+func (c Config) Validate() error {
+	if c.RedirectAllRequestsTo != nil {
+		if c.IndexDocument != nil || c.ErrorDocument != nil || len(c.RoutingRules) != 0 {
+			return errRedirectAllExclusive
+		}
+		if c.RedirectAllRequestsTo.HostName == "" {
+			return errMissingHostName
+		}
+		if !validProtocol(c.RedirectAllRequestsTo.Protocol) {
+			return errInvalidProtocol
+		}
+		return nil
+	}
+	if c.IndexDocument == nil {
+		return errMissingIndexDocument
+	}
+	if c.IndexDocument.Suffix == "" || strings.Contains(c.IndexDocument.Suffix, "/") {
+		return errInvalidIndexSuffix
+	}
+	if c.ErrorDocument != nil && c.ErrorDocument.Key == "" {
+		return errMissingErrorKey
+	}
+	if len(c.RoutingRules) > maxRoutingRules {
+		return errTooManyRoutingRules
+	}
+	for _, rule := range c.RoutingRules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IndexKey - returns the object key to be served for key, the index
+// document suffix is appended to the root and to keys ending in '/'.
+func (c *Config) IndexKey(key string) string {
+	if c.IndexDocument == nil {
+		return key
+	}
+	if key == "" || strings.HasSuffix(key, "/") {
+		return key + c.IndexDocument.Suffix
+	}
+	return key
+}
+
+// ErrorKey - returns the key of the custom error document, empty if
+// none is configured.
+func (c *Config) ErrorKey() string {
+	if c.ErrorDocument == nil {
+		return ""
+	}
+	return c.ErrorDocument.Key
+}
+
+// MatchRoutingRule - returns the first routing rule which applies to
+// key. A zero errorCode only matches rules without an error code
+// condition and is meant to be evaluated before serving the object,
+// a non-zero errorCode only matches rules conditioned on that code.
+// Returns nil if no rule matches.
+func (c *Config) MatchRoutingRule(key string, errorCode int) *RoutingRule {
+	if c == nil {
+		return nil
+	}
+	for i := range c.RoutingRules {
+		rule := &c.RoutingRules[i]
+		var cond Condition
+		if rule.Condition != nil {
+			cond = *rule.Condition
+		}
+		if !strings.HasPrefix(key, cond.KeyPrefixEquals) {
+			continue
+		}
+		switch {
+		case cond.HTTPErrorCodeReturnedEquals == "":
+			if errorCode != 0 {
+				continue
+			}
+		case cond.HTTPErrorCodeReturnedEquals != strconv.Itoa(errorCode):
+			continue
+		}
+		return rule
+	}
+	return nil
+}
+
+// StatusCode - returns the HTTP status code of the redirect.
+func (r RoutingRule) StatusCode() int {
+	if r.Redirect != nil && r.Redirect.HTTPRedirectCode != "" {
+		if code, err := strconv.Atoi(r.Redirect.HTTPRedirectCode); err == nil {
+			return code
+		}
+	}
+	return http.StatusMovedPermanently
+}
+
+// Location - returns the redirect location for a request on key which
+// was received on host using protocol.
+func (r RoutingRule) Location(key, host, protocol string) string {
+	redirect := r.Redirect
+	if redirect.HostName != "" {
+		host = redirect.HostName
+	}
+	if redirect.Protocol != "" {
+		protocol = redirect.Protocol
+	}
+	switch {
+	case redirect.ReplaceKeyWith != "":
+		key = redirect.ReplaceKeyWith
+	case redirect.ReplaceKeyPrefixWith != "":
+		var prefix string
+		if r.Condition != nil {
+			prefix = r.Condition.KeyPrefixEquals
+		}
+		key = redirect.ReplaceKeyPrefixWith + strings.TrimPrefix(key, prefix)
+	}
+	return protocol + "://" + host + "/" + key
+}
+
+// Location - returns the redirect location for a request on key which
+// was received using protocol.
+func (r RedirectAllRequestsTo) Location(key, protocol string) string {
+	if r.Protocol != "" {
+		protocol = r.Protocol
+	}
+	return protocol + "://" + r.HostName + "/" + key
+}
+
+// ParseConfig - parses data in given reader to WebsiteConfiguration.
+func ParseConfig(reader io.Reader) (*Config, error) {
+	var c Config
+	if err := xml.NewDecoder(reader).Decode(&c); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if c.XMLNS == "" {
+		c.XMLNS = xmlNS
+	}
+	return &c, nil
+}
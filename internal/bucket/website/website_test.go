@@ -0,0 +1,189 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package website
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		input string
+		err   error
+	}{
+		{
+			input: `<WebsiteConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <IndexDocument><Suffix>index.html</Suffix></IndexDocument>
+                                  <ErrorDocument><Key>404.html</Key></ErrorDocument>
+                                  <RoutingRules>
+                                    <RoutingRule>
+                                      <Condition><KeyPrefixEquals>docs/</KeyPrefixEquals></Condition>
+                                      <Redirect><ReplaceKeyPrefixWith>documents/</ReplaceKeyPrefixWith></Redirect>
+                                    </RoutingRule>
+                                  </RoutingRules>
+                                </WebsiteConfiguration>`,
+			err: nil,
+		},
+		{
+			input: `<WebsiteConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <RedirectAllRequestsTo><HostName>example.com</HostName></RedirectAllRequestsTo>
+                                </WebsiteConfiguration>`,
+			err: nil,
+		},
+		{
+			input: `<WebsiteConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                </WebsiteConfiguration>`,
+			err: errMissingIndexDocument,
+		},
+		{
+			input: `<WebsiteConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <IndexDocument><Suffix>index.html</Suffix></IndexDocument>
+                                  <RedirectAllRequestsTo><HostName>example.com</HostName></RedirectAllRequestsTo>
+                                </WebsiteConfiguration>`,
+			err: errRedirectAllExclusive,
+		},
+		{
+			input: `<WebsiteConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <IndexDocument><Suffix>site/index.html</Suffix></IndexDocument>
+                                </WebsiteConfiguration>`,
+			err: errInvalidIndexSuffix,
+		},
+		{
+			input: `<WebsiteConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <RedirectAllRequestsTo><HostName>example.com</HostName><Protocol>ftp</Protocol></RedirectAllRequestsTo>
+                                </WebsiteConfiguration>`,
+			err: errInvalidProtocol,
+		},
+		{
+			input: `<WebsiteConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <IndexDocument><Suffix>index.html</Suffix></IndexDocument>
+                                  <RoutingRules>
+                                    <RoutingRule>
+                                      <Redirect><ReplaceKeyPrefixWith>a/</ReplaceKeyPrefixWith><ReplaceKeyWith>b</ReplaceKeyWith></Redirect>
+                                    </RoutingRule>
+                                  </RoutingRules>
+                                </WebsiteConfiguration>`,
+			err: errReplaceKeyExclusive,
+		},
+		{
+			input: `<WebsiteConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <IndexDocument><Suffix>index.html</Suffix></IndexDocument>
+                                  <RoutingRules>
+                                    <RoutingRule>
+                                      <Redirect><HttpRedirectCode>200</HttpRedirectCode></Redirect>
+                                    </RoutingRule>
+                                  </RoutingRules>
+                                </WebsiteConfiguration>`,
+			err: errInvalidRedirectCode,
+		},
+		{
+			input: `<WebsiteConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+                                  <IndexDocument><Suffix>index.html</Suffix></IndexDocument>
+                                  <RoutingRules>
+                                    <RoutingRule>
+                                      <Condition><HttpErrorCodeReturnedEquals>200</HttpErrorCodeReturnedEquals></Condition>
+                                      <Redirect><HostName>example.com</HostName></Redirect>
+                                    </RoutingRule>
+                                  </RoutingRules>
+                                </WebsiteConfiguration>`,
+			err: errInvalidErrorCondition,
+		},
+	}
+
+	for i, tc := range testCases {
+		_, err := ParseConfig(strings.NewReader(tc.input))
+		if err != tc.err {
+			t.Fatalf("Test %d: expected %v but got %v", i+1, tc.err, err)
+		}
+	}
+}
+
+func TestIndexKey(t *testing.T) {
+	config := &Config{IndexDocument: &IndexDocument{Suffix: "index.html"}}
+	testCases := []struct {
+		key      string
+		expected string
+	}{
+		{"", "index.html"},
+		{"docs/", "docs/index.html"},
+		{"docs", "docs"},
+		{"docs/intro.html", "docs/intro.html"},
+	}
+	for i, tc := range testCases {
+		if got := config.IndexKey(tc.key); got != tc.expected {
+			t.Errorf("Test %d: expected %s but got %s", i+1, tc.expected, got)
+		}
+	}
+}
+
+func TestMatchRoutingRule(t *testing.T) {
+	config, err := ParseConfig(strings.NewReader(`<WebsiteConfiguration>
+  <IndexDocument><Suffix>index.html</Suffix></IndexDocument>
+  <RoutingRules>
+    <RoutingRule>
+      <Condition><KeyPrefixEquals>docs/</KeyPrefixEquals></Condition>
+      <Redirect><ReplaceKeyPrefixWith>documents/</ReplaceKeyPrefixWith></Redirect>
+    </RoutingRule>
+    <RoutingRule>
+      <Condition><KeyPrefixEquals>old.html</KeyPrefixEquals></Condition>
+      <Redirect><Protocol>https</Protocol><HostName>example.com</HostName><ReplaceKeyWith>new.html</ReplaceKeyWith><HttpRedirectCode>302</HttpRedirectCode></Redirect>
+    </RoutingRule>
+    <RoutingRule>
+      <Condition><HttpErrorCodeReturnedEquals>404</HttpErrorCodeReturnedEquals></Condition>
+      <Redirect><HostName>fallback.example.com</HostName></Redirect>
+    </RoutingRule>
+  </RoutingRules>
+</WebsiteConfiguration>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		key        string
+		errorCode  int
+		location   string
+		statusCode int
+	}{
+		{"docs/intro.html", 0, "http://site.local/documents/intro.html", 301},
+		{"old.html", 0, "https://example.com/new.html", 302},
+		{"index.html", 0, "", 0},
+		{"missing.html", 404, "http://fallback.example.com/missing.html", 301},
+		{"missing.html", 403, "", 0},
+	}
+
+	for i, tc := range testCases {
+		rule := config.MatchRoutingRule(tc.key, tc.errorCode)
+		if rule == nil {
+			if tc.location != "" {
+				t.Errorf("Test %d: expected a routing rule to match", i+1)
+			}
+			continue
+		}
+		if tc.location == "" {
+			t.Errorf("Test %d: expected no routing rule to match", i+1)
+			continue
+		}
+		if got := rule.Location(tc.key, "site.local", "http"); got != tc.location {
+			t.Errorf("Test %d: expected location %s but got %s", i+1, tc.location, got)
+		}
+		if got := rule.StatusCode(); got != tc.statusCode {
+			t.Errorf("Test %d: expected status %d but got %d", i+1, tc.statusCode, got)
+		}
+	}
+}
@@ -113,6 +113,8 @@ const (
 	LoggerWebhookSubSys  = madmin.LoggerWebhookSubSys
 	AuditWebhookSubSys   = madmin.AuditWebhookSubSys
 	AuditKafkaSubSys     = madmin.AuditKafkaSubSys
+	LoggerFileSubSys     = "logger_file"
+	AuditFileSubSys      = "audit_file"
 	HealSubSys           = madmin.HealSubSys
 	ScannerSubSys        = madmin.ScannerSubSys
 	CrawlerSubSys        = madmin.CrawlerSubSys
@@ -134,6 +136,7 @@ const (
 	NotifyPostgresSubSys = madmin.NotifyPostgresSubSys
 	NotifyRedisSubSys    = madmin.NotifyRedisSubSys
 	NotifyWebhookSubSys  = madmin.NotifyWebhookSubSys
+	NotifyPulsarSubSys   = "notify_pulsar"
 
 	// Add new constants here (similar to above) if you add new fields to config.
 )
@@ -150,6 +153,7 @@ var NotifySubSystems = set.CreateStringSet(
 	NotifyPostgresSubSys,
 	NotifyRedisSubSys,
 	NotifyWebhookSubSys,
+	NotifyPulsarSubSys,
 )
 
 // LoggerSubSystems - all sub-systems related to logger
@@ -157,10 +161,16 @@ var LoggerSubSystems = set.CreateStringSet(
 	LoggerWebhookSubSys,
 	AuditWebhookSubSys,
 	AuditKafkaSubSys,
+	LoggerFileSubSys,
+	AuditFileSubSys,
 )
 
 // SubSystems - all supported sub-systems
-var SubSystems = madmin.SubSystems
+var SubSystems = set.CreateStringSet(append(madmin.SubSystems.ToSlice(),
+	NotifyPulsarSubSys,
+	LoggerFileSubSys,
+	AuditFileSubSys,
+)...)
 
 // SubSystemsDynamic - all sub-systems that have dynamic config.
 var SubSystemsDynamic = set.CreateStringSet(
@@ -173,6 +183,8 @@ var SubSystemsDynamic = set.CreateStringSet(
 	LoggerWebhookSubSys,
 	AuditWebhookSubSys,
 	AuditKafkaSubSys,
+	LoggerFileSubSys,
+	AuditFileSubSys,
 	StorageClassSubSys,
 )
 
@@ -43,13 +43,14 @@ const (
 	// 'podman run -e ENV=value'
 	EnvConfigEnvFile = "MINIO_CONFIG_ENV_FILE"
 
-	EnvBrowser    = "MINIO_BROWSER"
-	EnvDomain     = "MINIO_DOMAIN"
-	EnvPublicIPs  = "MINIO_PUBLIC_IPS"
-	EnvFSOSync    = "MINIO_FS_OSYNC"
-	EnvArgs       = "MINIO_ARGS"
-	EnvVolumes    = "MINIO_VOLUMES"
-	EnvDNSWebhook = "MINIO_DNS_WEBHOOK_ENDPOINT"
+	EnvBrowser       = "MINIO_BROWSER"
+	EnvDomain        = "MINIO_DOMAIN"
+	EnvWebsiteDomain = "MINIO_WEBSITE_DOMAIN"
+	EnvPublicIPs     = "MINIO_PUBLIC_IPS"
+	EnvFSOSync       = "MINIO_FS_OSYNC"
+	EnvArgs          = "MINIO_ARGS"
+	EnvVolumes       = "MINIO_VOLUMES"
+	EnvDNSWebhook    = "MINIO_DNS_WEBHOOK_ENDPOINT"
 
 	EnvSiteName   = "MINIO_SITE_NAME"
 	EnvSiteRegion = "MINIO_SITE_REGION"
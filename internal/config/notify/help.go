@@ -23,9 +23,15 @@ import (
 )
 
 const (
-	formatComment     = `'namespace' reflects current bucket/object list and 'access' reflects a journal of object operations, defaults to 'namespace'`
-	queueDirComment   = `staging dir for undelivered messages e.g. '/home/events'`
-	queueLimitComment = `maximum limit for undelivered messages, defaults to '100000'`
+	formatComment           = `'namespace' reflects current bucket/object list and 'access' reflects a journal of object operations, defaults to 'namespace'`
+	queueDirComment         = `staging dir for undelivered messages e.g. '/home/events'`
+	queueLimitComment       = `maximum limit for undelivered messages, defaults to '100000'`
+	queueSizeLimitComment   = `maximum size of undelivered messages on disk e.g. '10GiB', defaults to '0' (no limit)`
+	queueCompressionComment = `set to 'on' to compress undelivered messages on disk, defaults to 'off'`
+
+	batchSizeComment     = `maximum number of queued events sent at once, requires 'queue_dir', defaults to '0' (no batching)`
+	batchMaxWaitComment  = `maximum time to wait for a batch to fill up, defaults to '1s'`
+	payloadFormatComment = `'default' sends the event log, 'cloudevents_structured' and 'cloudevents_binary' send CloudEvents 1.0, defaults to 'default'`
 )
 
 var enableHelp = config.HelpKV{
@@ -65,6 +71,36 @@ var (
 			Optional:    true,
 			Type:        "number",
 		},
+		config.HelpKV{
+			Key:         target.WebhookQueueSizeLimit,
+			Description: queueSizeLimitComment,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         target.WebhookQueueCompression,
+			Description: queueCompressionComment,
+			Optional:    true,
+			Type:        "on|off",
+		},
+		config.HelpKV{
+			Key:         target.WebhookBatchSize,
+			Description: batchSizeComment,
+			Optional:    true,
+			Type:        "number",
+		},
+		config.HelpKV{
+			Key:         target.WebhookBatchMaxWait,
+			Description: batchMaxWaitComment,
+			Optional:    true,
+			Type:        "duration",
+		},
+		config.HelpKV{
+			Key:         target.WebhookPayloadFormat,
+			Description: payloadFormatComment,
+			Optional:    true,
+			Type:        "string",
+		},
 		config.HelpKV{
 			Key:         config.Comment,
 			Description: config.DefaultComment,
@@ -168,6 +204,18 @@ var (
 			Optional:    true,
 			Type:        "number",
 		},
+		config.HelpKV{
+			Key:         target.AmqpQueueSizeLimit,
+			Description: queueSizeLimitComment,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         target.AmqpQueueCompression,
+			Description: queueCompressionComment,
+			Optional:    true,
+			Type:        "on|off",
+		},
 		config.HelpKV{
 			Key:         config.Comment,
 			Description: config.DefaultComment,
@@ -259,12 +307,42 @@ var (
 			Optional:    true,
 			Type:        "number",
 		},
+		config.HelpKV{
+			Key:         target.KafkaQueueSizeLimit,
+			Description: queueSizeLimitComment,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         target.KafkaQueueCompression,
+			Description: queueCompressionComment,
+			Optional:    true,
+			Type:        "on|off",
+		},
 		config.HelpKV{
 			Key:         target.KafkaVersion,
 			Description: "specify the version of the Kafka cluster",
 			Optional:    true,
 			Type:        "string",
 		},
+		config.HelpKV{
+			Key:         target.KafkaBatchSize,
+			Description: batchSizeComment,
+			Optional:    true,
+			Type:        "number",
+		},
+		config.HelpKV{
+			Key:         target.KafkaBatchMaxWait,
+			Description: batchMaxWaitComment,
+			Optional:    true,
+			Type:        "duration",
+		},
+		config.HelpKV{
+			Key:         target.KafkaPayloadFormat,
+			Description: payloadFormatComment,
+			Optional:    true,
+			Type:        "string",
+		},
 		config.HelpKV{
 			Key:         config.Comment,
 			Description: config.DefaultComment,
@@ -330,6 +408,18 @@ var (
 			Optional:    true,
 			Type:        "number",
 		},
+		config.HelpKV{
+			Key:         target.MqttQueueSizeLimit,
+			Description: queueSizeLimitComment,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         target.MqttQueueCompression,
+			Description: queueCompressionComment,
+			Optional:    true,
+			Type:        "on|off",
+		},
 		config.HelpKV{
 			Key:         config.Comment,
 			Description: config.DefaultComment,
@@ -368,6 +458,18 @@ var (
 			Optional:    true,
 			Type:        "number",
 		},
+		config.HelpKV{
+			Key:         target.PostgresQueueSizeLimit,
+			Description: queueSizeLimitComment,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         target.PostgresQueueCompression,
+			Description: queueCompressionComment,
+			Optional:    true,
+			Type:        "on|off",
+		},
 		config.HelpKV{
 			Key:         config.Comment,
 			Description: config.DefaultComment,
@@ -413,6 +515,18 @@ var (
 			Optional:    true,
 			Type:        "number",
 		},
+		config.HelpKV{
+			Key:         target.MySQLQueueSizeLimit,
+			Description: queueSizeLimitComment,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         target.MySQLQueueCompression,
+			Description: queueCompressionComment,
+			Optional:    true,
+			Type:        "on|off",
+		},
 		config.HelpKV{
 			Key:         config.Comment,
 			Description: config.DefaultComment,
@@ -518,6 +632,18 @@ var (
 			Optional:    true,
 			Type:        "number",
 		},
+		config.HelpKV{
+			Key:         target.NATSQueueSizeLimit,
+			Description: queueSizeLimitComment,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         target.NATSQueueCompression,
+			Description: queueCompressionComment,
+			Optional:    true,
+			Type:        "on|off",
+		},
 		config.HelpKV{
 			Key:         target.NATSStreaming,
 			Description: "[DEPRECATED] set to 'on', to use streaming NATS server",
@@ -587,6 +713,89 @@ var (
 			Optional:    true,
 			Type:        "number",
 		},
+		config.HelpKV{
+			Key:         target.NSQQueueSizeLimit,
+			Description: queueSizeLimitComment,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         target.NSQQueueCompression,
+			Description: queueCompressionComment,
+			Optional:    true,
+			Type:        "on|off",
+		},
+		config.HelpKV{
+			Key:         config.Comment,
+			Description: config.DefaultComment,
+			Optional:    true,
+			Type:        "sentence",
+		},
+	}
+
+	HelpPulsar = config.HelpKVS{
+		enableHelp,
+		config.HelpKV{
+			Key:         target.PulsarURL,
+			Description: "Pulsar web service URL of a broker or proxy e.g. 'http://localhost:8080'",
+			Type:        "url",
+		},
+		config.HelpKV{
+			Key:         target.PulsarTopic,
+			Description: "Pulsar topic e.g. 'persistent://tenant/namespace/topic', short names are topics of 'public/default'",
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         target.PulsarAuthToken,
+			Description: "JWT authentication token",
+			Optional:    true,
+			Type:        "string",
+			Sensitive:   true,
+		},
+		config.HelpKV{
+			Key:         target.PulsarTLSSkipVerify,
+			Description: `trust server TLS without verification, defaults to "off" (verify)`,
+			Optional:    true,
+			Type:        "on|off",
+		},
+		config.HelpKV{
+			Key:         target.PulsarClientTLSCert,
+			Description: "path to client certificate for TLS authentication",
+			Optional:    true,
+			Type:        "path",
+			Sensitive:   true,
+		},
+		config.HelpKV{
+			Key:         target.PulsarClientTLSKey,
+			Description: "path to client key for TLS authentication",
+			Optional:    true,
+			Type:        "path",
+			Sensitive:   true,
+		},
+		config.HelpKV{
+			Key:         target.PulsarQueueDir,
+			Description: queueDirComment,
+			Optional:    true,
+			Type:        "path",
+		},
+		config.HelpKV{
+			Key:         target.PulsarQueueLimit,
+			Description: queueLimitComment,
+			Optional:    true,
+			Type:        "number",
+		},
+		config.HelpKV{
+			Key:         target.PulsarQueueSizeLimit,
+			Description: queueSizeLimitComment,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         target.PulsarQueueCompression,
+			Description: queueCompressionComment,
+			Optional:    true,
+			Type:        "on|off",
+		},
 		config.HelpKV{
 			Key:         config.Comment,
 			Description: config.DefaultComment,
@@ -625,6 +834,18 @@ var (
 			Optional:    true,
 			Type:        "number",
 		},
+		config.HelpKV{
+			Key:         target.ElasticQueueSizeLimit,
+			Description: queueSizeLimitComment,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         target.ElasticQueueCompression,
+			Description: queueCompressionComment,
+			Optional:    true,
+			Type:        "on|off",
+		},
 		config.HelpKV{
 			Key:         target.ElasticUsername,
 			Description: "username for Elasticsearch basic-auth",
@@ -685,6 +906,18 @@ var (
 			Optional:    true,
 			Type:        "number",
 		},
+		config.HelpKV{
+			Key:         target.RedisQueueSizeLimit,
+			Description: queueSizeLimitComment,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         target.RedisQueueCompression,
+			Description: queueCompressionComment,
+			Optional:    true,
+			Type:        "on|off",
+		},
 		config.HelpKV{
 			Key:         config.Comment,
 			Description: config.DefaultComment,
@@ -28,6 +28,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/infobsmi/b33s/internal/config"
 	"github.com/infobsmi/b33s/internal/event"
 	"github.com/infobsmi/b33s/internal/event/target"
@@ -43,6 +44,26 @@ const (
 // ErrTargetsOffline - Indicates single/multiple target failures.
 var ErrTargetsOffline = errors.New("one or more targets are offline. Please use `mc admin info --json` to check the offline targets")
 
+// getQueueStoreArgs - parses the size limit in bytes and the compression of
+// the queue store of a target.
+func getQueueStoreArgs(kv config.KVS, k, sizeLimitKey, sizeLimitEnv, compressionKey, compressionEnv string) (sizeLimit uint64, compress bool, err error) {
+	if k != config.Default {
+		sizeLimitEnv = sizeLimitEnv + config.Default + k
+		compressionEnv = compressionEnv + config.Default + k
+	}
+	if v := env.Get(sizeLimitEnv, kv.Get(sizeLimitKey)); v != "" {
+		if sizeLimit, err = humanize.ParseBytes(v); err != nil {
+			return 0, false, err
+		}
+	}
+	if v := env.Get(compressionEnv, kv.Get(compressionKey)); v != "" {
+		if compress, err = config.ParseBool(v); err != nil {
+			return 0, false, err
+		}
+	}
+	return sizeLimit, compress, nil
+}
+
 // TestSubSysNotificationTargets - tests notification targets of given subsystem
 func TestSubSysNotificationTargets(ctx context.Context, cfg config.Config, subSys string, transport *http.Transport) error {
 	if err := checkValidNotificationKeysForSubSys(subSys, cfg[subSys]); err != nil {
@@ -187,6 +208,21 @@ func fetchSubSysTargets(ctx context.Context, cfg config.Config, subSys string, t
 			}
 			targets = append(targets, t)
 		}
+	case config.NotifyPulsarSubSys:
+		pulsarTargets, err := GetNotifyPulsar(cfg[config.NotifyPulsarSubSys], transport.TLSClientConfig.RootCAs)
+		if err != nil {
+			return nil, err
+		}
+		for id, args := range pulsarTargets {
+			if !args.Enable {
+				continue
+			}
+			t, err := target.NewPulsarTarget(id, args, logger.LogOnceIf)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, t)
+		}
 	case config.NotifyPostgresSubSys:
 		postgresTargets, err := GetNotifyPostgres(cfg[config.NotifyPostgresSubSys])
 		if err != nil {
@@ -262,6 +298,7 @@ var (
 		config.NotifyMySQLSubSys:    DefaultMySQLKVS,
 		config.NotifyNATSSubSys:     DefaultNATSKVS,
 		config.NotifyNSQSubSys:      DefaultNSQKVS,
+		config.NotifyPulsarSubSys:   DefaultPulsarKVS,
 		config.NotifyPostgresSubSys: DefaultPostgresKVS,
 		config.NotifyRedisSubSys:    DefaultRedisKVS,
 		config.NotifyWebhookSubSys:  DefaultWebhookKVS,
@@ -343,6 +380,14 @@ var (
 			Key:   target.KafkaQueueLimit,
 			Value: "0",
 		},
+		config.KV{
+			Key:   target.KafkaQueueSizeLimit,
+			Value: "0",
+		},
+		config.KV{
+			Key:   target.KafkaQueueCompression,
+			Value: config.EnableOff,
+		},
 		config.KV{
 			Key:   target.KafkaQueueDir,
 			Value: "",
@@ -351,6 +396,18 @@ var (
 			Key:   target.KafkaVersion,
 			Value: "",
 		},
+		config.KV{
+			Key:   target.KafkaBatchSize,
+			Value: "0",
+		},
+		config.KV{
+			Key:   target.KafkaBatchMaxWait,
+			Value: "1s",
+		},
+		config.KV{
+			Key:   target.KafkaPayloadFormat,
+			Value: target.PayloadFormatDefault,
+		},
 	}
 )
 
@@ -399,6 +456,13 @@ func GetNotifyKafka(kafkaKVS map[string]config.KVS) (map[string]target.KafkaArgs
 			return nil, err
 		}
 
+		queueSizeLimit, queueCompress, err := getQueueStoreArgs(kv, k,
+			target.KafkaQueueSizeLimit, target.EnvKafkaQueueSizeLimit,
+			target.KafkaQueueCompression, target.EnvKafkaQueueCompression)
+		if err != nil {
+			return nil, err
+		}
+
 		clientAuthEnv := target.EnvKafkaTLSClientAuth
 		if k != config.Default {
 			clientAuthEnv = clientAuthEnv + config.Default + k
@@ -423,13 +487,44 @@ func GetNotifyKafka(kafkaKVS map[string]config.KVS) (map[string]target.KafkaArgs
 			versionEnv = versionEnv + config.Default + k
 		}
 
+		batchSizeEnv := target.EnvKafkaBatchSize
+		if k != config.Default {
+			batchSizeEnv = batchSizeEnv + config.Default + k
+		}
+		batchSize, err := strconv.ParseUint(env.Get(batchSizeEnv, kv.Get(target.KafkaBatchSize)), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		batchMaxWaitEnv := target.EnvKafkaBatchMaxWait
+		if k != config.Default {
+			batchMaxWaitEnv = batchMaxWaitEnv + config.Default + k
+		}
+		var batchMaxWait time.Duration
+		if v := env.Get(batchMaxWaitEnv, kv.Get(target.KafkaBatchMaxWait)); v != "" {
+			batchMaxWait, err = time.ParseDuration(v)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		payloadFormatEnv := target.EnvKafkaPayloadFormat
+		if k != config.Default {
+			payloadFormatEnv = payloadFormatEnv + config.Default + k
+		}
+
 		kafkaArgs := target.KafkaArgs{
-			Enable:     enabled,
-			Brokers:    brokers,
-			Topic:      env.Get(topicEnv, kv.Get(target.KafkaTopic)),
-			QueueDir:   env.Get(queueDirEnv, kv.Get(target.KafkaQueueDir)),
-			QueueLimit: queueLimit,
-			Version:    env.Get(versionEnv, kv.Get(target.KafkaVersion)),
+			Enable:         enabled,
+			Brokers:        brokers,
+			Topic:          env.Get(topicEnv, kv.Get(target.KafkaTopic)),
+			QueueDir:       env.Get(queueDirEnv, kv.Get(target.KafkaQueueDir)),
+			QueueLimit:     queueLimit,
+			QueueSizeLimit: queueSizeLimit,
+			QueueCompress:  queueCompress,
+			Version:        env.Get(versionEnv, kv.Get(target.KafkaVersion)),
+			BatchSize:      uint32(batchSize),
+			BatchMaxWait:   batchMaxWait,
+			PayloadFormat:  env.Get(payloadFormatEnv, kv.Get(target.KafkaPayloadFormat)),
 		}
 
 		tlsEnableEnv := target.EnvKafkaTLS
@@ -532,6 +627,14 @@ var (
 			Key:   target.MqttQueueLimit,
 			Value: "0",
 		},
+		config.KV{
+			Key:   target.MqttQueueSizeLimit,
+			Value: "0",
+		},
+		config.KV{
+			Key:   target.MqttQueueCompression,
+			Value: config.EnableOff,
+		},
 	}
 )
 
@@ -591,6 +694,13 @@ func GetNotifyMQTT(mqttKVS map[string]config.KVS, rootCAs *x509.CertPool) (map[s
 			return nil, err
 		}
 
+		queueSizeLimit, queueCompress, err := getQueueStoreArgs(kv, k,
+			target.MqttQueueSizeLimit, target.EnvMQTTQueueSizeLimit,
+			target.MqttQueueCompression, target.EnvMQTTQueueCompression)
+		if err != nil {
+			return nil, err
+		}
+
 		qosEnv := target.EnvMQTTQoS
 		if k != config.Default {
 			qosEnv = qosEnv + config.Default + k
@@ -634,6 +744,8 @@ func GetNotifyMQTT(mqttKVS map[string]config.KVS, rootCAs *x509.CertPool) (map[s
 			RootCAs:              rootCAs,
 			QueueDir:             env.Get(queueDirEnv, kv.Get(target.MqttQueueDir)),
 			QueueLimit:           queueLimit,
+			QueueSizeLimit:       queueSizeLimit,
+			QueueCompress:        queueCompress,
 		}
 
 		if err = mqttArgs.Validate(); err != nil {
@@ -671,6 +783,14 @@ var (
 			Key:   target.MySQLQueueLimit,
 			Value: "0",
 		},
+		config.KV{
+			Key:   target.MySQLQueueSizeLimit,
+			Value: "0",
+		},
+		config.KV{
+			Key:   target.MySQLQueueCompression,
+			Value: config.EnableOff,
+		},
 		config.KV{
 			Key:   target.MySQLMaxOpenConnections,
 			Value: "2",
@@ -704,6 +824,13 @@ func GetNotifyMySQL(mysqlKVS map[string]config.KVS) (map[string]target.MySQLArgs
 			return nil, err
 		}
 
+		queueSizeLimit, queueCompress, err := getQueueStoreArgs(kv, k,
+			target.MySQLQueueSizeLimit, target.EnvMySQLQueueSizeLimit,
+			target.MySQLQueueCompression, target.EnvMySQLQueueCompression)
+		if err != nil {
+			return nil, err
+		}
+
 		formatEnv := target.EnvMySQLFormat
 		if k != config.Default {
 			formatEnv = formatEnv + config.Default + k
@@ -741,6 +868,8 @@ func GetNotifyMySQL(mysqlKVS map[string]config.KVS) (map[string]target.MySQLArgs
 			Table:              env.Get(tableEnv, kv.Get(target.MySQLTable)),
 			QueueDir:           env.Get(queueDirEnv, kv.Get(target.MySQLQueueDir)),
 			QueueLimit:         queueLimit,
+			QueueSizeLimit:     queueSizeLimit,
+			QueueCompress:      queueCompress,
 			MaxOpenConnections: maxOpenConnections,
 		}
 		if err = mysqlArgs.Validate(); err != nil {
@@ -830,6 +959,14 @@ var (
 			Key:   target.NATSQueueLimit,
 			Value: "0",
 		},
+		config.KV{
+			Key:   target.NATSQueueSizeLimit,
+			Value: "0",
+		},
+		config.KV{
+			Key:   target.NATSQueueCompression,
+			Value: config.EnableOff,
+		},
 	}
 )
 
@@ -880,6 +1017,13 @@ func GetNotifyNATS(natsKVS map[string]config.KVS, rootCAs *x509.CertPool) (map[s
 			return nil, err
 		}
 
+		queueSizeLimit, queueCompress, err := getQueueStoreArgs(kv, k,
+			target.NATSQueueSizeLimit, target.EnvNATSQueueSizeLimit,
+			target.NATSQueueCompression, target.EnvNATSQueueCompression)
+		if err != nil {
+			return nil, err
+		}
+
 		tlsEnv := target.EnvNATSTLS
 		if k != config.Default {
 			tlsEnv = tlsEnv + config.Default + k
@@ -936,21 +1080,23 @@ func GetNotifyNATS(natsKVS map[string]config.KVS, rootCAs *x509.CertPool) (map[s
 		}
 
 		natsArgs := target.NATSArgs{
-			Enable:        true,
-			Address:       *address,
-			Subject:       env.Get(subjectEnv, kv.Get(target.NATSSubject)),
-			Username:      env.Get(usernameEnv, kv.Get(target.NATSUsername)),
-			Password:      env.Get(passwordEnv, kv.Get(target.NATSPassword)),
-			CertAuthority: env.Get(certAuthorityEnv, kv.Get(target.NATSCertAuthority)),
-			ClientCert:    env.Get(clientCertEnv, kv.Get(target.NATSClientCert)),
-			ClientKey:     env.Get(clientKeyEnv, kv.Get(target.NATSClientKey)),
-			Token:         env.Get(tokenEnv, kv.Get(target.NATSToken)),
-			TLS:           env.Get(tlsEnv, kv.Get(target.NATSTLS)) == config.EnableOn,
-			TLSSkipVerify: env.Get(tlsSkipVerifyEnv, kv.Get(target.NATSTLSSkipVerify)) == config.EnableOn,
-			PingInterval:  pingInterval,
-			QueueDir:      env.Get(queueDirEnv, kv.Get(target.NATSQueueDir)),
-			QueueLimit:    queueLimit,
-			RootCAs:       rootCAs,
+			Enable:         true,
+			Address:        *address,
+			Subject:        env.Get(subjectEnv, kv.Get(target.NATSSubject)),
+			Username:       env.Get(usernameEnv, kv.Get(target.NATSUsername)),
+			Password:       env.Get(passwordEnv, kv.Get(target.NATSPassword)),
+			CertAuthority:  env.Get(certAuthorityEnv, kv.Get(target.NATSCertAuthority)),
+			ClientCert:     env.Get(clientCertEnv, kv.Get(target.NATSClientCert)),
+			ClientKey:      env.Get(clientKeyEnv, kv.Get(target.NATSClientKey)),
+			Token:          env.Get(tokenEnv, kv.Get(target.NATSToken)),
+			TLS:            env.Get(tlsEnv, kv.Get(target.NATSTLS)) == config.EnableOn,
+			TLSSkipVerify:  env.Get(tlsSkipVerifyEnv, kv.Get(target.NATSTLSSkipVerify)) == config.EnableOn,
+			PingInterval:   pingInterval,
+			QueueDir:       env.Get(queueDirEnv, kv.Get(target.NATSQueueDir)),
+			QueueLimit:     queueLimit,
+			QueueSizeLimit: queueSizeLimit,
+			QueueCompress:  queueCompress,
+			RootCAs:        rootCAs,
 		}
 		natsArgs.JetStream.Enable = env.Get(jetStreamEnableEnv, kv.Get(target.NATSJetStream)) == config.EnableOn
 
@@ -1024,6 +1170,14 @@ var (
 			Key:   target.NSQQueueLimit,
 			Value: "0",
 		},
+		config.KV{
+			Key:   target.NSQQueueSizeLimit,
+			Value: "0",
+		},
+		config.KV{
+			Key:   target.NSQQueueCompression,
+			Value: config.EnableOff,
+		},
 	}
 )
 
@@ -1070,6 +1224,13 @@ func GetNotifyNSQ(nsqKVS map[string]config.KVS) (map[string]target.NSQArgs, erro
 			return nil, err
 		}
 
+		queueSizeLimit, queueCompress, err := getQueueStoreArgs(kv, k,
+			target.NSQQueueSizeLimit, target.EnvNSQQueueSizeLimit,
+			target.NSQQueueCompression, target.EnvNSQQueueCompression)
+		if err != nil {
+			return nil, err
+		}
+
 		topicEnv := target.EnvNSQTopic
 		if k != config.Default {
 			topicEnv = topicEnv + config.Default + k
@@ -1080,11 +1241,13 @@ func GetNotifyNSQ(nsqKVS map[string]config.KVS) (map[string]target.NSQArgs, erro
 		}
 
 		nsqArgs := target.NSQArgs{
-			Enable:      enabled,
-			NSQDAddress: *nsqdAddress,
-			Topic:       env.Get(topicEnv, kv.Get(target.NSQTopic)),
-			QueueDir:    env.Get(queueDirEnv, kv.Get(target.NSQQueueDir)),
-			QueueLimit:  queueLimit,
+			Enable:         enabled,
+			NSQDAddress:    *nsqdAddress,
+			Topic:          env.Get(topicEnv, kv.Get(target.NSQTopic)),
+			QueueDir:       env.Get(queueDirEnv, kv.Get(target.NSQQueueDir)),
+			QueueLimit:     queueLimit,
+			QueueSizeLimit: queueSizeLimit,
+			QueueCompress:  queueCompress,
 		}
 		nsqArgs.TLS.Enable = env.Get(tlsEnableEnv, kv.Get(target.NSQTLS)) == config.EnableOn
 		nsqArgs.TLS.SkipVerify = env.Get(tlsSkipVerifyEnv, kv.Get(target.NSQTLSSkipVerify)) == config.EnableOn
@@ -1098,6 +1261,147 @@ func GetNotifyNSQ(nsqKVS map[string]config.KVS) (map[string]target.NSQArgs, erro
 	return nsqTargets, nil
 }
 
+// DefaultPulsarKVS - Pulsar KV for config
+var (
+	DefaultPulsarKVS = config.KVS{
+		config.KV{
+			Key:   config.Enable,
+			Value: config.EnableOff,
+		},
+		config.KV{
+			Key:   target.PulsarURL,
+			Value: "",
+		},
+		config.KV{
+			Key:   target.PulsarTopic,
+			Value: "",
+		},
+		config.KV{
+			Key:   target.PulsarAuthToken,
+			Value: "",
+		},
+		config.KV{
+			Key:   target.PulsarTLSSkipVerify,
+			Value: config.EnableOff,
+		},
+		config.KV{
+			Key:   target.PulsarClientTLSCert,
+			Value: "",
+		},
+		config.KV{
+			Key:   target.PulsarClientTLSKey,
+			Value: "",
+		},
+		config.KV{
+			Key:   target.PulsarQueueDir,
+			Value: "",
+		},
+		config.KV{
+			Key:   target.PulsarQueueLimit,
+			Value: "0",
+		},
+		config.KV{
+			Key:   target.PulsarQueueSizeLimit,
+			Value: "0",
+		},
+		config.KV{
+			Key:   target.PulsarQueueCompression,
+			Value: config.EnableOff,
+		},
+	}
+)
+
+// GetNotifyPulsar - returns a map of registered notification 'pulsar' targets
+func GetNotifyPulsar(pulsarKVS map[string]config.KVS, rootCAs *x509.CertPool) (map[string]target.PulsarArgs, error) {
+	pulsarTargets := make(map[string]target.PulsarArgs)
+	for k, kv := range config.Merge(pulsarKVS, target.EnvPulsarEnable, DefaultPulsarKVS) {
+		enableEnv := target.EnvPulsarEnable
+		if k != config.Default {
+			enableEnv = enableEnv + config.Default + k
+		}
+
+		enabled, err := config.ParseBool(env.Get(enableEnv, kv.Get(config.Enable)))
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			continue
+		}
+
+		urlEnv := target.EnvPulsarURL
+		if k != config.Default {
+			urlEnv = urlEnv + config.Default + k
+		}
+		url, err := xnet.ParseHTTPURL(env.Get(urlEnv, kv.Get(target.PulsarURL)))
+		if err != nil {
+			return nil, err
+		}
+
+		queueLimitEnv := target.EnvPulsarQueueLimit
+		if k != config.Default {
+			queueLimitEnv = queueLimitEnv + config.Default + k
+		}
+		queueLimit, err := strconv.ParseUint(env.Get(queueLimitEnv, kv.Get(target.PulsarQueueLimit)), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		queueSizeLimit, queueCompress, err := getQueueStoreArgs(kv, k,
+			target.PulsarQueueSizeLimit, target.EnvPulsarQueueSizeLimit,
+			target.PulsarQueueCompression, target.EnvPulsarQueueCompression)
+		if err != nil {
+			return nil, err
+		}
+
+		topicEnv := target.EnvPulsarTopic
+		if k != config.Default {
+			topicEnv = topicEnv + config.Default + k
+		}
+		authTokenEnv := target.EnvPulsarAuthToken
+		if k != config.Default {
+			authTokenEnv = authTokenEnv + config.Default + k
+		}
+		tlsSkipVerifyEnv := target.EnvPulsarTLSSkipVerify
+		if k != config.Default {
+			tlsSkipVerifyEnv = tlsSkipVerifyEnv + config.Default + k
+		}
+		clientTLSCertEnv := target.EnvPulsarClientTLSCert
+		if k != config.Default {
+			clientTLSCertEnv = clientTLSCertEnv + config.Default + k
+		}
+		clientTLSKeyEnv := target.EnvPulsarClientTLSKey
+		if k != config.Default {
+			clientTLSKeyEnv = clientTLSKeyEnv + config.Default + k
+		}
+		queueDirEnv := target.EnvPulsarQueueDir
+		if k != config.Default {
+			queueDirEnv = queueDirEnv + config.Default + k
+		}
+
+		pulsarArgs := target.PulsarArgs{
+			Enable:         enabled,
+			URL:            *url,
+			Topic:          env.Get(topicEnv, kv.Get(target.PulsarTopic)),
+			AuthToken:      env.Get(authTokenEnv, kv.Get(target.PulsarAuthToken)),
+			QueueDir:       env.Get(queueDirEnv, kv.Get(target.PulsarQueueDir)),
+			QueueLimit:     queueLimit,
+			QueueSizeLimit: queueSizeLimit,
+			QueueCompress:  queueCompress,
+		}
+		pulsarArgs.TLS.RootCAs = rootCAs
+		pulsarArgs.TLS.SkipVerify = env.Get(tlsSkipVerifyEnv, kv.Get(target.PulsarTLSSkipVerify)) == config.EnableOn
+		pulsarArgs.TLS.ClientTLSCert = env.Get(clientTLSCertEnv, kv.Get(target.PulsarClientTLSCert))
+		pulsarArgs.TLS.ClientTLSKey = env.Get(clientTLSKeyEnv, kv.Get(target.PulsarClientTLSKey))
+
+		if err = pulsarArgs.Validate(); err != nil {
+			return nil, err
+		}
+
+		pulsarTargets[k] = pulsarArgs
+	}
+	return pulsarTargets, nil
+}
+
 // DefaultPostgresKVS - default Postgres KV for server config.
 var (
 	DefaultPostgresKVS = config.KVS{
@@ -1125,6 +1429,14 @@ var (
 			Key:   target.PostgresQueueLimit,
 			Value: "0",
 		},
+		config.KV{
+			Key:   target.PostgresQueueSizeLimit,
+			Value: "0",
+		},
+		config.KV{
+			Key:   target.PostgresQueueCompression,
+			Value: config.EnableOff,
+		},
 		config.KV{
 			Key:   target.PostgresMaxOpenConnections,
 			Value: "2",
@@ -1159,6 +1471,13 @@ func GetNotifyPostgres(postgresKVS map[string]config.KVS) (map[string]target.Pos
 			return nil, err
 		}
 
+		queueSizeLimit, queueCompress, err := getQueueStoreArgs(kv, k,
+			target.PostgresQueueSizeLimit, target.EnvPostgresQueueSizeLimit,
+			target.PostgresQueueCompression, target.EnvPostgresQueueCompression)
+		if err != nil {
+			return nil, err
+		}
+
 		formatEnv := target.EnvPostgresFormat
 		if k != config.Default {
 			formatEnv = formatEnv + config.Default + k
@@ -1196,6 +1515,8 @@ func GetNotifyPostgres(postgresKVS map[string]config.KVS) (map[string]target.Pos
 			Table:              env.Get(tableEnv, kv.Get(target.PostgresTable)),
 			QueueDir:           env.Get(queueDirEnv, kv.Get(target.PostgresQueueDir)),
 			QueueLimit:         uint64(queueLimit),
+			QueueSizeLimit:     queueSizeLimit,
+			QueueCompress:      queueCompress,
 			MaxOpenConnections: maxOpenConnections,
 		}
 		if err = psqlArgs.Validate(); err != nil {
@@ -1238,6 +1559,14 @@ var (
 			Key:   target.RedisQueueLimit,
 			Value: "0",
 		},
+		config.KV{
+			Key:   target.RedisQueueSizeLimit,
+			Value: "0",
+		},
+		config.KV{
+			Key:   target.RedisQueueCompression,
+			Value: config.EnableOff,
+		},
 	}
 )
 
@@ -1274,6 +1603,13 @@ func GetNotifyRedis(redisKVS map[string]config.KVS) (map[string]target.RedisArgs
 		if err != nil {
 			return nil, err
 		}
+
+		queueSizeLimit, queueCompress, err := getQueueStoreArgs(kv, k,
+			target.RedisQueueSizeLimit, target.EnvRedisQueueSizeLimit,
+			target.RedisQueueCompression, target.EnvRedisQueueCompression)
+		if err != nil {
+			return nil, err
+		}
 		formatEnv := target.EnvRedisFormat
 		if k != config.Default {
 			formatEnv = formatEnv + config.Default + k
@@ -1291,13 +1627,15 @@ func GetNotifyRedis(redisKVS map[string]config.KVS) (map[string]target.RedisArgs
 			queueDirEnv = queueDirEnv + config.Default + k
 		}
 		redisArgs := target.RedisArgs{
-			Enable:     enabled,
-			Format:     env.Get(formatEnv, kv.Get(target.RedisFormat)),
-			Addr:       *addr,
-			Password:   env.Get(passwordEnv, kv.Get(target.RedisPassword)),
-			Key:        env.Get(keyEnv, kv.Get(target.RedisKey)),
-			QueueDir:   env.Get(queueDirEnv, kv.Get(target.RedisQueueDir)),
-			QueueLimit: uint64(queueLimit),
+			Enable:         enabled,
+			Format:         env.Get(formatEnv, kv.Get(target.RedisFormat)),
+			Addr:           *addr,
+			Password:       env.Get(passwordEnv, kv.Get(target.RedisPassword)),
+			Key:            env.Get(keyEnv, kv.Get(target.RedisKey)),
+			QueueDir:       env.Get(queueDirEnv, kv.Get(target.RedisQueueDir)),
+			QueueLimit:     uint64(queueLimit),
+			QueueSizeLimit: queueSizeLimit,
+			QueueCompress:  queueCompress,
 		}
 		if err = redisArgs.Validate(); err != nil {
 			return nil, err
@@ -1326,6 +1664,14 @@ var (
 			Key:   target.WebhookQueueLimit,
 			Value: "0",
 		},
+		config.KV{
+			Key:   target.WebhookQueueSizeLimit,
+			Value: "0",
+		},
+		config.KV{
+			Key:   target.WebhookQueueCompression,
+			Value: config.EnableOff,
+		},
 		config.KV{
 			Key:   target.WebhookQueueDir,
 			Value: "",
@@ -1338,6 +1684,18 @@ var (
 			Key:   target.WebhookClientKey,
 			Value: "",
 		},
+		config.KV{
+			Key:   target.WebhookBatchSize,
+			Value: "0",
+		},
+		config.KV{
+			Key:   target.WebhookBatchMaxWait,
+			Value: "1s",
+		},
+		config.KV{
+			Key:   target.WebhookPayloadFormat,
+			Value: target.PayloadFormatDefault,
+		},
 	}
 )
 
@@ -1374,6 +1732,13 @@ func GetNotifyWebhook(webhookKVS map[string]config.KVS, transport *http.Transpor
 		if err != nil {
 			return nil, err
 		}
+
+		queueSizeLimit, queueCompress, err := getQueueStoreArgs(kv, k,
+			target.WebhookQueueSizeLimit, target.EnvWebhookQueueSizeLimit,
+			target.WebhookQueueCompression, target.EnvWebhookQueueCompression)
+		if err != nil {
+			return nil, err
+		}
 		queueDirEnv := target.EnvWebhookQueueDir
 		if k != config.Default {
 			queueDirEnv = queueDirEnv + config.Default + k
@@ -1392,15 +1757,46 @@ func GetNotifyWebhook(webhookKVS map[string]config.KVS, transport *http.Transpor
 			clientKeyEnv = clientKeyEnv + config.Default + k
 		}
 
+		batchSizeEnv := target.EnvWebhookBatchSize
+		if k != config.Default {
+			batchSizeEnv = batchSizeEnv + config.Default + k
+		}
+		batchSize, err := strconv.ParseUint(env.Get(batchSizeEnv, kv.Get(target.WebhookBatchSize)), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		batchMaxWaitEnv := target.EnvWebhookBatchMaxWait
+		if k != config.Default {
+			batchMaxWaitEnv = batchMaxWaitEnv + config.Default + k
+		}
+		var batchMaxWait time.Duration
+		if v := env.Get(batchMaxWaitEnv, kv.Get(target.WebhookBatchMaxWait)); v != "" {
+			batchMaxWait, err = time.ParseDuration(v)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		payloadFormatEnv := target.EnvWebhookPayloadFormat
+		if k != config.Default {
+			payloadFormatEnv = payloadFormatEnv + config.Default + k
+		}
+
 		webhookArgs := target.WebhookArgs{
-			Enable:     enabled,
-			Endpoint:   *url,
-			Transport:  transport,
-			AuthToken:  env.Get(authEnv, kv.Get(target.WebhookAuthToken)),
-			QueueDir:   env.Get(queueDirEnv, kv.Get(target.WebhookQueueDir)),
-			QueueLimit: uint64(queueLimit),
-			ClientCert: env.Get(clientCertEnv, kv.Get(target.WebhookClientCert)),
-			ClientKey:  env.Get(clientKeyEnv, kv.Get(target.WebhookClientKey)),
+			Enable:         enabled,
+			Endpoint:       *url,
+			Transport:      transport,
+			AuthToken:      env.Get(authEnv, kv.Get(target.WebhookAuthToken)),
+			QueueDir:       env.Get(queueDirEnv, kv.Get(target.WebhookQueueDir)),
+			QueueLimit:     uint64(queueLimit),
+			QueueSizeLimit: queueSizeLimit,
+			QueueCompress:  queueCompress,
+			ClientCert:     env.Get(clientCertEnv, kv.Get(target.WebhookClientCert)),
+			ClientKey:      env.Get(clientKeyEnv, kv.Get(target.WebhookClientKey)),
+			BatchSize:      uint32(batchSize),
+			BatchMaxWait:   batchMaxWait,
+			PayloadFormat:  env.Get(payloadFormatEnv, kv.Get(target.WebhookPayloadFormat)),
 		}
 		if err = webhookArgs.Validate(); err != nil {
 			return nil, err
@@ -1437,6 +1833,14 @@ var (
 			Key:   target.ElasticQueueLimit,
 			Value: "0",
 		},
+		config.KV{
+			Key:   target.ElasticQueueSizeLimit,
+			Value: "0",
+		},
+		config.KV{
+			Key:   target.ElasticQueueCompression,
+			Value: config.EnableOff,
+		},
 		config.KV{
 			Key:   target.ElasticUsername,
 			Value: "",
@@ -1484,6 +1888,13 @@ func GetNotifyES(esKVS map[string]config.KVS, transport *http.Transport) (map[st
 			return nil, err
 		}
 
+		queueSizeLimit, queueCompress, err := getQueueStoreArgs(kv, k,
+			target.ElasticQueueSizeLimit, target.EnvElasticQueueSizeLimit,
+			target.ElasticQueueCompression, target.EnvElasticQueueCompression)
+		if err != nil {
+			return nil, err
+		}
+
 		formatEnv := target.EnvElasticFormat
 		if k != config.Default {
 			formatEnv = formatEnv + config.Default + k
@@ -1510,15 +1921,17 @@ func GetNotifyES(esKVS map[string]config.KVS, transport *http.Transport) (map[st
 		}
 
 		esArgs := target.ElasticsearchArgs{
-			Enable:     enabled,
-			Format:     env.Get(formatEnv, kv.Get(target.ElasticFormat)),
-			URL:        *url,
-			Index:      env.Get(indexEnv, kv.Get(target.ElasticIndex)),
-			QueueDir:   env.Get(queueDirEnv, kv.Get(target.ElasticQueueDir)),
-			QueueLimit: uint64(queueLimit),
-			Transport:  transport,
-			Username:   env.Get(usernameEnv, kv.Get(target.ElasticUsername)),
-			Password:   env.Get(passwordEnv, kv.Get(target.ElasticPassword)),
+			Enable:         enabled,
+			Format:         env.Get(formatEnv, kv.Get(target.ElasticFormat)),
+			URL:            *url,
+			Index:          env.Get(indexEnv, kv.Get(target.ElasticIndex)),
+			QueueDir:       env.Get(queueDirEnv, kv.Get(target.ElasticQueueDir)),
+			QueueLimit:     uint64(queueLimit),
+			QueueSizeLimit: queueSizeLimit,
+			QueueCompress:  queueCompress,
+			Transport:      transport,
+			Username:       env.Get(usernameEnv, kv.Get(target.ElasticUsername)),
+			Password:       env.Get(passwordEnv, kv.Get(target.ElasticPassword)),
 		}
 		if err = esArgs.Validate(); err != nil {
 			return nil, err
@@ -1583,6 +1996,14 @@ var (
 			Key:   target.AmqpQueueLimit,
 			Value: "0",
 		},
+		config.KV{
+			Key:   target.AmqpQueueSizeLimit,
+			Value: "0",
+		},
+		config.KV{
+			Key:   target.AmqpQueueCompression,
+			Value: config.EnableOff,
+		},
 		config.KV{
 			Key:   target.AmqpQueueDir,
 			Value: "",
@@ -1673,6 +2094,13 @@ func GetNotifyAMQP(amqpKVS map[string]config.KVS) (map[string]target.AMQPArgs, e
 		if err != nil {
 			return nil, err
 		}
+
+		queueSizeLimit, queueCompress, err := getQueueStoreArgs(kv, k,
+			target.AmqpQueueSizeLimit, target.EnvAMQPQueueSizeLimit,
+			target.AmqpQueueCompression, target.EnvAMQPQueueCompression)
+		if err != nil {
+			return nil, err
+		}
 		amqpArgs := target.AMQPArgs{
 			Enable:            enabled,
 			URL:               *url,
@@ -1689,6 +2117,8 @@ func GetNotifyAMQP(amqpKVS map[string]config.KVS) (map[string]target.AMQPArgs, e
 			PublisherConfirms: env.Get(publisherConfirmsEnv, kv.Get(target.AmqpPublisherConfirms)) == config.EnableOn,
 			QueueDir:          env.Get(queueDirEnv, kv.Get(target.AmqpQueueDir)),
 			QueueLimit:        queueLimit,
+			QueueSizeLimit:    queueSizeLimit,
+			QueueCompress:     queueCompress,
 		}
 		if err = amqpArgs.Validate(); err != nil {
 			return nil, err
@@ -0,0 +1,198 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package storageclass
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/infobsmi/b33s/internal/config"
+)
+
+const (
+	// Separates the storage classes in the custom value.
+	customClassSeparator = ";"
+	// Separates the parity and the options of a storage class.
+	customOptionSeparator = ","
+	// Separates the pool indexes of the pools option.
+	customPoolSeparator = ":"
+
+	customOptionInline = "inline"
+	customOptionPools  = "pools"
+
+	// Largest inline data threshold allowed for a storage class.
+	maxInlineBlock = 1 * humanize.MiByte
+)
+
+var customClassNameRegexp = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,62}$`)
+
+// CustomClass - holds a storage class defined by the administrator.
+type CustomClass struct {
+	Parity int
+	// Shard size below which object data is stored inline in the
+	// metadata, -1 if the default threshold applies.
+	InlineBlock int64
+	// Pools new objects of this storage class are placed on, all
+	// pools if empty.
+	Pools []int
+}
+
+// String returns the textual form of the storage class as accepted
+// by the custom config value.
+func (c CustomClass) String() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "%s:%d", schemePrefix, c.Parity)
+	if c.InlineBlock >= 0 {
+		fmt.Fprintf(&s, "%s%s=%d", customOptionSeparator, customOptionInline, c.InlineBlock)
+	}
+	if len(c.Pools) > 0 {
+		pools := make([]string, len(c.Pools))
+		for i, p := range c.Pools {
+			pools[i] = strconv.Itoa(p)
+		}
+		fmt.Fprintf(&s, "%s%s=%s", customOptionSeparator, customOptionPools, strings.Join(pools, customPoolSeparator))
+	}
+	return s.String()
+}
+
+// Parses the custom storage classes, in the format
+// "NAME=EC:parity[,inline=size][,pools=idx[:idx...]][;NAME=...]".
+func parseCustomClasses(s string) (map[string]CustomClass, error) {
+	classes := make(map[string]CustomClass)
+	for _, entry := range strings.Split(s, customClassSeparator) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, spec, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, config.ErrStorageClassValue(nil).Msg("Missing parity for storage class " + entry)
+		}
+		name = strings.TrimSpace(name)
+		if !customClassNameRegexp.MatchString(name) {
+			return nil, config.ErrStorageClassValue(nil).Msg("Invalid storage class name " + name + ", only upper case letters, digits and '_' are allowed")
+		}
+		if IsValid(name) {
+			return nil, config.ErrStorageClassValue(nil).Msg("Storage class name " + name + " is reserved")
+		}
+		if _, ok = classes[name]; ok {
+			return nil, config.ErrStorageClassValue(nil).Msg("Duplicate storage class " + name)
+		}
+		c, err := parseCustomClass(spec)
+		if err != nil {
+			return nil, err
+		}
+		classes[name] = c
+	}
+	if len(classes) == 0 {
+		return nil, nil
+	}
+	return classes, nil
+}
+
+// Parses a single custom storage class, e.g. "EC:8,inline=64KiB,pools=1:2".
+func parseCustomClass(spec string) (c CustomClass, err error) {
+	opts := strings.Split(spec, customOptionSeparator)
+	sc, err := parseStorageClass(strings.TrimSpace(opts[0]))
+	if err != nil {
+		return c, err
+	}
+	c.Parity = sc.Parity
+	c.InlineBlock = -1
+	for _, opt := range opts[1:] {
+		k, v, ok := strings.Cut(strings.TrimSpace(opt), "=")
+		if !ok {
+			return c, config.ErrStorageClassValue(nil).Msg("Invalid storage class option " + opt)
+		}
+		switch k {
+		case customOptionInline:
+			size, err := humanize.ParseBytes(v)
+			if err != nil {
+				return c, config.ErrStorageClassValue(err)
+			}
+			if size > maxInlineBlock {
+				return c, config.ErrStorageClassValue(nil).Msg("Inline threshold " + v + " should be less than or equal to " + humanize.IBytes(maxInlineBlock))
+			}
+			c.InlineBlock = int64(size)
+		case customOptionPools:
+			seen := make(map[int]struct{})
+			for _, p := range strings.Split(v, customPoolSeparator) {
+				idx, err := strconv.Atoi(p)
+				if err != nil {
+					return c, config.ErrStorageClassValue(err)
+				}
+				if idx < 0 {
+					return c, config.ErrStorageClassValue(nil).Msg("Unsupported pool index " + p + " provided")
+				}
+				if _, ok := seen[idx]; ok {
+					continue
+				}
+				seen[idx] = struct{}{}
+				c.Pools = append(c.Pools, idx)
+			}
+			sort.Ints(c.Pools)
+		default:
+			return c, config.ErrStorageClassValue(nil).Msg("Unsupported storage class option " + k)
+		}
+	}
+	return c, nil
+}
+
+// IsCustom - returns true if sc is a custom storage class defined in sCfg.
+func (sCfg Config) IsCustom(sc string) bool {
+	ConfigLock.RLock()
+	defer ConfigLock.RUnlock()
+	_, ok := sCfg.Custom[sc]
+	return ok
+}
+
+// InlineBlockForSC - returns the inline data threshold of the storage
+// class sc, ok is false if the default threshold applies.
+func (sCfg Config) InlineBlockForSC(sc string) (size int64, ok bool) {
+	ConfigLock.RLock()
+	defer ConfigLock.RUnlock()
+	c, found := sCfg.Custom[sc]
+	if !found || c.InlineBlock < 0 {
+		return 0, false
+	}
+	return c.InlineBlock, true
+}
+
+// PoolsForSC - returns the indexes of the pools new objects of the
+// storage class sc are placed on, nil if any pool may be used.
+func (sCfg Config) PoolsForSC(sc string) []int {
+	ConfigLock.RLock()
+	defer ConfigLock.RUnlock()
+	return sCfg.Custom[sc].Pools
+}
+
+// ValidatePools - validates the pool affinity of the custom storage
+// classes against the number of pools of the deployment.
+func (sCfg Config) ValidatePools(poolCount int) error {
+	for name, c := range sCfg.Custom {
+		for _, idx := range c.Pools {
+			if idx >= poolCount {
+				return fmt.Errorf("Custom storage class %s pool index %d should be less than %d", name, idx, poolCount)
+			}
+		}
+	}
+	return nil
+}
@@ -38,6 +38,12 @@ var (
 			Optional:    true,
 			Type:        "string",
 		},
+		config.HelpKV{
+			Key:         ClassCustom,
+			Description: `define named storage classes e.g. "COLD_EC8=EC:8,inline=0,pools=1;HOT_EC2=EC:2,inline=256KiB"` + defaultHelpPostfix(ClassCustom),
+			Optional:    true,
+			Type:        "string",
+		},
 		config.HelpKV{
 			Key:         config.Comment,
 			Description: config.DefaultComment,
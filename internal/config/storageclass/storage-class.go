@@ -40,11 +40,14 @@ const (
 const (
 	ClassStandard = "standard"
 	ClassRRS      = "rrs"
+	ClassCustom   = "custom"
 
 	// Reduced redundancy storage class environment variable
 	RRSEnv = "MINIO_STORAGE_CLASS_RRS"
 	// Standard storage class environment variable
 	StandardEnv = "MINIO_STORAGE_CLASS_STANDARD"
+	// Custom storage classes environment variable
+	CustomEnv = "MINIO_STORAGE_CLASS_CUSTOM"
 
 	// Supported storage class scheme is EC
 	schemePrefix = "EC"
@@ -67,6 +70,10 @@ var (
 			Key:   ClassRRS,
 			Value: "EC:1",
 		},
+		config.KV{
+			Key:   ClassCustom,
+			Value: "",
+		},
 	}
 )
 
@@ -80,8 +87,9 @@ var ConfigLock sync.RWMutex
 
 // Config storage class configuration
 type Config struct {
-	Standard StorageClass `json:"standard"`
-	RRS      StorageClass `json:"rrs"`
+	Standard StorageClass           `json:"standard"`
+	RRS      StorageClass           `json:"rrs"`
+	Custom   map[string]CustomClass `json:"custom,omitempty"`
 }
 
 // UnmarshalJSON - Validate SS and RRS parity when unmarshalling JSON.
@@ -101,6 +109,15 @@ func IsValid(sc string) bool {
 	return sc == RRS || sc == STANDARD
 }
 
+// IsValid - returns true if input string is one of the built-in
+// storage classes or a custom storage class defined in sCfg.
+func (sCfg Config) IsValid(sc string) bool {
+	if IsValid(sc) {
+		return true
+	}
+	return sCfg.IsCustom(sc)
+}
+
 // UnmarshalText unmarshals storage class from its textual form into
 // storageClass structure.
 func (sc *StorageClass) UnmarshalText(b []byte) error {
@@ -228,12 +245,17 @@ func validateParity(ssParity, rrsParity, setDriveCount int) (err error) {
 func (sCfg Config) GetParityForSC(sc string) (parity int) {
 	ConfigLock.RLock()
 	defer ConfigLock.RUnlock()
-	switch strings.TrimSpace(sc) {
+	sc = strings.TrimSpace(sc)
+	switch sc {
 	case RRS:
 		return sCfg.RRS.Parity
-	default:
+	case STANDARD, "":
 		return sCfg.Standard.Parity
 	}
+	if c, ok := sCfg.Custom[sc]; ok {
+		return c.Parity
+	}
+	return sCfg.Standard.Parity
 }
 
 // Update update storage-class with new config
@@ -242,13 +264,15 @@ func (sCfg *Config) Update(newCfg Config) {
 	defer ConfigLock.Unlock()
 	sCfg.RRS = newCfg.RRS
 	sCfg.Standard = newCfg.Standard
+	sCfg.Custom = newCfg.Custom
 }
 
-// Enabled returns if etcd is enabled.
+// Enabled returns if storage class is configured.
 func Enabled(kvs config.KVS) bool {
 	ssc := kvs.Get(ClassStandard)
 	rrsc := kvs.Get(ClassRRS)
-	return ssc != "" || rrsc != ""
+	csc := kvs.Get(ClassCustom)
+	return ssc != "" || rrsc != "" || csc != ""
 }
 
 // DefaultParityBlocks returns default parity blocks for 'drive' count
@@ -312,5 +336,18 @@ func LookupConfig(kvs config.KVS, setDriveCount int) (cfg Config, err error) {
 		return cfg, err
 	}
 
+	if csc := env.Get(CustomEnv, kvs.Get(ClassCustom)); csc != "" {
+		cfg.Custom, err = parseCustomClasses(csc)
+		if err != nil {
+			return cfg, err
+		}
+		for name, c := range cfg.Custom {
+			if err = ValidateParity(c.Parity, setDriveCount); err != nil {
+				cfg.Custom = nil
+				return cfg, fmt.Errorf("Custom storage class %s %w", name, err)
+			}
+		}
+	}
+
 	return cfg, nil
 }
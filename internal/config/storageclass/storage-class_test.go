@@ -187,3 +187,80 @@ func TestIsValidStorageClassKind(t *testing.T) {
 		}
 	}
 }
+
+func TestParseCustomClasses(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    map[string]CustomClass
+		success bool
+	}{
+		{"", nil, true},
+		{"COLD_EC8=EC:8", map[string]CustomClass{
+			"COLD_EC8": {Parity: 8, InlineBlock: -1},
+		}, true},
+		{"COLD_EC8=EC:8,inline=0,pools=2:1:2; HOT_EC2=EC:2,inline=256KiB", map[string]CustomClass{
+			"COLD_EC8": {Parity: 8, InlineBlock: 0, Pools: []int{1, 2}},
+			"HOT_EC2":  {Parity: 2, InlineBlock: 256 << 10},
+		}, true},
+		{"COLD_EC8", nil, false},
+		{"cold=EC:8", nil, false},
+		{"STANDARD=EC:2", nil, false},
+		{"COLD=EC:2;COLD=EC:3", nil, false},
+		{"COLD=EC:x", nil, false},
+		{"COLD=EC:2,inline=2MiB", nil, false},
+		{"COLD=EC:2,pools=-1", nil, false},
+		{"COLD=EC:2,dma=write", nil, false},
+	}
+	for i, tt := range tests {
+		got, err := parseCustomClasses(tt.value)
+		if err != nil && tt.success {
+			t.Errorf("Test %d, Expected success, got %s", i+1, err)
+			continue
+		}
+		if err == nil && !tt.success {
+			t.Errorf("Test %d, Expected failure, got success", i+1)
+			continue
+		}
+		if tt.success && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Test %d, Expected %v, got %v", i+1, tt.want, got)
+		}
+	}
+}
+
+func TestCustomClassConfig(t *testing.T) {
+	kvs := DefaultKVS.Clone()
+	kvs.Set(ClassCustom, "COLD_EC8=EC:8,pools=1;HOT_EC2=EC:2,inline=256KiB")
+	scfg, err := LookupConfig(kvs, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !scfg.IsValid("COLD_EC8") || !scfg.IsValid(STANDARD) || scfg.IsValid("WARM") {
+		t.Error("Expected custom and built-in storage classes to be valid")
+	}
+	if p := scfg.GetParityForSC("COLD_EC8"); p != 8 {
+		t.Errorf("Expected parity 8, got %d", p)
+	}
+	if p := scfg.GetParityForSC("WARM"); p != scfg.Standard.Parity {
+		t.Errorf("Expected standard parity %d, got %d", scfg.Standard.Parity, p)
+	}
+	if sz, ok := scfg.InlineBlockForSC("HOT_EC2"); !ok || sz != 256<<10 {
+		t.Errorf("Expected inline threshold 256KiB, got %d, %t", sz, ok)
+	}
+	if _, ok := scfg.InlineBlockForSC("COLD_EC8"); ok {
+		t.Error("Expected default inline threshold")
+	}
+	if pools := scfg.PoolsForSC("COLD_EC8"); !reflect.DeepEqual(pools, []int{1}) {
+		t.Errorf("Expected pools [1], got %v", pools)
+	}
+	if err = scfg.ValidatePools(2); err != nil {
+		t.Error(err)
+	}
+	if err = scfg.ValidatePools(1); err == nil {
+		t.Error("Expected failure for pool index out of range")
+	}
+
+	kvs.Set(ClassCustom, "COLD_EC8=EC:8")
+	if _, err = LookupConfig(kvs, 8); err == nil {
+		t.Error("Expected failure for parity larger than half the drives")
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"io"
 	"reflect"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 
@@ -137,20 +138,143 @@ func (ruleList FilterRuleList) Pattern() string {
 	return NewPattern(prefix, suffix)
 }
 
-// S3Key - represents elements inside <S3Key>...</S3Key>
+// SizeFilter - represents elements inside <S3Size>...</S3Size>, a missing
+// or zero <Max> means no upper limit.
+type SizeFilter struct {
+	Min int64 `xml:"Min,omitempty" json:"Min,omitempty"`
+	Max int64 `xml:"Max,omitempty" json:"Max,omitempty"`
+}
+
+func (size SizeFilter) isEmpty() bool {
+	return size.Min == 0 && size.Max == 0
+}
+
+// MarshalXML implements a custom marshaller to support `omitempty` feature.
+func (size SizeFilter) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if size.isEmpty() {
+		return nil
+	}
+	type sizeFilterWrapper SizeFilter
+	return e.EncodeElement(sizeFilterWrapper(size), start)
+}
+
+// UnmarshalXML - decodes XML data.
+func (size *SizeFilter) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	// Make subtype to avoid recursive UnmarshalXML().
+	type sizeFilter SizeFilter
+	parsedSize := sizeFilter{}
+	if err := d.DecodeElement(&parsedSize, &start); err != nil {
+		return err
+	}
+
+	if parsedSize.Min < 0 {
+		return &ErrInvalidFilterValue{strconv.FormatInt(parsedSize.Min, 10)}
+	}
+	if parsedSize.Max < 0 || (parsedSize.Max > 0 && parsedSize.Max < parsedSize.Min) {
+		return &ErrInvalidFilterValue{strconv.FormatInt(parsedSize.Max, 10)}
+	}
+
+	*size = SizeFilter(parsedSize)
+	return nil
+}
+
+// ValueFilterRuleList - represents multiple <FilterRule>...</FilterRule>
+// inside <S3Metadata> or <S3Tags>, the name of a rule is a metadata key
+// or a tag key and the value is a wildcard pattern.
+type ValueFilterRuleList struct {
+	Rules []FilterRule `xml:"FilterRule,omitempty"`
+}
+
+// UnmarshalXML - decodes XML data.
+func (ruleList *ValueFilterRuleList) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	// Make subtype to avoid recursive UnmarshalXML(), the rules are
+	// decoded without the prefix/suffix checks of FilterRule.
+	var rules struct {
+		Rules []struct {
+			Name  string `xml:"Name"`
+			Value string `xml:"Value"`
+		} `xml:"FilterRule"`
+	}
+	if err := d.DecodeElement(&rules, &start); err != nil {
+		return err
+	}
+
+	// Every key must be used only once.
+	nameSet := set.NewStringSet()
+	parsedRules := make([]FilterRule, 0, len(rules.Rules))
+	for _, rule := range rules.Rules {
+		if rule.Name == "" || len(rule.Name) > 128 || !utf8.ValidString(rule.Name) {
+			return &ErrInvalidFilterName{rule.Name}
+		}
+		if nameSet.Contains(strings.ToLower(rule.Name)) {
+			return &ErrDuplicateFilterName{rule.Name}
+		}
+		nameSet.Add(strings.ToLower(rule.Name))
+
+		if len(rule.Value) > 1024 || !utf8.ValidString(rule.Value) {
+			return &ErrInvalidFilterValue{rule.Value}
+		}
+		parsedRules = append(parsedRules, FilterRule{Name: rule.Name, Value: rule.Value})
+	}
+
+	ruleList.Rules = parsedRules
+	return nil
+}
+
+// MarshalXML implements a custom marshaller to support `omitempty` feature.
+func (ruleList ValueFilterRuleList) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if ruleList.isEmpty() {
+		return nil
+	}
+	type valueFilterRuleListWrapper ValueFilterRuleList
+	return e.EncodeElement(valueFilterRuleListWrapper(ruleList), start)
+}
+
+func (ruleList ValueFilterRuleList) isEmpty() bool {
+	return len(ruleList.Rules) == 0
+}
+
+// toMap - returns the rules as map of name to value.
+func (ruleList ValueFilterRuleList) toMap() map[string]string {
+	if ruleList.isEmpty() {
+		return nil
+	}
+	m := make(map[string]string, len(ruleList.Rules))
+	for _, rule := range ruleList.Rules {
+		m[rule.Name] = rule.Value
+	}
+	return m
+}
+
+// S3Key - represents elements inside <Filter>...</Filter>, i.e. the
+// prefix/suffix rules inside <S3Key>...</S3Key> and the object filters
+// inside <S3Size>, <S3Metadata> and <S3Tags>.
 type S3Key struct {
-	RuleList FilterRuleList `xml:"S3Key,omitempty" json:"S3Key,omitempty"`
+	RuleList FilterRuleList      `xml:"S3Key,omitempty" json:"S3Key,omitempty"`
+	Size     SizeFilter          `xml:"S3Size,omitempty" json:"S3Size,omitempty"`
+	Metadata ValueFilterRuleList `xml:"S3Metadata,omitempty" json:"S3Metadata,omitempty"`
+	Tags     ValueFilterRuleList `xml:"S3Tags,omitempty" json:"S3Tags,omitempty"`
 }
 
 // MarshalXML implements a custom marshaller to support `omitempty` feature.
 func (s3Key S3Key) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
-	if s3Key.RuleList.isEmpty() {
+	if s3Key.RuleList.isEmpty() && s3Key.Size.isEmpty() && s3Key.Metadata.isEmpty() && s3Key.Tags.isEmpty() {
 		return nil
 	}
 	type s3KeyWrapper S3Key
 	return e.EncodeElement(s3KeyWrapper(s3Key), start)
 }
 
+// ObjectFilter - returns the object filter of size, metadata and tag rules.
+func (s3Key S3Key) ObjectFilter() ObjectFilter {
+	return ObjectFilter{
+		MinSize:  s3Key.Size.Min,
+		MaxSize:  s3Key.Size.Max,
+		Metadata: s3Key.Metadata.toMap(),
+		Tags:     s3Key.Tags.toMap(),
+	}
+}
+
 // common - represents common elements inside <QueueConfiguration>, <CloudFunctionConfiguration>
 // and <TopicConfiguration>
 type common struct {
@@ -219,8 +343,9 @@ func (q *Queue) SetRegion(region string) {
 
 // ToRulesMap - converts Queue to RulesMap
 func (q Queue) ToRulesMap() RulesMap {
-	pattern := q.Filter.RuleList.Pattern()
-	return NewRulesMap(q.Events, pattern, q.ARN.TargetID)
+	rulesMap := make(RulesMap)
+	rulesMap.addRule(q.Events, NewRule(q.Filter.RuleList.Pattern(), q.Filter.ObjectFilter()), q.ARN.TargetID)
+	return rulesMap
 }
 
 // Unused.  Available for completion.
@@ -33,6 +33,8 @@ func IsEventError(err error) bool {
 		return true
 	case ErrInvalidFilterValue, *ErrInvalidFilterValue:
 		return true
+	case ErrDuplicateFilterName, *ErrDuplicateFilterName:
+		return true
 	case ErrDuplicateEventName, *ErrDuplicateEventName:
 		return true
 	case ErrUnsupportedConfiguration, *ErrUnsupportedConfiguration:
@@ -84,6 +86,15 @@ func (err ErrInvalidFilterValue) Error() string {
 	return fmt.Sprintf("invalid filter value '%v'", err.FilterValue)
 }
 
+// ErrDuplicateFilterName - duplicate metadata or tag key in filter rule error.
+type ErrDuplicateFilterName struct {
+	FilterName string
+}
+
+func (err ErrDuplicateFilterName) Error() string {
+	return fmt.Sprintf("duplicate filter name '%v'", err.FilterName)
+}
+
 // ErrDuplicateEventName - duplicate event name error.
 type ErrDuplicateEventName struct {
 	EventName Name
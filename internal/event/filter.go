@@ -0,0 +1,149 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package event
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/minio/pkg/wildcard"
+)
+
+// Object filter keys of the encoded rule.
+const (
+	filterMinSize        = "min-size"
+	filterMaxSize        = "max-size"
+	filterMetadataPrefix = "metadata:"
+	filterTagPrefix      = "tag:"
+)
+
+// userMetadataPrefix - prefix of user metadata keys, which is optional in
+// metadata filter rules.
+const userMetadataPrefix = "x-amz-meta-"
+
+// ObjectProps - object properties matched by the object filter of a rule.
+type ObjectProps struct {
+	Name string
+	Size int64
+	// Metadata holds the content type and the user metadata, keys
+	// are matched case insensitively.
+	Metadata map[string]string
+	Tags     map[string]string
+}
+
+// ObjectFilter - filters events on the size, the metadata and the tags of
+// the object. Metadata and tag values are wildcard patterns.
+type ObjectFilter struct {
+	MinSize  int64
+	MaxSize  int64 // no upper limit if zero
+	Metadata map[string]string
+	Tags     map[string]string
+}
+
+// IsEmpty - returns whether the filter matches every object.
+func (filter ObjectFilter) IsEmpty() bool {
+	return filter.MinSize <= 0 && filter.MaxSize <= 0 && len(filter.Metadata) == 0 && len(filter.Tags) == 0
+}
+
+// encode - returns the canonical string form of the filter.
+func (filter ObjectFilter) encode() string {
+	values := make(url.Values)
+	if filter.MinSize > 0 {
+		values.Set(filterMinSize, strconv.FormatInt(filter.MinSize, 10))
+	}
+	if filter.MaxSize > 0 {
+		values.Set(filterMaxSize, strconv.FormatInt(filter.MaxSize, 10))
+	}
+	for key, value := range filter.Metadata {
+		values.Set(filterMetadataPrefix+metadataFilterKey(key), value)
+	}
+	for key, value := range filter.Tags {
+		values.Set(filterTagPrefix+key, value)
+	}
+	// Encode sorts by key, equal filters are equal strings.
+	return values.Encode()
+}
+
+// metadataFilterKey - returns the lower case metadata key without the user
+// metadata prefix.
+func metadataFilterKey(key string) string {
+	return strings.TrimPrefix(strings.ToLower(key), userMetadataPrefix)
+}
+
+// Match - returns whether the object properties match the filter. Objects
+// without the filtered metadata key or tag do not match.
+func (filter ObjectFilter) Match(obj ObjectProps) bool {
+	if filter.MinSize > 0 && obj.Size < filter.MinSize {
+		return false
+	}
+	if filter.MaxSize > 0 && obj.Size > filter.MaxSize {
+		return false
+	}
+
+	if len(filter.Metadata) > 0 {
+		metadata := make(map[string]string, len(obj.Metadata))
+		for key, value := range obj.Metadata {
+			metadata[metadataFilterKey(key)] = value
+		}
+		for key, pattern := range filter.Metadata {
+			value, ok := metadata[metadataFilterKey(key)]
+			if !ok || !wildcard.MatchSimple(pattern, value) {
+				return false
+			}
+		}
+	}
+
+	for key, pattern := range filter.Tags {
+		value, ok := obj.Tags[key]
+		if !ok || !wildcard.MatchSimple(pattern, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Rule - object name pattern and object filter of an event rule.
+type Rule struct {
+	Pattern string
+	Filter  ObjectFilter
+}
+
+// NewRule - returns the rule of an object name pattern and an object filter.
+func NewRule(pattern string, filter ObjectFilter) Rule {
+	if pattern == "" {
+		pattern = "*"
+	}
+	return Rule{Pattern: pattern, Filter: filter}
+}
+
+// key - returns the string form of the rule, equal rules have equal keys.
+// The key is the pattern if the filter is empty.
+func (rule Rule) key() string {
+	if rule.Filter.IsEmpty() {
+		return rule.Pattern
+	}
+	// Neither patterns nor encoded filters contain a backslash.
+	return rule.Pattern + `\` + rule.Filter.encode()
+}
+
+// Match - returns whether the object matches the pattern and the filter of
+// the rule.
+func (rule Rule) Match(obj ObjectProps) bool {
+	return wildcard.MatchSimple(rule.Pattern, obj.Name) && rule.Filter.Match(obj)
+}
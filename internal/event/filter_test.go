@@ -0,0 +1,168 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package event
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestObjectFilterMatch(t *testing.T) {
+	filter := ObjectFilter{
+		MinSize:  1024,
+		MaxSize:  4096,
+		Metadata: map[string]string{"Content-Type": "video/*", "x-amz-meta-camera": "front"},
+		Tags:     map[string]string{"process": "true"},
+	}
+	obj := ObjectProps{
+		Name:     "videos/1.mp4",
+		Size:     2048,
+		Metadata: map[string]string{"content-type": "video/mp4", "X-Amz-Meta-Camera": "front"},
+		Tags:     map[string]string{"process": "true"},
+	}
+
+	testCases := []struct {
+		modify         func(obj *ObjectProps)
+		expectedResult bool
+	}{
+		{func(obj *ObjectProps) {}, true},
+		{func(obj *ObjectProps) { obj.Size = 1024 }, true},
+		{func(obj *ObjectProps) { obj.Size = 4096 }, true},
+		{func(obj *ObjectProps) { obj.Size = 1023 }, false},
+		{func(obj *ObjectProps) { obj.Size = 4097 }, false},
+		{func(obj *ObjectProps) {
+			obj.Metadata = map[string]string{"Content-Type": "video/webm", "camera": "front"}
+		}, true},
+		{func(obj *ObjectProps) {
+			obj.Metadata = map[string]string{"Content-Type": "image/jpeg", "camera": "front"}
+		}, false},
+		{func(obj *ObjectProps) { obj.Metadata = map[string]string{"Content-Type": "video/mp4"} }, false},
+		{func(obj *ObjectProps) { obj.Tags = map[string]string{"process": "false"} }, false},
+		{func(obj *ObjectProps) { obj.Tags = nil }, false},
+	}
+
+	for i, testCase := range testCases {
+		o := obj
+		testCase.modify(&o)
+		if result := filter.Match(o); result != testCase.expectedResult {
+			t.Errorf("test %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestNewRule(t *testing.T) {
+	filter := ObjectFilter{MinSize: 1024, Tags: map[string]string{"process": "true"}}
+
+	testCases := []struct {
+		pattern         string
+		filter          ObjectFilter
+		expectedPattern string
+		expectedKey     string
+	}{
+		{"", ObjectFilter{}, "*", "*"},
+		{"videos/*", ObjectFilter{}, "videos/*", "videos/*"},
+		{"videos/*", filter, "videos/*", `videos/*\min-size=1024&tag%3Aprocess=true`},
+		{"", ObjectFilter{Metadata: map[string]string{"X-Amz-Meta-Camera": "front"}}, "*", `*\metadata%3Acamera=front`},
+	}
+
+	for i, testCase := range testCases {
+		result := NewRule(testCase.pattern, testCase.filter)
+		if result.Pattern != testCase.expectedPattern {
+			t.Errorf("test %v: expected pattern: %v, got: %v", i+1, testCase.expectedPattern, result.Pattern)
+		}
+		if !reflect.DeepEqual(result.Filter, testCase.filter) {
+			t.Errorf("test %v: expected filter: %v, got: %v", i+1, testCase.filter, result.Filter)
+		}
+		if key := result.key(); key != testCase.expectedKey {
+			t.Errorf("test %v: expected key: %v, got: %v", i+1, testCase.expectedKey, key)
+		}
+	}
+}
+
+func TestRulesMatchObject(t *testing.T) {
+	rules := make(Rules)
+	rules.AddRule(NewRule("videos/*", ObjectFilter{MinSize: 1024}), TargetID{"1", "webhook"})
+	rules.AddRule(NewRule("videos/*", ObjectFilter{}), TargetID{"2", "webhook"})
+	rules.AddRule(NewRule("*", ObjectFilter{Tags: map[string]string{"process": "true"}}), TargetID{"3", "webhook"})
+	rules.AddRule(NewRule("*", ObjectFilter{Tags: map[string]string{"process": "true"}}), TargetID{"4", "webhook"})
+
+	testCases := []struct {
+		obj            ObjectProps
+		expectedResult TargetIDSet
+	}{
+		{ObjectProps{Name: "videos/1.mp4", Size: 2048}, NewTargetIDSet(TargetID{"1", "webhook"}, TargetID{"2", "webhook"})},
+		{ObjectProps{Name: "videos/1.mp4", Size: 512}, NewTargetIDSet(TargetID{"2", "webhook"})},
+		{ObjectProps{Name: "images/1.jpg", Tags: map[string]string{"process": "true"}}, NewTargetIDSet(TargetID{"3", "webhook"}, TargetID{"4", "webhook"})},
+		{ObjectProps{Name: "images/1.jpg"}, NewTargetIDSet()},
+	}
+
+	for i, testCase := range testCases {
+		result := rules.MatchObject(testCase.obj)
+		if !reflect.DeepEqual(result, testCase.expectedResult) {
+			t.Errorf("test %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+
+	if !rules.MatchSimple("videos/1.mp4") {
+		t.Errorf("expected simple match of rule pattern")
+	}
+}
+
+func TestS3KeyUnmarshalXML(t *testing.T) {
+	testCases := []struct {
+		data           []byte
+		expectedResult ObjectFilter
+		expectErr      bool
+	}{
+		{[]byte(`<Filter><S3Key><FilterRule><Name>prefix</Name><Value>videos/</Value></FilterRule></S3Key></Filter>`), ObjectFilter{}, false},
+		{[]byte(`<Filter><S3Size><Min>1048576</Min></S3Size><S3Metadata><FilterRule><Name>Content-Type</Name><Value>video/*</Value></FilterRule></S3Metadata><S3Tags><FilterRule><Name>process</Name><Value>true</Value></FilterRule></S3Tags></Filter>`),
+			ObjectFilter{MinSize: 1048576, Metadata: map[string]string{"Content-Type": "video/*"}, Tags: map[string]string{"process": "true"}}, false},
+		{[]byte(`<Filter><S3Size><Min>10</Min><Max>100</Max></S3Size></Filter>`), ObjectFilter{MinSize: 10, MaxSize: 100}, false},
+		{[]byte(`<Filter><S3Size><Min>100</Min><Max>10</Max></S3Size></Filter>`), ObjectFilter{}, true},
+		{[]byte(`<Filter><S3Size><Min>-1</Min></S3Size></Filter>`), ObjectFilter{}, true},
+		{[]byte(`<Filter><S3Tags><FilterRule><Name></Name><Value>true</Value></FilterRule></S3Tags></Filter>`), ObjectFilter{}, true},
+		{[]byte(`<Filter><S3Tags><FilterRule><Name>a</Name><Value>1</Value></FilterRule><FilterRule><Name>a</Name><Value>2</Value></FilterRule></S3Tags></Filter>`), ObjectFilter{}, true},
+	}
+
+	for i, testCase := range testCases {
+		var s3Key S3Key
+		err := xml.Unmarshal(testCase.data, &s3Key)
+		if (err != nil) != testCase.expectErr {
+			t.Fatalf("test %v: error: expected: %v, got: %v", i+1, testCase.expectErr, err)
+		}
+		if err != nil {
+			continue
+		}
+		if result := s3Key.ObjectFilter(); !reflect.DeepEqual(result, testCase.expectedResult) {
+			t.Errorf("test %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+
+		data, err := xml.Marshal(s3Key)
+		if err != nil {
+			t.Fatalf("test %v: unexpected error %v", i+1, err)
+		}
+		var s3Key2 S3Key
+		if err = xml.Unmarshal(data, &s3Key2); err != nil {
+			t.Fatalf("test %v: unexpected error %v", i+1, err)
+		}
+		if !reflect.DeepEqual(s3Key, s3Key2) {
+			t.Errorf("test %v: expected: %v, got: %v", i+1, s3Key, s3Key2)
+		}
+	}
+}
@@ -57,6 +57,9 @@ const (
 	ObjectRestorePostCompleted
 	ObjectTransitionFailed
 	ObjectTransitionComplete
+	ObjectRestoreDelete
+	ObjectLifecycleExpirationDelete
+	ObjectLifecycleExpirationDeleteMarkerCreated
 
 	objectSingleTypesEnd
 	// Start Compound types that require expansion:
@@ -67,6 +70,7 @@ const (
 	ObjectReplicationAll
 	ObjectRestorePostAll
 	ObjectTransitionAll
+	ObjectLifecycleExpirationAll
 	Everything
 )
 
@@ -107,12 +111,18 @@ func (name Name) Expand() []Name {
 		return []Name{
 			ObjectRestorePostInitiated,
 			ObjectRestorePostCompleted,
+			ObjectRestoreDelete,
 		}
 	case ObjectTransitionAll:
 		return []Name{
 			ObjectTransitionFailed,
 			ObjectTransitionComplete,
 		}
+	case ObjectLifecycleExpirationAll:
+		return []Name{
+			ObjectLifecycleExpirationDelete,
+			ObjectLifecycleExpirationDeleteMarkerCreated,
+		}
 	case Everything:
 		res := make([]Name, objectSingleTypesEnd-1)
 		for i := range res {
@@ -190,16 +200,26 @@ func (name Name) String() string {
 		return "s3:Replication:OperationMissedThreshold"
 	case ObjectReplicationReplicatedAfterThreshold:
 		return "s3:Replication:OperationReplicatedAfterThreshold"
+	case ObjectRestorePostAll:
+		return "s3:ObjectRestore:*"
 	case ObjectRestorePostInitiated:
 		return "s3:ObjectRestore:Post"
 	case ObjectRestorePostCompleted:
 		return "s3:ObjectRestore:Completed"
+	case ObjectRestoreDelete:
+		return "s3:ObjectRestore:Delete"
 	case ObjectTransitionAll:
 		return "s3:ObjectTransition:*"
 	case ObjectTransitionFailed:
 		return "s3:ObjectTransition:Failed"
 	case ObjectTransitionComplete:
 		return "s3:ObjectTransition:Complete"
+	case ObjectLifecycleExpirationAll:
+		return "s3:LifecycleExpiration:*"
+	case ObjectLifecycleExpirationDelete:
+		return "s3:LifecycleExpiration:Delete"
+	case ObjectLifecycleExpirationDeleteMarkerCreated:
+		return "s3:LifecycleExpiration:DeleteMarkerCreated"
 	}
 
 	return ""
@@ -306,12 +326,20 @@ func ParseName(s string) (Name, error) {
 		return ObjectRestorePostInitiated, nil
 	case "s3:ObjectRestore:Completed":
 		return ObjectRestorePostCompleted, nil
+	case "s3:ObjectRestore:Delete":
+		return ObjectRestoreDelete, nil
 	case "s3:ObjectTransition:Failed":
 		return ObjectTransitionFailed, nil
 	case "s3:ObjectTransition:Complete":
 		return ObjectTransitionComplete, nil
 	case "s3:ObjectTransition:*":
 		return ObjectTransitionAll, nil
+	case "s3:LifecycleExpiration:*":
+		return ObjectLifecycleExpirationAll, nil
+	case "s3:LifecycleExpiration:Delete":
+		return ObjectLifecycleExpirationDelete, nil
+	case "s3:LifecycleExpiration:DeleteMarkerCreated":
+		return ObjectLifecycleExpirationDeleteMarkerCreated, nil
 	default:
 		return 0, &ErrInvalidEventName{s}
 	}
@@ -37,6 +37,8 @@ func TestNameExpand(t *testing.T) {
 			ObjectCreatedPutRetention, ObjectCreatedPutLegalHold, ObjectCreatedPutTagging, ObjectCreatedDeleteTagging,
 		}},
 		{ObjectRemovedAll, []Name{ObjectRemovedDelete, ObjectRemovedDeleteMarkerCreated}},
+		{ObjectRestorePostAll, []Name{ObjectRestorePostInitiated, ObjectRestorePostCompleted, ObjectRestoreDelete}},
+		{ObjectLifecycleExpirationAll, []Name{ObjectLifecycleExpirationDelete, ObjectLifecycleExpirationDeleteMarkerCreated}},
 		{ObjectAccessedHead, []Name{ObjectAccessedHead}},
 	}
 
@@ -72,6 +74,11 @@ func TestNameString(t *testing.T) {
 		{ObjectCreatedPutLegalHold, "s3:ObjectCreated:PutLegalHold"},
 		{ObjectAccessedGetRetention, "s3:ObjectAccessed:GetRetention"},
 		{ObjectAccessedGetLegalHold, "s3:ObjectAccessed:GetLegalHold"},
+		{ObjectRestorePostAll, "s3:ObjectRestore:*"},
+		{ObjectRestoreDelete, "s3:ObjectRestore:Delete"},
+		{ObjectLifecycleExpirationAll, "s3:LifecycleExpiration:*"},
+		{ObjectLifecycleExpirationDelete, "s3:LifecycleExpiration:Delete"},
+		{ObjectLifecycleExpirationDeleteMarkerCreated, "s3:LifecycleExpiration:DeleteMarkerCreated"},
 
 		{blankName, ""},
 	}
@@ -213,6 +220,9 @@ func TestParseName(t *testing.T) {
 	}{
 		{"s3:ObjectAccessed:*", ObjectAccessedAll, false},
 		{"s3:ObjectRemoved:Delete", ObjectRemovedDelete, false},
+		{"s3:ObjectRestore:Delete", ObjectRestoreDelete, false},
+		{"s3:LifecycleExpiration:*", ObjectLifecycleExpirationAll, false},
+		{"s3:LifecycleExpiration:DeleteMarkerCreated", ObjectLifecycleExpirationDeleteMarkerCreated, false},
 		{"", blankName, true},
 	}
 
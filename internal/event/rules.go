@@ -46,18 +46,40 @@ func NewPattern(prefix, suffix string) (pattern string) {
 	return pattern
 }
 
-// Rules - event rules
-type Rules map[string]TargetIDSet
+// RuleTargets - target IDs of an event rule.
+type RuleTargets struct {
+	Rule
+	TargetIDs TargetIDSet
+}
+
+// Rules - event rules, by the key of the rule.
+type Rules map[string]RuleTargets
 
 // Add - adds pattern and target ID.
 func (rules Rules) Add(pattern string, targetID TargetID) {
-	rules[pattern] = NewTargetIDSet(targetID).Union(rules[pattern])
+	rules.AddRule(Rule{Pattern: pattern}, targetID)
+}
+
+// AddRule - adds rule and target ID.
+func (rules Rules) AddRule(rule Rule, targetID TargetID) {
+	rules.add(rule, NewTargetIDSet(targetID))
+}
+
+func (rules Rules) add(rule Rule, targetIDSet TargetIDSet) {
+	key := rule.key()
+	rt, ok := rules[key]
+	if !ok {
+		rt.Rule = rule
+	}
+	rt.TargetIDs = targetIDSet.Union(rt.TargetIDs)
+	rules[key] = rt
 }
 
 // MatchSimple - returns true one of the matching object name in rules.
+// Object filters of the rules are not checked.
 func (rules Rules) MatchSimple(objectName string) bool {
-	for pattern := range rules {
-		if wildcard.MatchSimple(pattern, objectName) {
+	for _, rt := range rules {
+		if wildcard.MatchSimple(rt.Pattern, objectName) {
 			return true
 		}
 	}
@@ -66,11 +88,17 @@ func (rules Rules) MatchSimple(objectName string) bool {
 
 // Match - returns TargetIDSet matching object name in rules.
 func (rules Rules) Match(objectName string) TargetIDSet {
+	return rules.MatchObject(ObjectProps{Name: objectName})
+}
+
+// MatchObject - returns TargetIDSet matching object name and object
+// properties in rules.
+func (rules Rules) MatchObject(obj ObjectProps) TargetIDSet {
 	targetIDs := NewTargetIDSet()
 
-	for pattern, targetIDSet := range rules {
-		if wildcard.MatchSimple(pattern, objectName) {
-			targetIDs = targetIDs.Union(targetIDSet)
+	for _, rt := range rules {
+		if rt.Match(obj) {
+			targetIDs = targetIDs.Union(rt.TargetIDs)
 		}
 	}
 
@@ -81,8 +109,8 @@ func (rules Rules) Match(objectName string) TargetIDSet {
 func (rules Rules) Clone() Rules {
 	rulesCopy := make(Rules)
 
-	for pattern, targetIDSet := range rules {
-		rulesCopy[pattern] = targetIDSet.Clone()
+	for key, rt := range rules {
+		rulesCopy[key] = RuleTargets{Rule: rt.Rule, TargetIDs: rt.TargetIDs.Clone()}
 	}
 
 	return rulesCopy
@@ -92,8 +120,8 @@ func (rules Rules) Clone() Rules {
 func (rules Rules) Union(rules2 Rules) Rules {
 	nrules := rules.Clone()
 
-	for pattern, targetIDSet := range rules2 {
-		nrules[pattern] = nrules[pattern].Union(targetIDSet)
+	for _, rt := range rules2 {
+		nrules.add(rt.Rule, rt.TargetIDs)
 	}
 
 	return nrules
@@ -103,9 +131,9 @@ func (rules Rules) Union(rules2 Rules) Rules {
 func (rules Rules) Difference(rules2 Rules) Rules {
 	nrules := make(Rules)
 
-	for pattern, targetIDSet := range rules {
-		if nv := targetIDSet.Difference(rules2[pattern]); len(nv) > 0 {
-			nrules[pattern] = nv
+	for key, rt := range rules {
+		if nv := rt.TargetIDs.Difference(rules2[key].TargetIDs); len(nv) > 0 {
+			nrules[key] = RuleTargets{Rule: rt.Rule, TargetIDs: nv}
 		}
 	}
 
@@ -22,8 +22,13 @@ type RulesMap map[Name]Rules
 
 // add - adds event names, prefixes, suffixes and target ID to rules map.
 func (rulesMap RulesMap) add(eventNames []Name, pattern string, targetID TargetID) {
+	rulesMap.addRule(eventNames, Rule{Pattern: pattern}, targetID)
+}
+
+// addRule - adds event names, rule and target ID to rules map.
+func (rulesMap RulesMap) addRule(eventNames []Name, rule Rule, targetID TargetID) {
 	rules := make(Rules)
-	rules.Add(pattern, targetID)
+	rules.AddRule(rule, targetID)
 
 	for _, eventName := range eventNames {
 		for _, name := range eventName.Expand() {
@@ -71,6 +76,12 @@ func (rulesMap RulesMap) Match(eventName Name, objectName string) TargetIDSet {
 	return rulesMap[eventName].Match(objectName)
 }
 
+// MatchObject - returns TargetIDSet matching object properties and event
+// name in rules map.
+func (rulesMap RulesMap) MatchObject(eventName Name, obj ObjectProps) TargetIDSet {
+	return rulesMap[eventName].MatchObject(obj)
+}
+
 // NewRulesMap - creates new rules map with given values.
 func NewRulesMap(eventNames []Name, pattern string, targetID TargetID) RulesMap {
 	// If pattern is empty, add '*' wildcard to match all.
@@ -51,14 +51,18 @@ type AMQPArgs struct {
 	PublisherConfirms bool     `json:"publisherConfirms"`
 	QueueDir          string   `json:"queueDir"`
 	QueueLimit        uint64   `json:"queueLimit"`
+	QueueSizeLimit    uint64   `json:"queueSizeLimit"`
+	QueueCompress     bool     `json:"queueCompress"`
 }
 
 //lint:file-ignore ST1003 We cannot change these exported names.
 
 // AMQP input constants.
 const (
-	AmqpQueueDir   = "queue_dir"
-	AmqpQueueLimit = "queue_limit"
+	AmqpQueueDir         = "queue_dir"
+	AmqpQueueLimit       = "queue_limit"
+	AmqpQueueSizeLimit   = "queue_size_limit"
+	AmqpQueueCompression = "queue_compression"
 
 	AmqpURL               = "url"
 	AmqpExchange          = "exchange"
@@ -90,6 +94,8 @@ const (
 	EnvAMQPPublisherConfirms = "MINIO_NOTIFY_AMQP_PUBLISHING_CONFIRMS"
 	EnvAMQPQueueDir          = "MINIO_NOTIFY_AMQP_QUEUE_DIR"
 	EnvAMQPQueueLimit        = "MINIO_NOTIFY_AMQP_QUEUE_LIMIT"
+	EnvAMQPQueueSizeLimit    = "MINIO_NOTIFY_AMQP_QUEUE_SIZE_LIMIT"
+	EnvAMQPQueueCompression  = "MINIO_NOTIFY_AMQP_QUEUE_COMPRESSION"
 )
 
 // Validate AMQP arguments
@@ -345,8 +351,8 @@ func NewAMQPTarget(id string, args AMQPArgs, loggerOnce logger.LogOnce) (*AMQPTa
 	var store Store
 	if args.QueueDir != "" {
 		queueDir := filepath.Join(args.QueueDir, storePrefix+"-amqp-"+id)
-		store = NewQueueStore(queueDir, args.QueueLimit)
-		if err := store.Open(); err != nil {
+		var err error
+		if store, err = OpenQueueStore(queueDir, args.QueueLimit, args.QueueSizeLimit, args.QueueCompress); err != nil {
 			return nil, fmt.Errorf("unable to initialize the queue store of AMQP `%s`: %w", id, err)
 		}
 	}
@@ -0,0 +1,166 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package target
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/infobsmi/b33s/internal/event"
+	"github.com/infobsmi/b33s/internal/logger"
+)
+
+// defaultBatchMaxWait is the time an incomplete batch of events waits for
+// more events, if not configured.
+const defaultBatchMaxWait = time.Second
+
+// batchTarget is a target which sends the events of its store in batches.
+type batchTarget interface {
+	event.Target
+
+	// batchArgs returns the maximum number of events of a batch
+	// and the maximum time a batch waits for more events.
+	batchArgs() (size int, maxWait time.Duration)
+
+	// SendBatch reads the events from the store, sends them and
+	// removes them from the store.
+	SendBatch(eventKeys []string) error
+}
+
+// validateBatchArgs returns an error if batching is configured without a
+// queue store, the events of a batch are collected in the store.
+func validateBatchArgs(batchSize uint32, queueDir string) error {
+	if batchSize > 1 && queueDir == "" {
+		return errors.New("batch size requires queueDir")
+	}
+	return nil
+}
+
+// getBatch returns the events of the keys from the store. Events which do
+// not exist anymore have been sent already and are skipped.
+func getBatch(store Store, eventKeys []string) (keys []string, events []event.Event, err error) {
+	for _, key := range eventKeys {
+		eventData, err := store.Get(key)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+		events = append(events, eventData)
+	}
+	return keys, events, nil
+}
+
+// delBatch removes the events of a batch which has been sent from the store.
+func delBatch(store Store, keys []string) error {
+	for _, key := range keys {
+		if err := store.Del(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendEventBatches - Reads events from the store and re-plays them in batches.
+func sendEventBatches(target batchTarget, eventKeyCh <-chan string, doneCh <-chan struct{}, loggerOnce logger.LogOnce) {
+	retryTicker := time.NewTicker(retryInterval)
+	defer retryTicker.Stop()
+
+	size, maxWait := target.batchArgs()
+	if maxWait <= 0 {
+		maxWait = defaultBatchMaxWait
+	}
+	keys := make([]string, 0, size)
+	// The store is listed again while a batch is waiting, skip the
+	// keys which are already part of the batch.
+	pending := make(map[string]struct{}, size)
+	timer := time.NewTimer(maxWait)
+	timer.Stop()
+	defer timer.Stop()
+
+	send := func() bool {
+		for {
+			start := time.Now()
+			err := target.SendBatch(keys)
+			event.RecordStoreDelivery(target.ID(), len(keys), err, time.Since(start))
+			if err == nil {
+				break
+			}
+
+			if err != errNotConnected && !IsConnResetErr(err) {
+				loggerOnce(context.Background(),
+					fmt.Errorf("target.SendBatch() failed with '%w'", err),
+					target.ID().String())
+			}
+
+			// Retrying after 3secs back-off
+
+			select {
+			case <-retryTicker.C:
+			case <-doneCh:
+				return false
+			}
+		}
+		keys = keys[:0]
+		for key := range pending {
+			delete(pending, key)
+		}
+		return true
+	}
+
+	for {
+		select {
+		case eventKey, ok := <-eventKeyCh:
+			if !ok {
+				// closed channel.
+				return
+			}
+			if _, ok = pending[eventKey]; ok {
+				continue
+			}
+			pending[eventKey] = struct{}{}
+			keys = append(keys, eventKey)
+			if len(keys) == 1 {
+				timer.Reset(maxWait)
+			}
+			if len(keys) < size {
+				continue
+			}
+			if !timer.Stop() {
+				<-timer.C
+			}
+			if !send() {
+				return
+			}
+		case <-timer.C:
+			if len(keys) == 0 {
+				continue
+			}
+			if !send() {
+				return
+			}
+		case <-doneCh:
+			return
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package target
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infobsmi/b33s/internal/event"
+)
+
+type testBatchTarget struct {
+	event.Target
+
+	size    int
+	maxWait time.Duration
+
+	mu      sync.Mutex
+	batches [][]string
+	sent    chan struct{}
+}
+
+func (target *testBatchTarget) ID() event.TargetID {
+	return event.TargetID{ID: "1", Name: "test"}
+}
+
+func (target *testBatchTarget) batchArgs() (int, time.Duration) {
+	return target.size, target.maxWait
+}
+
+func (target *testBatchTarget) SendBatch(eventKeys []string) error {
+	target.mu.Lock()
+	target.batches = append(target.batches, append([]string{}, eventKeys...))
+	target.mu.Unlock()
+	target.sent <- struct{}{}
+	return nil
+}
+
+func TestSendEventBatches(t *testing.T) {
+	target := &testBatchTarget{size: 3, maxWait: 50 * time.Millisecond, sent: make(chan struct{}, 10)}
+	eventKeyCh := make(chan string)
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	go sendEventBatches(target, eventKeyCh, doneCh, func(ctx context.Context, err error, id string, errKind ...interface{}) {
+		t.Error(err)
+	})
+
+	// A full batch is sent at once, keys listed twice are skipped.
+	for _, key := range []string{"a", "b", "a", "c", "d"} {
+		eventKeyCh <- key
+	}
+	<-target.sent
+	// An incomplete batch is sent after the maximum wait.
+	start := time.Now()
+	<-target.sent
+	if time.Since(start) < 25*time.Millisecond {
+		t.Errorf("incomplete batch sent before the maximum wait")
+	}
+
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	want := [][]string{{"a", "b", "c"}, {"d"}}
+	if !reflect.DeepEqual(target.batches, want) {
+		t.Errorf("want batches %v, got %v", want, target.batches)
+	}
+}
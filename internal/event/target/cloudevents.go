@@ -0,0 +1,123 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package target
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/infobsmi/b33s/internal/event"
+)
+
+// Payload formats of the webhook and Kafka targets.
+const (
+	// PayloadFormatDefault sends the event log, i.e. the event name,
+	// the object key and the S3 event record.
+	PayloadFormatDefault = "default"
+	// PayloadFormatCloudEventsStructured sends CloudEvents 1.0 in
+	// structured content mode, i.e. the event log is the data of a
+	// JSON formatted CloudEvent.
+	PayloadFormatCloudEventsStructured = "cloudevents_structured"
+	// PayloadFormatCloudEventsBinary sends CloudEvents 1.0 in binary
+	// content mode, i.e. the event log with the CloudEvent attributes
+	// in HTTP or Kafka headers.
+	PayloadFormatCloudEventsBinary = "cloudevents_binary"
+)
+
+// CloudEvents content types.
+const (
+	cloudEventsContentType      = "application/cloudevents+json"
+	cloudEventsBatchContentType = "application/cloudevents-batch+json"
+)
+
+// validatePayloadFormat returns an error for unknown payload formats.
+func validatePayloadFormat(format string) error {
+	switch format {
+	case "", PayloadFormatDefault, PayloadFormatCloudEventsStructured, PayloadFormatCloudEventsBinary:
+		return nil
+	}
+	return fmt.Errorf("unknown payload format '%s', expected '%s', '%s' or '%s'", format,
+		PayloadFormatDefault, PayloadFormatCloudEventsStructured, PayloadFormatCloudEventsBinary)
+}
+
+// cloudEvent is a CloudEvent 1.0 in the JSON event format.
+type cloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            string          `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// eventLog returns the key and the JSON encoded event log of an event.
+func eventLog(eventData event.Event) (key string, data []byte, err error) {
+	objectName, err := url.QueryUnescape(eventData.S3.Object.Key)
+	if err != nil {
+		return "", nil, err
+	}
+	key = eventData.S3.Bucket.Name + "/" + objectName
+
+	data, err = json.Marshal(event.Log{EventName: eventData.EventName, Key: key, Records: []event.Event{eventData}})
+	return key, data, err
+}
+
+// newCloudEvent returns the CloudEvent of an event with the JSON encoded
+// event log as data. The bucket ARN is the source and the object name the
+// subject of the CloudEvent.
+func newCloudEvent(eventData event.Event, key string, data []byte) cloudEvent {
+	id := eventData.S3.Object.Sequencer
+	if requestID := eventData.ResponseElements["x-amz-request-id"]; requestID != "" {
+		id = requestID + "-" + id
+	}
+	source := eventData.S3.Bucket.ARN
+	if source == "" {
+		source = eventData.EventSource
+	}
+	return cloudEvent{
+		SpecVersion:     "1.0",
+		ID:              id,
+		Source:          source,
+		Type:            eventData.EventName.String(),
+		Subject:         key[len(eventData.S3.Bucket.Name)+1:],
+		Time:            eventData.EventTime,
+		DataContentType: "application/json",
+		Data:            data,
+	}
+}
+
+// attributes returns the context attributes of the CloudEvent for the
+// binary content mode, the data content type excluded.
+func (ce cloudEvent) attributes() map[string]string {
+	attrs := map[string]string{
+		"specversion": ce.SpecVersion,
+		"id":          ce.ID,
+		"source":      ce.Source,
+		"type":        ce.Type,
+	}
+	if ce.Subject != "" {
+		attrs["subject"] = ce.Subject
+	}
+	if ce.Time != "" {
+		attrs["time"] = ce.Time
+	}
+	return attrs
+}
@@ -42,22 +42,26 @@ import (
 
 // Elastic constants
 const (
-	ElasticFormat     = "format"
-	ElasticURL        = "url"
-	ElasticIndex      = "index"
-	ElasticQueueDir   = "queue_dir"
-	ElasticQueueLimit = "queue_limit"
-	ElasticUsername   = "username"
-	ElasticPassword   = "password"
-
-	EnvElasticEnable     = "MINIO_NOTIFY_ELASTICSEARCH_ENABLE"
-	EnvElasticFormat     = "MINIO_NOTIFY_ELASTICSEARCH_FORMAT"
-	EnvElasticURL        = "MINIO_NOTIFY_ELASTICSEARCH_URL"
-	EnvElasticIndex      = "MINIO_NOTIFY_ELASTICSEARCH_INDEX"
-	EnvElasticQueueDir   = "MINIO_NOTIFY_ELASTICSEARCH_QUEUE_DIR"
-	EnvElasticQueueLimit = "MINIO_NOTIFY_ELASTICSEARCH_QUEUE_LIMIT"
-	EnvElasticUsername   = "MINIO_NOTIFY_ELASTICSEARCH_USERNAME"
-	EnvElasticPassword   = "MINIO_NOTIFY_ELASTICSEARCH_PASSWORD"
+	ElasticFormat           = "format"
+	ElasticURL              = "url"
+	ElasticIndex            = "index"
+	ElasticQueueDir         = "queue_dir"
+	ElasticQueueLimit       = "queue_limit"
+	ElasticQueueSizeLimit   = "queue_size_limit"
+	ElasticQueueCompression = "queue_compression"
+	ElasticUsername         = "username"
+	ElasticPassword         = "password"
+
+	EnvElasticEnable           = "MINIO_NOTIFY_ELASTICSEARCH_ENABLE"
+	EnvElasticFormat           = "MINIO_NOTIFY_ELASTICSEARCH_FORMAT"
+	EnvElasticURL              = "MINIO_NOTIFY_ELASTICSEARCH_URL"
+	EnvElasticIndex            = "MINIO_NOTIFY_ELASTICSEARCH_INDEX"
+	EnvElasticQueueDir         = "MINIO_NOTIFY_ELASTICSEARCH_QUEUE_DIR"
+	EnvElasticQueueLimit       = "MINIO_NOTIFY_ELASTICSEARCH_QUEUE_LIMIT"
+	EnvElasticQueueSizeLimit   = "MINIO_NOTIFY_ELASTICSEARCH_QUEUE_SIZE_LIMIT"
+	EnvElasticQueueCompression = "MINIO_NOTIFY_ELASTICSEARCH_QUEUE_COMPRESSION"
+	EnvElasticUsername         = "MINIO_NOTIFY_ELASTICSEARCH_USERNAME"
+	EnvElasticPassword         = "MINIO_NOTIFY_ELASTICSEARCH_PASSWORD"
 )
 
 // ESSupportStatus is a typed string representing the support status for
@@ -114,15 +118,17 @@ type esClient interface {
 
 // ElasticsearchArgs - Elasticsearch target arguments.
 type ElasticsearchArgs struct {
-	Enable     bool            `json:"enable"`
-	Format     string          `json:"format"`
-	URL        xnet.URL        `json:"url"`
-	Index      string          `json:"index"`
-	QueueDir   string          `json:"queueDir"`
-	QueueLimit uint64          `json:"queueLimit"`
-	Transport  *http.Transport `json:"-"`
-	Username   string          `json:"username"`
-	Password   string          `json:"password"`
+	Enable         bool            `json:"enable"`
+	Format         string          `json:"format"`
+	URL            xnet.URL        `json:"url"`
+	Index          string          `json:"index"`
+	QueueDir       string          `json:"queueDir"`
+	QueueLimit     uint64          `json:"queueLimit"`
+	QueueSizeLimit uint64          `json:"queueSizeLimit"`
+	QueueCompress  bool            `json:"queueCompress"`
+	Transport      *http.Transport `json:"-"`
+	Username       string          `json:"username"`
+	Password       string          `json:"password"`
 }
 
 // Validate ElasticsearchArgs fields
@@ -362,8 +368,8 @@ func NewElasticsearchTarget(id string, args ElasticsearchArgs, loggerOnce logger
 	var store Store
 	if args.QueueDir != "" {
 		queueDir := filepath.Join(args.QueueDir, storePrefix+"-elasticsearch-"+id)
-		store = NewQueueStore(queueDir, args.QueueLimit)
-		if err := store.Open(); err != nil {
+		var err error
+		if store, err = OpenQueueStore(queueDir, args.QueueLimit, args.QueueSizeLimit, args.QueueCompress); err != nil {
 			return nil, fmt.Errorf("unable to initialize the queue store of Elasticsearch `%s`: %w", id, err)
 		}
 	}
@@ -25,9 +25,9 @@ import (
 	"errors"
 	"fmt"
 	"net"
-	"net/url"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/infobsmi/b33s/internal/event"
 	"github.com/infobsmi/b33s/internal/logger"
@@ -39,47 +39,64 @@ import (
 
 // Kafka input constants
 const (
-	KafkaBrokers       = "brokers"
-	KafkaTopic         = "topic"
-	KafkaQueueDir      = "queue_dir"
-	KafkaQueueLimit    = "queue_limit"
-	KafkaTLS           = "tls"
-	KafkaTLSSkipVerify = "tls_skip_verify"
-	KafkaTLSClientAuth = "tls_client_auth"
-	KafkaSASL          = "sasl"
-	KafkaSASLUsername  = "sasl_username"
-	KafkaSASLPassword  = "sasl_password"
-	KafkaSASLMechanism = "sasl_mechanism"
-	KafkaClientTLSCert = "client_tls_cert"
-	KafkaClientTLSKey  = "client_tls_key"
-	KafkaVersion       = "version"
-
-	EnvKafkaEnable        = "MINIO_NOTIFY_KAFKA_ENABLE"
-	EnvKafkaBrokers       = "MINIO_NOTIFY_KAFKA_BROKERS"
-	EnvKafkaTopic         = "MINIO_NOTIFY_KAFKA_TOPIC"
-	EnvKafkaQueueDir      = "MINIO_NOTIFY_KAFKA_QUEUE_DIR"
-	EnvKafkaQueueLimit    = "MINIO_NOTIFY_KAFKA_QUEUE_LIMIT"
-	EnvKafkaTLS           = "MINIO_NOTIFY_KAFKA_TLS"
-	EnvKafkaTLSSkipVerify = "MINIO_NOTIFY_KAFKA_TLS_SKIP_VERIFY"
-	EnvKafkaTLSClientAuth = "MINIO_NOTIFY_KAFKA_TLS_CLIENT_AUTH"
-	EnvKafkaSASLEnable    = "MINIO_NOTIFY_KAFKA_SASL"
-	EnvKafkaSASLUsername  = "MINIO_NOTIFY_KAFKA_SASL_USERNAME"
-	EnvKafkaSASLPassword  = "MINIO_NOTIFY_KAFKA_SASL_PASSWORD"
-	EnvKafkaSASLMechanism = "MINIO_NOTIFY_KAFKA_SASL_MECHANISM"
-	EnvKafkaClientTLSCert = "MINIO_NOTIFY_KAFKA_CLIENT_TLS_CERT"
-	EnvKafkaClientTLSKey  = "MINIO_NOTIFY_KAFKA_CLIENT_TLS_KEY"
-	EnvKafkaVersion       = "MINIO_NOTIFY_KAFKA_VERSION"
+	KafkaBrokers          = "brokers"
+	KafkaTopic            = "topic"
+	KafkaQueueDir         = "queue_dir"
+	KafkaQueueLimit       = "queue_limit"
+	KafkaQueueSizeLimit   = "queue_size_limit"
+	KafkaQueueCompression = "queue_compression"
+	KafkaTLS              = "tls"
+	KafkaTLSSkipVerify    = "tls_skip_verify"
+	KafkaTLSClientAuth    = "tls_client_auth"
+	KafkaSASL             = "sasl"
+	KafkaSASLUsername     = "sasl_username"
+	KafkaSASLPassword     = "sasl_password"
+	KafkaSASLMechanism    = "sasl_mechanism"
+	KafkaClientTLSCert    = "client_tls_cert"
+	KafkaClientTLSKey     = "client_tls_key"
+	KafkaVersion          = "version"
+	KafkaBatchSize        = "batch_size"
+	KafkaBatchMaxWait     = "batch_max_wait"
+	KafkaPayloadFormat    = "payload_format"
+
+	EnvKafkaEnable           = "MINIO_NOTIFY_KAFKA_ENABLE"
+	EnvKafkaBrokers          = "MINIO_NOTIFY_KAFKA_BROKERS"
+	EnvKafkaTopic            = "MINIO_NOTIFY_KAFKA_TOPIC"
+	EnvKafkaQueueDir         = "MINIO_NOTIFY_KAFKA_QUEUE_DIR"
+	EnvKafkaQueueLimit       = "MINIO_NOTIFY_KAFKA_QUEUE_LIMIT"
+	EnvKafkaQueueSizeLimit   = "MINIO_NOTIFY_KAFKA_QUEUE_SIZE_LIMIT"
+	EnvKafkaQueueCompression = "MINIO_NOTIFY_KAFKA_QUEUE_COMPRESSION"
+	EnvKafkaTLS              = "MINIO_NOTIFY_KAFKA_TLS"
+	EnvKafkaTLSSkipVerify    = "MINIO_NOTIFY_KAFKA_TLS_SKIP_VERIFY"
+	EnvKafkaTLSClientAuth    = "MINIO_NOTIFY_KAFKA_TLS_CLIENT_AUTH"
+	EnvKafkaSASLEnable       = "MINIO_NOTIFY_KAFKA_SASL"
+	EnvKafkaSASLUsername     = "MINIO_NOTIFY_KAFKA_SASL_USERNAME"
+	EnvKafkaSASLPassword     = "MINIO_NOTIFY_KAFKA_SASL_PASSWORD"
+	EnvKafkaSASLMechanism    = "MINIO_NOTIFY_KAFKA_SASL_MECHANISM"
+	EnvKafkaClientTLSCert    = "MINIO_NOTIFY_KAFKA_CLIENT_TLS_CERT"
+	EnvKafkaClientTLSKey     = "MINIO_NOTIFY_KAFKA_CLIENT_TLS_KEY"
+	EnvKafkaVersion          = "MINIO_NOTIFY_KAFKA_VERSION"
+	EnvKafkaBatchSize        = "MINIO_NOTIFY_KAFKA_BATCH_SIZE"
+	EnvKafkaBatchMaxWait     = "MINIO_NOTIFY_KAFKA_BATCH_MAX_WAIT"
+	EnvKafkaPayloadFormat    = "MINIO_NOTIFY_KAFKA_PAYLOAD_FORMAT"
 )
 
 // KafkaArgs - Kafka target arguments.
 type KafkaArgs struct {
-	Enable     bool        `json:"enable"`
-	Brokers    []xnet.Host `json:"brokers"`
-	Topic      string      `json:"topic"`
-	QueueDir   string      `json:"queueDir"`
-	QueueLimit uint64      `json:"queueLimit"`
-	Version    string      `json:"version"`
-	TLS        struct {
+	Enable         bool        `json:"enable"`
+	Brokers        []xnet.Host `json:"brokers"`
+	Topic          string      `json:"topic"`
+	QueueDir       string      `json:"queueDir"`
+	QueueLimit     uint64      `json:"queueLimit"`
+	QueueSizeLimit uint64      `json:"queueSizeLimit"`
+	QueueCompress  bool        `json:"queueCompress"`
+	Version        string      `json:"version"`
+
+	BatchSize     uint32        `json:"batchSize"`
+	BatchMaxWait  time.Duration `json:"batchMaxWait"`
+	PayloadFormat string        `json:"payloadFormat"`
+
+	TLS struct {
 		Enable        bool               `json:"enable"`
 		RootCAs       *x509.CertPool     `json:"-"`
 		SkipVerify    bool               `json:"skipVerify"`
@@ -113,10 +130,23 @@ func (k KafkaArgs) Validate() error {
 			return errors.New("queueDir path should be absolute")
 		}
 	}
+	if err := validatePayloadFormat(k.PayloadFormat); err != nil {
+		return err
+	}
+	if err := validateBatchArgs(k.BatchSize, k.QueueDir); err != nil {
+		return err
+	}
 	if k.Version != "" {
-		if _, err := sarama.ParseKafkaVersion(k.Version); err != nil {
+		kafkaVersion, err := sarama.ParseKafkaVersion(k.Version)
+		if err != nil {
 			return err
 		}
+		// CloudEvents attributes are sent in record headers.
+		if k.PayloadFormat == PayloadFormatCloudEventsStructured || k.PayloadFormat == PayloadFormatCloudEventsBinary {
+			if !kafkaVersion.IsAtLeast(sarama.V0_11_0_0) {
+				return errors.New("CloudEvents payload format requires Kafka version 0.11.0 or later")
+			}
+		}
 	}
 	return nil
 }
@@ -180,26 +210,41 @@ func (target *KafkaTarget) send(eventData event.Event) error {
 	if target.producer == nil {
 		return errNotConnected
 	}
-	objectName, err := url.QueryUnescape(eventData.S3.Object.Key)
+	msg, err := target.message(eventData)
 	if err != nil {
 		return err
 	}
-	key := eventData.S3.Bucket.Name + "/" + objectName
+	_, _, err = target.producer.SendMessage(msg)
+
+	return err
+}
 
-	data, err := json.Marshal(event.Log{EventName: eventData.EventName, Key: key, Records: []event.Event{eventData}})
+// message - returns the Kafka message of an event.
+func (target *KafkaTarget) message(eventData event.Event) (*sarama.ProducerMessage, error) {
+	key, data, err := eventLog(eventData)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	msg := sarama.ProducerMessage{
 		Topic: target.args.Topic,
 		Key:   sarama.StringEncoder(key),
-		Value: sarama.ByteEncoder(data),
 	}
-
-	_, _, err = target.producer.SendMessage(&msg)
-
-	return err
+	switch target.args.PayloadFormat {
+	case PayloadFormatCloudEventsStructured:
+		data, err = json.Marshal(newCloudEvent(eventData, key, data))
+		if err != nil {
+			return nil, err
+		}
+		msg.Headers = []sarama.RecordHeader{{Key: []byte("content-type"), Value: []byte(cloudEventsContentType)}}
+	case PayloadFormatCloudEventsBinary:
+		msg.Headers = []sarama.RecordHeader{{Key: []byte("content-type"), Value: []byte("application/json")}}
+		for name, value := range newCloudEvent(eventData, key, data).attributes() {
+			msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte("ce_" + name), Value: []byte(value)})
+		}
+	}
+	msg.Value = sarama.ByteEncoder(data)
+	return &msg, nil
 }
 
 // Send - reads an event from store and sends it to Kafka.
@@ -209,25 +254,10 @@ func (target *KafkaTarget) Send(eventKey string) error {
 	}
 
 	var err error
-	_, err = target.isActive()
-	if err != nil {
+	if err = target.connect(); err != nil {
 		return err
 	}
 
-	if target.producer == nil {
-		brokers := []string{}
-		for _, broker := range target.args.Brokers {
-			brokers = append(brokers, broker.String())
-		}
-		target.producer, err = sarama.NewSyncProducer(brokers, target.config)
-		if err != nil {
-			if err != sarama.ErrOutOfBrokers {
-				return err
-			}
-			return errNotConnected
-		}
-	}
-
 	eventData, eErr := target.store.Get(eventKey)
 	if eErr != nil {
 		// The last event key in a successful batch will be sent in the channel atmost once by the replayEvents()
@@ -251,6 +281,75 @@ func (target *KafkaTarget) Send(eventKey string) error {
 	return target.store.Del(eventKey)
 }
 
+// SendBatch - reads a batch of events from store and sends it to Kafka.
+func (target *KafkaTarget) SendBatch(eventKeys []string) error {
+	if err := target.init(); err != nil {
+		return err
+	}
+
+	if err := target.connect(); err != nil {
+		return err
+	}
+
+	keys, events, err := getBatch(target.store, eventKeys)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	msgs := make([]*sarama.ProducerMessage, 0, len(events))
+	for _, eventData := range events {
+		msg, err := target.message(eventData)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, msg)
+	}
+	if err = target.producer.SendMessages(msgs); err != nil {
+		var errs sarama.ProducerErrors
+		if errors.As(err, &errs) && len(errs) > 0 {
+			err = errs[0].Err
+		}
+		// Sarama opens the ciruit breaker after 3 consecutive connection failures.
+		if err == sarama.ErrLeaderNotAvailable || err.Error() == "circuit breaker is open" {
+			return errNotConnected
+		}
+		return err
+	}
+
+	// Delete the events from store.
+	return delBatch(target.store, keys)
+}
+
+func (target *KafkaTarget) batchArgs() (int, time.Duration) {
+	return int(target.args.BatchSize), target.args.BatchMaxWait
+}
+
+// connect - checks the brokers and creates the producer if necessary.
+func (target *KafkaTarget) connect() error {
+	if _, err := target.isActive(); err != nil {
+		return err
+	}
+
+	if target.producer == nil {
+		brokers := []string{}
+		for _, broker := range target.args.Brokers {
+			brokers = append(brokers, broker.String())
+		}
+		producer, err := sarama.NewSyncProducer(brokers, target.config)
+		if err != nil {
+			if err != sarama.ErrOutOfBrokers {
+				return err
+			}
+			return errNotConnected
+		}
+		target.producer = producer
+	}
+	return nil
+}
+
 // Close - closes underneath kafka connection.
 func (target *KafkaTarget) Close() error {
 	close(target.quitCh)
@@ -342,8 +441,8 @@ func NewKafkaTarget(id string, args KafkaArgs, loggerOnce logger.LogOnce) (*Kafk
 	var store Store
 	if args.QueueDir != "" {
 		queueDir := filepath.Join(args.QueueDir, storePrefix+"-kafka-"+id)
-		store = NewQueueStore(queueDir, args.QueueLimit)
-		if err := store.Open(); err != nil {
+		var err error
+		if store, err = OpenQueueStore(queueDir, args.QueueLimit, args.QueueSizeLimit, args.QueueCompress); err != nil {
 			return nil, fmt.Errorf("unable to initialize the queue store of Kafka `%s`: %w", id, err)
 		}
 	}
@@ -50,6 +50,8 @@ const (
 	MqttKeepAliveInterval = "keep_alive_interval"
 	MqttQueueDir          = "queue_dir"
 	MqttQueueLimit        = "queue_limit"
+	MqttQueueSizeLimit    = "queue_size_limit"
+	MqttQueueCompression  = "queue_compression"
 
 	EnvMQTTEnable            = "MINIO_NOTIFY_MQTT_ENABLE"
 	EnvMQTTBroker            = "MINIO_NOTIFY_MQTT_BROKER"
@@ -61,6 +63,8 @@ const (
 	EnvMQTTKeepAliveInterval = "MINIO_NOTIFY_MQTT_KEEP_ALIVE_INTERVAL"
 	EnvMQTTQueueDir          = "MINIO_NOTIFY_MQTT_QUEUE_DIR"
 	EnvMQTTQueueLimit        = "MINIO_NOTIFY_MQTT_QUEUE_LIMIT"
+	EnvMQTTQueueSizeLimit    = "MINIO_NOTIFY_MQTT_QUEUE_SIZE_LIMIT"
+	EnvMQTTQueueCompression  = "MINIO_NOTIFY_MQTT_QUEUE_COMPRESSION"
 )
 
 // MQTTArgs - MQTT target arguments.
@@ -76,6 +80,8 @@ type MQTTArgs struct {
 	RootCAs              *x509.CertPool `json:"-"`
 	QueueDir             string         `json:"queueDir"`
 	QueueLimit           uint64         `json:"queueLimit"`
+	QueueSizeLimit       uint64         `json:"queueSizeLimit"`
+	QueueCompress        bool           `json:"queueCompress"`
 }
 
 // Validate MQTTArgs fields
@@ -277,8 +283,8 @@ func NewMQTTTarget(id string, args MQTTArgs, loggerOnce logger.LogOnce) (*MQTTTa
 	var store Store
 	if args.QueueDir != "" {
 		queueDir := filepath.Join(args.QueueDir, storePrefix+"-mqtt-"+id)
-		store = NewQueueStore(queueDir, args.QueueLimit)
-		if err := store.Open(); err != nil {
+		var err error
+		if store, err = OpenQueueStore(queueDir, args.QueueLimit, args.QueueSizeLimit, args.QueueCompress); err != nil {
 			return nil, fmt.Errorf("unable to initialize the queue store of MQTT `%s`: %w", id, err)
 		}
 	}
@@ -63,6 +63,8 @@ const (
 	MySQLPassword           = "password"
 	MySQLDatabase           = "database"
 	MySQLQueueLimit         = "queue_limit"
+	MySQLQueueSizeLimit     = "queue_size_limit"
+	MySQLQueueCompression   = "queue_compression"
 	MySQLQueueDir           = "queue_dir"
 	MySQLMaxOpenConnections = "max_open_connections"
 
@@ -76,6 +78,8 @@ const (
 	EnvMySQLPassword           = "MINIO_NOTIFY_MYSQL_PASSWORD"
 	EnvMySQLDatabase           = "MINIO_NOTIFY_MYSQL_DATABASE"
 	EnvMySQLQueueLimit         = "MINIO_NOTIFY_MYSQL_QUEUE_LIMIT"
+	EnvMySQLQueueSizeLimit     = "MINIO_NOTIFY_MYSQL_QUEUE_SIZE_LIMIT"
+	EnvMySQLQueueCompression   = "MINIO_NOTIFY_MYSQL_QUEUE_COMPRESSION"
 	EnvMySQLQueueDir           = "MINIO_NOTIFY_MYSQL_QUEUE_DIR"
 	EnvMySQLMaxOpenConnections = "MINIO_NOTIFY_MYSQL_MAX_OPEN_CONNECTIONS"
 )
@@ -93,6 +97,8 @@ type MySQLArgs struct {
 	Database           string   `json:"database"`
 	QueueDir           string   `json:"queueDir"`
 	QueueLimit         uint64   `json:"queueLimit"`
+	QueueSizeLimit     uint64   `json:"queueSizeLimit"`
+	QueueCompress      bool     `json:"queueCompress"`
 	MaxOpenConnections int      `json:"maxOpenConnections"`
 }
 
@@ -395,8 +401,8 @@ func NewMySQLTarget(id string, args MySQLArgs, loggerOnce logger.LogOnce) (*MySQ
 	var store Store
 	if args.QueueDir != "" {
 		queueDir := filepath.Join(args.QueueDir, storePrefix+"-mysql-"+id)
-		store = NewQueueStore(queueDir, args.QueueLimit)
-		if err := store.Open(); err != nil {
+		var err error
+		if store, err = OpenQueueStore(queueDir, args.QueueLimit, args.QueueSizeLimit, args.QueueCompress); err != nil {
 			return nil, fmt.Errorf("unable to initialize the queue store of MySQL `%s`: %w", id, err)
 		}
 	}
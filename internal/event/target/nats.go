@@ -37,19 +37,21 @@ import (
 
 // NATS related constants
 const (
-	NATSAddress       = "address"
-	NATSSubject       = "subject"
-	NATSUsername      = "username"
-	NATSPassword      = "password"
-	NATSToken         = "token"
-	NATSTLS           = "tls"
-	NATSTLSSkipVerify = "tls_skip_verify"
-	NATSPingInterval  = "ping_interval"
-	NATSQueueDir      = "queue_dir"
-	NATSQueueLimit    = "queue_limit"
-	NATSCertAuthority = "cert_authority"
-	NATSClientCert    = "client_cert"
-	NATSClientKey     = "client_key"
+	NATSAddress          = "address"
+	NATSSubject          = "subject"
+	NATSUsername         = "username"
+	NATSPassword         = "password"
+	NATSToken            = "token"
+	NATSTLS              = "tls"
+	NATSTLSSkipVerify    = "tls_skip_verify"
+	NATSPingInterval     = "ping_interval"
+	NATSQueueDir         = "queue_dir"
+	NATSQueueLimit       = "queue_limit"
+	NATSQueueSizeLimit   = "queue_size_limit"
+	NATSQueueCompression = "queue_compression"
+	NATSCertAuthority    = "cert_authority"
+	NATSClientCert       = "client_cert"
+	NATSClientKey        = "client_key"
 
 	// Streaming constants - deprecated
 	NATSStreaming                   = "streaming"
@@ -60,20 +62,22 @@ const (
 	// JetStream constants
 	NATSJetStream = "jetstream"
 
-	EnvNATSEnable        = "MINIO_NOTIFY_NATS_ENABLE"
-	EnvNATSAddress       = "MINIO_NOTIFY_NATS_ADDRESS"
-	EnvNATSSubject       = "MINIO_NOTIFY_NATS_SUBJECT"
-	EnvNATSUsername      = "MINIO_NOTIFY_NATS_USERNAME"
-	EnvNATSPassword      = "MINIO_NOTIFY_NATS_PASSWORD"
-	EnvNATSToken         = "MINIO_NOTIFY_NATS_TOKEN"
-	EnvNATSTLS           = "MINIO_NOTIFY_NATS_TLS"
-	EnvNATSTLSSkipVerify = "MINIO_NOTIFY_NATS_TLS_SKIP_VERIFY"
-	EnvNATSPingInterval  = "MINIO_NOTIFY_NATS_PING_INTERVAL"
-	EnvNATSQueueDir      = "MINIO_NOTIFY_NATS_QUEUE_DIR"
-	EnvNATSQueueLimit    = "MINIO_NOTIFY_NATS_QUEUE_LIMIT"
-	EnvNATSCertAuthority = "MINIO_NOTIFY_NATS_CERT_AUTHORITY"
-	EnvNATSClientCert    = "MINIO_NOTIFY_NATS_CLIENT_CERT"
-	EnvNATSClientKey     = "MINIO_NOTIFY_NATS_CLIENT_KEY"
+	EnvNATSEnable           = "MINIO_NOTIFY_NATS_ENABLE"
+	EnvNATSAddress          = "MINIO_NOTIFY_NATS_ADDRESS"
+	EnvNATSSubject          = "MINIO_NOTIFY_NATS_SUBJECT"
+	EnvNATSUsername         = "MINIO_NOTIFY_NATS_USERNAME"
+	EnvNATSPassword         = "MINIO_NOTIFY_NATS_PASSWORD"
+	EnvNATSToken            = "MINIO_NOTIFY_NATS_TOKEN"
+	EnvNATSTLS              = "MINIO_NOTIFY_NATS_TLS"
+	EnvNATSTLSSkipVerify    = "MINIO_NOTIFY_NATS_TLS_SKIP_VERIFY"
+	EnvNATSPingInterval     = "MINIO_NOTIFY_NATS_PING_INTERVAL"
+	EnvNATSQueueDir         = "MINIO_NOTIFY_NATS_QUEUE_DIR"
+	EnvNATSQueueLimit       = "MINIO_NOTIFY_NATS_QUEUE_LIMIT"
+	EnvNATSQueueSizeLimit   = "MINIO_NOTIFY_NATS_QUEUE_SIZE_LIMIT"
+	EnvNATSQueueCompression = "MINIO_NOTIFY_NATS_QUEUE_COMPRESSION"
+	EnvNATSCertAuthority    = "MINIO_NOTIFY_NATS_CERT_AUTHORITY"
+	EnvNATSClientCert       = "MINIO_NOTIFY_NATS_CLIENT_CERT"
+	EnvNATSClientKey        = "MINIO_NOTIFY_NATS_CLIENT_KEY"
 
 	// Streaming constants - deprecated
 	EnvNATSStreaming                   = "MINIO_NOTIFY_NATS_STREAMING"
@@ -87,22 +91,24 @@ const (
 
 // NATSArgs - NATS target arguments.
 type NATSArgs struct {
-	Enable        bool      `json:"enable"`
-	Address       xnet.Host `json:"address"`
-	Subject       string    `json:"subject"`
-	Username      string    `json:"username"`
-	Password      string    `json:"password"`
-	Token         string    `json:"token"`
-	TLS           bool      `json:"tls"`
-	TLSSkipVerify bool      `json:"tlsSkipVerify"`
-	Secure        bool      `json:"secure"`
-	CertAuthority string    `json:"certAuthority"`
-	ClientCert    string    `json:"clientCert"`
-	ClientKey     string    `json:"clientKey"`
-	PingInterval  int64     `json:"pingInterval"`
-	QueueDir      string    `json:"queueDir"`
-	QueueLimit    uint64    `json:"queueLimit"`
-	JetStream     struct {
+	Enable         bool      `json:"enable"`
+	Address        xnet.Host `json:"address"`
+	Subject        string    `json:"subject"`
+	Username       string    `json:"username"`
+	Password       string    `json:"password"`
+	Token          string    `json:"token"`
+	TLS            bool      `json:"tls"`
+	TLSSkipVerify  bool      `json:"tlsSkipVerify"`
+	Secure         bool      `json:"secure"`
+	CertAuthority  string    `json:"certAuthority"`
+	ClientCert     string    `json:"clientCert"`
+	ClientKey      string    `json:"clientKey"`
+	PingInterval   int64     `json:"pingInterval"`
+	QueueDir       string    `json:"queueDir"`
+	QueueLimit     uint64    `json:"queueLimit"`
+	QueueSizeLimit uint64    `json:"queueSizeLimit"`
+	QueueCompress  bool      `json:"queueCompress"`
+	JetStream      struct {
 		Enable bool `json:"enable"`
 	} `json:"jetStream"`
 	Streaming struct {
@@ -423,8 +429,8 @@ func NewNATSTarget(id string, args NATSArgs, loggerOnce logger.LogOnce) (*NATSTa
 	var store Store
 	if args.QueueDir != "" {
 		queueDir := filepath.Join(args.QueueDir, storePrefix+"-nats-"+id)
-		store = NewQueueStore(queueDir, args.QueueLimit)
-		if err := store.Open(); err != nil {
+		var err error
+		if store, err = OpenQueueStore(queueDir, args.QueueLimit, args.QueueSizeLimit, args.QueueCompress); err != nil {
 			return nil, fmt.Errorf("unable to initialize the queue store of NATS `%s`: %w", id, err)
 		}
 	}
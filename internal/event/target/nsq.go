@@ -36,20 +36,24 @@ import (
 
 // NSQ constants
 const (
-	NSQAddress       = "nsqd_address"
-	NSQTopic         = "topic"
-	NSQTLS           = "tls"
-	NSQTLSSkipVerify = "tls_skip_verify"
-	NSQQueueDir      = "queue_dir"
-	NSQQueueLimit    = "queue_limit"
-
-	EnvNSQEnable        = "MINIO_NOTIFY_NSQ_ENABLE"
-	EnvNSQAddress       = "MINIO_NOTIFY_NSQ_NSQD_ADDRESS"
-	EnvNSQTopic         = "MINIO_NOTIFY_NSQ_TOPIC"
-	EnvNSQTLS           = "MINIO_NOTIFY_NSQ_TLS"
-	EnvNSQTLSSkipVerify = "MINIO_NOTIFY_NSQ_TLS_SKIP_VERIFY"
-	EnvNSQQueueDir      = "MINIO_NOTIFY_NSQ_QUEUE_DIR"
-	EnvNSQQueueLimit    = "MINIO_NOTIFY_NSQ_QUEUE_LIMIT"
+	NSQAddress          = "nsqd_address"
+	NSQTopic            = "topic"
+	NSQTLS              = "tls"
+	NSQTLSSkipVerify    = "tls_skip_verify"
+	NSQQueueDir         = "queue_dir"
+	NSQQueueLimit       = "queue_limit"
+	NSQQueueSizeLimit   = "queue_size_limit"
+	NSQQueueCompression = "queue_compression"
+
+	EnvNSQEnable           = "MINIO_NOTIFY_NSQ_ENABLE"
+	EnvNSQAddress          = "MINIO_NOTIFY_NSQ_NSQD_ADDRESS"
+	EnvNSQTopic            = "MINIO_NOTIFY_NSQ_TOPIC"
+	EnvNSQTLS              = "MINIO_NOTIFY_NSQ_TLS"
+	EnvNSQTLSSkipVerify    = "MINIO_NOTIFY_NSQ_TLS_SKIP_VERIFY"
+	EnvNSQQueueDir         = "MINIO_NOTIFY_NSQ_QUEUE_DIR"
+	EnvNSQQueueLimit       = "MINIO_NOTIFY_NSQ_QUEUE_LIMIT"
+	EnvNSQQueueSizeLimit   = "MINIO_NOTIFY_NSQ_QUEUE_SIZE_LIMIT"
+	EnvNSQQueueCompression = "MINIO_NOTIFY_NSQ_QUEUE_COMPRESSION"
 )
 
 // NSQArgs - NSQ target arguments.
@@ -61,8 +65,10 @@ type NSQArgs struct {
 		Enable     bool `json:"enable"`
 		SkipVerify bool `json:"skipVerify"`
 	} `json:"tls"`
-	QueueDir   string `json:"queueDir"`
-	QueueLimit uint64 `json:"queueLimit"`
+	QueueDir       string `json:"queueDir"`
+	QueueLimit     uint64 `json:"queueLimit"`
+	QueueSizeLimit uint64 `json:"queueSizeLimit"`
+	QueueCompress  bool   `json:"queueCompress"`
 }
 
 // Validate NSQArgs fields
@@ -257,8 +263,8 @@ func NewNSQTarget(id string, args NSQArgs, loggerOnce logger.LogOnce) (*NSQTarge
 	var store Store
 	if args.QueueDir != "" {
 		queueDir := filepath.Join(args.QueueDir, storePrefix+"-nsq-"+id)
-		store = NewQueueStore(queueDir, args.QueueLimit)
-		if err := store.Open(); err != nil {
+		var err error
+		if store, err = OpenQueueStore(queueDir, args.QueueLimit, args.QueueSizeLimit, args.QueueCompress); err != nil {
 			return nil, fmt.Errorf("unable to initialize the queue store of NSQ `%s`: %w", id, err)
 		}
 	}
@@ -59,6 +59,8 @@ const (
 	PostgresDatabase           = "database"
 	PostgresQueueDir           = "queue_dir"
 	PostgresQueueLimit         = "queue_limit"
+	PostgresQueueSizeLimit     = "queue_size_limit"
+	PostgresQueueCompression   = "queue_compression"
 	PostgresMaxOpenConnections = "max_open_connections"
 
 	EnvPostgresEnable             = "MINIO_NOTIFY_POSTGRES_ENABLE"
@@ -72,6 +74,8 @@ const (
 	EnvPostgresDatabase           = "MINIO_NOTIFY_POSTGRES_DATABASE"
 	EnvPostgresQueueDir           = "MINIO_NOTIFY_POSTGRES_QUEUE_DIR"
 	EnvPostgresQueueLimit         = "MINIO_NOTIFY_POSTGRES_QUEUE_LIMIT"
+	EnvPostgresQueueSizeLimit     = "MINIO_NOTIFY_POSTGRES_QUEUE_SIZE_LIMIT"
+	EnvPostgresQueueCompression   = "MINIO_NOTIFY_POSTGRES_QUEUE_COMPRESSION"
 	EnvPostgresMaxOpenConnections = "MINIO_NOTIFY_POSTGRES_MAX_OPEN_CONNECTIONS"
 )
 
@@ -88,6 +92,8 @@ type PostgreSQLArgs struct {
 	Database           string    `json:"database"` // default: same as user
 	QueueDir           string    `json:"queueDir"`
 	QueueLimit         uint64    `json:"queueLimit"`
+	QueueSizeLimit     uint64    `json:"queueSizeLimit"`
+	QueueCompress      bool      `json:"queueCompress"`
 	MaxOpenConnections int       `json:"maxOpenConnections"`
 }
 
@@ -410,8 +416,8 @@ func NewPostgreSQLTarget(id string, args PostgreSQLArgs, loggerOnce logger.LogOn
 	var store Store
 	if args.QueueDir != "" {
 		queueDir := filepath.Join(args.QueueDir, storePrefix+"-postgresql-"+id)
-		store = NewQueueStore(queueDir, args.QueueLimit)
-		if err := store.Open(); err != nil {
+		var err error
+		if store, err = OpenQueueStore(queueDir, args.QueueLimit, args.QueueSizeLimit, args.QueueCompress); err != nil {
 			return nil, fmt.Errorf("unable to initialize the queue store of PostgreSQL `%s`: %w", id, err)
 		}
 	}
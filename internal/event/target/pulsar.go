@@ -0,0 +1,441 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package target
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/infobsmi/b33s/internal/event"
+	"github.com/infobsmi/b33s/internal/logger"
+	"github.com/minio/pkg/certs"
+	xnet "github.com/minio/pkg/net"
+)
+
+// Pulsar constants
+const (
+	PulsarURL              = "url"
+	PulsarTopic            = "topic"
+	PulsarAuthToken        = "auth_token"
+	PulsarTLSSkipVerify    = "tls_skip_verify"
+	PulsarClientTLSCert    = "client_tls_cert"
+	PulsarClientTLSKey     = "client_tls_key"
+	PulsarQueueDir         = "queue_dir"
+	PulsarQueueLimit       = "queue_limit"
+	PulsarQueueSizeLimit   = "queue_size_limit"
+	PulsarQueueCompression = "queue_compression"
+
+	EnvPulsarEnable           = "MINIO_NOTIFY_PULSAR_ENABLE"
+	EnvPulsarURL              = "MINIO_NOTIFY_PULSAR_URL"
+	EnvPulsarTopic            = "MINIO_NOTIFY_PULSAR_TOPIC"
+	EnvPulsarAuthToken        = "MINIO_NOTIFY_PULSAR_AUTH_TOKEN"
+	EnvPulsarTLSSkipVerify    = "MINIO_NOTIFY_PULSAR_TLS_SKIP_VERIFY"
+	EnvPulsarClientTLSCert    = "MINIO_NOTIFY_PULSAR_CLIENT_TLS_CERT"
+	EnvPulsarClientTLSKey     = "MINIO_NOTIFY_PULSAR_CLIENT_TLS_KEY"
+	EnvPulsarQueueDir         = "MINIO_NOTIFY_PULSAR_QUEUE_DIR"
+	EnvPulsarQueueLimit       = "MINIO_NOTIFY_PULSAR_QUEUE_LIMIT"
+	EnvPulsarQueueSizeLimit   = "MINIO_NOTIFY_PULSAR_QUEUE_SIZE_LIMIT"
+	EnvPulsarQueueCompression = "MINIO_NOTIFY_PULSAR_QUEUE_COMPRESSION"
+)
+
+// pulsarTimeout is the timeout for connecting to Pulsar and for the
+// acknowledgement of a message.
+const pulsarTimeout = 10 * time.Second
+
+// PulsarArgs - Pulsar target arguments.
+type PulsarArgs struct {
+	Enable         bool     `json:"enable"`
+	URL            xnet.URL `json:"url"`
+	Topic          string   `json:"topic"`
+	AuthToken      string   `json:"authToken"`
+	QueueDir       string   `json:"queueDir"`
+	QueueLimit     uint64   `json:"queueLimit"`
+	QueueSizeLimit uint64   `json:"queueSizeLimit"`
+	QueueCompress  bool     `json:"queueCompress"`
+	TLS            struct {
+		RootCAs       *x509.CertPool `json:"-"`
+		SkipVerify    bool           `json:"skipVerify"`
+		ClientTLSCert string         `json:"clientTLSCert"`
+		ClientTLSKey  string         `json:"clientTLSKey"`
+	} `json:"tls"`
+}
+
+// Validate PulsarArgs fields
+func (p PulsarArgs) Validate() error {
+	if !p.Enable {
+		return nil
+	}
+	if p.URL.IsEmpty() {
+		return errors.New("empty url")
+	}
+	if p.URL.Scheme != "http" && p.URL.Scheme != "https" {
+		return fmt.Errorf("unsupported url scheme '%s', expected http or https", p.URL.Scheme)
+	}
+	if _, err := pulsarTopicPath(p.Topic); err != nil {
+		return err
+	}
+	if p.QueueDir != "" {
+		if !filepath.IsAbs(p.QueueDir) {
+			return errors.New("queueDir path should be absolute")
+		}
+	}
+	if p.TLS.ClientTLSCert != "" && p.TLS.ClientTLSKey == "" || p.TLS.ClientTLSCert == "" && p.TLS.ClientTLSKey != "" {
+		return errors.New("cert and key must be specified as a pair")
+	}
+	return nil
+}
+
+// pulsarTopicPath returns the path of a topic in the Pulsar REST and
+// WebSocket APIs. Topics without tenant and namespace are persistent
+// topics of the 'public/default' namespace, as in the Pulsar clients.
+func pulsarTopicPath(topic string) (string, error) {
+	if topic == "" {
+		return "", errors.New("empty topic")
+	}
+	domain := "persistent"
+	if i := strings.Index(topic, "://"); i >= 0 {
+		domain = topic[:i]
+		topic = topic[i+3:]
+		if domain != "persistent" && domain != "non-persistent" {
+			return "", fmt.Errorf("invalid topic domain '%s'", domain)
+		}
+	}
+	parts := strings.Split(topic, "/")
+	switch len(parts) {
+	case 1:
+		parts = []string{"public", "default", parts[0]}
+	case 3:
+	default:
+		return "", fmt.Errorf("invalid topic '%s', expected 'persistent://tenant/namespace/topic' or 'topic'", topic)
+	}
+	for i, part := range parts {
+		if part == "" {
+			return "", fmt.Errorf("invalid topic '%s'", topic)
+		}
+		parts[i] = url.PathEscape(part)
+	}
+	return domain + "/" + strings.Join(parts, "/"), nil
+}
+
+// pulsarMessage is a message sent to the WebSocket producer API.
+type pulsarMessage struct {
+	Payload    []byte            `json:"payload"`
+	Key        string            `json:"key,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+	Context    string            `json:"context"`
+}
+
+// pulsarResponse is the acknowledgement of a message.
+type pulsarResponse struct {
+	Result    string `json:"result"`
+	MessageID string `json:"messageId"`
+	ErrorMsg  string `json:"errorMsg"`
+	Context   string `json:"context"`
+}
+
+// PulsarTarget - Pulsar target.
+//
+// Events are published with the WebSocket producer API of the Pulsar
+// brokers or proxies, which takes care of topic lookup and partitioning.
+type PulsarTarget struct {
+	lazyInit lazyInit
+
+	id         event.TargetID
+	args       PulsarArgs
+	endpoint   string
+	dialer     *websocket.Dialer
+	header     http.Header
+	store      Store
+	loggerOnce logger.LogOnce
+	quitCh     chan struct{}
+
+	mu   sync.Mutex // protects conn and seq
+	conn *websocket.Conn
+	seq  uint64
+}
+
+// ID - returns target ID.
+func (target *PulsarTarget) ID() event.TargetID {
+	return target.id
+}
+
+// Store returns any underlying store if set.
+func (target *PulsarTarget) Store() event.TargetStore {
+	return target.store
+}
+
+// IsActive - Return true if target is up and active
+func (target *PulsarTarget) IsActive() (bool, error) {
+	if err := target.init(); err != nil {
+		return false, err
+	}
+	return target.isActive()
+}
+
+func (target *PulsarTarget) isActive() (bool, error) {
+	target.mu.Lock()
+	defer target.mu.Unlock()
+
+	if target.conn != nil {
+		err := target.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(pulsarTimeout))
+		if err == nil {
+			return true, nil
+		}
+		target.closeConn()
+	}
+	if err := target.connect(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// connect opens a producer connection, it must be called with mu held.
+func (target *PulsarTarget) connect() error {
+	ctx, cancel := context.WithTimeout(context.Background(), pulsarTimeout)
+	defer cancel()
+
+	conn, resp, err := target.dialer.DialContext(ctx, target.endpoint, target.header)
+	if err != nil {
+		if resp != nil {
+			resp.Body.Close()
+			return fmt.Errorf("pulsar: unable to create producer: %s", resp.Status)
+		}
+		if xnet.IsNetworkOrHostDown(err, false) || IsConnRefusedErr(err) {
+			return errNotConnected
+		}
+		return err
+	}
+	target.conn = conn
+	return nil
+}
+
+// closeConn closes the producer connection, it must be called with mu held.
+func (target *PulsarTarget) closeConn() {
+	if target.conn != nil {
+		target.conn.Close()
+		target.conn = nil
+	}
+}
+
+// Save - saves the events to the store which will be replayed when the Pulsar connection is active.
+func (target *PulsarTarget) Save(eventData event.Event) error {
+	if err := target.init(); err != nil {
+		return err
+	}
+
+	if target.store != nil {
+		return target.store.Put(eventData)
+	}
+	_, err := target.isActive()
+	if err != nil {
+		return err
+	}
+	return target.send(eventData)
+}
+
+// send - sends an event to Pulsar and waits for its acknowledgement.
+func (target *PulsarTarget) send(eventData event.Event) error {
+	objectName, err := url.QueryUnescape(eventData.S3.Object.Key)
+	if err != nil {
+		return err
+	}
+	key := eventData.S3.Bucket.Name + "/" + objectName
+
+	data, err := json.Marshal(event.Log{EventName: eventData.EventName, Key: key, Records: []event.Event{eventData}})
+	if err != nil {
+		return err
+	}
+
+	target.mu.Lock()
+	defer target.mu.Unlock()
+
+	if target.conn == nil {
+		if err = target.connect(); err != nil {
+			return err
+		}
+	}
+
+	target.seq++
+	msg := pulsarMessage{
+		Payload:    data,
+		Key:        key,
+		Properties: map[string]string{"eventName": eventData.EventName.String()},
+		Context:    strconv.FormatUint(target.seq, 10),
+	}
+	var resp pulsarResponse
+	err = func() error {
+		deadline := time.Now().Add(pulsarTimeout)
+		target.conn.SetWriteDeadline(deadline)
+		if err := target.conn.WriteJSON(msg); err != nil {
+			return err
+		}
+		target.conn.SetReadDeadline(deadline)
+		for {
+			if err := target.conn.ReadJSON(&resp); err != nil {
+				return err
+			}
+			// Skip acknowledgements of messages which timed out before.
+			if resp.Context == msg.Context {
+				return nil
+			}
+		}
+	}()
+	if err != nil {
+		// The state of the connection is unknown, reconnect for the next message.
+		target.closeConn()
+		if xnet.IsNetworkOrHostDown(err, true) || IsConnResetErr(err) || websocket.IsUnexpectedCloseError(err) {
+			return errNotConnected
+		}
+		return err
+	}
+	if resp.Result != "ok" {
+		return fmt.Errorf("pulsar: unable to publish message: %s %s", resp.Result, resp.ErrorMsg)
+	}
+	return nil
+}
+
+// Send - reads an event from store and sends it to Pulsar.
+func (target *PulsarTarget) Send(eventKey string) error {
+	if err := target.init(); err != nil {
+		return err
+	}
+
+	_, err := target.isActive()
+	if err != nil {
+		return err
+	}
+
+	eventData, eErr := target.store.Get(eventKey)
+	if eErr != nil {
+		// The last event key in a successful batch will be sent in the channel atmost once by the replayEvents()
+		// Such events will not exist and wouldve been already been sent successfully.
+		if os.IsNotExist(eErr) {
+			return nil
+		}
+		return eErr
+	}
+
+	if err := target.send(eventData); err != nil {
+		return err
+	}
+
+	// Delete the event from store.
+	return target.store.Del(eventKey)
+}
+
+// Close - closes underneath connection to Pulsar.
+func (target *PulsarTarget) Close() error {
+	close(target.quitCh)
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	target.closeConn()
+	return nil
+}
+
+func (target *PulsarTarget) init() error {
+	return target.lazyInit.Do(target.initPulsar)
+}
+
+func (target *PulsarTarget) initPulsar() error {
+	args := target.args
+
+	u := url.URL(args.URL)
+	u.Scheme = strings.Replace(u.Scheme, "http", "ws", 1)
+	topicPath, err := pulsarTopicPath(args.Topic)
+	if err != nil {
+		return err
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/ws/v2/producer/" + topicPath
+	u.RawPath = ""
+	target.endpoint = u.String()
+
+	tlsConfig := &tls.Config{
+		RootCAs:            args.TLS.RootCAs,
+		InsecureSkipVerify: args.TLS.SkipVerify,
+	}
+	if args.TLS.ClientTLSCert != "" && args.TLS.ClientTLSKey != "" {
+		manager, err := certs.NewManager(context.Background(), args.TLS.ClientTLSCert, args.TLS.ClientTLSKey, tls.LoadX509KeyPair)
+		if err != nil {
+			target.loggerOnce(context.Background(), err, target.ID().String())
+			return err
+		}
+		tlsConfig.GetClientCertificate = manager.GetClientCertificate
+	}
+	target.dialer = &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: pulsarTimeout,
+		TLSClientConfig:  tlsConfig,
+	}
+
+	target.header = make(http.Header)
+	if args.AuthToken != "" {
+		target.header.Set("Authorization", "Bearer "+args.AuthToken)
+	}
+
+	yes, err := target.isActive()
+	if err != nil {
+		if err != errNotConnected {
+			target.loggerOnce(context.Background(), err, target.ID().String())
+		}
+		return err
+	}
+	if !yes {
+		return errNotConnected
+	}
+
+	return nil
+}
+
+// NewPulsarTarget - creates new Pulsar target.
+func NewPulsarTarget(id string, args PulsarArgs, loggerOnce logger.LogOnce) (*PulsarTarget, error) {
+	var store Store
+	if args.QueueDir != "" {
+		queueDir := filepath.Join(args.QueueDir, storePrefix+"-pulsar-"+id)
+		var err error
+		if store, err = OpenQueueStore(queueDir, args.QueueLimit, args.QueueSizeLimit, args.QueueCompress); err != nil {
+			return nil, fmt.Errorf("unable to initialize the queue store of Pulsar `%s`: %w", id, err)
+		}
+	}
+
+	target := &PulsarTarget{
+		id:         event.TargetID{ID: id, Name: "pulsar"},
+		args:       args,
+		loggerOnce: loggerOnce,
+		store:      store,
+		quitCh:     make(chan struct{}),
+	}
+
+	if target.store != nil {
+		streamEventsFromStore(target.store, target, target.quitCh, target.loggerOnce)
+	}
+
+	return target, nil
+}
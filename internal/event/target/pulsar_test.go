@@ -0,0 +1,137 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package target
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/infobsmi/b33s/internal/event"
+	xnet "github.com/minio/pkg/net"
+)
+
+func TestPulsarTopicPath(t *testing.T) {
+	testCases := []struct {
+		topic   string
+		want    string
+		wantErr bool
+	}{
+		{"events", "persistent/public/default/events", false},
+		{"persistent://tenant/ns/events", "persistent/tenant/ns/events", false},
+		{"non-persistent://tenant/ns/events", "non-persistent/tenant/ns/events", false},
+		{"tenant/ns/events", "persistent/tenant/ns/events", false},
+		{"", "", true},
+		{"tenant/events", "", true},
+		{"tenant//events", "", true},
+		{"kafka://tenant/ns/events", "", true},
+	}
+	for _, testCase := range testCases {
+		got, err := pulsarTopicPath(testCase.topic)
+		if (err != nil) != testCase.wantErr {
+			t.Errorf("%q: unexpected error %v", testCase.topic, err)
+		}
+		if got != testCase.want {
+			t.Errorf("%q: want %q, got %q", testCase.topic, testCase.want, got)
+		}
+	}
+}
+
+func TestPulsarTarget(t *testing.T) {
+	received := make(chan pulsarMessage, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ws/v2/producer/persistent/public/default/events" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var msg pulsarMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			received <- msg
+			if err := conn.WriteJSON(pulsarResponse{Result: "ok", MessageID: "1:0:-1", Context: msg.Context}); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	u, err := xnet.ParseURL(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := PulsarArgs{Enable: true, URL: *u, Topic: "events", AuthToken: "token"}
+	if err = args.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	target, err := NewPulsarTarget("1", args, func(ctx context.Context, err error, id string, errKind ...interface{}) {
+		t.Error(err)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Close()
+
+	if yes, err := target.IsActive(); !yes || err != nil {
+		t.Fatalf("target is not active: %v", err)
+	}
+
+	eventData := event.Event{EventName: event.ObjectCreatedPut}
+	eventData.S3.Bucket.Name = "bucket"
+	eventData.S3.Object.Key = "object%20name"
+	if err = target.Save(eventData); err != nil {
+		t.Fatal(err)
+	}
+	msg := <-received
+	if msg.Key != "bucket/object name" {
+		t.Errorf("unexpected key %q", msg.Key)
+	}
+	if msg.Properties["eventName"] != "s3:ObjectCreated:Put" {
+		t.Errorf("unexpected properties %v", msg.Properties)
+	}
+	var log event.Log
+	if err = json.Unmarshal(msg.Payload, &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Key != "bucket/object name" || len(log.Records) != 1 {
+		t.Errorf("unexpected payload %s", msg.Payload)
+	}
+
+	args.AuthToken = "invalid"
+	target, err = NewPulsarTarget("2", args, func(ctx context.Context, err error, id string, errKind ...interface{}) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Close()
+	if _, err = target.IsActive(); err == nil {
+		t.Fatal("expected error for invalid token")
+	}
+}
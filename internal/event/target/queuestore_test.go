@@ -35,7 +35,7 @@ var testEvent = event.Event{EventVersion: "1.0", EventSource: "test_source", Aws
 
 // Initialize the store.
 func setUpStore(directory string, limit uint64) (Store, error) {
-	store := NewQueueStore(queueDir, limit)
+	store := NewQueueStore(queueDir, limit, 0, false)
 	if oErr := store.Open(); oErr != nil {
 		return nil, oErr
 	}
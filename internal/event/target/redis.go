@@ -36,31 +36,37 @@ import (
 
 // Redis constants
 const (
-	RedisFormat     = "format"
-	RedisAddress    = "address"
-	RedisPassword   = "password"
-	RedisKey        = "key"
-	RedisQueueDir   = "queue_dir"
-	RedisQueueLimit = "queue_limit"
-
-	EnvRedisEnable     = "MINIO_NOTIFY_REDIS_ENABLE"
-	EnvRedisFormat     = "MINIO_NOTIFY_REDIS_FORMAT"
-	EnvRedisAddress    = "MINIO_NOTIFY_REDIS_ADDRESS"
-	EnvRedisPassword   = "MINIO_NOTIFY_REDIS_PASSWORD"
-	EnvRedisKey        = "MINIO_NOTIFY_REDIS_KEY"
-	EnvRedisQueueDir   = "MINIO_NOTIFY_REDIS_QUEUE_DIR"
-	EnvRedisQueueLimit = "MINIO_NOTIFY_REDIS_QUEUE_LIMIT"
+	RedisFormat           = "format"
+	RedisAddress          = "address"
+	RedisPassword         = "password"
+	RedisKey              = "key"
+	RedisQueueDir         = "queue_dir"
+	RedisQueueLimit       = "queue_limit"
+	RedisQueueSizeLimit   = "queue_size_limit"
+	RedisQueueCompression = "queue_compression"
+
+	EnvRedisEnable           = "MINIO_NOTIFY_REDIS_ENABLE"
+	EnvRedisFormat           = "MINIO_NOTIFY_REDIS_FORMAT"
+	EnvRedisAddress          = "MINIO_NOTIFY_REDIS_ADDRESS"
+	EnvRedisPassword         = "MINIO_NOTIFY_REDIS_PASSWORD"
+	EnvRedisKey              = "MINIO_NOTIFY_REDIS_KEY"
+	EnvRedisQueueDir         = "MINIO_NOTIFY_REDIS_QUEUE_DIR"
+	EnvRedisQueueLimit       = "MINIO_NOTIFY_REDIS_QUEUE_LIMIT"
+	EnvRedisQueueSizeLimit   = "MINIO_NOTIFY_REDIS_QUEUE_SIZE_LIMIT"
+	EnvRedisQueueCompression = "MINIO_NOTIFY_REDIS_QUEUE_COMPRESSION"
 )
 
 // RedisArgs - Redis target arguments.
 type RedisArgs struct {
-	Enable     bool      `json:"enable"`
-	Format     string    `json:"format"`
-	Addr       xnet.Host `json:"address"`
-	Password   string    `json:"password"`
-	Key        string    `json:"key"`
-	QueueDir   string    `json:"queueDir"`
-	QueueLimit uint64    `json:"queueLimit"`
+	Enable         bool      `json:"enable"`
+	Format         string    `json:"format"`
+	Addr           xnet.Host `json:"address"`
+	Password       string    `json:"password"`
+	Key            string    `json:"key"`
+	QueueDir       string    `json:"queueDir"`
+	QueueLimit     uint64    `json:"queueLimit"`
+	QueueSizeLimit uint64    `json:"queueSizeLimit"`
+	QueueCompress  bool      `json:"queueCompress"`
 }
 
 // RedisAccessEvent holds event log data and timestamp
@@ -309,8 +315,8 @@ func NewRedisTarget(id string, args RedisArgs, loggerOnce logger.LogOnce) (*Redi
 	var store Store
 	if args.QueueDir != "" {
 		queueDir := filepath.Join(args.QueueDir, storePrefix+"-redis-"+id)
-		store = NewQueueStore(queueDir, args.QueueLimit)
-		if err := store.Open(); err != nil {
+		var err error
+		if store, err = OpenQueueStore(queueDir, args.QueueLimit, args.QueueSizeLimit, args.QueueCompress); err != nil {
 			return nil, fmt.Errorf("unable to initialize the queue store of Redis `%s`: %w", id, err)
 		}
 	}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/infobsmi/b33s/internal/event"
 	"github.com/infobsmi/b33s/internal/logger"
+	"github.com/infobsmi/b33s/internal/store"
 )
 
 const retryInterval = 3 * time.Second
@@ -35,17 +36,10 @@ const retryInterval = 3 * time.Second
 var errNotConnected = errors.New("not connected to target server/service")
 
 // errLimitExceeded error is sent when the maximum limit is reached.
-var errLimitExceeded = errors.New("the maximum store limit reached")
+var errLimitExceeded = event.ErrQueueLimitExceeded
 
 // Store - To persist the events.
-type Store interface {
-	Put(event event.Event) error
-	Get(key string) (event.Event, error)
-	Len() int
-	List() ([]string, error)
-	Del(key string) error
-	Open() error
-}
+type Store = store.Store[event.Event]
 
 // replayEvents - Reads the events from the store and replays.
 func replayEvents(store Store, doneCh <-chan struct{}, loggerOnce logger.LogOnce, id string) <-chan string {
@@ -64,7 +58,7 @@ func replayEvents(store Store, doneCh <-chan struct{}, loggerOnce logger.LogOnce
 			} else {
 				for _, name := range names {
 					select {
-					case eventKeyCh <- strings.TrimSuffix(name, eventExt):
+					case eventKeyCh <- name:
 					// Get next key.
 					case <-doneCh:
 						return
@@ -104,7 +98,9 @@ func sendEvents(target event.Target, eventKeyCh <-chan string, doneCh <-chan str
 
 	send := func(eventKey string) bool {
 		for {
+			start := time.Now()
 			err := target.Send(eventKey)
+			event.RecordStoreDelivery(target.ID(), 1, err, time.Since(start))
 			if err == nil {
 				break
 			}
@@ -147,7 +143,13 @@ func streamEventsFromStore(store Store, target event.Target, doneCh <-chan struc
 	go func() {
 		// Replays the events from the store.
 		eventKeyCh := replayEvents(store, doneCh, loggerOnce, target.ID().String())
-		// Send events from the store.
+		// Send events from the store, in batches if configured.
+		if t, ok := target.(batchTarget); ok {
+			if size, _ := t.batchArgs(); size > 1 {
+				sendEventBatches(t, eventKeyCh, doneCh, loggerOnce)
+				return
+			}
+		}
 		sendEvents(target, eventKeyCh, doneCh, loggerOnce)
 	}()
 }
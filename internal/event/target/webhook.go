@@ -26,7 +26,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -41,32 +40,50 @@ import (
 
 // Webhook constants
 const (
-	WebhookEndpoint   = "endpoint"
-	WebhookAuthToken  = "auth_token"
-	WebhookQueueDir   = "queue_dir"
-	WebhookQueueLimit = "queue_limit"
-	WebhookClientCert = "client_cert"
-	WebhookClientKey  = "client_key"
-
-	EnvWebhookEnable     = "MINIO_NOTIFY_WEBHOOK_ENABLE"
-	EnvWebhookEndpoint   = "MINIO_NOTIFY_WEBHOOK_ENDPOINT"
-	EnvWebhookAuthToken  = "MINIO_NOTIFY_WEBHOOK_AUTH_TOKEN"
-	EnvWebhookQueueDir   = "MINIO_NOTIFY_WEBHOOK_QUEUE_DIR"
-	EnvWebhookQueueLimit = "MINIO_NOTIFY_WEBHOOK_QUEUE_LIMIT"
-	EnvWebhookClientCert = "MINIO_NOTIFY_WEBHOOK_CLIENT_CERT"
-	EnvWebhookClientKey  = "MINIO_NOTIFY_WEBHOOK_CLIENT_KEY"
+	WebhookEndpoint         = "endpoint"
+	WebhookAuthToken        = "auth_token"
+	WebhookQueueDir         = "queue_dir"
+	WebhookQueueLimit       = "queue_limit"
+	WebhookQueueSizeLimit   = "queue_size_limit"
+	WebhookQueueCompression = "queue_compression"
+	WebhookClientCert       = "client_cert"
+	WebhookClientKey        = "client_key"
+
+	WebhookBatchSize     = "batch_size"
+	WebhookBatchMaxWait  = "batch_max_wait"
+	WebhookPayloadFormat = "payload_format"
+
+	EnvWebhookEnable           = "MINIO_NOTIFY_WEBHOOK_ENABLE"
+	EnvWebhookEndpoint         = "MINIO_NOTIFY_WEBHOOK_ENDPOINT"
+	EnvWebhookAuthToken        = "MINIO_NOTIFY_WEBHOOK_AUTH_TOKEN"
+	EnvWebhookQueueDir         = "MINIO_NOTIFY_WEBHOOK_QUEUE_DIR"
+	EnvWebhookQueueLimit       = "MINIO_NOTIFY_WEBHOOK_QUEUE_LIMIT"
+	EnvWebhookQueueSizeLimit   = "MINIO_NOTIFY_WEBHOOK_QUEUE_SIZE_LIMIT"
+	EnvWebhookQueueCompression = "MINIO_NOTIFY_WEBHOOK_QUEUE_COMPRESSION"
+	EnvWebhookClientCert       = "MINIO_NOTIFY_WEBHOOK_CLIENT_CERT"
+	EnvWebhookClientKey        = "MINIO_NOTIFY_WEBHOOK_CLIENT_KEY"
+
+	EnvWebhookBatchSize     = "MINIO_NOTIFY_WEBHOOK_BATCH_SIZE"
+	EnvWebhookBatchMaxWait  = "MINIO_NOTIFY_WEBHOOK_BATCH_MAX_WAIT"
+	EnvWebhookPayloadFormat = "MINIO_NOTIFY_WEBHOOK_PAYLOAD_FORMAT"
 )
 
 // WebhookArgs - Webhook target arguments.
 type WebhookArgs struct {
-	Enable     bool            `json:"enable"`
-	Endpoint   xnet.URL        `json:"endpoint"`
-	AuthToken  string          `json:"authToken"`
-	Transport  *http.Transport `json:"-"`
-	QueueDir   string          `json:"queueDir"`
-	QueueLimit uint64          `json:"queueLimit"`
-	ClientCert string          `json:"clientCert"`
-	ClientKey  string          `json:"clientKey"`
+	Enable         bool            `json:"enable"`
+	Endpoint       xnet.URL        `json:"endpoint"`
+	AuthToken      string          `json:"authToken"`
+	Transport      *http.Transport `json:"-"`
+	QueueDir       string          `json:"queueDir"`
+	QueueLimit     uint64          `json:"queueLimit"`
+	QueueSizeLimit uint64          `json:"queueSizeLimit"`
+	QueueCompress  bool            `json:"queueCompress"`
+	ClientCert     string          `json:"clientCert"`
+	ClientKey      string          `json:"clientKey"`
+
+	BatchSize     uint32        `json:"batchSize"`
+	BatchMaxWait  time.Duration `json:"batchMaxWait"`
+	PayloadFormat string        `json:"payloadFormat"`
 }
 
 // Validate WebhookArgs fields
@@ -85,6 +102,15 @@ func (w WebhookArgs) Validate() error {
 	if w.ClientCert != "" && w.ClientKey == "" || w.ClientCert == "" && w.ClientKey != "" {
 		return errors.New("cert and key must be specified as a pair")
 	}
+	if err := validatePayloadFormat(w.PayloadFormat); err != nil {
+		return err
+	}
+	if err := validateBatchArgs(w.BatchSize, w.QueueDir); err != nil {
+		return err
+	}
+	if w.BatchSize > 1 && w.PayloadFormat == PayloadFormatCloudEventsBinary {
+		return errors.New("batches cannot be sent in binary CloudEvents format")
+	}
 	return nil
 }
 
@@ -173,21 +199,67 @@ func (target *WebhookTarget) Save(eventData event.Event) error {
 
 // send - sends an event to the webhook.
 func (target *WebhookTarget) send(eventData event.Event) error {
-	objectName, err := url.QueryUnescape(eventData.S3.Object.Key)
+	key, data, err := eventLog(eventData)
 	if err != nil {
 		return err
 	}
-	key := eventData.S3.Bucket.Name + "/" + objectName
 
-	data, err := json.Marshal(event.Log{EventName: eventData.EventName, Key: key, Records: []event.Event{eventData}})
-	if err != nil {
-		return err
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	switch target.args.PayloadFormat {
+	case PayloadFormatCloudEventsStructured:
+		data, err = json.Marshal(newCloudEvent(eventData, key, data))
+		if err != nil {
+			return err
+		}
+		header.Set("Content-Type", cloudEventsContentType)
+	case PayloadFormatCloudEventsBinary:
+		for name, value := range newCloudEvent(eventData, key, data).attributes() {
+			header.Set("ce-"+name, value)
+		}
+	}
+	return target.post(data, header)
+}
+
+// sendBatch - sends a batch of events to the webhook as JSON array.
+func (target *WebhookTarget) sendBatch(events []event.Event) error {
+	structured := target.args.PayloadFormat == PayloadFormatCloudEventsStructured
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, eventData := range events {
+		key, data, err := eventLog(eventData)
+		if err != nil {
+			return err
+		}
+		if structured {
+			data, err = json.Marshal(newCloudEvent(eventData, key, data))
+			if err != nil {
+				return err
+			}
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(data)
+	}
+	buf.WriteByte(']')
+
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	if structured {
+		header.Set("Content-Type", cloudEventsBatchContentType)
 	}
+	return target.post(buf.Bytes(), header)
+}
 
+// post - posts the payload of one or more events to the webhook.
+func (target *WebhookTarget) post(data []byte, header http.Header) error {
 	req, err := http.NewRequest(http.MethodPost, target.args.Endpoint.String(), bytes.NewReader(data))
 	if err != nil {
 		return err
 	}
+	req.Header = header
 
 	// Verify if the authToken already contains
 	// <Key> <Token> like format, if this is
@@ -201,8 +273,6 @@ func (target *WebhookTarget) send(eventData event.Event) error {
 		req.Header.Set("Authorization", "Bearer "+target.args.AuthToken)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
 	resp, err := target.httpClient.Do(req)
 	if err != nil {
 		return err
@@ -244,6 +314,35 @@ func (target *WebhookTarget) Send(eventKey string) error {
 	return target.store.Del(eventKey)
 }
 
+// SendBatch - reads a batch of events from store and sends it to webhook.
+func (target *WebhookTarget) SendBatch(eventKeys []string) error {
+	if err := target.init(); err != nil {
+		return err
+	}
+
+	keys, events, err := getBatch(target.store, eventKeys)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	if err := target.sendBatch(events); err != nil {
+		if xnet.IsNetworkOrHostDown(err, false) {
+			return errNotConnected
+		}
+		return err
+	}
+
+	// Delete the events from store.
+	return delBatch(target.store, keys)
+}
+
+func (target *WebhookTarget) batchArgs() (int, time.Duration) {
+	return int(target.args.BatchSize), target.args.BatchMaxWait
+}
+
 // Close - does nothing and available for interface compatibility.
 func (target *WebhookTarget) Close() error {
 	target.cancel()
@@ -287,8 +386,8 @@ func NewWebhookTarget(ctx context.Context, id string, args WebhookArgs, loggerOn
 	var store Store
 	if args.QueueDir != "" {
 		queueDir := filepath.Join(args.QueueDir, storePrefix+"-webhook-"+id)
-		store = NewQueueStore(queueDir, args.QueueLimit)
-		if err := store.Open(); err != nil {
+		var err error
+		if store, err = OpenQueueStore(queueDir, args.QueueLimit, args.QueueSizeLimit, args.QueueCompress); err != nil {
 			cancel()
 			return nil, fmt.Errorf("unable to initialize the queue store of Webhook `%s`: %w", id, err)
 		}
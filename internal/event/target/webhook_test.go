@@ -0,0 +1,251 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package target
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/infobsmi/b33s/internal/event"
+	xnet "github.com/minio/pkg/net"
+)
+
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newWebhookTestServer(t *testing.T) (*httptest.Server, <-chan webhookRequest) {
+	received := make(chan webhookRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		received <- webhookRequest{header: r.Header, body: body}
+	}))
+	return server, received
+}
+
+func newWebhookTestEvent(name string) event.Event {
+	eventData := event.Event{
+		EventVersion: "2.0",
+		EventSource:  "minio:s3",
+		EventTime:    "2023-01-01T00:00:00.000Z",
+		EventName:    event.ObjectCreatedPut,
+		ResponseElements: map[string]string{
+			"x-amz-request-id": "17A2B3C4D5E6F708",
+		},
+	}
+	eventData.S3.Bucket.Name = "bucket"
+	eventData.S3.Bucket.ARN = "arn:aws:s3:::bucket"
+	eventData.S3.Object.Key = name
+	eventData.S3.Object.Sequencer = "17A2B3C4D5E6F709"
+	return eventData
+}
+
+func TestWebhookArgsValidate(t *testing.T) {
+	u, err := xnet.ParseHTTPURL("http://localhost:8080/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		args    WebhookArgs
+		wantErr bool
+	}{
+		{WebhookArgs{Enable: true, Endpoint: *u}, false},
+		{WebhookArgs{Enable: true, Endpoint: *u, PayloadFormat: PayloadFormatCloudEventsStructured}, false},
+		{WebhookArgs{Enable: true, Endpoint: *u, PayloadFormat: "xml"}, true},
+		{WebhookArgs{Enable: true, Endpoint: *u, BatchSize: 10}, true},
+		{WebhookArgs{Enable: true, Endpoint: *u, BatchSize: 10, QueueDir: "/tmp/events"}, false},
+		{WebhookArgs{Enable: true, Endpoint: *u, BatchSize: 10, QueueDir: "/tmp/events", PayloadFormat: PayloadFormatCloudEventsBinary}, true},
+	}
+	for i, testCase := range testCases {
+		if err := testCase.args.Validate(); (err != nil) != testCase.wantErr {
+			t.Errorf("test %d: unexpected error %v", i+1, err)
+		}
+	}
+}
+
+func TestWebhookPayloadFormat(t *testing.T) {
+	server, received := newWebhookTestServer(t)
+	defer server.Close()
+
+	u, err := xnet.ParseHTTPURL(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{PayloadFormatDefault, PayloadFormatCloudEventsStructured, PayloadFormatCloudEventsBinary} {
+		args := WebhookArgs{Enable: true, Endpoint: *u, PayloadFormat: format}
+		if err = args.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		target, err := NewWebhookTarget(context.Background(), format, args, func(ctx context.Context, err error, id string, errKind ...interface{}) {
+			t.Error(err)
+		}, http.DefaultTransport.(*http.Transport).Clone())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = target.Save(newWebhookTestEvent("object%20name")); err != nil {
+			t.Fatal(err)
+		}
+		target.Close()
+
+		req := <-received
+		contentType := req.header.Get("Content-Type")
+		var log event.Log
+		switch format {
+		case PayloadFormatCloudEventsStructured:
+			if contentType != cloudEventsContentType {
+				t.Errorf("%s: unexpected content type %q", format, contentType)
+			}
+			var ce cloudEvent
+			if err = json.Unmarshal(req.body, &ce); err != nil {
+				t.Fatal(err)
+			}
+			if ce.SpecVersion != "1.0" || ce.ID != "17A2B3C4D5E6F708-17A2B3C4D5E6F709" ||
+				ce.Source != "arn:aws:s3:::bucket" || ce.Type != "s3:ObjectCreated:Put" || ce.Subject != "object name" {
+				t.Errorf("%s: unexpected CloudEvent %s", format, req.body)
+			}
+			req.body = ce.Data
+		case PayloadFormatCloudEventsBinary:
+			if contentType != "application/json" {
+				t.Errorf("%s: unexpected content type %q", format, contentType)
+			}
+			if req.header.Get("ce-specversion") != "1.0" || req.header.Get("ce-type") != "s3:ObjectCreated:Put" ||
+				req.header.Get("ce-subject") != "object name" || req.header.Get("ce-time") != "2023-01-01T00:00:00.000Z" {
+				t.Errorf("%s: unexpected headers %v", format, req.header)
+			}
+		default:
+			if contentType != "application/json" {
+				t.Errorf("%s: unexpected content type %q", format, contentType)
+			}
+			if req.header.Get("ce-specversion") != "" {
+				t.Errorf("%s: unexpected headers %v", format, req.header)
+			}
+		}
+		if err = json.Unmarshal(req.body, &log); err != nil {
+			t.Fatal(err)
+		}
+		if log.Key != "bucket/object name" || len(log.Records) != 1 {
+			t.Errorf("%s: unexpected event log %s", format, req.body)
+		}
+	}
+}
+
+func TestWebhookSharedQueueStore(t *testing.T) {
+	server, _ := newWebhookTestServer(t)
+	defer server.Close()
+
+	u, err := xnet.ParseHTTPURL(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := WebhookArgs{Enable: true, Endpoint: *u, QueueDir: t.TempDir(), QueueLimit: 10}
+	loggerOnce := func(ctx context.Context, err error, id string, errKind ...interface{}) {
+		t.Error(err)
+	}
+	// A target tested with the configuration of a running target
+	// must use its queue store instead of opening the directory again.
+	running, err := NewWebhookTarget(context.Background(), "shared", args, loggerOnce, http.DefaultTransport.(*http.Transport).Clone())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer running.Close()
+	tested, err := NewWebhookTarget(context.Background(), "shared", args, loggerOnce, http.DefaultTransport.(*http.Transport).Clone())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tested.Close()
+	if tested.store != running.store {
+		t.Fatal("expected the queue store of the running target")
+	}
+}
+
+func TestWebhookSendBatch(t *testing.T) {
+	server, received := newWebhookTestServer(t)
+	defer server.Close()
+
+	u, err := xnet.ParseHTTPURL(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{PayloadFormatDefault, PayloadFormatCloudEventsStructured} {
+		store := NewQueueStore(t.TempDir(), 10, 0, false)
+		if err = store.Open(); err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"a", "b"} {
+			if err = store.Put(newWebhookTestEvent(name)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		names, err := store.List()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys := []string{"missing"}
+		for _, name := range names {
+			keys = append(keys, strings.TrimSuffix(name, eventExt))
+		}
+
+		target := &WebhookTarget{
+			id:        event.TargetID{ID: format, Name: "webhook"},
+			args:      WebhookArgs{Enable: true, Endpoint: *u, PayloadFormat: format, BatchSize: 10},
+			transport: http.DefaultTransport.(*http.Transport).Clone(),
+			store:     store,
+		}
+		if err = target.SendBatch(keys); err != nil {
+			t.Fatal(err)
+		}
+		if store.Len() != 0 {
+			t.Errorf("%s: events are not removed from the store", format)
+		}
+
+		req := <-received
+		var batch []json.RawMessage
+		if err = json.Unmarshal(req.body, &batch); err != nil {
+			t.Fatal(err)
+		}
+		if len(batch) != 2 {
+			t.Fatalf("%s: unexpected batch %s", format, req.body)
+		}
+		wantContentType := "application/json"
+		if format == PayloadFormatCloudEventsStructured {
+			wantContentType = cloudEventsBatchContentType
+			var ce cloudEvent
+			if err = json.Unmarshal(batch[0], &ce); err != nil {
+				t.Fatal(err)
+			}
+			if ce.SpecVersion != "1.0" {
+				t.Errorf("%s: unexpected CloudEvent %s", format, batch[0])
+			}
+		}
+		if contentType := req.header.Get("Content-Type"); contentType != wantContentType {
+			t.Errorf("%s: unexpected content type %q", format, contentType)
+		}
+	}
+}
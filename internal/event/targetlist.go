@@ -18,10 +18,14 @@
 package event
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
+
+	"github.com/infobsmi/b33s/internal/store"
 )
 
 const (
@@ -29,6 +33,10 @@ const (
 	maxConcurrentTargetSendCalls = 20000
 )
 
+// ErrQueueLimitExceeded - the queue store of a target is full, the event
+// is dropped.
+var ErrQueueLimitExceeded = store.ErrLimitExceeded
+
 // Target - event target interface
 type Target interface {
 	ID() TargetID
@@ -56,6 +64,71 @@ type TargetStats struct {
 type TargetStat struct {
 	ID           TargetID
 	CurrentQueue int // Populated if target has a store.
+
+	// TotalEvents is the number of events delivered to the target,
+	// including the events replayed from its store if it has one.
+	TotalEvents int64
+	// FailedEvents is the number of events which failed to be
+	// delivered, counted once per failed attempt.
+	FailedEvents int64
+	// DroppedEvents is the number of events dropped because of too
+	// many concurrent Send calls or a full store.
+	DroppedEvents int64
+	// TotalLatency is the total time taken by successful deliveries.
+	TotalLatency time.Duration
+	// LatencyCount is the number of successful deliveries measured
+	// in TotalLatency, a batch of events counts once.
+	LatencyCount int64
+}
+
+// targetStat - delivery counters of a target.
+type targetStat struct {
+	totalEvents   int64
+	failedEvents  int64
+	droppedEvents int64
+	totalLatency  int64 // in nanoseconds, of the delivered events
+	latencyCount  int64
+}
+
+// record - records the result of delivering n events at once.
+func (st *targetStat) record(n int, err error, latency time.Duration) {
+	switch {
+	case err == nil:
+		atomic.AddInt64(&st.totalEvents, int64(n))
+		atomic.AddInt64(&st.totalLatency, int64(latency))
+		atomic.AddInt64(&st.latencyCount, 1)
+	case errors.Is(err, ErrQueueLimitExceeded):
+		atomic.AddInt64(&st.droppedEvents, int64(n))
+	default:
+		atomic.AddInt64(&st.failedEvents, int64(n))
+	}
+}
+
+// storeStats - delivery counters of the targets with a store, the events
+// of such targets are delivered when they are replayed from the store,
+// outside of any TargetList. The counters are shared by all the lists
+// holding the target and survive reloading its configuration.
+var storeStats = struct {
+	sync.Mutex
+	stats map[TargetID]*targetStat
+}{stats: make(map[TargetID]*targetStat)}
+
+func getStoreStat(id TargetID) *targetStat {
+	storeStats.Lock()
+	defer storeStats.Unlock()
+
+	st, ok := storeStats.stats[id]
+	if !ok {
+		st = &targetStat{}
+		storeStats.stats[id] = st
+	}
+	return st
+}
+
+// RecordStoreDelivery - records the result of delivering n events
+// replayed from the store of the target.
+func RecordStoreDelivery(id TargetID, n int, err error, latency time.Duration) {
+	getStoreStat(id).record(n, err, latency)
 }
 
 // TargetList - holds list of targets indexed by target ID.
@@ -65,6 +138,7 @@ type TargetList struct {
 
 	sync.RWMutex
 	targets map[TargetID]Target
+	stats   map[TargetID]*targetStat
 }
 
 // Add - adds unique target to target list.
@@ -77,6 +151,11 @@ func (list *TargetList) Add(targets ...Target) error {
 			return fmt.Errorf("target %v already exists", target.ID())
 		}
 		list.targets[target.ID()] = target
+		if target.Store() != nil {
+			list.stats[target.ID()] = getStoreStat(target.ID())
+		} else {
+			list.stats[target.ID()] = &targetStat{}
+		}
 	}
 
 	return nil
@@ -110,6 +189,7 @@ func (list *TargetList) Remove(targetIDSet TargetIDSet) {
 		if ok {
 			target.Close()
 			delete(list.targets, id)
+			delete(list.stats, id)
 		}
 	}
 }
@@ -155,6 +235,13 @@ func (list *TargetList) TargetMap() map[TargetID]Target {
 func (list *TargetList) Send(event Event, targetIDset TargetIDSet, resCh chan<- TargetIDResult) {
 	if atomic.LoadInt64(&list.currentSendCalls) > maxConcurrentTargetSendCalls {
 		err := fmt.Errorf("concurrent target notifications exceeded %d", maxConcurrentTargetSendCalls)
+		list.RLock()
+		for id := range targetIDset {
+			if st, ok := list.stats[id]; ok {
+				atomic.AddInt64(&st.droppedEvents, 1)
+			}
+		}
+		list.RUnlock()
 		for id := range targetIDset {
 			resCh <- TargetIDResult{ID: id, Err: err}
 		}
@@ -166,19 +253,26 @@ func (list *TargetList) Send(event Event, targetIDset TargetIDSet, resCh chan<-
 		for id := range targetIDset {
 			list.RLock()
 			target, ok := list.targets[id]
+			st := list.stats[id]
 			list.RUnlock()
 			if ok {
 				wg.Add(1)
-				go func(id TargetID, target Target) {
+				go func(id TargetID, target Target, st *targetStat) {
 					atomic.AddInt64(&list.currentSendCalls, 1)
 					defer atomic.AddInt64(&list.currentSendCalls, -1)
 					defer wg.Done()
 					tgtRes := TargetIDResult{ID: id}
+					start := time.Now()
 					if err := target.Save(event); err != nil {
 						tgtRes.Err = err
 					}
+					// Saving to the store is not a delivery, the
+					// delivery is recorded when the event is replayed.
+					if target.Store() == nil || tgtRes.Err != nil {
+						st.record(1, tgtRes.Err, time.Since(start))
+					}
 					resCh <- tgtRes
-				}(id, target)
+				}(id, target, st)
 			} else {
 				resCh <- TargetIDResult{ID: id}
 			}
@@ -202,6 +296,13 @@ func (list *TargetList) Stats() TargetStats {
 		if st := target.Store(); st != nil {
 			ts.CurrentQueue = st.Len()
 		}
+		if st, ok := list.stats[id]; ok {
+			ts.TotalEvents = atomic.LoadInt64(&st.totalEvents)
+			ts.FailedEvents = atomic.LoadInt64(&st.failedEvents)
+			ts.DroppedEvents = atomic.LoadInt64(&st.droppedEvents)
+			ts.TotalLatency = time.Duration(atomic.LoadInt64(&st.totalLatency))
+			ts.LatencyCount = atomic.LoadInt64(&st.latencyCount)
+		}
 		t.TargetStats[strings.ReplaceAll(id.String(), ":", "_")] = ts
 	}
 	return t
@@ -209,5 +310,8 @@ func (list *TargetList) Stats() TargetStats {
 
 // NewTargetList - creates TargetList.
 func NewTargetList() *TargetList {
-	return &TargetList{targets: make(map[TargetID]Target)}
+	return &TargetList{
+		targets: make(map[TargetID]Target),
+		stats:   make(map[TargetID]*targetStat),
+	}
 }
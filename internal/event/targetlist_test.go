@@ -259,6 +259,112 @@ func TestTargetListSend(t *testing.T) {
 	}
 }
 
+func TestTargetListStats(t *testing.T) {
+	targetList := NewTargetList()
+	okID, errID := TargetID{"1", "testcase"}, TargetID{"2", "testcase"}
+	if err := targetList.Add(&ExampleTarget{okID, false, false}, &ExampleTarget{errID, true, false}); err != nil {
+		t.Fatal(err)
+	}
+
+	resCh := make(chan TargetIDResult)
+	for i := 0; i < 2; i++ {
+		targetList.Send(Event{}, NewTargetIDSet(okID, errID), resCh)
+		<-resCh
+		<-resCh
+	}
+
+	stats := targetList.Stats()
+	okStat, errStat := stats.TargetStats["1_testcase"], stats.TargetStats["2_testcase"]
+	if okStat.TotalEvents != 2 || okStat.FailedEvents != 0 || okStat.DroppedEvents != 0 {
+		t.Errorf("unexpected stats %+v", okStat)
+	}
+	if errStat.TotalEvents != 0 || errStat.FailedEvents != 2 || errStat.DroppedEvents != 0 {
+		t.Errorf("unexpected stats %+v", errStat)
+	}
+	if okStat.LatencyCount != 2 || okStat.TotalLatency <= 0 || okStat.TotalLatency > time.Second {
+		t.Errorf("unexpected latency %v for %d deliveries", okStat.TotalLatency, okStat.LatencyCount)
+	}
+
+	targetList.Remove(NewTargetIDSet(errID))
+	if _, ok := targetList.Stats().TargetStats["2_testcase"]; ok {
+		t.Errorf("stats of removed target are not removed")
+	}
+}
+
+type exampleStore struct {
+	keys []string
+}
+
+func (store *exampleStore) Len() int {
+	return len(store.keys)
+}
+
+// ExampleStoreTarget - queues the events in a store, they are delivered
+// when replayed.
+type ExampleStoreTarget struct {
+	ExampleTarget
+	store *exampleStore
+}
+
+func (target *ExampleStoreTarget) Save(eventData Event) error {
+	if target.sendErr {
+		return ErrQueueLimitExceeded
+	}
+	target.store.keys = append(target.store.keys, "key")
+	return nil
+}
+
+func (target *ExampleStoreTarget) Store() TargetStore {
+	return target.store
+}
+
+func TestTargetListStoreStats(t *testing.T) {
+	id := TargetID{"store", "testcase"}
+	target := &ExampleStoreTarget{ExampleTarget{id: id}, &exampleStore{}}
+	targetList, otherList := NewTargetList(), NewTargetList()
+	if err := targetList.Add(target); err != nil {
+		t.Fatal(err)
+	}
+	if err := otherList.Add(target); err != nil {
+		t.Fatal(err)
+	}
+
+	resCh := make(chan TargetIDResult)
+	targetList.Send(Event{}, NewTargetIDSet(id), resCh)
+	if res := <-resCh; res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	st := targetList.Stats().TargetStats["store_testcase"]
+	if st.CurrentQueue != 1 || st.TotalEvents != 0 || st.LatencyCount != 0 {
+		t.Errorf("saving to the store must not be counted as delivered, got %+v", st)
+	}
+
+	// Replay the queued event, the first attempt fails.
+	RecordStoreDelivery(id, 1, errors.New("not connected"), time.Millisecond)
+	RecordStoreDelivery(id, 1, nil, 2*time.Millisecond)
+	// A batch of events.
+	RecordStoreDelivery(id, 3, nil, 4*time.Millisecond)
+
+	for _, list := range []*TargetList{targetList, otherList} {
+		st = list.Stats().TargetStats["store_testcase"]
+		if st.TotalEvents != 4 || st.FailedEvents != 1 || st.DroppedEvents != 0 {
+			t.Errorf("unexpected stats %+v", st)
+		}
+		if st.LatencyCount != 2 || st.TotalLatency != 6*time.Millisecond {
+			t.Errorf("unexpected latency %v for %d deliveries", st.TotalLatency, st.LatencyCount)
+		}
+	}
+
+	target.sendErr = true
+	targetList.Send(Event{}, NewTargetIDSet(id), resCh)
+	if res := <-resCh; !errors.Is(res.Err, ErrQueueLimitExceeded) {
+		t.Fatalf("expected %v, got %v", ErrQueueLimitExceeded, res.Err)
+	}
+	if st = targetList.Stats().TargetStats["store_testcase"]; st.DroppedEvents != 1 {
+		t.Errorf("unexpected stats %+v", st)
+	}
+}
+
 func TestNewTargetList(t *testing.T) {
 	if result := NewTargetList(); result == nil {
 		t.Fatalf("test: result: expected: <non-nil>, got: <nil>")
@@ -42,6 +42,20 @@ const (
 	Range              = "Range"
 )
 
+// Standard CORS HTTP header constants
+const (
+	Origin                        = "Origin"
+	Vary                          = "Vary"
+	AccessControlRequestMethod    = "Access-Control-Request-Method"
+	AccessControlRequestHeaders   = "Access-Control-Request-Headers"
+	AccessControlAllowOrigin      = "Access-Control-Allow-Origin"
+	AccessControlAllowMethods     = "Access-Control-Allow-Methods"
+	AccessControlAllowHeaders     = "Access-Control-Allow-Headers"
+	AccessControlAllowCredentials = "Access-Control-Allow-Credentials"
+	AccessControlExposeHeaders    = "Access-Control-Expose-Headers"
+	AccessControlMaxAge           = "Access-Control-Max-Age"
+)
+
 // Non standard S3 HTTP response constants
 const (
 	XCache       = "X-Cache"
@@ -161,6 +175,11 @@ const (
 	AmzChecksumSHA256 = "x-amz-checksum-sha256"
 	AmzChecksumMode   = "x-amz-checksum-mode"
 
+	// GetObjectAttributes
+	AmzObjectAttributes = "x-amz-object-attributes"
+	AmzMaxParts         = "x-amz-max-parts"
+	AmzPartNumberMarker = "x-amz-part-number-marker"
+
 	// Delete special flag to force delete a bucket or a prefix
 	B33SForceDelete = "x-minio-force-delete"
 
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	xhttp "github.com/infobsmi/b33s/internal/http"
@@ -32,6 +33,31 @@ import (
 
 const contextAuditKey = contextKeyType("audit-entry")
 
+// AuditHook receives the audit entries of incoming API calls on the
+// buckets it is enabled for, independently of the audit targets.
+type AuditHook interface {
+	// Enabled returns true if the API calls on bucket must be logged.
+	Enabled(bucket string) bool
+	// Log is called with reqInfo read locked, it must not block.
+	Log(entry audit.Entry, r *http.Request, reqInfo *ReqInfo)
+}
+
+type auditHookHolder struct {
+	hook AuditHook
+}
+
+var auditHook atomic.Value
+
+// SetAuditHook sets the audit hook, a nil hook disables it.
+func SetAuditHook(hook AuditHook) {
+	auditHook.Store(auditHookHolder{hook: hook})
+}
+
+func getAuditHook() AuditHook {
+	holder, _ := auditHook.Load().(auditHookHolder)
+	return holder.hook
+}
+
 // SetAuditEntry sets Audit info in the context.
 func SetAuditEntry(ctx context.Context, audit *audit.Entry) context.Context {
 	if ctx == nil {
@@ -61,7 +87,8 @@ func GetAuditEntry(ctx context.Context) *audit.Entry {
 // AuditLog - logs audit logs to all audit targets.
 func AuditLog(ctx context.Context, w http.ResponseWriter, r *http.Request, reqClaims map[string]interface{}, filterKeys ...string) {
 	auditTgts := AuditTargets()
-	if len(auditTgts) == 0 {
+	hook := getAuditHook()
+	if len(auditTgts) == 0 && hook == nil {
 		return
 	}
 
@@ -74,6 +101,11 @@ func AuditLog(ctx context.Context, w http.ResponseWriter, r *http.Request, reqCl
 		reqInfo.RLock()
 		defer reqInfo.RUnlock()
 
+		hooked := hook != nil && reqInfo.BucketName != "" && hook.Enabled(reqInfo.BucketName)
+		if len(auditTgts) == 0 && !hooked {
+			return
+		}
+
 		entry = audit.ToEntry(w, r, reqClaims, xhttp.GlobalDeploymentID)
 		// indicates all requests for this API call are inbound
 		entry.Trigger = "incoming"
@@ -132,6 +164,10 @@ func AuditLog(ctx context.Context, w http.ResponseWriter, r *http.Request, reqCl
 		if timeToFirstByte != 0 {
 			entry.API.TimeToFirstByte = strconv.FormatInt(timeToFirstByte.Nanoseconds(), 10) + "ns"
 		}
+
+		if hooked {
+			hook.Log(entry, r, reqInfo)
+		}
 	} else {
 		auditEntry := GetAuditEntry(ctx)
 		if auditEntry != nil {
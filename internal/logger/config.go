@@ -20,13 +20,17 @@ package logger
 import (
 	"crypto/tls"
 	"errors"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/minio/pkg/env"
 	xnet "github.com/minio/pkg/net"
 
 	"github.com/infobsmi/b33s/internal/config"
+	"github.com/infobsmi/b33s/internal/logger/target/file"
 	"github.com/infobsmi/b33s/internal/logger/target/http"
 	"github.com/infobsmi/b33s/internal/logger/target/kafka"
 )
@@ -43,6 +47,7 @@ const (
 	ClientCert = "client_cert"
 	ClientKey  = "client_key"
 	QueueSize  = "queue_size"
+	QueueDir   = "queue_dir"
 
 	KafkaBrokers       = "brokers"
 	KafkaTopic         = "topic"
@@ -56,6 +61,13 @@ const (
 	KafkaClientTLSCert = "client_tls_cert"
 	KafkaClientTLSKey  = "client_tls_key"
 	KafkaVersion       = "version"
+	KafkaQueueDir      = "queue_dir"
+
+	FilePath       = "path"
+	FileMaxSize    = "max_size"
+	FileMaxAge     = "max_age"
+	FileMaxBackups = "max_backups"
+	FileCompress   = "compress"
 
 	EnvLoggerWebhookEnable     = "MINIO_LOGGER_WEBHOOK_ENABLE"
 	EnvLoggerWebhookEndpoint   = "MINIO_LOGGER_WEBHOOK_ENDPOINT"
@@ -63,6 +75,7 @@ const (
 	EnvLoggerWebhookClientCert = "MINIO_LOGGER_WEBHOOK_CLIENT_CERT"
 	EnvLoggerWebhookClientKey  = "MINIO_LOGGER_WEBHOOK_CLIENT_KEY"
 	EnvLoggerWebhookQueueSize  = "MINIO_LOGGER_WEBHOOK_QUEUE_SIZE"
+	EnvLoggerWebhookQueueDir   = "MINIO_LOGGER_WEBHOOK_QUEUE_DIR"
 
 	EnvAuditWebhookEnable     = "MINIO_AUDIT_WEBHOOK_ENABLE"
 	EnvAuditWebhookEndpoint   = "MINIO_AUDIT_WEBHOOK_ENDPOINT"
@@ -70,6 +83,7 @@ const (
 	EnvAuditWebhookClientCert = "MINIO_AUDIT_WEBHOOK_CLIENT_CERT"
 	EnvAuditWebhookClientKey  = "MINIO_AUDIT_WEBHOOK_CLIENT_KEY"
 	EnvAuditWebhookQueueSize  = "MINIO_AUDIT_WEBHOOK_QUEUE_SIZE"
+	EnvAuditWebhookQueueDir   = "MINIO_AUDIT_WEBHOOK_QUEUE_DIR"
 
 	EnvKafkaEnable        = "MINIO_AUDIT_KAFKA_ENABLE"
 	EnvKafkaBrokers       = "MINIO_AUDIT_KAFKA_BROKERS"
@@ -84,6 +98,21 @@ const (
 	EnvKafkaClientTLSCert = "MINIO_AUDIT_KAFKA_CLIENT_TLS_CERT"
 	EnvKafkaClientTLSKey  = "MINIO_AUDIT_KAFKA_CLIENT_TLS_KEY"
 	EnvKafkaVersion       = "MINIO_AUDIT_KAFKA_VERSION"
+	EnvKafkaQueueDir      = "MINIO_AUDIT_KAFKA_QUEUE_DIR"
+
+	EnvLoggerFileEnable     = "MINIO_LOGGER_FILE_ENABLE"
+	EnvLoggerFilePath       = "MINIO_LOGGER_FILE_PATH"
+	EnvLoggerFileMaxSize    = "MINIO_LOGGER_FILE_MAX_SIZE"
+	EnvLoggerFileMaxAge     = "MINIO_LOGGER_FILE_MAX_AGE"
+	EnvLoggerFileMaxBackups = "MINIO_LOGGER_FILE_MAX_BACKUPS"
+	EnvLoggerFileCompress   = "MINIO_LOGGER_FILE_COMPRESS"
+
+	EnvAuditFileEnable     = "MINIO_AUDIT_FILE_ENABLE"
+	EnvAuditFilePath       = "MINIO_AUDIT_FILE_PATH"
+	EnvAuditFileMaxSize    = "MINIO_AUDIT_FILE_MAX_SIZE"
+	EnvAuditFileMaxAge     = "MINIO_AUDIT_FILE_MAX_AGE"
+	EnvAuditFileMaxBackups = "MINIO_AUDIT_FILE_MAX_BACKUPS"
+	EnvAuditFileCompress   = "MINIO_AUDIT_FILE_COMPRESS"
 )
 
 // Default KVS for loggerHTTP and loggerAuditHTTP
@@ -113,6 +142,10 @@ var (
 			Key:   QueueSize,
 			Value: "100000",
 		},
+		config.KV{
+			Key:   QueueDir,
+			Value: "",
+		},
 	}
 
 	DefaultAuditWebhookKVS = config.KVS{
@@ -140,6 +173,10 @@ var (
 			Key:   QueueSize,
 			Value: "100000",
 		},
+		config.KV{
+			Key:   QueueDir,
+			Value: "",
+		},
 	}
 
 	DefaultAuditKafkaKVS = config.KVS{
@@ -195,6 +232,64 @@ var (
 			Key:   KafkaVersion,
 			Value: "",
 		},
+		config.KV{
+			Key:   KafkaQueueDir,
+			Value: "",
+		},
+	}
+
+	DefaultLoggerFileKVS = config.KVS{
+		config.KV{
+			Key:   config.Enable,
+			Value: config.EnableOff,
+		},
+		config.KV{
+			Key:   FilePath,
+			Value: "",
+		},
+		config.KV{
+			Key:   FileMaxSize,
+			Value: "100MiB",
+		},
+		config.KV{
+			Key:   FileMaxAge,
+			Value: "0s",
+		},
+		config.KV{
+			Key:   FileMaxBackups,
+			Value: "10",
+		},
+		config.KV{
+			Key:   FileCompress,
+			Value: config.EnableOn,
+		},
+	}
+
+	DefaultAuditFileKVS = config.KVS{
+		config.KV{
+			Key:   config.Enable,
+			Value: config.EnableOff,
+		},
+		config.KV{
+			Key:   FilePath,
+			Value: "",
+		},
+		config.KV{
+			Key:   FileMaxSize,
+			Value: "100MiB",
+		},
+		config.KV{
+			Key:   FileMaxAge,
+			Value: "0s",
+		},
+		config.KV{
+			Key:   FileMaxBackups,
+			Value: "10",
+		},
+		config.KV{
+			Key:   FileCompress,
+			Value: config.EnableOn,
+		},
 	}
 )
 
@@ -204,6 +299,8 @@ type Config struct {
 	HTTP         map[string]http.Config  `json:"http"`
 	AuditWebhook map[string]http.Config  `json:"audit"`
 	AuditKafka   map[string]kafka.Config `json:"audit_kafka"`
+	LoggerFile   map[string]file.Config  `json:"logger_file"`
+	AuditFile    map[string]file.Config  `json:"audit_file"`
 }
 
 // NewConfig - initialize new logger config.
@@ -216,6 +313,8 @@ func NewConfig() Config {
 		HTTP:         make(map[string]http.Config),
 		AuditWebhook: make(map[string]http.Config),
 		AuditKafka:   make(map[string]kafka.Config),
+		LoggerFile:   make(map[string]file.Config),
+		AuditFile:    make(map[string]file.Config),
 	}
 
 	return cfg
@@ -335,11 +434,22 @@ func lookupAuditKafkaConfig(scfg config.Config, cfg Config) (Config, error) {
 			versionEnv = versionEnv + config.Default + k
 		}
 
+		queueDirEnv := EnvKafkaQueueDir
+		if k != config.Default {
+			queueDirEnv = queueDirEnv + config.Default + k
+		}
+		queueDir := env.Get(queueDirEnv, kv.Get(KafkaQueueDir))
+		if queueDir != "" && !filepath.IsAbs(queueDir) {
+			return cfg, config.Errorf("kafka 'queue_dir' path should be absolute")
+		}
+
 		kafkaArgs := kafka.Config{
-			Enabled: enabled,
-			Brokers: brokers,
-			Topic:   env.Get(topicEnv, kv.Get(KafkaTopic)),
-			Version: env.Get(versionEnv, kv.Get(KafkaVersion)),
+			Enabled:  enabled,
+			Brokers:  brokers,
+			Topic:    env.Get(topicEnv, kv.Get(KafkaTopic)),
+			Version:  env.Get(versionEnv, kv.Get(KafkaVersion)),
+			Name:     k,
+			QueueDir: queueDir,
 		}
 
 		tlsEnableEnv := EnvKafkaTLS
@@ -395,6 +505,72 @@ func lookupAuditKafkaConfig(scfg config.Config, cfg Config) (Config, error) {
 	return cfg, nil
 }
 
+// fileEnvs - environment variables of a file logger sub-system.
+type fileEnvs struct {
+	enable, path, maxSize, maxAge, maxBackups, compress string
+}
+
+func lookupFileConfig(scfg config.Config, subSys string, defaultKVS config.KVS, envs fileEnvs) (map[string]file.Config, error) {
+	cfgs := make(map[string]file.Config)
+	for k, kv := range config.Merge(scfg[subSys], envs.enable, defaultKVS) {
+		envName := func(name string) string {
+			if k != config.Default {
+				return name + config.Default + k
+			}
+			return name
+		}
+		enabled, err := config.ParseBool(env.Get(envName(envs.enable), kv.Get(config.Enable)))
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			continue
+		}
+
+		path := env.Get(envName(envs.path), kv.Get(FilePath))
+		if path == "" {
+			return nil, config.Errorf("%s 'path' cannot be empty", subSys)
+		}
+		if !filepath.IsAbs(path) {
+			return nil, config.Errorf("%s 'path' should be absolute", subSys)
+		}
+		var maxSize uint64
+		if v := env.Get(envName(envs.maxSize), kv.Get(FileMaxSize)); v != "" {
+			if maxSize, err = humanize.ParseBytes(v); err != nil {
+				return nil, err
+			}
+		}
+		var maxAge time.Duration
+		if v := env.Get(envName(envs.maxAge), kv.Get(FileMaxAge)); v != "" {
+			if maxAge, err = time.ParseDuration(v); err != nil {
+				return nil, err
+			}
+		}
+		maxBackups, err := strconv.Atoi(env.Get(envName(envs.maxBackups), kv.Get(FileMaxBackups)))
+		if err != nil {
+			return nil, err
+		}
+		if maxBackups < 0 {
+			return nil, errors.New("invalid max_backups value")
+		}
+		compress, err := config.ParseBool(env.Get(envName(envs.compress), kv.Get(FileCompress)))
+		if err != nil {
+			return nil, err
+		}
+
+		cfgs[k] = file.Config{
+			Enabled:    true,
+			Name:       k,
+			Path:       path,
+			MaxSize:    int64(maxSize),
+			MaxAge:     maxAge,
+			MaxBackups: maxBackups,
+			Compress:   compress,
+		}
+	}
+	return cfgs, nil
+}
+
 func lookupLoggerWebhookConfig(scfg config.Config, cfg Config) (Config, error) {
 	envs := env.List(EnvLoggerWebhookEndpoint)
 	var loggerTargets []string
@@ -452,6 +628,14 @@ func lookupLoggerWebhookConfig(scfg config.Config, cfg Config) (Config, error) {
 		if queueSize <= 0 {
 			return cfg, errors.New("invalid queue_size value")
 		}
+		queueDirEnv := EnvLoggerWebhookQueueDir
+		if target != config.Default {
+			queueDirEnv = EnvLoggerWebhookQueueDir + config.Default + target
+		}
+		queueDir := env.Get(queueDirEnv, "")
+		if queueDir != "" && !filepath.IsAbs(queueDir) {
+			return cfg, errors.New("queue_dir path should be absolute")
+		}
 		cfg.HTTP[target] = http.Config{
 			Enabled:    true,
 			Endpoint:   env.Get(endpointEnv, ""),
@@ -459,6 +643,8 @@ func lookupLoggerWebhookConfig(scfg config.Config, cfg Config) (Config, error) {
 			ClientCert: env.Get(clientCertEnv, ""),
 			ClientKey:  env.Get(clientKeyEnv, ""),
 			QueueSize:  queueSize,
+			QueueDir:   queueDir,
+			Subsystem:  config.LoggerWebhookSubSys,
 			Name:       target,
 		}
 	}
@@ -495,6 +681,10 @@ func lookupLoggerWebhookConfig(scfg config.Config, cfg Config) (Config, error) {
 		if queueSize <= 0 {
 			return cfg, errors.New("invalid queue_size value")
 		}
+		queueDir := kv.Get(QueueDir)
+		if queueDir != "" && !filepath.IsAbs(queueDir) {
+			return cfg, errors.New("queue_dir path should be absolute")
+		}
 		cfg.HTTP[starget] = http.Config{
 			Enabled:    true,
 			Endpoint:   kv.Get(Endpoint),
@@ -502,6 +692,8 @@ func lookupLoggerWebhookConfig(scfg config.Config, cfg Config) (Config, error) {
 			ClientCert: kv.Get(ClientCert),
 			ClientKey:  kv.Get(ClientKey),
 			QueueSize:  queueSize,
+			QueueDir:   queueDir,
+			Subsystem:  config.LoggerWebhookSubSys,
 			Name:       starget,
 		}
 	}
@@ -565,6 +757,14 @@ func lookupAuditWebhookConfig(scfg config.Config, cfg Config) (Config, error) {
 		if queueSize <= 0 {
 			return cfg, errors.New("invalid queue_size value")
 		}
+		queueDirEnv := EnvAuditWebhookQueueDir
+		if target != config.Default {
+			queueDirEnv = EnvAuditWebhookQueueDir + config.Default + target
+		}
+		queueDir := env.Get(queueDirEnv, "")
+		if queueDir != "" && !filepath.IsAbs(queueDir) {
+			return cfg, errors.New("queue_dir path should be absolute")
+		}
 		cfg.AuditWebhook[target] = http.Config{
 			Enabled:    true,
 			Endpoint:   env.Get(endpointEnv, ""),
@@ -572,6 +772,8 @@ func lookupAuditWebhookConfig(scfg config.Config, cfg Config) (Config, error) {
 			ClientCert: env.Get(clientCertEnv, ""),
 			ClientKey:  env.Get(clientKeyEnv, ""),
 			QueueSize:  queueSize,
+			QueueDir:   queueDir,
+			Subsystem:  config.AuditWebhookSubSys,
 			Name:       target,
 		}
 	}
@@ -609,6 +811,10 @@ func lookupAuditWebhookConfig(scfg config.Config, cfg Config) (Config, error) {
 			return cfg, errors.New("invalid queue_size value")
 		}
 
+		queueDir := kv.Get(QueueDir)
+		if queueDir != "" && !filepath.IsAbs(queueDir) {
+			return cfg, errors.New("queue_dir path should be absolute")
+		}
 		cfg.AuditWebhook[starget] = http.Config{
 			Enabled:    true,
 			Endpoint:   kv.Get(Endpoint),
@@ -616,6 +822,8 @@ func lookupAuditWebhookConfig(scfg config.Config, cfg Config) (Config, error) {
 			ClientCert: kv.Get(ClientCert),
 			ClientKey:  kv.Get(ClientKey),
 			QueueSize:  queueSize,
+			QueueDir:   queueDir,
+			Subsystem:  config.AuditWebhookSubSys,
 			Name:       starget,
 		}
 	}
@@ -641,6 +849,28 @@ func LookupConfigForSubSys(scfg config.Config, subSys string) (cfg Config, err e
 		if cfg, err = lookupAuditKafkaConfig(scfg, cfg); err != nil {
 			return cfg, err
 		}
+	case config.LoggerFileSubSys:
+		if cfg.LoggerFile, err = lookupFileConfig(scfg, subSys, DefaultLoggerFileKVS, fileEnvs{
+			enable:     EnvLoggerFileEnable,
+			path:       EnvLoggerFilePath,
+			maxSize:    EnvLoggerFileMaxSize,
+			maxAge:     EnvLoggerFileMaxAge,
+			maxBackups: EnvLoggerFileMaxBackups,
+			compress:   EnvLoggerFileCompress,
+		}); err != nil {
+			return cfg, err
+		}
+	case config.AuditFileSubSys:
+		if cfg.AuditFile, err = lookupFileConfig(scfg, subSys, DefaultAuditFileKVS, fileEnvs{
+			enable:     EnvAuditFileEnable,
+			path:       EnvAuditFilePath,
+			maxSize:    EnvAuditFileMaxSize,
+			maxAge:     EnvAuditFileMaxAge,
+			maxBackups: EnvAuditFileMaxBackups,
+			compress:   EnvAuditFileCompress,
+		}); err != nil {
+			return cfg, err
+		}
 	}
 	return cfg, nil
 }
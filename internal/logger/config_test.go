@@ -0,0 +1,78 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/infobsmi/b33s/internal/config"
+	"github.com/infobsmi/b33s/internal/logger/target/http"
+)
+
+func TestWebhookTargetsSharedQueueDir(t *testing.T) {
+	queueDir := t.TempDir()
+	enable := func(defaults config.KVS) config.KVS {
+		kvs := defaults.Clone()
+		kvs.Set(config.Enable, config.EnableOn)
+		kvs.Set(Endpoint, "http://127.0.0.1:1") // offline, entries stay queued
+		kvs.Set(QueueDir, queueDir)
+		return kvs
+	}
+	scfg := config.Config{
+		config.LoggerWebhookSubSys: {config.Default: enable(DefaultLoggerWebhookKVS)},
+		config.AuditWebhookSubSys:  {config.Default: enable(DefaultAuditWebhookKVS)},
+	}
+
+	var targets []*http.Target
+	for _, subSys := range []string{config.LoggerWebhookSubSys, config.AuditWebhookSubSys} {
+		cfg, err := LookupConfigForSubSys(scfg, subSys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hcfg := cfg.HTTP[config.Default]
+		if subSys == config.AuditWebhookSubSys {
+			hcfg = cfg.AuditWebhook[config.Default]
+		}
+		hcfg.LogOnce = func(ctx context.Context, err error, id string, errKind ...interface{}) {}
+		target := http.New(hcfg)
+		if err = target.Init(); err != nil {
+			t.Fatal(err)
+		}
+		defer target.Cancel()
+		targets = append(targets, target)
+	}
+
+	// Each target only queues its own entries.
+	if err := targets[0].Send(map[string]string{"log": "server"}); err != nil {
+		t.Fatal(err)
+	}
+	for i, target := range targets {
+		if n := target.Stats().QueueLength; n != 1-i {
+			t.Errorf("%s: expected %d queued entries, got %d", target, 1-i, n)
+		}
+	}
+	dirs, err := os.ReadDir(queueDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) != 2 {
+		t.Fatalf("expected a queue directory per subsystem in %s, got %d", queueDir, len(dirs))
+	}
+}
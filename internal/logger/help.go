@@ -64,6 +64,12 @@ var (
 			Optional:    true,
 			Type:        "number",
 		},
+		config.HelpKV{
+			Key:         QueueDir,
+			Description: "staging dir for undelivered log entries e.g. '/home/logs', queued in memory if not set",
+			Optional:    true,
+			Type:        "path",
+		},
 		config.HelpKV{
 			Key:         config.Comment,
 			Description: config.DefaultComment,
@@ -113,6 +119,12 @@ var (
 			Optional:    true,
 			Type:        "number",
 		},
+		config.HelpKV{
+			Key:         QueueDir,
+			Description: "staging dir for undelivered audit entries e.g. '/home/audit', queued in memory if not set",
+			Optional:    true,
+			Type:        "path",
+		},
 		config.HelpKV{
 			Key:         config.Comment,
 			Description: config.DefaultComment,
@@ -204,6 +216,102 @@ var (
 			Optional:    true,
 			Type:        "string",
 		},
+		config.HelpKV{
+			Key:         KafkaQueueDir,
+			Description: "staging dir for undelivered audit entries e.g. '/home/audit', queued in memory if not set",
+			Optional:    true,
+			Type:        "path",
+		},
+		config.HelpKV{
+			Key:         config.Comment,
+			Description: config.DefaultComment,
+			Optional:    true,
+			Type:        "sentence",
+		},
+	}
+
+	HelpLoggerFile = config.HelpKVS{
+		config.HelpKV{
+			Key:         config.Enable,
+			Description: "set to 'on' to enable the logger file target",
+			Optional:    true,
+			Type:        "on|off",
+			Sensitive:   false,
+		},
+		config.HelpKV{
+			Key:         FilePath,
+			Description: "absolute path of the server log file e.g. '/var/log/b33s/server.log'",
+			Type:        "path",
+		},
+		config.HelpKV{
+			Key:         FileMaxSize,
+			Description: "rotate the log file once it exceeds this size, '0' disables size based rotation, defaults to '100MiB'",
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         FileMaxAge,
+			Description: "rotate the log file once it is older than this duration e.g. '24h', defaults to '0s' (no age based rotation)",
+			Optional:    true,
+			Type:        "duration",
+		},
+		config.HelpKV{
+			Key:         FileMaxBackups,
+			Description: "number of rotated log files to retain, '0' retains all, defaults to '10'",
+			Optional:    true,
+			Type:        "number",
+		},
+		config.HelpKV{
+			Key:         FileCompress,
+			Description: "set to 'on' to gzip rotated log files, defaults to 'on'",
+			Optional:    true,
+			Type:        "on|off",
+		},
+		config.HelpKV{
+			Key:         config.Comment,
+			Description: config.DefaultComment,
+			Optional:    true,
+			Type:        "sentence",
+		},
+	}
+
+	HelpAuditFile = config.HelpKVS{
+		config.HelpKV{
+			Key:         config.Enable,
+			Description: "set to 'on' to enable the audit file target",
+			Optional:    true,
+			Type:        "on|off",
+			Sensitive:   false,
+		},
+		config.HelpKV{
+			Key:         FilePath,
+			Description: "absolute path of the audit log file e.g. '/var/log/b33s/audit.log'",
+			Type:        "path",
+		},
+		config.HelpKV{
+			Key:         FileMaxSize,
+			Description: "rotate the log file once it exceeds this size, '0' disables size based rotation, defaults to '100MiB'",
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         FileMaxAge,
+			Description: "rotate the log file once it is older than this duration e.g. '24h', defaults to '0s' (no age based rotation)",
+			Optional:    true,
+			Type:        "duration",
+		},
+		config.HelpKV{
+			Key:         FileMaxBackups,
+			Description: "number of rotated log files to retain, '0' retains all, defaults to '10'",
+			Optional:    true,
+			Type:        "number",
+		},
+		config.HelpKV{
+			Key:         FileCompress,
+			Description: "set to 'on' to gzip rotated log files, defaults to 'on'",
+			Optional:    true,
+			Type:        "on|off",
+		},
 		config.HelpKV{
 			Key:         config.Comment,
 			Description: config.DefaultComment,
@@ -0,0 +1,371 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package file
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/infobsmi/b33s/internal/logger/target/types"
+)
+
+const (
+	// Size of the channel of log entries
+	queueSize = 10000
+
+	// Layout of the rotation time in the names of old log files
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+
+	compressSuffix = ".gz"
+)
+
+// Config file logger target
+type Config struct {
+	Enabled    bool          `json:"enabled"`
+	Name       string        `json:"name"`
+	Path       string        `json:"path"`
+	MaxSize    int64         `json:"maxSize"`
+	MaxAge     time.Duration `json:"maxAge"`
+	MaxBackups int           `json:"maxBackups"`
+	Compress   bool          `json:"compress"`
+
+	// Custom logger
+	LogOnce func(ctx context.Context, err error, id string, errKind ...interface{}) `json:"-"`
+}
+
+// Target implements logger.Target and writes the json format
+// of log entries, one per line, to a local file. The file is
+// rotated once it exceeds MaxSize bytes or is older than
+// MaxAge, old files are optionally gzipped and the newest
+// MaxBackups of them are retained.
+type Target struct {
+	totalMessages  int64
+	failedMessages int64
+
+	wg     sync.WaitGroup
+	doneCh chan struct{}
+
+	// Channel of log entries
+	logCh chan interface{}
+
+	// is the target online?
+	online bool
+
+	config Config
+
+	// Current log file, its size and when it was opened,
+	// only accessed by the writer routine after Init
+	file     *os.File
+	size     int64
+	openTime time.Time
+}
+
+// New initializes a new logger target which
+// writes logs to the specified file
+func New(config Config) *Target {
+	return &Target{
+		logCh:  make(chan interface{}, queueSize),
+		doneCh: make(chan struct{}),
+		config: config,
+		online: false,
+	}
+}
+
+// Endpoint returns the path of the log file
+func (h *Target) Endpoint() string {
+	return h.config.Path
+}
+
+func (h *Target) String() string {
+	return h.config.Name
+}
+
+// IsOnline returns true if the initialization was successful
+func (h *Target) IsOnline() bool {
+	return h.online
+}
+
+// Stats returns the target statistics.
+func (h *Target) Stats() types.TargetStats {
+	return types.TargetStats{
+		TotalMessages:  atomic.LoadInt64(&h.totalMessages),
+		FailedMessages: atomic.LoadInt64(&h.failedMessages),
+		QueueLength:    len(h.logCh),
+	}
+}
+
+// Init validate and initialize the file target
+func (h *Target) Init() error {
+	if h.online {
+		return nil
+	}
+	if !filepath.IsAbs(h.config.Path) {
+		return errors.New("log file path should be absolute")
+	}
+	if err := os.MkdirAll(filepath.Dir(h.config.Path), 0o750); err != nil {
+		return err
+	}
+	if err := h.open(); err != nil {
+		return err
+	}
+
+	h.online = true
+	h.wg.Add(1)
+	go h.startFileLogger()
+	return nil
+}
+
+// open opens the log file for appending.
+func (h *Target) open() error {
+	f, err := os.OpenFile(h.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	h.file = f
+	h.size = fi.Size()
+	h.openTime = time.Now()
+	return nil
+}
+
+func (h *Target) startFileLogger() {
+	defer func() {
+		h.file.Close()
+		h.wg.Done()
+	}()
+
+	for {
+		select {
+		case entry := <-h.logCh:
+			h.logEntry(entry)
+		case <-h.doneCh:
+			// Write the remaining entries before exiting.
+			for {
+				select {
+				case entry := <-h.logCh:
+					h.logEntry(entry)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (h *Target) logEntry(entry interface{}) {
+	atomic.AddInt64(&h.totalMessages, 1)
+	logJSON, err := json.Marshal(&entry)
+	if err != nil {
+		atomic.AddInt64(&h.failedMessages, 1)
+		return
+	}
+	logJSON = append(logJSON, '\n')
+
+	if h.shouldRotate(int64(len(logJSON))) {
+		if err = h.rotate(); err != nil {
+			h.config.LogOnce(context.Background(), err, h.config.Path)
+		}
+	}
+	if h.file == nil {
+		// Reopen after a failed rotation.
+		if err = h.open(); err != nil {
+			atomic.AddInt64(&h.failedMessages, 1)
+			h.config.LogOnce(context.Background(), err, h.config.Path)
+			return
+		}
+	}
+
+	n, err := h.file.Write(logJSON)
+	h.size += int64(n)
+	if err != nil {
+		atomic.AddInt64(&h.failedMessages, 1)
+		h.config.LogOnce(context.Background(), err, h.config.Path)
+	}
+}
+
+// shouldRotate returns whether the log file must be rotated
+// before writing n bytes.
+func (h *Target) shouldRotate(n int64) bool {
+	if h.size == 0 {
+		return false
+	}
+	if h.config.MaxSize > 0 && h.size+n > h.config.MaxSize {
+		return true
+	}
+	return h.config.MaxAge > 0 && time.Since(h.openTime) >= h.config.MaxAge
+}
+
+// rotate renames the log file to a backup named after the
+// rotation time, opens a new log file and removes old backups.
+func (h *Target) rotate() error {
+	if h.file != nil {
+		h.file.Close()
+		h.file = nil
+	}
+
+	// Names must be unique even if rotating faster than
+	// the resolution of the rotation time.
+	rotateTime := time.Now()
+	backup := backupName(h.config.Path, rotateTime)
+	for fileExists(backup) || fileExists(backup+compressSuffix) {
+		rotateTime = rotateTime.Add(time.Millisecond)
+		backup = backupName(h.config.Path, rotateTime)
+	}
+	if err := os.Rename(h.config.Path, backup); err != nil {
+		return err
+	}
+	if err := h.open(); err != nil {
+		return err
+	}
+
+	if h.config.Compress {
+		if err := compressFile(backup); err != nil {
+			return err
+		}
+	}
+	return h.removeOldBackups()
+}
+
+// backupName returns the name of the backup of a log file,
+// e.g. audit-2006-01-02T15-04-05.000.log for audit.log.
+func backupName(path string, t time.Time) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + t.UTC().Format(backupTimeFormat) + ext
+}
+
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// compressFile gzips a file and removes the original.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+compressSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(dst)
+	if _, err = io.Copy(gw, src); err == nil {
+		err = gw.Close()
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path + compressSuffix)
+		return err
+	}
+	return os.Remove(path)
+}
+
+// removeOldBackups removes all but the newest MaxBackups
+// backups of the log file.
+func (h *Target) removeOldBackups() error {
+	if h.config.MaxBackups <= 0 {
+		return nil
+	}
+
+	ext := filepath.Ext(h.config.Path)
+	prefix := strings.TrimSuffix(filepath.Base(h.config.Path), ext) + "-"
+	entries, err := os.ReadDir(filepath.Dir(h.config.Path))
+	if err != nil {
+		return err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimSuffix(name, compressSuffix), ext)
+		if _, err := time.Parse(backupTimeFormat, strings.TrimPrefix(stamp, prefix)); err != nil {
+			continue
+		}
+		backups = append(backups, name)
+	}
+	if len(backups) <= h.config.MaxBackups {
+		return nil
+	}
+
+	// Backup names sort by their rotation time.
+	sort.Strings(backups)
+	for _, name := range backups[:len(backups)-h.config.MaxBackups] {
+		if err := os.Remove(filepath.Join(filepath.Dir(h.config.Path), name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Send log message 'e' to file target.
+func (h *Target) Send(entry interface{}) error {
+	if !h.online {
+		return nil
+	}
+
+	select {
+	case <-h.doneCh:
+		return nil
+	default:
+	}
+
+	select {
+	case <-h.doneCh:
+	case h.logCh <- entry:
+	default:
+		// log channel is full, do not wait and return
+		// an error immediately to the caller
+		atomic.AddInt64(&h.totalMessages, 1)
+		atomic.AddInt64(&h.failedMessages, 1)
+		return errors.New("log buffer full")
+	}
+
+	return nil
+}
+
+// Cancel - cancels the target
+func (h *Target) Cancel() {
+	close(h.doneCh)
+	h.wg.Wait()
+}
+
+// Type - returns type of the target
+func (h *Target) Type() types.TargetType {
+	return types.TargetFile
+}
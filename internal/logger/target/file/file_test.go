@@ -0,0 +1,178 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33S Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package file
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testEntry struct {
+	Seq  int    `json:"seq"`
+	Data string `json:"data"`
+}
+
+func newTestTarget(t *testing.T, cfg Config) *Target {
+	t.Helper()
+	cfg.Enabled = true
+	cfg.Name = "test"
+	cfg.LogOnce = func(ctx context.Context, err error, id string, errKind ...interface{}) {
+		t.Error(err)
+	}
+	h := New(cfg)
+	if err := h.Init(); err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+// readEntries returns the sequence numbers of the entries in a log
+// file, which is gunzipped if compressed.
+func readEntries(t *testing.T, path string) []int {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if strings.HasSuffix(path, compressSuffix) {
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scanner = bufio.NewScanner(gr)
+	}
+	var seqs []int
+	for scanner.Scan() {
+		var entry testEntry
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		seqs = append(seqs, entry.Seq)
+	}
+	if err = scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return seqs
+}
+
+func TestFileTargetRotate(t *testing.T) {
+	testCases := []struct {
+		compress   bool
+		maxBackups int
+		backups    int
+	}{
+		{compress: false, maxBackups: 0, backups: 9},
+		{compress: true, maxBackups: 0, backups: 9},
+		{compress: true, maxBackups: 3, backups: 3},
+	}
+
+	for i, testCase := range testCases {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		h := newTestTarget(t, Config{
+			Path:       path,
+			MaxSize:    1024,
+			MaxBackups: testCase.maxBackups,
+			Compress:   testCase.compress,
+		})
+
+		// Each entry is about 220 bytes, 4 fit in a log file.
+		for seq := 0; seq < 40; seq++ {
+			h.logEntry(testEntry{Seq: seq, Data: strings.Repeat("a", 200)})
+		}
+		h.Cancel()
+
+		backups, err := filepath.Glob(filepath.Join(filepath.Dir(path), "audit-*"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(backups)
+		if len(backups) != testCase.backups {
+			t.Fatalf("Test %d: expected %d backups, got %v", i+1, testCase.backups, backups)
+		}
+
+		// The retained files hold the latest entries in order.
+		var seqs []int
+		for _, backup := range backups {
+			if strings.HasSuffix(backup, compressSuffix) != testCase.compress {
+				t.Fatalf("Test %d: unexpected backup %s", i+1, backup)
+			}
+			seqs = append(seqs, readEntries(t, backup)...)
+		}
+		seqs = append(seqs, readEntries(t, path)...)
+		for j, seq := range seqs {
+			if seq != 40-len(seqs)+j {
+				t.Fatalf("Test %d: expected the latest entries in order, got %v", i+1, seqs)
+			}
+		}
+	}
+}
+
+func TestFileTargetMaxAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	h := newTestTarget(t, Config{Path: path, MaxAge: time.Hour})
+	h.logEntry(testEntry{Seq: 0})
+	h.logEntry(testEntry{Seq: 1})
+
+	h.openTime = h.openTime.Add(-time.Hour)
+	h.logEntry(testEntry{Seq: 2})
+	h.Cancel()
+
+	backups, err := filepath.Glob(filepath.Join(filepath.Dir(path), "server-*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("Expected 1 backup, got %v", backups)
+	}
+	if seqs := readEntries(t, backups[0]); len(seqs) != 2 {
+		t.Fatalf("Expected 2 entries in the backup, got %v", seqs)
+	}
+	if seqs := readEntries(t, path); len(seqs) != 1 || seqs[0] != 2 {
+		t.Fatalf("Expected entry 2 in the log file, got %v", seqs)
+	}
+}
+
+func TestFileTargetSend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "server.log")
+	h := newTestTarget(t, Config{Path: path})
+	for seq := 0; seq < 100; seq++ {
+		if err := h.Send(testEntry{Seq: seq}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Cancel writes the queued entries.
+	h.Cancel()
+
+	if seqs := readEntries(t, path); len(seqs) != 100 {
+		t.Fatalf("Expected 100 entries, got %d", len(seqs))
+	}
+	if stats := h.Stats(); stats.TotalMessages != 100 || stats.FailedMessages != 0 {
+		t.Fatalf("Unexpected stats %+v", stats)
+	}
+}
@@ -24,6 +24,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -31,6 +33,7 @@ import (
 
 	xhttp "github.com/infobsmi/b33s/internal/http"
 	"github.com/infobsmi/b33s/internal/logger/target/types"
+	"github.com/infobsmi/b33s/internal/store"
 )
 
 const (
@@ -39,6 +42,11 @@ const (
 
 	// maxWorkers is the maximum number of concurrent operations.
 	maxWorkers = 8
+
+	// Interval to retry sending the queued log entries
+	retryInterval = 3 * time.Second
+
+	storePrefix = "b33s-http-"
 )
 
 // Config http logger target
@@ -51,6 +59,8 @@ type Config struct {
 	ClientCert string            `json:"clientCert"`
 	ClientKey  string            `json:"clientKey"`
 	QueueSize  int               `json:"queueSize"`
+	QueueDir   string            `json:"queueDir"`
+	Subsystem  string            `json:"-"` // keeps the queues of targets with the same name apart
 	Transport  http.RoundTripper `json:"-"`
 
 	// Custom logger
@@ -61,7 +71,9 @@ type Config struct {
 // format of a log entry to the configured http endpoint.
 // An internal buffer of logs is maintained but when the
 // buffer is full, new logs are just ignored and an error
-// is returned to the caller.
+// is returned to the caller. If a queue directory is
+// configured, logs are persisted on disk instead and sent
+// once the endpoint is reachable.
 type Target struct {
 	totalMessages  int64
 	failedMessages int64
@@ -77,8 +89,14 @@ type Target struct {
 	// Channel of log entries
 	logCh chan interface{}
 
-	// is the target online?
-	online bool
+	// Store of log entries and the channel notifying the
+	// sender of new entries, if a queue directory is set
+	store   store.Store[json.RawMessage]
+	storeCh chan struct{}
+
+	// is the target online? If a queue directory is set, it
+	// follows the results of sending the queued entries.
+	online int32
 
 	config Config
 	client *http.Client
@@ -93,22 +111,82 @@ func (h *Target) String() string {
 	return h.config.Name
 }
 
-// IsOnline returns true if the initialization was successful
+// IsOnline returns true if the initialization was successful, or
+// if the last queued entry was sent if a queue directory is set.
 func (h *Target) IsOnline() bool {
-	return h.online
+	return atomic.LoadInt32(&h.online) == 1
+}
+
+func (h *Target) setOnline(online bool) {
+	if online {
+		atomic.StoreInt32(&h.online, 1)
+	} else {
+		atomic.StoreInt32(&h.online, 0)
+	}
 }
 
 // Stats returns the target statistics.
 func (h *Target) Stats() types.TargetStats {
+	queueLength := len(h.logCh)
+	if h.store != nil {
+		queueLength = h.store.Len()
+	}
 	return types.TargetStats{
 		TotalMessages:  atomic.LoadInt64(&h.totalMessages),
 		FailedMessages: atomic.LoadInt64(&h.failedMessages),
-		QueueLength:    len(h.logCh),
+		QueueLength:    queueLength,
 	}
 }
 
 // Init validate and initialize the http target
 func (h *Target) Init() error {
+	h.client = &http.Client{Transport: h.config.Transport}
+	err := h.ping()
+
+	if h.config.QueueDir != "" {
+		// Log entries are queued on disk while the
+		// endpoint is offline.
+		if h.store == nil {
+			queue, oErr := store.OpenQueueStore[json.RawMessage](store.Config{
+				Directory:  h.storeDir(),
+				EntryLimit: uint64(h.config.QueueSize),
+			})
+			if oErr != nil {
+				return oErr
+			}
+			h.store = queue
+			h.startQueueSender()
+		}
+		// The sender updates the state once it sends an entry.
+		h.setOnline(err == nil)
+		if err != nil {
+			h.config.LogOnce(context.Background(), err, h.config.Endpoint)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	h.lastStarted = time.Now()
+	h.setOnline(true)
+	atomic.AddInt64(&h.workers, 1)
+	go h.startHTTPLogger()
+	return nil
+}
+
+// storeDir returns the directory of the queued log entries, logger and
+// audit webhook targets share their names and possibly their queue_dir.
+func (h *Target) storeDir() string {
+	name := storePrefix + h.config.Name
+	if h.config.Subsystem != "" {
+		name = storePrefix + h.config.Subsystem + "-" + h.config.Name
+	}
+	return filepath.Join(h.config.QueueDir, name)
+}
+
+// ping checks if the endpoint accepts log entries.
+func (h *Target) ping() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*webhookCallTimeout)
 	defer cancel()
 
@@ -127,12 +205,10 @@ func (h *Target) Init() error {
 		req.Header.Set("Authorization", h.config.AuthToken)
 	}
 
-	client := http.Client{Transport: h.config.Transport}
-	resp, err := client.Do(req)
+	resp, err := h.client.Do(req)
 	if err != nil {
 		return err
 	}
-	h.client = &client
 
 	// Drain any response.
 	xhttp.DrainBody(resp.Body)
@@ -146,11 +222,6 @@ func (h *Target) Init() error {
 		return fmt.Errorf("%s returned '%s', please check your endpoint configuration",
 			h.config.Endpoint, resp.Status)
 	}
-
-	h.lastStarted = time.Now()
-	h.online = true
-	atomic.AddInt64(&h.workers, 1)
-	go h.startHTTPLogger()
 	return nil
 }
 
@@ -168,14 +239,20 @@ func (h *Target) logEntry(entry interface{}) {
 		return
 	}
 
+	if err = h.send(logJSON); err != nil {
+		atomic.AddInt64(&h.failedMessages, 1)
+		h.config.LogOnce(context.Background(), err, h.config.Endpoint)
+	}
+}
+
+// send posts a json log entry to the endpoint.
+func (h *Target) send(logJSON []byte) error {
 	ctx, cancel := context.WithTimeout(context.Background(), webhookCallTimeout)
 	defer cancel()
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
 		h.config.Endpoint, bytes.NewReader(logJSON))
 	if err != nil {
-		h.config.LogOnce(ctx, fmt.Errorf("%s returned '%w', please check your endpoint configuration", h.config.Endpoint, err), h.config.Endpoint)
-		atomic.AddInt64(&h.failedMessages, 1)
-		return
+		return fmt.Errorf("%s returned '%w', please check your endpoint configuration", h.config.Endpoint, err)
 	}
 	req.Header.Set(xhttp.ContentType, "application/json")
 	req.Header.Set(xhttp.B33SVersion, xhttp.GlobalB33SVersion)
@@ -191,23 +268,78 @@ func (h *Target) logEntry(entry interface{}) {
 
 	resp, err := h.client.Do(req)
 	if err != nil {
-		atomic.AddInt64(&h.failedMessages, 1)
-		h.config.LogOnce(ctx, fmt.Errorf("%s returned '%w', please check your endpoint configuration", h.config.Endpoint, err), h.config.Endpoint)
-		return
+		return fmt.Errorf("%s returned '%w', please check your endpoint configuration", h.config.Endpoint, err)
 	}
 
 	// Drain any response.
 	xhttp.DrainBody(resp.Body)
 
 	if !acceptedResponseStatusCode(resp.StatusCode) {
-		atomic.AddInt64(&h.failedMessages, 1)
 		switch resp.StatusCode {
 		case http.StatusForbidden:
-			h.config.LogOnce(ctx, fmt.Errorf("%s returned '%s', please check if your auth token is correctly set", h.config.Endpoint, resp.Status), h.config.Endpoint)
+			return fmt.Errorf("%s returned '%s', please check if your auth token is correctly set", h.config.Endpoint, resp.Status)
 		default:
-			h.config.LogOnce(ctx, fmt.Errorf("%s returned '%s', please check your endpoint configuration", h.config.Endpoint, resp.Status), h.config.Endpoint)
+			return fmt.Errorf("%s returned '%s', please check your endpoint configuration", h.config.Endpoint, resp.Status)
 		}
 	}
+	return nil
+}
+
+// startQueueSender starts a routine which sends the queued log
+// entries, oldest first, and retries while the endpoint is offline.
+func (h *Target) startQueueSender() {
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+
+		retryTicker := time.NewTicker(retryInterval)
+		defer retryTicker.Stop()
+
+		for {
+			keys, err := h.store.List()
+			if err != nil {
+				h.config.LogOnce(context.Background(), err, h.config.Endpoint)
+			}
+			for _, key := range keys {
+				if err = h.sendFromStore(key); err != nil {
+					h.config.LogOnce(context.Background(), err, h.config.Endpoint)
+					break
+				}
+				select {
+				case <-h.doneCh:
+					return
+				default:
+				}
+			}
+
+			select {
+			case <-h.storeCh:
+			case <-retryTicker.C:
+			case <-h.doneCh:
+				return
+			}
+		}
+	}()
+}
+
+// sendFromStore sends a queued log entry and removes it from the store.
+func (h *Target) sendFromStore(key string) error {
+	logJSON, err := h.store.Get(key)
+	if err != nil {
+		// The entry is already sent or unreadable, in which
+		// case the store removed it.
+		if !os.IsNotExist(err) {
+			atomic.AddInt64(&h.failedMessages, 1)
+		}
+		return nil
+	}
+	if err = h.send(logJSON); err != nil {
+		h.setOnline(false)
+		return err
+	}
+	h.setOnline(true)
+	atomic.AddInt64(&h.totalMessages, 1)
+	return h.store.Del(key)
 }
 
 func (h *Target) startHTTPLogger() {
@@ -236,10 +368,10 @@ func (h *Target) startHTTPLogger() {
 // sends log over http to the specified endpoint
 func New(config Config) *Target {
 	h := &Target{
-		logCh:  make(chan interface{}, config.QueueSize),
-		doneCh: make(chan struct{}),
-		config: config,
-		online: false,
+		logCh:   make(chan interface{}, config.QueueSize),
+		storeCh: make(chan struct{}, 1),
+		doneCh:  make(chan struct{}),
+		config:  config,
 	}
 
 	return h
@@ -247,16 +379,21 @@ func New(config Config) *Target {
 
 // Send log message 'e' to http target.
 func (h *Target) Send(entry interface{}) error {
-	if !h.online {
-		return nil
-	}
-
 	select {
 	case <-h.doneCh:
 		return nil
 	default:
 	}
 
+	// Entries are queued while the endpoint is offline.
+	if h.store != nil {
+		return h.queue(entry)
+	}
+
+	if !h.IsOnline() {
+		return nil
+	}
+
 	select {
 	case <-h.doneCh:
 	case h.logCh <- entry:
@@ -291,6 +428,28 @@ func (h *Target) Send(entry interface{}) error {
 	return nil
 }
 
+// queue persists log message 'e' in the store of the target.
+func (h *Target) queue(entry interface{}) error {
+	logJSON, err := json.Marshal(&entry)
+	if err != nil {
+		atomic.AddInt64(&h.totalMessages, 1)
+		atomic.AddInt64(&h.failedMessages, 1)
+		return err
+	}
+	if err = h.store.Put(logJSON); err != nil {
+		atomic.AddInt64(&h.totalMessages, 1)
+		atomic.AddInt64(&h.failedMessages, 1)
+		return err
+	}
+
+	// Wake up the sender.
+	select {
+	case h.storeCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
 // Cancel - cancels the target
 func (h *Target) Cancel() {
 	close(h.doneCh)
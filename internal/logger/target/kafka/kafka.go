@@ -24,17 +24,28 @@ import (
 	"encoding/json"
 	"errors"
 	"net"
+	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/Shopify/sarama"
 	saramatls "github.com/Shopify/sarama/tools/tls"
 
 	"github.com/infobsmi/b33s/internal/logger/message/audit"
 	"github.com/infobsmi/b33s/internal/logger/target/types"
+	"github.com/infobsmi/b33s/internal/store"
 	xnet "github.com/minio/pkg/net"
 )
 
+const (
+	// Interval to retry sending the queued log entries
+	retryInterval = 3 * time.Second
+
+	storePrefix = "b33s-kafka-"
+)
+
 // Target - Kafka target.
 type Target struct {
 	totalMessages  int64
@@ -46,8 +57,14 @@ type Target struct {
 	// Channel of log entries
 	logCh chan audit.Entry
 
-	// is the target online?
-	online bool
+	// Store of log entries and the channel notifying the
+	// sender of new entries, if a queue directory is set
+	store   store.Store[audit.Entry]
+	storeCh chan struct{}
+
+	// is the target online? If a queue directory is set, it
+	// follows the results of sending the queued entries.
+	online int32
 
 	producer sarama.SyncProducer
 	kconfig  Config
@@ -56,10 +73,6 @@ type Target struct {
 
 // Send log message 'e' to kafka target.
 func (h *Target) Send(entry interface{}) error {
-	if !h.online {
-		return nil
-	}
-
 	select {
 	case <-h.doneCh:
 		return nil
@@ -67,6 +80,13 @@ func (h *Target) Send(entry interface{}) error {
 	}
 
 	if e, ok := entry.(audit.Entry); ok {
+		// Entries are queued while the brokers are offline.
+		if h.store != nil {
+			return h.queue(e)
+		}
+		if !h.IsOnline() {
+			return nil
+		}
 		select {
 		case <-h.doneCh:
 		case h.logCh <- e:
@@ -82,12 +102,35 @@ func (h *Target) Send(entry interface{}) error {
 	return nil
 }
 
+// queue persists log message 'e' in the store of the target.
+func (h *Target) queue(entry audit.Entry) error {
+	if err := h.store.Put(entry); err != nil {
+		atomic.AddInt64(&h.totalMessages, 1)
+		atomic.AddInt64(&h.failedMessages, 1)
+		return err
+	}
+
+	// Wake up the sender.
+	select {
+	case h.storeCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
 func (h *Target) logEntry(entry audit.Entry) {
 	atomic.AddInt64(&h.totalMessages, 1)
+	if err := h.send(entry); err != nil {
+		atomic.AddInt64(&h.failedMessages, 1)
+		h.kconfig.LogOnce(context.Background(), err, h.kconfig.Topic)
+	}
+}
+
+// send sends a log entry to the topic.
+func (h *Target) send(entry audit.Entry) error {
 	logJSON, err := json.Marshal(&entry)
 	if err != nil {
-		atomic.AddInt64(&h.failedMessages, 1)
-		return
+		return err
 	}
 	msg := sarama.ProducerMessage{
 		Topic: h.kconfig.Topic,
@@ -96,11 +139,74 @@ func (h *Target) logEntry(entry audit.Entry) {
 	}
 
 	_, _, err = h.producer.SendMessage(&msg)
+	return err
+}
+
+// startQueueSender starts a routine which sends the queued log
+// entries, oldest first, and reconnects while the brokers are offline.
+func (h *Target) startQueueSender() {
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+
+		retryTicker := time.NewTicker(retryInterval)
+		defer retryTicker.Stop()
+
+		for {
+			if h.producer == nil {
+				if err := h.connect(); err != nil {
+					h.setOnline(false)
+					h.kconfig.LogOnce(context.Background(), err, h.kconfig.Topic)
+				}
+			}
+			if h.producer != nil {
+				h.sendFromStore()
+			}
+
+			select {
+			case <-h.storeCh:
+			case <-retryTicker.C:
+			case <-h.doneCh:
+				return
+			}
+		}
+	}()
+}
+
+// sendFromStore sends the queued log entries until sending fails.
+func (h *Target) sendFromStore() {
+	keys, err := h.store.List()
 	if err != nil {
-		atomic.AddInt64(&h.failedMessages, 1)
 		h.kconfig.LogOnce(context.Background(), err, h.kconfig.Topic)
 		return
 	}
+	for _, key := range keys {
+		entry, err := h.store.Get(key)
+		if err != nil {
+			// The entry is already sent or unreadable, in
+			// which case the store removed it.
+			if !os.IsNotExist(err) {
+				atomic.AddInt64(&h.failedMessages, 1)
+			}
+			continue
+		}
+		if err = h.send(entry); err != nil {
+			h.setOnline(false)
+			h.kconfig.LogOnce(context.Background(), err, h.kconfig.Topic)
+			return
+		}
+		h.setOnline(true)
+		atomic.AddInt64(&h.totalMessages, 1)
+		if err = h.store.Del(key); err != nil {
+			h.kconfig.LogOnce(context.Background(), err, h.kconfig.Topic)
+		}
+
+		select {
+		case <-h.doneCh:
+			return
+		default:
+		}
+	}
 }
 
 func (h *Target) startKakfaLogger() {
@@ -123,11 +229,13 @@ func (h *Target) startKakfaLogger() {
 
 // Config - kafka target arguments.
 type Config struct {
-	Enabled bool        `json:"enable"`
-	Brokers []xnet.Host `json:"brokers"`
-	Topic   string      `json:"topic"`
-	Version string      `json:"version"`
-	TLS     struct {
+	Enabled  bool        `json:"enable"`
+	Brokers  []xnet.Host `json:"brokers"`
+	Topic    string      `json:"topic"`
+	Version  string      `json:"version"`
+	Name     string      `json:"name"`
+	QueueDir string      `json:"queueDir"`
+	TLS      struct {
 		Enable        bool               `json:"enable"`
 		RootCAs       *x509.CertPool     `json:"-"`
 		SkipVerify    bool               `json:"skipVerify"`
@@ -163,10 +271,14 @@ func (k Config) pingBrokers() error {
 
 // Stats returns the target statistics.
 func (h *Target) Stats() types.TargetStats {
+	queueLength := len(h.logCh)
+	if h.store != nil {
+		queueLength = h.store.Len()
+	}
 	return types.TargetStats{
 		TotalMessages:  atomic.LoadInt64(&h.totalMessages),
 		FailedMessages: atomic.LoadInt64(&h.failedMessages),
-		QueueLength:    len(h.logCh),
+		QueueLength:    queueLength,
 	}
 }
 
@@ -180,9 +292,18 @@ func (h *Target) String() string {
 	return "kafka"
 }
 
-// IsOnline returns true if the initialization was successful
+// IsOnline returns true if the initialization was successful, or
+// if the last queued entry was sent if a queue directory is set.
 func (h *Target) IsOnline() bool {
-	return h.online
+	return atomic.LoadInt32(&h.online) == 1
+}
+
+func (h *Target) setOnline(online bool) {
+	if online {
+		atomic.StoreInt32(&h.online, 1)
+	} else {
+		atomic.StoreInt32(&h.online, 0)
+	}
 }
 
 // Init initialize kafka target
@@ -190,6 +311,12 @@ func (h *Target) Init() error {
 	if !h.kconfig.Enabled {
 		return nil
 	}
+	if h.store != nil {
+		// Already initialized, the queue sender reconnects
+		// to the brokers.
+		h.setOnline(h.kconfig.pingBrokers() == nil)
+		return nil
+	}
 	if len(h.kconfig.Brokers) == 0 {
 		return errors.New("no broker address found")
 	}
@@ -198,9 +325,6 @@ func (h *Target) Init() error {
 			return err
 		}
 	}
-	if err := h.kconfig.pingBrokers(); err != nil {
-		return err
-	}
 
 	sconfig := sarama.NewConfig()
 	if h.kconfig.Version != "" {
@@ -233,19 +357,46 @@ func (h *Target) Init() error {
 
 	h.config = sconfig
 
+	if h.kconfig.QueueDir != "" {
+		// Log entries are queued on disk while the
+		// brokers are offline.
+		queue, err := store.OpenQueueStore[audit.Entry](store.Config{
+			Directory: filepath.Join(h.kconfig.QueueDir, storePrefix+h.kconfig.Name),
+		})
+		if err != nil {
+			return err
+		}
+		h.store = queue
+		h.startQueueSender()
+		// The sender updates the state once it sends an entry.
+		h.setOnline(h.kconfig.pingBrokers() == nil)
+		return nil
+	}
+
+	if err = h.connect(); err != nil {
+		return err
+	}
+	h.setOnline(true)
+	go h.startKakfaLogger()
+	return nil
+}
+
+// connect creates the producer if a broker is reachable.
+func (h *Target) connect() error {
+	if err := h.kconfig.pingBrokers(); err != nil {
+		return err
+	}
+
 	var brokers []string
 	for _, broker := range h.kconfig.Brokers {
 		brokers = append(brokers, broker.String())
 	}
 
-	producer, err := sarama.NewSyncProducer(brokers, sconfig)
+	producer, err := sarama.NewSyncProducer(brokers, h.config)
 	if err != nil {
 		return err
 	}
-
 	h.producer = producer
-	h.online = true
-	go h.startKakfaLogger()
 	return nil
 }
 
@@ -261,9 +412,9 @@ func (h *Target) Cancel() {
 func New(config Config) *Target {
 	target := &Target{
 		logCh:   make(chan audit.Entry, 10000),
+		storeCh: make(chan struct{}, 1),
 		doneCh:  make(chan struct{}),
 		kconfig: config,
-		online:  false,
 	}
 	return target
 }
@@ -0,0 +1,59 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/infobsmi/b33s/internal/logger/message/audit"
+	xnet "github.com/minio/pkg/net"
+)
+
+func TestQueuedTargetOffline(t *testing.T) {
+	broker, err := xnet.ParseHost("127.0.0.1:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := New(Config{
+		Enabled:  true,
+		Brokers:  []xnet.Host{*broker},
+		Topic:    "audit",
+		Name:     "_",
+		QueueDir: t.TempDir(),
+		LogOnce:  func(ctx context.Context, err error, id string, errKind ...interface{}) {},
+	})
+	if err = target.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer target.Cancel()
+
+	// Entries are queued while no broker is reachable.
+	if target.IsOnline() {
+		t.Fatal("expected the target to be offline")
+	}
+	if err = target.Send(audit.Entry{RequestID: "request"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := target.Stats().QueueLength; n != 1 {
+		t.Fatalf("expected 1 queued entry, got %d", n)
+	}
+	if err = target.Init(); err != nil || target.IsOnline() {
+		t.Fatalf("expected the target to stay offline, got %v", err)
+	}
+}
@@ -11,11 +11,12 @@ func _() {
 	_ = x[TargetConsole-1]
 	_ = x[TargetHTTP-2]
 	_ = x[TargetKafka-3]
+	_ = x[TargetFile-4]
 }
 
-const _TargetType_name = "ConsoleHTTPKafka"
+const _TargetType_name = "ConsoleHTTPKafkaFile"
 
-var _TargetType_index = [...]uint8{0, 7, 11, 16}
+var _TargetType_index = [...]uint8{0, 7, 11, 16, 20}
 
 func (i TargetType) String() string {
 	i -= 1
@@ -17,7 +17,7 @@
 
 package types
 
-// TargetType indicates type of the target e.g. console, http, kafka
+// TargetType indicates type of the target e.g. console, http, kafka, file
 type TargetType uint8
 
 //go:generate stringer -type=TargetType -trimprefix=Target $GOFILE
@@ -28,6 +28,7 @@ const (
 	TargetConsole
 	TargetHTTP
 	TargetKafka
+	TargetFile
 )
 
 // TargetStats contains statistics for a target.
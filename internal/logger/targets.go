@@ -22,6 +22,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/infobsmi/b33s/internal/logger/target/file"
 	"github.com/infobsmi/b33s/internal/logger/target/http"
 	"github.com/infobsmi/b33s/internal/logger/target/kafka"
 	"github.com/infobsmi/b33s/internal/logger/target/types"
@@ -61,8 +62,9 @@ func TargetStatus(h Target) madmin.Status {
 	if h.IsOnline() {
 		return madmin.Status{Status: string(madmin.ItemOnline)}
 	}
-	// Previous initialization had failed. Try again.
-	if e := h.Init(); e == nil {
+	// Previous initialization had failed. Try again, a target
+	// queueing its entries may initialize while still offline.
+	if e := h.Init(); e == nil && h.IsOnline() {
 		return madmin.Status{Status: string(madmin.ItemOnline)}
 	}
 	return madmin.Status{Status: string(madmin.ItemOffline)}
@@ -178,6 +180,23 @@ func initKafkaTargets(cfgMap map[string]kafka.Config) ([]Target, []error) {
 	return tgts, errs
 }
 
+func initFileTargets(cfgMap map[string]file.Config) ([]Target, []error) {
+	tgts := []Target{}
+	errs := []error{}
+	for _, l := range cfgMap {
+		if l.Enabled {
+			t := file.New(l)
+			tgts = append(tgts, t)
+
+			e := t.Init()
+			if e != nil {
+				errs = append(errs, e)
+			}
+		}
+	}
+	return tgts, errs
+}
+
 // Split targets into two groups:
 //
 //	group1 contains all targets of type t
@@ -204,12 +223,28 @@ func UpdateSystemTargets(cfg Config) []error {
 	newTgts, errs := initSystemTargets(cfg.HTTP)
 
 	swapSystemMuRW.Lock()
-	consoleTargets, otherTargets := splitTargets(systemTargets, types.TargetConsole)
-	newTgts = append(newTgts, consoleTargets...)
+	// Retain console and file targets
+	oldTgts, otherTgts := splitTargets(systemTargets, types.TargetHTTP)
+	newTgts = append(newTgts, otherTgts...)
 	systemTargets = newTgts
 	swapSystemMuRW.Unlock()
 
-	cancelTargets(otherTargets) // cancel running targets
+	cancelTargets(oldTgts) // cancel running targets
+	return errs
+}
+
+// UpdateSystemFileTargets swaps file logger targets with newly loaded ones from the cfg
+func UpdateSystemFileTargets(cfg Config) []error {
+	newFileTgts, errs := initFileTargets(cfg.LoggerFile)
+
+	swapSystemMuRW.Lock()
+	// Retain console and webhook targets
+	oldFileTgts, otherTgts := splitTargets(systemTargets, types.TargetFile)
+	newFileTgts = append(newFileTgts, otherTgts...)
+	systemTargets = newFileTgts
+	swapSystemMuRW.Unlock()
+
+	cancelTargets(oldFileTgts) // cancel running targets
 	return errs
 }
 
@@ -218,7 +253,7 @@ func UpdateAuditWebhookTargets(cfg Config) []error {
 	newWebhookTgts, errs := initSystemTargets(cfg.AuditWebhook)
 
 	swapAuditMuRW.Lock()
-	// Retain kafka targets
+	// Retain kafka and file targets
 	oldWebhookTgts, otherTgts := splitTargets(auditTargets, types.TargetHTTP)
 	newWebhookTgts = append(newWebhookTgts, otherTgts...)
 	auditTargets = newWebhookTgts
@@ -233,7 +268,7 @@ func UpdateAuditKafkaTargets(cfg Config) []error {
 	newKafkaTgts, errs := initKafkaTargets(cfg.AuditKafka)
 
 	swapAuditMuRW.Lock()
-	// Retain webhook targets
+	// Retain webhook and file targets
 	oldKafkaTgts, otherTgts := splitTargets(auditTargets, types.TargetKafka)
 	newKafkaTgts = append(newKafkaTgts, otherTgts...)
 	auditTargets = newKafkaTgts
@@ -242,3 +277,18 @@ func UpdateAuditKafkaTargets(cfg Config) []error {
 	cancelTargets(oldKafkaTgts) // cancel running targets
 	return errs
 }
+
+// UpdateAuditFileTargets swaps audit file targets with newly loaded ones from the cfg
+func UpdateAuditFileTargets(cfg Config) []error {
+	newFileTgts, errs := initFileTargets(cfg.AuditFile)
+
+	swapAuditMuRW.Lock()
+	// Retain webhook and kafka targets
+	oldFileTgts, otherTgts := splitTargets(auditTargets, types.TargetFile)
+	newFileTgts = append(newFileTgts, otherTgts...)
+	auditTargets = newFileTgts
+	swapAuditMuRW.Unlock()
+
+	cancelTargets(oldFileTgts) // cancel running targets
+	return errs
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package arrow
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// The metadata of Arrow IPC messages is encoded as flatbuffers. Only a
+// handful of tables are needed to write a schema and record batches,
+// they are encoded with the minimal serializer below. It writes every
+// object before the objects it refers to, as flatbuffers offsets must
+// point forward.
+
+// fbObject is a flatbuffers table, vector or string.
+type fbObject interface {
+	// write appends the object and returns its position.
+	write(b *fbBuilder) int
+}
+
+// fbField is a field of a table, either an inline scalar or a
+// reference to another object. The zero value is an absent field.
+type fbField struct {
+	scalar []byte
+	ref    fbObject
+}
+
+func (f fbField) size() int {
+	if f.ref != nil {
+		return 4
+	}
+	return len(f.scalar)
+}
+
+func fbUint8(v uint8) fbField {
+	return fbField{scalar: []byte{v}}
+}
+
+func fbBool(v bool) fbField {
+	if v {
+		return fbUint8(1)
+	}
+	return fbUint8(0)
+}
+
+func fbInt16(v int16) fbField {
+	return fbField{scalar: appendUint16(nil, uint16(v))}
+}
+
+func fbInt32(v int32) fbField {
+	return fbField{scalar: appendUint32(nil, uint32(v))}
+}
+
+func fbInt64(v int64) fbField {
+	return fbField{scalar: appendUint64(nil, uint64(v))}
+}
+
+func fbRef(obj fbObject) fbField {
+	return fbField{ref: obj}
+}
+
+// fbTable is a table, the index of a field is its field id.
+type fbTable []fbField
+
+func (t fbTable) write(b *fbBuilder) int {
+	// The inline fields follow the offset to the vtable, ordered by
+	// decreasing size to avoid padding.
+	ids := make([]int, 0, len(t))
+	for id, f := range t {
+		if f.size() > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.SliceStable(ids, func(i, j int) bool {
+		return t[ids[i]].size() > t[ids[j]].size()
+	})
+	offsets := make([]int, len(t))
+	size := 4
+	for _, id := range ids {
+		sz := t[id].size()
+		size = (size + sz - 1) / sz * sz
+		offsets[id] = size
+		size += sz
+	}
+
+	b.pad(2)
+	vtable := len(b.buf)
+	b.buf = appendUint16(b.buf, uint16(4+2*len(t)))
+	b.buf = appendUint16(b.buf, uint16(size))
+	for _, off := range offsets {
+		b.buf = appendUint16(b.buf, uint16(off))
+	}
+
+	// Tables are 8 byte aligned, such that the inline scalars are
+	// aligned to their size.
+	b.pad(8)
+	start := len(b.buf)
+	b.buf = append(b.buf, make([]byte, size)...)
+	binary.LittleEndian.PutUint32(b.buf[start:], uint32(start-vtable))
+	for _, id := range ids {
+		if t[id].ref == nil {
+			copy(b.buf[start+offsets[id]:], t[id].scalar)
+		}
+	}
+	for _, id := range ids {
+		if t[id].ref != nil {
+			b.putOffset(start+offsets[id], t[id].ref.write(b))
+		}
+	}
+	return start
+}
+
+// fbVector is a vector of tables.
+type fbVector []fbObject
+
+func (v fbVector) write(b *fbBuilder) int {
+	b.pad(4)
+	start := len(b.buf)
+	b.buf = appendUint32(b.buf, uint32(len(v)))
+	b.buf = append(b.buf, make([]byte, 4*len(v))...)
+	for i, obj := range v {
+		b.putOffset(start+4+4*i, obj.write(b))
+	}
+	return start
+}
+
+// fbStructs is a vector of structs of 64-bit integers, the structs
+// are encoded in data.
+type fbStructs struct {
+	n    int
+	data []byte
+}
+
+func (v fbStructs) write(b *fbBuilder) int {
+	// The structs following the length are 8 byte aligned.
+	for (len(b.buf)+4)%8 != 0 {
+		b.buf = append(b.buf, 0)
+	}
+	start := len(b.buf)
+	b.buf = appendUint32(b.buf, uint32(v.n))
+	b.buf = append(b.buf, v.data...)
+	return start
+}
+
+// fbString is a string.
+type fbString string
+
+func (s fbString) write(b *fbBuilder) int {
+	b.pad(4)
+	start := len(b.buf)
+	b.buf = appendUint32(b.buf, uint32(len(s)))
+	b.buf = append(b.buf, s...)
+	b.buf = append(b.buf, 0)
+	return start
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v), byte(v>>8))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+// fbBuilder holds the serialized flatbuffer.
+type fbBuilder struct {
+	buf []byte
+}
+
+func (b *fbBuilder) pad(align int) {
+	for len(b.buf)%align != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+// putOffset sets the offset stored at pos to point to target.
+func (b *fbBuilder) putOffset(pos, target int) {
+	binary.LittleEndian.PutUint32(b.buf[pos:], uint32(target-pos))
+}
+
+// fbFinish returns the flatbuffer of the root table, padded to a
+// multiple of 8 bytes.
+func fbFinish(root fbTable) []byte {
+	b := &fbBuilder{buf: make([]byte, 4, 512)}
+	b.putOffset(0, root.write(b))
+	b.pad(8)
+	return b.buf
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package arrow
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+
+	"github.com/infobsmi/b33s/internal/s3select/sql"
+)
+
+// Arrow IPC format constants, refer
+// https://arrow.apache.org/docs/format/Columnar.html#serialization-and-interprocess-communication-ipc
+const (
+	// metadataVersionV5 - MetadataVersion.V5
+	metadataVersionV5 = 4
+
+	// MessageHeader union types
+	messageHeaderSchema      = 1
+	messageHeaderRecordBatch = 3
+
+	// Type union types
+	typeInt           = 2
+	typeFloatingPoint = 3
+	typeUtf8          = 5
+	typeBool          = 6
+	typeTimestamp     = 10
+
+	// Precision.DOUBLE
+	precisionDouble = 2
+	// TimeUnit.MICROSECOND
+	timeUnitMicrosecond = 2
+
+	continuationMarker = 0xFFFFFFFF
+)
+
+// maxBatchRows is the number of rows after which a record batch is
+// written to the output.
+const maxBatchRows = 4096
+
+// Writer implements writing records as an Arrow IPC stream.
+type Writer struct {
+	w       io.Writer
+	columns []sql.Column
+	rows    [][]interface{}
+}
+
+// NewWriter creates a Writer of an Arrow IPC stream with the given
+// columns and writes the schema of the stream. All the columns are
+// nullable.
+func NewWriter(w io.Writer, columns []sql.Column, args *WriterArgs) (*Writer, error) {
+	fields := make(fbVector, 0, len(columns))
+	for _, col := range columns {
+		typeType, typ := fieldType(col.Type)
+		fields = append(fields, fbTable{
+			fbRef(fbString(col.Name)), // name
+			fbBool(true),              // nullable
+			fbUint8(typeType),         // type_type
+			fbRef(typ),                // type
+			{},                        // dictionary
+			fbRef(fbVector{}),         // children
+		})
+	}
+	schema := fbTable{
+		fbInt16(0),    // endianness: Little
+		fbRef(fields), // fields
+	}
+
+	writer := &Writer{
+		w:       w,
+		columns: columns,
+	}
+	if err := writer.writeMessage(messageHeaderSchema, schema, nil); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// fieldType returns the type union type and table of the column type.
+func fieldType(t sql.ColumnType) (uint8, fbTable) {
+	switch t {
+	case sql.ColumnTypeBool:
+		return typeBool, fbTable{}
+	case sql.ColumnTypeInt:
+		return typeInt, fbTable{
+			fbInt32(64),  // bitWidth
+			fbBool(true), // is_signed
+		}
+	case sql.ColumnTypeFloat:
+		return typeFloatingPoint, fbTable{
+			fbInt16(precisionDouble), // precision
+		}
+	case sql.ColumnTypeTimestamp:
+		return typeTimestamp, fbTable{
+			fbInt16(timeUnitMicrosecond), // unit
+			fbRef(fbString("UTC")),       // timezone
+		}
+	}
+	return typeUtf8, fbTable{}
+}
+
+// writeMessage writes an encapsulated message with the header and
+// body, refer
+// https://arrow.apache.org/docs/format/Columnar.html#encapsulated-message-format
+func (w *Writer) writeMessage(headerType uint8, header fbTable, body []byte) error {
+	metadata := fbFinish(fbTable{
+		fbInt16(metadataVersionV5), // version
+		fbUint8(headerType),        // header_type
+		fbRef(header),              // header
+		fbInt64(int64(len(body))),  // bodyLength
+	})
+
+	var prefix [8]byte
+	binary.LittleEndian.PutUint32(prefix[0:], continuationMarker)
+	binary.LittleEndian.PutUint32(prefix[4:], uint32(len(metadata)))
+	if _, err := w.w.Write(prefix[:]); err != nil {
+		return err
+	}
+	if _, err := w.w.Write(metadata); err != nil {
+		return err
+	}
+	_, err := w.w.Write(body)
+	return err
+}
+
+// Write writes a row, the values are in the order of the columns of
+// the writer and nil values are written as nulls.
+func (w *Writer) Write(row []*sql.Value) error {
+	values := make([]interface{}, len(w.columns))
+	for i, col := range w.columns {
+		if i >= len(row) {
+			break
+		}
+		v, err := col.Type.Convert(row[i])
+		if err != nil {
+			return err
+		}
+		values[i] = v
+	}
+	w.rows = append(w.rows, values)
+	if len(w.rows) < maxBatchRows {
+		return nil
+	}
+	return w.flush()
+}
+
+// flush writes the pending rows as a record batch.
+func (w *Writer) flush() error {
+	if len(w.rows) == 0 {
+		return nil
+	}
+
+	var body, nodes, buffers []byte
+	addBuffer := func(buf []byte) {
+		buffers = appendUint64(buffers, uint64(len(body)))
+		buffers = appendUint64(buffers, uint64(len(buf)))
+		body = append(body, buf...)
+		// Buffers are 8 byte aligned.
+		for len(body)%8 != 0 {
+			body = append(body, 0)
+		}
+	}
+
+	n := len(w.rows)
+	for i, col := range w.columns {
+		validity := make([]byte, (n+7)/8)
+		nulls := 0
+		for j, row := range w.rows {
+			if row[i] == nil {
+				nulls++
+				continue
+			}
+			validity[j/8] |= 1 << (j % 8)
+		}
+		nodes = appendUint64(nodes, uint64(n))
+		nodes = appendUint64(nodes, uint64(nulls))
+		addBuffer(validity)
+
+		switch col.Type {
+		case sql.ColumnTypeBool:
+			values := make([]byte, (n+7)/8)
+			for j, row := range w.rows {
+				if b, _ := row[i].(bool); b {
+					values[j/8] |= 1 << (j % 8)
+				}
+			}
+			addBuffer(values)
+		case sql.ColumnTypeInt, sql.ColumnTypeFloat, sql.ColumnTypeTimestamp:
+			values := make([]byte, 0, 8*n)
+			for _, row := range w.rows {
+				var v uint64
+				switch val := row[i].(type) {
+				case int64:
+					v = uint64(val)
+				case float64:
+					v = math.Float64bits(val)
+				case time.Time:
+					v = uint64(val.Unix()*int64(time.Second/time.Microsecond) + int64(val.Nanosecond())/int64(time.Microsecond))
+				}
+				values = appendUint64(values, v)
+			}
+			addBuffer(values)
+		default:
+			offsets := make([]byte, 0, 4*(n+1))
+			var data []byte
+			offsets = appendUint32(offsets, 0)
+			for _, row := range w.rows {
+				s, _ := row[i].(string)
+				data = append(data, s...)
+				offsets = appendUint32(offsets, uint32(len(data)))
+			}
+			addBuffer(offsets)
+			addBuffer(data)
+		}
+	}
+
+	batch := fbTable{
+		fbInt64(int64(n)), // length
+		fbRef(fbStructs{n: len(w.columns), data: nodes}),      // nodes
+		fbRef(fbStructs{n: len(buffers) / 16, data: buffers}), // buffers
+	}
+	w.rows = w.rows[:0]
+	return w.writeMessage(messageHeaderRecordBatch, batch, body)
+}
+
+// Close writes the pending rows and the end of the stream.
+func (w *Writer) Close() error {
+	if err := w.flush(); err != nil {
+		return err
+	}
+	var eos [8]byte
+	binary.LittleEndian.PutUint32(eos[0:], continuationMarker)
+	_, err := w.w.Write(eos[:])
+	return err
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package arrow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/infobsmi/b33s/internal/s3select/sql"
+)
+
+// fbReader reads a flatbuffers table, it is used to verify the
+// messages written to the stream.
+type fbReader struct {
+	buf []byte
+	pos int
+}
+
+func (t fbReader) field(id int) (int, bool) {
+	vtable := t.pos - int(int32(binary.LittleEndian.Uint32(t.buf[t.pos:])))
+	if 4+2*id >= int(binary.LittleEndian.Uint16(t.buf[vtable:])) {
+		return 0, false
+	}
+	off := int(binary.LittleEndian.Uint16(t.buf[vtable+4+2*id:]))
+	return t.pos + off, off != 0
+}
+
+func (t fbReader) uint8(id int) uint8 {
+	pos, ok := t.field(id)
+	if !ok {
+		return 0
+	}
+	return t.buf[pos]
+}
+
+func (t fbReader) int16(id int) int16 {
+	pos, ok := t.field(id)
+	if !ok {
+		return 0
+	}
+	return int16(binary.LittleEndian.Uint16(t.buf[pos:]))
+}
+
+func (t fbReader) int64(id int) int64 {
+	pos, ok := t.field(id)
+	if !ok {
+		return 0
+	}
+	return int64(binary.LittleEndian.Uint64(t.buf[pos:]))
+}
+
+func (t fbReader) ref(id int) int {
+	pos, ok := t.field(id)
+	if !ok {
+		return -1
+	}
+	return pos + int(binary.LittleEndian.Uint32(t.buf[pos:]))
+}
+
+func (t fbReader) table(id int) fbReader {
+	return fbReader{buf: t.buf, pos: t.ref(id)}
+}
+
+func (t fbReader) string(id int) string {
+	pos := t.ref(id)
+	n := int(binary.LittleEndian.Uint32(t.buf[pos:]))
+	return string(t.buf[pos+4 : pos+4+n])
+}
+
+// vector returns the position of the first element and the length.
+func (t fbReader) vector(id int) (int, int) {
+	pos := t.ref(id)
+	return pos + 4, int(binary.LittleEndian.Uint32(t.buf[pos:]))
+}
+
+// readMessages returns the metadata and bodies of the messages of the
+// stream.
+func readMessages(t *testing.T, stream []byte) (metadata []fbReader, bodies [][]byte) {
+	for {
+		if binary.LittleEndian.Uint32(stream) != continuationMarker {
+			t.Fatal("missing continuation marker")
+		}
+		n := int(binary.LittleEndian.Uint32(stream[4:]))
+		if n == 0 {
+			if len(stream) != 8 {
+				t.Fatalf("unexpected data after end of stream: %d bytes", len(stream)-8)
+			}
+			return metadata, bodies
+		}
+		if n%8 != 0 {
+			t.Fatalf("metadata size %d is not a multiple of 8", n)
+		}
+		buf := stream[8 : 8+n]
+		msg := fbReader{buf: buf, pos: int(binary.LittleEndian.Uint32(buf))}
+		if version := msg.int16(0); version != metadataVersionV5 {
+			t.Fatalf("unexpected metadata version %d", version)
+		}
+		bodyLength := int(msg.int64(3))
+		if bodyLength%8 != 0 {
+			t.Fatalf("body length %d is not a multiple of 8", bodyLength)
+		}
+		metadata = append(metadata, msg)
+		bodies = append(bodies, stream[8+n:8+n+bodyLength])
+		stream = stream[8+n+bodyLength:]
+	}
+}
+
+func TestWriter(t *testing.T) {
+	ts := time.Date(2023, 1, 2, 3, 4, 5, 6000, time.UTC)
+	columns := []sql.Column{
+		{Name: "name", Type: sql.ColumnTypeString},
+		{Name: "count", Type: sql.ColumnTypeInt},
+		{Name: "ratio", Type: sql.ColumnTypeFloat},
+		{Name: "ok", Type: sql.ColumnTypeBool},
+		{Name: "ts", Type: sql.ColumnTypeTimestamp},
+	}
+	rows := [][]*sql.Value{
+		{sql.FromString("a"), sql.FromInt(1), sql.FromFloat(0.5), sql.FromBool(true), sql.FromTimestamp(ts)},
+		{sql.FromString("bc"), sql.FromNull(), sql.FromInt(2), nil, nil},
+		{nil, sql.FromInt(-3), nil, sql.FromBool(false), nil},
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, columns, &WriterArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		if err = w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, bodies := readMessages(t, buf.Bytes())
+	if len(metadata) != 2 {
+		t.Fatalf("expected a schema and a record batch message, got %d messages", len(metadata))
+	}
+
+	// Schema
+	if typ := metadata[0].uint8(1); typ != messageHeaderSchema {
+		t.Fatalf("expected schema message, got %d", typ)
+	}
+	schema := metadata[0].table(2)
+	pos, n := schema.vector(1)
+	if n != len(columns) {
+		t.Fatalf("expected %d fields, got %d", len(columns), n)
+	}
+	expectedTypes := []uint8{typeUtf8, typeInt, typeFloatingPoint, typeBool, typeTimestamp}
+	for i := 0; i < n; i++ {
+		p := pos + 4*i
+		field := fbReader{buf: schema.buf, pos: p + int(binary.LittleEndian.Uint32(schema.buf[p:]))}
+		if name := field.string(0); name != columns[i].Name {
+			t.Errorf("field %d: expected name %s, got %s", i, columns[i].Name, name)
+		}
+		if field.uint8(1) != 1 {
+			t.Errorf("field %d: expected nullable field", i)
+		}
+		if typ := field.uint8(2); typ != expectedTypes[i] {
+			t.Errorf("field %d: expected type %d, got %d", i, expectedTypes[i], typ)
+		}
+		if _, children := field.vector(5); children != 0 {
+			t.Errorf("field %d: unexpected children", i)
+		}
+		if expectedTypes[i] == typeTimestamp {
+			typ := field.table(3)
+			if unit, tz := typ.int16(0), typ.string(1); unit != timeUnitMicrosecond || tz != "UTC" {
+				t.Errorf("field %d: unexpected timestamp unit %d and timezone %s", i, unit, tz)
+			}
+		}
+	}
+	// Record batch
+	if typ := metadata[1].uint8(1); typ != messageHeaderRecordBatch {
+		t.Fatalf("expected record batch message, got %d", typ)
+	}
+	batch := metadata[1].table(2)
+	if length := batch.int64(0); length != int64(len(rows)) {
+		t.Fatalf("expected %d rows, got %d", len(rows), length)
+	}
+	nodesPos, nodes := batch.vector(1)
+	if nodes != len(columns) {
+		t.Fatalf("expected %d field nodes, got %d", len(columns), nodes)
+	}
+	expectedNulls := []uint64{1, 1, 1, 1, 2}
+	for i := 0; i < nodes; i++ {
+		if nulls := binary.LittleEndian.Uint64(batch.buf[nodesPos+16*i+8:]); nulls != expectedNulls[i] {
+			t.Errorf("field %d: expected %d nulls, got %d", i, expectedNulls[i], nulls)
+		}
+	}
+	buffersPos, nbuffers := batch.vector(2)
+	if nbuffers != 11 {
+		t.Fatalf("expected 11 buffers, got %d", nbuffers)
+	}
+	buffer := func(i int) []byte {
+		off := binary.LittleEndian.Uint64(batch.buf[buffersPos+16*i:])
+		length := binary.LittleEndian.Uint64(batch.buf[buffersPos+16*i+8:])
+		if off%8 != 0 {
+			t.Fatalf("buffer %d is not aligned", i)
+		}
+		return bodies[1][off : off+length]
+	}
+
+	// name: validity, offsets, data
+	if v := buffer(0)[0]; v != 0b011 {
+		t.Errorf("name: unexpected validity %b", v)
+	}
+	offsets := buffer(1)
+	data := buffer(2)
+	for i, expected := range []string{"a", "bc", ""} {
+		start := binary.LittleEndian.Uint32(offsets[4*i:])
+		end := binary.LittleEndian.Uint32(offsets[4*i+4:])
+		if s := string(data[start:end]); s != expected {
+			t.Errorf("name %d: expected %q, got %q", i, expected, s)
+		}
+	}
+	// count: validity, values
+	if v := buffer(3)[0]; v != 0b101 {
+		t.Errorf("count: unexpected validity %b", v)
+	}
+	if v := int64(binary.LittleEndian.Uint64(buffer(4)[16:])); v != -3 {
+		t.Errorf("count: expected -3, got %d", v)
+	}
+	// ratio: integers are widened to floats
+	if v := math.Float64frombits(binary.LittleEndian.Uint64(buffer(6)[8:])); v != 2 {
+		t.Errorf("ratio: expected 2, got %v", v)
+	}
+	// ok: validity, values
+	if v := buffer(7)[0]; v != 0b101 {
+		t.Errorf("ok: unexpected validity %b", v)
+	}
+	if v := buffer(8)[0]; v != 0b001 {
+		t.Errorf("ok: unexpected values %b", v)
+	}
+	// ts: microseconds since the epoch
+	if v := int64(binary.LittleEndian.Uint64(buffer(10))); v != ts.UnixNano()/1000 {
+		t.Errorf("ts: expected %d, got %d", ts.UnixNano()/1000, v)
+	}
+}
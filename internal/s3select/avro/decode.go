@@ -0,0 +1,280 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package avro
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/bcicen/jstream"
+	"github.com/infobsmi/b33s/internal/s3select/sql"
+)
+
+// maxNestingDepth limits the nesting of records, arrays, maps and
+// unions in a value, recursive schemas allow arbitrarily deep values.
+const maxNestingDepth = 128
+
+var (
+	errShortBuffer   = errors.New("unexpected end of block")
+	errInvalidVarint = errors.New("invalid variable length integer")
+	errTooDeep       = errors.New("value is nested too deeply")
+)
+
+// decoder decodes values of the Avro binary encoding from a block.
+type decoder struct {
+	buf   []byte
+	depth int
+}
+
+func (d *decoder) readLong() (int64, error) {
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		if n == 0 {
+			return 0, errShortBuffer
+		}
+		return 0, errInvalidVarint
+	}
+	d.buf = d.buf[n:]
+	return v, nil
+}
+
+// readLength reads a non-negative length, which must not exceed the
+// remaining bytes of the block.
+func (d *decoder) readLength() (int, error) {
+	n, err := d.readLong()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("invalid negative length %d", n)
+	}
+	if n > int64(len(d.buf)) {
+		return 0, errShortBuffer
+	}
+	return int(n), nil
+}
+
+func (d *decoder) readFixed(n int) ([]byte, error) {
+	if n > len(d.buf) {
+		return nil, errShortBuffer
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b, nil
+}
+
+func (d *decoder) readBytes() ([]byte, error) {
+	n, err := d.readLength()
+	if err != nil {
+		return nil, err
+	}
+	return d.readFixed(n)
+}
+
+// readBlockCount reads the item count of the next block of an array or
+// map. Negative counts are followed by the size of the block in bytes.
+func (d *decoder) readBlockCount(items *schema) (int, error) {
+	n, err := d.readLong()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		if n == math.MinInt64 {
+			return 0, errInvalidVarint
+		}
+		n = -n
+		if _, err = d.readLong(); err != nil {
+			return 0, err
+		}
+	}
+	// Every item requires at least one byte, unless its type can
+	// be encoded without any data.
+	if n > int64(len(d.buf)) && (!items.zeroSize || n > maxBlockSize) {
+		return 0, errShortBuffer
+	}
+	return int(n), nil
+}
+
+// value decodes a value of the schema. Records and maps are returned
+// as jstream.KVS, arrays as []interface{} so they can be evaluated
+// like JSON values.
+func (d *decoder) value(s *schema) (interface{}, error) {
+	switch s.typ {
+	case typeNull:
+		return nil, nil
+	case typeBoolean:
+		b, err := d.readFixed(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case typeInt, typeLong:
+		v, err := d.readLong()
+		if err != nil {
+			return nil, err
+		}
+		if s.typ == typeInt && (v < math.MinInt32 || v > math.MaxInt32) {
+			return nil, fmt.Errorf("int value %d out of range", v)
+		}
+		return convertLong(s.logical, v), nil
+	case typeFloat:
+		b, err := d.readFixed(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
+	case typeDouble:
+		b, err := d.readFixed(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case typeBytes, typeString:
+		b, err := d.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		if s.logical == logicalDecimal {
+			return decimalValue(b, s.scale), nil
+		}
+		return string(b), nil
+	case typeFixed:
+		b, err := d.readFixed(s.size)
+		if err != nil {
+			return nil, err
+		}
+		if s.logical == logicalDecimal {
+			return decimalValue(b, s.scale), nil
+		}
+		return string(b), nil
+	case typeEnum:
+		i, err := d.readLong()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= int64(len(s.symbols)) {
+			return nil, fmt.Errorf("invalid index %d of enum %q", i, s.name)
+		}
+		return s.symbols[i], nil
+	}
+
+	if d.depth >= maxNestingDepth {
+		return nil, errTooDeep
+	}
+	d.depth++
+	defer func() { d.depth-- }()
+
+	switch s.typ {
+	case typeRecord:
+		kvs := make(jstream.KVS, 0, len(s.fields))
+		for _, f := range s.fields {
+			v, err := d.value(f.typ)
+			if err != nil {
+				return nil, err
+			}
+			kvs = append(kvs, jstream.KV{Key: f.name, Value: v})
+		}
+		return kvs, nil
+	case typeArray:
+		values := []interface{}{}
+		for {
+			n, err := d.readBlockCount(s.items)
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 {
+				return values, nil
+			}
+			for i := 0; i < n; i++ {
+				v, err := d.value(s.items)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, v)
+			}
+		}
+	case typeMap:
+		kvs := jstream.KVS{}
+		for {
+			n, err := d.readBlockCount(s.items)
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 {
+				return kvs, nil
+			}
+			for i := 0; i < n; i++ {
+				k, err := d.readBytes()
+				if err != nil {
+					return nil, err
+				}
+				v, err := d.value(s.items)
+				if err != nil {
+					return nil, err
+				}
+				kvs = append(kvs, jstream.KV{Key: string(k), Value: v})
+			}
+		}
+	case typeUnion:
+		i, err := d.readLong()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= int64(len(s.branches)) {
+			return nil, fmt.Errorf("invalid union index %d", i)
+		}
+		return d.value(s.branches[i])
+	}
+	return nil, fmt.Errorf("unknown schema type %d", s.typ)
+}
+
+// convertLong converts dates and timestamps to the timestamp
+// representation of S3 Select.
+func convertLong(logical string, v int64) interface{} {
+	var t time.Time
+	switch logical {
+	case logicalDate:
+		t = time.Unix(v*24*60*60, 0)
+	case logicalTimestampMillis, logicalLocalTSMillis:
+		t = time.UnixMilli(v)
+	case logicalTimestampMicros, logicalLocalTSMicros:
+		t = time.UnixMicro(v)
+	default:
+		return v
+	}
+	return sql.FormatSQLTimestamp(t.UTC())
+}
+
+// decimalValue converts the big-endian two's complement unscaled value
+// of a decimal to a float.
+func decimalValue(b []byte, scale int) interface{} {
+	unscaled := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(len(b))*8))
+	}
+	if scale == 0 && unscaled.IsInt64() {
+		return unscaled.Int64()
+	}
+	f, _ := strconv.ParseFloat(unscaled.String()+"e"+strconv.Itoa(-scale), 64)
+	return f
+}
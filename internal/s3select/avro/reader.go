@@ -0,0 +1,321 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package avro
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/bcicen/jstream"
+	jsonfmt "github.com/infobsmi/b33s/internal/s3select/json"
+	"github.com/infobsmi/b33s/internal/s3select/sql"
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	syncSize = 16
+
+	// maxBlockSize limits the size of a block of objects, compressed
+	// and decompressed. Writers typically use blocks of 16KiB to 1MiB.
+	maxBlockSize = 64 << 20
+
+	// maxHeaderSize limits the size of the file metadata.
+	maxHeaderSize = 16 << 20
+
+	codecNull      = "null"
+	codecDeflate   = "deflate"
+	codecSnappy    = "snappy"
+	codecZstandard = "zstandard"
+	codecBzip2     = "bzip2"
+)
+
+// magic starts every object container file.
+const magic = "Obj\x01"
+
+// Reader - Avro object container file reader for S3Select.
+type Reader struct {
+	r      *bufio.Reader
+	closer io.Closer
+
+	schema *schema
+	codec  string
+	sync   [syncSize]byte
+
+	block     decoder
+	remaining int64 // objects left in the current block
+	raw       []byte
+	buf       []byte
+	zstd      *zstd.Decoder
+}
+
+// Read - reads single record.
+// Once Read is called the previous record should no longer be referenced.
+func (r *Reader) Read(dst sql.Record) (sql.Record, error) {
+	for r.remaining == 0 {
+		if err := r.nextBlock(); err != nil {
+			if err == io.EOF {
+				return nil, err
+			}
+			return nil, errAvroParsingError(err)
+		}
+	}
+
+	v, err := r.block.value(r.schema)
+	if err != nil {
+		return nil, errAvroParsingError(err)
+	}
+	r.remaining--
+	if r.remaining == 0 && len(r.block.buf) > 0 {
+		return nil, errAvroParsingError(errors.New("unexpected data at end of block"))
+	}
+
+	kvs, ok := v.(jstream.KVS)
+	if !ok || r.schema.typ != typeRecord {
+		// Objects which are not records are returned as a single column.
+		kvs = jstream.KVS{{Key: "_1", Value: v}}
+	}
+
+	// Reuse destination if we can.
+	dstRec, ok := dst.(*jsonfmt.Record)
+	if !ok {
+		dstRec = &jsonfmt.Record{}
+	}
+	dstRec.SelectFormat = sql.SelectFmtAvro
+	dstRec.KVS = kvs
+	return dstRec, nil
+}
+
+// Close - closes underlying reader.
+func (r *Reader) Close() error {
+	if r.zstd != nil {
+		r.zstd.Close()
+		r.zstd = nil
+	}
+	return r.closer.Close()
+}
+
+// readHeader reads the magic, the metadata and the sync marker of the
+// file.
+func (r *Reader) readHeader() error {
+	var b [len(magic)]byte
+	if _, err := io.ReadFull(r.r, b[:]); err != nil {
+		return fmt.Errorf("reading header: %w", err)
+	}
+	if string(b[:]) != magic {
+		return errors.New("not an Avro object container file")
+	}
+
+	meta := make(map[string][]byte)
+	var size int64
+	for {
+		n, err := r.readLong()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			break
+		}
+		if n < 0 {
+			n = -n
+			if _, err = r.readLong(); err != nil {
+				return err
+			}
+		}
+		for ; n > 0; n-- {
+			k, err := r.readBytes(maxHeaderSize - size)
+			if err != nil {
+				return err
+			}
+			size += int64(len(k))
+			v, err := r.readBytes(maxHeaderSize - size)
+			if err != nil {
+				return err
+			}
+			size += int64(len(v))
+			meta[string(k)] = v
+		}
+	}
+	if _, err := io.ReadFull(r.r, r.sync[:]); err != nil {
+		return fmt.Errorf("reading header: %w", err)
+	}
+
+	schema, err := parseSchema(meta["avro.schema"])
+	if err != nil {
+		return err
+	}
+	r.schema = schema
+
+	r.codec = string(meta["avro.codec"])
+	switch r.codec {
+	case "":
+		r.codec = codecNull
+	case codecNull, codecDeflate, codecSnappy, codecBzip2:
+	case codecZstandard:
+		r.zstd, err = zstd.NewReader(nil, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(maxBlockSize))
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported codec %q", r.codec)
+	}
+	return nil
+}
+
+// nextBlock reads the next block of objects. io.EOF is returned at the
+// end of the file.
+func (r *Reader) nextBlock() error {
+	count, err := r.readLong()
+	if err != nil {
+		return err
+	}
+	size, err := r.readLong()
+	if err != nil {
+		return unexpectedEOF(err)
+	}
+	if count < 0 || size < 0 || size > maxBlockSize {
+		return fmt.Errorf("invalid block of %d objects with %d bytes", count, size)
+	}
+
+	if cap(r.raw) < int(size) {
+		r.raw = make([]byte, size)
+	}
+	r.raw = r.raw[:size]
+	if _, err = io.ReadFull(r.r, r.raw); err != nil {
+		return unexpectedEOF(err)
+	}
+	var sync [syncSize]byte
+	if _, err = io.ReadFull(r.r, sync[:]); err != nil {
+		return unexpectedEOF(err)
+	}
+	if sync != r.sync {
+		return errors.New("invalid sync marker")
+	}
+
+	if r.block.buf, err = r.decompress(r.raw); err != nil {
+		return err
+	}
+	if count > int64(len(r.block.buf)) && (!r.schema.zeroSize || count > maxBlockSize) {
+		return fmt.Errorf("invalid block of %d objects with %d bytes", count, len(r.block.buf))
+	}
+	r.remaining = count
+	return nil
+}
+
+func (r *Reader) decompress(raw []byte) ([]byte, error) {
+	switch r.codec {
+	case codecNull:
+		return raw, nil
+	case codecDeflate:
+		return r.readAll(flate.NewReader(bytes.NewReader(raw)))
+	case codecBzip2:
+		return r.readAll(bzip2.NewReader(bytes.NewReader(raw)))
+	case codecSnappy:
+		// Snappy blocks are followed by the CRC32 of the decompressed data.
+		if len(raw) < 4 {
+			return nil, errors.New("invalid snappy block")
+		}
+		n, err := s2.DecodedLen(raw[:len(raw)-4])
+		if err != nil {
+			return nil, err
+		}
+		if n > maxBlockSize {
+			return nil, errors.New("decompressed block too large")
+		}
+		buf, err := s2.Decode(r.buf[:cap(r.buf)], raw[:len(raw)-4])
+		if err != nil {
+			return nil, err
+		}
+		r.buf = buf
+		if crc32.ChecksumIEEE(buf) != binary.BigEndian.Uint32(raw[len(raw)-4:]) {
+			return nil, errors.New("snappy block checksum mismatch")
+		}
+		return buf, nil
+	case codecZstandard:
+		buf, err := r.zstd.DecodeAll(raw, r.buf[:0])
+		if err != nil {
+			return nil, err
+		}
+		r.buf = buf
+		return buf, nil
+	}
+	return nil, fmt.Errorf("unsupported codec %q", r.codec)
+}
+
+// readAll reads the decompressed block from a stream decompressor.
+func (r *Reader) readAll(rd io.Reader) ([]byte, error) {
+	w := bytes.NewBuffer(r.buf[:0])
+	n, err := w.ReadFrom(io.LimitReader(rd, maxBlockSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if n > maxBlockSize {
+		return nil, errors.New("decompressed block too large")
+	}
+	r.buf = w.Bytes()
+	return r.buf, nil
+}
+
+func (r *Reader) readLong() (int64, error) {
+	v, err := binary.ReadVarint(r.r)
+	if err != nil && err != io.EOF {
+		return 0, unexpectedEOF(err)
+	}
+	return v, err
+}
+
+func (r *Reader) readBytes(limit int64) ([]byte, error) {
+	n, err := r.readLong()
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if n < 0 || n > limit {
+		return nil, errors.New("invalid file metadata")
+	}
+	b := make([]byte, n)
+	if _, err = io.ReadFull(r.r, b); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return b, nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// NewReader - creates new Avro reader using readCloser.
+func NewReader(readCloser io.ReadCloser, _ *ReaderArgs) (*Reader, error) {
+	r := &Reader{
+		r:      bufio.NewReader(readCloser),
+		closer: readCloser,
+	}
+	if err := r.readHeader(); err != nil {
+		return nil, errAvroParsingError(err)
+	}
+	return r, nil
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package avro
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcicen/jstream"
+	jsonfmt "github.com/infobsmi/b33s/internal/s3select/json"
+	"github.com/infobsmi/b33s/internal/s3select/sql"
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+const testSchema = `{
+  "type": "record", "name": "Event", "namespace": "com.example",
+  "fields": [
+    {"name": "id", "type": "long"},
+    {"name": "name", "type": ["null", "string"]},
+    {"name": "ok", "type": "boolean"},
+    {"name": "ratio", "type": "float"},
+    {"name": "value", "type": "double"},
+    {"name": "ts", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+    {"name": "day", "type": {"type": "int", "logicalType": "date"}},
+    {"name": "price", "type": {"type": "bytes", "logicalType": "decimal", "precision": 9, "scale": 2}},
+    {"name": "kind", "type": {"type": "enum", "name": "Kind", "symbols": ["A", "B"]}},
+    {"name": "tags", "type": {"type": "array", "items": "string"}},
+    {"name": "attrs", "type": {"type": "map", "values": "int"}},
+    {"name": "hash", "type": {"type": "fixed", "name": "Hash", "size": 2}},
+    {"name": "next", "type": ["null", "Event"]}
+  ]
+}`
+
+// encoder produces the Avro binary encoding of values.
+type encoder struct {
+	bytes.Buffer
+}
+
+func (e *encoder) long(v int64) *encoder {
+	var b [binary.MaxVarintLen64]byte
+	e.Write(b[:binary.PutVarint(b[:], v)])
+	return e
+}
+
+func (e *encoder) str(s string) *encoder {
+	e.long(int64(len(s)))
+	e.WriteString(s)
+	return e
+}
+
+func (e *encoder) fixed(b ...byte) *encoder {
+	e.Write(b)
+	return e
+}
+
+func (e *encoder) float(f float32) *encoder {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], math.Float32bits(f))
+	return e.fixed(b[:]...)
+}
+
+func (e *encoder) double(f float64) *encoder {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(f))
+	return e.fixed(b[:]...)
+}
+
+// event appends an Event record, without a next event.
+func (e *encoder) event(id int64, name string, ts time.Time) *encoder {
+	e.long(id)
+	if name == "" {
+		e.long(0)
+	} else {
+		e.long(1).str(name)
+	}
+	e.fixed(1).float(0.5).double(-1.25)
+	e.long(ts.UnixMilli()).long(ts.Unix() / 86400)
+	e.long(2).fixed(0xfc, 0x18) // -1000 cents
+	e.long(1)                   // B
+	e.long(2).str("x").str("y").long(0)
+	e.long(-1).long(2).str("k").long(7).long(0) // block with size
+	e.fixed('h', 'i')
+	return e
+}
+
+func writeFile(codec string, blocks ...[]byte) []byte {
+	sync := []byte("0123456789abcdef")
+	var f encoder
+	f.WriteString(magic)
+	f.long(2).str("avro.schema").str(testSchema).str("avro.codec").str(codec).long(0)
+	f.Write(sync)
+	for _, block := range blocks {
+		count := int64(block[0])
+		data := block[1:]
+		switch codec {
+		case codecDeflate:
+			var buf bytes.Buffer
+			w, _ := flate.NewWriter(&buf, flate.BestSpeed)
+			w.Write(data)
+			w.Close()
+			data = buf.Bytes()
+		case codecSnappy:
+			var crc [4]byte
+			binary.BigEndian.PutUint32(crc[:], crc32.ChecksumIEEE(data))
+			data = append(s2.EncodeSnappy(nil, data), crc[:]...)
+		case codecZstandard:
+			enc, _ := zstd.NewWriter(nil)
+			data = enc.EncodeAll(data, nil)
+			enc.Close()
+		}
+		f.long(count).long(int64(len(data)))
+		f.Write(data)
+		f.Write(sync)
+	}
+	return f.Bytes()
+}
+
+func TestReader(t *testing.T) {
+	ts := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	var b1 encoder
+	b1.WriteByte(2) // object count, see writeFile
+	b1.event(1, "first", ts).long(0)
+	b1.event(2, "", ts).long(1).event(3, "nested", ts).long(0)
+	var b2 encoder
+	b2.WriteByte(1)
+	b2.event(4, "last", ts).long(0)
+
+	event := func(id int64, name interface{}, next interface{}) jstream.KVS {
+		return jstream.KVS{
+			{Key: "id", Value: id},
+			{Key: "name", Value: name},
+			{Key: "ok", Value: true},
+			{Key: "ratio", Value: 0.5},
+			{Key: "value", Value: -1.25},
+			{Key: "ts", Value: sql.FormatSQLTimestamp(ts)},
+			{Key: "day", Value: sql.FormatSQLTimestamp(ts)},
+			{Key: "price", Value: -10.0},
+			{Key: "kind", Value: "B"},
+			{Key: "tags", Value: []interface{}{"x", "y"}},
+			{Key: "attrs", Value: jstream.KVS{{Key: "k", Value: int64(7)}}},
+			{Key: "hash", Value: "hi"},
+			{Key: "next", Value: next},
+		}
+	}
+	expected := []jstream.KVS{
+		event(1, "first", nil),
+		event(2, nil, event(3, "nested", nil)),
+		event(4, "last", nil),
+	}
+
+	for _, codec := range []string{codecNull, codecDeflate, codecSnappy, codecZstandard} {
+		t.Run(codec, func(t *testing.T) {
+			data := writeFile(codec, b1.Bytes(), []byte{0}, b2.Bytes())
+			r, err := NewReader(io.NopCloser(bytes.NewReader(data)), &ReaderArgs{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			var record sql.Record
+			for i := 0; ; i++ {
+				record, err = r.Read(record)
+				if err == io.EOF {
+					if i != len(expected) {
+						t.Fatalf("expected %d records, got %d", len(expected), i)
+					}
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if i >= len(expected) {
+					t.Fatalf("unexpected record %v", record)
+				}
+				kvs := record.(*jsonfmt.Record).KVS
+				if !reflect.DeepEqual(kvs, expected[i]) {
+					t.Errorf("record %d: expected %v, got %v", i, expected[i], kvs)
+				}
+			}
+		})
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	var block encoder
+	block.WriteByte(1)
+	block.event(1, "first", time.Now()).long(0)
+	valid := writeFile(codecNull, block.Bytes())
+
+	testCases := []struct {
+		name string
+		data []byte
+	}{
+		{"magic", append([]byte("Obj\x02"), valid[4:]...)},
+		{"truncated header", valid[:20]},
+		{"truncated block", valid[:len(valid)-20]},
+		{"sync marker", append(append([]byte{}, valid[:len(valid)-1]...), 'x')},
+		{"codec", writeFile("xz", block.Bytes())},
+		{"count", writeFile(codecNull, append([]byte{2}, block.Bytes()[1:]...))},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewReader(io.NopCloser(bytes.NewReader(tc.data)), &ReaderArgs{})
+			for err == nil {
+				_, err = r.Read(nil)
+			}
+			if err == io.EOF {
+				t.Fatal("expected an error")
+			}
+			if _, ok := err.(*s3Error); !ok {
+				t.Fatalf("expected an s3Error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseSchema(t *testing.T) {
+	testCases := []struct {
+		schema string
+		err    string
+	}{
+		{schema: `"string"`},
+		{schema: `["null", "long"]`},
+		{schema: testSchema},
+		{schema: `{"type": "record", "name": "a.R", "fields": [{"name": "f", "type": {"type": "fixed", "name": "F", "size": 1}}, {"name": "g", "type": "a.F"}]}`},
+		{schema: `{"type": "record", "name": "R", "fields": [{"name": "f", "type": "S"}]}`, err: "unknown type"},
+		{schema: `["null", ["long"]]`, err: "unions may not"},
+		{schema: `{"type": "enum", "symbols": ["A"]}`, err: "missing name"},
+		{schema: `[{"type": "fixed", "name": "F", "size": 1}, {"type": "fixed", "name": "F", "size": 2}]`, err: "defined twice"},
+		{schema: `{`, err: "invalid schema"},
+	}
+	for _, tc := range testCases {
+		_, err := parseSchema([]byte(tc.schema))
+		if tc.err == "" && err != nil {
+			t.Errorf("%s: unexpected error %v", tc.schema, err)
+		}
+		if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("%s: expected error %q, got %v", tc.schema, tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package avro
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type schemaType int
+
+const (
+	typeNull schemaType = iota
+	typeBoolean
+	typeInt
+	typeLong
+	typeFloat
+	typeDouble
+	typeBytes
+	typeString
+	typeRecord
+	typeEnum
+	typeArray
+	typeMap
+	typeUnion
+	typeFixed
+)
+
+var primitiveTypes = map[string]schemaType{
+	"null":    typeNull,
+	"boolean": typeBoolean,
+	"int":     typeInt,
+	"long":    typeLong,
+	"float":   typeFloat,
+	"double":  typeDouble,
+	"bytes":   typeBytes,
+	"string":  typeString,
+}
+
+// Supported logical types, others are ignored as required by the
+// specification and the value of the underlying type is returned.
+const (
+	logicalDate            = "date"
+	logicalDecimal         = "decimal"
+	logicalTimestampMillis = "timestamp-millis"
+	logicalTimestampMicros = "timestamp-micros"
+	logicalLocalTSMillis   = "local-timestamp-millis"
+	logicalLocalTSMicros   = "local-timestamp-micros"
+)
+
+// schema is a parsed Avro schema, see
+// https://avro.apache.org/docs/1.11.1/specification/
+type schema struct {
+	typ      schemaType
+	name     string // full name of named types
+	logical  string
+	scale    int       // decimal
+	size     int       // fixed
+	fields   []field   // record
+	symbols  []string  // enum
+	items    *schema   // array items and map values
+	branches []*schema // union
+
+	// zeroSize is set when values may be encoded with zero bytes,
+	// used to bound the number of items in arrays and maps.
+	zeroSize bool
+}
+
+type field struct {
+	name string
+	typ  *schema
+}
+
+type schemaParser struct {
+	names map[string]*schema
+}
+
+// parseSchema parses the JSON representation of a schema.
+func parseSchema(data []byte) (*schema, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	p := schemaParser{names: make(map[string]*schema)}
+	return p.parse(v, "")
+}
+
+func (p *schemaParser) parse(v interface{}, namespace string) (*schema, error) {
+	switch v := v.(type) {
+	case string:
+		if t, ok := primitiveTypes[v]; ok {
+			return &schema{typ: t, zeroSize: t == typeNull}, nil
+		}
+		if s := p.lookup(v, namespace); s != nil {
+			return s, nil
+		}
+		return nil, fmt.Errorf("unknown type %q in schema", v)
+	case []interface{}:
+		s := &schema{typ: typeUnion}
+		for _, b := range v {
+			if _, ok := b.([]interface{}); ok {
+				return nil, errors.New("unions may not immediately contain other unions")
+			}
+			bs, err := p.parse(b, namespace)
+			if err != nil {
+				return nil, err
+			}
+			s.branches = append(s.branches, bs)
+		}
+		if len(s.branches) == 0 {
+			return nil, errors.New("empty union in schema")
+		}
+		return s, nil
+	case map[string]interface{}:
+		return p.parseComplex(v, namespace)
+	}
+	return nil, fmt.Errorf("invalid schema element %v", v)
+}
+
+func (p *schemaParser) parseComplex(v map[string]interface{}, namespace string) (*schema, error) {
+	typeName, ok := v["type"].(string)
+	if !ok {
+		// A nested type definition such as {"type": {"type": "array", ...}}
+		return p.parse(v["type"], namespace)
+	}
+
+	if t, ok := primitiveTypes[typeName]; ok {
+		s := &schema{typ: t, zeroSize: t == typeNull}
+		logical, _ := v["logicalType"].(string)
+		switch {
+		case t == typeInt && logical == logicalDate,
+			t == typeLong && (logical == logicalTimestampMillis || logical == logicalTimestampMicros ||
+				logical == logicalLocalTSMillis || logical == logicalLocalTSMicros):
+			s.logical = logical
+		case t == typeBytes && logical == logicalDecimal:
+			s.logical = logical
+			s.scale = intAttr(v, "scale")
+		}
+		return s, nil
+	}
+
+	var s *schema
+	switch typeName {
+	case "record", "error":
+		s = &schema{typ: typeRecord}
+	case "enum":
+		s = &schema{typ: typeEnum}
+	case "fixed":
+		s = &schema{typ: typeFixed}
+	case "array":
+		items, err := p.parse(v["items"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &schema{typ: typeArray, items: items}, nil
+	case "map":
+		values, err := p.parse(v["values"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &schema{typ: typeMap, items: values}, nil
+	default:
+		if s := p.lookup(typeName, namespace); s != nil {
+			return s, nil
+		}
+		return nil, fmt.Errorf("unknown type %q in schema", typeName)
+	}
+
+	// Register named types before parsing record fields so they may
+	// refer to the record itself.
+	name, _ := v["name"].(string)
+	if name == "" {
+		return nil, fmt.Errorf("missing name of %s type in schema", typeName)
+	}
+	if ns, ok := v["namespace"].(string); ok && !strings.Contains(name, ".") {
+		namespace = ns
+	}
+	s.name = fullName(name, namespace)
+	if i := strings.LastIndexByte(s.name, '.'); i >= 0 {
+		namespace = s.name[:i]
+	}
+	if _, ok := p.names[s.name]; ok {
+		return nil, fmt.Errorf("type %q is defined twice in schema", s.name)
+	}
+	p.names[s.name] = s
+
+	switch s.typ {
+	case typeRecord:
+		fields, _ := v["fields"].([]interface{})
+		s.zeroSize = true
+		for _, f := range fields {
+			fm, ok := f.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid field in record %q", s.name)
+			}
+			fname, _ := fm["name"].(string)
+			ft, err := p.parse(fm["type"], namespace)
+			if err != nil {
+				return nil, err
+			}
+			s.fields = append(s.fields, field{name: fname, typ: ft})
+			s.zeroSize = s.zeroSize && ft.zeroSize
+		}
+	case typeEnum:
+		symbols, _ := v["symbols"].([]interface{})
+		for _, sym := range symbols {
+			str, ok := sym.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid symbol in enum %q", s.name)
+			}
+			s.symbols = append(s.symbols, str)
+		}
+	case typeFixed:
+		s.size = intAttr(v, "size")
+		if s.size < 0 {
+			return nil, fmt.Errorf("invalid size of fixed %q", s.name)
+		}
+		s.zeroSize = s.size == 0
+		if logical, _ := v["logicalType"].(string); logical == logicalDecimal {
+			s.logical = logical
+			s.scale = intAttr(v, "scale")
+		}
+	}
+	return s, nil
+}
+
+// lookup returns the named type with the given name, relative names
+// are resolved in the enclosing namespace first.
+func (p *schemaParser) lookup(name, namespace string) *schema {
+	if s, ok := p.names[fullName(name, namespace)]; ok {
+		return s
+	}
+	return p.names[name]
+}
+
+func fullName(name, namespace string) string {
+	if namespace == "" || strings.Contains(name, ".") {
+		return name
+	}
+	return namespace + "." + name
+}
+
+func intAttr(v map[string]interface{}, key string) int {
+	f, _ := v[key].(float64)
+	return int(f)
+}
@@ -0,0 +1,307 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package s3select
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/bcicen/jstream"
+	"github.com/infobsmi/b33s/internal/s3select/arrow"
+	jsonfmt "github.com/infobsmi/b33s/internal/s3select/json"
+	"github.com/infobsmi/b33s/internal/s3select/parquet"
+	"github.com/infobsmi/b33s/internal/s3select/simdj"
+	"github.com/infobsmi/b33s/internal/s3select/sql"
+	csv "github.com/minio/csvparser"
+)
+
+// maxSchemaRows is the number of rows used to infer the column types of
+// columnar output formats.
+const maxSchemaRows = 1000
+
+// columnWriter writes rows in a columnar output format.
+type columnWriter interface {
+	Write(row []*sql.Value) error
+	Close() error
+}
+
+// columnarOutput converts output records into the rows of a columnar
+// output format (Parquet, Arrow). The columns and their types are
+// inferred from the first rows, later rows with other columns or with
+// values which can not be converted to the column type fail. No output
+// is written when the query does not return any rows.
+type columnarOutput struct {
+	newWriter func(w io.Writer, columns []sql.Column) (columnWriter, error)
+
+	out     bytes.Buffer
+	columns []sql.Column
+	index   map[string]int
+	pending []columnRecord
+	writer  columnWriter
+}
+
+func newColumnarOutput(output *OutputSerialization) *columnarOutput {
+	c := &columnarOutput{index: make(map[string]int)}
+	switch output.format {
+	case parquetFormat:
+		c.newWriter = func(w io.Writer, columns []sql.Column) (columnWriter, error) {
+			return parquet.NewWriter(w, columns, &output.ParquetArgs)
+		}
+	case arrowFormat:
+		c.newWriter = func(w io.Writer, columns []sql.Column) (columnWriter, error) {
+			return arrow.NewWriter(w, columns, &output.ArrowArgs)
+		}
+	default:
+		return nil
+	}
+	return c
+}
+
+// write writes the record and moves the output written so far to buf.
+func (c *columnarOutput) write(buf *bytes.Buffer, record sql.Record) error {
+	rec, err := recordColumns(record)
+	if err != nil {
+		return err
+	}
+
+	if c.writer == nil {
+		for i, name := range rec.names {
+			idx, ok := c.index[name]
+			if !ok {
+				idx = len(c.columns)
+				c.index[name] = idx
+				c.columns = append(c.columns, sql.Column{Name: name})
+			}
+			c.columns[idx].Type = c.columns[idx].Type.Merge(rec.values[i])
+		}
+		c.pending = append(c.pending, rec)
+		if len(c.pending) < maxSchemaRows {
+			return nil
+		}
+		if err = c.start(); err != nil {
+			return err
+		}
+	} else if err = c.writeRow(rec); err != nil {
+		return err
+	}
+
+	buf.Write(c.out.Bytes())
+	c.out.Reset()
+	return nil
+}
+
+// start creates the writer with the inferred columns and writes the
+// pending rows.
+func (c *columnarOutput) start() (err error) {
+	for i := range c.columns {
+		if c.columns[i].Type == sql.ColumnTypeNull {
+			c.columns[i].Type = sql.ColumnTypeString
+		}
+	}
+	if c.writer, err = c.newWriter(&c.out, c.columns); err != nil {
+		return err
+	}
+	for _, rec := range c.pending {
+		if err = c.writeRow(rec); err != nil {
+			return err
+		}
+	}
+	c.pending = nil
+	return nil
+}
+
+func (c *columnarOutput) writeRow(rec columnRecord) error {
+	row := make([]*sql.Value, len(c.columns))
+	for i, name := range rec.names {
+		idx, ok := c.index[name]
+		if !ok {
+			return fmt.Errorf("column %v is not part of the output columns", name)
+		}
+		row[idx] = rec.values[i]
+	}
+	return c.writer.Write(row)
+}
+
+// close completes the output and moves the remaining output to buf.
+func (c *columnarOutput) close(buf *bytes.Buffer) error {
+	if c.writer == nil {
+		if len(c.pending) == 0 {
+			return nil
+		}
+		if err := c.start(); err != nil {
+			return err
+		}
+	}
+	if err := c.writer.Close(); err != nil {
+		return err
+	}
+	buf.Write(c.out.Bytes())
+	c.out.Reset()
+	return nil
+}
+
+// recordColumns returns the columns of a record. Records are
+// columnRecords unless all the columns were selected, in which case the
+// input records are returned.
+func recordColumns(record sql.Record) (columnRecord, error) {
+	var kvs jstream.KVS
+	switch rec := record.(type) {
+	case *columnRecord:
+		// Output records are reused, the pending rows need a copy.
+		return *rec.Clone(nil).(*columnRecord), nil
+	case *jsonfmt.Record:
+		kvs = rec.KVS
+	case *simdj.Record:
+		jrec, err := rec.CloneTo(nil)
+		if err != nil {
+			return columnRecord{}, err
+		}
+		kvs = jrec.(*jsonfmt.Record).KVS
+	default:
+		// CSV records do not expose their columns, all their values are
+		// strings which are preserved by the JSON representation.
+		var buf bytes.Buffer
+		if err := record.WriteJSON(&buf); err != nil {
+			return columnRecord{}, err
+		}
+		d := jstream.NewDecoder(&buf, 0).ObjectAsKVS()
+		for mv := range d.Stream() {
+			kvs, _ = mv.Value.(jstream.KVS)
+		}
+		if err := d.Err(); err != nil {
+			return columnRecord{}, err
+		}
+	}
+
+	rec := columnRecord{
+		names:  make([]string, 0, len(kvs)),
+		values: make([]*sql.Value, 0, len(kvs)),
+	}
+	for _, kv := range kvs {
+		var value *sql.Value
+		switch v := kv.Value.(type) {
+		case nil:
+			value = sql.FromNull()
+		case bool:
+			value = sql.FromBool(v)
+		case int64:
+			value = sql.FromInt(v)
+		case float64:
+			value = sql.FromFloat(v)
+		case string:
+			value = sql.FromString(v)
+		case time.Time:
+			value = sql.FromTimestamp(v)
+		default:
+			// Objects and arrays are written as JSON strings.
+			b, err := json.Marshal(v)
+			if err != nil {
+				return columnRecord{}, err
+			}
+			value = sql.FromString(string(b))
+		}
+		rec.names = append(rec.names, kv.Key)
+		rec.values = append(rec.values, value)
+	}
+	return rec, nil
+}
+
+// columnRecord is the output record of columnar output formats, it keeps
+// the typed values of the columns in the order in which they were set.
+type columnRecord struct {
+	names  []string
+	values []*sql.Value
+}
+
+// Get - gets the value for a column name.
+func (r *columnRecord) Get(name string) (*sql.Value, error) {
+	for i, n := range r.names {
+		if n == name {
+			return r.values[i], nil
+		}
+	}
+	return nil, fmt.Errorf("column %v not found", name)
+}
+
+// Set - sets the value for a column name.
+func (r *columnRecord) Set(name string, value *sql.Value) (sql.Record, error) {
+	if value.IsMissing() {
+		return r, nil
+	}
+	v := *value
+	r.names = append(r.names, name)
+	r.values = append(r.values, &v)
+	return r, nil
+}
+
+// WriteCSV - encodes to CSV data.
+func (r *columnRecord) WriteCSV(writer io.Writer, opts sql.WriteCSVOpts) error {
+	csvRecord := make([]string, 0, len(r.values))
+	for _, v := range r.values {
+		csvRecord = append(csvRecord, v.CSVString())
+	}
+	w := csv.NewWriter(writer)
+	w.Comma = opts.FieldDelimiter
+	w.Quote = opts.Quote
+	w.QuoteEscape = opts.QuoteEscape
+	w.AlwaysQuote = opts.AlwaysQuote
+	if err := w.Write(csvRecord); err != nil {
+		return err
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// WriteJSON - encodes to JSON data.
+func (r *columnRecord) WriteJSON(writer io.Writer) error {
+	kvs := make(jstream.KVS, 0, len(r.values))
+	for i, v := range r.values {
+		kvs = append(kvs, jstream.KV{Key: r.names[i], Value: v})
+	}
+	return json.NewEncoder(writer).Encode(kvs)
+}
+
+// Clone the record and if possible use the destination provided.
+func (r *columnRecord) Clone(dst sql.Record) sql.Record {
+	other, ok := dst.(*columnRecord)
+	if !ok {
+		other = &columnRecord{}
+	}
+	other.names = append(other.names[:0], r.names...)
+	other.values = append(other.values[:0], r.values...)
+	return other
+}
+
+// Reset the record.
+func (r *columnRecord) Reset() {
+	r.names = r.names[:0]
+	r.values = r.values[:0]
+}
+
+// Raw - returns the underlying representation.
+func (r *columnRecord) Raw() (sql.SelectObjectFormat, interface{}) {
+	return sql.SelectFmtUnknown, r
+}
+
+// Replace - is not supported for column records.
+func (r *columnRecord) Replace(_ interface{}) error {
+	return fmt.Errorf("Replace is not supported for column records")
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package csv
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// rangeReader returns the records of an object which start inside a byte
+// range. A record which starts before the range is skipped, a record which
+// starts inside the range is read until its end, even if the end is after
+// the range.
+type rangeReader struct {
+	r         *bufio.Reader
+	closer    io.Closer
+	delim     []byte
+	header    []byte // header line, returned before the records
+	last      []byte // last len(delim) bytes read
+	remaining int64  // bytes left in the range, negative if unbounded
+	done      bool
+}
+
+// NewRangeReader returns a reader for the records of rsc which start inside
+// the scan range of length bytes at offset start. A negative length selects
+// all records after start. Records are separated by the RecordDelimiter of
+// args, quoted record delimiters are not supported. If the object has a
+// header, it is returned before the records of the range.
+func NewRangeReader(rsc io.ReadSeekCloser, start, length int64, args *ReaderArgs) (io.ReadCloser, error) {
+	r := &rangeReader{
+		closer:    rsc,
+		delim:     []byte(args.RecordDelimiter),
+		remaining: length,
+	}
+	if len(r.delim) == 0 {
+		r.delim = []byte(defaultRecordDelimiter)
+	}
+	r.last = make([]byte, 0, len(r.delim))
+
+	if start > 0 && args.FileHeaderInfo != none {
+		// The header is returned before the records of every range.
+		if _, err := rsc.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		r.r = bufio.NewReaderSize(rsc, csvSplitSize)
+		for !r.atDelim() {
+			b, err := r.r.ReadByte()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			r.header = append(r.header, b)
+			r.track(r.header[len(r.header)-1:])
+		}
+		r.last = r.last[:0]
+	}
+
+	// Start reading just before the range, so a record starting
+	// at the first byte of the range is found as well.
+	before := int64(len(r.delim))
+	if start < before {
+		before = start
+	}
+	if _, err := rsc.Seek(start-before, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if r.r == nil {
+		r.r = bufio.NewReaderSize(rsc, csvSplitSize)
+	} else {
+		r.r.Reset(rsc)
+	}
+	if start == 0 {
+		return r, nil
+	}
+
+	// Skip to the end of the record containing the byte before the range.
+	skipped := -before
+	for !r.atDelim() {
+		b, err := r.r.ReadByte()
+		if err == io.EOF {
+			r.done = true
+			return r, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.track([]byte{b})
+		skipped++
+	}
+	if length >= 0 {
+		r.remaining -= skipped
+		if r.remaining <= 0 {
+			r.done = true
+		}
+	}
+	return r, nil
+}
+
+func (r *rangeReader) Read(p []byte) (n int, err error) {
+	if len(r.header) > 0 {
+		n = copy(p, r.header)
+		r.header = r.header[n:]
+		return n, nil
+	}
+	if r.done {
+		return 0, io.EOF
+	}
+	if r.remaining != 0 {
+		if r.remaining > 0 && int64(len(p)) > r.remaining {
+			p = p[:r.remaining]
+		}
+		n, err = r.r.Read(p)
+		r.track(p[:n])
+		if r.remaining > 0 {
+			r.remaining -= int64(n)
+			if r.remaining == 0 && r.atDelim() {
+				r.done = true
+			}
+		}
+		return n, err
+	}
+
+	// Past the end of the range, complete the last record.
+	for n < len(p) {
+		if p[n], err = r.r.ReadByte(); err != nil {
+			return n, err
+		}
+		n++
+		r.track(p[n-1 : n])
+		if r.atDelim() {
+			r.done = true
+			break
+		}
+	}
+	return n, nil
+}
+
+// Close closes the underlying reader.
+func (r *rangeReader) Close() error {
+	return r.closer.Close()
+}
+
+// track keeps the last bytes read to find record delimiters.
+func (r *rangeReader) track(b []byte) {
+	k := len(r.delim)
+	if len(b) >= k {
+		r.last = append(r.last[:0], b[len(b)-k:]...)
+		return
+	}
+	if len(r.last)+len(b) > k {
+		drop := len(r.last) + len(b) - k
+		r.last = append(r.last[:0], r.last[drop:]...)
+	}
+	r.last = append(r.last, b...)
+}
+
+// atDelim returns whether the bytes read so far end with a record delimiter.
+func (r *rangeReader) atDelim() bool {
+	return bytes.Equal(r.last, r.delim)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package csv
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+type nopSeekCloser struct {
+	*bytes.Reader
+}
+
+func (nopSeekCloser) Close() error { return nil }
+
+func readRange(t *testing.T, content, delim string, start, length int64) string {
+	t.Helper()
+	r, err := NewRangeReader(nopSeekCloser{bytes.NewReader([]byte(content))}, start, length, &ReaderArgs{FileHeaderInfo: none, RecordDelimiter: delim})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(io.LimitReader(r, int64(len(content))+1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestRangeReader(t *testing.T) {
+	const content = "a,1\nbb,2\nccc,3\n"
+	cases := []struct {
+		start, length int64
+		want          string
+	}{
+		{0, -1, content},
+		{0, 1, "a,1\n"},
+		{0, 4, "a,1\n"},
+		{0, 5, "a,1\nbb,2\n"},
+		{1, -1, "bb,2\nccc,3\n"},
+		{3, 1, ""},
+		{3, 2, "bb,2\n"},
+		{4, 1, "bb,2\n"},
+		{4, 5, "bb,2\n"},
+		{4, 6, "bb,2\nccc,3\n"},
+		{5, 3, ""},
+		{9, -1, "ccc,3\n"},
+		{10, -1, ""},
+		{14, 1, ""},
+	}
+	for _, c := range cases {
+		if got := readRange(t, content, "\n", c.start, c.length); got != c.want {
+			t.Errorf("range %d+%d: want %q, got %q", c.start, c.length, c.want, got)
+		}
+	}
+}
+
+func TestRangeReaderHeader(t *testing.T) {
+	const content = "a,b\n1,2\n3,4\n"
+	args := &ReaderArgs{FileHeaderInfo: use, RecordDelimiter: "\n"}
+	for start, want := range []string{content, "a,b\n1,2\n3,4\n", "a,b\n1,2\n3,4\n", "a,b\n1,2\n3,4\n", "a,b\n1,2\n3,4\n", "a,b\n3,4\n"} {
+		r, err := NewRangeReader(nopSeekCloser{bytes.NewReader([]byte(content))}, int64(start), -1, args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("start %d: want %q, got %q", start, want, got)
+		}
+	}
+}
+
+func TestRangeReaderSplits(t *testing.T) {
+	// Consecutive ranges must return every record exactly once.
+	for _, delim := range []string{"\n", "\r\n", "\t"} {
+		var sb strings.Builder
+		for i := 0; i < 50; i++ {
+			sb.WriteString(strings.Repeat("x", i%7))
+			sb.WriteString(",y")
+			sb.WriteString(delim)
+		}
+		content := sb.String()
+		for size := int64(1); size < 40; size++ {
+			var got string
+			for start := int64(0); start < int64(len(content)); start += size {
+				got += readRange(t, content, delim, start, size)
+			}
+			if got != content {
+				t.Fatalf("delimiter %q, range size %d: want %q, got %q", delim, size, content, got)
+			}
+		}
+	}
+}
@@ -17,7 +17,10 @@
 
 package s3select
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // SelectError - represents s3 select error specified in
 // https://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectSELECTContent.html#RESTObjectSELECTContent-responses-special-errors.
@@ -83,10 +86,19 @@ func errInvalidCompression(err error, t CompressionType) *s3Error {
 	}
 }
 
+func errDecompressedSizeTooLarge(err error, ratio int64) *s3Error {
+	return &s3Error{
+		code:       "DecompressedSizeTooLarge",
+		message:    fmt.Sprintf("The decompressed object is more than %d times larger than the object.", ratio),
+		statusCode: 400,
+		cause:      err,
+	}
+}
+
 func errInvalidDataSource(err error) *s3Error {
 	return &s3Error{
 		code:       "InvalidDataSource",
-		message:    "Invalid data source type. Only CSV, JSON, and Parquet are supported.",
+		message:    "Invalid data source type. Only CSV, JSON, Parquet, ORC and Avro are supported.",
 		statusCode: 400,
 		cause:      err,
 	}
@@ -110,6 +122,15 @@ func errInvalidScanRangeParameter(err error) *s3Error {
 	}
 }
 
+func errUnsupportedScanRangeInput(err error) *s3Error {
+	return &s3Error{
+		code:       "UnsupportedScanRangeInput",
+		message:    "Scan range queries are not supported on this type of object.",
+		statusCode: 400,
+		cause:      err,
+	}
+}
+
 func errObjectSerializationConflict(err error) *s3Error {
 	return &s3Error{
 		code:       "ObjectSerializationConflict",
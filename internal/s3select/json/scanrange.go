@@ -0,0 +1,124 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package json
+
+import (
+	"bufio"
+	"io"
+)
+
+// rangeReader returns the lines of an object which start inside a byte
+// range. A line which starts before the range is skipped, a line which
+// starts inside the range is read until its end, even if the end is after
+// the range.
+type rangeReader struct {
+	r         *bufio.Reader
+	closer    io.Closer
+	atEOL     bool  // whether the last byte read was a newline
+	remaining int64 // bytes left in the range, negative if unbounded
+	done      bool
+}
+
+// NewRangeReader returns a reader for the JSON Lines records of rsc which
+// start inside the scan range of length bytes at offset start. A negative
+// length selects all records after start.
+func NewRangeReader(rsc io.ReadSeekCloser, start, length int64) (io.ReadCloser, error) {
+	r := &rangeReader{
+		closer:    rsc,
+		remaining: length,
+	}
+
+	// Start reading at the byte before the range, so a line
+	// starting at the first byte of the range is found as well.
+	before := int64(0)
+	if start > 0 {
+		before = 1
+	}
+	if _, err := rsc.Seek(start-before, io.SeekStart); err != nil {
+		return nil, err
+	}
+	r.r = bufio.NewReaderSize(rsc, jsonSplitSize)
+	if start == 0 {
+		return r, nil
+	}
+
+	// Skip to the end of the line containing the byte before the range.
+	skipped := -before
+	for {
+		line, err := r.r.ReadSlice('\n')
+		skipped += int64(len(line))
+		if err == io.EOF {
+			r.done = true
+			return r, nil
+		}
+		if err == nil {
+			break
+		}
+		if err != bufio.ErrBufferFull {
+			return nil, err
+		}
+	}
+	r.atEOL = true
+	if length >= 0 {
+		r.remaining -= skipped
+		if r.remaining <= 0 {
+			r.done = true
+		}
+	}
+	return r, nil
+}
+
+func (r *rangeReader) Read(p []byte) (n int, err error) {
+	if r.done {
+		return 0, io.EOF
+	}
+	if r.remaining != 0 {
+		if r.remaining > 0 && int64(len(p)) > r.remaining {
+			p = p[:r.remaining]
+		}
+		n, err = r.r.Read(p)
+		if n > 0 {
+			r.atEOL = p[n-1] == '\n'
+		}
+		if r.remaining > 0 {
+			r.remaining -= int64(n)
+			if r.remaining == 0 && r.atEOL {
+				r.done = true
+			}
+		}
+		return n, err
+	}
+
+	// Past the end of the range, complete the last line.
+	for n < len(p) {
+		if p[n], err = r.r.ReadByte(); err != nil {
+			return n, err
+		}
+		n++
+		if p[n-1] == '\n' {
+			r.done = true
+			break
+		}
+	}
+	return n, nil
+}
+
+// Close closes the underlying reader.
+func (r *rangeReader) Close() error {
+	return r.closer.Close()
+}
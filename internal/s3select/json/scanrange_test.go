@@ -0,0 +1,70 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package json
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+type nopSeekCloser struct {
+	*bytes.Reader
+}
+
+func (nopSeekCloser) Close() error { return nil }
+
+func TestRangeReader(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 50; i++ {
+		sb.WriteString(`{"a":"`)
+		sb.WriteString(strings.Repeat("x", i%7))
+		sb.WriteString("\"}\n")
+	}
+	content := sb.String()
+
+	readRange := func(start, length int64) string {
+		r, err := NewRangeReader(nopSeekCloser{bytes.NewReader([]byte(content))}, start, length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	if got := readRange(0, -1); got != content {
+		t.Fatalf("want %q, got %q", content, got)
+	}
+	if got, want := readRange(1, 10), content[9:19]; got != want {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+	// Consecutive ranges must return every line exactly once.
+	for size := int64(1); size < 40; size++ {
+		var got string
+		for start := int64(0); start < int64(len(content)); start += size {
+			got += readRange(start, size)
+		}
+		if got != content {
+			t.Fatalf("range size %d: want %q, got %q", size, content, got)
+		}
+	}
+}
@@ -0,0 +1,515 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package orc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/bcicen/jstream"
+	"github.com/infobsmi/b33s/internal/s3select/sql"
+)
+
+const (
+	// maxNestingDepth limits the nesting of the schema.
+	maxNestingDepth = 128
+
+	// maxListLength limits the number of elements of lists and maps.
+	maxListLength = 1 << 20
+
+	// maxDecimalBytes limits the encoded size of decimals, 38 digits
+	// require 19 bytes.
+	maxDecimalBytes = 32
+)
+
+// column reads the values of a column of a stripe. next is only called
+// for rows in which the parent value is present.
+type column interface {
+	next() (interface{}, error)
+}
+
+// stripe holds the streams of the columns of a stripe.
+type stripe struct {
+	streams   map[streamKey][]byte
+	encodings []columnEncoding
+	location  *time.Location
+	d         *decompressor
+}
+
+type streamKey struct {
+	column uint32
+	kind   streamKind
+}
+
+// stream returns the reader of a stream, missing streams are empty.
+func (s *stripe) stream(id int, kind streamKind) *streamReader {
+	return newStreamReader(s.streams[streamKey{column: uint32(id), kind: kind}], s.d)
+}
+
+func (s *stripe) encoding(id int) encodingKind {
+	if id < len(s.encodings) {
+		return s.encodings[id].kind
+	}
+	return encodingDirect
+}
+
+// newColumn creates the reader of the column id and its children.
+func newColumn(types []orcType, id int, s *stripe, depth int) (column, error) {
+	if depth > maxNestingDepth {
+		return nil, errors.New("schema is nested too deeply")
+	}
+	t := &types[id]
+	for _, sub := range t.subtypes {
+		// Types are stored in pre-order, which also rules out cycles.
+		if int(sub) <= id || int(sub) >= len(types) {
+			return nil, fmt.Errorf("invalid subtype %d of column %d", sub, id)
+		}
+	}
+	var p presence
+	if _, ok := s.streams[streamKey{column: uint32(id), kind: streamPresent}]; ok {
+		p.present = newBoolRLE(s.stream(id, streamPresent))
+	}
+	encoding := s.encoding(id)
+
+	switch t.kind {
+	case kindBoolean:
+		return &boolColumn{presence: p, data: newBoolRLE(s.stream(id, streamData))}, nil
+	case kindByte:
+		return &byteColumn{presence: p, data: byteRLE{r: s.stream(id, streamData)}}, nil
+	case kindShort, kindInt, kindLong:
+		return &intColumn{presence: p, data: newIntReader(s.stream(id, streamData), encoding, true)}, nil
+	case kindFloat, kindDouble:
+		return &floatColumn{presence: p, data: s.stream(id, streamData), double: t.kind == kindDouble}, nil
+	case kindString, kindBinary, kindVarchar, kindChar:
+		if encoding == encodingDictionary || encoding == encodingDictionaryV2 {
+			dict, err := readDictionary(s.encodings[id].dictionarySize,
+				newIntReader(s.stream(id, streamLength), encoding, false), s.stream(id, streamDictionaryData))
+			if err != nil {
+				return nil, fmt.Errorf("reading dictionary of column %d: %w", id, err)
+			}
+			return &dictionaryColumn{presence: p, data: newIntReader(s.stream(id, streamData), encoding, false), dict: dict}, nil
+		}
+		return &stringColumn{presence: p, data: s.stream(id, streamData), length: newIntReader(s.stream(id, streamLength), encoding, false)}, nil
+	case kindDecimal:
+		return &decimalColumn{presence: p, data: s.stream(id, streamData), scale: newIntReader(s.stream(id, streamSecondary), encoding, true)}, nil
+	case kindDate:
+		return &dateColumn{presence: p, data: newIntReader(s.stream(id, streamData), encoding, true)}, nil
+	case kindTimestamp, kindTimestampInstant:
+		// Timestamps are stored as seconds since 2015-01-01, in the time
+		// zone of the writer unless they are instants.
+		loc := time.UTC
+		if t.kind == kindTimestamp {
+			loc = s.location
+		}
+		return &timestampColumn{
+			presence: p,
+			data:     newIntReader(s.stream(id, streamData), encoding, true),
+			nanos:    newIntReader(s.stream(id, streamSecondary), encoding, false),
+			epoch:    time.Date(2015, 1, 1, 0, 0, 0, 0, loc).Unix(),
+			location: loc,
+		}, nil
+	case kindList, kindMap:
+		if len(t.subtypes) != int(t.kind-kindList)+1 {
+			return nil, fmt.Errorf("invalid subtypes of column %d", id)
+		}
+		c := &listColumn{presence: p, length: newIntReader(s.stream(id, streamLength), encoding, false)}
+		var err error
+		if c.items, err = newColumn(types, int(t.subtypes[0]), s, depth+1); err != nil {
+			return nil, err
+		}
+		if t.kind == kindMap {
+			c.keys = c.items
+			if c.items, err = newColumn(types, int(t.subtypes[1]), s, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return c, nil
+	case kindStruct:
+		if len(t.fieldNames) != len(t.subtypes) {
+			return nil, fmt.Errorf("invalid field names of column %d", id)
+		}
+		c := &structColumn{presence: p, names: t.fieldNames}
+		for _, sub := range t.subtypes {
+			f, err := newColumn(types, int(sub), s, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			c.fields = append(c.fields, f)
+		}
+		return c, nil
+	case kindUnion:
+		c := &unionColumn{presence: p, tags: byteRLE{r: s.stream(id, streamData)}}
+		for _, sub := range t.subtypes {
+			f, err := newColumn(types, int(sub), s, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			c.children = append(c.children, f)
+		}
+		return c, nil
+	}
+	return nil, fmt.Errorf("unsupported type %d of column %d", t.kind, id)
+}
+
+// presence reads the optional PRESENT stream of a column.
+type presence struct {
+	present *boolRLE
+}
+
+func (p *presence) null() (bool, error) {
+	if p.present == nil {
+		return false, nil
+	}
+	present, err := p.present.next()
+	return !present, err
+}
+
+type boolColumn struct {
+	presence
+	data *boolRLE
+}
+
+func (c *boolColumn) next() (interface{}, error) {
+	if null, err := c.null(); null || err != nil {
+		return nil, err
+	}
+	return c.data.next()
+}
+
+type byteColumn struct {
+	presence
+	data byteRLE
+}
+
+func (c *byteColumn) next() (interface{}, error) {
+	if null, err := c.null(); null || err != nil {
+		return nil, err
+	}
+	b, err := c.data.next()
+	return int64(int8(b)), err
+}
+
+type intColumn struct {
+	presence
+	data intReader
+}
+
+func (c *intColumn) next() (interface{}, error) {
+	if null, err := c.null(); null || err != nil {
+		return nil, err
+	}
+	return c.data.next()
+}
+
+type floatColumn struct {
+	presence
+	data   byteStream
+	double bool
+	buf    [8]byte
+}
+
+func (c *floatColumn) next() (interface{}, error) {
+	if null, err := c.null(); null || err != nil {
+		return nil, err
+	}
+	if c.double {
+		if _, err := io.ReadFull(c.data, c.buf[:8]); err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(c.buf[:8])), nil
+	}
+	if _, err := io.ReadFull(c.data, c.buf[:4]); err != nil {
+		return nil, err
+	}
+	return float64(math.Float32frombits(binary.LittleEndian.Uint32(c.buf[:4]))), nil
+}
+
+// stringColumn reads directly encoded strings and binary values, which
+// are returned as strings.
+type stringColumn struct {
+	presence
+	data   byteStream
+	length intReader
+}
+
+func (c *stringColumn) next() (interface{}, error) {
+	if null, err := c.null(); null || err != nil {
+		return nil, err
+	}
+	n, err := c.length.next()
+	if err != nil {
+		return nil, err
+	}
+	return readString(c.data, n)
+}
+
+type dictionaryColumn struct {
+	presence
+	data intReader
+	dict []string
+}
+
+func (c *dictionaryColumn) next() (interface{}, error) {
+	if null, err := c.null(); null || err != nil {
+		return nil, err
+	}
+	i, err := c.data.next()
+	if err != nil {
+		return nil, err
+	}
+	if i < 0 || i >= int64(len(c.dict)) {
+		return nil, fmt.Errorf("invalid dictionary index %d", i)
+	}
+	return c.dict[i], nil
+}
+
+func readDictionary(size uint32, lengths intReader, data byteStream) ([]string, error) {
+	var dict []string
+	for i := uint32(0); i < size; i++ {
+		n, err := lengths.next()
+		if err != nil {
+			return nil, err
+		}
+		s, err := readString(data, n)
+		if err != nil {
+			return nil, err
+		}
+		dict = append(dict, s)
+	}
+	return dict, nil
+}
+
+// readString reads n bytes, large values are only allocated as they
+// are read.
+func readString(r byteStream, n int64) (string, error) {
+	if n < 0 {
+		return "", fmt.Errorf("invalid length %d", n)
+	}
+	if n <= 64<<10 {
+		b := make([]byte, n)
+		_, err := io.ReadFull(r, b)
+		return string(b), err
+	}
+	var b bytes.Buffer
+	if _, err := io.CopyN(&b, r, n); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// decimalColumn reads decimals, the unscaled values are stored as
+// signed varints of unbounded length and the scales in a separate
+// stream.
+type decimalColumn struct {
+	presence
+	data     byteStream
+	scale    intReader
+	unscaled big.Int
+	digit    big.Int
+}
+
+func (c *decimalColumn) next() (interface{}, error) {
+	if null, err := c.null(); null || err != nil {
+		return nil, err
+	}
+	v := &c.unscaled
+	v.SetInt64(0)
+	for i := uint(0); ; i++ {
+		b, err := c.data.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if i == maxDecimalBytes {
+			return nil, errors.New("decimal value too large")
+		}
+		c.digit.SetUint64(uint64(b & 0x7f))
+		v.Or(v, c.digit.Lsh(&c.digit, 7*i))
+		if b < 0x80 {
+			break
+		}
+	}
+	// Zig-zag decoding.
+	negative := v.Bit(0) == 1
+	v.Rsh(v, 1)
+	if negative {
+		v.Not(v)
+	}
+
+	scale, err := c.scale.next()
+	if err != nil {
+		return nil, err
+	}
+	if scale == 0 && v.IsInt64() {
+		return v.Int64(), nil
+	}
+	f, _ := strconv.ParseFloat(v.String()+"e"+strconv.FormatInt(-scale, 10), 64)
+	return f, nil
+}
+
+// dateColumn reads dates, stored as days since the epoch.
+type dateColumn struct {
+	presence
+	data intReader
+}
+
+func (c *dateColumn) next() (interface{}, error) {
+	if null, err := c.null(); null || err != nil {
+		return nil, err
+	}
+	days, err := c.data.next()
+	if err != nil {
+		return nil, err
+	}
+	return sql.FormatSQLTimestamp(time.Unix(days*24*60*60, 0).UTC()), nil
+}
+
+type timestampColumn struct {
+	presence
+	data     intReader
+	nanos    intReader
+	epoch    int64
+	location *time.Location
+}
+
+func (c *timestampColumn) next() (interface{}, error) {
+	if null, err := c.null(); null || err != nil {
+		return nil, err
+	}
+	secs, err := c.data.next()
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := c.nanos.next()
+	if err != nil {
+		return nil, err
+	}
+	// The low 3 bits hold the number of trailing decimal zeros removed
+	// from the nanoseconds, minus one.
+	nanos := encoded >> 3
+	if zeros := encoded & 7; zeros != 0 {
+		for i := int64(0); i <= zeros; i++ {
+			nanos *= 10
+		}
+	}
+	secs += c.epoch
+	// Writers truncate negative timestamps towards zero.
+	if secs < 0 && nanos > 999999 {
+		secs--
+	}
+	return sql.FormatSQLTimestamp(time.Unix(secs, nanos).In(c.location)), nil
+}
+
+// listColumn reads lists and maps, maps are returned as jstream.KVS and
+// lists as []interface{} so they can be evaluated like JSON values.
+type listColumn struct {
+	presence
+	length intReader
+	keys   column
+	items  column
+}
+
+func (c *listColumn) next() (interface{}, error) {
+	if null, err := c.null(); null || err != nil {
+		return nil, err
+	}
+	n, err := c.length.next()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || n > maxListLength {
+		return nil, fmt.Errorf("invalid length %d", n)
+	}
+
+	if c.keys == nil {
+		items := make([]interface{}, 0, n)
+		for i := int64(0); i < n; i++ {
+			v, err := c.items.next()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	}
+
+	kvs := make(jstream.KVS, 0, n)
+	for i := int64(0); i < n; i++ {
+		k, err := c.keys.next()
+		if err != nil {
+			return nil, err
+		}
+		v, err := c.items.next()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			key = fmt.Sprint(k)
+		}
+		kvs = append(kvs, jstream.KV{Key: key, Value: v})
+	}
+	return kvs, nil
+}
+
+type structColumn struct {
+	presence
+	names  []string
+	fields []column
+}
+
+func (c *structColumn) next() (interface{}, error) {
+	if null, err := c.null(); null || err != nil {
+		return nil, err
+	}
+	kvs := make(jstream.KVS, 0, len(c.fields))
+	for i, f := range c.fields {
+		v, err := f.next()
+		if err != nil {
+			return nil, err
+		}
+		kvs = append(kvs, jstream.KV{Key: c.names[i], Value: v})
+	}
+	return kvs, nil
+}
+
+type unionColumn struct {
+	presence
+	tags     byteRLE
+	children []column
+}
+
+func (c *unionColumn) next() (interface{}, error) {
+	if null, err := c.null(); null || err != nil {
+		return nil, err
+	}
+	tag, err := c.tags.next()
+	if err != nil {
+		return nil, err
+	}
+	if int(tag) >= len(c.children) {
+		return nil, fmt.Errorf("invalid union tag %d", tag)
+	}
+	return c.children[tag].next()
+}
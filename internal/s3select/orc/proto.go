@@ -0,0 +1,299 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package orc
+
+import (
+	"errors"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The file tail and stripe footers are protobuf messages, only the
+// fields needed to read the rows are decoded. See
+// https://orc.apache.org/specification/ORCv1/
+
+type compressionKind uint64
+
+const (
+	compressionNone compressionKind = iota
+	compressionZlib
+	compressionSnappy
+	compressionLzo
+	compressionLz4
+	compressionZstd
+)
+
+type typeKind uint64
+
+const (
+	kindBoolean typeKind = iota
+	kindByte
+	kindShort
+	kindInt
+	kindLong
+	kindFloat
+	kindDouble
+	kindString
+	kindBinary
+	kindTimestamp
+	kindList
+	kindMap
+	kindStruct
+	kindUnion
+	kindDecimal
+	kindDate
+	kindVarchar
+	kindChar
+	kindTimestampInstant
+)
+
+type streamKind uint64
+
+const (
+	streamPresent streamKind = iota
+	streamData
+	streamLength
+	streamDictionaryData
+	streamDictionaryCount
+	streamSecondary
+	streamRowIndex
+)
+
+type encodingKind uint64
+
+const (
+	encodingDirect encodingKind = iota
+	encodingDictionary
+	encodingDirectV2
+	encodingDictionaryV2
+)
+
+type postScript struct {
+	footerLength         uint64
+	compression          compressionKind
+	compressionBlockSize uint64
+	magic                string
+}
+
+type fileFooter struct {
+	stripes      []stripeInformation
+	types        []orcType
+	numberOfRows uint64
+}
+
+type stripeInformation struct {
+	offset       uint64
+	indexLength  uint64
+	dataLength   uint64
+	footerLength uint64
+	numberOfRows uint64
+}
+
+type orcType struct {
+	kind       typeKind
+	subtypes   []uint32
+	fieldNames []string
+}
+
+type stripeFooter struct {
+	streams        []streamInformation
+	columns        []columnEncoding
+	writerTimezone string
+}
+
+type streamInformation struct {
+	kind   streamKind
+	column uint32
+	length uint64
+}
+
+type columnEncoding struct {
+	kind           encodingKind
+	dictionarySize uint32
+}
+
+// protoField is a field of a protobuf message, either a varint or the
+// content of a length delimited field.
+type protoField struct {
+	num    protowire.Number
+	varint uint64
+	bytes  []byte
+}
+
+// parseMessage calls fn for all varint and length delimited fields of
+// the message, other fields are skipped.
+func parseMessage(b []byte, fn func(f protoField) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		f := protoField{num: num}
+		switch typ {
+		case protowire.VarintType:
+			f.varint, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			f.bytes, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n >= 0 {
+				b = b[n:]
+				continue
+			}
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendUint32s appends the values of a repeated uint32 field, which
+// may be packed.
+func (f protoField) appendUint32s(dst []uint32) ([]uint32, error) {
+	if f.bytes == nil {
+		return append(dst, uint32(f.varint)), nil
+	}
+	b := f.bytes
+	for len(b) > 0 {
+		v, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		dst = append(dst, uint32(v))
+		b = b[n:]
+	}
+	return dst, nil
+}
+
+func parsePostScript(b []byte) (ps postScript, err error) {
+	err = parseMessage(b, func(f protoField) error {
+		switch f.num {
+		case 1:
+			ps.footerLength = f.varint
+		case 2:
+			ps.compression = compressionKind(f.varint)
+		case 3:
+			ps.compressionBlockSize = f.varint
+		case 8000:
+			ps.magic = string(f.bytes)
+		}
+		return nil
+	})
+	return ps, err
+}
+
+func parseFileFooter(b []byte) (*fileFooter, error) {
+	var footer fileFooter
+	err := parseMessage(b, func(f protoField) error {
+		switch f.num {
+		case 3:
+			var si stripeInformation
+			err := parseMessage(f.bytes, func(f protoField) error {
+				switch f.num {
+				case 1:
+					si.offset = f.varint
+				case 2:
+					si.indexLength = f.varint
+				case 3:
+					si.dataLength = f.varint
+				case 4:
+					si.footerLength = f.varint
+				case 5:
+					si.numberOfRows = f.varint
+				}
+				return nil
+			})
+			footer.stripes = append(footer.stripes, si)
+			return err
+		case 4:
+			var t orcType
+			err := parseMessage(f.bytes, func(f protoField) (err error) {
+				switch f.num {
+				case 1:
+					t.kind = typeKind(f.varint)
+				case 2:
+					t.subtypes, err = f.appendUint32s(t.subtypes)
+				case 3:
+					t.fieldNames = append(t.fieldNames, string(f.bytes))
+				}
+				return err
+			})
+			footer.types = append(footer.types, t)
+			return err
+		case 6:
+			footer.numberOfRows = f.varint
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(footer.types) == 0 {
+		return nil, errors.New("missing schema in file footer")
+	}
+	return &footer, nil
+}
+
+func parseStripeFooter(b []byte) (*stripeFooter, error) {
+	var footer stripeFooter
+	err := parseMessage(b, func(f protoField) error {
+		switch f.num {
+		case 1:
+			var s streamInformation
+			err := parseMessage(f.bytes, func(f protoField) error {
+				switch f.num {
+				case 1:
+					s.kind = streamKind(f.varint)
+				case 2:
+					s.column = uint32(f.varint)
+				case 3:
+					s.length = f.varint
+				}
+				return nil
+			})
+			footer.streams = append(footer.streams, s)
+			return err
+		case 2:
+			var e columnEncoding
+			err := parseMessage(f.bytes, func(f protoField) error {
+				switch f.num {
+				case 1:
+					e.kind = encodingKind(f.varint)
+				case 2:
+					e.dictionarySize = uint32(f.varint)
+				}
+				return nil
+			})
+			footer.columns = append(footer.columns, e)
+			return err
+		case 3:
+			footer.writerTimezone = string(f.bytes)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &footer, nil
+}
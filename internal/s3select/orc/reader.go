@@ -0,0 +1,254 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package orc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/bcicen/jstream"
+	jsonfmt "github.com/infobsmi/b33s/internal/s3select/json"
+	"github.com/infobsmi/b33s/internal/s3select/sql"
+)
+
+const (
+	magic = "ORC"
+
+	// maxFooterSize limits the size of the file and stripe footers.
+	maxFooterSize = 64 << 20
+
+	// maxStripeSize limits the size of the data of a stripe, which is
+	// read into memory at once. Writers use 64MiB by default.
+	maxStripeSize = 1 << 30
+
+	// tailSize is the number of bytes read from the end of the file,
+	// which usually includes the footer.
+	tailSize = 16 << 10
+)
+
+// Reader - ORC file reader for S3Select.
+type Reader struct {
+	r      io.ReaderAt
+	closer io.Closer
+	size   int64
+
+	footer *fileFooter
+	d      *decompressor
+
+	stripe int    // index of the next stripe
+	rows   uint64 // rows left in the current stripe
+	root   column
+	data   []byte
+}
+
+// Read - reads single record.
+// Once Read is called the previous record should no longer be referenced.
+func (r *Reader) Read(dst sql.Record) (sql.Record, error) {
+	for r.rows == 0 {
+		if r.stripe == len(r.footer.stripes) {
+			return nil, io.EOF
+		}
+		if err := r.openStripe(); err != nil {
+			return nil, errORCParsingError(err)
+		}
+	}
+
+	v, err := r.root.next()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, errORCParsingError(err)
+	}
+	r.rows--
+
+	kvs, ok := v.(jstream.KVS)
+	if !ok || r.footer.types[0].kind != kindStruct {
+		// Rows which are not structs are returned as a single column.
+		kvs = jstream.KVS{{Key: "_1", Value: v}}
+	}
+
+	// Reuse destination if we can.
+	dstRec, ok := dst.(*jsonfmt.Record)
+	if !ok {
+		dstRec = &jsonfmt.Record{}
+	}
+	dstRec.SelectFormat = sql.SelectFmtORC
+	dstRec.KVS = kvs
+	return dstRec, nil
+}
+
+// Close - closes underlying reader.
+func (r *Reader) Close() error {
+	if r.d != nil {
+		r.d.close()
+	}
+	return r.closer.Close()
+}
+
+// readTail reads the postscript and the file footer.
+func (r *Reader) readTail() error {
+	var header [len(magic)]byte
+	if _, err := r.r.ReadAt(header[:], 0); err != nil || string(header[:]) != magic {
+		return errors.New("not an ORC file")
+	}
+
+	n := int64(tailSize)
+	if n > r.size {
+		n = r.size
+	}
+	tail := make([]byte, n)
+	if _, err := r.r.ReadAt(tail, r.size-n); err != nil {
+		return err
+	}
+	psLen := int64(tail[n-1])
+	if psLen+1 > n {
+		return errors.New("invalid postscript length")
+	}
+	ps, err := parsePostScript(tail[n-1-psLen : n-1])
+	if err != nil {
+		return fmt.Errorf("invalid postscript: %w", err)
+	}
+	if ps.magic != "" && ps.magic != magic {
+		return errors.New("invalid postscript magic")
+	}
+	if r.d, err = newDecompressor(ps.compression, ps.compressionBlockSize); err != nil {
+		return err
+	}
+
+	footerEnd := r.size - 1 - psLen
+	if ps.footerLength > maxFooterSize || int64(ps.footerLength) > footerEnd-int64(len(magic)) {
+		return errors.New("invalid footer length")
+	}
+	footerLen := int64(ps.footerLength)
+	var footer []byte
+	if footerLen <= n-1-psLen {
+		footer = tail[n-1-psLen-footerLen : n-1-psLen]
+	} else {
+		footer = make([]byte, footerLen)
+		if _, err = r.r.ReadAt(footer, footerEnd-footerLen); err != nil {
+			return err
+		}
+	}
+	if footer, err = r.d.readAll(footer); err != nil {
+		return fmt.Errorf("invalid footer: %w", err)
+	}
+	if r.footer, err = parseFileFooter(footer); err != nil {
+		return fmt.Errorf("invalid footer: %w", err)
+	}
+	return nil
+}
+
+// openStripe reads the data and the footer of the next stripe.
+func (r *Reader) openStripe() error {
+	si := r.footer.stripes[r.stripe]
+	r.stripe++
+
+	dataStart := si.offset + si.indexLength
+	footerStart := dataStart + si.dataLength
+	if si.offset < uint64(len(magic)) || dataStart < si.offset || footerStart < dataStart ||
+		si.dataLength > maxStripeSize || si.footerLength > maxFooterSize ||
+		footerStart+si.footerLength < footerStart || footerStart+si.footerLength > uint64(r.size) {
+		return fmt.Errorf("invalid stripe %d", r.stripe-1)
+	}
+
+	// The data streams are read at once, index streams are skipped.
+	size := si.dataLength + si.footerLength
+	if uint64(cap(r.data)) < size {
+		r.data = make([]byte, size)
+	}
+	r.data = r.data[:size]
+	if _, err := r.r.ReadAt(r.data, int64(dataStart)); err != nil {
+		return err
+	}
+	footerData, err := r.d.readAll(r.data[si.dataLength:])
+	if err != nil {
+		return fmt.Errorf("invalid stripe footer: %w", err)
+	}
+	footer, err := parseStripeFooter(footerData)
+	if err != nil {
+		return fmt.Errorf("invalid stripe footer: %w", err)
+	}
+
+	s := &stripe{
+		streams:   make(map[streamKey][]byte),
+		encodings: footer.columns,
+		location:  time.UTC,
+		d:         r.d,
+	}
+	if footer.writerTimezone != "" {
+		if loc, err := time.LoadLocation(footer.writerTimezone); err == nil {
+			s.location = loc
+		}
+	}
+	offset := si.offset
+	for _, st := range footer.streams {
+		end := offset + st.length
+		if end < offset || end > footerStart {
+			return fmt.Errorf("invalid stream of column %d in stripe %d", st.column, r.stripe-1)
+		}
+		if offset >= dataStart {
+			switch st.kind {
+			case streamPresent, streamData, streamLength, streamDictionaryData, streamSecondary:
+				s.streams[streamKey{column: st.column, kind: st.kind}] = r.data[offset-dataStart : end-dataStart]
+			}
+		}
+		offset = end
+	}
+
+	if r.root, err = newColumn(r.footer.types, 0, s, 0); err != nil {
+		return err
+	}
+	r.rows = si.numberOfRows
+	return nil
+}
+
+// readerAt implements io.ReaderAt using a seeker, it must not be used
+// concurrently.
+type readerAt struct {
+	rs io.ReadSeeker
+}
+
+func (r readerAt) ReadAt(p []byte, off int64) (int, error) {
+	if _, err := r.rs.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.ReadFull(r.rs, p)
+}
+
+// NewReader - creates new ORC reader using rsc.
+func NewReader(rsc io.ReadSeekCloser, _ *ReaderArgs) (*Reader, error) {
+	// Seeking to the end of an object is not supported by all readers.
+	last, err := rsc.Seek(-1, io.SeekEnd)
+	if err != nil {
+		return nil, errORCParsingError(err)
+	}
+	r := &Reader{r: readerAt{rs: rsc}, closer: rsc, size: last + 1}
+	if ra, ok := rsc.(io.ReaderAt); ok {
+		r.r = ra
+	}
+	if err = r.readTail(); err != nil {
+		if r.d != nil {
+			r.d.close()
+		}
+		return nil, errORCParsingError(err)
+	}
+	return r, nil
+}
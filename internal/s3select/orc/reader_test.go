@@ -0,0 +1,469 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package orc
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bcicen/jstream"
+	jsonfmt "github.com/infobsmi/b33s/internal/s3select/json"
+	"github.com/infobsmi/b33s/internal/s3select/sql"
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type nopReadSeekCloser struct {
+	*bytes.Reader
+}
+
+func (nopReadSeekCloser) Close() error { return nil }
+
+// Encoders of the test file streams.
+
+func boolsRLE(bits ...bool) []byte {
+	var b []byte
+	for i, bit := range bits {
+		if i%8 == 0 {
+			b = append(b, 0)
+		}
+		if bit {
+			b[len(b)-1] |= 0x80 >> (i % 8)
+		}
+	}
+	return bytesRLE(b...)
+}
+
+func bytesRLE(b ...byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	return append([]byte{byte(256 - len(b))}, b...)
+}
+
+// intsV1 encodes literals of version 1 of the integer encoding.
+func intsV1(signed bool, values ...int64) []byte {
+	b := []byte{byte(256 - len(values))}
+	var buf [binary.MaxVarintLen64]byte
+	for _, v := range values {
+		if signed {
+			b = append(b, buf[:binary.PutVarint(buf[:], v)]...)
+		} else {
+			b = append(b, buf[:binary.PutUvarint(buf[:], uint64(v))]...)
+		}
+	}
+	return b
+}
+
+// intsV2 encodes 64 bit values of version 2 of the integer encoding.
+func intsV2(signed bool, values ...int64) []byte {
+	n := len(values) - 1
+	b := []byte{0x40 | 31<<1 | byte(n>>8), byte(n)}
+	var buf [8]byte
+	for _, v := range values {
+		u := uint64(v)
+		if signed {
+			u = uint64(v<<1) ^ uint64(v>>63)
+		}
+		binary.BigEndian.PutUint64(buf[:], u)
+		b = append(b, buf[:]...)
+	}
+	return b
+}
+
+func doubles(values ...float64) []byte {
+	b := make([]byte, 8*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint64(b[8*i:], math.Float64bits(v))
+	}
+	return b
+}
+
+func floats(values ...float32) []byte {
+	b := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(v))
+	}
+	return b
+}
+
+func decimals(values ...int64) []byte {
+	var b []byte
+	var buf [binary.MaxVarintLen64]byte
+	for _, v := range values {
+		b = append(b, buf[:binary.PutVarint(buf[:], v)]...)
+	}
+	return b
+}
+
+type testStream struct {
+	column uint32
+	kind   streamKind
+	data   []byte
+}
+
+type testStripe struct {
+	rows      uint64
+	timezone  string
+	encodings []encodingKind
+	dictSize  map[int]uint32
+	index     []testStream
+	streams   []testStream
+}
+
+// testFile builds an ORC file.
+type testFile struct {
+	compression compressionKind
+	blockSize   int
+	types       []orcType
+	stripes     []testStripe
+}
+
+// compress splits data into compressed chunks.
+func (f *testFile) compress(data []byte) []byte {
+	if f.compression == compressionNone {
+		return data
+	}
+	var out []byte
+	for len(data) > 0 {
+		n := f.blockSize
+		if n > len(data) {
+			n = len(data)
+		}
+		chunk := data[:n]
+		data = data[n:]
+
+		var compressed []byte
+		switch f.compression {
+		case compressionZlib:
+			var buf bytes.Buffer
+			w, _ := flate.NewWriter(&buf, flate.BestCompression)
+			w.Write(chunk)
+			w.Close()
+			compressed = buf.Bytes()
+		case compressionSnappy:
+			compressed = s2.EncodeSnappy(nil, chunk)
+		case compressionLz4:
+			compressed = make([]byte, lz4.CompressBlockBound(len(chunk)))
+			n, _ := lz4.CompressBlock(chunk, compressed, nil)
+			compressed = compressed[:n]
+		case compressionZstd:
+			enc, _ := zstd.NewWriter(nil)
+			compressed = enc.EncodeAll(chunk, nil)
+			enc.Close()
+		}
+		h := len(compressed) << 1
+		if len(compressed) == 0 || len(compressed) >= len(chunk) {
+			h, compressed = len(chunk)<<1|1, chunk
+		}
+		out = append(out, byte(h), byte(h>>8), byte(h>>16))
+		out = append(out, compressed...)
+	}
+	return out
+}
+
+func appendVarintField(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendBytesField(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func (f *testFile) bytes() []byte {
+	data := []byte(magic)
+	var footer []byte
+	var rows uint64
+	for _, s := range f.stripes {
+		start := len(data)
+		var sf []byte
+		writeStreams := func(streams []testStream) {
+			for _, st := range streams {
+				compressed := f.compress(st.data)
+				data = append(data, compressed...)
+				var msg []byte
+				msg = appendVarintField(msg, 1, uint64(st.kind))
+				msg = appendVarintField(msg, 2, uint64(st.column))
+				msg = appendVarintField(msg, 3, uint64(len(compressed)))
+				sf = appendBytesField(sf, 1, msg)
+			}
+		}
+		writeStreams(s.index)
+		indexEnd := len(data)
+		writeStreams(s.streams)
+		dataEnd := len(data)
+		for id, kind := range s.encodings {
+			var msg []byte
+			msg = appendVarintField(msg, 1, uint64(kind))
+			if n, ok := s.dictSize[id]; ok {
+				msg = appendVarintField(msg, 2, uint64(n))
+			}
+			sf = appendBytesField(sf, 2, msg)
+		}
+		if s.timezone != "" {
+			sf = appendBytesField(sf, 3, []byte(s.timezone))
+		}
+		data = append(data, f.compress(sf)...)
+
+		var si []byte
+		si = appendVarintField(si, 1, uint64(start))
+		si = appendVarintField(si, 2, uint64(indexEnd-start))
+		si = appendVarintField(si, 3, uint64(dataEnd-indexEnd))
+		si = appendVarintField(si, 4, uint64(len(data)-dataEnd))
+		si = appendVarintField(si, 5, s.rows)
+		footer = appendBytesField(footer, 3, si)
+		rows += s.rows
+	}
+	for _, t := range f.types {
+		var msg []byte
+		msg = appendVarintField(msg, 1, uint64(t.kind))
+		if len(t.subtypes) > 0 {
+			var packed []byte
+			for _, sub := range t.subtypes {
+				packed = protowire.AppendVarint(packed, uint64(sub))
+			}
+			msg = appendBytesField(msg, 2, packed)
+		}
+		for _, name := range t.fieldNames {
+			msg = appendBytesField(msg, 3, []byte(name))
+		}
+		footer = appendBytesField(footer, 4, msg)
+	}
+	footer = appendVarintField(footer, 6, rows)
+	footer = f.compress(footer)
+	data = append(data, footer...)
+
+	var ps []byte
+	ps = appendVarintField(ps, 1, uint64(len(footer)))
+	ps = appendVarintField(ps, 2, uint64(f.compression))
+	ps = appendVarintField(ps, 3, uint64(f.blockSize))
+	ps = appendBytesField(ps, 8000, []byte(magic))
+	data = append(data, ps...)
+	return append(data, byte(len(ps)))
+}
+
+var testTypes = []orcType{
+	{kind: kindStruct, subtypes: []uint32{1, 2, 3, 4, 5, 7, 8, 9, 10, 13, 15, 18, 19}, fieldNames: []string{
+		"id", "name", "ok", "score", "tags", "ts", "day", "price", "attrs", "nested", "u", "b", "f",
+	}},
+	{kind: kindLong},
+	{kind: kindString},
+	{kind: kindBoolean},
+	{kind: kindDouble},
+	{kind: kindList, subtypes: []uint32{6}},
+	{kind: kindVarchar},
+	{kind: kindTimestamp},
+	{kind: kindDate},
+	{kind: kindDecimal},
+	{kind: kindMap, subtypes: []uint32{11, 12}},
+	{kind: kindString},
+	{kind: kindInt},
+	{kind: kindStruct, subtypes: []uint32{14}, fieldNames: []string{"x"}},
+	{kind: kindShort},
+	{kind: kindUnion, subtypes: []uint32{16, 17}},
+	{kind: kindInt},
+	{kind: kindString},
+	{kind: kindByte},
+	{kind: kindFloat},
+}
+
+const orcEpoch = 1420070400
+
+func testStripes() []testStripe {
+	ts := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	day := ts.Unix() / (24 * 60 * 60)
+
+	v2 := make([]encodingKind, len(testTypes))
+	for i := range v2 {
+		v2[i] = encodingDirectV2
+	}
+	v2[2] = encodingDictionaryV2
+
+	return []testStripe{{
+		rows:      3,
+		timezone:  "UTC",
+		encodings: v2,
+		dictSize:  map[int]uint32{2: 1},
+		index:     []testStream{{column: 0, kind: streamRowIndex, data: []byte("index")}},
+		streams: []testStream{
+			{1, streamData, intsV2(true, 1, -2, 300)},
+			{2, streamPresent, boolsRLE(true, false, true)},
+			{2, streamData, intsV2(false, 0, 0)},
+			{2, streamLength, intsV2(false, 1)},
+			{2, streamDictionaryData, []byte("a")},
+			{3, streamData, boolsRLE(true, false, true)},
+			{4, streamData, doubles(1.5, -2, 0)},
+			{5, streamLength, intsV2(false, 2, 0, 1)},
+			{6, streamData, []byte("xyz")},
+			{6, streamLength, intsV2(false, 1, 1, 1)},
+			{7, streamPresent, boolsRLE(true, false, true)},
+			{7, streamData, intsV2(true, ts.Unix()-orcEpoch, 0)},
+			{7, streamSecondary, intsV2(false, 5<<3|7, 0)},
+			{8, streamPresent, boolsRLE(true, false, true)},
+			{8, streamData, intsV2(true, day, 0)},
+			{9, streamData, decimals(1234, -5, 7)},
+			{9, streamSecondary, intsV2(true, 2, 1, 0)},
+			{10, streamLength, intsV2(false, 1, 0, 2)},
+			{11, streamData, []byte("kab")},
+			{11, streamLength, intsV2(false, 1, 1, 1)},
+			{12, streamData, intsV2(true, 7, 1, 2)},
+			{13, streamPresent, boolsRLE(true, false, true)},
+			{14, streamData, intsV2(true, 5, -6)},
+			{15, streamData, bytesRLE(0, 1, 0)},
+			{16, streamData, intsV2(true, 3, -7)},
+			{17, streamData, []byte("s")},
+			{17, streamLength, intsV2(false, 1)},
+			{18, streamData, bytesRLE(0xff, 2, 0)},
+			{19, streamPresent, boolsRLE(true, false, true)},
+			{19, streamData, floats(0.25, 1)},
+		},
+	}, {
+		rows:      1,
+		encodings: make([]encodingKind, len(testTypes)),
+		index:     nil,
+		streams: []testStream{
+			{1, streamData, intsV1(true, 4)},
+			{2, streamData, []byte("b")},
+			{2, streamLength, intsV1(false, 1)},
+			{3, streamData, boolsRLE(false)},
+			{4, streamData, doubles(3)},
+			{5, streamPresent, boolsRLE(false)},
+			// -1.5s, truncated towards zero by the writer
+			{7, streamData, intsV1(true, -1-orcEpoch)},
+			{7, streamSecondary, intsV1(false, 5<<3|7)},
+			{8, streamPresent, boolsRLE(false)},
+			{9, streamPresent, boolsRLE(false)},
+			{10, streamPresent, boolsRLE(false)},
+			{14, streamPresent, boolsRLE(false)},
+			{15, streamPresent, boolsRLE(false)},
+			{18, streamPresent, boolsRLE(false)},
+			{19, streamPresent, boolsRLE(false)},
+		},
+	}}
+}
+
+func TestReader(t *testing.T) {
+	ts := time.Date(2023, 1, 2, 3, 4, 5, 5e8, time.UTC)
+	row := func(values ...interface{}) jstream.KVS {
+		kvs := jstream.KVS{}
+		for i, name := range testTypes[0].fieldNames {
+			kvs = append(kvs, jstream.KV{Key: name, Value: values[i]})
+		}
+		return kvs
+	}
+	expected := []jstream.KVS{
+		row(int64(1), "a", true, 1.5, []interface{}{"x", "y"}, sql.FormatSQLTimestamp(ts),
+			sql.FormatSQLTimestamp(time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)), 12.34,
+			jstream.KVS{{Key: "k", Value: int64(7)}}, jstream.KVS{{Key: "x", Value: int64(5)}},
+			int64(3), int64(-1), 0.25),
+		row(int64(-2), nil, false, -2.0, []interface{}{}, nil, nil, -0.5, jstream.KVS{}, nil, "s", int64(2), nil),
+		row(int64(300), "a", true, 0.0, []interface{}{"z"}, sql.FormatSQLTimestamp(time.Unix(orcEpoch, 0).UTC()),
+			sql.FormatSQLTimestamp(time.Unix(0, 0).UTC()), int64(7),
+			jstream.KVS{{Key: "a", Value: int64(1)}, {Key: "b", Value: int64(2)}}, jstream.KVS{{Key: "x", Value: int64(-6)}},
+			int64(-7), int64(0), 1.0),
+		row(int64(4), "b", false, 3.0, nil, sql.FormatSQLTimestamp(time.Unix(-2, 5e8).UTC()),
+			nil, nil, nil, jstream.KVS{{Key: "x", Value: nil}}, nil, nil, nil),
+	}
+
+	testCases := []struct {
+		name        string
+		compression compressionKind
+	}{
+		{"none", compressionNone},
+		{"zlib", compressionZlib},
+		{"snappy", compressionSnappy},
+		{"lz4", compressionLz4},
+		{"zstd", compressionZstd},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := testFile{compression: tc.compression, blockSize: 64, types: testTypes, stripes: testStripes()}
+			r, err := NewReader(nopReadSeekCloser{bytes.NewReader(f.bytes())}, &ReaderArgs{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			var record sql.Record
+			for i := 0; ; i++ {
+				record, err = r.Read(record)
+				if err == io.EOF {
+					if i != len(expected) {
+						t.Fatalf("expected %d records, got %d", len(expected), i)
+					}
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if i >= len(expected) {
+					t.Fatalf("unexpected record %v", record)
+				}
+				kvs := record.(*jsonfmt.Record).KVS
+				if !reflect.DeepEqual(kvs, expected[i]) {
+					t.Errorf("record %d: expected %v, got %v", i, expected[i], kvs)
+				}
+			}
+		})
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	valid := testFile{compression: compressionZlib, blockSize: 64, types: testTypes, stripes: testStripes()}
+	truncatedStream := testFile{types: testTypes, stripes: testStripes()}
+	truncatedStream.stripes[0].streams[0].data = intsV2(true, 1, 2)
+	cyclic := testFile{types: append([]orcType{}, testTypes...), stripes: testStripes()}
+	cyclic.types[5] = orcType{kind: kindList, subtypes: []uint32{0}}
+
+	data := valid.bytes()
+	testCases := []struct {
+		name string
+		data []byte
+	}{
+		{"magic", append([]byte("ORK"), data[3:]...)},
+		{"truncated", data[:len(data)-10]},
+		{"postscript", append(append([]byte{}, data[:len(data)-1]...), 0xff)},
+		{"stripe", append(append([]byte{}, data[:100]...), data[120:]...)},
+		{"stream", truncatedStream.bytes()},
+		{"schema", cyclic.bytes()},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewReader(nopReadSeekCloser{bytes.NewReader(tc.data)}, &ReaderArgs{})
+			for err == nil {
+				_, err = r.Read(nil)
+			}
+			if err == io.EOF {
+				t.Fatal("expected an error")
+			}
+			if _, ok := err.(*s3Error); !ok {
+				t.Fatalf("expected an s3Error, got %v", err)
+			}
+		})
+	}
+}
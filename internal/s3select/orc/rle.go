@@ -0,0 +1,408 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package orc
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// byteStream is the decompressed content of a stream.
+type byteStream interface {
+	ReadByte() (byte, error)
+	Read(p []byte) (int, error)
+}
+
+// byteRLE decodes byte run length encoding, runs of 3 to 130 repeated
+// bytes or 1 to 128 literal bytes.
+type byteRLE struct {
+	r         byteStream
+	remaining int
+	repeat    bool
+	value     byte
+}
+
+func (d *byteRLE) next() (byte, error) {
+	if d.remaining == 0 {
+		h, err := d.r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if h < 0x80 {
+			d.remaining, d.repeat = int(h)+3, true
+			if d.value, err = d.r.ReadByte(); err != nil {
+				return 0, err
+			}
+		} else {
+			d.remaining, d.repeat = 256-int(h), false
+		}
+	}
+	d.remaining--
+	if d.repeat {
+		return d.value, nil
+	}
+	return d.r.ReadByte()
+}
+
+// boolRLE decodes booleans, stored as bits (most significant bit first)
+// of byte run length encoded bytes.
+type boolRLE struct {
+	bytes byteRLE
+	bits  byte
+	n     int
+}
+
+func newBoolRLE(r byteStream) *boolRLE {
+	return &boolRLE{bytes: byteRLE{r: r}}
+}
+
+func (d *boolRLE) next() (bool, error) {
+	if d.n == 0 {
+		b, err := d.bytes.next()
+		if err != nil {
+			return false, err
+		}
+		d.bits, d.n = b, 8
+	}
+	d.n--
+	return d.bits>>d.n&1 == 1, nil
+}
+
+// intReader decodes integer run length encoding.
+type intReader interface {
+	next() (int64, error)
+}
+
+func newIntReader(r byteStream, encoding encodingKind, signed bool) intReader {
+	if encoding == encodingDirectV2 || encoding == encodingDictionaryV2 {
+		return &intRLEv2{r: r, signed: signed}
+	}
+	return &intRLEv1{r: r, signed: signed}
+}
+
+func readVarint(r byteStream, signed bool) (int64, error) {
+	if signed {
+		return binary.ReadVarint(r)
+	}
+	u, err := binary.ReadUvarint(r)
+	return int64(u), err
+}
+
+func zigzag(u uint64) int64 {
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+// intRLEv1 decodes version 1 of the integer run length encoding, runs
+// of 3 to 130 values with a fixed delta or 1 to 128 literal varints.
+type intRLEv1 struct {
+	r         byteStream
+	signed    bool
+	remaining int
+	literals  bool
+	delta     int64
+	value     int64
+}
+
+func (d *intRLEv1) next() (int64, error) {
+	if d.remaining == 0 {
+		h, err := d.r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if h >= 0x80 {
+			d.remaining, d.literals = 256-int(h), true
+		} else {
+			d.remaining, d.literals = int(h)+3, false
+			delta, err := d.r.ReadByte()
+			if err != nil {
+				return 0, err
+			}
+			d.delta = int64(int8(delta))
+			if d.value, err = readVarint(d.r, d.signed); err != nil {
+				return 0, err
+			}
+		}
+	}
+	d.remaining--
+	if d.literals {
+		return readVarint(d.r, d.signed)
+	}
+	v := d.value
+	d.value += d.delta
+	return v, nil
+}
+
+// Sub-encodings of version 2 of the integer run length encoding.
+const (
+	rleShortRepeat = iota
+	rleDirect
+	rlePatchedBase
+	rleDelta
+)
+
+var errInvalidRLE = errors.New("invalid integer run length encoding")
+
+// intRLEv2 decodes version 2 of the integer run length encoding, each
+// run of up to 512 values is decoded at once.
+type intRLEv2 struct {
+	r        byteStream
+	signed   bool
+	literals []int64
+	pos      int
+	unpacked []int64
+}
+
+func (d *intRLEv2) next() (int64, error) {
+	if d.pos == len(d.literals) {
+		if err := d.readRun(); err != nil {
+			return 0, err
+		}
+	}
+	v := d.literals[d.pos]
+	d.pos++
+	return v, nil
+}
+
+func (d *intRLEv2) readRun() error {
+	h, err := d.r.ReadByte()
+	if err != nil {
+		return err
+	}
+	d.literals, d.pos = d.literals[:0], 0
+
+	switch h >> 6 {
+	case rleShortRepeat:
+		width, count := int(h>>3&7)+1, int(h&7)+3
+		u, err := d.readBigEndian(width)
+		if err != nil {
+			return err
+		}
+		v := int64(u)
+		if d.signed {
+			v = zigzag(u)
+		}
+		for i := 0; i < count; i++ {
+			d.literals = append(d.literals, v)
+		}
+		return nil
+	case rleDirect:
+		h2, err := d.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		width, count := decodeBitWidth(h>>1&0x1f), (int(h&1)<<8|int(h2))+1
+		if err = d.unpack(count, width); err != nil {
+			return err
+		}
+		for _, u := range d.unpacked {
+			v := u
+			if d.signed {
+				v = zigzag(uint64(u))
+			}
+			d.literals = append(d.literals, v)
+		}
+		return nil
+	case rlePatchedBase:
+		return d.readPatchedBase(h)
+	default:
+		return d.readDelta(h)
+	}
+}
+
+// readPatchedBase decodes values relative to a base value, with the
+// high bits of outliers stored in a separate patch list.
+func (d *intRLEv2) readPatchedBase(h byte) error {
+	var hdr [3]byte
+	if _, err := readFull(d.r, hdr[:]); err != nil {
+		return err
+	}
+	width, count := decodeBitWidth(h>>1&0x1f), (int(h&1)<<8|int(hdr[0]))+1
+	baseWidth, patchWidth := int(hdr[1]>>5)+1, decodeBitWidth(hdr[1]&0x1f)
+	gapWidth, patchCount := int(hdr[2]>>5)+1, int(hdr[2]&0x1f)
+	if patchWidth+gapWidth > 64 || patchCount == 0 {
+		return errInvalidRLE
+	}
+
+	// The base value is stored in sign-magnitude representation.
+	u, err := d.readBigEndian(baseWidth)
+	if err != nil {
+		return err
+	}
+	base := int64(u)
+	if mask := uint64(1) << (baseWidth*8 - 1); u&mask != 0 {
+		base = -int64(u &^ mask)
+	}
+
+	if err = d.unpack(count, width); err != nil {
+		return err
+	}
+	values := append(d.literals[:0], d.unpacked...)
+	if err = d.unpack(patchCount, closestFixedBits(patchWidth+gapWidth)); err != nil {
+		return err
+	}
+	patches := d.unpacked
+
+	// Each patch entry holds the gap to the previous patched value and
+	// the patch, gaps larger than 255 are split into entries with an
+	// empty patch.
+	patchMask := uint64(1)<<patchWidth - 1
+	pos := 0
+	for i := 0; i < len(patches); i++ {
+		gap, patch := uint64(patches[i])>>patchWidth, uint64(patches[i])&patchMask
+		pos += int(gap)
+		if gap == 255 && patch == 0 {
+			continue
+		}
+		if pos >= count {
+			return errInvalidRLE
+		}
+		values[pos] |= int64(patch << width)
+	}
+	for i := range values {
+		values[i] += base
+	}
+	d.literals = values
+	return nil
+}
+
+// readDelta decodes a base value followed by a fixed delta, or the
+// first delta followed by bit packed deltas of the same sign.
+func (d *intRLEv2) readDelta(h byte) error {
+	h2, err := d.r.ReadByte()
+	if err != nil {
+		return err
+	}
+	width, count := 0, (int(h&1)<<8|int(h2))+1
+	if w := h >> 1 & 0x1f; w != 0 {
+		width = decodeBitWidth(w)
+	}
+
+	base, err := readVarint(d.r, d.signed)
+	if err != nil {
+		return err
+	}
+	delta, err := binary.ReadVarint(d.r)
+	if err != nil {
+		return err
+	}
+	d.literals = append(d.literals, base)
+	if width == 0 {
+		for i := 1; i < count; i++ {
+			base += delta
+			d.literals = append(d.literals, base)
+		}
+		return nil
+	}
+	if count < 2 {
+		return errInvalidRLE
+	}
+	base += delta
+	d.literals = append(d.literals, base)
+	if err = d.unpack(count-2, width); err != nil {
+		return err
+	}
+	for _, u := range d.unpacked {
+		if delta < 0 {
+			base -= u
+		} else {
+			base += u
+		}
+		d.literals = append(d.literals, base)
+	}
+	return nil
+}
+
+func (d *intRLEv2) readBigEndian(n int) (uint64, error) {
+	var u uint64
+	for i := 0; i < n; i++ {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		u = u<<8 | uint64(b)
+	}
+	return u, nil
+}
+
+// unpack reads count bit packed values of the given width into
+// d.unpacked, the values start at a byte boundary.
+func (d *intRLEv2) unpack(count, width int) error {
+	d.unpacked = d.unpacked[:0]
+	var cur byte
+	bitsLeft := 0
+	for i := 0; i < count; i++ {
+		var u uint64
+		for need := width; need > 0; {
+			if bitsLeft == 0 {
+				b, err := d.r.ReadByte()
+				if err != nil {
+					return err
+				}
+				cur, bitsLeft = b, 8
+			}
+			take := need
+			if take > bitsLeft {
+				take = bitsLeft
+			}
+			u = u<<take | uint64(cur>>(bitsLeft-take))&(1<<take-1)
+			bitsLeft -= take
+			need -= take
+		}
+		d.unpacked = append(d.unpacked, int64(u))
+	}
+	return nil
+}
+
+// decodeBitWidth returns the bit width of the 5 bit encoded width.
+func decodeBitWidth(w byte) int {
+	switch {
+	case w < 24:
+		return int(w) + 1
+	case w < 28:
+		return 26 + int(w-24)*2
+	default:
+		return 40 + int(w-28)*8
+	}
+}
+
+// closestFixedBits returns the smallest supported bit width of at
+// least n bits.
+func closestFixedBits(n int) int {
+	switch {
+	case n <= 1:
+		return 1
+	case n <= 24:
+		return n
+	case n <= 32:
+		return n + n&1
+	case n <= 64:
+		return (n + 7) / 8 * 8
+	}
+	return 64
+}
+
+func readFull(r byteStream, p []byte) (int, error) {
+	for i := range p {
+		b, err := r.ReadByte()
+		if err != nil {
+			return i, err
+		}
+		p[i] = b
+	}
+	return len(p), nil
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package orc
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func uncompressedStream(b ...byte) *streamReader {
+	return newStreamReader(b, &decompressor{kind: compressionNone})
+}
+
+func TestIntRLE(t *testing.T) {
+	repeat := func(v int64, n int) []int64 {
+		values := make([]int64, n)
+		for i := range values {
+			values[i] = v
+		}
+		return values
+	}
+	descending := make([]int64, 100)
+	for i := range descending {
+		descending[i] = int64(100 - i)
+	}
+
+	// The examples of the specification.
+	testCases := []struct {
+		name     string
+		encoding encodingKind
+		signed   bool
+		data     []byte
+		expected []int64
+	}{
+		{"v1 run", encodingDirect, false, []byte{0x61, 0x00, 0x07}, repeat(7, 100)},
+		{"v1 delta", encodingDirect, false, []byte{0x61, 0xff, 0x64}, descending},
+		{"v1 literals", encodingDirect, false, []byte{0xfb, 0x02, 0x03, 0x06, 0x07, 0x0b}, []int64{2, 3, 6, 7, 11}},
+		{"v1 signed", encodingDirect, true, []byte{0xfe, 0x03, 0x04, 0x00, 0x01, 0x02}, []int64{-2, 2, 1, 2, 3}},
+		{"v2 short repeat", encodingDirectV2, false, []byte{0x0a, 0x27, 0x10}, repeat(10000, 5)},
+		{"v2 direct", encodingDirectV2, false, []byte{0x5e, 0x03, 0x5c, 0xa1, 0xab, 0x1e, 0xde, 0xad, 0xbe, 0xef}, []int64{23713, 43806, 57005, 48879}},
+		{"v2 direct signed", encodingDirectV2, true, []byte{0x42, 0x02, 0x6c}, []int64{-1, 1, -2}},
+		{
+			"v2 patched base", encodingDirectV2, false,
+			[]byte{
+				0x8e, 0x13, 0x2b, 0x21, 0x07, 0xd0, 0x1e, 0x00, 0x14, 0x70, 0x28, 0x32, 0x3c, 0x46,
+				0x50, 0x5a, 0x64, 0x6e, 0x78, 0x82, 0x8c, 0x96, 0xa0, 0xaa, 0xb4, 0xbe, 0xfc, 0xe8,
+			},
+			[]int64{
+				2030, 2000, 2020, 1000000, 2040, 2050, 2060, 2070, 2080, 2090,
+				2100, 2110, 2120, 2130, 2140, 2150, 2160, 2170, 2180, 2190,
+			},
+		},
+		{"v2 delta", encodingDirectV2, false, []byte{0xc6, 0x09, 0x02, 0x02, 0x22, 0x42, 0x42, 0x46}, []int64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}},
+		{"v2 fixed delta", encodingDirectV2, true, []byte{0xc0, 0x03, 0x01, 0x03}, []int64{-1, -3, -5, -7}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newIntReader(uncompressedStream(tc.data...), tc.encoding, tc.signed)
+			var values []int64
+			for {
+				v, err := r.next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				values = append(values, v)
+			}
+			if !reflect.DeepEqual(values, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, values)
+			}
+		})
+	}
+}
+
+func TestByteRLE(t *testing.T) {
+	r := newBoolRLE(uncompressedStream(0xff, 0x80, 0x00, 0x00, 0xff, 0xa5))
+	var bits []bool
+	for {
+		b, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		bits = append(bits, b)
+	}
+	expected := []bool{true, false, false, false, false, false, false, false}
+	expected = append(expected, make([]bool, 24)...)
+	expected = append(expected, true, false, true, false, false, true, false, true)
+	if !reflect.DeepEqual(bits, expected) {
+		t.Errorf("expected %v, got %v", expected, bits)
+	}
+}
+
+func TestWidths(t *testing.T) {
+	for w := byte(0); w < 32; w++ {
+		width := decodeBitWidth(w)
+		if closestFixedBits(width) != width {
+			t.Errorf("width %d of %d is not a fixed width", width, w)
+		}
+	}
+	for n, expected := range map[int]int{0: 1, 7: 7, 25: 26, 31: 32, 33: 40, 50: 56, 64: 64} {
+		if got := closestFixedBits(n); got != expected {
+			t.Errorf("closestFixedBits(%d): expected %d, got %d", n, expected, got)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package orc
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4"
+)
+
+// maxCompressionBlockSize limits the size of decompressed chunks.
+// Writers use 256KiB by default.
+const maxCompressionBlockSize = 16 << 20
+
+var errCorruptChunk = errors.New("corrupt compressed chunk")
+
+// decompressor decompresses the chunks of streams and footers.
+type decompressor struct {
+	kind      compressionKind
+	blockSize int
+	flate     io.ReadCloser
+	zstd      *zstd.Decoder
+}
+
+func newDecompressor(kind compressionKind, blockSize uint64) (*decompressor, error) {
+	d := &decompressor{kind: kind, blockSize: int(blockSize)}
+	switch kind {
+	case compressionNone:
+		return d, nil
+	case compressionZlib, compressionSnappy, compressionLz4:
+	case compressionZstd:
+		var err error
+		d.zstd, err = zstd.NewReader(nil, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(maxCompressionBlockSize))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported compression kind %d", kind)
+	}
+	if blockSize == 0 || blockSize > maxCompressionBlockSize {
+		return nil, fmt.Errorf("invalid compression block size %d", blockSize)
+	}
+	return d, nil
+}
+
+func (d *decompressor) close() {
+	if d.zstd != nil {
+		d.zstd.Close()
+		d.zstd = nil
+	}
+}
+
+// decompress decompresses a chunk into dst, which is returned.
+func (d *decompressor) decompress(dst, src []byte) ([]byte, error) {
+	switch d.kind {
+	case compressionZlib:
+		if d.flate == nil {
+			d.flate = flate.NewReader(bytes.NewReader(src))
+		} else if err := d.flate.(flate.Resetter).Reset(bytes.NewReader(src), nil); err != nil {
+			return nil, err
+		}
+		w := bytes.NewBuffer(dst[:0])
+		n, err := w.ReadFrom(io.LimitReader(d.flate, int64(d.blockSize)+1))
+		if err != nil {
+			return nil, err
+		}
+		if n > int64(d.blockSize) {
+			return nil, errCorruptChunk
+		}
+		return w.Bytes(), nil
+	case compressionSnappy:
+		n, err := s2.DecodedLen(src)
+		if err != nil {
+			return nil, err
+		}
+		if n > d.blockSize {
+			return nil, errCorruptChunk
+		}
+		return s2.Decode(dst[:cap(dst)], src)
+	case compressionLz4:
+		if cap(dst) < d.blockSize {
+			dst = make([]byte, d.blockSize)
+		}
+		n, err := lz4.UncompressBlock(src, dst[:d.blockSize])
+		if err != nil {
+			return nil, err
+		}
+		return dst[:n], nil
+	case compressionZstd:
+		dst, err := d.zstd.DecodeAll(src, dst[:0])
+		if err != nil {
+			return nil, err
+		}
+		if len(dst) > d.blockSize {
+			return nil, errCorruptChunk
+		}
+		return dst, nil
+	}
+	return nil, fmt.Errorf("unsupported compression kind %d", d.kind)
+}
+
+// streamReader reads the decompressed content of a stream. Compressed
+// streams consist of chunks, each prefixed with a 3 byte header holding
+// the length of the chunk and whether it is stored uncompressed.
+type streamReader struct {
+	data  []byte // remaining chunks
+	d     *decompressor
+	chunk []byte // remaining decompressed bytes of the current chunk
+	buf   []byte
+}
+
+func newStreamReader(data []byte, d *decompressor) *streamReader {
+	return &streamReader{data: data, d: d}
+}
+
+// nextChunk decompresses the next chunk of the stream.
+func (s *streamReader) nextChunk() error {
+	if len(s.data) == 0 {
+		return io.EOF
+	}
+	if s.d.kind == compressionNone {
+		s.chunk, s.data = s.data, nil
+		return nil
+	}
+
+	if len(s.data) < 3 {
+		return errCorruptChunk
+	}
+	h := int(s.data[0]) | int(s.data[1])<<8 | int(s.data[2])<<16
+	original, length := h&1 == 1, h>>1
+	if length > s.d.blockSize || length > len(s.data)-3 {
+		return errCorruptChunk
+	}
+	raw := s.data[3 : 3+length]
+	s.data = s.data[3+length:]
+	if original {
+		s.chunk = raw
+		return nil
+	}
+	buf, err := s.d.decompress(s.buf, raw)
+	if err != nil {
+		return err
+	}
+	s.buf = buf
+	s.chunk = buf
+	return nil
+}
+
+// ReadByte reads a single byte of the stream.
+func (s *streamReader) ReadByte() (byte, error) {
+	for len(s.chunk) == 0 {
+		if err := s.nextChunk(); err != nil {
+			return 0, err
+		}
+	}
+	b := s.chunk[0]
+	s.chunk = s.chunk[1:]
+	return b, nil
+}
+
+// Read reads from the stream.
+func (s *streamReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for len(s.chunk) == 0 {
+		if err := s.nextChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, s.chunk)
+	s.chunk = s.chunk[n:]
+	return n, nil
+}
+
+// readAll reads the whole decompressed content of a footer.
+func (d *decompressor) readAll(b []byte) ([]byte, error) {
+	if d.kind == compressionNone {
+		return b, nil
+	}
+	var out bytes.Buffer
+	n, err := out.ReadFrom(io.LimitReader(newStreamReader(b, d), maxFooterSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if n > maxFooterSize {
+		return nil, errors.New("footer too large")
+	}
+	return out.Bytes(), nil
+}
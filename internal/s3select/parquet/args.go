@@ -17,7 +17,13 @@
 
 package parquet
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+const defaultCompressionCodec = "SNAPPY"
 
 // ReaderArgs - represents elements inside <InputSerialization><Parquet/> in request XML.
 type ReaderArgs struct {
@@ -41,3 +47,37 @@ func (args *ReaderArgs) UnmarshalXML(d *xml.Decoder, start xml.StartElement) err
 	args.unmarshaled = true
 	return nil
 }
+
+// WriterArgs - represents elements inside <OutputSerialization><Parquet/> in request XML.
+type WriterArgs struct {
+	CompressionCodec string `xml:"CompressionCodec"`
+	unmarshaled      bool
+}
+
+// IsEmpty - returns whether writer args is empty or not.
+func (args *WriterArgs) IsEmpty() bool {
+	return !args.unmarshaled
+}
+
+// UnmarshalXML - decodes XML data.
+func (args *WriterArgs) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	// Make subtype to avoid recursive UnmarshalXML().
+	type subWriterArgs WriterArgs
+	parsedArgs := subWriterArgs{}
+	if err := d.DecodeElement(&parsedArgs, &start); err != nil {
+		return err
+	}
+
+	parsedArgs.CompressionCodec = strings.ToUpper(parsedArgs.CompressionCodec)
+	switch parsedArgs.CompressionCodec {
+	case "":
+		parsedArgs.CompressionCodec = defaultCompressionCodec
+	case "NONE", "UNCOMPRESSED", "SNAPPY", "GZIP":
+	default:
+		return fmt.Errorf("unsupported CompressionCodec '%v'", parsedArgs.CompressionCodec)
+	}
+
+	*args = WriterArgs(parsedArgs)
+	args.unmarshaled = true
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package parquet
+
+import (
+	"io"
+	"time"
+
+	parquetgo "github.com/fraugster/parquet-go"
+	parquettypes "github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	"github.com/infobsmi/b33s/internal/s3select/sql"
+)
+
+// maxRowGroupSize is the uncompressed size after which a row group is
+// written to the output.
+const maxRowGroupSize = 16 << 20
+
+// Writer implements writing records as a parquet file.
+type Writer struct {
+	fw      *parquetgo.FileWriter
+	columns []sql.Column
+	data    map[string]interface{}
+}
+
+// NewWriter creates a Writer of a parquet file with the given columns.
+// All the columns are optional, such that null values can be written.
+func NewWriter(w io.Writer, columns []sql.Column, args *WriterArgs) (*Writer, error) {
+	codec := parquettypes.CompressionCodec_SNAPPY
+	switch args.CompressionCodec {
+	case "NONE", "UNCOMPRESSED":
+		codec = parquettypes.CompressionCodec_UNCOMPRESSED
+	case "GZIP":
+		codec = parquettypes.CompressionCodec_GZIP
+	}
+
+	root := &parquetschema.ColumnDefinition{
+		SchemaElement: &parquettypes.SchemaElement{Name: "s3select"},
+	}
+	for _, col := range columns {
+		root.Children = append(root.Children, &parquetschema.ColumnDefinition{
+			SchemaElement: schemaElement(col),
+		})
+	}
+
+	fw := parquetgo.NewFileWriter(w,
+		parquetgo.WithCompressionCodec(codec),
+		parquetgo.WithMaxRowGroupSize(maxRowGroupSize),
+		parquetgo.WithCreator("B33S S3 Select"),
+	)
+	if err := fw.SetSchemaDefinition(parquetschema.SchemaDefinitionFromColumnDefinition(root)); err != nil {
+		return nil, err
+	}
+
+	return &Writer{
+		fw:      fw,
+		columns: columns,
+		data:    make(map[string]interface{}, len(columns)),
+	}, nil
+}
+
+// schemaElement returns the parquet schema element of the column.
+func schemaElement(col sql.Column) *parquettypes.SchemaElement {
+	se := &parquettypes.SchemaElement{
+		Name:           col.Name,
+		RepetitionType: parquettypes.FieldRepetitionTypePtr(parquettypes.FieldRepetitionType_OPTIONAL),
+	}
+	switch col.Type {
+	case sql.ColumnTypeBool:
+		se.Type = parquettypes.TypePtr(parquettypes.Type_BOOLEAN)
+	case sql.ColumnTypeInt:
+		se.Type = parquettypes.TypePtr(parquettypes.Type_INT64)
+	case sql.ColumnTypeFloat:
+		se.Type = parquettypes.TypePtr(parquettypes.Type_DOUBLE)
+	case sql.ColumnTypeTimestamp:
+		se.Type = parquettypes.TypePtr(parquettypes.Type_INT64)
+		se.ConvertedType = parquettypes.ConvertedTypePtr(parquettypes.ConvertedType_TIMESTAMP_MICROS)
+		se.LogicalType = &parquettypes.LogicalType{
+			TIMESTAMP: &parquettypes.TimestampType{
+				IsAdjustedToUTC: true,
+				Unit:            &parquettypes.TimeUnit{MICROS: &parquettypes.MicroSeconds{}},
+			},
+		}
+	default:
+		se.Type = parquettypes.TypePtr(parquettypes.Type_BYTE_ARRAY)
+		se.ConvertedType = parquettypes.ConvertedTypePtr(parquettypes.ConvertedType_UTF8)
+		se.LogicalType = &parquettypes.LogicalType{STRING: &parquettypes.StringType{}}
+	}
+	return se
+}
+
+// Write writes a row, the values are in the order of the columns of
+// the writer and nil values are written as nulls.
+func (w *Writer) Write(row []*sql.Value) error {
+	for k := range w.data {
+		delete(w.data, k)
+	}
+	for i, col := range w.columns {
+		if i >= len(row) {
+			break
+		}
+		v, err := col.Type.Convert(row[i])
+		if err != nil {
+			return err
+		}
+		switch val := v.(type) {
+		case nil:
+			// Optional columns are null when they are absent.
+			continue
+		case time.Time:
+			v = val.Unix()*int64(time.Second/time.Microsecond) + int64(val.Nanosecond())/int64(time.Microsecond)
+		case string:
+			v = []byte(val)
+		}
+		w.data[col.Name] = v
+	}
+	return w.fw.AddData(w.data)
+}
+
+// Close writes the last row group and the footer of the parquet file.
+func (w *Writer) Close() error {
+	return w.fw.Close()
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package parquet
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/bcicen/jstream"
+	jsonfmt "github.com/infobsmi/b33s/internal/s3select/json"
+	"github.com/infobsmi/b33s/internal/s3select/sql"
+)
+
+type nopReadSeekCloser struct {
+	*bytes.Reader
+}
+
+func (nopReadSeekCloser) Close() error { return nil }
+
+func TestWriter(t *testing.T) {
+	ts := time.Date(2023, 1, 2, 3, 4, 5, 6000, time.UTC)
+	columns := []sql.Column{
+		{Name: "name", Type: sql.ColumnTypeString},
+		{Name: "count", Type: sql.ColumnTypeInt},
+		{Name: "ratio", Type: sql.ColumnTypeFloat},
+		{Name: "ok", Type: sql.ColumnTypeBool},
+		{Name: "ts", Type: sql.ColumnTypeTimestamp},
+	}
+	rows := [][]*sql.Value{
+		{sql.FromString("a"), sql.FromInt(1), sql.FromFloat(0.5), sql.FromBool(true), sql.FromTimestamp(ts)},
+		// integers are widened to float columns and nulls are kept
+		{sql.FromString("b"), sql.FromNull(), sql.FromInt(2), nil, nil},
+	}
+	expected := []jstream.KVS{
+		{{Key: "name", Value: "a"}, {Key: "count", Value: int64(1)}, {Key: "ratio", Value: 0.5}, {Key: "ok", Value: true}, {Key: "ts", Value: sql.FormatSQLTimestamp(ts)}},
+		{{Key: "name", Value: "b"}, {Key: "count", Value: nil}, {Key: "ratio", Value: 2.0}, {Key: "ok", Value: nil}, {Key: "ts", Value: nil}},
+	}
+
+	for _, codec := range []string{"NONE", "SNAPPY", "GZIP"} {
+		t.Run(codec, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, columns, &WriterArgs{CompressionCodec: codec})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, row := range rows {
+				if err = w.Write(row); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err = w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			r, err := NewParquetReader(nopReadSeekCloser{bytes.NewReader(buf.Bytes())}, &ReaderArgs{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var record sql.Record
+			for i := 0; ; i++ {
+				record, err = r.Read(record)
+				if err == io.EOF {
+					if i != len(expected) {
+						t.Fatalf("expected %d rows, got %d", len(expected), i)
+					}
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				kvs := record.(*jsonfmt.Record).KVS
+				if len(kvs) != len(expected[i]) {
+					t.Fatalf("row %d: expected %v, got %v", i, expected[i], kvs)
+				}
+				for j, kv := range kvs {
+					if kv != expected[i][j] {
+						t.Errorf("row %d: expected %v, got %v", i, expected[i][j], kv)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWriterConversionError(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, []sql.Column{{Name: "count", Type: sql.ColumnTypeInt}}, &WriterArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Write([]*sql.Value{sql.FromString("a")}); err == nil {
+		t.Fatal("expected an error writing a string to an integer column")
+	}
+}
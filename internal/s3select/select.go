@@ -27,11 +27,15 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/infobsmi/b33s/internal/s3select/arrow"
+	"github.com/infobsmi/b33s/internal/s3select/avro"
 	"github.com/infobsmi/b33s/internal/s3select/csv"
 	"github.com/infobsmi/b33s/internal/s3select/json"
+	"github.com/infobsmi/b33s/internal/s3select/orc"
 	"github.com/infobsmi/b33s/internal/s3select/parquet"
 	"github.com/infobsmi/b33s/internal/s3select/simdj"
 	"github.com/infobsmi/b33s/internal/s3select/sql"
@@ -53,6 +57,9 @@ const (
 	csvFormat     = "csv"
 	jsonFormat    = "json"
 	parquetFormat = "parquet"
+	arrowFormat   = "arrow"
+	orcFormat     = "orc"
+	avroFormat    = "avro"
 )
 
 // CompressionType - represents value inside <CompressionType/> in request XML.
@@ -71,6 +78,10 @@ const (
 
 const (
 	maxRecordSize = 1 << 20 // 1 MiB
+
+	// Compressed ORC objects may be at most this many times larger
+	// once decompressed, see orcMaxDecompressionRatio().
+	defaultORCMaxDecompressionRatio = 100
 )
 
 var bufPool = sync.Pool{
@@ -116,6 +127,8 @@ type InputSerialization struct {
 	CSVArgs         csv.ReaderArgs     `xml:"CSV"`
 	JSONArgs        json.ReaderArgs    `xml:"JSON"`
 	ParquetArgs     parquet.ReaderArgs `xml:"Parquet"`
+	ORCArgs         orc.ReaderArgs     `xml:"ORC"`
+	AvroArgs        avro.ReaderArgs    `xml:"Avro"`
 	unmarshaled     bool
 	format          string
 }
@@ -156,6 +169,14 @@ func (input *InputSerialization) UnmarshalXML(d *xml.Decoder, start xml.StartEle
 		parsedInput.format = parquetFormat
 		found++
 	}
+	if !parsedInput.ORCArgs.IsEmpty() {
+		parsedInput.format = orcFormat
+		found++
+	}
+	if !parsedInput.AvroArgs.IsEmpty() {
+		parsedInput.format = avroFormat
+		found++
+	}
 
 	if found != 1 {
 		return errInvalidDataSource(nil)
@@ -168,8 +189,10 @@ func (input *InputSerialization) UnmarshalXML(d *xml.Decoder, start xml.StartEle
 
 // OutputSerialization - represents elements inside <OutputSerialization/> in request XML.
 type OutputSerialization struct {
-	CSVArgs     csv.WriterArgs  `xml:"CSV"`
-	JSONArgs    json.WriterArgs `xml:"JSON"`
+	CSVArgs     csv.WriterArgs     `xml:"CSV"`
+	JSONArgs    json.WriterArgs    `xml:"JSON"`
+	ParquetArgs parquet.WriterArgs `xml:"Parquet"`
+	ArrowArgs   arrow.WriterArgs   `xml:"Arrow"`
 	unmarshaled bool
 	format      string
 }
@@ -197,8 +220,16 @@ func (output *OutputSerialization) UnmarshalXML(d *xml.Decoder, start xml.StartE
 		parsedOutput.format = jsonFormat
 		found++
 	}
+	if !parsedOutput.ParquetArgs.IsEmpty() {
+		parsedOutput.format = parquetFormat
+		found++
+	}
+	if !parsedOutput.ArrowArgs.IsEmpty() {
+		parsedOutput.format = arrowFormat
+		found++
+	}
 	if found != 1 {
-		return errObjectSerializationConflict(fmt.Errorf("either CSV or JSON should be present in OutputSerialization"))
+		return errObjectSerializationConflict(fmt.Errorf("either CSV, JSON, Parquet or Arrow should be present in OutputSerialization"))
 	}
 
 	*output = OutputSerialization(parsedOutput)
@@ -289,6 +320,7 @@ type S3Select struct {
 	statement      *sql.SelectStatement
 	progressReader *progressReader
 	recordReader   recordReader
+	columnarOutput *columnarOutput
 }
 
 var legacyXMLName = "SelectObjectContentRequest"
@@ -346,6 +378,8 @@ func (s3Select *S3Select) outputRecord() sql.Record {
 		return csv.NewRecord()
 	case jsonFormat:
 		return json.NewRecord(sql.SelectFmtJSON)
+	case parquetFormat, arrowFormat:
+		return &columnRecord{}
 	}
 
 	panic(fmt.Errorf("unknown output format '%v'", s3Select.Output.format))
@@ -360,7 +394,7 @@ func (s3Select *S3Select) getProgress() (bytesScanned, bytesProcessed int64) {
 }
 
 // Open - opens S3 object by using callback for SQL selection query.
-// Currently CSV, JSON and Apache Parquet formats are supported.
+// Currently CSV, JSON, Apache Parquet, ORC and Avro formats are supported.
 func (s3Select *S3Select) Open(rsc io.ReadSeekCloser) error {
 	offset, length, err := s3Select.ScanRange.StartLen()
 	if err != nil {
@@ -372,13 +406,19 @@ func (s3Select *S3Select) Open(rsc io.ReadSeekCloser) error {
 	}
 	switch s3Select.Input.format {
 	case csvFormat:
-		_, err = rsc.Seek(offset, seekDirection)
-		if err != nil {
-			return err
-		}
 		var rc io.ReadCloser = rsc
-		if length != -1 {
-			rc = newLimitedReadCloser(rsc, length)
+		if s3Select.ScanRange != nil {
+			if s3Select.Input.CompressionType != noneType || s3Select.Input.CSVArgs.AllowQuotedRecordDelimiter {
+				return errUnsupportedScanRangeInput(errors.New("scan ranges require uncompressed CSV without quoted record delimiters"))
+			}
+			if offset, err = rsc.Seek(offset, seekDirection); err != nil {
+				return err
+			}
+			if rc, err = csv.NewRangeReader(rsc, offset, length, &s3Select.Input.CSVArgs); err != nil {
+				return err
+			}
+		} else if _, err = rsc.Seek(0, io.SeekStart); err != nil {
+			return err
 		}
 
 		s3Select.progressReader, err = newProgressReader(rc, s3Select.Input.CompressionType)
@@ -392,33 +432,26 @@ func (s3Select *S3Select) Open(rsc io.ReadSeekCloser) error {
 			// Close all reader resources opened so far.
 			s3Select.progressReader.Close()
 
-			var stErr bzip2.StructuralError
-			if errors.As(err, &stErr) {
+			if isCompressionError(err) {
 				return errInvalidCompression(err, s3Select.Input.CompressionType)
 			}
-			// Test these compressor errors
-			errs := []error{
-				gzip.ErrHeader, gzip.ErrChecksum,
-				s2.ErrCorrupt, s2.ErrUnsupported, s2.ErrCRC,
-				zstd.ErrBlockTooSmall, zstd.ErrMagicMismatch, zstd.ErrWindowSizeExceeded, zstd.ErrUnknownDictionary, zstd.ErrWindowSizeTooSmall,
-				lz4.ErrInvalid, lz4.ErrBlockDependency,
-			}
-			for _, e := range errs {
-				if errors.Is(err, e) {
-					return errInvalidCompression(err, s3Select.Input.CompressionType)
-				}
-			}
 			return err
 		}
 		return nil
 	case jsonFormat:
-		_, err = rsc.Seek(offset, seekDirection)
-		if err != nil {
-			return err
-		}
 		var rc io.ReadCloser = rsc
-		if length != -1 {
-			rc = newLimitedReadCloser(rsc, length)
+		if s3Select.ScanRange != nil {
+			if s3Select.Input.CompressionType != noneType || !strings.EqualFold(s3Select.Input.JSONArgs.ContentType, "lines") {
+				return errUnsupportedScanRangeInput(errors.New("scan ranges require uncompressed JSON Lines"))
+			}
+			if offset, err = rsc.Seek(offset, seekDirection); err != nil {
+				return err
+			}
+			if rc, err = json.NewRangeReader(rsc, offset, length); err != nil {
+				return err
+			}
+		} else if _, err = rsc.Seek(0, io.SeekStart); err != nil {
+			return err
 		}
 
 		s3Select.progressReader, err = newProgressReader(rc, s3Select.Input.CompressionType)
@@ -449,6 +482,62 @@ func (s3Select *S3Select) Open(rsc io.ReadSeekCloser) error {
 		var err error
 		s3Select.recordReader, err = parquet.NewParquetReader(rsc, &s3Select.Input.ParquetArgs)
 		return err
+	case orcFormat:
+		if offset != 0 || length != -1 {
+			// Offsets do not make sense in ORC files.
+			return errors.New("ORC format does not support offsets")
+		}
+		if s3Select.Input.CompressionType != noneType {
+			// ORC files are read from the end, whole object compression
+			// requires a decompressed copy, its size is limited to a
+			// multiple of the object size.
+			size, err := objectSize(rsc)
+			if err != nil {
+				rsc.Close()
+				return err
+			}
+			ratio := orcMaxDecompressionRatio()
+			s3Select.progressReader, err = newProgressReader(rsc, s3Select.Input.CompressionType)
+			if err != nil {
+				rsc.Close()
+				return err
+			}
+			rsc, err = newTempFile(s3Select.progressReader, "s3select-orc-", size*ratio)
+			s3Select.progressReader.Close()
+			if err != nil {
+				if isCompressionError(err) {
+					return errInvalidCompression(err, s3Select.Input.CompressionType)
+				}
+				if errors.Is(err, errTempFileTooLarge) {
+					return errDecompressedSizeTooLarge(err, ratio)
+				}
+				return err
+			}
+		}
+		s3Select.recordReader, err = orc.NewReader(rsc, &s3Select.Input.ORCArgs)
+		if err != nil {
+			rsc.Close()
+		}
+		return err
+	case avroFormat:
+		if offset != 0 || length != -1 {
+			// Avro blocks do not start at arbitrary offsets.
+			return errors.New("Avro format does not support offsets")
+		}
+		s3Select.progressReader, err = newProgressReader(rsc, s3Select.Input.CompressionType)
+		if err != nil {
+			rsc.Close()
+			return err
+		}
+		s3Select.recordReader, err = avro.NewReader(s3Select.progressReader, &s3Select.Input.AvroArgs)
+		if err != nil {
+			s3Select.progressReader.Close()
+			if isCompressionError(err) {
+				return errInvalidCompression(err, s3Select.Input.CompressionType)
+			}
+			return err
+		}
+		return nil
 	}
 
 	return fmt.Errorf("unknown input format '%v'", s3Select.Input.format)
@@ -497,6 +586,8 @@ func (s3Select *S3Select) marshal(buf *bytes.Buffer, record sql.Record) error {
 		buf.WriteString(s3Select.Output.JSONArgs.RecordDelimiter)
 
 		return nil
+	case parquetFormat, arrowFormat:
+		return s3Select.columnarOutput.write(buf, record)
 	}
 
 	panic(fmt.Errorf("unknown output format '%v'", s3Select.Output.format))
@@ -509,15 +600,9 @@ func (s3Select *S3Select) Evaluate(w http.ResponseWriter) {
 		getProgressFunc = nil
 	}
 	writer := newMessageWriter(w, getProgressFunc)
+	s3Select.columnarOutput = newColumnarOutput(&s3Select.Output)
 
-	var outputQueue []sql.Record
-
-	// Create queue based on the type.
-	if s3Select.statement.IsAggregated() {
-		outputQueue = make([]sql.Record, 0, 1)
-	} else {
-		outputQueue = make([]sql.Record, 0, 100)
-	}
+	outputQueue := make([]sql.Record, 0, 100)
 	var err error
 	sendRecord := func() bool {
 		buf := bufPool.Get().(*bytes.Buffer)
@@ -532,7 +617,8 @@ func (s3Select *S3Select) Evaluate(w http.ResponseWriter) {
 				bufPool.Put(buf)
 				return false
 			}
-			if buf.Len()-before > maxRecordSize {
+			// Columnar output formats write many records at once.
+			if s3Select.columnarOutput == nil && buf.Len()-before > maxRecordSize {
 				writer.FinishWithError("OverMaxRecordSize", "The length of a record in the input or result is greater than maxCharsPerRecord of 1 MB.")
 				bufPool.Put(buf)
 				return false
@@ -549,11 +635,32 @@ func (s3Select *S3Select) Evaluate(w http.ResponseWriter) {
 		return true
 	}
 
+	// Columnar output formats are only complete once the writer is
+	// closed, after the last record was sent.
+	finishOutput := func() bool {
+		if s3Select.columnarOutput == nil {
+			return true
+		}
+		buf := bufPool.Get().(*bytes.Buffer)
+		buf.Reset()
+		if err = s3Select.columnarOutput.close(buf); err != nil {
+			bufPool.Put(buf)
+			return false
+		}
+		if err = writer.SendRecord(buf); err != nil {
+			// FIXME: log this error.
+			err = nil
+			bufPool.Put(buf)
+			return false
+		}
+		return true
+	}
+
 	var rec sql.Record
 OuterLoop:
 	for {
 		if s3Select.statement.LimitReached() {
-			if !sendRecord() {
+			if !sendRecord() || !finishOutput() {
 				break
 			}
 			if err = writer.Finish(s3Select.getProgress()); err != nil {
@@ -569,14 +676,25 @@ OuterLoop:
 			}
 
 			if s3Select.statement.IsAggregated() {
-				outputRecord := s3Select.outputRecord()
-				if err = s3Select.statement.AggregateResult(outputRecord); err != nil {
+				for !s3Select.statement.LimitReached() {
+					var outputRecord sql.Record
+					if outputRecord, err = s3Select.statement.AggregateResult(s3Select.outputRecord()); err != nil {
+						break
+					}
+					outputQueue = append(outputQueue, outputRecord)
+					if len(outputQueue) == cap(outputQueue) && !sendRecord() {
+						break OuterLoop
+					}
+				}
+				if err == io.EOF {
+					err = nil
+				}
+				if err != nil {
 					break
 				}
-				outputQueue = append(outputQueue, outputRecord)
 			}
 
-			if !sendRecord() {
+			if !sendRecord() || !finishOutput() {
 				break
 			}
 
@@ -625,7 +743,7 @@ OuterLoop:
 
 				outputQueue[len(outputQueue)-1] = outputRecord
 				if s3Select.statement.LimitReached() {
-					if !sendRecord() {
+					if !sendRecord() || !finishOutput() {
 						break
 					}
 					if err = writer.Finish(s3Select.getProgress()); err != nil {
@@ -653,6 +771,9 @@ OuterLoop:
 
 // Close - closes opened S3 object.
 func (s3Select *S3Select) Close() error {
+	if s3Select.statement != nil {
+		s3Select.statement.Close()
+	}
 	if s3Select.recordReader == nil {
 		return nil
 	}
@@ -673,17 +794,84 @@ func NewS3Select(r io.Reader) (*S3Select, error) {
 // Helpers
 /////////////////
 
-// limitedReadCloser is like io.LimitedReader, but also implements io.Closer.
-type limitedReadCloser struct {
-	io.LimitedReader
-	io.Closer
+// isCompressionError returns whether err is caused by corrupt compressed
+// input.
+func isCompressionError(err error) bool {
+	var stErr bzip2.StructuralError
+	if errors.As(err, &stErr) {
+		return true
+	}
+	// Test these compressor errors
+	errs := []error{
+		gzip.ErrHeader, gzip.ErrChecksum,
+		s2.ErrCorrupt, s2.ErrUnsupported, s2.ErrCRC,
+		zstd.ErrBlockTooSmall, zstd.ErrMagicMismatch, zstd.ErrWindowSizeExceeded, zstd.ErrUnknownDictionary, zstd.ErrWindowSizeTooSmall,
+		lz4.ErrInvalid, lz4.ErrBlockDependency,
+	}
+	for _, e := range errs {
+		if errors.Is(err, e) {
+			return true
+		}
+	}
+	return false
 }
 
-func newLimitedReadCloser(r io.ReadCloser, n int64) *limitedReadCloser {
-	return &limitedReadCloser{
-		LimitedReader: io.LimitedReader{R: r, N: n},
-		Closer:        r,
+// objectSize returns the size of the object read by rsc, which is left
+// positioned at the start of the object.
+func objectSize(rsc io.ReadSeekCloser) (int64, error) {
+	if o, ok := rsc.(*ObjectReadSeekCloser); ok {
+		return o.size, nil
+	}
+	size, err := rsc.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
 	}
+	_, err = rsc.Seek(0, io.SeekStart)
+	return size, err
+}
+
+// orcMaxDecompressionRatio returns how many times larger than the object
+// a compressed ORC object may become once decompressed.
+func orcMaxDecompressionRatio() int64 {
+	if v := os.Getenv("MINIO_API_SELECT_ORC_MAX_DECOMPRESSION_RATIO"); v != "" {
+		if ratio, err := strconv.ParseInt(v, 10, 64); err == nil && ratio > 0 {
+			return ratio
+		}
+	}
+	return defaultORCMaxDecompressionRatio
+}
+
+var errTempFileTooLarge = errors.New("temporary file size limit exceeded")
+
+// tempFile is a temporary file, which is removed when it is closed.
+type tempFile struct {
+	*os.File
+}
+
+// newTempFile copies r into a new temporary file, fails with
+// errTempFileTooLarge if r is larger than maxSize bytes.
+func newTempFile(r io.Reader, pattern string, maxSize int64) (*tempFile, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return nil, err
+	}
+	tf := &tempFile{File: f}
+	n, err := io.Copy(f, io.LimitReader(r, maxSize+1))
+	if err == nil && n > maxSize {
+		err = errTempFileTooLarge
+	}
+	if err != nil {
+		tf.Close()
+		return nil, err
+	}
+	return tf, nil
+}
+
+// Close closes and removes the file.
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	os.Remove(f.Name())
+	return err
 }
 
 // ObjectSegmentReaderFn is a function that returns a reader for a contiguous
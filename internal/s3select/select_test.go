@@ -19,6 +19,7 @@ package s3select
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -903,6 +904,26 @@ func TestCSVQueries2(t *testing.T) {
 			query:      `select * from S3object where _2 != '' AND _2 > 1`,
 			wantResult: `{"c1":"1","c2":"2","c3":"3"}`,
 		},
+		{
+			name: "select-group-by-order-by",
+			input: []byte(`c1,c2,c3
+a,1,x
+b,2,y
+a,3,x`),
+			query: `select c1, SUM(c2) AS total from S3object GROUP BY c1 ORDER BY total DESC`,
+			wantResult: `{"c1":"a","total":4}
+{"c1":"b","total":2}`,
+		},
+		{
+			name: "select-distinct",
+			input: []byte(`c1,c2,c3
+a,1,x
+b,2,y
+a,3,x`),
+			query: `select DISTINCT c1, c3 from S3object ORDER BY c1`,
+			wantResult: `{"c1":"a","c3":"x"}
+{"c1":"b","c3":"y"}`,
+		},
 	}
 
 	defRequest := `<?xml version="1.0" encoding="UTF-8"?>
@@ -1471,8 +1492,8 @@ func TestCSVRanges(t *testing.T) {
 		{
 			name:  "select-middle",
 			input: testInput,
-			// Since we are doing offset, no headers are used.
-			wantResult: `{"_1":"a text, with comma"}`,
+			// No record starts inside the range.
+			wantResult: ``,
 			requestXML: []byte(`<?xml version="1.0" encoding="UTF-8"?>
 <SelectObjectContentRequest>
     <Expression>SELECT * from s3object AS s</Expression>
@@ -1492,6 +1513,86 @@ func TestCSVRanges(t *testing.T) {
         <Enabled>FALSE</Enabled>
     </RequestProgress>
 	<ScanRange><Start>56</Start><End>76</End></ScanRange>
+</SelectObjectContentRequest>`),
+		},
+		{
+			name:  "select-record-start",
+			input: testInput,
+			// The record starting at the first byte of the range is read past the end of the range.
+			wantResult: `{"_1":"1","_2":"2010-01-01T","_3":"7867786","_4":"4565.908123","_5":"a text, with comma"}`,
+			requestXML: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>SELECT * from s3object AS s</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>NONE</CompressionType>
+        <CSV>
+        <FileHeaderInfo>NONE</FileHeaderInfo>
+	    <QuoteCharacter>"</QuoteCharacter>
+        </CSV>
+    </InputSerialization>
+    <OutputSerialization>
+        <JSON>
+        </JSON>
+    </OutputSerialization>
+    <RequestProgress>
+        <Enabled>FALSE</Enabled>
+    </RequestProgress>
+	<ScanRange><Start>22</Start><End>22</End></ScanRange>
+</SelectObjectContentRequest>`),
+		},
+		{
+			name:  "select-record-end",
+			input: testInput,
+			// Records starting at the last byte of the range are included.
+			wantResult: `{"_1":"1","_2":"2010-01-01T","_3":"7867786","_4":"4565.908123","_5":"a text, with comma"}
+{"_1":"2","_2":"2017-01-02T03:04Z","_3":"-5","_4":" 0.765111","_5":""}`,
+			requestXML: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>SELECT * from s3object AS s</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>NONE</CompressionType>
+        <CSV>
+        <FileHeaderInfo>NONE</FileHeaderInfo>
+	    <QuoteCharacter>"</QuoteCharacter>
+        </CSV>
+    </InputSerialization>
+    <OutputSerialization>
+        <JSON>
+        </JSON>
+    </OutputSerialization>
+    <RequestProgress>
+        <Enabled>FALSE</Enabled>
+    </RequestProgress>
+	<ScanRange><Start>1</Start><End>77</End></ScanRange>
+</SelectObjectContentRequest>`),
+		},
+		{
+			name:  "error-compressed",
+			input: testInput,
+			// Scan ranges are not supported on compressed objects.
+			wantResult: ``,
+			wantErr:    true,
+			requestXML: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>SELECT * from s3object AS s</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>GZIP</CompressionType>
+        <CSV>
+        <FileHeaderInfo>NONE</FileHeaderInfo>
+	    <QuoteCharacter>"</QuoteCharacter>
+        </CSV>
+    </InputSerialization>
+    <OutputSerialization>
+        <JSON>
+        </JSON>
+    </OutputSerialization>
+    <RequestProgress>
+        <Enabled>FALSE</Enabled>
+    </RequestProgress>
+	<ScanRange><Start>0</Start><End>10</End></ScanRange>
 </SelectObjectContentRequest>`),
 		},
 		{
@@ -1985,3 +2086,65 @@ func TestParquetInputSchemaCSV(t *testing.T) {
 		})
 	}
 }
+
+func TestORCDecompressedSizeLimit(t *testing.T) {
+	requestXML := []byte(`
+<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>SELECT * from S3Object</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>GZIP</CompressionType>
+        <ORC/>
+    </InputSerialization>
+    <OutputSerialization>
+        <CSV>
+        </CSV>
+    </OutputSerialization>
+</SelectObjectContentRequest>
+`)
+
+	// 1MiB of zeros compress about a thousand times.
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(make([]byte, 1<<20)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		ratio        string
+		expectedCode string
+	}{
+		{ratio: "", expectedCode: "DecompressedSizeTooLarge"},
+		{ratio: "10", expectedCode: "DecompressedSizeTooLarge"},
+		// Within the limit the decompressed object is not valid ORC.
+		{ratio: "10000", expectedCode: ""},
+	}
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			t.Setenv("MINIO_API_SELECT_ORC_MAX_DECOMPRESSION_RATIO", testCase.ratio)
+			s3Select, err := NewS3Select(bytes.NewReader(requestXML))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = s3Select.Open(newBytesRSC(buf.Bytes()))
+			if err == nil {
+				s3Select.Close()
+				t.Fatal("expected an error")
+			}
+			var code string
+			if serr, ok := err.(SelectError); ok {
+				code = serr.ErrorCode()
+			}
+			if testCase.expectedCode != "" && code != testCase.expectedCode {
+				t.Fatalf("expected error code %s, got %v", testCase.expectedCode, err)
+			}
+			if testCase.expectedCode == "" && code == "DecompressedSizeTooLarge" {
+				t.Fatalf("unexpected error %v", err)
+			}
+		})
+	}
+}
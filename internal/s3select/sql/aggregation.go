@@ -60,6 +60,32 @@ func newAggVal(fn FuncName) *aggVal {
 	}
 }
 
+// merge combines the partial aggregation b of the same function
+// into a.
+func (a *aggVal) merge(b *aggVal) error {
+	a.runningCount += b.runningCount
+	if a.runningSum != nil && b.runningSum != nil {
+		if err := a.runningSum.arithOp(opPlus, b.runningSum); err != nil {
+			return err
+		}
+	}
+	if !b.seen {
+		return nil
+	}
+	if a.runningMin != nil && b.runningMin != nil {
+		if err := a.runningMin.minmax(b.runningMin, false, !a.seen); err != nil {
+			return err
+		}
+	}
+	if a.runningMax != nil && b.runningMax != nil {
+		if err := a.runningMax.minmax(b.runningMax, true, !a.seen); err != nil {
+			return err
+		}
+	}
+	a.seen = true
+	return nil
+}
+
 // evalAggregationNode - performs partial computation using the
 // current row and stores the result.
 //
@@ -271,6 +297,28 @@ func (e *PrimaryTerm) aggregateRow(r Record, tableAlias string) error {
 		return e.SubExpression.aggregateRow(r, tableAlias)
 	case e.FuncCall != nil:
 		return e.FuncCall.aggregateRow(r, tableAlias)
+	case e.Case != nil:
+		return e.Case.aggregateRow(r, tableAlias)
+	}
+	return nil
+}
+
+func (e *CaseExpr) aggregateRow(r Record, tableAlias string) error {
+	if e.Operand != nil {
+		if err := e.Operand.aggregateRow(r, tableAlias); err != nil {
+			return err
+		}
+	}
+	for _, when := range e.When {
+		if err := when.Condition.aggregateRow(r, tableAlias); err != nil {
+			return err
+		}
+		if err := when.Result.aggregateRow(r, tableAlias); err != nil {
+			return err
+		}
+	}
+	if e.Else != nil {
+		return e.Else.aggregateRow(r, tableAlias)
 	}
 	return nil
 }
@@ -77,7 +77,14 @@ func (e *SelectExpression) analyze(s *Select) (result qProp) {
 	}
 
 	for _, ex := range e.Expressions {
-		result.combine(ex.analyze(s))
+		q := ex.analyze(s)
+		if len(s.GroupBy) > 0 {
+			// Row functions are evaluated once per group, so
+			// they can be combined with aggregations in
+			// other expressions.
+			q.isRowFunc = false
+		}
+		result.combine(q)
 	}
 	return
 }
@@ -176,6 +183,9 @@ func (e *PrimaryTerm) analyze(s *Select) (result qProp) {
 	case e.Value != nil:
 		result = qProp{}
 
+	case e.Case != nil:
+		result = e.Case.analyze(s)
+
 	case e.JPathExpr != nil:
 		// Check if the path expression is valid
 		if len(e.JPathExpr.PathExpr) > 0 {
@@ -201,6 +211,38 @@ func (e *PrimaryTerm) analyze(s *Select) (result qProp) {
 	return
 }
 
+func (e *CaseExpr) analyze(s *Select) (result qProp) {
+	if e.Operand != nil {
+		result.combine(e.Operand.analyze(s))
+	}
+	for _, when := range e.When {
+		result.combine(when.Condition.analyze(s))
+		result.combine(when.Result.analyze(s))
+	}
+	if e.Else != nil {
+		result.combine(e.Else.analyze(s))
+	}
+	return
+}
+
+// analyzeArgs analyzes the arguments of a simple argument function
+// taking between min and max (-1 for any number) arguments.
+func (e *FuncExpr) analyzeArgs(s *Select, min, max int) (result qProp) {
+	n := len(e.SFunc.ArgsList)
+	switch {
+	case min == max && n != min:
+		return qProp{err: fmt.Errorf("%s needs exactly %d argument(s)", e.getFunctionName(), min)}
+	case n < min:
+		return qProp{err: fmt.Errorf("%s needs at least %d argument(s)", e.getFunctionName(), min)}
+	case max >= 0 && n > max:
+		return qProp{err: fmt.Errorf("%s takes at most %d arguments", e.getFunctionName(), max)}
+	}
+	for _, arg := range e.SFunc.ArgsList {
+		result.combine(arg.analyze(s))
+	}
+	return result
+}
+
 func (e *FuncExpr) analyze(s *Select) (result qProp) {
 	funcName := e.getFunctionName()
 
@@ -224,7 +266,10 @@ func (e *FuncExpr) analyze(s *Select) (result qProp) {
 	// Handle aggregation function calls
 	case aggFnAvg, aggFnMax, aggFnMin, aggFnSum, aggFnCount:
 		// Initialize accumulator
-		e.aggregate = newAggVal(funcName)
+		if e.aggregate == nil {
+			e.aggregate = newAggVal(funcName)
+			s.aggregates = append(s.aggregates, e)
+		}
 
 		var exprA qProp
 		if funcName == aggFnCount {
@@ -317,6 +362,29 @@ func (e *FuncExpr) analyze(s *Select) (result qProp) {
 			result.err = fmt.Errorf("%s() takes no arguments", string(funcName))
 		}
 		return result
+
+	case sqlFnAbs, sqlFnCeil, sqlFnCeiling, sqlFnFloor:
+		return e.analyzeArgs(s, 1, 1)
+
+	case sqlFnRound:
+		return e.analyzeArgs(s, 1, 2)
+
+	case sqlFnMod, sqlFnRegexpLike, sqlFnJSONExtract:
+		return e.analyzeArgs(s, 2, 2)
+
+	case sqlFnRegexpExtract:
+		return e.analyzeArgs(s, 2, 3)
+
+	case sqlFnReplace:
+		return e.analyzeArgs(s, 3, 3)
+
+	case sqlFnConcat:
+		return e.analyzeArgs(s, 1, -1)
+
+	case sqlFnPosition:
+		result.combine(e.Position.Substr.analyze(s))
+		result.combine(e.Position.Str.analyze(s))
+		return result
 	}
 
 	// TODO: implement other functions
@@ -0,0 +1,129 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package sql
+
+import (
+	"fmt"
+	"time"
+)
+
+// ColumnType - is the type of a column in columnar output formats
+// (Parquet, Arrow).
+type ColumnType int
+
+const (
+	// ColumnTypeNull - only null values were seen so far
+	ColumnTypeNull ColumnType = iota
+	// ColumnTypeBool - boolean values
+	ColumnTypeBool
+	// ColumnTypeInt - 64-bit integer values
+	ColumnTypeInt
+	// ColumnTypeFloat - 64-bit floating point values
+	ColumnTypeFloat
+	// ColumnTypeTimestamp - timestamp values
+	ColumnTypeTimestamp
+	// ColumnTypeString - string values, also used for values without
+	// a native columnar type such as arrays
+	ColumnTypeString
+)
+
+// Column - is a named and typed column of a columnar output format.
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// columnTypeOf returns the column type of the value.
+func columnTypeOf(v *Value) ColumnType {
+	switch v.value.(type) {
+	case nil, Missing:
+		return ColumnTypeNull
+	case bool:
+		return ColumnTypeBool
+	case int64:
+		return ColumnTypeInt
+	case float64:
+		return ColumnTypeFloat
+	case time.Time:
+		return ColumnTypeTimestamp
+	}
+	return ColumnTypeString
+}
+
+// Merge returns the type of a column holding values of type t and the
+// value v. Integers are widened to floats, any other conflicting types
+// result in a string column.
+func (t ColumnType) Merge(v *Value) ColumnType {
+	vt := columnTypeOf(v)
+	switch {
+	case t == ColumnTypeNull:
+		return vt
+	case vt == ColumnTypeNull, vt == t:
+		return t
+	case t == ColumnTypeInt && vt == ColumnTypeFloat, t == ColumnTypeFloat && vt == ColumnTypeInt:
+		return ColumnTypeFloat
+	}
+	return ColumnTypeString
+}
+
+// Convert returns v as a value of the column type t, which is one of
+// bool, int64, float64, time.Time or string. Null and missing values
+// are returned as nil.
+func (t ColumnType) Convert(v *Value) (interface{}, error) {
+	if v == nil || columnTypeOf(v) == ColumnTypeNull {
+		return nil, nil
+	}
+	switch t {
+	case ColumnTypeBool:
+		if b, ok := v.ToBool(); ok {
+			return b, nil
+		}
+	case ColumnTypeInt:
+		if i, ok := v.ToInt(); ok {
+			return i, nil
+		}
+	case ColumnTypeFloat:
+		if f, ok := v.ToFloat(); ok {
+			return f, nil
+		}
+	case ColumnTypeTimestamp:
+		if ts, ok := v.ToTimestamp(); ok {
+			return ts, nil
+		}
+	case ColumnTypeString, ColumnTypeNull:
+		return v.CSVString(), nil
+	}
+	return nil, fmt.Errorf("cannot convert %s value to column type %s", v.GetTypeString(), t)
+}
+
+// String returns the name of the column type.
+func (t ColumnType) String() string {
+	switch t {
+	case ColumnTypeNull:
+		return "NULL"
+	case ColumnTypeBool:
+		return "BOOL"
+	case ColumnTypeInt:
+		return "INT"
+	case ColumnTypeFloat:
+		return "FLOAT"
+	case ColumnTypeTimestamp:
+		return "TIMESTAMP"
+	}
+	return "STRING"
+}
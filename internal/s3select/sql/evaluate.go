@@ -319,13 +319,20 @@ func (e *Operand) evalNode(r Record, tableAlias string) (*Value, error) {
 
 	// Process remaining child nodes - result must be
 	// numeric. This AST node is for terms separated by + or -
-	// symbols.
+	// symbols, or a string for the || operator.
 	for _, rightTerm := range e.Right {
 		op := rightTerm.Op
 		rval, rerr := rightTerm.Right.evalNode(r, tableAlias)
 		if rerr != nil {
 			return nil, rerr
 		}
+		if op == opConcat {
+			lval, rerr = concat([]*Value{lval, rval})
+			if rerr != nil {
+				return nil, rerr
+			}
+			continue
+		}
 		err := lval.arithOp(op, rval)
 		if err != nil {
 			return nil, err
@@ -459,6 +466,8 @@ func (e *PrimaryTerm) evalNode(r Record, tableAlias string) (res *Value, err err
 	switch {
 	case e.Value != nil:
 		return e.Value.evalNode(r)
+	case e.Case != nil:
+		return e.Case.evalNode(r, tableAlias)
 	case e.JPathExpr != nil:
 		return e.JPathExpr.evalNode(r, tableAlias)
 	case e.ListExpr != nil:
@@ -471,6 +480,49 @@ func (e *PrimaryTerm) evalNode(r Record, tableAlias string) (res *Value, err err
 	return nil, errInvalidASTNode
 }
 
+// evalNode returns the result of the first WHEN clause matching the
+// operand or with a true condition, or the ELSE result (NULL if
+// absent) if none matches.
+func (e *CaseExpr) evalNode(r Record, tableAlias string) (*Value, error) {
+	var operand *Value
+	if e.Operand != nil {
+		v, err := e.Operand.evalNode(r, tableAlias)
+		if err != nil {
+			return nil, err
+		}
+		operand = v
+	}
+
+	for _, when := range e.When {
+		v, err := when.Condition.evalNode(r, tableAlias)
+		if err != nil {
+			return nil, err
+		}
+
+		var match bool
+		if operand != nil {
+			if !hasNullArg(operand, v) {
+				// Compare a copy, as comparison
+				// may convert the operand type.
+				lhs := *operand
+				if match, err = lhs.compareOp(opEq, v); err != nil {
+					return nil, err
+				}
+			}
+		} else {
+			match, _ = v.ToBool()
+		}
+		if match {
+			return when.Result.evalNode(r, tableAlias)
+		}
+	}
+
+	if e.Else == nil {
+		return FromNull(), nil
+	}
+	return e.Else.evalNode(r, tableAlias)
+}
+
 func (e *FuncExpr) evalNode(r Record, tableAlias string) (res *Value, err error) {
 	switch e.getFunctionName() {
 	case aggFnCount, aggFnAvg, aggFnMax, aggFnMin, aggFnSum:
@@ -18,8 +18,10 @@
 package sql
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -37,6 +39,17 @@ const (
 	// Conversion
 	sqlFnCast FuncName = "CAST"
 
+	// JSON
+	sqlFnJSONExtract FuncName = "JSON_EXTRACT"
+
+	// Math
+	sqlFnAbs     FuncName = "ABS"
+	sqlFnCeil    FuncName = "CEIL"
+	sqlFnCeiling FuncName = "CEILING"
+	sqlFnFloor   FuncName = "FLOOR"
+	sqlFnMod     FuncName = "MOD"
+	sqlFnRound   FuncName = "ROUND"
+
 	// Date and time
 	sqlFnDateAdd     FuncName = "DATE_ADD"
 	sqlFnDateDiff    FuncName = "DATE_DIFF"
@@ -48,7 +61,12 @@ const (
 	// String
 	sqlFnCharLength      FuncName = "CHAR_LENGTH"
 	sqlFnCharacterLength FuncName = "CHARACTER_LENGTH"
+	sqlFnConcat          FuncName = "CONCAT"
 	sqlFnLower           FuncName = "LOWER"
+	sqlFnPosition        FuncName = "POSITION"
+	sqlFnRegexpExtract   FuncName = "REGEXP_EXTRACT"
+	sqlFnRegexpLike      FuncName = "REGEXP_LIKE"
+	sqlFnReplace         FuncName = "REPLACE"
 	sqlFnSubstring       FuncName = "SUBSTRING"
 	sqlFnTrim            FuncName = "TRIM"
 	sqlFnUpper           FuncName = "UPPER"
@@ -60,6 +78,14 @@ var (
 	errNonTimestampArg   = errors.New("Expected a timestamp argument")
 )
 
+// funcCache caches the compiled constant argument of a function call
+// during evaluation.
+type funcCache struct {
+	arg    string
+	regexp *regexp.Regexp
+	path   []*JSONPathElement
+}
+
 func (e *FuncExpr) getFunctionName() FuncName {
 	switch {
 	case e.SFunc != nil:
@@ -78,6 +104,8 @@ func (e *FuncExpr) getFunctionName() FuncName {
 		return sqlFnDateAdd
 	case e.DateDiff != nil:
 		return sqlFnDateDiff
+	case e.Position != nil:
+		return sqlFnPosition
 	default:
 		return ""
 	}
@@ -108,6 +136,9 @@ func (e *FuncExpr) evalSQLFnNode(r Record, tableAlias string) (res *Value, err e
 	case sqlFnDateDiff:
 		return handleDateDiff(r, e.DateDiff, tableAlias)
 
+	case sqlFnPosition:
+		return handleSQLPosition(r, e.Position, tableAlias)
+
 	}
 
 	// For all simple argument functions, we evaluate the arguments here
@@ -138,6 +169,36 @@ func (e *FuncExpr) evalSQLFnNode(r Record, tableAlias string) (res *Value, err e
 	case sqlFnUTCNow:
 		return handleUTCNow()
 
+	case sqlFnAbs:
+		return abs(argVals[0])
+
+	case sqlFnCeil, sqlFnCeiling:
+		return ceil(argVals[0])
+
+	case sqlFnFloor:
+		return floor(argVals[0])
+
+	case sqlFnRound:
+		return round(argVals)
+
+	case sqlFnMod:
+		return mod(argVals[0], argVals[1])
+
+	case sqlFnConcat:
+		return concat(argVals)
+
+	case sqlFnReplace:
+		return replace(argVals[0], argVals[1], argVals[2])
+
+	case sqlFnRegexpLike:
+		return e.regexpLike(argVals[0], argVals[1])
+
+	case sqlFnRegexpExtract:
+		return e.regexpExtract(argVals)
+
+	case sqlFnJSONExtract:
+		return e.jsonExtract(argVals[0], argVals[1])
+
 	case sqlFnToString, sqlFnToTimestamp:
 		// TODO: implement
 		fallthrough
@@ -200,6 +261,211 @@ func charlen(v *Value) (*Value, error) {
 	return FromInt(int64(len([]rune(s)))), nil
 }
 
+// hasNullArg returns if any of the arguments is NULL or MISSING, the
+// result of most functions is NULL in this case.
+func hasNullArg(args ...*Value) bool {
+	for _, arg := range args {
+		if arg.IsNull() || arg.IsMissing() {
+			return true
+		}
+	}
+	return false
+}
+
+// stringArg converts a function argument to a string.
+func stringArg(fn FuncName, v *Value) (string, error) {
+	inferTypeAsString(v)
+	s, ok := v.ToString()
+	if !ok {
+		err := fmt.Errorf("%s expects string arguments", fn)
+		return "", errIncorrectSQLFunctionArgumentType(err)
+	}
+	return s, nil
+}
+
+func concat(args []*Value) (*Value, error) {
+	if hasNullArg(args...) {
+		return FromNull(), nil
+	}
+	var sb strings.Builder
+	for _, arg := range args {
+		s, err := concatString(arg)
+		if err != nil {
+			return nil, err
+		}
+		sb.WriteString(s)
+	}
+	return FromString(sb.String()), nil
+}
+
+// concatString converts an argument of CONCAT or || to a string,
+// unlike the other string functions these also accept numbers,
+// booleans and timestamps.
+func concatString(v *Value) (string, error) {
+	switch v.value.(type) {
+	case string, []byte, int64, float64, bool, time.Time:
+		return v.CSVString(), nil
+	}
+	err := fmt.Errorf("%s cannot concatenate %s values", sqlFnConcat, v.GetTypeString())
+	return "", errIncorrectSQLFunctionArgumentType(err)
+}
+
+func replace(v, from, to *Value) (*Value, error) {
+	if hasNullArg(v, from, to) {
+		return FromNull(), nil
+	}
+	args := make([]string, 3)
+	for i, arg := range []*Value{v, from, to} {
+		s, err := stringArg(sqlFnReplace, arg)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = s
+	}
+	return FromString(evalSQLReplace(args[0], args[1], args[2])), nil
+}
+
+func handleSQLPosition(r Record, e *PositionFunc, tableAlias string) (*Value, error) {
+	substr, err := e.Substr.evalNode(r, tableAlias)
+	if err != nil {
+		return nil, err
+	}
+	str, err := e.Str.evalNode(r, tableAlias)
+	if err != nil {
+		return nil, err
+	}
+	if hasNullArg(substr, str) {
+		return FromNull(), nil
+	}
+	sub, err := stringArg(sqlFnPosition, substr)
+	if err != nil {
+		return nil, err
+	}
+	s, err := stringArg(sqlFnPosition, str)
+	if err != nil {
+		return nil, err
+	}
+	return FromInt(int64(evalSQLPosition(sub, s))), nil
+}
+
+// compileRegexp returns the compiled pattern, the last pattern is
+// cached as it is usually a constant.
+func (e *FuncExpr) compileRegexp(pattern string) (*regexp.Regexp, error) {
+	if e.cache != nil && e.cache.regexp != nil && e.cache.arg == pattern {
+		return e.cache.regexp, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		err = fmt.Errorf("%s: invalid regular expression: %w", e.getFunctionName(), err)
+		return nil, errIncorrectSQLFunctionArgumentType(err)
+	}
+	e.cache = &funcCache{arg: pattern, regexp: re}
+	return re, nil
+}
+
+func (e *FuncExpr) regexpLike(v, pattern *Value) (*Value, error) {
+	if hasNullArg(v, pattern) {
+		return FromNull(), nil
+	}
+	s, err := stringArg(sqlFnRegexpLike, v)
+	if err != nil {
+		return nil, err
+	}
+	p, err := stringArg(sqlFnRegexpLike, pattern)
+	if err != nil {
+		return nil, err
+	}
+	re, err := e.compileRegexp(p)
+	if err != nil {
+		return nil, err
+	}
+	return FromBool(re.MatchString(s)), nil
+}
+
+// regexpExtract returns the first match of the pattern or of the
+// given capture group, NULL if the pattern does not match.
+func (e *FuncExpr) regexpExtract(args []*Value) (*Value, error) {
+	if hasNullArg(args...) {
+		return FromNull(), nil
+	}
+	s, err := stringArg(sqlFnRegexpExtract, args[0])
+	if err != nil {
+		return nil, err
+	}
+	p, err := stringArg(sqlFnRegexpExtract, args[1])
+	if err != nil {
+		return nil, err
+	}
+	re, err := e.compileRegexp(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var group int64
+	if len(args) > 2 {
+		inferTypeForArithOp(args[2])
+		var ok bool
+		group, ok = args[2].ToInt()
+		if !ok || group < 0 || group > int64(re.NumSubexp()) {
+			err := fmt.Errorf("%s: invalid capture group", sqlFnRegexpExtract)
+			return nil, errIncorrectSQLFunctionArgumentType(err)
+		}
+	}
+
+	m := re.FindStringSubmatchIndex(s)
+	if m == nil || m[2*group] < 0 {
+		return FromNull(), nil
+	}
+	return FromString(s[m[2*group]:m[2*group+1]]), nil
+}
+
+// jsonExtract evaluates a JSON path such as '$.a.b[0]' on a JSON
+// document, which is either a string or an object or array of JSON
+// input.
+func (e *FuncExpr) jsonExtract(doc, path *Value) (*Value, error) {
+	if hasNullArg(doc, path) {
+		return FromNull(), nil
+	}
+	p, err := stringArg(sqlFnJSONExtract, path)
+	if err != nil {
+		return nil, err
+	}
+	if e.cache == nil || e.cache.path == nil || e.cache.arg != p {
+		pathExpr, err := parseJSONPathString(p)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", sqlFnJSONExtract, err)
+			return nil, errIncorrectSQLFunctionArgumentType(err)
+		}
+		e.cache = &funcCache{arg: p, path: pathExpr}
+	}
+
+	var data []byte
+	switch x := doc.value.(type) {
+	case string:
+		data = []byte(x)
+	case []byte:
+		data = x
+	case []Value:
+		if data, err = json.Marshal(x); err != nil {
+			return nil, err
+		}
+	default:
+		err := fmt.Errorf("%s expects a JSON document, got %s", sqlFnJSONExtract, doc.GetTypeString())
+		return nil, errIncorrectSQLFunctionArgumentType(err)
+	}
+
+	v, err := decodeJSONDocument(data)
+	if err != nil {
+		err = fmt.Errorf("%s: invalid JSON document: %w", sqlFnJSONExtract, err)
+		return nil, errIncorrectSQLFunctionArgumentType(err)
+	}
+	result, _, err := jsonpathEval(e.cache.path, v)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToValue(result)
+}
+
 func lowerCase(v *Value) (*Value, error) {
 	inferTypeAsString(v)
 	s, ok := v.ToString()
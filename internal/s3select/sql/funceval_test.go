@@ -0,0 +1,102 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package sql
+
+import (
+	"testing"
+)
+
+func TestEvalSQLFunctions(t *testing.T) {
+	input := "name=Jane Doe,age=42,score=-3.5,email=jane@example.com"
+	doc := `{"a":{"b":[1,"x",{"c":true}]}}`
+	testCases := []struct {
+		expr string
+		want string
+	}{
+		// CASE
+		{expr: "CASE WHEN age > 40 THEN 'old' ELSE 'young' END", want: `"old":STRING`},
+		{expr: "CASE WHEN age > 50 THEN 'old' END", want: ":NULL"},
+		{expr: "CASE age WHEN 41 THEN 'a' WHEN 42 THEN 'b' ELSE 'c' END", want: `"b":STRING`},
+		{expr: "CASE name WHEN 'John Doe' THEN 1 ELSE 0 END", want: "0:INT"},
+
+		// Math
+		{expr: "ABS(score)", want: "3.5:FLOAT"},
+		{expr: "ABS(-age)", want: "42:INT"},
+		{expr: "ROUND(score)", want: "-4:INT"},
+		{expr: "ROUND(2.345, 2)", want: "2.35:FLOAT"},
+		{expr: "ROUND(1250, -2)", want: "1300:INT"},
+		{expr: "FLOOR(score)", want: "-4:INT"},
+		{expr: "CEIL(score)", want: "-3:INT"},
+		{expr: "CEILING(2.1)", want: "3:INT"},
+		{expr: "MOD(age, 5)", want: "2:INT"},
+		{expr: "ABS(missingcol)", want: ":NULL"},
+
+		// String
+		{expr: "CONCAT(name, ' (', age, ')')", want: `"Jane Doe (42)":STRING`},
+		{expr: "name || '!' || 1", want: `"Jane Doe!1":STRING`},
+		{expr: "name || NULL", want: ":NULL"},
+		{expr: "REPLACE(name, 'Doe', 'Roe')", want: `"Jane Roe":STRING`},
+		{expr: "POSITION('Doe' IN name)", want: "6:INT"},
+		{expr: "POSITION('x' IN name)", want: "0:INT"},
+		{expr: "REGEXP_LIKE(email, '^[a-z]+@example\\.com$')", want: "true:BOOL"},
+		{expr: "REGEXP_LIKE(name, '^Doe')", want: "false:BOOL"},
+		{expr: "REGEXP_EXTRACT(email, '@(.*)$', 1)", want: `"example.com":STRING`},
+		{expr: "REGEXP_EXTRACT(email, '[0-9]+')", want: ":NULL"},
+
+		// JSON
+		{expr: "JSON_EXTRACT(doc, '$.a.b[1]')", want: `"x":STRING`},
+		{expr: "JSON_EXTRACT(doc, '$.a.b[2].c')", want: "true:BOOL"},
+		{expr: "JSON_EXTRACT(doc, '$[''a''].b[0]')", want: "1:FLOAT"},
+		{expr: "JSON_EXTRACT(doc, '$.a.x')", want: ":MISSING"},
+		{expr: "JSON_EXTRACT(doc, '$.a.b[2]')", want: `"{"c":true}":BYTES`},
+	}
+
+	for i, testCase := range testCases {
+		query := "SELECT " + testCase.expr + " FROM S3Object"
+		stmt, err := ParseSelectStatement(query)
+		if err != nil {
+			t.Fatalf("Test %d: %s: %v", i+1, query, err)
+		}
+		rec := newTestRecord(input)
+		rec.Set("doc", FromBytes([]byte(doc)))
+
+		out, err := stmt.Eval(rec, &testRecord{vals: make(map[string]*Value)})
+		if err != nil {
+			t.Fatalf("Test %d: %s: %v", i+1, query, err)
+		}
+		v, _ := out.Get("_1")
+		if got := v.Repr(); got != testCase.want {
+			t.Errorf("Test %d: %s: expected %s, got %s", i+1, testCase.expr, testCase.want, got)
+		}
+	}
+}
+
+func TestEvalSQLFunctionErrors(t *testing.T) {
+	queries := []string{
+		"SELECT ABS(1, 2) FROM S3Object",
+		"SELECT ROUND() FROM S3Object",
+		"SELECT REPLACE('a', 'b') FROM S3Object",
+		"SELECT CONCAT() FROM S3Object",
+		"SELECT CASE WHEN 1 = 1 THEN 1 FROM S3Object",
+	}
+	for _, query := range queries {
+		if _, err := ParseSelectStatement(query); err == nil {
+			t.Errorf("%s: expected an error", query)
+		}
+	}
+}
@@ -18,7 +18,11 @@
 package sql
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/bcicen/jstream"
 	"github.com/minio/simdjson-go"
@@ -127,3 +131,95 @@ func jsonpathEval(p []*JSONPathElement, v interface{}) (r interface{}, flat bool
 	}
 	panic("cannot reach here")
 }
+
+// parseJSONPathString parses a JSON path given as a string, such as
+// '$.a.b[0]' or "$['a'][*]", the leading '$' is optional.
+func parseJSONPathString(path string) ([]*JSONPathElement, error) {
+	errPath := fmt.Errorf("invalid JSON path %q", path)
+	s := strings.TrimSpace(path)
+	if strings.HasPrefix(s, "$") {
+		s = s[1:]
+	} else if s != "" && s[0] != '.' && s[0] != '[' {
+		// 'a.b' is the same as '$.a.b'
+		s = "." + s
+	}
+	var p []*JSONPathElement
+	for len(s) > 0 {
+		switch {
+		case strings.HasPrefix(s, ".*"):
+			p = append(p, &JSONPathElement{ObjectWildcard: true})
+			s = s[2:]
+
+		case strings.HasPrefix(s, "[*]"):
+			p = append(p, &JSONPathElement{ArrayWildcard: true})
+			s = s[3:]
+
+		case s[0] == '.':
+			s = s[1:]
+			var key string
+			if strings.HasPrefix(s, `"`) {
+				end := strings.IndexByte(s[1:], '"')
+				if end < 0 {
+					return nil, errPath
+				}
+				key, s = s[1:end+1], s[end+2:]
+			} else {
+				end := strings.IndexAny(s, ".[")
+				if end < 0 {
+					end = len(s)
+				}
+				key, s = s[:end], s[end:]
+			}
+			if key == "" {
+				return nil, errPath
+			}
+			p = append(p, jsonPathKey(key))
+
+		case s[0] == '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, errPath
+			}
+			inner := s[1:end]
+			s = s[end+1:]
+			if n := len(inner); n >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[n-1] == inner[0] {
+				p = append(p, jsonPathKey(inner[1:n-1]))
+				continue
+			}
+			idx, err := strconv.Atoi(strings.TrimSpace(inner))
+			if err != nil || idx < 0 {
+				return nil, errPath
+			}
+			p = append(p, &JSONPathElement{Index: &idx})
+
+		default:
+			return nil, errPath
+		}
+	}
+	return p, nil
+}
+
+func jsonPathKey(key string) *JSONPathElement {
+	lit := LiteralString(key)
+	return &JSONPathElement{Key: &ObjectKey{Lit: &lit}}
+}
+
+// decodeJSONDocument decodes a JSON document into the representation
+// evaluated by jsonpathEval.
+func decodeJSONDocument(data []byte) (v interface{}, err error) {
+	d := jstream.NewDecoder(bytes.NewReader(data), 0).ObjectAsKVS()
+	n := 0
+	for mv := range d.Stream() {
+		if n == 0 {
+			v = mv.Value
+		}
+		n++
+	}
+	if err = d.Err(); err != nil {
+		return nil, err
+	}
+	if n != 1 {
+		return nil, errors.New("expected a single JSON value")
+	}
+	return v, nil
+}
@@ -97,3 +97,39 @@ func TestJsonpathEval(t *testing.T) {
 		})
 	}
 }
+
+func TestParseJSONPathString(t *testing.T) {
+	cases := []struct {
+		path string
+		str  string
+		err  bool
+	}{
+		{path: "$", str: ""},
+		{path: "$.a.b", str: "['a']['b']"},
+		{path: "a[0][*].*", str: "['a'][0][*].*"},
+		{path: `$."a.b"['c d']["e"]`, str: "['a.b']['c d']['e']"},
+		{path: "$.a[", err: true},
+		{path: "$.a[-1]", err: true},
+		{path: "$..a", err: true},
+		{path: "$a", err: true},
+	}
+	for i, tc := range cases {
+		p, err := parseJSONPathString(tc.path)
+		if tc.err {
+			if err == nil {
+				t.Errorf("Test %d: %s: expected an error", i+1, tc.path)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %s: %v", i+1, tc.path, err)
+		}
+		var str string
+		for _, e := range p {
+			str += e.String()
+		}
+		if str != tc.str {
+			t.Errorf("Test %d: %s: expected %s, got %s", i+1, tc.path, tc.str, str)
+		}
+	}
+}
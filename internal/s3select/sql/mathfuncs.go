@@ -0,0 +1,136 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package sql
+
+import (
+	"fmt"
+	"math"
+)
+
+// numericArg converts a function argument to a number.
+func numericArg(fn FuncName, v *Value) error {
+	if err := inferTypeForArithOp(v); err != nil || !v.isNumeric() {
+		err := fmt.Errorf("%s expects numeric arguments", fn)
+		return errIncorrectSQLFunctionArgumentType(err)
+	}
+	return nil
+}
+
+// intOrFloat returns an integral float as an int if it is in range.
+func intOrFloat(f float64) *Value {
+	if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+		return FromInt(int64(f))
+	}
+	return FromFloat(f)
+}
+
+func abs(v *Value) (*Value, error) {
+	if hasNullArg(v) {
+		return FromNull(), nil
+	}
+	if err := numericArg(sqlFnAbs, v); err != nil {
+		return nil, err
+	}
+	if i, ok := v.ToInt(); ok {
+		if i < 0 {
+			i = -i
+		}
+		return FromInt(i), nil
+	}
+	f, _ := v.ToFloat()
+	return FromFloat(math.Abs(f)), nil
+}
+
+func ceil(v *Value) (*Value, error) {
+	if hasNullArg(v) {
+		return FromNull(), nil
+	}
+	if err := numericArg(sqlFnCeil, v); err != nil {
+		return nil, err
+	}
+	if _, ok := v.ToInt(); ok {
+		return v, nil
+	}
+	f, _ := v.ToFloat()
+	return intOrFloat(math.Ceil(f)), nil
+}
+
+func floor(v *Value) (*Value, error) {
+	if hasNullArg(v) {
+		return FromNull(), nil
+	}
+	if err := numericArg(sqlFnFloor, v); err != nil {
+		return nil, err
+	}
+	if _, ok := v.ToInt(); ok {
+		return v, nil
+	}
+	f, _ := v.ToFloat()
+	return intOrFloat(math.Floor(f)), nil
+}
+
+// round rounds half away from zero to the given number of decimal
+// places (default 0), which may be negative.
+func round(args []*Value) (*Value, error) {
+	if hasNullArg(args...) {
+		return FromNull(), nil
+	}
+	v := args[0]
+	if err := numericArg(sqlFnRound, v); err != nil {
+		return nil, err
+	}
+	var places int64
+	if len(args) > 1 {
+		if err := numericArg(sqlFnRound, args[1]); err != nil {
+			return nil, err
+		}
+		var ok bool
+		if places, ok = args[1].ToInt(); !ok {
+			err := fmt.Errorf("%s expects an integer number of decimal places", sqlFnRound)
+			return nil, errIncorrectSQLFunctionArgumentType(err)
+		}
+	}
+
+	_, isInt := v.ToInt()
+	if isInt && places >= 0 {
+		return v, nil
+	}
+	f, _ := v.ToFloat()
+	if places > 0 {
+		p := math.Pow10(int(places))
+		return FromFloat(math.Round(f*p) / p), nil
+	}
+	p := math.Pow10(int(-places))
+	return intOrFloat(math.Round(f/p) * p), nil
+}
+
+func mod(v, d *Value) (*Value, error) {
+	if hasNullArg(v, d) {
+		return FromNull(), nil
+	}
+	if err := numericArg(sqlFnMod, v); err != nil {
+		return nil, err
+	}
+	if err := numericArg(sqlFnMod, d); err != nil {
+		return nil, err
+	}
+	if err := v.arithOp(opModulo, d); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
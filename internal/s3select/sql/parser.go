@@ -89,14 +89,27 @@ type Select struct {
 	Expression *SelectExpression `parser:"\"SELECT\" @@"`
 	From       *TableExpression  `parser:"\"FROM\" @@"`
 	Where      *Expression       `parser:"( \"WHERE\" @@ )?"`
+	GroupBy    []*Expression     `parser:"( \"GROUP\" \"BY\" @@ ( \",\" @@ )* )?"`
+	Having     *Expression       `parser:"( \"HAVING\" @@ )?"`
+	OrderBy    []*OrderByTerm    `parser:"( \"ORDER\" \"BY\" @@ ( \",\" @@ )* )?"`
 	Limit      *LitValue         `parser:"( \"LIMIT\" @@ )?"`
+
+	// Aggregation function calls, collected during analysis
+	aggregates []*FuncExpr
 }
 
 // SelectExpression represents the items requested in the select
 // statement
 type SelectExpression struct {
-	All         bool                 `parser:"  @\"*\""`
-	Expressions []*AliasedExpression `parser:"| @@ { \",\" @@ }"`
+	Distinct    bool                 `parser:"@\"DISTINCT\"?"`
+	All         bool                 `parser:"(  @\"*\""`
+	Expressions []*AliasedExpression `parser:" | @@ { \",\" @@ } )"`
+}
+
+// OrderByTerm represents an expression of the ORDER BY clause
+type OrderByTerm struct {
+	Expression *Expression `parser:"@@"`
+	Desc       bool        `parser:"( @\"DESC\" | \"ASC\" )?"`
 }
 
 // TableExpression represents the FROM clause
@@ -209,22 +222,22 @@ type In struct {
 
 // Grammar for Operand:
 //
-// operand → multOp ( ("-" | "+") multOp )*
+// operand → multOp ( ("-" | "+" | "||") multOp )*
 // multOp  → unary ( ("/" | "*" | "%") unary )*
 // unary   → "-" unary | primary
-// primary → Value | Variable | "(" expression ")"
+// primary → Value | Case | Variable | "(" expression ")"
 //
 
 // An Operand is a single term followed by an optional sequence of
-// terms separated by +/-
+// terms separated by +/- or the string concatenation operator ||
 type Operand struct {
 	Left  *MultOp     `parser:"@@"`
 	Right []*OpFactor `parser:"(@@)*"`
 }
 
-// OpFactor represents the right-side of a +/- operation.
+// OpFactor represents the right-side of a +/- or || operation.
 type OpFactor struct {
-	Op    string  `parser:"@(\"+\" | \"-\")"`
+	Op    string  `parser:"@(\"+\" | \"-\" | \"||\")"`
 	Right *MultOp `parser:"@@"`
 }
 
@@ -256,6 +269,7 @@ type NegatedTerm struct {
 // or a function call.
 type PrimaryTerm struct {
 	Value         *LitValue   `parser:"  @@"`
+	Case          *CaseExpr   `parser:"| @@"`
 	JPathExpr     *JSONPath   `parser:"| @@"`
 	ListExpr      *ListExpr   `parser:"| @@"`
 	SubExpression *Expression `parser:"| \"(\" @@ \")\""`
@@ -263,6 +277,21 @@ type PrimaryTerm struct {
 	FuncCall *FuncExpr `parser:"| @@"`
 }
 
+// CaseExpr represents a CASE expression, either with an operand
+// compared to the WHEN values or with WHEN conditions.
+type CaseExpr struct {
+	Operand *Expression   `parser:" \"CASE\" @@? "`
+	When    []*WhenClause `parser:" @@+ "`
+	Else    *Expression   `parser:" ( \"ELSE\" @@ )? \"END\" "`
+}
+
+// WhenClause represents a WHEN ... THEN ... clause of a CASE
+// expression
+type WhenClause struct {
+	Condition *Expression `parser:" \"WHEN\" @@ "`
+	Result    *Expression `parser:" \"THEN\" @@ "`
+}
+
 // FuncExpr represents a function call
 type FuncExpr struct {
 	SFunc     *SimpleArgFunc `parser:"  @@"`
@@ -273,15 +302,20 @@ type FuncExpr struct {
 	Trim      *TrimFunc      `parser:"| @@"`
 	DateAdd   *DateAddFunc   `parser:"| @@"`
 	DateDiff  *DateDiffFunc  `parser:"| @@"`
+	Position  *PositionFunc  `parser:"| @@"`
 
 	// Used during evaluation for aggregation funcs
 	aggregate *aggVal
+
+	// Used during evaluation to cache the compiled regular
+	// expression or JSON path argument
+	cache *funcCache
 }
 
 // SimpleArgFunc represents functions with simple expression
 // arguments.
 type SimpleArgFunc struct {
-	FunctionName string `parser:" @(\"AVG\" | \"MAX\" | \"MIN\" | \"SUM\" |  \"COALESCE\" | \"NULLIF\" | \"TO_STRING\" | \"TO_TIMESTAMP\" | \"UTCNOW\" | \"CHAR_LENGTH\" | \"CHARACTER_LENGTH\" | \"LOWER\" | \"UPPER\") "`
+	FunctionName string `parser:" @(\"AVG\" | \"MAX\" | \"MIN\" | \"SUM\" |  \"COALESCE\" | \"NULLIF\" | \"TO_STRING\" | \"TO_TIMESTAMP\" | \"UTCNOW\" | \"CHAR_LENGTH\" | \"CHARACTER_LENGTH\" | \"LOWER\" | \"UPPER\" | \"ABS\" | \"ROUND\" | \"FLOOR\" | \"CEIL\" | \"CEILING\" | \"MOD\" | \"CONCAT\" | \"REPLACE\" | \"REGEXP_LIKE\" | \"REGEXP_EXTRACT\" | \"JSON_EXTRACT\") "`
 
 	ArgsList []*Expression `parser:"\"(\" (@@ (\",\" @@)*)?\")\""`
 }
@@ -320,6 +354,12 @@ type TrimFunc struct {
 	TrimFrom  *PrimaryTerm `parser:"             \"FROM\" )? @@ \")\" "`
 }
 
+// PositionFunc represents the POSITION function
+type PositionFunc struct {
+	Substr *Operand `parser:" \"POSITION\" \"(\" @@ "`
+	Str    *Operand `parser:" \"IN\" @@ \")\" "`
+}
+
 // DateAddFunc represents the DATE_ADD function
 type DateAddFunc struct {
 	DatePart  string       `parser:" \"DATE_ADD\" \"(\" @( \"YEAR\":Timeword | \"MONTH\":Timeword | \"DAY\":Timeword | \"HOUR\":Timeword | \"MINUTE\":Timeword | \"SECOND\":Timeword ) \",\""`
@@ -353,13 +393,13 @@ type Identifier struct {
 var (
 	sqlLexer = lexer.Must(lexer.Regexp(`(\s+)` +
 		`|(?P<Timeword>(?i)\b(?:YEAR|MONTH|DAY|HOUR|MINUTE|SECOND|TIMEZONE_HOUR|TIMEZONE_MINUTE)\b)` +
-		`|(?P<Keyword>(?i)\b(?:SELECT|FROM|TOP|DISTINCT|ALL|WHERE|GROUP|BY|HAVING|UNION|MINUS|EXCEPT|INTERSECT|ORDER|LIMIT|OFFSET|TRUE|FALSE|NULL|IS|NOT|ANY|SOME|BETWEEN|AND|OR|LIKE|ESCAPE|AS|IN|BOOL|INT|INTEGER|STRING|FLOAT|DECIMAL|NUMERIC|TIMESTAMP|AVG|COUNT|MAX|MIN|SUM|COALESCE|NULLIF|CAST|DATE_ADD|DATE_DIFF|EXTRACT|TO_STRING|TO_TIMESTAMP|UTCNOW|CHAR_LENGTH|CHARACTER_LENGTH|LOWER|SUBSTRING|TRIM|UPPER|LEADING|TRAILING|BOTH|FOR|MISSING)\b)` +
+		`|(?P<Keyword>(?i)\b(?:SELECT|FROM|TOP|DISTINCT|ALL|WHERE|GROUP|BY|HAVING|UNION|MINUS|EXCEPT|INTERSECT|ORDER|ASC|DESC|LIMIT|OFFSET|TRUE|FALSE|NULL|IS|NOT|ANY|SOME|BETWEEN|AND|OR|LIKE|ESCAPE|AS|IN|BOOL|INT|INTEGER|STRING|FLOAT|DECIMAL|NUMERIC|TIMESTAMP|AVG|COUNT|MAX|MIN|SUM|COALESCE|NULLIF|CAST|DATE_ADD|DATE_DIFF|EXTRACT|TO_STRING|TO_TIMESTAMP|UTCNOW|CHAR_LENGTH|CHARACTER_LENGTH|LOWER|SUBSTRING|TRIM|UPPER|LEADING|TRAILING|BOTH|FOR|MISSING|CASE|WHEN|THEN|ELSE|END|ABS|ROUND|FLOOR|CEIL|CEILING|MOD|CONCAT|REPLACE|POSITION|REGEXP_LIKE|REGEXP_EXTRACT|JSON_EXTRACT)\b)` +
 		`|(?P<Ident>[a-zA-Z_][a-zA-Z0-9_]*)` +
 		`|(?P<QuotIdent>"([^"]*("")?)*")` +
 		`|(?P<Float>\d*\.\d+([eE][-+]?\d+)?)` +
 		`|(?P<Int>\d+)` +
 		`|(?P<LitString>'([^']*('')?)*')` +
-		`|(?P<Operators><>|!=|<=|>=|\.\*|\[\*\]|[-+*/%,.()=<>\[\]])`,
+		`|(?P<Operators><>|!=|<=|>=|\|\||\.\*|\[\*\]|[-+*/%,.()=<>\[\]])`,
 	))
 
 	// SQLParser is used to parse SQL statements
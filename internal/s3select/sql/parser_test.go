@@ -355,6 +355,8 @@ func TestSelectParsing(t *testing.T) {
 		"select * from s3object where name > 2 or value > 1 or word > 2",
 		"select s.word.id + 2 from s3object s",
 		"select 1-2-3 from s3object s limit 1",
+		"select s.a, count(*) from s3object s group by s.a having count(*) > 1 order by 2 desc limit 1",
+		"select distinct a, b from s3object order by a asc, b desc",
 	}
 	for i, tc := range cases {
 		err := p.ParseString(tc, &s)
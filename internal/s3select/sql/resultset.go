@@ -0,0 +1,444 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	errAggregationInGroupBy = errors.New("GROUP BY clause cannot have an aggregation")
+	errHavingWithoutGroupBy = errors.New("HAVING clause requires GROUP BY or an aggregation")
+	errResultSetSelectAll   = errors.New("GROUP BY, DISTINCT and ORDER BY require a list of select expressions")
+	errHavingNotBool        = errors.New("HAVING expression did not return bool")
+)
+
+// resultSet evaluates the GROUP BY, HAVING, DISTINCT and ORDER BY
+// clauses of a statement. All input rows are collected before the
+// results are returned, the collected rows are spilled to disk when
+// they do not fit in memory.
+//
+// The results are computed in stages, each of them is only present if
+// required by the statement:
+//
+//  1. The input rows are aggregated by group (GROUP BY or
+//     aggregation), the groups failing the HAVING clause are dropped.
+//  2. Duplicate result rows are dropped (DISTINCT).
+//  3. The result rows are sorted (ORDER BY).
+type resultSet struct {
+	// slots are the expressions evaluated for each result row, the
+	// select expressions come first.
+	slots []resultSlot
+	// having is the slot of the HAVING clause, -1 if not present.
+	having  int
+	orderBy []orderBySlot
+	groupBy []*Expression
+
+	// aggregates are all aggregation function calls of the
+	// statement, their state is swapped in and out for each group.
+	aggregates []*FuncExpr
+
+	grouped  bool
+	distinct bool
+	nSelect  int
+
+	groups       *spiller
+	distinctRows *spiller
+	sortedRows   *spiller
+	// seq keeps the sort stable.
+	seq uint64
+	// key is reused for the group key of input rows.
+	key []byte
+
+	// next returns the next result row once all input rows have
+	// been added, io.EOF after the last one.
+	next func() ([]*Value, error)
+}
+
+type resultSlot struct {
+	expr *Expression
+	qProp
+}
+
+type orderBySlot struct {
+	slot int
+	desc bool
+}
+
+// newResultSet returns the result set of the given select statement,
+// nil if the results can be computed row by row.
+func newResultSet(s *Select, selectQProp qProp) (*resultSet, error) {
+	grouped := selectQProp.isAggregation || len(s.GroupBy) > 0
+	if !grouped && !s.Expression.Distinct && len(s.OrderBy) == 0 {
+		if s.Having != nil {
+			return nil, errHavingWithoutGroupBy
+		}
+		return nil, nil
+	}
+	if s.Expression.All {
+		return nil, errResultSetSelectAll
+	}
+
+	rs := &resultSet{
+		having:   -1,
+		groupBy:  s.GroupBy,
+		grouped:  grouped,
+		distinct: s.Expression.Distinct,
+		nSelect:  len(s.Expression.Expressions),
+	}
+	for _, e := range s.GroupBy {
+		q := e.analyze(s)
+		if q.err != nil {
+			return nil, fmt.Errorf("GROUP BY clause error: %w", q.err)
+		}
+		if q.isAggregation {
+			return nil, errAggregationInGroupBy
+		}
+	}
+
+	for _, e := range s.Expression.Expressions {
+		if err := rs.addSlot(s, e.Expression); err != nil {
+			return nil, err
+		}
+	}
+	if s.Having != nil {
+		if !grouped {
+			return nil, errHavingWithoutGroupBy
+		}
+		rs.having = len(rs.slots)
+		if err := rs.addSlot(s, s.Having); err != nil {
+			return nil, fmt.Errorf("HAVING clause error: %w", err)
+		}
+	}
+	for _, term := range s.OrderBy {
+		slot, err := orderByColumn(term.Expression, s.Expression.Expressions)
+		if err != nil {
+			return nil, err
+		}
+		if slot < 0 {
+			slot = len(rs.slots)
+			if err = rs.addSlot(s, term.Expression); err != nil {
+				return nil, fmt.Errorf("ORDER BY clause error: %w", err)
+			}
+		}
+		rs.orderBy = append(rs.orderBy, orderBySlot{slot: slot, desc: term.Desc})
+	}
+	rs.aggregates = s.aggregates
+
+	if grouped {
+		rs.groups = newSpiller(rs.mergeGroups)
+	}
+	if rs.distinct {
+		rs.distinctRows = newSpiller(nil)
+	}
+	if len(rs.orderBy) > 0 {
+		rs.sortedRows = newSpiller(nil)
+	}
+	return rs, nil
+}
+
+func (rs *resultSet) addSlot(s *Select, e *Expression) error {
+	q := e.analyze(s)
+	switch {
+	case q.err != nil:
+		return q.err
+	case q.isAggregation && !rs.grouped:
+		return errors.New("aggregations require GROUP BY or an aggregated select list")
+	case q.isRowFunc && rs.grouped && len(rs.groupBy) == 0:
+		return errNestedAggregation
+	}
+	rs.slots = append(rs.slots, resultSlot{expr: e, qProp: q})
+	return nil
+}
+
+// orderByColumn returns the select expression referenced by an ORDER
+// BY expression, either by its alias or by its (1-based) position,
+// and -1 if the expression does not reference a select expression.
+func orderByColumn(e *Expression, exprs []*AliasedExpression) (int, error) {
+	primary := getPrimaryTerm(e)
+	switch {
+	case primary == nil:
+	case primary.Value != nil && primary.Value.Int != nil:
+		n := int(*primary.Value.Int)
+		if n < 1 || n > len(exprs) {
+			return -1, fmt.Errorf("ORDER BY position %d is not in select list", n)
+		}
+		return n - 1, nil
+	case primary.JPathExpr != nil && len(primary.JPathExpr.PathExpr) == 0:
+		name := primary.JPathExpr.BaseKey.String()
+		for i, expr := range exprs {
+			if expr.As == name {
+				return i, nil
+			}
+		}
+	}
+	return -1, nil
+}
+
+// setAggregates makes aggs the state of the aggregation function
+// calls.
+func (rs *resultSet) setAggregates(aggs []*aggVal) {
+	for i, fn := range rs.aggregates {
+		fn.aggregate = aggs[i]
+	}
+}
+
+func (rs *resultSet) newAggregates() []*aggVal {
+	aggs := make([]*aggVal, len(rs.aggregates))
+	for i, fn := range rs.aggregates {
+		aggs[i] = newAggVal(fn.getFunctionName())
+	}
+	return aggs
+}
+
+func (rs *resultSet) mergeGroups(dst, src *spillEntry) error {
+	for i, a := range dst.aggs {
+		if err := a.merge(src.aggs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addRow adds an input row passing the WHERE clause.
+func (rs *resultSet) addRow(r Record, tableAlias string) error {
+	if !rs.grouped {
+		vals := make([]*Value, len(rs.slots))
+		for i, slot := range rs.slots {
+			v, err := slot.expr.evalNode(r, tableAlias)
+			if err != nil {
+				return err
+			}
+			vals[i] = v
+		}
+		return rs.addResult(vals)
+	}
+
+	rs.key = rs.key[:0]
+	for _, e := range rs.groupBy {
+		v, err := e.evalNode(r, tableAlias)
+		if err != nil {
+			return err
+		}
+		rs.key = appendKey(rs.key, v, false)
+	}
+
+	group := rs.groups.get(rs.key)
+	isNew := group == nil
+	if isNew {
+		// Row functions are evaluated on the first row of
+		// the group.
+		vals := make([]*Value, len(rs.slots))
+		for i, slot := range rs.slots {
+			if !slot.isRowFunc {
+				continue
+			}
+			v, err := slot.expr.evalNode(r, tableAlias)
+			if err != nil {
+				return err
+			}
+			vals[i] = v
+		}
+		group = &spillEntry{
+			key:  append([]byte(nil), rs.key...),
+			vals: appendValues(nil, vals),
+			aggs: rs.newAggregates(),
+		}
+	}
+
+	rs.setAggregates(group.aggs)
+	for _, slot := range rs.slots {
+		if !slot.isAggregation {
+			continue
+		}
+		if err := slot.expr.aggregateRow(r, tableAlias); err != nil {
+			return err
+		}
+	}
+
+	if isNew {
+		return rs.groups.add(group)
+	}
+	return nil
+}
+
+// addResult adds a result row to the DISTINCT or ORDER BY stage.
+func (rs *resultSet) addResult(vals []*Value) error {
+	if !rs.distinct {
+		return rs.addSorted(vals)
+	}
+
+	var key []byte
+	for _, v := range vals[:rs.nSelect] {
+		key = appendKey(key, v, false)
+	}
+	if rs.distinctRows.get(key) != nil {
+		return nil
+	}
+	return rs.distinctRows.add(&spillEntry{
+		key:  key,
+		vals: appendValues(nil, vals),
+	})
+}
+
+func (rs *resultSet) addSorted(vals []*Value) error {
+	var key []byte
+	for _, o := range rs.orderBy {
+		key = appendKey(key, vals[o.slot], o.desc)
+	}
+	key = appendUint64(key, rs.seq)
+	rs.seq++
+	return rs.sortedRows.add(&spillEntry{
+		key:  key,
+		vals: appendValues(nil, vals),
+	})
+}
+
+// groupResult computes the result row of a group, it returns nil if
+// the group does not pass the HAVING clause.
+func (rs *resultSet) groupResult(group *spillEntry, tableAlias string) ([]*Value, error) {
+	vals, err := decodeValues(group.vals)
+	if err != nil {
+		return nil, err
+	}
+	rs.setAggregates(group.aggs)
+	for i, slot := range rs.slots {
+		if slot.isRowFunc {
+			continue
+		}
+		if vals[i], err = slot.expr.evalNode(nil, tableAlias); err != nil {
+			return nil, err
+		}
+	}
+
+	if rs.having >= 0 {
+		b, ok := vals[rs.having].ToBool()
+		if !ok {
+			return nil, errHavingNotBool
+		}
+		if !b {
+			return nil, nil
+		}
+	}
+	return vals, nil
+}
+
+// finish is called after all input rows have been added, it
+// computes all stages up to the last one, from which the results are
+// then returned by next.
+func (rs *resultSet) finish(tableAlias string) error {
+	if rs.grouped {
+		if len(rs.groupBy) == 0 && rs.groups.get(nil) == nil {
+			// An aggregation without GROUP BY always
+			// returns a row, even without input rows.
+			err := rs.groups.add(&spillEntry{
+				vals: appendValues(nil, make([]*Value, len(rs.slots))),
+				aggs: rs.newAggregates(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		it, err := rs.groups.iterate()
+		if err != nil {
+			return err
+		}
+		next := func() ([]*Value, error) {
+			for {
+				group, err := it.next()
+				if err != nil {
+					return nil, err
+				}
+				vals, err := rs.groupResult(group, tableAlias)
+				if vals != nil || err != nil {
+					return vals, err
+				}
+			}
+		}
+		if !rs.distinct && rs.sortedRows == nil {
+			rs.next = next
+			return nil
+		}
+		if err = drainResults(next, rs.addResult); err != nil {
+			return err
+		}
+	}
+
+	if rs.distinct {
+		it, err := rs.distinctRows.iterate()
+		if err != nil {
+			return err
+		}
+		if rs.sortedRows == nil {
+			rs.next = entryResults(it)
+			return nil
+		}
+		if err = drainResults(entryResults(it), rs.addSorted); err != nil {
+			return err
+		}
+	}
+
+	it, err := rs.sortedRows.iterate()
+	if err != nil {
+		return err
+	}
+	rs.next = entryResults(it)
+	return nil
+}
+
+// close removes all spilled rows.
+func (rs *resultSet) close() error {
+	var firstErr error
+	for _, s := range []*spiller{rs.groups, rs.distinctRows, rs.sortedRows} {
+		if s == nil {
+			continue
+		}
+		if err := s.close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func entryResults(it *spillIterator) func() ([]*Value, error) {
+	return func() ([]*Value, error) {
+		e, err := it.next()
+		if err != nil {
+			return nil, err
+		}
+		return decodeValues(e.vals)
+	}
+}
+
+func drainResults(next func() ([]*Value, error), add func([]*Value) error) error {
+	for {
+		vals, err := next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = add(vals); err != nil {
+			return err
+		}
+	}
+}
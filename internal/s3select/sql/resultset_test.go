@@ -0,0 +1,222 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// testRecord is a minimal record of untyped column values, like CSV
+// records.
+type testRecord struct {
+	names []string
+	vals  map[string]*Value
+}
+
+func newTestRecord(row string) *testRecord {
+	r := &testRecord{vals: make(map[string]*Value)}
+	for _, kv := range strings.Split(row, ",") {
+		kv := strings.SplitN(kv, "=", 2)
+		r.Set(kv[0], FromBytes([]byte(kv[1])))
+	}
+	return r
+}
+
+func (r *testRecord) Get(name string) (*Value, error) {
+	if v, ok := r.vals[name]; ok {
+		return v, nil
+	}
+	return FromMissing(), nil
+}
+
+func (r *testRecord) Set(name string, value *Value) (Record, error) {
+	if _, ok := r.vals[name]; !ok {
+		r.names = append(r.names, name)
+	}
+	r.vals[name] = value
+	return r, nil
+}
+
+func (r *testRecord) WriteCSV(writer io.Writer, opts WriteCSVOpts) error {
+	return errors.New("not implemented")
+}
+
+func (r *testRecord) WriteJSON(writer io.Writer) error {
+	return errors.New("not implemented")
+}
+
+func (r *testRecord) Clone(dst Record) Record {
+	c := &testRecord{vals: make(map[string]*Value)}
+	for _, name := range r.names {
+		c.Set(name, r.vals[name])
+	}
+	return c
+}
+
+func (r *testRecord) Reset() {
+	r.names = nil
+	r.vals = make(map[string]*Value)
+}
+
+func (r *testRecord) Raw() (SelectObjectFormat, interface{}) {
+	return SelectFmtCSV, nil
+}
+
+func (r *testRecord) Replace(k interface{}) error {
+	return errors.New("not implemented")
+}
+
+func (r *testRecord) String() string {
+	parts := make([]string, len(r.names))
+	for i, name := range r.names {
+		parts[i] = name + "=" + r.vals[name].CSVString()
+	}
+	return strings.Join(parts, ",")
+}
+
+var resultSetInput = []string{
+	"name=a,dept=x,salary=10",
+	"name=b,dept=y,salary=20",
+	"name=c,dept=x,salary=30",
+	"name=d,dept=z,salary=5",
+	"name=e,dept=y,salary=20",
+	"name=f,dept=x,salary=15",
+}
+
+func evalResultSet(query string, input []string) ([]string, error) {
+	stmt, err := ParseSelectStatement(query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	if !stmt.IsAggregated() {
+		return nil, errors.New("expected an aggregated statement")
+	}
+
+	for _, row := range input {
+		if err = stmt.AggregateRow(newTestRecord(row)); err != nil {
+			return nil, err
+		}
+	}
+	var results []string
+	for !stmt.LimitReached() {
+		rec, err := stmt.AggregateResult(&testRecord{vals: make(map[string]*Value)})
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, rec.(*testRecord).String())
+	}
+	return results, nil
+}
+
+func TestResultSet(t *testing.T) {
+	testCases := []struct {
+		query string
+		want  string
+	}{
+		{
+			query: "SELECT COUNT(*), SUM(salary) FROM S3Object",
+			want:  "_1=6,_2=100",
+		},
+		{
+			query: "SELECT COUNT(*) FROM S3Object WHERE salary > 100",
+			want:  "_1=0",
+		},
+		{
+			query: "SELECT dept, COUNT(*) AS n, SUM(salary) FROM S3Object GROUP BY dept",
+			want:  "dept=x,n=3,_3=55;dept=y,n=2,_3=40;dept=z,n=1,_3=5",
+		},
+		{
+			query: "SELECT dept, COUNT(*) AS n FROM S3Object GROUP BY dept HAVING COUNT(*) > 1",
+			want:  "dept=x,n=3;dept=y,n=2",
+		},
+		{
+			query: "SELECT s.dept, MAX(s.salary) FROM S3Object s WHERE s.name != 'c' GROUP BY s.dept ORDER BY MAX(s.salary) DESC",
+			want:  "dept=y,_2=20;dept=x,_2=15;dept=z,_2=5",
+		},
+		{
+			query: "SELECT dept, salary, COUNT(*) AS n FROM S3Object GROUP BY dept, salary ORDER BY n DESC, dept",
+			want:  "dept=y,salary=20,n=2;dept=x,salary=10,n=1;dept=x,salary=15,n=1;dept=x,salary=30,n=1;dept=z,salary=5,n=1",
+		},
+		{
+			query: "SELECT dept, AVG(salary) AS a FROM S3Object GROUP BY dept ORDER BY a LIMIT 2",
+			want:  "dept=z,a=5;dept=x,a=18.333333333333332",
+		},
+		{
+			query: "SELECT DISTINCT dept FROM S3Object ORDER BY dept DESC",
+			want:  "dept=z;dept=y;dept=x",
+		},
+		{
+			query: "SELECT DISTINCT salary FROM S3Object",
+			want:  "salary=5;salary=10;salary=15;salary=20;salary=30",
+		},
+		{
+			query: "SELECT name, salary FROM S3Object ORDER BY 2 DESC, name DESC LIMIT 3",
+			want:  "name=c,salary=30;name=e,salary=20;name=b,salary=20",
+		},
+		{
+			query: "SELECT name FROM S3Object ORDER BY salary",
+			want:  "name=d;name=a;name=f;name=b;name=e;name=c",
+		},
+		{
+			query: "SELECT DISTINCT COUNT(*) FROM S3Object GROUP BY dept ORDER BY 1",
+			want:  "_1=1;_1=2;_1=3",
+		},
+	}
+
+	for _, threshold := range []int{spillThreshold, 1} {
+		for i, testCase := range testCases {
+			t.Run(fmt.Sprintf("%d-%d", threshold, i+1), func(t *testing.T) {
+				defer func(threshold int) { spillThreshold = threshold }(spillThreshold)
+				spillThreshold = threshold
+
+				results, err := evalResultSet(testCase.query, resultSetInput)
+				if err != nil {
+					t.Fatalf("%s: %v", testCase.query, err)
+				}
+				if got := strings.Join(results, ";"); got != testCase.want {
+					t.Errorf("%s:\ngot:  %s\nwant: %s", testCase.query, got, testCase.want)
+				}
+			})
+		}
+	}
+}
+
+func TestResultSetErrors(t *testing.T) {
+	queries := []string{
+		"SELECT * FROM S3Object ORDER BY name",
+		"SELECT DISTINCT * FROM S3Object",
+		"SELECT name FROM S3Object HAVING COUNT(*) > 1",
+		"SELECT COUNT(*) FROM S3Object GROUP BY COUNT(*)",
+		"SELECT name FROM S3Object ORDER BY COUNT(*)",
+		"SELECT name FROM S3Object ORDER BY 2",
+		"SELECT COUNT(*) FROM S3Object HAVING name = 'a'",
+	}
+	for _, query := range queries {
+		if _, err := ParseSelectStatement(query); err == nil {
+			t.Errorf("%s: expected an error", query)
+		}
+	}
+}
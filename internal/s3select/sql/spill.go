@@ -0,0 +1,657 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package sql
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"time"
+)
+
+// GROUP BY, DISTINCT and ORDER BY need to see all input rows before
+// the first result can be returned. The intermediate rows are kept in
+// memory as spill entries until they exceed spillThreshold bytes, at
+// which point they are sorted by key and written to a temporary file
+// (a run). The results are read by merging the in-memory entries with
+// all runs, entries with equal keys are merged into one.
+
+// spillThreshold is the approximate number of bytes of intermediate
+// rows kept in memory before they are spilled to disk.
+var spillThreshold = 64 << 20
+
+// spillEntryOverhead is the estimated memory overhead of an entry.
+const spillEntryOverhead = 128
+
+var errCorruptSpillFile = errors.New("corrupt spill file")
+
+// spillEntry is a row of intermediate results.
+type spillEntry struct {
+	// key is the order preserving encoding of the row key, see
+	// appendKey.
+	key []byte
+	// vals are the encoded values of the row, see appendValues.
+	vals []byte
+	// aggs are the partial aggregations of a group.
+	aggs []*aggVal
+}
+
+func (e *spillEntry) size() int {
+	return len(e.key) + len(e.vals) + len(e.aggs)*spillEntryOverhead + spillEntryOverhead
+}
+
+// spiller collects spill entries by key.
+type spiller struct {
+	// merge combines the entry src into dst when both have the same
+	// key, if nil the first entry is kept.
+	merge func(dst, src *spillEntry) error
+
+	entries map[string]*spillEntry
+	size    int
+	runs    []*os.File
+}
+
+func newSpiller(merge func(dst, src *spillEntry) error) *spiller {
+	return &spiller{
+		merge:   merge,
+		entries: make(map[string]*spillEntry),
+	}
+}
+
+// get returns the in-memory entry with the given key, it returns nil
+// if there is no such entry or if it has been spilled already.
+func (s *spiller) get(key []byte) *spillEntry {
+	return s.entries[string(key)]
+}
+
+// add adds a new entry. The entry may be updated as long as it is
+// returned by get, i.e. until it is spilled.
+func (s *spiller) add(e *spillEntry) error {
+	s.entries[string(e.key)] = e
+	s.size += e.size()
+	if s.size < spillThreshold {
+		return nil
+	}
+	return s.spill()
+}
+
+// sorted returns the in-memory entries sorted by key.
+func (s *spiller) sorted() []*spillEntry {
+	entries := make([]*spillEntry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+	return entries
+}
+
+// spill writes all in-memory entries to a new run.
+func (s *spiller) spill() error {
+	f, err := os.CreateTemp("", "s3select-spill-")
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, f)
+
+	w := bufio.NewWriter(f)
+	var buf []byte
+	for _, e := range s.sorted() {
+		buf = appendEntry(buf[:0], e)
+		if _, err = w.Write(appendUvarint(nil, uint64(len(buf)))); err != nil {
+			return err
+		}
+		if _, err = w.Write(buf); err != nil {
+			return err
+		}
+	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+
+	s.entries = make(map[string]*spillEntry)
+	s.size = 0
+	return nil
+}
+
+// iterate returns an iterator over all entries in key order. No
+// entries may be added afterwards.
+func (s *spiller) iterate() (*spillIterator, error) {
+	it := &spillIterator{merge: s.merge}
+	sources := []*spillSource{{entries: s.sorted()}}
+	s.entries = nil
+	for _, f := range s.runs {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		sources = append(sources, &spillSource{r: bufio.NewReader(f)})
+	}
+	for _, src := range sources {
+		if err := src.advance(); err != nil {
+			return nil, err
+		}
+		if src.cur != nil {
+			it.h = append(it.h, src)
+		}
+	}
+	heap.Init(&it.h)
+	return it, nil
+}
+
+// close removes all runs.
+func (s *spiller) close() error {
+	var firstErr error
+	for _, f := range s.runs {
+		f.Close()
+		if err := os.Remove(f.Name()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	s.runs = nil
+	s.entries = nil
+	return firstErr
+}
+
+// spillSource is either the sorted in-memory entries or a run.
+type spillSource struct {
+	entries []*spillEntry
+	r       *bufio.Reader
+	cur     *spillEntry
+}
+
+// advance sets cur to the next entry of the source, nil at the end.
+func (src *spillSource) advance() error {
+	if src.r == nil {
+		src.cur = nil
+		if len(src.entries) > 0 {
+			src.cur, src.entries = src.entries[0], src.entries[1:]
+		}
+		return nil
+	}
+
+	n, err := binary.ReadUvarint(src.r)
+	if err == io.EOF {
+		src.cur = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, n)
+	if _, err = io.ReadFull(src.r, buf); err != nil {
+		return err
+	}
+	src.cur, err = decodeEntry(buf)
+	return err
+}
+
+type spillHeap []*spillSource
+
+func (h spillHeap) Len() int           { return len(h) }
+func (h spillHeap) Less(i, j int) bool { return bytes.Compare(h[i].cur.key, h[j].cur.key) < 0 }
+func (h spillHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *spillHeap) Push(x interface{}) {
+	*h = append(*h, x.(*spillSource))
+}
+
+func (h *spillHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// spillIterator merges the entries of all sources.
+type spillIterator struct {
+	merge func(dst, src *spillEntry) error
+	h     spillHeap
+}
+
+// pop removes the smallest entry.
+func (it *spillIterator) pop() (*spillEntry, error) {
+	src := it.h[0]
+	e := src.cur
+	if err := src.advance(); err != nil {
+		return nil, err
+	}
+	if src.cur == nil {
+		heap.Pop(&it.h)
+	} else {
+		heap.Fix(&it.h, 0)
+	}
+	return e, nil
+}
+
+// next returns the next entry, io.EOF once all entries were returned.
+func (it *spillIterator) next() (*spillEntry, error) {
+	if len(it.h) == 0 {
+		return nil, io.EOF
+	}
+	e, err := it.pop()
+	if err != nil {
+		return nil, err
+	}
+	for len(it.h) > 0 && bytes.Equal(it.h[0].cur.key, e.key) {
+		o, err := it.pop()
+		if err != nil {
+			return nil, err
+		}
+		if it.merge != nil {
+			if err = it.merge(e, o); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return e, nil
+}
+
+// Order preserving encoding of keys - the encoded keys of two rows
+// compare (bytewise) like their values. Values of different types are
+// ordered NULL < BOOL < numbers < TIMESTAMP < STRING < ARRAY, untyped
+// values are treated as numbers if possible and as strings otherwise.
+// The encoding of a value is never a prefix of the encoding of
+// another value, so the keys of multiple values can be concatenated.
+const (
+	keyTagEnd byte = iota
+	keyTagNull
+	keyTagBool
+	keyTagNumber
+	keyTagTimestamp
+	keyTagString
+	keyTagArray
+)
+
+// appendKey appends the key encoding of v to b, in descending order if
+// desc is set.
+func appendKey(b []byte, v *Value, desc bool) []byte {
+	n := len(b)
+	b = appendKeyValue(b, v)
+	if desc {
+		for i := n; i < len(b); i++ {
+			b[i] = ^b[i]
+		}
+	}
+	return b
+}
+
+func appendKeyValue(b []byte, v *Value) []byte {
+	switch x := v.value.(type) {
+	case bool:
+		if x {
+			return append(b, keyTagBool, 1)
+		}
+		return append(b, keyTagBool, 0)
+	case int64:
+		return appendKeyInt(b, x)
+	case float64:
+		return appendKeyFloat(b, x)
+	case string:
+		return appendKeyString(b, x)
+	case []byte:
+		if i, ok := v.bytesToInt(); ok {
+			return appendKeyInt(b, i)
+		}
+		if f, ok := v.bytesToFloat(); ok {
+			return appendKeyFloat(b, f)
+		}
+		return appendKeyString(b, string(x))
+	case time.Time:
+		b = append(b, keyTagTimestamp)
+		b = appendUint64(b, uint64(x.Unix())^1<<63)
+		return appendUint32(b, uint32(x.Nanosecond()))
+	case []Value:
+		b = append(b, keyTagArray)
+		for i := range x {
+			b = appendKeyValue(b, &x[i])
+		}
+		return append(b, keyTagEnd)
+	default:
+		// NULL and MISSING
+		return append(b, keyTagNull)
+	}
+}
+
+// Numbers are encoded by their float value followed by the exact
+// integer value for integral numbers (so that large integers remain
+// distinct and 1 equals 1.0).
+func appendKeyInt(b []byte, i int64) []byte {
+	b = append(b, keyTagNumber)
+	b = appendUint64(b, orderedFloat(float64(i)))
+	return appendUint64(b, uint64(i)^1<<63)
+}
+
+func appendKeyFloat(b []byte, f float64) []byte {
+	if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+		return appendKeyInt(b, int64(f))
+	}
+	b = append(b, keyTagNumber)
+	b = appendUint64(b, orderedFloat(f))
+	return appendUint64(b, orderedFloat(f))
+}
+
+func orderedFloat(f float64) uint64 {
+	u := math.Float64bits(f)
+	if u&(1<<63) != 0 {
+		return ^u
+	}
+	return u | 1<<63
+}
+
+// Strings are terminated by 0x00 0x01, 0x00 bytes within the string
+// are escaped as 0x00 0xff.
+func appendKeyString(b []byte, s string) []byte {
+	b = append(b, keyTagString)
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0 {
+			b = append(b, 0, 0xff)
+			continue
+		}
+		b = append(b, s[i])
+	}
+	return append(b, 0, 1)
+}
+
+// Exact encoding of values, used to restore them after spilling.
+const (
+	valueTagNull byte = iota
+	valueTagMissing
+	valueTagFalse
+	valueTagTrue
+	valueTagInt
+	valueTagFloat
+	valueTagString
+	valueTagBytes
+	valueTagTimestamp
+	valueTagArray
+)
+
+// appendValues appends the encoding of vals to b, nil values are
+// encoded as NULL.
+func appendValues(b []byte, vals []*Value) []byte {
+	b = appendUvarint(b, uint64(len(vals)))
+	for _, v := range vals {
+		if v == nil {
+			b = append(b, valueTagNull)
+			continue
+		}
+		b = appendValue(b, v)
+	}
+	return b
+}
+
+func appendValue(b []byte, v *Value) []byte {
+	switch x := v.value.(type) {
+	case Missing:
+		return append(b, valueTagMissing)
+	case bool:
+		if x {
+			return append(b, valueTagTrue)
+		}
+		return append(b, valueTagFalse)
+	case int64:
+		return appendUint64(append(b, valueTagInt), uint64(x))
+	case float64:
+		return appendUint64(append(b, valueTagFloat), math.Float64bits(x))
+	case string:
+		b = appendUvarint(append(b, valueTagString), uint64(len(x)))
+		return append(b, x...)
+	case []byte:
+		b = appendUvarint(append(b, valueTagBytes), uint64(len(x)))
+		return append(b, x...)
+	case time.Time:
+		data, err := x.MarshalBinary()
+		if err != nil {
+			// Zone offsets which are not whole minutes can
+			// not be encoded.
+			data, _ = x.UTC().MarshalBinary()
+		}
+		b = appendUvarint(append(b, valueTagTimestamp), uint64(len(data)))
+		return append(b, data...)
+	case []Value:
+		b = appendUvarint(append(b, valueTagArray), uint64(len(x)))
+		for i := range x {
+			b = appendValue(b, &x[i])
+		}
+		return b
+	default:
+		return append(b, valueTagNull)
+	}
+}
+
+// decodeValues decodes values encoded by appendValues.
+func decodeValues(b []byte) ([]*Value, error) {
+	n, b, err := readUvarint(b)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(b)) {
+		return nil, errCorruptSpillFile
+	}
+	vals := make([]*Value, n)
+	for i := range vals {
+		if vals[i], b, err = readValue(b); err != nil {
+			return nil, err
+		}
+	}
+	return vals, nil
+}
+
+func readValue(b []byte) (*Value, []byte, error) {
+	if len(b) == 0 {
+		return nil, nil, errCorruptSpillFile
+	}
+	tag, b := b[0], b[1:]
+	switch tag {
+	case valueTagNull:
+		return FromNull(), b, nil
+	case valueTagMissing:
+		return FromMissing(), b, nil
+	case valueTagFalse, valueTagTrue:
+		return FromBool(tag == valueTagTrue), b, nil
+	case valueTagInt, valueTagFloat:
+		if len(b) < 8 {
+			return nil, nil, errCorruptSpillFile
+		}
+		u := binary.BigEndian.Uint64(b)
+		if tag == valueTagInt {
+			return FromInt(int64(u)), b[8:], nil
+		}
+		return FromFloat(math.Float64frombits(u)), b[8:], nil
+	case valueTagString, valueTagBytes, valueTagTimestamp:
+		n, b, err := readUvarint(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		if n > uint64(len(b)) {
+			return nil, nil, errCorruptSpillFile
+		}
+		data := b[:n:n]
+		switch tag {
+		case valueTagString:
+			return FromString(string(data)), b[n:], nil
+		case valueTagBytes:
+			return FromBytes(data), b[n:], nil
+		}
+		var t time.Time
+		if err = t.UnmarshalBinary(data); err != nil {
+			return nil, nil, err
+		}
+		return FromTimestamp(t), b[n:], nil
+	case valueTagArray:
+		n, b, err := readUvarint(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		if n > uint64(len(b)) {
+			return nil, nil, errCorruptSpillFile
+		}
+		arr := make([]Value, n)
+		for i := range arr {
+			var v *Value
+			if v, b, err = readValue(b); err != nil {
+				return nil, nil, err
+			}
+			arr[i] = *v
+		}
+		return FromArray(arr), b, nil
+	}
+	return nil, nil, errCorruptSpillFile
+}
+
+// Flags of encoded partial aggregations.
+const (
+	aggFlagSeen byte = 1 << iota
+	aggFlagSum
+	aggFlagMin
+	aggFlagMax
+)
+
+func appendAggVal(b []byte, a *aggVal) []byte {
+	var flags byte
+	if a.seen {
+		flags |= aggFlagSeen
+	}
+	if a.runningSum != nil {
+		flags |= aggFlagSum
+	}
+	if a.runningMin != nil {
+		flags |= aggFlagMin
+	}
+	if a.runningMax != nil {
+		flags |= aggFlagMax
+	}
+	b = appendUint64(append(b, flags), uint64(a.runningCount))
+	for _, v := range []*Value{a.runningSum, a.runningMin, a.runningMax} {
+		if v != nil {
+			b = appendValue(b, v)
+		}
+	}
+	return b
+}
+
+func readAggVal(b []byte) (*aggVal, []byte, error) {
+	if len(b) < 9 {
+		return nil, nil, errCorruptSpillFile
+	}
+	flags := b[0]
+	a := &aggVal{
+		seen:         flags&aggFlagSeen != 0,
+		runningCount: int64(binary.BigEndian.Uint64(b[1:])),
+	}
+	b = b[9:]
+	var err error
+	if flags&aggFlagSum != 0 {
+		if a.runningSum, b, err = readValue(b); err != nil {
+			return nil, nil, err
+		}
+	}
+	if flags&aggFlagMin != 0 {
+		if a.runningMin, b, err = readValue(b); err != nil {
+			return nil, nil, err
+		}
+	}
+	if flags&aggFlagMax != 0 {
+		if a.runningMax, b, err = readValue(b); err != nil {
+			return nil, nil, err
+		}
+	}
+	return a, b, nil
+}
+
+func appendEntry(b []byte, e *spillEntry) []byte {
+	b = appendUvarint(b, uint64(len(e.key)))
+	b = append(b, e.key...)
+	b = appendUvarint(b, uint64(len(e.vals)))
+	b = append(b, e.vals...)
+	b = appendUvarint(b, uint64(len(e.aggs)))
+	for _, a := range e.aggs {
+		b = appendAggVal(b, a)
+	}
+	return b
+}
+
+func decodeEntry(b []byte) (*spillEntry, error) {
+	var e spillEntry
+	var err error
+	if e.key, b, err = readBytes(b); err != nil {
+		return nil, err
+	}
+	if e.vals, b, err = readBytes(b); err != nil {
+		return nil, err
+	}
+	n, b, err := readUvarint(b)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(b)) {
+		return nil, errCorruptSpillFile
+	}
+	e.aggs = make([]*aggVal, n)
+	for i := range e.aggs {
+		if e.aggs[i], b, err = readAggVal(b); err != nil {
+			return nil, err
+		}
+	}
+	return &e, nil
+}
+
+func readBytes(b []byte) ([]byte, []byte, error) {
+	n, b, err := readUvarint(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if n > uint64(len(b)) {
+		return nil, nil, errCorruptSpillFile
+	}
+	return b[:n:n], b[n:], nil
+}
+
+func readUvarint(b []byte) (uint64, []byte, error) {
+	n, l := binary.Uvarint(b)
+	if l <= 0 {
+		return 0, nil, errCorruptSpillFile
+	}
+	return n, b[l:], nil
+}
+
+func appendUvarint(b []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], x)
+	return append(b, buf[:n]...)
+}
+
+func appendUint64(b []byte, x uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], x)
+	return append(b, buf[:]...)
+}
+
+func appendUint32(b []byte, x uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], x)
+	return append(b, buf[:]...)
+}
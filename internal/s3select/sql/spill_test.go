@@ -0,0 +1,189 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package sql
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAppendKeyOrder(t *testing.T) {
+	ts := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	// Values in ascending order
+	values := []*Value{
+		FromNull(),
+		FromBool(false),
+		FromBool(true),
+		FromInt(-1 << 62),
+		FromFloat(-2.5),
+		FromInt(-1),
+		FromBytes([]byte("0")),
+		FromFloat(0.5),
+		FromInt(1),
+		FromBytes([]byte("1.5")),
+		FromInt(2),
+		FromInt(1<<53 + 1),
+		FromTimestamp(ts),
+		FromTimestamp(ts.Add(time.Nanosecond)),
+		FromString(""),
+		FromString("a"),
+		FromString("a\x00"),
+		FromString("a\x00b"),
+		FromBytes([]byte("ab")),
+		FromString("b"),
+		FromArray([]Value{*FromInt(1)}),
+		FromArray([]Value{*FromInt(1), *FromInt(2)}),
+	}
+	for i := 1; i < len(values); i++ {
+		for _, desc := range []bool{false, true} {
+			a := appendKey(nil, values[i-1], desc)
+			b := appendKey(nil, values[i], desc)
+			want := -1
+			if desc {
+				want = 1
+			}
+			if got := bytes.Compare(a, b); got != want {
+				t.Errorf("Test %d (desc %t): %s compares %d to %s, want %d", i, desc, values[i-1].Repr(), got, values[i].Repr(), want)
+			}
+		}
+	}
+
+	equal := [][2]*Value{
+		{FromInt(1), FromFloat(1)},
+		{FromInt(1), FromBytes([]byte("1"))},
+		{FromNull(), FromMissing()},
+		{FromString("a"), FromBytes([]byte("a"))},
+	}
+	for i, tc := range equal {
+		if !bytes.Equal(appendKey(nil, tc[0], false), appendKey(nil, tc[1], false)) {
+			t.Errorf("Test %d: expected keys of %s and %s to be equal", i+1, tc[0].Repr(), tc[1].Repr())
+		}
+	}
+}
+
+func TestAppendValues(t *testing.T) {
+	vals := []*Value{
+		FromNull(),
+		FromMissing(),
+		FromBool(true),
+		FromBool(false),
+		FromInt(-42),
+		FromFloat(3.25),
+		FromString("abc"),
+		FromBytes([]byte("def")),
+		FromTimestamp(time.Date(2023, 1, 2, 3, 4, 5, 6, time.FixedZone("", 3600))),
+		FromArray([]Value{*FromInt(1), *FromString("x")}),
+	}
+	got, err := decodeValues(appendValues(nil, vals))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(vals) {
+		t.Fatalf("expected %d values, got %d", len(vals), len(got))
+	}
+	for i := range vals {
+		if vals[i].Repr() != got[i].Repr() {
+			t.Errorf("Test %d: expected %s, got %s", i+1, vals[i].Repr(), got[i].Repr())
+		}
+	}
+}
+
+func TestSpiller(t *testing.T) {
+	defer func(threshold int) { spillThreshold = threshold }(spillThreshold)
+	spillThreshold = 4096
+
+	s := newSpiller(func(dst, src *spillEntry) error {
+		return dst.aggs[0].merge(src.aggs[0])
+	})
+	defer s.close()
+
+	const groups, rows = 100, 5000
+	for i := 0; i < rows; i++ {
+		key := appendKey(nil, FromInt(int64(i%groups)), false)
+		e := s.get(key)
+		isNew := e == nil
+		if isNew {
+			e = &spillEntry{
+				key:  key,
+				vals: appendValues(nil, []*Value{FromInt(int64(i % groups))}),
+				aggs: []*aggVal{newAggVal(aggFnSum)},
+			}
+		}
+		e.aggs[0].runningCount++
+		if err := e.aggs[0].runningSum.arithOp(opPlus, FromInt(int64(i))); err != nil {
+			t.Fatal(err)
+		}
+		if isNew {
+			if err := s.add(e); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if len(s.runs) == 0 {
+		t.Fatal("expected entries to be spilled")
+	}
+	names := make([]string, len(s.runs))
+	for i, f := range s.runs {
+		names[i] = f.Name()
+	}
+
+	it, err := s.iterate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for group := int64(0); ; group++ {
+		e, err := it.next()
+		if err == io.EOF {
+			if group != groups {
+				t.Fatalf("expected %d groups, got %d", groups, group)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		vals, err := decodeValues(e.vals)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, _ := vals[0].ToInt(); v != group {
+			t.Fatalf("expected group %d, got %d", group, v)
+		}
+		// Sum of group, group + groups, ...
+		n := int64(rows / groups)
+		wantSum := n*group + groups*n*(n-1)/2
+		if e.aggs[0].runningCount != n {
+			t.Errorf("group %d: expected count %d, got %d", group, n, e.aggs[0].runningCount)
+		}
+		if sum, _ := e.aggs[0].runningSum.ToFloat(); int64(sum) != wantSum {
+			t.Errorf("group %d: expected sum %d, got %v", group, wantSum, sum)
+		}
+	}
+
+	if err = s.close(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		if _, err = os.Stat(name); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", name)
+		}
+	}
+}
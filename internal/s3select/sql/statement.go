@@ -48,6 +48,10 @@ type SelectStatement struct {
 
 	// Table alias
 	tableAlias string
+
+	// Evaluation of GROUP BY, HAVING, DISTINCT and ORDER BY
+	// (otherwise nil)
+	results *resultSet
 }
 
 // ParseSelectStatement - parses a select query from the given string
@@ -108,6 +112,13 @@ func ParseSelectStatement(s string) (stmt SelectStatement, err error) {
 	err = stmt.selectQProp.err
 	if err != nil {
 		err = errQueryAnalysisFailure(err)
+		return
+	}
+
+	stmt.results, err = newResultSet(&selectAST, stmt.selectQProp)
+	if err != nil {
+		err = errQueryAnalysisFailure(err)
+		return
 	}
 
 	// Set table alias
@@ -222,29 +233,47 @@ func (e *SelectStatement) EvalFrom(format string, input Record) ([]*Record, erro
 	return nil, errDataSource(errors.New("unexpected non JSON input"))
 }
 
-// IsAggregated returns if the statement involves SQL aggregation
+// IsAggregated returns if the statement involves SQL aggregation,
+// GROUP BY, DISTINCT or ORDER BY. The input records of such statements
+// are passed to AggregateRow and the results are returned by
+// AggregateResult once all input records have been processed.
 func (e *SelectStatement) IsAggregated() bool {
-	return e.selectQProp.isAggregation
+	return e.results != nil
 }
 
-// AggregateResult - returns the aggregated result after all input
-// records have been processed. Applies only to aggregation queries.
-func (e *SelectStatement) AggregateResult(output Record) error {
-	for i, expr := range e.selectAST.Expression.Expressions {
-		v, err := expr.evalNode(nil, e.tableAlias)
-		if err != nil {
-			return err
-		}
-		if expr.As != "" {
-			output, err = output.Set(expr.As, v)
-		} else {
-			output, err = output.Set(fmt.Sprintf("_%d", i+1), v)
+// AggregateResult - returns the next result record after all input
+// records have been processed, and io.EOF after the last one. Applies
+// only to aggregation queries.
+func (e *SelectStatement) AggregateResult(output Record) (Record, error) {
+	if e.results.next == nil {
+		if err := e.results.finish(e.tableAlias); err != nil {
+			return nil, err
 		}
+	}
+	vals, err := e.results.next()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, expr := range e.selectAST.Expression.Expressions {
+		output, err = output.Set(outputColumnName(i, expr), vals[i])
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
-	return nil
+
+	// Update count of records output.
+	e.outputCount++
+
+	return output, nil
+}
+
+// Close removes the temporary files of aggregation queries.
+func (e *SelectStatement) Close() error {
+	if e.results == nil {
+		return nil
+	}
+	return e.results.close()
 }
 
 func (e *SelectStatement) isPassingWhereClause(input Record) (bool, error) {
@@ -276,13 +305,7 @@ func (e *SelectStatement) AggregateRow(input Record) error {
 		return nil
 	}
 
-	for _, expr := range e.selectAST.Expression.Expressions {
-		err := expr.aggregateRow(input, e.tableAlias)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	return e.results.addRow(input, e.tableAlias)
 }
 
 // Eval - evaluates the Select statement for the given record. It
@@ -311,14 +334,7 @@ func (e *SelectStatement) Eval(input, output Record) (Record, error) {
 			return nil, err
 		}
 
-		// Pick output column names
-		if expr.As != "" {
-			output, err = output.Set(expr.As, v)
-		} else if comp, ok := getLastKeypathComponent(expr.Expression); ok {
-			output, err = output.Set(comp, v)
-		} else {
-			output, err = output.Set(fmt.Sprintf("_%d", i+1), v)
-		}
+		output, err = output.Set(outputColumnName(i, expr), v)
 		if err != nil {
 			return nil, err
 		}
@@ -330,6 +346,18 @@ func (e *SelectStatement) Eval(input, output Record) (Record, error) {
 	return output, nil
 }
 
+// outputColumnName picks the output column name of the i-th select
+// expression.
+func outputColumnName(i int, expr *AliasedExpression) string {
+	if expr.As != "" {
+		return expr.As
+	}
+	if comp, ok := getLastKeypathComponent(expr.Expression); ok {
+		return comp
+	}
+	return fmt.Sprintf("_%d", i+1)
+}
+
 // LimitReached - returns true if the number of records output has
 // reached the value of the `LIMIT` clause.
 func (e *SelectStatement) LimitReached() bool {
@@ -20,6 +20,7 @@ package sql
 import (
 	"errors"
 	"strings"
+	"unicode/utf8"
 )
 
 var (
@@ -198,3 +199,22 @@ func evalSQLTrim(where *string, trimChars, text string) (result string, err erro
 
 	return trimFunc(text, cutSet), nil
 }
+
+// evalSQLReplace replaces all occurrences of from in text by to, an
+// empty from leaves the text unchanged.
+func evalSQLReplace(text, from, to string) string {
+	if from == "" {
+		return text
+	}
+	return strings.ReplaceAll(text, from, to)
+}
+
+// evalSQLPosition returns the 1-based character position of the first
+// occurrence of substr in text, and 0 if it does not occur.
+func evalSQLPosition(substr, text string) int {
+	i := strings.Index(text, substr)
+	if i < 0 {
+		return 0
+	}
+	return utf8.RuneCountInString(text[:i]) + 1
+}
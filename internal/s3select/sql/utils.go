@@ -98,23 +98,13 @@ func (o *ObjectKey) keyString() string {
 // expression, and if so extracts the last dot separated component of
 // the path. Otherwise it returns false.
 func getLastKeypathComponent(e *Expression) (string, bool) {
-	if len(e.And) > 1 ||
-		len(e.And[0].Condition) > 1 ||
-		e.And[0].Condition[0].Not != nil ||
-		e.And[0].Condition[0].Operand.ConditionRHS != nil {
-		return "", false
-	}
-
-	operand := e.And[0].Condition[0].Operand.Operand
-	if operand.Right != nil ||
-		operand.Left.Right != nil ||
-		operand.Left.Left.Negated != nil ||
-		operand.Left.Left.Primary.JPathExpr == nil {
+	primary := getPrimaryTerm(e)
+	if primary == nil || primary.JPathExpr == nil {
 		return "", false
 	}
 
 	// Check if path expression ends in a key
-	jpath := operand.Left.Left.Primary.JPathExpr
+	jpath := primary.JPathExpr
 	n := len(jpath.PathExpr)
 	if n > 0 && jpath.PathExpr[n-1].Key == nil {
 		return "", false
@@ -127,6 +117,25 @@ func getLastKeypathComponent(e *Expression) (string, bool) {
 	return ps, true
 }
 
+// getPrimaryTerm returns the primary term if the given expression
+// consists of a single primary term. Otherwise it returns nil.
+func getPrimaryTerm(e *Expression) *PrimaryTerm {
+	if len(e.And) > 1 ||
+		len(e.And[0].Condition) > 1 ||
+		e.And[0].Condition[0].Not != nil ||
+		e.And[0].Condition[0].Operand.ConditionRHS != nil {
+		return nil
+	}
+
+	operand := e.And[0].Condition[0].Operand.Operand
+	if operand.Right != nil ||
+		operand.Left.Right != nil ||
+		operand.Left.Left.Negated != nil {
+		return nil
+	}
+	return operand.Left.Left.Primary
+}
+
 // HasKeypath returns if the from clause has a key path -
 // e.g. S3object[*].id
 func (from *TableExpression) HasKeypath() bool {
@@ -607,6 +607,9 @@ const (
 	opDivide   = "/"
 	opMultiply = "*"
 	opModulo   = "%"
+
+	// String concatenation
+	opConcat = "||"
 )
 
 // For arithmetic operations, if both values are numeric then the
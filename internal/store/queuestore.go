@@ -0,0 +1,586 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package store
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/s2"
+)
+
+const (
+	// DefaultEntryLimit is the maximum number of items of a store, if
+	// not configured.
+	DefaultEntryLimit = 100000
+	// DefaultSegmentSize is the size of a log segment after which a
+	// new segment is started, if not configured.
+	DefaultSegmentSize = 4 << 20
+
+	segmentExt = ".log"
+	indexExt   = ".idx"
+
+	// A record is the payload length, the CRC-32C of the payload, the
+	// flags and the payload.
+	recordHeaderSize = 9
+	maxRecordSize    = 1 << 30
+
+	flagCompressed = 1 << 0
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// errCorruptRecord - the record at an offset of a segment is incomplete or
+// its checksum does not match.
+var errCorruptRecord = errors.New("corrupt queue store record")
+
+// Config - configuration of a queue store.
+type Config struct {
+	// Directory holds the log segments of the store.
+	Directory string
+	// EntryLimit is the maximum number of items, DefaultEntryLimit
+	// if zero.
+	EntryLimit uint64
+	// SizeLimit is the maximum size of the log segments in bytes,
+	// no limit if zero.
+	SizeLimit uint64
+	// SegmentSize is the size of a segment after which a new segment
+	// is started, DefaultSegmentSize if zero.
+	SegmentSize int64
+	// Compress enables S2 compression of the items.
+	Compress bool
+	// LegacyExt is the extension of the files of earlier versions,
+	// which stored one JSON encoded item per file. Open moves these
+	// items into the log.
+	LegacyExt string
+}
+
+// segment - a log segment file and the offsets of its items which are not
+// deleted.
+type segment struct {
+	id   uint64
+	size int64
+	live map[int64]struct{}
+}
+
+// QueueStore - a persistent queue of items in an append-only segmented log.
+//
+// Items are appended as JSON encoded records with a checksum to the current
+// segment file. The offsets of deleted items are appended to the index file
+// of their segment, a segment is removed once all its items are deleted. Open
+// scans the segments, truncates a torn record at the end of a segment left by
+// a crash and restores the deleted items from the indexes.
+type QueueStore[I any] struct {
+	sync.RWMutex
+	cfg Config
+
+	segments map[uint64]*segment
+	current  *segment // segment new items are appended to, if any
+	lastID   uint64
+	entries  int
+	size     uint64 // total size of the segments
+}
+
+// NewQueueStore - Creates an instance for QueueStore.
+func NewQueueStore[I any](cfg Config) *QueueStore[I] {
+	if cfg.EntryLimit == 0 {
+		cfg.EntryLimit = DefaultEntryLimit
+	}
+	if cfg.SegmentSize <= 0 {
+		cfg.SegmentSize = DefaultSegmentSize
+	}
+	return &QueueStore[I]{
+		cfg:      cfg,
+		segments: make(map[uint64]*segment),
+	}
+}
+
+var (
+	openStoresMu sync.Mutex
+	openStores   = make(map[string]interface{})
+)
+
+// OpenQueueStore - Returns the opened queue store of the directory of cfg,
+// creating and opening it on first use. Targets which replace each other
+// on reconfiguration share the store, a directory must not be used by two
+// instances at the same time. The limits and the compression of cfg are
+// applied to a store which is already open.
+func OpenQueueStore[I any](cfg Config) (*QueueStore[I], error) {
+	dir, err := filepath.Abs(cfg.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	openStoresMu.Lock()
+	defer openStoresMu.Unlock()
+
+	if s, ok := openStores[dir]; ok {
+		store, ok := s.(*QueueStore[I])
+		if !ok {
+			return nil, fmt.Errorf("queue store '%s' is in use for other items", dir)
+		}
+		store.configure(cfg)
+		return store, nil
+	}
+
+	store := NewQueueStore[I](cfg)
+	if err = store.Open(); err != nil {
+		return nil, err
+	}
+	openStores[dir] = store
+	return store, nil
+}
+
+// configure - applies the limits and the compression of cfg, new items are
+// stored with them.
+func (store *QueueStore[I]) configure(cfg Config) {
+	if cfg.EntryLimit == 0 {
+		cfg.EntryLimit = DefaultEntryLimit
+	}
+
+	store.Lock()
+	defer store.Unlock()
+
+	store.cfg.EntryLimit = cfg.EntryLimit
+	store.cfg.SizeLimit = cfg.SizeLimit
+	store.cfg.Compress = cfg.Compress
+}
+
+// Open - Creates the directory if not present and loads the log segments.
+func (store *QueueStore[I]) Open() error {
+	store.Lock()
+	defer store.Unlock()
+
+	if err := os.MkdirAll(store.cfg.Directory, os.FileMode(0o770)); err != nil {
+		return err
+	}
+
+	files, err := os.ReadDir(store.cfg.Directory)
+	if err != nil {
+		return err
+	}
+
+	var ids []uint64
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 16, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		seg, err := store.loadSegment(id)
+		if err != nil {
+			return err
+		}
+		if id > store.lastID {
+			store.lastID = id
+		}
+		if len(seg.live) == 0 {
+			store.removeSegment(seg)
+			continue
+		}
+		store.segments[id] = seg
+		store.entries += len(seg.live)
+		store.size += uint64(seg.size)
+	}
+
+	// Continue to append to the last segment.
+	if seg, ok := store.segments[store.lastID]; ok && seg.size < store.cfg.SegmentSize {
+		store.current = seg
+	}
+
+	if store.cfg.LegacyExt != "" {
+		return store.importLegacy(files)
+	}
+	return nil
+}
+
+// loadSegment - reads the index and scans the records of a segment. A
+// corrupt record and the records after it are truncated.
+func (store *QueueStore[I]) loadSegment(id uint64) (*segment, error) {
+	seg := &segment{id: id, live: make(map[int64]struct{})}
+
+	deleted := make(map[int64]struct{})
+	index, err := os.ReadFile(store.indexPath(id))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	// A partially written offset at the end is ignored.
+	for len(index) >= 8 {
+		deleted[int64(binary.LittleEndian.Uint64(index))] = struct{}{}
+		index = index[8:]
+	}
+
+	f, err := os.Open(store.segmentPath(id))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var header [recordHeaderSize]byte
+	var payload []byte
+	for {
+		if _, err = io.ReadFull(r, header[:]); err != nil {
+			break
+		}
+		n := binary.LittleEndian.Uint32(header[0:4])
+		if n > maxRecordSize {
+			err = errCorruptRecord
+			break
+		}
+		if cap(payload) < int(n) {
+			payload = make([]byte, n)
+		}
+		payload = payload[:n]
+		if _, err = io.ReadFull(r, payload); err != nil {
+			break
+		}
+		if crc32.Checksum(payload, crcTable) != binary.LittleEndian.Uint32(header[4:8]) {
+			err = errCorruptRecord
+			break
+		}
+		if _, ok := deleted[seg.size]; !ok {
+			seg.live[seg.size] = struct{}{}
+		}
+		seg.size += recordHeaderSize + int64(n)
+	}
+	if err == io.EOF {
+		return seg, nil
+	}
+	if err != io.ErrUnexpectedEOF && err != errCorruptRecord {
+		return nil, err
+	}
+
+	// Truncate the torn record of an interrupted write.
+	if err = os.Truncate(store.segmentPath(id), seg.size); err != nil {
+		return nil, err
+	}
+	return seg, nil
+}
+
+// importLegacy - moves the items of the files of earlier versions, oldest
+// first, into the log.
+func (store *QueueStore[I]) importLegacy(files []os.DirEntry) error {
+	type legacyFile struct {
+		name    string
+		modTime int64
+	}
+	var legacyFiles []legacyFile
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), store.cfg.LegacyExt) {
+			continue
+		}
+		fi, err := file.Info()
+		if err != nil {
+			continue
+		}
+		legacyFiles = append(legacyFiles, legacyFile{file.Name(), fi.ModTime().UnixNano()})
+	}
+	sort.Slice(legacyFiles, func(i, j int) bool { return legacyFiles[i].modTime < legacyFiles[j].modTime })
+
+	for _, file := range legacyFiles {
+		path := filepath.Join(store.cfg.Directory, file.name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var item I
+		if len(data) > 0 && json.Unmarshal(data, &item) == nil {
+			if err = store.append(data); err != nil {
+				return err
+			}
+		}
+		if err = os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Put - puts an item to the store.
+func (store *QueueStore[I]) Put(item I) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	store.Lock()
+	defer store.Unlock()
+
+	if uint64(store.entries) >= store.cfg.EntryLimit {
+		return ErrLimitExceeded
+	}
+	if store.cfg.SizeLimit > 0 && store.size+uint64(recordHeaderSize+len(data)) > store.cfg.SizeLimit {
+		return ErrLimitExceeded
+	}
+	return store.append(data)
+}
+
+// append - appends the record of an item to the current segment, a new
+// segment is started if the current one is full.
+func (store *QueueStore[I]) append(data []byte) error {
+	var flags byte
+	if store.cfg.Compress {
+		data = s2.EncodeBetter(nil, data)
+		flags |= flagCompressed
+	}
+
+	record := make([]byte, recordHeaderSize+len(data))
+	binary.LittleEndian.PutUint32(record[0:4], uint32(len(data)))
+	binary.LittleEndian.PutUint32(record[4:8], crc32.Checksum(data, crcTable))
+	record[8] = flags
+	copy(record[recordHeaderSize:], data)
+
+	if store.current != nil && store.current.size > 0 && store.current.size+int64(len(record)) > store.cfg.SegmentSize {
+		if len(store.current.live) == 0 {
+			store.removeSegment(store.current)
+		}
+		store.current = nil
+	}
+	if store.current == nil {
+		store.lastID++
+		store.current = &segment{id: store.lastID, live: make(map[int64]struct{})}
+		store.segments[store.current.id] = store.current
+	}
+	seg := store.current
+
+	f, err := os.OpenFile(store.segmentPath(seg.id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(record)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		// Drop a partially written record.
+		os.Truncate(store.segmentPath(seg.id), seg.size)
+		return err
+	}
+
+	seg.live[seg.size] = struct{}{}
+	seg.size += int64(len(record))
+	store.entries++
+	store.size += uint64(len(record))
+	return nil
+}
+
+// Get - gets an item from the store.
+func (store *QueueStore[I]) Get(key string) (item I, err error) {
+	store.RLock()
+
+	defer func(store *QueueStore[I]) {
+		store.RUnlock()
+		if err != nil {
+			// Upon error we remove the entry.
+			store.Del(key)
+		}
+	}(store)
+
+	id, offset, err := parseKey(key)
+	if err != nil {
+		return item, os.ErrNotExist
+	}
+	seg, ok := store.segments[id]
+	if !ok {
+		return item, os.ErrNotExist
+	}
+	if _, ok = seg.live[offset]; !ok {
+		return item, os.ErrNotExist
+	}
+
+	data, err := store.readRecord(id, offset)
+	if err != nil {
+		return item, err
+	}
+	if err = json.Unmarshal(data, &item); err != nil {
+		return item, err
+	}
+	return item, nil
+}
+
+// readRecord - reads and verifies the record at the offset of a segment and
+// returns its uncompressed payload.
+func (store *QueueStore[I]) readRecord(id uint64, offset int64) ([]byte, error) {
+	f, err := os.Open(store.segmentPath(id))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var header [recordHeaderSize]byte
+	if _, err = f.ReadAt(header[:], offset); err != nil {
+		return nil, err
+	}
+	n := binary.LittleEndian.Uint32(header[0:4])
+	if n > maxRecordSize {
+		return nil, errCorruptRecord
+	}
+	data := make([]byte, n)
+	if _, err = f.ReadAt(data, offset+recordHeaderSize); err != nil {
+		return nil, err
+	}
+	if crc32.Checksum(data, crcTable) != binary.LittleEndian.Uint32(header[4:8]) {
+		return nil, errCorruptRecord
+	}
+	if header[8]&flagCompressed != 0 {
+		return s2.Decode(nil, data)
+	}
+	return data, nil
+}
+
+// Del - Deletes an entry from the store.
+func (store *QueueStore[I]) Del(key string) error {
+	store.Lock()
+	defer store.Unlock()
+
+	id, offset, err := parseKey(key)
+	if err != nil {
+		return nil
+	}
+	seg, ok := store.segments[id]
+	if !ok {
+		return nil
+	}
+	if _, ok = seg.live[offset]; !ok {
+		return nil
+	}
+
+	// Delete as entry no matter the result
+	delete(seg.live, offset)
+	store.entries--
+
+	if len(seg.live) == 0 {
+		if seg == store.current {
+			store.current = nil
+		}
+		return store.removeSegment(seg)
+	}
+
+	f, err := os.OpenFile(store.indexPath(id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(offset))
+	_, err = f.Write(b[:])
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// removeSegment - removes a segment without items and its index.
+func (store *QueueStore[I]) removeSegment(seg *segment) error {
+	if _, ok := store.segments[seg.id]; ok {
+		delete(store.segments, seg.id)
+		store.size -= uint64(seg.size)
+	}
+	err := os.Remove(store.segmentPath(seg.id))
+	if ierr := os.Remove(store.indexPath(seg.id)); ierr != nil && !os.IsNotExist(ierr) && err == nil {
+		err = ierr
+	}
+	return err
+}
+
+// Len returns the entry count.
+func (store *QueueStore[I]) Len() int {
+	store.RLock()
+	defer store.RUnlock()
+	return store.entries
+}
+
+// Size returns the size of the log segments in bytes.
+func (store *QueueStore[I]) Size() uint64 {
+	store.RLock()
+	defer store.RUnlock()
+	return store.size
+}
+
+// List - lists the keys of all items in the store, oldest first.
+func (store *QueueStore[I]) List() ([]string, error) {
+	store.RLock()
+	defer store.RUnlock()
+
+	ids := make([]uint64, 0, len(store.segments))
+	for id := range store.segments {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	keys := make([]string, 0, store.entries)
+	for _, id := range ids {
+		offsets := make([]int64, 0, len(store.segments[id].live))
+		for offset := range store.segments[id].live {
+			offsets = append(offsets, offset)
+		}
+		sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+		for _, offset := range offsets {
+			keys = append(keys, formatKey(id, offset))
+		}
+	}
+	return keys, nil
+}
+
+func (store *QueueStore[I]) segmentPath(id uint64) string {
+	return filepath.Join(store.cfg.Directory, fmt.Sprintf("%016x", id)+segmentExt)
+}
+
+func (store *QueueStore[I]) indexPath(id uint64) string {
+	return filepath.Join(store.cfg.Directory, fmt.Sprintf("%016x", id)+indexExt)
+}
+
+// formatKey - returns the key of the item at the offset of a segment, keys
+// sort in the order of the items.
+func formatKey(id uint64, offset int64) string {
+	return fmt.Sprintf("%016x-%016x", id, offset)
+}
+
+// parseKey - returns the segment and the offset of the item of a key.
+func parseKey(key string) (id uint64, offset int64, err error) {
+	s, o, ok := strings.Cut(key, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid queue store key '%s'", key)
+	}
+	if id, err = strconv.ParseUint(s, 16, 64); err != nil {
+		return 0, 0, err
+	}
+	if offset, err = strconv.ParseInt(o, 16, 64); err != nil {
+		return 0, 0, err
+	}
+	return id, offset, nil
+}
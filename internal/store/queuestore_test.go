@@ -0,0 +1,317 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testItem struct {
+	Name string `json:"name"`
+	Data string `json:"data"`
+}
+
+var item = testItem{Name: "test", Data: strings.Repeat("b33s", 64)}
+
+// Initialize the store.
+func setUpStore(t *testing.T, cfg Config) *QueueStore[testItem] {
+	t.Helper()
+	if cfg.Directory == "" {
+		cfg.Directory = t.TempDir()
+	}
+	store := NewQueueStore[testItem](cfg)
+	if err := store.Open(); err != nil {
+		t.Fatal("Failed to create a queue store ", err)
+	}
+	return store
+}
+
+func putItems(t *testing.T, store *QueueStore[testItem], n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if err := store.Put(item); err != nil {
+			t.Fatal("Failed to put to queue store ", err)
+		}
+	}
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestQueueStorePutGetDel(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		store := setUpStore(t, Config{Compress: compress, SegmentSize: 256})
+		putItems(t, store, 20)
+
+		keys, err := store.List()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 20 || store.Len() != 20 {
+			t.Fatalf("Expected 20 items, got %d keys and Len() %d", len(keys), store.Len())
+		}
+		for _, key := range keys {
+			got, err := store.Get(key)
+			if err != nil {
+				t.Fatal("Failed to Get the item from the queue store ", err)
+			}
+			if !reflect.DeepEqual(item, got) {
+				t.Fatalf("Expected %v, got %v", item, got)
+			}
+		}
+		if len(segmentFiles(t, store.cfg.Directory)) < 2 {
+			t.Fatal("Expected the items to span several segments")
+		}
+
+		for _, key := range keys {
+			if err = store.Del(key); err != nil {
+				t.Fatal("queue store Del failed with ", err)
+			}
+		}
+		if _, err = store.Get(keys[0]); !os.IsNotExist(err) {
+			t.Fatalf("Expected %v for a deleted item, got %v", os.ErrNotExist, err)
+		}
+		if err = store.Del(keys[0]); err != nil {
+			t.Fatal("Expected no error deleting a deleted item, got ", err)
+		}
+		if store.Len() != 0 || store.Size() != 0 {
+			t.Fatalf("Expected an empty store, got Len() %d and Size() %d", store.Len(), store.Size())
+		}
+		if files := segmentFiles(t, store.cfg.Directory); len(files) != 0 {
+			t.Fatalf("Expected the segments to be removed, got %v", files)
+		}
+	}
+}
+
+func TestQueueStoreCompression(t *testing.T) {
+	plain := setUpStore(t, Config{})
+	compressed := setUpStore(t, Config{Compress: true})
+	putItems(t, plain, 10)
+	putItems(t, compressed, 10)
+	if compressed.Size() >= plain.Size() {
+		t.Fatalf("Expected compressed size %d to be less than %d", compressed.Size(), plain.Size())
+	}
+}
+
+func TestQueueStoreLimits(t *testing.T) {
+	store := setUpStore(t, Config{EntryLimit: 5})
+	putItems(t, store, 5)
+	if err := store.Put(item); err != ErrLimitExceeded {
+		t.Fatalf("Expected %v, got %v", ErrLimitExceeded, err)
+	}
+
+	store = setUpStore(t, Config{SizeLimit: 1000})
+	putItems(t, store, 3)
+	if err := store.Put(item); err != ErrLimitExceeded {
+		t.Fatalf("Expected %v, got %v", ErrLimitExceeded, err)
+	}
+	keys, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Del(keys[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Put(item); err != ErrLimitExceeded {
+		t.Fatalf("Expected %v while the segment is not removed, got %v", ErrLimitExceeded, err)
+	}
+	for _, key := range keys[1:] {
+		if err = store.Del(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	putItems(t, store, 1)
+}
+
+func TestQueueStoreReopen(t *testing.T) {
+	dir := t.TempDir()
+	store := setUpStore(t, Config{Directory: dir, SegmentSize: 1024})
+	putItems(t, store, 10)
+	keys, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys[:4] {
+		if err = store.Del(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// re-open
+	store = setUpStore(t, Config{Directory: dir, SegmentSize: 1024})
+	got, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keys[4:], got) {
+		t.Fatalf("Expected %v, got %v", keys[4:], got)
+	}
+
+	// New items are appended after the existing ones.
+	putItems(t, store, 1)
+	got, err = store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 7 || got[5] != keys[9] {
+		t.Fatalf("Expected 7 items after %s, got %v", keys[9], got)
+	}
+}
+
+func TestQueueStoreTornWrite(t *testing.T) {
+	dir := t.TempDir()
+	store := setUpStore(t, Config{Directory: dir})
+	putItems(t, store, 3)
+	size := store.Size()
+
+	// Simulate a crash while appending a record.
+	files := segmentFiles(t, dir)
+	f, err := os.OpenFile(files[len(files)-1], os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.Write([]byte{0xff, 0x00, 0x00, 0x00, 0x01, 0x02}); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	store = setUpStore(t, Config{Directory: dir})
+	if store.Len() != 3 || store.Size() != size {
+		t.Fatalf("Expected 3 items of %d bytes, got %d items of %d bytes", size, store.Len(), store.Size())
+	}
+	putItems(t, store, 1)
+	keys, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		if _, err = store.Get(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestQueueStoreCorruptRecord(t *testing.T) {
+	dir := t.TempDir()
+	store := setUpStore(t, Config{Directory: dir})
+	putItems(t, store, 3)
+	keys, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Flip a byte of the payload of the last record.
+	files := segmentFiles(t, dir)
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-2] ^= 0xff
+	if err = os.WriteFile(files[0], data, 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = store.Get(keys[2]); err != errCorruptRecord {
+		t.Fatalf("Expected %v, got %v", errCorruptRecord, err)
+	}
+	if store.Len() != 2 {
+		t.Fatalf("Expected the corrupt item to be deleted, got Len() %d", store.Len())
+	}
+
+	store = setUpStore(t, Config{Directory: dir})
+	if store.Len() != 2 {
+		t.Fatalf("Expected 2 items, got %d", store.Len())
+	}
+}
+
+func TestQueueStoreLegacyImport(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for i, name := range []string{"b", "a", "c"} {
+		data, err := json.Marshal(testItem{Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name+".event")
+		if err = os.WriteFile(path, data, 0o640); err != nil {
+			t.Fatal(err)
+		}
+		modTime := now.Add(time.Duration(i) * time.Second)
+		if err = os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := setUpStore(t, Config{Directory: dir, LegacyExt: ".event"})
+	keys, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, key := range keys {
+		got, err := store.Get(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, got.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"b", "a", "c"}) {
+		t.Fatalf("Expected the items in modification order, got %v", names)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.event")); len(files) != 0 {
+		t.Fatalf("Expected the legacy files to be removed, got %v", files)
+	}
+}
+
+func TestOpenQueueStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenQueueStore[testItem](Config{Directory: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	putItems(t, store, 2)
+
+	same, err := OpenQueueStore[testItem](Config{Directory: dir + "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same != store {
+		t.Fatal("Expected the opened store of the directory")
+	}
+	if _, err = OpenQueueStore[testItem](Config{Directory: dir, EntryLimit: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Put(testItem{Name: "over-limit"}); err != ErrLimitExceeded {
+		t.Fatalf("Expected %v applying the new limit, got %v", ErrLimitExceeded, err)
+	}
+	if _, err = OpenQueueStore[string](Config{Directory: dir}); err == nil {
+		t.Fatal("Expected an error opening the store for other items")
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2000-2023 Infobsmi
+//
+// This file is part of B33SObject Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package store implements persistent queues of items, which are sent by
+// notification and logger targets once they are online.
+package store
+
+import "errors"
+
+// ErrLimitExceeded error is sent when the maximum limit is reached.
+var ErrLimitExceeded = errors.New("the maximum store limit reached")
+
+// Store - To persist the items. List returns the keys of the items in the
+// order they were put.
+type Store[I any] interface {
+	Put(item I) error
+	Get(key string) (I, error)
+	Len() int
+	List() ([]string, error)
+	Del(key string) error
+	Open() error
+}